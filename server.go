@@ -16,7 +16,8 @@ var (
 )
 var flgDb = flag.String("db", "ubergang.db", "Database file")
 var flgConfigure = flag.Bool("configure", false, "Configure server")
-var flgAccount = flag.Bool("account", false, "Create account")
+var flgAccount = flag.Bool("account", false, "Invite a user to create an account")
+var flgImport = flag.String("import", "", "With --account, invite the users in this CSV or YAML file")
 var flgClearDb = flag.Bool("clear-db", false, "Clear database (DANGER!)")
 var flgTestMode = flag.Bool("test-mode", false, "Test Mode (Only used in integration tests)")
 
@@ -31,6 +32,12 @@ func main() {
 	} else if *flgConfigure {
 		s.Configure()
 		return
+	} else if flag.Arg(0) == "config" {
+		s.ManageConfig(flag.Args()[1:])
+		return
+	} else if *flgAccount && *flgImport != "" {
+		s.ImportAccounts(*flgImport)
+		return
 	} else if *flgAccount {
 		s.CreateAccount()
 		return
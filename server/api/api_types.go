@@ -8,6 +8,12 @@ type ApiAssertionRequest struct {
 	RPID             string                             `json:"rpId"`
 	AllowCredentials []ApiPublicKeyCredentialDescriptor `json:"allowCredentials"`
 	UserVerification string                             `json:"userVerification"`
+	// The relying party ID to try instead, if the user has no passkey for
+	// RPID.
+	FallbackRPID string `json:"fallbackRpId,omitempty"`
+	// What kind of authenticator the browser should suggest: "client-device"
+	// or "security-key".
+	Hints []string `json:"hints,omitempty"`
 }
 
 // Mapping https://www.w3.org/TR/webauthn-2/#iface-authenticatorattestationresponse
@@ -16,6 +22,9 @@ type ApiAuthenticatorAttestationResponse struct {
 	AttestationObject string   `json:"attestationObject"`
 	ClientDataJSON    string   `json:"clientDataJson"`
 	Transports        []string `json:"transports"`
+	// Where the passkey was stored: "platform" or "cross-platform", if the
+	// browser tells.
+	AuthenticatorAttachment string `json:"authenticatorAttachment,omitempty"`
 }
 
 // https://www.w3.org/TR/webauthn-2/#authenticatorassertionresponse
@@ -74,6 +83,7 @@ type ApiPublicKeyCredentialCreationOptions struct {
 	Attestation            string                             `json:"attestation"`
 	ExcludeCredentials     []ApiPublicKeyCredentialDescriptor `json:"excludeCredentials"`
 	AuthenticatorSelection ApiAuthenticatorSelection          `json:"authenticatorSelection,omitempty"`
+	Hints                  []string                           `json:"hints,omitempty"`
 }
 
 type ApiEnrollRequest struct {
@@ -89,6 +99,8 @@ type ApiFinishEnrollRequest struct {
 
 type ApiFinishEnrollError struct {
 	InvalidEnrollment bool `json:"invalidEnrollment"`
+	// The passkey policy doesn't allow passkeys from this authenticator.
+	AuthenticatorNotAllowed bool `json:"authenticatorNotAllowed,omitempty"`
 }
 
 type ApiFinishEnrollResponse struct {
@@ -99,9 +111,14 @@ type ApiFinishEnrollResponse struct {
 // enroll_start
 
 type ApiStartEnrollRequest struct {
+	// Where to store the passkey: "platform" for this device or
+	// "cross-platform" for a security key. If empty, the WebAuthn settings
+	// decide.
+	Attachment string `json:"attachment,omitempty"`
 }
 
 type ApiEnrollStartError struct {
+	AttachmentNotAllowed bool `json:"attachmentNotAllowed,omitempty"`
 }
 
 type ApiStartEnrollResponse struct {
@@ -109,6 +126,41 @@ type ApiStartEnrollResponse struct {
 	EnrollRequest *ApiEnrollRequest    `json:"enrollRequest,omitempty"`
 }
 
+// totp_enroll_start
+
+type ApiStartTotpEnrollError struct {
+	NotAllowed      bool `json:"notAllowed,omitempty"`
+	AlreadyEnrolled bool `json:"alreadyEnrolled,omitempty"`
+}
+
+type ApiStartTotpEnrollResponse struct {
+	Error *ApiStartTotpEnrollError `json:"error,omitempty"`
+	Token string                   `json:"token,omitempty"`
+	// The secret, for entering it manually in an authenticator app.
+	Secret string `json:"secret,omitempty"`
+	// The otpauth:// URI, and the same as a QR code data URI.
+	Uri       string `json:"uri,omitempty"`
+	QrCodeUrl string `json:"qrCodeUrl,omitempty"`
+}
+
+// totp_enroll_finish
+
+type ApiFinishTotpEnrollRequest struct {
+	Token string `json:"token"`
+	Code  string `json:"code"`
+	Name  string `json:"name"`
+}
+
+type ApiFinishTotpEnrollError struct {
+	InvalidEnrollment bool `json:"invalidEnrollment,omitempty"`
+	InvalidCode       bool `json:"invalidCode,omitempty"`
+}
+
+type ApiFinishTotpEnrollResponse struct {
+	Error      *ApiFinishTotpEnrollError `json:"error,omitempty"`
+	Credential *ApiCredential            `json:"credential,omitempty"`
+}
+
 // credential_update
 type ApiUpdateCredentialRequest struct {
 	Name *string `json:"name"`
@@ -130,15 +182,23 @@ type ApiBackend struct {
 	CreatedAt   string             `json:"createdAt"`
 	UpdatedAt   string             `json:"updatedAt"`
 	// Can be NORMAL or PUBLIC.
-	AccessLevel string `json:"accessLevel"`
-	JsScript    string `json:"jsScript"`
+	AccessLevel   string           `json:"accessLevel"`
+	JsScript      string           `json:"jsScript"`
+	SessionPolicy ApiSessionPolicy `json:"sessionPolicy"`
+	// Users must have signed in with a passkey within this time. Zero if unset.
+	MaxAuthAgeSeconds int64 `json:"maxAuthAgeSeconds"`
+	// Accepts app passwords using HTTP Basic authentication.
+	AllowBasicAuth bool `json:"allowBasicAuth"`
 }
 
 type ApiUpdateBackendRequest struct {
-	UpstreamUrl *string             `json:"upstreamUrl"`
-	Headers     *[]ApiBackendHeader `json:"headers"`
-	AccessLevel *string             `json:"accessLevel"`
-	JsScript    string              `json:"jsScript"`
+	UpstreamUrl       *string             `json:"upstreamUrl"`
+	Headers           *[]ApiBackendHeader `json:"headers"`
+	AccessLevel       *string             `json:"accessLevel"`
+	JsScript          string              `json:"jsScript"`
+	SessionPolicy     *ApiSessionPolicy   `json:"sessionPolicy"`
+	MaxAuthAgeSeconds *int64              `json:"maxAuthAgeSeconds"`
+	AllowBasicAuth    *bool               `json:"allowBasicAuth"`
 }
 
 type ApiUpdateBackendResponse struct {
@@ -204,6 +264,240 @@ type ApiListMqttClientsResponse struct {
 	MqttClients []ApiMqttClient `json:"mqtt_clients"`
 }
 
+// mqtt_import
+
+type ApiMqttImportResponse struct {
+	Success       bool `json:"success"`
+	ProfilesCount int  `json:"profiles_count"`
+	ClientsCount  int  `json:"clients_count"`
+}
+
+// service_account
+type ApiServiceAccount struct {
+	ID                string            `json:"id"`
+	Name              string            `json:"name"`
+	Description       string            `json:"description"`
+	AllowedHosts      []string          `json:"allowedHosts"`
+	HasClientSecret   bool              `json:"hasClientSecret"`
+	PublicKey         string            `json:"publicKey"`
+	MqttProfileId     string            `json:"mqttProfileId"`
+	MqttValues        map[string]string `json:"mqttValues"`
+	SshAuthorizedKeys []string          `json:"sshAuthorizedKeys"`
+	CreatedAt         string            `json:"createdAt"`
+	UpdatedAt         string            `json:"updatedAt"`
+	LastUsedAt        string            `json:"lastUsedAt,omitempty"`
+	Disabled          bool              `json:"disabled"`
+}
+
+type ApiUpdateServiceAccountRequest struct {
+	Name         *string   `json:"name"`
+	Description  *string   `json:"description"`
+	AllowedHosts *[]string `json:"allowedHosts"`
+	// PEM-encoded public key used to verify JWT assertions.
+	PublicKey     *string            `json:"publicKey"`
+	MqttProfileId *string            `json:"mqttProfileId"`
+	MqttValues    *map[string]string `json:"mqttValues"`
+	// In authorized_keys format.
+	SshAuthorizedKeys *[]string `json:"sshAuthorizedKeys"`
+	Disabled          *bool     `json:"disabled"`
+}
+
+type ApiUpdateServiceAccountResponse struct {
+}
+
+type ApiListServiceAccountsResponse struct {
+	ServiceAccounts []ApiServiceAccount `json:"serviceAccounts"`
+}
+
+type ApiCreateServiceAccountSecretResponse struct {
+	ClientId string `json:"clientId"`
+	// Only returned once.
+	ClientSecret string `json:"clientSecret"`
+}
+
+// oauth_token
+
+// https://www.rfc-editor.org/rfc/rfc6749#section-5.1
+type ApiOAuthTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Scope       string `json:"scope,omitempty"`
+	// Only set if the openid scope was granted.
+	IdToken string `json:"id_token,omitempty"`
+}
+
+// https://www.rfc-editor.org/rfc/rfc6749#section-5.2
+type ApiOAuthErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// oauth_device_authorization
+
+// https://www.rfc-editor.org/rfc/rfc8628#section-3.2
+type ApiDeviceAuthorizationResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationUri         string `json:"verification_uri"`
+	VerificationUriComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int64  `json:"expires_in"`
+	Interval                int64  `json:"interval"`
+	// The complete verification URI as a QR code, as a data URI.
+	QrCodeUrl string `json:"qr_code_url"`
+}
+
+// device_query
+
+type ApiQueryDeviceRequest struct {
+	UserCode string `json:"userCode"`
+}
+
+type ApiQueryDeviceError struct {
+	InvalidCode        bool `json:"invalidCode,omitempty"`
+	NotAllowed         bool `json:"notAllowed,omitempty"`
+	InvalidCredentials bool `json:"invalidCredentials,omitempty"`
+}
+
+type ApiQueryDeviceResponse struct {
+	Error              *ApiQueryDeviceError `json:"error,omitempty"`
+	UserCode           string               `json:"userCode,omitempty"`
+	ClientName         string               `json:"clientName,omitempty"`
+	Scopes             []string             `json:"scopes,omitempty"`
+	RequestorUserAgent string               `json:"requestorUserAgent,omitempty"`
+	RequestorIP        string               `json:"requestorIp,omitempty"`
+	Token              string               `json:"token,omitempty"`
+	AssertionRequest   *ApiAssertionRequest `json:"assertionRequest,omitempty"`
+}
+
+// device_confirm
+
+type ApiConfirmDeviceRequest struct {
+	Token      string                 `json:"token"`
+	Credential ApiAssertionCredential `json:"credential"`
+}
+
+type ApiConfirmDeviceError struct {
+	InvalidToken      bool `json:"invalidToken,omitempty"`
+	InvalidCredential bool `json:"invalidCredential,omitempty"`
+}
+
+type ApiConfirmDeviceResponse struct {
+	Error *ApiConfirmDeviceError `json:"error,omitempty"`
+}
+
+// device_deny
+
+type ApiDenyDeviceRequest struct {
+	UserCode string `json:"userCode"`
+}
+
+type ApiDenyDeviceResponse struct {
+}
+
+// oidc_client
+
+type ApiOidcClient struct {
+	ID              string   `json:"id"`
+	Name            string   `json:"name"`
+	RedirectUris    []string `json:"redirectUris"`
+	IsPublic        bool     `json:"isPublic"`
+	HasClientSecret bool     `json:"hasClientSecret"`
+	AllowedGroups   []string `json:"allowedGroups"`
+	CreatedAt       string   `json:"createdAt"`
+	UpdatedAt       string   `json:"updatedAt"`
+}
+
+type ApiUpdateOidcClientRequest struct {
+	Name          *string   `json:"name"`
+	RedirectUris  *[]string `json:"redirectUris"`
+	IsPublic      *bool     `json:"isPublic"`
+	AllowedGroups *[]string `json:"allowedGroups"`
+}
+
+type ApiUpdateOidcClientResponse struct {
+}
+
+type ApiListOidcClientsResponse struct {
+	OidcClients []ApiOidcClient `json:"oidcClients"`
+}
+
+type ApiCreateOidcClientSecretResponse struct {
+	ClientId string `json:"clientId"`
+	// Only returned once.
+	ClientSecret string `json:"clientSecret"`
+}
+
+// webauthn_well_known
+
+// https://w3c.github.io/webauthn/#sctn-related-origins
+type ApiWebauthnWellKnownResponse struct {
+	Origins []string `json:"origins"`
+}
+
+// oidc_discovery
+
+// https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderMetadata
+type ApiOidcDiscoveryResponse struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	DeviceAuthorizationEndpoint       string   `json:"device_authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserinfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JwksUri                           string   `json:"jwks_uri"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IdTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+}
+
+// https://www.rfc-editor.org/rfc/rfc7517#section-4
+type ApiJsonWebKey struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type ApiJsonWebKeySet struct {
+	Keys []ApiJsonWebKey `json:"keys"`
+}
+
+// upstream_oidc
+
+type ApiUpstreamOidcProvider struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Issuer          string `json:"issuer"`
+	ClientId        string `json:"clientId"`
+	HasClientSecret bool   `json:"hasClientSecret"`
+	LinkByEmail     bool   `json:"linkByEmail"`
+	CreatedAt       string `json:"createdAt"`
+	UpdatedAt       string `json:"updatedAt"`
+}
+
+type ApiUpdateUpstreamOidcProviderRequest struct {
+	Name         *string `json:"name"`
+	Issuer       *string `json:"issuer"`
+	ClientId     *string `json:"clientId"`
+	ClientSecret *string `json:"clientSecret"`
+	LinkByEmail  *bool   `json:"linkByEmail"`
+}
+
+type ApiUpdateUpstreamOidcProviderResponse struct {
+}
+
+type ApiListUpstreamOidcProvidersResponse struct {
+	Providers []ApiUpstreamOidcProvider `json:"providers"`
+}
+
 // user_list
 
 type ApiListUsersResponse struct {
@@ -308,7 +602,8 @@ type ApiRequestSigninPinRequest struct {
 }
 
 type ApiRequestSigninPinError struct {
-	InvalidEmail bool `json:"invalidEmail"`
+	InvalidEmail    bool `json:"invalidEmail"`
+	TooManyRequests bool `json:"tooManyRequests"`
 }
 
 type ApiRequestSigninPinResponse struct {
@@ -323,6 +618,36 @@ type ApiStartSigninResponse struct {
 	AssertionRequest ApiAssertionRequest `json:"assertionRequest,omitempty"`
 }
 
+// signin_upstream
+
+type ApiSigninProvider struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type ApiListSigninProvidersResponse struct {
+	Providers []ApiSigninProvider `json:"providers"`
+	// If sign-in links can be sent by e-mail, see signin_link.
+	SigninLinks bool `json:"signinLinks"`
+}
+
+// signin_link
+
+type ApiSendSigninLinkRequest struct {
+	Email string `json:"email"`
+}
+
+type ApiSendSigninLinkError struct {
+	InternalError bool `json:"internalError,omitempty"`
+	NotConfigured bool `json:"notConfigured,omitempty"`
+}
+
+// To not reveal which users exist, this is also returned for unknown e-mail
+// addresses.
+type ApiSendSigninLinkResponse struct {
+	Error *ApiSendSigninLinkError `json:"error,omitempty"`
+}
+
 // signin_webauthn
 
 type ApiSignInWebauthnRequest struct {
@@ -334,6 +659,8 @@ type ApiSignInWebauthnRequest struct {
 type ApiSignInWebauthnError struct {
 	InternalError     bool `json:"internalError,omitempty"`
 	InvalidCredential bool `json:"invalidCredential,omitempty"`
+	// The user must use a one-time password, but hasn't enrolled one.
+	TotpNotEnrolled bool `json:"totpNotEnrolled,omitempty"`
 }
 
 type ApiSignInWebauthnSuccess struct {
@@ -341,8 +668,41 @@ type ApiSignInWebauthnSuccess struct {
 	Redirect string `json:"redirect"`
 }
 
+// The user must also provide a one-time password, see signin_totp.
+type ApiSignInTotpRequired struct {
+	Token string `json:"token"`
+}
+
 type ApiSignInWebauthResponse struct {
-	Error   *ApiSignInWebauthnError   `json:"error,omitempty"`
+	Error        *ApiSignInWebauthnError   `json:"error,omitempty"`
+	Success      *ApiSignInWebauthnSuccess `json:"success,omitempty"`
+	TotpRequired *ApiSignInTotpRequired    `json:"totpRequired,omitempty"`
+}
+
+// signin_totp
+
+// Either `Token`, as returned when a one-time password is required after
+// signing in, or `Email` of a user that may use one instead of a passkey.
+type ApiSignInTotpRequest struct {
+	Token    string `json:"token,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Code     string `json:"code"`
+	Redirect string `json:"redirect"`
+}
+
+type ApiSignInTotpError struct {
+	InternalError bool `json:"internalError,omitempty"`
+	// The token has expired, and the user has to sign in again.
+	InvalidToken bool `json:"invalidToken,omitempty"`
+	InvalidCode  bool `json:"invalidCode,omitempty"`
+	// Too many failed attempts; try again later.
+	Blocked bool `json:"blocked,omitempty"`
+}
+
+type ApiSignInTotpResponse struct {
+	Error *ApiSignInTotpError `json:"error,omitempty"`
+	// A new token to try again with, if the code was wrong.
+	Token   string                    `json:"token,omitempty"`
 	Success *ApiSignInWebauthnSuccess `json:"success,omitempty"`
 }
 
@@ -422,6 +782,10 @@ type ApiSession struct {
 	RemoteAddr string `json:"remoteAddr"`
 	CreatedAt  string `json:"createdAt"`
 	AccessedAt string `json:"accessedAt"`
+	// Where the session was last used from, which may differ from where the
+	// user signed in.
+	LastUserAgent  string `json:"lastUserAgent"`
+	LastRemoteAddr string `json:"lastRemoteAddr"`
 }
 
 type ApiCredential struct {
@@ -434,28 +798,176 @@ type ApiCredential struct {
 	LastUsedAt string   `json:"lastUsedAt"`
 	UsedBy     []string `json:"used_by_session_ids"`
 	Aaguid     string   `json:"aaguid"`
+	// Set if the signature counter has gone backwards, which indicates that
+	// the passkey may have been cloned.
+	CloneWarning bool `json:"cloneWarning"`
+	// How the passkey violates the passkey policy: "authenticator_not_allowed"
+	// or "cloned".
+	PolicyViolations []string `json:"policyViolations,omitempty"`
+	// Set if the passkey can't be used because of the passkey policy.
+	Blocked bool `json:"blocked,omitempty"`
+	// Where the passkey is stored: "platform", "cross-platform" or empty if
+	// not known.
+	Attachment string `json:"attachment"`
+	// The relying party ID that the passkey was created for. Empty for passkeys
+	// created for the admin FQDN before this was recorded.
+	RpId string `json:"rpId"`
 }
 
 type ApiUser struct {
-	ID             string          `json:"id"`
-	Email          string          `json:"email"`
-	DisplayName    string          `json:"displayName"`
-	AllowedHosts   []string        `json:"allowedHosts"`
-	IsAdmin        bool            `json:"isAdmin"`
-	Credentials    []ApiCredential `json:"credentials"`
-	Sessions       []ApiSession    `json:"sessions"`
-	CurrentSession *ApiSession     `json:"currentSession"`
-	SSHKeys        []ApiSSHKey     `json:"sshKeys"`
+	ID                  string                 `json:"id"`
+	Email               string                 `json:"email"`
+	DisplayName         string                 `json:"displayName"`
+	AllowedHosts        []string               `json:"allowedHosts"`
+	IsAdmin             bool                   `json:"isAdmin"`
+	IsDisabled          bool                   `json:"isDisabled"`
+	TotpPolicy          string                 `json:"totpPolicy"`
+	Groups              []string               `json:"groups"`
+	Credentials         []ApiCredential        `json:"credentials"`
+	Sessions            []ApiSession           `json:"sessions"`
+	CurrentSession      *ApiSession            `json:"currentSession"`
+	SSHKeys             []ApiSSHKey            `json:"sshKeys"`
+	AccessTokens        []ApiAccessToken       `json:"accessTokens"`
+	AppPasswords        []ApiAppPassword       `json:"appPasswords"`
+	FederatedIdentities []ApiFederatedIdentity `json:"federatedIdentities"`
+	// How the user's passkeys violate the passkey policy: "too_few_passkeys".
+	PasskeyPolicyViolations []string `json:"passkeyPolicyViolations,omitempty"`
+	// Only set for "me", if an administrator is viewing the site as the user.
+	Impersonation *ApiImpersonation `json:"impersonation,omitempty"`
+}
+
+type ApiImpersonation struct {
+	ImpersonatorEmail string `json:"impersonatorEmail"`
+	ExpiresAt         string `json:"expiresAt"`
+}
+
+type ApiFederatedIdentity struct {
+	ProviderId string `json:"providerId"`
+	Subject    string `json:"subject"`
+	Email      string `json:"email"`
+	LinkedAt   string `json:"linkedAt"`
+	LastUsedAt string `json:"lastUsedAt,omitempty"`
+}
+
+// access_token_start
+
+type ApiAccessToken struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Scopes       []string `json:"scopes"`
+	AllowedHosts []string `json:"allowedHosts"`
+	CreatedAt    string   `json:"createdAt"`
+	ExpiresAt    string   `json:"expiresAt"`
+	LastUsedAt   string   `json:"lastUsedAt,omitempty"`
+}
+
+type ApiStartCreateAccessTokenRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	// If empty, the token can access all hosts that the user can access.
+	AllowedHosts []string `json:"allowedHosts"`
+	// Defaults to 30 days.
+	ExpiresInDays int `json:"expiresInDays"`
+}
+
+type ApiStartCreateAccessTokenError struct {
+	InvalidName   bool `json:"invalidName,omitempty"`
+	InvalidScope  bool `json:"invalidScope,omitempty"`
+	InvalidHost   bool `json:"invalidHost,omitempty"`
+	InvalidExpiry bool `json:"invalidExpiry,omitempty"`
+}
+
+type ApiStartCreateAccessTokenAuthenticate struct {
+	Token            string              `json:"token"`
+	AssertionRequest ApiAssertionRequest `json:"assertionRequest"`
+}
+
+type ApiStartCreateAccessTokenResponse struct {
+	Error        *ApiStartCreateAccessTokenError        `json:"error,omitempty"`
+	Authenticate *ApiStartCreateAccessTokenAuthenticate `json:"authenticate,omitempty"`
+}
+
+// access_token_finish
+
+type ApiFinishCreateAccessTokenRequest struct {
+	Token      string                 `json:"token"`
+	Credential ApiAssertionCredential `json:"credential"`
+}
+
+type ApiFinishCreateAccessTokenError struct {
+	FailedAuthentication bool `json:"failedAuthentication,omitempty"`
+}
+
+type ApiFinishCreateAccessTokenResult struct {
+	AccessToken ApiAccessToken `json:"accessToken"`
+	// Only returned once.
+	Secret string `json:"secret"`
+}
+
+type ApiFinishCreateAccessTokenResponse struct {
+	Error  *ApiFinishCreateAccessTokenError  `json:"error,omitempty"`
+	Result *ApiFinishCreateAccessTokenResult `json:"result,omitempty"`
+}
+
+// app_password_create
+
+type ApiAppPassword struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	BackendFqdn    string `json:"backendFqdn"`
+	CreatedAt      string `json:"createdAt"`
+	LastUsedAt     string `json:"lastUsedAt,omitempty"`
+	LastRemoteAddr string `json:"lastRemoteAddr,omitempty"`
+}
+
+type ApiCreateAppPasswordRequest struct {
+	Name        string `json:"name"`
+	BackendFqdn string `json:"backendFqdn"`
+}
+
+type ApiCreateAppPasswordError struct {
+	InvalidName    bool `json:"invalidName,omitempty"`
+	InvalidBackend bool `json:"invalidBackend,omitempty"`
+}
+
+type ApiCreateAppPasswordResult struct {
+	AppPassword ApiAppPassword `json:"appPassword"`
+	Username    string         `json:"username"`
+	// Only returned once.
+	Password string `json:"password"`
+}
+
+type ApiCreateAppPasswordResponse struct {
+	Error  *ApiCreateAppPasswordError  `json:"error,omitempty"`
+	Result *ApiCreateAppPasswordResult `json:"result,omitempty"`
+}
+
+// user_activity
+
+type ApiSessionActivity struct {
+	SessionID       string `json:"sessionId"`
+	FirstAccessedAt string `json:"firstAccessedAt"`
+	LastAccessedAt  string `json:"lastAccessedAt"`
+	RemoteAddr      string `json:"remoteAddr"`
+	UserAgent       string `json:"userAgent"`
+}
+
+type ApiUserActivityResponse struct {
+	// Most recent first.
+	Activity []ApiSessionActivity `json:"activity"`
 }
 
 // user_create
 
 type ApiCreateUserRequest struct {
 	Email string `json:"email"`
+	// Sends the sign-in link to the user by e-mail.
+	SendInvitation bool `json:"sendInvitation"`
 }
 
 type ApiCreateUserResponse struct {
-	ID string `json:"id"`
+	ID             string `json:"id"`
+	InvitationSent bool   `json:"invitationSent"`
 }
 
 // user_update
@@ -465,6 +977,10 @@ type ApiUpdateUserRequest struct {
 	DisplayName  *string   `json:"displayName,omitempty"`
 	Admin        *bool     `json:"admin,omitempty"`
 	AllowedHosts *[]string `json:"allowedHosts,omitempty"`
+	Groups       *[]string `json:"groups,omitempty"`
+	Disabled     *bool     `json:"disabled,omitempty"`
+	// One of "disabled", "fallback" or "required".
+	TotpPolicy *string `json:"totpPolicy,omitempty"`
 }
 
 type ApiUpdateUserResponse struct {
@@ -472,8 +988,321 @@ type ApiUpdateUserResponse struct {
 
 // user_recover
 
+// The request body is optional.
+type ApiUserRecoverRequest struct {
+	// Sends the recovery URL to the user by e-mail.
+	SendEmail bool `json:"sendEmail"`
+}
+
 type ApiUserRecoverResponse struct {
 	RecoveryUrl string `json:"recoveryUrl"`
+	EmailSent   bool   `json:"emailSent"`
+}
+
+// invitation_list
+
+type ApiInvitation struct {
+	ID           string   `json:"id"`
+	Email        string   `json:"email"`
+	DisplayName  string   `json:"displayName"`
+	IsAdmin      bool     `json:"isAdmin"`
+	AllowedHosts []string `json:"allowedHosts"`
+	Groups       []string `json:"groups"`
+	CreatedAt    string   `json:"createdAt"`
+	ExpiresAt    string   `json:"expiresAt"`
+	// The ID of the administrator that created it, or empty if it was created
+	// using the command line.
+	CreatedBy string `json:"createdBy"`
+	// One of "pending", "expired", "revoked" or "redeemed".
+	State string `json:"state"`
+	// The user that was created, if redeemed.
+	UserID string `json:"userId,omitempty"`
+}
+
+type ApiListInvitationsResponse struct {
+	Invitations []ApiInvitation `json:"invitations"`
+}
+
+// invitation_create
+
+type ApiCreateInvitationRequest struct {
+	Email        string   `json:"email"`
+	DisplayName  string   `json:"displayName"`
+	Admin        bool     `json:"admin"`
+	AllowedHosts []string `json:"allowedHosts"`
+	Groups       []string `json:"groups"`
+	// How long the link is valid. Zero means the default of seven days.
+	LifetimeSeconds int64 `json:"lifetimeSeconds"`
+	// Sends the link to the invitee by e-mail.
+	SendEmail bool `json:"sendEmail"`
+}
+
+type ApiCreateInvitationResponse struct {
+	Invitation ApiInvitation `json:"invitation"`
+	Url        string        `json:"url"`
+	EmailSent  bool          `json:"emailSent"`
+}
+
+// invitation_import
+
+// The request body is CSV, with the Content-Type "text/csv", or otherwise a
+// YAML list. The CSV has a header row with the columns "email",
+// "display_name", "admin", "allowed_hosts" and "groups", where only "email"
+// is required and lists are separated by semicolons. The YAML list has items
+// with the same keys. The query parameters "sendEmail" and
+// "lifetimeSeconds" apply to all invitees.
+
+type ApiImportedInvitation struct {
+	Email string `json:"email"`
+	// Empty if the invitation couldn't be created.
+	Url       string `json:"url,omitempty"`
+	Error     string `json:"error,omitempty"`
+	EmailSent bool   `json:"emailSent"`
+}
+
+type ApiImportInvitationsResponse struct {
+	Invitations []ApiImportedInvitation `json:"invitations"`
+}
+
+// invitation_redeem
+
+type ApiRedeemInvitationRequest struct {
+	Token string `json:"token"`
+}
+
+type ApiRedeemInvitationError struct {
+	InvalidToken bool `json:"invalidToken,omitempty"`
+	Expired      bool `json:"expired,omitempty"`
+	Revoked      bool `json:"revoked,omitempty"`
+	Redeemed     bool `json:"redeemed,omitempty"`
+	UserExists   bool `json:"userExists,omitempty"`
+}
+
+type ApiRedeemInvitationSuccess struct {
+	Cookie   string `json:"cookie"`
+	Redirect string `json:"redirect"`
+}
+
+type ApiRedeemInvitationResponse struct {
+	Error   *ApiRedeemInvitationError   `json:"error,omitempty"`
+	Success *ApiRedeemInvitationSuccess `json:"success,omitempty"`
+}
+
+// user_impersonate
+
+type ApiUserImpersonateResponse struct {
+	// The session cookie to view the site as the user. It can't be used to
+	// make changes.
+	Cookie    string `json:"cookie"`
+	ExpiresAt string `json:"expiresAt"`
+}
+
+// impersonation_stop
+
+type ApiImpersonationStopResponse struct {
+	// The administrator's own session cookie, or empty if that session no
+	// longer exists.
+	Cookie string `json:"cookie"`
+}
+
+// user_access
+
+// Why a user may or may not access a URL through the proxy.
+type ApiUserAccessResponse struct {
+	Url     string `json:"url"`
+	Host    string `json:"host"`
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason"`
+	// What else applies to the request, e.g. that a recent sign-in is required.
+	Notes []string `json:"notes"`
+}
+
+// settings_get
+
+// A zero value means that there is no limit.
+type ApiSessionPolicy struct {
+	AbsoluteLifetimeSeconds int64 `json:"absoluteLifetimeSeconds"`
+	IdleTimeoutSeconds      int64 `json:"idleTimeoutSeconds"`
+}
+
+// An empty host means that no e-mails are sent.
+type ApiSmtpSettings struct {
+	Host     string `json:"host"`
+	Port     uint32 `json:"port"`
+	Username string `json:"username"`
+	// Never returned. When updating, an empty password keeps the current one.
+	Password    string `json:"password,omitempty"`
+	PasswordSet bool   `json:"passwordSet"`
+	From        string `json:"from"`
+	ImplicitTls bool   `json:"implicitTls"`
+}
+
+// Where security notifications are sent. Empty values disable a channel.
+type ApiNotificationSettings struct {
+	EmailUser   bool   `json:"emailUser"`
+	EmailAdmins bool   `json:"emailAdmins"`
+	WebhookUrl  string `json:"webhookUrl"`
+	// Never returned. When updating, an empty secret keeps the current one.
+	WebhookSecret    string `json:"webhookSecret,omitempty"`
+	WebhookSecretSet bool   `json:"webhookSecretSet"`
+	MqttTopic        string `json:"mqttTopic"`
+}
+
+// Limits how often clients may try to authenticate. Zero values use defaults.
+type ApiBanPolicy struct {
+	Disabled                bool   `json:"disabled"`
+	MaxFailures             uint32 `json:"maxFailures"`
+	FailureWindowSeconds    int64  `json:"failureWindowSeconds"`
+	BanDurationSeconds      int64  `json:"banDurationSeconds"`
+	MaxBanDurationSeconds   int64  `json:"maxBanDurationSeconds"`
+	SigninRequestsPerMinute uint32 `json:"signinRequestsPerMinute"`
+	// For endpoints that are polled or used by machines, such as the token
+	// endpoint.
+	TokenRequestsPerMinute uint32 `json:"tokenRequestsPerMinute"`
+	// Addresses or CIDR ranges of reverse proxies, whose X-Forwarded-For
+	// headers are trusted.
+	TrustedProxies []string `json:"trustedProxies"`
+}
+
+// An authenticator that passkeys can be stored in.
+type ApiAuthenticator struct {
+	Aaguid string `json:"aaguid"`
+	// Ignored when updating, and empty for unknown authenticators.
+	Name string `json:"name,omitempty"`
+}
+
+// Which passkeys users may have.
+type ApiPasskeyPolicy struct {
+	// If not empty, only these authenticators are allowed.
+	AllowedAuthenticators []ApiAuthenticator `json:"allowedAuthenticators"`
+	DeniedAuthenticators  []ApiAuthenticator `json:"deniedAuthenticators"`
+	// What to do with passkeys that may have been cloned: "flag" (default) or
+	// "block".
+	CloneAction string `json:"cloneAction"`
+	// The number of passkeys each user should have. Zero means no minimum.
+	MinPasskeys uint32 `json:"minPasskeys"`
+}
+
+type ApiWebauthnSettings struct {
+	// Where passkeys may be stored: "platform" (default), "cross-platform" or
+	// "any", which lets the user choose.
+	Attachment string `json:"attachment"`
+	// "required" (default), "preferred" or "discouraged".
+	UserVerification string `json:"userVerification"`
+	// "required" (default), "preferred" or "discouraged".
+	ResidentKey string `json:"residentKey"`
+	// Create passkeys for the site FQDN instead of the admin FQDN, so that
+	// they can be used on all of the site's subdomains.
+	UseSiteRpId bool `json:"useSiteRpId"`
+	// Let backends that aren't public use the site's passkeys for step-up
+	// authentication. Requires UseSiteRpId.
+	RelatedOrigins bool `json:"relatedOrigins"`
+}
+
+type ApiSettings struct {
+	SessionPolicy ApiSessionPolicy        `json:"sessionPolicy"`
+	Smtp          ApiSmtpSettings         `json:"smtp"`
+	Notifications ApiNotificationSettings `json:"notifications"`
+	BanPolicy     ApiBanPolicy            `json:"banPolicy"`
+	PasskeyPolicy ApiPasskeyPolicy        `json:"passkeyPolicy"`
+	Webauthn      ApiWebauthnSettings     `json:"webauthn"`
+}
+
+// settings_update
+
+type ApiUpdateSettingsRequest struct {
+	SessionPolicy *ApiSessionPolicy        `json:"sessionPolicy"`
+	Smtp          *ApiSmtpSettings         `json:"smtp"`
+	Notifications *ApiNotificationSettings `json:"notifications"`
+	BanPolicy     *ApiBanPolicy            `json:"banPolicy"`
+	PasskeyPolicy *ApiPasskeyPolicy        `json:"passkeyPolicy"`
+	Webauthn      *ApiWebauthnSettings     `json:"webauthn"`
+}
+
+type ApiUpdateSettingsResponse struct {
+}
+
+// audit_list
+
+// A changed field. The values are JSON, and are empty if the field wasn't set.
+type ApiAuditChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+type ApiAuditRecord struct {
+	ID         string           `json:"id"`
+	Timestamp  string           `json:"timestamp"`
+	ActorId    string           `json:"actorId"`
+	ActorName  string           `json:"actorName"`
+	SessionId  string           `json:"sessionId,omitempty"`
+	Ip         string           `json:"ip"`
+	Action     string           `json:"action"`
+	TargetType string           `json:"targetType"`
+	TargetId   string           `json:"targetId"`
+	Changes    []ApiAuditChange `json:"changes"`
+}
+
+type ApiListAuditRecordsResponse struct {
+	Records []ApiAuditRecord `json:"records"`
+}
+
+// A failed attempt to authenticate.
+type ApiSecurityEvent struct {
+	ID        string `json:"id"`
+	Timestamp string `json:"timestamp"`
+	Source    string `json:"source"`
+	Ip        string `json:"ip"`
+	Principal string `json:"principal,omitempty"`
+	Reason    string `json:"reason"`
+}
+
+type ApiListSecurityEventsResponse struct {
+	Events []ApiSecurityEvent `json:"events"`
+}
+
+// An IP address that is banned after failing to authenticate too often.
+type ApiBan struct {
+	Ip          string `json:"ip"`
+	BannedUntil string `json:"bannedUntil"`
+	// The number of bans in a row, including this one.
+	BanCount uint32 `json:"banCount"`
+	// What caused the ban: "http", "ssh" or "mqtt".
+	Source string `json:"source"`
+}
+
+type ApiListBansResponse struct {
+	Bans []ApiBan `json:"bans"`
+}
+
+// authenticator_list
+
+type ApiListAuthenticatorsResponse struct {
+	Authenticators []ApiAuthenticator `json:"authenticators"`
+}
+
+// config_plan
+
+// A change to an entity in the configuration. `Action` is "create", "update"
+// or "delete".
+type ApiConfigChange struct {
+	Kind    string           `json:"kind"`
+	ID      string           `json:"id"`
+	Action  string           `json:"action"`
+	Changes []ApiAuditChange `json:"changes"`
+}
+
+type ApiConfigPlanResponse struct {
+	Changes []ApiConfigChange `json:"changes"`
+}
+
+// config_apply
+
+type ApiConfigApplyResponse struct {
+	Changes []ApiConfigChange `json:"changes"`
+	// Set if the changes could not be applied, in which case none were.
+	Error string `json:"error,omitempty"`
 }
 
 // testing_setup
@@ -0,0 +1,103 @@
+// Package client calls the JSON API of an Ubergang server. The methods are
+// generated from api.Endpoints, like the OpenAPI document served at
+// "/api/openapi.json".
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Client makes requests to the admin host of a server.
+type Client struct {
+	baseUrl string
+	token   string
+	// Used to make requests. Set a cookie jar to use sessions, e.g. when
+	// signing in.
+	HttpClient *http.Client
+}
+
+// New returns a client for the admin host at `baseUrl`, e.g.
+// "https://admin.example.com". Unless empty, `token` is an access token with
+// the "admin" scope, which is sent with every request.
+func New(baseUrl, token string) *Client {
+	return &Client{
+		baseUrl:    strings.TrimSuffix(baseUrl, "/"),
+		token:      token,
+		HttpClient: http.DefaultClient,
+	}
+}
+
+// Error is returned when the server responds with an unsuccessful status.
+type Error struct {
+	StatusCode int
+	// The response body, which describes the error.
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// call makes a request. A `body` of []byte is sent as is with `contentType`,
+// and any other non-nil `body` as JSON. A `res` of *[]byte receives the
+// response body as is, and any other non-nil `res` is decoded from JSON.
+func (c *Client) call(ctx context.Context, method, path string, query url.Values, contentType string, body, res any) error {
+	u := c.baseUrl + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if data, ok := body.([]byte); ok {
+		reader = bytes.NewReader(data)
+	} else if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+		contentType = "application/json"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp, err := c.HttpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &Error{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+	}
+	switch res := res.(type) {
+	case nil:
+	case *[]byte:
+		*res = data
+	default:
+		return json.Unmarshal(data, res)
+	}
+	return nil
+}
@@ -0,0 +1,754 @@
+// Code generated by apigen. DO NOT EDIT.
+
+package client
+
+import (
+	"boivie/ubergang/server/api"
+	"context"
+	"net/url"
+)
+
+// StartEnroll calls `POST /api/enroll/start`, to start enrolling a passkey.
+func (c *Client) StartEnroll(ctx context.Context, req *api.ApiStartEnrollRequest) (*api.ApiStartEnrollResponse, error) {
+	var res api.ApiStartEnrollResponse
+	if err := c.call(ctx, "POST", "/api/enroll/start", nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// FinishEnroll calls `POST /api/enroll/finish`, to finish enrolling a passkey.
+func (c *Client) FinishEnroll(ctx context.Context, req *api.ApiFinishEnrollRequest) (*api.ApiFinishEnrollResponse, error) {
+	var res api.ApiFinishEnrollResponse
+	if err := c.call(ctx, "POST", "/api/enroll/finish", nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// StartTotpEnroll calls `POST /api/totp/enroll/start`, to start enrolling an authenticator app.
+func (c *Client) StartTotpEnroll(ctx context.Context) (*api.ApiStartTotpEnrollResponse, error) {
+	var res api.ApiStartTotpEnrollResponse
+	if err := c.call(ctx, "POST", "/api/totp/enroll/start", nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// FinishTotpEnroll calls `POST /api/totp/enroll/finish`, to finish enrolling an authenticator app.
+func (c *Client) FinishTotpEnroll(ctx context.Context, req *api.ApiFinishTotpEnrollRequest) (*api.ApiFinishTotpEnrollResponse, error) {
+	var res api.ApiFinishTotpEnrollResponse
+	if err := c.call(ctx, "POST", "/api/totp/enroll/finish", nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// StartSignin calls `GET /api/signin/start`, to start signing in with a passkey.
+func (c *Client) StartSignin(ctx context.Context) (*api.ApiStartSigninResponse, error) {
+	var res api.ApiStartSigninResponse
+	if err := c.call(ctx, "GET", "/api/signin/start", nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// SigninEmail calls `POST /api/signin/email`, to look up how a user can sign in.
+func (c *Client) SigninEmail(ctx context.Context, req *api.ApiSignInEmailRequest) (*api.ApiSigninEmailResponse, error) {
+	var res api.ApiSigninEmailResponse
+	if err := c.call(ctx, "POST", "/api/signin/email", nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// SigninWebauthn calls `POST /api/signin/webauthn`, to sign in with a passkey.
+func (c *Client) SigninWebauthn(ctx context.Context, req *api.ApiSignInWebauthnRequest) (*api.ApiSignInWebauthResponse, error) {
+	var res api.ApiSignInWebauthResponse
+	if err := c.call(ctx, "POST", "/api/signin/webauthn", nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// SigninTotp calls `POST /api/signin/totp`, to sign in with a one-time password.
+func (c *Client) SigninTotp(ctx context.Context, req *api.ApiSignInTotpRequest) (*api.ApiSignInTotpResponse, error) {
+	var res api.ApiSignInTotpResponse
+	if err := c.call(ctx, "POST", "/api/signin/totp", nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// SendSigninLink calls `POST /api/signin/link`, to e-mail a sign-in link.
+func (c *Client) SendSigninLink(ctx context.Context, req *api.ApiSendSigninLinkRequest) (*api.ApiSendSigninLinkResponse, error) {
+	var res api.ApiSendSigninLinkResponse
+	if err := c.call(ctx, "POST", "/api/signin/link", nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// RequestSigninPin calls `POST /api/signin/pin/request`, to request a PIN to sign in using another device.
+func (c *Client) RequestSigninPin(ctx context.Context, req *api.ApiRequestSigninPinRequest) (*api.ApiRequestSigninPinResponse, error) {
+	var res api.ApiRequestSigninPinResponse
+	if err := c.call(ctx, "POST", "/api/signin/pin/request", nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// PollSigninPin calls `POST /api/signin/pin/poll`, to poll whether a PIN has been confirmed.
+func (c *Client) PollSigninPin(ctx context.Context, req *api.ApiPollSigninPinRequest) (*api.ApiPollSigninPinResponse, error) {
+	var res api.ApiPollSigninPinResponse
+	if err := c.call(ctx, "POST", "/api/signin/pin/poll", nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// QuerySigninPin calls `POST /api/signin/pin/query`, to look up a PIN to confirm.
+func (c *Client) QuerySigninPin(ctx context.Context, req *api.ApiQuerySigninPinRequest) (*api.ApiQuerySigninPinResponse, error) {
+	var res api.ApiQuerySigninPinResponse
+	if err := c.call(ctx, "POST", "/api/signin/pin/query", nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ConfirmSigninPin calls `POST /api/signin/pin/confirm`, to confirm a PIN, signing in the other device.
+func (c *Client) ConfirmSigninPin(ctx context.Context, req *api.ApiConfirmSigninPinRequest) (*api.ApiConfirmSigninPinResponse, error) {
+	var res api.ApiConfirmSigninPinResponse
+	if err := c.call(ctx, "POST", "/api/signin/pin/confirm", nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListSigninProviders calls `GET /api/signin/upstream`, to list upstream OpenID Connect providers to sign in with.
+func (c *Client) ListSigninProviders(ctx context.Context) (*api.ApiListSigninProvidersResponse, error) {
+	var res api.ApiListSigninProvidersResponse
+	if err := c.call(ctx, "GET", "/api/signin/upstream", nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetConfirmSshKey calls `GET /api/ssh-key/{id}/confirm`, to look up a proposed SSH key to confirm.
+func (c *Client) GetConfirmSshKey(ctx context.Context, id string) (*api.ApiGetConfirmSshKeyResponse, error) {
+	var res api.ApiGetConfirmSshKeyResponse
+	if err := c.call(ctx, "GET", "/api/ssh-key/"+url.PathEscape(id)+"/confirm", nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ConfirmSshKey calls `POST /api/ssh-key/{id}/confirm`, to confirm a proposed SSH key.
+func (c *Client) ConfirmSshKey(ctx context.Context, id string, req *api.ApiPostConfirmSshKeyRequest) (*api.ApiPostConfirmSshKeyResponse, error) {
+	var res api.ApiPostConfirmSshKeyResponse
+	if err := c.call(ctx, "POST", "/api/ssh-key/"+url.PathEscape(id)+"/confirm", nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetSshKey calls `GET /api/ssh-key/{id}`, to get an SSH key.
+func (c *Client) GetSshKey(ctx context.Context, id string) (*api.ApiSSHKey, error) {
+	var res api.ApiSSHKey
+	if err := c.call(ctx, "GET", "/api/ssh-key/"+url.PathEscape(id), nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ProposeSshKey calls `POST /api/ssh-key/{id}`, to propose a new public key, to be confirmed by the user.
+func (c *Client) ProposeSshKey(ctx context.Context, id string, req *api.ApiProposeSshKeyRequest) (*api.ApiProposeSshKeyResponse, error) {
+	var res api.ApiProposeSshKeyResponse
+	if err := c.call(ctx, "POST", "/api/ssh-key/"+url.PathEscape(id), nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CreateSshKey calls `POST /api/ssh-key`, to create an SSH key.
+func (c *Client) CreateSshKey(ctx context.Context, req *api.ApiCreateSshKeyRequest) (*api.ApiCreateSshKeyResponse, error) {
+	var res api.ApiCreateSshKeyResponse
+	if err := c.call(ctx, "POST", "/api/ssh-key", nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// UpdateBackend calls `POST /api/backend/{fqdn}`, to create or update a backend.
+func (c *Client) UpdateBackend(ctx context.Context, fqdn string, req *api.ApiUpdateBackendRequest) (*api.ApiUpdateBackendResponse, error) {
+	var res api.ApiUpdateBackendResponse
+	if err := c.call(ctx, "POST", "/api/backend/"+url.PathEscape(fqdn), nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetBackend calls `GET /api/backend/{fqdn}`, to get a backend.
+func (c *Client) GetBackend(ctx context.Context, fqdn string) (*api.ApiBackend, error) {
+	var res api.ApiBackend
+	if err := c.call(ctx, "GET", "/api/backend/"+url.PathEscape(fqdn), nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListBackends calls `GET /api/backend`, to list backends.
+func (c *Client) ListBackends(ctx context.Context) (*api.ApiListBackendsResponse, error) {
+	var res api.ApiListBackendsResponse
+	if err := c.call(ctx, "GET", "/api/backend", nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteBackend calls `DELETE /api/backend/{fqdn}`, to delete a backend.
+func (c *Client) DeleteBackend(ctx context.Context, fqdn string) error {
+	return c.call(ctx, "DELETE", "/api/backend/"+url.PathEscape(fqdn), nil, "", nil, nil)
+}
+
+// UpdateMqttProfile calls `POST /api/mqtt-profile/{id}`, to create or update an MQTT profile.
+func (c *Client) UpdateMqttProfile(ctx context.Context, id string, req *api.ApiUpdateMqttProfileRequest) (*api.ApiUpdateMqttProfileResponse, error) {
+	var res api.ApiUpdateMqttProfileResponse
+	if err := c.call(ctx, "POST", "/api/mqtt-profile/"+url.PathEscape(id), nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetMqttProfile calls `GET /api/mqtt-profile/{id}`, to get an MQTT profile.
+func (c *Client) GetMqttProfile(ctx context.Context, id string) (*api.ApiMqttProfile, error) {
+	var res api.ApiMqttProfile
+	if err := c.call(ctx, "GET", "/api/mqtt-profile/"+url.PathEscape(id), nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListMqttProfiles calls `GET /api/mqtt-profile`, to list MQTT profiles.
+func (c *Client) ListMqttProfiles(ctx context.Context) (*api.ApiListMqttProfilesResponse, error) {
+	var res api.ApiListMqttProfilesResponse
+	if err := c.call(ctx, "GET", "/api/mqtt-profile", nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteMqttProfile calls `DELETE /api/mqtt-profile/{id}`, to delete an MQTT profile.
+func (c *Client) DeleteMqttProfile(ctx context.Context, id string) error {
+	return c.call(ctx, "DELETE", "/api/mqtt-profile/"+url.PathEscape(id), nil, "", nil, nil)
+}
+
+// UpdateMqttClient calls `POST /api/mqtt-client/{id}`, to create or update an MQTT client.
+func (c *Client) UpdateMqttClient(ctx context.Context, id string, req *api.ApiUpdateMqttClientRequest) (*api.ApiUpdateMqttClientResponse, error) {
+	var res api.ApiUpdateMqttClientResponse
+	if err := c.call(ctx, "POST", "/api/mqtt-client/"+url.PathEscape(id), nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetMqttClient calls `GET /api/mqtt-client/{id}`, to get an MQTT client.
+func (c *Client) GetMqttClient(ctx context.Context, id string) (*api.ApiMqttClient, error) {
+	var res api.ApiMqttClient
+	if err := c.call(ctx, "GET", "/api/mqtt-client/"+url.PathEscape(id), nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListMqttClients calls `GET /api/mqtt-client`, to list MQTT clients.
+func (c *Client) ListMqttClients(ctx context.Context) (*api.ApiListMqttClientsResponse, error) {
+	var res api.ApiListMqttClientsResponse
+	if err := c.call(ctx, "GET", "/api/mqtt-client", nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteMqttClient calls `DELETE /api/mqtt-client/{id}`, to delete an MQTT client.
+func (c *Client) DeleteMqttClient(ctx context.Context, id string) error {
+	return c.call(ctx, "DELETE", "/api/mqtt-client/"+url.PathEscape(id), nil, "", nil, nil)
+}
+
+// ImportMqtt calls `POST /api/mqtt/import`, to import MQTT profiles and clients from YAML.
+func (c *Client) ImportMqtt(ctx context.Context, body []byte) (*api.ApiMqttImportResponse, error) {
+	var res api.ApiMqttImportResponse
+	if err := c.call(ctx, "POST", "/api/mqtt/import", nil, "application/x-yaml", body, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ExportMqtt calls `GET /api/mqtt/export`, to export MQTT profiles and clients as YAML.
+func (c *Client) ExportMqtt(ctx context.Context) ([]byte, error) {
+	var res []byte
+	if err := c.call(ctx, "GET", "/api/mqtt/export", nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// CreateServiceAccountSecret calls `POST /api/service-account/{id}/secret`, to create a new client secret for a service account.
+func (c *Client) CreateServiceAccountSecret(ctx context.Context, id string) (*api.ApiCreateServiceAccountSecretResponse, error) {
+	var res api.ApiCreateServiceAccountSecretResponse
+	if err := c.call(ctx, "POST", "/api/service-account/"+url.PathEscape(id)+"/secret", nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// UpdateServiceAccount calls `POST /api/service-account/{id}`, to create or update a service account.
+func (c *Client) UpdateServiceAccount(ctx context.Context, id string, req *api.ApiUpdateServiceAccountRequest) (*api.ApiUpdateServiceAccountResponse, error) {
+	var res api.ApiUpdateServiceAccountResponse
+	if err := c.call(ctx, "POST", "/api/service-account/"+url.PathEscape(id), nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetServiceAccount calls `GET /api/service-account/{id}`, to get a service account.
+func (c *Client) GetServiceAccount(ctx context.Context, id string) (*api.ApiServiceAccount, error) {
+	var res api.ApiServiceAccount
+	if err := c.call(ctx, "GET", "/api/service-account/"+url.PathEscape(id), nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListServiceAccounts calls `GET /api/service-account`, to list service accounts.
+func (c *Client) ListServiceAccounts(ctx context.Context) (*api.ApiListServiceAccountsResponse, error) {
+	var res api.ApiListServiceAccountsResponse
+	if err := c.call(ctx, "GET", "/api/service-account", nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteServiceAccount calls `DELETE /api/service-account/{id}`, to delete a service account.
+func (c *Client) DeleteServiceAccount(ctx context.Context, id string) error {
+	return c.call(ctx, "DELETE", "/api/service-account/"+url.PathEscape(id), nil, "", nil, nil)
+}
+
+// CreateOidcClientSecret calls `POST /api/oidc-client/{id}/secret`, to create a new client secret for an OIDC client.
+func (c *Client) CreateOidcClientSecret(ctx context.Context, id string) (*api.ApiCreateOidcClientSecretResponse, error) {
+	var res api.ApiCreateOidcClientSecretResponse
+	if err := c.call(ctx, "POST", "/api/oidc-client/"+url.PathEscape(id)+"/secret", nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// UpdateOidcClient calls `POST /api/oidc-client/{id}`, to create or update an OIDC client.
+func (c *Client) UpdateOidcClient(ctx context.Context, id string, req *api.ApiUpdateOidcClientRequest) (*api.ApiUpdateOidcClientResponse, error) {
+	var res api.ApiUpdateOidcClientResponse
+	if err := c.call(ctx, "POST", "/api/oidc-client/"+url.PathEscape(id), nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetOidcClient calls `GET /api/oidc-client/{id}`, to get an OIDC client.
+func (c *Client) GetOidcClient(ctx context.Context, id string) (*api.ApiOidcClient, error) {
+	var res api.ApiOidcClient
+	if err := c.call(ctx, "GET", "/api/oidc-client/"+url.PathEscape(id), nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListOidcClients calls `GET /api/oidc-client`, to list OIDC clients.
+func (c *Client) ListOidcClients(ctx context.Context) (*api.ApiListOidcClientsResponse, error) {
+	var res api.ApiListOidcClientsResponse
+	if err := c.call(ctx, "GET", "/api/oidc-client", nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteOidcClient calls `DELETE /api/oidc-client/{id}`, to delete an OIDC client.
+func (c *Client) DeleteOidcClient(ctx context.Context, id string) error {
+	return c.call(ctx, "DELETE", "/api/oidc-client/"+url.PathEscape(id), nil, "", nil, nil)
+}
+
+// UpdateUpstreamOidcProvider calls `POST /api/upstream-oidc/{id}`, to create or update an upstream OpenID Connect provider.
+func (c *Client) UpdateUpstreamOidcProvider(ctx context.Context, id string, req *api.ApiUpdateUpstreamOidcProviderRequest) (*api.ApiUpdateUpstreamOidcProviderResponse, error) {
+	var res api.ApiUpdateUpstreamOidcProviderResponse
+	if err := c.call(ctx, "POST", "/api/upstream-oidc/"+url.PathEscape(id), nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetUpstreamOidcProvider calls `GET /api/upstream-oidc/{id}`, to get an upstream OpenID Connect provider.
+func (c *Client) GetUpstreamOidcProvider(ctx context.Context, id string) (*api.ApiUpstreamOidcProvider, error) {
+	var res api.ApiUpstreamOidcProvider
+	if err := c.call(ctx, "GET", "/api/upstream-oidc/"+url.PathEscape(id), nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListUpstreamOidcProviders calls `GET /api/upstream-oidc`, to list upstream OpenID Connect providers.
+func (c *Client) ListUpstreamOidcProviders(ctx context.Context) (*api.ApiListUpstreamOidcProvidersResponse, error) {
+	var res api.ApiListUpstreamOidcProvidersResponse
+	if err := c.call(ctx, "GET", "/api/upstream-oidc", nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteUpstreamOidcProvider calls `DELETE /api/upstream-oidc/{id}`, to delete an upstream OpenID Connect provider.
+func (c *Client) DeleteUpstreamOidcProvider(ctx context.Context, id string) error {
+	return c.call(ctx, "DELETE", "/api/upstream-oidc/"+url.PathEscape(id), nil, "", nil, nil)
+}
+
+// QueryDevice calls `POST /api/device/query`, to look up a device authorization by its user code.
+func (c *Client) QueryDevice(ctx context.Context, req *api.ApiQueryDeviceRequest) (*api.ApiQueryDeviceResponse, error) {
+	var res api.ApiQueryDeviceResponse
+	if err := c.call(ctx, "POST", "/api/device/query", nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ConfirmDevice calls `POST /api/device/confirm`, to authorize a device.
+func (c *Client) ConfirmDevice(ctx context.Context, req *api.ApiConfirmDeviceRequest) (*api.ApiConfirmDeviceResponse, error) {
+	var res api.ApiConfirmDeviceResponse
+	if err := c.call(ctx, "POST", "/api/device/confirm", nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DenyDevice calls `POST /api/device/deny`, to deny a device authorization.
+func (c *Client) DenyDevice(ctx context.Context, req *api.ApiDenyDeviceRequest) (*api.ApiDenyDeviceResponse, error) {
+	var res api.ApiDenyDeviceResponse
+	if err := c.call(ctx, "POST", "/api/device/deny", nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ExportConfig calls `GET /api/config`, to export the configuration as YAML.
+func (c *Client) ExportConfig(ctx context.Context) ([]byte, error) {
+	var res []byte
+	if err := c.call(ctx, "GET", "/api/config", nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// PlanConfig calls `POST /api/config/plan`, to show the changes that applying a configuration would make.
+func (c *Client) PlanConfig(ctx context.Context, body []byte) (*api.ApiConfigPlanResponse, error) {
+	var res api.ApiConfigPlanResponse
+	if err := c.call(ctx, "POST", "/api/config/plan", nil, "application/x-yaml", body, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ApplyConfig calls `POST /api/config/apply`, to apply a configuration.
+func (c *Client) ApplyConfig(ctx context.Context, body []byte) (*api.ApiConfigApplyResponse, error) {
+	var res api.ApiConfigApplyResponse
+	if err := c.call(ctx, "POST", "/api/config/apply", nil, "application/x-yaml", body, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// UpdateCredential calls `POST /api/credential/{id}`, to rename a passkey.
+func (c *Client) UpdateCredential(ctx context.Context, id string, req *api.ApiUpdateCredentialRequest) (*api.ApiUpdateCredentialResponse, error) {
+	var res api.ApiUpdateCredentialResponse
+	if err := c.call(ctx, "POST", "/api/credential/"+url.PathEscape(id), nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteCredential calls `DELETE /api/credential/{id}`, to delete a passkey.
+func (c *Client) DeleteCredential(ctx context.Context, id string) error {
+	return c.call(ctx, "DELETE", "/api/credential/"+url.PathEscape(id), nil, "", nil, nil)
+}
+
+// DeleteSession calls `DELETE /api/session/{id}`, to sign out a session.
+func (c *Client) DeleteSession(ctx context.Context, id string) error {
+	return c.call(ctx, "DELETE", "/api/session/"+url.PathEscape(id), nil, "", nil, nil)
+}
+
+// StartCreateAccessToken calls `POST /api/access-token/start`, to start creating an access token.
+func (c *Client) StartCreateAccessToken(ctx context.Context, req *api.ApiStartCreateAccessTokenRequest) (*api.ApiStartCreateAccessTokenResponse, error) {
+	var res api.ApiStartCreateAccessTokenResponse
+	if err := c.call(ctx, "POST", "/api/access-token/start", nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// FinishCreateAccessToken calls `POST /api/access-token/finish`, to finish creating an access token.
+func (c *Client) FinishCreateAccessToken(ctx context.Context, req *api.ApiFinishCreateAccessTokenRequest) (*api.ApiFinishCreateAccessTokenResponse, error) {
+	var res api.ApiFinishCreateAccessTokenResponse
+	if err := c.call(ctx, "POST", "/api/access-token/finish", nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteAccessToken calls `DELETE /api/access-token/{id}`, to revoke an access token.
+func (c *Client) DeleteAccessToken(ctx context.Context, id string) error {
+	return c.call(ctx, "DELETE", "/api/access-token/"+url.PathEscape(id), nil, "", nil, nil)
+}
+
+// CreateAppPassword calls `POST /api/app-password`, to create an app password.
+func (c *Client) CreateAppPassword(ctx context.Context, req *api.ApiCreateAppPasswordRequest) (*api.ApiCreateAppPasswordResponse, error) {
+	var res api.ApiCreateAppPasswordResponse
+	if err := c.call(ctx, "POST", "/api/app-password", nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteAppPassword calls `DELETE /api/app-password/{id}`, to revoke an app password.
+func (c *Client) DeleteAppPassword(ctx context.Context, id string) error {
+	return c.call(ctx, "DELETE", "/api/app-password/"+url.PathEscape(id), nil, "", nil, nil)
+}
+
+// CreateUser calls `POST /api/user`, to create a user.
+func (c *Client) CreateUser(ctx context.Context, req *api.ApiCreateUserRequest) (*api.ApiCreateUserResponse, error) {
+	var res api.ApiCreateUserResponse
+	if err := c.call(ctx, "POST", "/api/user", nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListUsers calls `GET /api/user`, to list users.
+func (c *Client) ListUsers(ctx context.Context) (*api.ApiListUsersResponse, error) {
+	var res api.ApiListUsersResponse
+	if err := c.call(ctx, "GET", "/api/user", nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetUser calls `GET /api/user/{id}`, to get a user, or the signed in user with the id "me".
+func (c *Client) GetUser(ctx context.Context, id string) (*api.ApiUser, error) {
+	var res api.ApiUser
+	if err := c.call(ctx, "GET", "/api/user/"+url.PathEscape(id), nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// UpdateUser calls `POST /api/user/{id}`, to update a user.
+func (c *Client) UpdateUser(ctx context.Context, id string, req *api.ApiUpdateUserRequest) (*api.ApiUpdateUserResponse, error) {
+	var res api.ApiUpdateUserResponse
+	if err := c.call(ctx, "POST", "/api/user/"+url.PathEscape(id), nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteUser calls `DELETE /api/user/{id}`, to delete a user.
+func (c *Client) DeleteUser(ctx context.Context, id string) error {
+	return c.call(ctx, "DELETE", "/api/user/"+url.PathEscape(id), nil, "", nil, nil)
+}
+
+// RecoverUser calls `POST /api/user/{id}/recover`, to create a sign-in link for a user that has lost their passkeys.
+func (c *Client) RecoverUser(ctx context.Context, id string, req *api.ApiUserRecoverRequest) (*api.ApiUserRecoverResponse, error) {
+	var res api.ApiUserRecoverResponse
+	if err := c.call(ctx, "POST", "/api/user/"+url.PathEscape(id)+"/recover", nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetUserActivity calls `GET /api/user/{id}/activity`, to list where the sessions of a user have been used.
+func (c *Client) GetUserActivity(ctx context.Context, id string) (*api.ApiUserActivityResponse, error) {
+	var res api.ApiUserActivityResponse
+	if err := c.call(ctx, "GET", "/api/user/"+url.PathEscape(id)+"/activity", nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ImpersonateUser calls `POST /api/user/{id}/impersonate`, to start viewing the site as a user.
+func (c *Client) ImpersonateUser(ctx context.Context, id string) (*api.ApiUserImpersonateResponse, error) {
+	var res api.ApiUserImpersonateResponse
+	if err := c.call(ctx, "POST", "/api/user/"+url.PathEscape(id)+"/impersonate", nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetUserAccess calls `GET /api/user/{id}/access`, to explain whether a user may access a URL.
+func (c *Client) GetUserAccess(ctx context.Context, id string, query url.Values) (*api.ApiUserAccessResponse, error) {
+	var res api.ApiUserAccessResponse
+	if err := c.call(ctx, "GET", "/api/user/"+url.PathEscape(id)+"/access", query, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// StopImpersonation calls `DELETE /api/impersonation`, to stop viewing the site as another user.
+func (c *Client) StopImpersonation(ctx context.Context) (*api.ApiImpersonationStopResponse, error) {
+	var res api.ApiImpersonationStopResponse
+	if err := c.call(ctx, "DELETE", "/api/impersonation", nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteFederatedIdentity calls `DELETE /api/user/{id}/federated-identity/{provider}`, to unlink an identity at an upstream provider from a user.
+func (c *Client) DeleteFederatedIdentity(ctx context.Context, id string, provider string) error {
+	return c.call(ctx, "DELETE", "/api/user/"+url.PathEscape(id)+"/federated-identity/"+url.PathEscape(provider), nil, "", nil, nil)
+}
+
+// ListInvitations calls `GET /api/invitation`, to list pending invitations.
+func (c *Client) ListInvitations(ctx context.Context) (*api.ApiListInvitationsResponse, error) {
+	var res api.ApiListInvitationsResponse
+	if err := c.call(ctx, "GET", "/api/invitation", nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CreateInvitation calls `POST /api/invitation`, to invite a user.
+func (c *Client) CreateInvitation(ctx context.Context, req *api.ApiCreateInvitationRequest) (*api.ApiCreateInvitationResponse, error) {
+	var res api.ApiCreateInvitationResponse
+	if err := c.call(ctx, "POST", "/api/invitation", nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ImportInvitations calls `POST /api/invitation/import`, to invite the users in a CSV or YAML list.
+func (c *Client) ImportInvitations(ctx context.Context, query url.Values, contentType string, body []byte) (*api.ApiImportInvitationsResponse, error) {
+	var res api.ApiImportInvitationsResponse
+	if err := c.call(ctx, "POST", "/api/invitation/import", query, contentType, body, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// RedeemInvitation calls `POST /api/invitation/redeem`, to accept an invitation.
+func (c *Client) RedeemInvitation(ctx context.Context, req *api.ApiRedeemInvitationRequest) (*api.ApiRedeemInvitationResponse, error) {
+	var res api.ApiRedeemInvitationResponse
+	if err := c.call(ctx, "POST", "/api/invitation/redeem", nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteInvitation calls `DELETE /api/invitation/{id}`, to revoke an invitation.
+func (c *Client) DeleteInvitation(ctx context.Context, id string) error {
+	return c.call(ctx, "DELETE", "/api/invitation/"+url.PathEscape(id), nil, "", nil, nil)
+}
+
+// GetSettings calls `GET /api/settings`, to get the settings.
+func (c *Client) GetSettings(ctx context.Context) (*api.ApiSettings, error) {
+	var res api.ApiSettings
+	if err := c.call(ctx, "GET", "/api/settings", nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// UpdateSettings calls `POST /api/settings`, to update the settings.
+func (c *Client) UpdateSettings(ctx context.Context, req *api.ApiUpdateSettingsRequest) (*api.ApiUpdateSettingsResponse, error) {
+	var res api.ApiUpdateSettingsResponse
+	if err := c.call(ctx, "POST", "/api/settings", nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListAuthenticators calls `GET /api/authenticators`, to list known authenticators that passkeys can be stored in.
+func (c *Client) ListAuthenticators(ctx context.Context) (*api.ApiListAuthenticatorsResponse, error) {
+	var res api.ApiListAuthenticatorsResponse
+	if err := c.call(ctx, "GET", "/api/authenticators", nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListAuditRecords calls `GET /api/audit`, to list audit records, newest first.
+func (c *Client) ListAuditRecords(ctx context.Context, query url.Values) (*api.ApiListAuditRecordsResponse, error) {
+	var res api.ApiListAuditRecordsResponse
+	if err := c.call(ctx, "GET", "/api/audit", query, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListSecurityEvents calls `GET /api/security-events`, to list failed attempts to authenticate, newest first.
+func (c *Client) ListSecurityEvents(ctx context.Context, query url.Values) (*api.ApiListSecurityEventsResponse, error) {
+	var res api.ApiListSecurityEventsResponse
+	if err := c.call(ctx, "GET", "/api/security-events", query, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ExportSecurityEventsFail2ban calls `GET /api/security-events/fail2ban`, to export failed attempts to authenticate as a fail2ban log.
+func (c *Client) ExportSecurityEventsFail2ban(ctx context.Context, query url.Values) ([]byte, error) {
+	var res []byte
+	if err := c.call(ctx, "GET", "/api/security-events/fail2ban", query, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ListBans calls `GET /api/ban`, to list banned IP addresses.
+func (c *Client) ListBans(ctx context.Context) (*api.ApiListBansResponse, error) {
+	var res api.ApiListBansResponse
+	if err := c.call(ctx, "GET", "/api/ban", nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteBan calls `DELETE /api/ban/{ip}`, to lift the ban of an IP address.
+func (c *Client) DeleteBan(ctx context.Context, ip string) error {
+	return c.call(ctx, "DELETE", "/api/ban/"+url.PathEscape(ip), nil, "", nil, nil)
+}
+
+// GetOpenApi calls `GET /api/openapi.json`, to get this OpenAPI document.
+func (c *Client) GetOpenApi(ctx context.Context) ([]byte, error) {
+	var res []byte
+	if err := c.call(ctx, "GET", "/api/openapi.json", nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// SetupTesting calls `POST /api/testing/setup`, to reset the database, in test mode only.
+func (c *Client) SetupTesting(ctx context.Context) (*api.ApiTestingSetupResponse, error) {
+	var res api.ApiTestingSetupResponse
+	if err := c.call(ctx, "POST", "/api/testing/setup", nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ConfigureBootstrap calls `POST /api/bootstrap/configure`, to configure the server.
+func (c *Client) ConfigureBootstrap(ctx context.Context, req *api.ApiBootstrapConfigureRequest) (*api.ApiBootstrapConfigureResponse, error) {
+	var res api.ApiBootstrapConfigureResponse
+	if err := c.call(ctx, "POST", "/api/bootstrap/configure", nil, "", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetBootstrapStatus calls `GET /api/bootstrap/status`, to get whether the server is configured.
+func (c *Client) GetBootstrapStatus(ctx context.Context) (*api.ApiBootstrapStatusResponse, error) {
+	var res api.ApiBootstrapStatusResponse
+	if err := c.call(ctx, "GET", "/api/bootstrap/status", nil, "", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
@@ -0,0 +1,253 @@
+package api
+
+// Endpoint describes an operation of the JSON API. The OpenAPI document and
+// the Go client are generated from these, see the openapi package.
+type Endpoint struct {
+	// Identifies the operation, and is the name of the Go client method.
+	Name   string
+	Method string
+	// With path parameters as in gorilla/mux, e.g. "/api/user/{id}".
+	Path    string
+	Tag     string
+	Summary string
+	// Names of optional query parameters.
+	Query []string
+	// A nil pointer of the type of the JSON request body, or nil if there
+	// isn't one.
+	Request any
+	// Media types of a request body that isn't JSON. The first is the
+	// default.
+	RawRequest []string
+	// A nil pointer of the type of the JSON response body. If nil, and
+	// `RawResponse` isn't set, the response is "204 No Content".
+	Response any
+	// Media type of a response body that isn't JSON.
+	RawResponse string
+	// Set for endpoints that browsers navigate to, which redirect.
+	Redirect bool
+}
+
+// Endpoints lists all operations of the JSON API, i.e. below "/api". The
+// OAuth, OpenID Connect and SCIM endpoints follow their standards, and are
+// not included.
+var Endpoints = []Endpoint{
+	// Enrolling
+	{Name: "StartEnroll", Method: "POST", Path: "/api/enroll/start", Tag: "enroll",
+		Summary: "Start enrolling a passkey", Request: (*ApiStartEnrollRequest)(nil), Response: (*ApiStartEnrollResponse)(nil)},
+	{Name: "FinishEnroll", Method: "POST", Path: "/api/enroll/finish", Tag: "enroll",
+		Summary: "Finish enrolling a passkey", Request: (*ApiFinishEnrollRequest)(nil), Response: (*ApiFinishEnrollResponse)(nil)},
+	{Name: "StartTotpEnroll", Method: "POST", Path: "/api/totp/enroll/start", Tag: "enroll",
+		Summary: "Start enrolling an authenticator app", Response: (*ApiStartTotpEnrollResponse)(nil)},
+	{Name: "FinishTotpEnroll", Method: "POST", Path: "/api/totp/enroll/finish", Tag: "enroll",
+		Summary: "Finish enrolling an authenticator app", Request: (*ApiFinishTotpEnrollRequest)(nil), Response: (*ApiFinishTotpEnrollResponse)(nil)},
+
+	// Signing in
+	{Name: "StartSignin", Method: "GET", Path: "/api/signin/start", Tag: "signin",
+		Summary: "Start signing in with a passkey", Response: (*ApiStartSigninResponse)(nil)},
+	{Name: "SigninEmail", Method: "POST", Path: "/api/signin/email", Tag: "signin",
+		Summary: "Look up how a user can sign in", Request: (*ApiSignInEmailRequest)(nil), Response: (*ApiSigninEmailResponse)(nil)},
+	{Name: "SigninWebauthn", Method: "POST", Path: "/api/signin/webauthn", Tag: "signin",
+		Summary: "Sign in with a passkey", Request: (*ApiSignInWebauthnRequest)(nil), Response: (*ApiSignInWebauthResponse)(nil)},
+	{Name: "SigninTotp", Method: "POST", Path: "/api/signin/totp", Tag: "signin",
+		Summary: "Sign in with a one-time password", Request: (*ApiSignInTotpRequest)(nil), Response: (*ApiSignInTotpResponse)(nil)},
+	{Name: "SendSigninLink", Method: "POST", Path: "/api/signin/link", Tag: "signin",
+		Summary: "E-mail a sign-in link", Request: (*ApiSendSigninLinkRequest)(nil), Response: (*ApiSendSigninLinkResponse)(nil)},
+	{Name: "RequestSigninPin", Method: "POST", Path: "/api/signin/pin/request", Tag: "signin",
+		Summary: "Request a PIN to sign in using another device", Request: (*ApiRequestSigninPinRequest)(nil), Response: (*ApiRequestSigninPinResponse)(nil)},
+	{Name: "PollSigninPin", Method: "POST", Path: "/api/signin/pin/poll", Tag: "signin",
+		Summary: "Poll whether a PIN has been confirmed", Request: (*ApiPollSigninPinRequest)(nil), Response: (*ApiPollSigninPinResponse)(nil)},
+	{Name: "QuerySigninPin", Method: "POST", Path: "/api/signin/pin/query", Tag: "signin",
+		Summary: "Look up a PIN to confirm", Request: (*ApiQuerySigninPinRequest)(nil), Response: (*ApiQuerySigninPinResponse)(nil)},
+	{Name: "ConfirmSigninPin", Method: "POST", Path: "/api/signin/pin/confirm", Tag: "signin",
+		Summary: "Confirm a PIN, signing in the other device", Request: (*ApiConfirmSigninPinRequest)(nil), Response: (*ApiConfirmSigninPinResponse)(nil)},
+	{Name: "ListSigninProviders", Method: "GET", Path: "/api/signin/upstream", Tag: "signin",
+		Summary: "List upstream OpenID Connect providers to sign in with", Response: (*ApiListSigninProvidersResponse)(nil)},
+	{Name: "StartSigninUpstream", Method: "GET", Path: "/api/signin/upstream/{id}/start", Tag: "signin",
+		Summary: "Redirect to an upstream provider to sign in", Query: []string{"rd", "link"}, Redirect: true},
+	{Name: "SigninUpstreamCallback", Method: "GET", Path: "/api/signin/upstream/{id}/callback", Tag: "signin",
+		Summary: "Finish signing in with an upstream provider", Query: []string{"state", "code", "error"}, Redirect: true},
+
+	// SSH keys
+	{Name: "GetConfirmSshKey", Method: "GET", Path: "/api/ssh-key/{id}/confirm", Tag: "ssh-key",
+		Summary: "Look up a proposed SSH key to confirm", Response: (*ApiGetConfirmSshKeyResponse)(nil)},
+	{Name: "ConfirmSshKey", Method: "POST", Path: "/api/ssh-key/{id}/confirm", Tag: "ssh-key",
+		Summary: "Confirm a proposed SSH key", Request: (*ApiPostConfirmSshKeyRequest)(nil), Response: (*ApiPostConfirmSshKeyResponse)(nil)},
+	{Name: "GetSshKey", Method: "GET", Path: "/api/ssh-key/{id}", Tag: "ssh-key",
+		Summary: "Get an SSH key", Response: (*ApiSSHKey)(nil)},
+	{Name: "ProposeSshKey", Method: "POST", Path: "/api/ssh-key/{id}", Tag: "ssh-key",
+		Summary: "Propose a new public key, to be confirmed by the user", Request: (*ApiProposeSshKeyRequest)(nil), Response: (*ApiProposeSshKeyResponse)(nil)},
+	{Name: "CreateSshKey", Method: "POST", Path: "/api/ssh-key", Tag: "ssh-key",
+		Summary: "Create an SSH key", Request: (*ApiCreateSshKeyRequest)(nil), Response: (*ApiCreateSshKeyResponse)(nil)},
+
+	// Backends
+	{Name: "UpdateBackend", Method: "POST", Path: "/api/backend/{fqdn}", Tag: "backend",
+		Summary: "Create or update a backend", Request: (*ApiUpdateBackendRequest)(nil), Response: (*ApiUpdateBackendResponse)(nil)},
+	{Name: "GetBackend", Method: "GET", Path: "/api/backend/{fqdn}", Tag: "backend",
+		Summary: "Get a backend", Response: (*ApiBackend)(nil)},
+	{Name: "ListBackends", Method: "GET", Path: "/api/backend", Tag: "backend",
+		Summary: "List backends", Response: (*ApiListBackendsResponse)(nil)},
+	{Name: "DeleteBackend", Method: "DELETE", Path: "/api/backend/{fqdn}", Tag: "backend",
+		Summary: "Delete a backend"},
+
+	// MQTT
+	{Name: "UpdateMqttProfile", Method: "POST", Path: "/api/mqtt-profile/{id}", Tag: "mqtt",
+		Summary: "Create or update an MQTT profile", Request: (*ApiUpdateMqttProfileRequest)(nil), Response: (*ApiUpdateMqttProfileResponse)(nil)},
+	{Name: "GetMqttProfile", Method: "GET", Path: "/api/mqtt-profile/{id}", Tag: "mqtt",
+		Summary: "Get an MQTT profile", Response: (*ApiMqttProfile)(nil)},
+	{Name: "ListMqttProfiles", Method: "GET", Path: "/api/mqtt-profile", Tag: "mqtt",
+		Summary: "List MQTT profiles", Response: (*ApiListMqttProfilesResponse)(nil)},
+	{Name: "DeleteMqttProfile", Method: "DELETE", Path: "/api/mqtt-profile/{id}", Tag: "mqtt",
+		Summary: "Delete an MQTT profile"},
+	{Name: "UpdateMqttClient", Method: "POST", Path: "/api/mqtt-client/{id}", Tag: "mqtt",
+		Summary: "Create or update an MQTT client", Request: (*ApiUpdateMqttClientRequest)(nil), Response: (*ApiUpdateMqttClientResponse)(nil)},
+	{Name: "GetMqttClient", Method: "GET", Path: "/api/mqtt-client/{id}", Tag: "mqtt",
+		Summary: "Get an MQTT client", Response: (*ApiMqttClient)(nil)},
+	{Name: "ListMqttClients", Method: "GET", Path: "/api/mqtt-client", Tag: "mqtt",
+		Summary: "List MQTT clients", Response: (*ApiListMqttClientsResponse)(nil)},
+	{Name: "DeleteMqttClient", Method: "DELETE", Path: "/api/mqtt-client/{id}", Tag: "mqtt",
+		Summary: "Delete an MQTT client"},
+	{Name: "ImportMqtt", Method: "POST", Path: "/api/mqtt/import", Tag: "mqtt",
+		Summary: "Import MQTT profiles and clients from YAML", RawRequest: []string{"application/x-yaml"}, Response: (*ApiMqttImportResponse)(nil)},
+	{Name: "ExportMqtt", Method: "GET", Path: "/api/mqtt/export", Tag: "mqtt",
+		Summary: "Export MQTT profiles and clients as YAML", RawResponse: "application/x-yaml"},
+
+	// Service accounts
+	{Name: "CreateServiceAccountSecret", Method: "POST", Path: "/api/service-account/{id}/secret", Tag: "service-account",
+		Summary: "Create a new client secret for a service account", Response: (*ApiCreateServiceAccountSecretResponse)(nil)},
+	{Name: "UpdateServiceAccount", Method: "POST", Path: "/api/service-account/{id}", Tag: "service-account",
+		Summary: "Create or update a service account", Request: (*ApiUpdateServiceAccountRequest)(nil), Response: (*ApiUpdateServiceAccountResponse)(nil)},
+	{Name: "GetServiceAccount", Method: "GET", Path: "/api/service-account/{id}", Tag: "service-account",
+		Summary: "Get a service account", Response: (*ApiServiceAccount)(nil)},
+	{Name: "ListServiceAccounts", Method: "GET", Path: "/api/service-account", Tag: "service-account",
+		Summary: "List service accounts", Response: (*ApiListServiceAccountsResponse)(nil)},
+	{Name: "DeleteServiceAccount", Method: "DELETE", Path: "/api/service-account/{id}", Tag: "service-account",
+		Summary: "Delete a service account"},
+
+	// OIDC clients
+	{Name: "CreateOidcClientSecret", Method: "POST", Path: "/api/oidc-client/{id}/secret", Tag: "oidc-client",
+		Summary: "Create a new client secret for an OIDC client", Response: (*ApiCreateOidcClientSecretResponse)(nil)},
+	{Name: "UpdateOidcClient", Method: "POST", Path: "/api/oidc-client/{id}", Tag: "oidc-client",
+		Summary: "Create or update an OIDC client", Request: (*ApiUpdateOidcClientRequest)(nil), Response: (*ApiUpdateOidcClientResponse)(nil)},
+	{Name: "GetOidcClient", Method: "GET", Path: "/api/oidc-client/{id}", Tag: "oidc-client",
+		Summary: "Get an OIDC client", Response: (*ApiOidcClient)(nil)},
+	{Name: "ListOidcClients", Method: "GET", Path: "/api/oidc-client", Tag: "oidc-client",
+		Summary: "List OIDC clients", Response: (*ApiListOidcClientsResponse)(nil)},
+	{Name: "DeleteOidcClient", Method: "DELETE", Path: "/api/oidc-client/{id}", Tag: "oidc-client",
+		Summary: "Delete an OIDC client"},
+
+	// Upstream OIDC providers
+	{Name: "UpdateUpstreamOidcProvider", Method: "POST", Path: "/api/upstream-oidc/{id}", Tag: "upstream-oidc",
+		Summary: "Create or update an upstream OpenID Connect provider", Request: (*ApiUpdateUpstreamOidcProviderRequest)(nil), Response: (*ApiUpdateUpstreamOidcProviderResponse)(nil)},
+	{Name: "GetUpstreamOidcProvider", Method: "GET", Path: "/api/upstream-oidc/{id}", Tag: "upstream-oidc",
+		Summary: "Get an upstream OpenID Connect provider", Response: (*ApiUpstreamOidcProvider)(nil)},
+	{Name: "ListUpstreamOidcProviders", Method: "GET", Path: "/api/upstream-oidc", Tag: "upstream-oidc",
+		Summary: "List upstream OpenID Connect providers", Response: (*ApiListUpstreamOidcProvidersResponse)(nil)},
+	{Name: "DeleteUpstreamOidcProvider", Method: "DELETE", Path: "/api/upstream-oidc/{id}", Tag: "upstream-oidc",
+		Summary: "Delete an upstream OpenID Connect provider"},
+
+	// Device authorization
+	{Name: "QueryDevice", Method: "POST", Path: "/api/device/query", Tag: "device",
+		Summary: "Look up a device authorization by its user code", Request: (*ApiQueryDeviceRequest)(nil), Response: (*ApiQueryDeviceResponse)(nil)},
+	{Name: "ConfirmDevice", Method: "POST", Path: "/api/device/confirm", Tag: "device",
+		Summary: "Authorize a device", Request: (*ApiConfirmDeviceRequest)(nil), Response: (*ApiConfirmDeviceResponse)(nil)},
+	{Name: "DenyDevice", Method: "POST", Path: "/api/device/deny", Tag: "device",
+		Summary: "Deny a device authorization", Request: (*ApiDenyDeviceRequest)(nil), Response: (*ApiDenyDeviceResponse)(nil)},
+
+	// Configuration
+	{Name: "ExportConfig", Method: "GET", Path: "/api/config", Tag: "config",
+		Summary: "Export the configuration as YAML", RawResponse: "application/x-yaml"},
+	{Name: "PlanConfig", Method: "POST", Path: "/api/config/plan", Tag: "config",
+		Summary: "Show the changes that applying a configuration would make", RawRequest: []string{"application/x-yaml"}, Response: (*ApiConfigPlanResponse)(nil)},
+	{Name: "ApplyConfig", Method: "POST", Path: "/api/config/apply", Tag: "config",
+		Summary: "Apply a configuration", RawRequest: []string{"application/x-yaml"}, Response: (*ApiConfigApplyResponse)(nil)},
+
+	// Credentials, sessions, access tokens and app passwords
+	{Name: "UpdateCredential", Method: "POST", Path: "/api/credential/{id}", Tag: "credential",
+		Summary: "Rename a passkey", Request: (*ApiUpdateCredentialRequest)(nil), Response: (*ApiUpdateCredentialResponse)(nil)},
+	{Name: "DeleteCredential", Method: "DELETE", Path: "/api/credential/{id}", Tag: "credential",
+		Summary: "Delete a passkey"},
+	{Name: "DeleteSession", Method: "DELETE", Path: "/api/session/{id}", Tag: "session",
+		Summary: "Sign out a session"},
+	{Name: "StartCreateAccessToken", Method: "POST", Path: "/api/access-token/start", Tag: "access-token",
+		Summary: "Start creating an access token", Request: (*ApiStartCreateAccessTokenRequest)(nil), Response: (*ApiStartCreateAccessTokenResponse)(nil)},
+	{Name: "FinishCreateAccessToken", Method: "POST", Path: "/api/access-token/finish", Tag: "access-token",
+		Summary: "Finish creating an access token", Request: (*ApiFinishCreateAccessTokenRequest)(nil), Response: (*ApiFinishCreateAccessTokenResponse)(nil)},
+	{Name: "DeleteAccessToken", Method: "DELETE", Path: "/api/access-token/{id}", Tag: "access-token",
+		Summary: "Revoke an access token"},
+	{Name: "CreateAppPassword", Method: "POST", Path: "/api/app-password", Tag: "app-password",
+		Summary: "Create an app password", Request: (*ApiCreateAppPasswordRequest)(nil), Response: (*ApiCreateAppPasswordResponse)(nil)},
+	{Name: "DeleteAppPassword", Method: "DELETE", Path: "/api/app-password/{id}", Tag: "app-password",
+		Summary: "Revoke an app password"},
+
+	// Users
+	{Name: "CreateUser", Method: "POST", Path: "/api/user", Tag: "user",
+		Summary: "Create a user", Request: (*ApiCreateUserRequest)(nil), Response: (*ApiCreateUserResponse)(nil)},
+	{Name: "ListUsers", Method: "GET", Path: "/api/user", Tag: "user",
+		Summary: "List users", Response: (*ApiListUsersResponse)(nil)},
+	{Name: "GetUser", Method: "GET", Path: "/api/user/{id}", Tag: "user",
+		Summary: `Get a user, or the signed in user with the id "me"`, Response: (*ApiUser)(nil)},
+	{Name: "UpdateUser", Method: "POST", Path: "/api/user/{id}", Tag: "user",
+		Summary: "Update a user", Request: (*ApiUpdateUserRequest)(nil), Response: (*ApiUpdateUserResponse)(nil)},
+	{Name: "DeleteUser", Method: "DELETE", Path: "/api/user/{id}", Tag: "user",
+		Summary: "Delete a user"},
+	{Name: "RecoverUser", Method: "POST", Path: "/api/user/{id}/recover", Tag: "user",
+		Summary: "Create a sign-in link for a user that has lost their passkeys", Request: (*ApiUserRecoverRequest)(nil), Response: (*ApiUserRecoverResponse)(nil)},
+	{Name: "GetUserActivity", Method: "GET", Path: "/api/user/{id}/activity", Tag: "user",
+		Summary: "List where the sessions of a user have been used", Response: (*ApiUserActivityResponse)(nil)},
+	{Name: "ImpersonateUser", Method: "POST", Path: "/api/user/{id}/impersonate", Tag: "user",
+		Summary: "Start viewing the site as a user", Response: (*ApiUserImpersonateResponse)(nil)},
+	{Name: "GetUserAccess", Method: "GET", Path: "/api/user/{id}/access", Tag: "user",
+		Summary: "Explain whether a user may access a URL", Query: []string{"url"}, Response: (*ApiUserAccessResponse)(nil)},
+	{Name: "StopImpersonation", Method: "DELETE", Path: "/api/impersonation", Tag: "user",
+		Summary: "Stop viewing the site as another user", Response: (*ApiImpersonationStopResponse)(nil)},
+	{Name: "DeleteFederatedIdentity", Method: "DELETE", Path: "/api/user/{id}/federated-identity/{provider}", Tag: "user",
+		Summary: "Unlink an identity at an upstream provider from a user"},
+
+	// Invitations
+	{Name: "ListInvitations", Method: "GET", Path: "/api/invitation", Tag: "invitation",
+		Summary: "List pending invitations", Response: (*ApiListInvitationsResponse)(nil)},
+	{Name: "CreateInvitation", Method: "POST", Path: "/api/invitation", Tag: "invitation",
+		Summary: "Invite a user", Request: (*ApiCreateInvitationRequest)(nil), Response: (*ApiCreateInvitationResponse)(nil)},
+	{Name: "ImportInvitations", Method: "POST", Path: "/api/invitation/import", Tag: "invitation",
+		Summary: "Invite the users in a CSV or YAML list", Query: []string{"sendEmail", "lifetimeSeconds"},
+		RawRequest: []string{"text/csv", "application/x-yaml"}, Response: (*ApiImportInvitationsResponse)(nil)},
+	{Name: "RedeemInvitation", Method: "POST", Path: "/api/invitation/redeem", Tag: "invitation",
+		Summary: "Accept an invitation", Request: (*ApiRedeemInvitationRequest)(nil), Response: (*ApiRedeemInvitationResponse)(nil)},
+	{Name: "DeleteInvitation", Method: "DELETE", Path: "/api/invitation/{id}", Tag: "invitation",
+		Summary: "Revoke an invitation"},
+
+	// Settings
+	{Name: "GetSettings", Method: "GET", Path: "/api/settings", Tag: "settings",
+		Summary: "Get the settings", Response: (*ApiSettings)(nil)},
+	{Name: "UpdateSettings", Method: "POST", Path: "/api/settings", Tag: "settings",
+		Summary: "Update the settings", Request: (*ApiUpdateSettingsRequest)(nil), Response: (*ApiUpdateSettingsResponse)(nil)},
+	{Name: "ListAuthenticators", Method: "GET", Path: "/api/authenticators", Tag: "settings",
+		Summary: "List known authenticators that passkeys can be stored in", Response: (*ApiListAuthenticatorsResponse)(nil)},
+
+	// Audit log and security events
+	{Name: "ListAuditRecords", Method: "GET", Path: "/api/audit", Tag: "audit",
+		Summary: "List audit records, newest first", Query: []string{"actor", "action", "targetType", "targetId", "since", "until", "limit"},
+		Response: (*ApiListAuditRecordsResponse)(nil)},
+	{Name: "ListSecurityEvents", Method: "GET", Path: "/api/security-events", Tag: "audit",
+		Summary: "List failed attempts to authenticate, newest first", Query: []string{"source", "ip", "principal", "since", "limit"},
+		Response: (*ApiListSecurityEventsResponse)(nil)},
+	{Name: "ExportSecurityEventsFail2ban", Method: "GET", Path: "/api/security-events/fail2ban", Tag: "audit",
+		Summary: "Export failed attempts to authenticate as a fail2ban log", Query: []string{"source", "ip", "principal", "since", "limit"},
+		RawResponse: "text/plain"},
+	{Name: "ListBans", Method: "GET", Path: "/api/ban", Tag: "audit",
+		Summary: "List banned IP addresses", Response: (*ApiListBansResponse)(nil)},
+	{Name: "DeleteBan", Method: "DELETE", Path: "/api/ban/{ip}", Tag: "audit",
+		Summary: "Lift the ban of an IP address"},
+
+	// Documentation and testing
+	{Name: "GetOpenApi", Method: "GET", Path: "/api/openapi.json", Tag: "meta",
+		Summary: "Get this OpenAPI document", RawResponse: "application/json"},
+	{Name: "SetupTesting", Method: "POST", Path: "/api/testing/setup", Tag: "meta",
+		Summary: "Reset the database, in test mode only", Response: (*ApiTestingSetupResponse)(nil)},
+
+	// Bootstrap mode, before the server is configured
+	{Name: "ConfigureBootstrap", Method: "POST", Path: "/api/bootstrap/configure", Tag: "bootstrap",
+		Summary: "Configure the server", Request: (*ApiBootstrapConfigureRequest)(nil), Response: (*ApiBootstrapConfigureResponse)(nil)},
+	{Name: "GetBootstrapStatus", Method: "GET", Path: "/api/bootstrap/status", Tag: "bootstrap",
+		Summary: "Get whether the server is configured", Response: (*ApiBootstrapStatusResponse)(nil)},
+}
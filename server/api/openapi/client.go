@@ -0,0 +1,115 @@
+package openapi
+
+import (
+	"boivie/ubergang/server/api"
+	"bytes"
+	"fmt"
+	"go/format"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// pathExpression returns Go code that builds `path`, with path parameters
+// taken from variables with the same names.
+func pathExpression(path string) string {
+	var parts []string
+	for path != "" {
+		start := strings.Index(path, "{")
+		if start < 0 {
+			parts = append(parts, fmt.Sprintf("%q", path))
+			break
+		}
+		end := strings.Index(path, "}")
+		if start > 0 {
+			parts = append(parts, fmt.Sprintf("%q", path[:start]))
+		}
+		parts = append(parts, "url.PathEscape("+path[start+1:end]+")")
+		path = path[end+1:]
+	}
+	return strings.Join(parts, " + ")
+}
+
+func typeName(v any) (string, error) {
+	t := reflect.TypeOf(v)
+	if t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Struct {
+		return "", fmt.Errorf("expected a pointer to a struct, got %s", t)
+	}
+	return "api." + t.Elem().Name(), nil
+}
+
+func lowerFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(r)) + s[size:]
+}
+
+func writeMethod(b *bytes.Buffer, e *api.Endpoint) error {
+	params := []string{"ctx context.Context"}
+	for _, m := range pathParameter.FindAllStringSubmatch(e.Path, -1) {
+		params = append(params, m[1]+" string")
+	}
+	query := "nil"
+	if len(e.Query) > 0 {
+		params = append(params, "query url.Values")
+		query = "query"
+	}
+	contentType, body := `""`, "nil"
+	switch {
+	case e.Request != nil:
+		name, err := typeName(e.Request)
+		if err != nil {
+			return err
+		}
+		params = append(params, "req *"+name)
+		body = "req"
+	case len(e.RawRequest) == 1:
+		params = append(params, "body []byte")
+		contentType, body = fmt.Sprintf("%q", e.RawRequest[0]), "body"
+	case len(e.RawRequest) > 1:
+		params = append(params, "contentType string", "body []byte")
+		contentType, body = "contentType", "body"
+	}
+
+	fmt.Fprintf(b, "\n// %s calls `%s %s`, to %s.\n", e.Name, e.Method, e.Path, lowerFirst(e.Summary))
+	call := fmt.Sprintf("c.call(ctx, %q, %s, %s, %s, %s", e.Method, pathExpression(e.Path), query, contentType, body)
+	switch {
+	case e.Response != nil:
+		name, err := typeName(e.Response)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(b, "func (c *Client) %s(%s) (*%s, error) {\n", e.Name, strings.Join(params, ", "), name)
+		fmt.Fprintf(b, "\tvar res %s\n", name)
+		fmt.Fprintf(b, "\tif err := %s, &res); err != nil {\n\t\treturn nil, err\n\t}\n", call)
+		fmt.Fprintf(b, "\treturn &res, nil\n}\n")
+	case e.RawResponse != "":
+		fmt.Fprintf(b, "func (c *Client) %s(%s) ([]byte, error) {\n", e.Name, strings.Join(params, ", "))
+		fmt.Fprintf(b, "\tvar res []byte\n")
+		fmt.Fprintf(b, "\tif err := %s, &res); err != nil {\n\t\treturn nil, err\n\t}\n", call)
+		fmt.Fprintf(b, "\treturn res, nil\n}\n")
+	default:
+		fmt.Fprintf(b, "func (c *Client) %s(%s) error {\n", e.Name, strings.Join(params, ", "))
+		fmt.Fprintf(b, "\treturn %s, nil)\n}\n", call)
+	}
+	return nil
+}
+
+// GenerateClient returns the Go source of the client methods of `endpoints`.
+// Endpoints that redirect browsers are left out.
+func GenerateClient(endpoints []api.Endpoint) ([]byte, error) {
+	b := &bytes.Buffer{}
+	b.WriteString("// Code generated by apigen. DO NOT EDIT.\n\n")
+	b.WriteString("package client\n\n")
+	b.WriteString("import (\n\t\"boivie/ubergang/server/api\"\n\t\"context\"\n\t\"net/url\"\n)\n")
+	for i := range endpoints {
+		e := &endpoints[i]
+		if e.Redirect {
+			continue
+		}
+		if err := writeMethod(b, e); err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Name, err)
+		}
+	}
+	return format.Source(b.Bytes())
+}
@@ -0,0 +1,324 @@
+// Package openapi generates the OpenAPI document and the Go client of the JSON
+// API, from api.Endpoints and the types in api_types.go.
+package openapi
+
+import (
+	"boivie/ubergang/server/api"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+//go:generate go run ../../../tools/apigen ../api_types.go openapi.json ../client/client_gen.go
+
+// Json is the generated OpenAPI document, as served by the admin API.
+//
+//go:embed openapi.json
+var Json []byte
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Operation struct {
+	OperationId string              `json:"operationId"`
+	Summary     string              `json:"summary"`
+	Tags        []string            `json:"tags"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+	Scheme      string `json:"scheme,omitempty"`
+	In          string `json:"in,omitempty"`
+	Name        string `json:"name,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Version     string `json:"version"`
+}
+
+type Document struct {
+	OpenApi    string                           `json:"openapi"`
+	Info       Info                             `json:"info"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components Components                       `json:"components"`
+	Security   []map[string][]string            `json:"security"`
+}
+
+// Docs are the doc comments of the API types, keyed by "Type" and
+// "Type.Field".
+type Docs map[string]string
+
+// Section markers in api_types.go, e.g. "// backend_update", aren't docs.
+var sectionMarker = regexp.MustCompile(`^[a-z_]+$`)
+
+func commentText(groups ...*ast.CommentGroup) string {
+	for _, g := range groups {
+		if text := strings.Join(strings.Fields(g.Text()), " "); text != "" && !sectionMarker.MatchString(text) {
+			return text
+		}
+	}
+	return ""
+}
+
+// ParseDocs reads the doc comments from the Go source file `filename`.
+func ParseDocs(filename string) (Docs, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), filename, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	docs := make(Docs)
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if text := commentText(ts.Doc, gen.Doc); text != "" {
+				docs[ts.Name.Name] = text
+			}
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			for _, field := range st.Fields.List {
+				text := commentText(field.Doc, field.Comment)
+				for _, name := range field.Names {
+					if text != "" {
+						docs[ts.Name.Name+"."+name.Name] = text
+					}
+				}
+			}
+		}
+	}
+	return docs, nil
+}
+
+type generator struct {
+	docs    Docs
+	schemas map[string]*Schema
+}
+
+// schemaOf returns the schema of `t`, adding named structs to the
+// components.
+func (g *generator) schemaOf(t reflect.Type) (*Schema, error) {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schemaOf(t.Elem())
+	case reflect.String:
+		return &Schema{Type: "string"}, nil
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}, nil
+	case reflect.Int32, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}, nil
+	case reflect.Slice:
+		items, err := g.schemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("unsupported map key of %s", t)
+		}
+		values, err := g.schemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Struct:
+		return g.structSchema(t)
+	}
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+func (g *generator) structSchema(t reflect.Type) (*Schema, error) {
+	ref := &Schema{Ref: "#/components/schemas/" + t.Name()}
+	if _, found := g.schemas[t.Name()]; found {
+		return ref, nil
+	}
+	schema := &Schema{
+		Type:        "object",
+		Description: g.docs[t.Name()],
+		Properties:  make(map[string]*Schema),
+	}
+	// Added before the fields, to handle recursive types.
+	g.schemas[t.Name()] = schema
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		property, err := g.schemaOf(field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+		}
+		if property.Ref == "" {
+			property.Description = g.docs[t.Name()+"."+field.Name]
+		}
+		schema.Properties[name] = property
+		if field.Type.Kind() != reflect.Pointer && !strings.Contains(opts, "omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return ref, nil
+}
+
+var pathParameter = regexp.MustCompile(`\{([^}]+)\}`)
+
+func (g *generator) operation(e *api.Endpoint) (*Operation, error) {
+	op := &Operation{
+		OperationId: e.Name,
+		Summary:     e.Summary,
+		Tags:        []string{e.Tag},
+		Responses: map[string]Response{
+			"default": {
+				Description: "An error, described in plain text",
+				Content:     map[string]MediaType{"text/plain": {Schema: &Schema{Type: "string"}}},
+			},
+		},
+	}
+	for _, m := range pathParameter.FindAllStringSubmatch(e.Path, -1) {
+		op.Parameters = append(op.Parameters, Parameter{Name: m[1], In: "path", Required: true, Schema: &Schema{Type: "string"}})
+	}
+	for _, name := range e.Query {
+		op.Parameters = append(op.Parameters, Parameter{Name: name, In: "query", Schema: &Schema{Type: "string"}})
+	}
+
+	if e.Request != nil {
+		schema, err := g.schemaOf(reflect.TypeOf(e.Request))
+		if err != nil {
+			return nil, err
+		}
+		op.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{"application/json": {Schema: schema}}}
+	} else if len(e.RawRequest) > 0 {
+		op.RequestBody = &RequestBody{Required: true, Content: make(map[string]MediaType)}
+		for _, mediaType := range e.RawRequest {
+			op.RequestBody.Content[mediaType] = MediaType{Schema: &Schema{Type: "string"}}
+		}
+	}
+
+	switch {
+	case e.Response != nil:
+		schema, err := g.schemaOf(reflect.TypeOf(e.Response))
+		if err != nil {
+			return nil, err
+		}
+		op.Responses["200"] = Response{Description: "OK", Content: map[string]MediaType{"application/json": {Schema: schema}}}
+	case e.RawResponse != "":
+		op.Responses["200"] = Response{Description: "OK", Content: map[string]MediaType{e.RawResponse: {}}}
+	case e.Redirect:
+		op.Responses["302"] = Response{Description: "Redirects the browser"}
+	default:
+		op.Responses["204"] = Response{Description: "No content"}
+	}
+	return op, nil
+}
+
+// Generate returns the OpenAPI document of `endpoints`.
+func Generate(endpoints []api.Endpoint, docs Docs) (*Document, error) {
+	g := &generator{docs: docs, schemas: make(map[string]*Schema)}
+	doc := &Document{
+		OpenApi: "3.0.3",
+		Info: Info{
+			Title:       "Ubergang",
+			Description: "The JSON API of Ubergang, served on the admin host.",
+			Version:     "unreleased",
+		},
+		Paths: make(map[string]map[string]*Operation),
+		Components: Components{
+			Schemas: g.schemas,
+			SecuritySchemes: map[string]SecurityScheme{
+				"session": {
+					Type:        "apiKey",
+					Description: "The session cookie of a signed in user",
+					In:          "cookie",
+					Name:        "__ug_sess",
+				},
+				"accessToken": {
+					Type:        "http",
+					Description: `An access token with the "admin" scope`,
+					Scheme:      "bearer",
+				},
+			},
+		},
+		Security: []map[string][]string{{"session": {}}, {"accessToken": {}}},
+	}
+	for i := range endpoints {
+		e := &endpoints[i]
+		op, err := g.operation(e)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Name, err)
+		}
+		if doc.Paths[e.Path] == nil {
+			doc.Paths[e.Path] = make(map[string]*Operation)
+		}
+		method := strings.ToLower(e.Method)
+		if _, found := doc.Paths[e.Path][method]; found {
+			return nil, fmt.Errorf("duplicate endpoint %s %s", e.Method, e.Path)
+		}
+		doc.Paths[e.Path][method] = op
+	}
+	return doc, nil
+}
+
+// Marshal returns the document as indented JSON.
+func (d *Document) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
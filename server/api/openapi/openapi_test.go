@@ -0,0 +1,126 @@
+package openapi
+
+import (
+	"boivie/ubergang/server/api"
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const regenerate = "run `go generate ./server/api/openapi` to regenerate it"
+
+func generate(t *testing.T) *Document {
+	t.Helper()
+	docs, err := ParseDocs("../api_types.go")
+	require.NoError(t, err)
+	doc, err := Generate(api.Endpoints, docs)
+	require.NoError(t, err)
+	return doc
+}
+
+func TestDocumentIsUpToDate(t *testing.T) {
+	data, err := generate(t).Marshal()
+	require.NoError(t, err)
+	assert.Equal(t, string(Json), string(data), "openapi.json is out of date, "+regenerate)
+}
+
+func TestClientIsUpToDate(t *testing.T) {
+	expected, err := GenerateClient(api.Endpoints)
+	require.NoError(t, err)
+	actual, err := os.ReadFile("../client/client_gen.go")
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(actual), "client_gen.go is out of date, "+regenerate)
+}
+
+func TestAllTypesAreDocumented(t *testing.T) {
+	// Used by endpoints that follow other standards.
+	standard := map[string]bool{
+		"ApiOAuthTokenResponse":          true,
+		"ApiOAuthErrorResponse":          true,
+		"ApiDeviceAuthorizationResponse": true,
+		"ApiWebauthnWellKnownResponse":   true,
+		"ApiOidcDiscoveryResponse":       true,
+		"ApiJsonWebKey":                  true,
+		"ApiJsonWebKeySet":               true,
+	}
+
+	file, err := parser.ParseFile(token.NewFileSet(), "../api_types.go", nil, 0)
+	require.NoError(t, err)
+	schemas := generate(t).Components.Schemas
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			name := spec.(*ast.TypeSpec).Name.Name
+			if !standard[name] {
+				assert.Contains(t, schemas, name, "%s isn't used by any endpoint in api.Endpoints", name)
+			}
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	doc := generate(t)
+
+	t.Run("path parameters and JSON bodies", func(t *testing.T) {
+		op := doc.Paths["/api/backend/{fqdn}"]["post"]
+		require.NotNil(t, op)
+		assert.Equal(t, "UpdateBackend", op.OperationId)
+		assert.Equal(t, []Parameter{{Name: "fqdn", In: "path", Required: true, Schema: &Schema{Type: "string"}}}, op.Parameters)
+		assert.Equal(t, "#/components/schemas/ApiUpdateBackendRequest", op.RequestBody.Content["application/json"].Schema.Ref)
+		assert.Equal(t, "#/components/schemas/ApiUpdateBackendResponse", op.Responses["200"].Content["application/json"].Schema.Ref)
+	})
+
+	t.Run("no content", func(t *testing.T) {
+		op := doc.Paths["/api/backend/{fqdn}"]["delete"]
+		require.NotNil(t, op)
+		assert.Nil(t, op.RequestBody)
+		assert.Contains(t, op.Responses, "204")
+		assert.NotContains(t, op.Responses, "200")
+	})
+
+	t.Run("raw bodies and query parameters", func(t *testing.T) {
+		op := doc.Paths["/api/invitation/import"]["post"]
+		require.NotNil(t, op)
+		assert.Contains(t, op.RequestBody.Content, "text/csv")
+		assert.Contains(t, op.RequestBody.Content, "application/x-yaml")
+		assert.Equal(t, "sendEmail", op.Parameters[0].Name)
+		assert.Equal(t, "query", op.Parameters[0].In)
+		assert.False(t, op.Parameters[0].Required)
+
+		op = doc.Paths["/api/config"]["get"]
+		require.NotNil(t, op)
+		assert.Contains(t, op.Responses["200"].Content, "application/x-yaml")
+	})
+
+	t.Run("schemas", func(t *testing.T) {
+		backend := doc.Components.Schemas["ApiBackend"]
+		require.NotNil(t, backend)
+		assert.Equal(t, "object", backend.Type)
+		assert.Equal(t, "array", backend.Properties["headers"].Type)
+		assert.Equal(t, "#/components/schemas/ApiBackendHeader", backend.Properties["headers"].Items.Ref)
+		assert.Equal(t, "Can be NORMAL or PUBLIC.", backend.Properties["accessLevel"].Description)
+		assert.Contains(t, backend.Required, "fqdn")
+
+		// Pointers and omitempty fields are optional.
+		update := doc.Components.Schemas["ApiUpdateUserRequest"]
+		require.NotNil(t, update)
+		assert.Empty(t, update.Required)
+		assert.Equal(t, "object", doc.Components.Schemas["ApiMqttClient"].Properties["values"].Type)
+		assert.Equal(t, "string", doc.Components.Schemas["ApiMqttClient"].Properties["values"].AdditionalProperties.Type)
+	})
+
+	t.Run("is valid JSON", func(t *testing.T) {
+		var v map[string]any
+		require.NoError(t, json.Unmarshal(Json, &v))
+		assert.Equal(t, "3.0.3", v["openapi"])
+	})
+}
@@ -0,0 +1,144 @@
+package audit
+
+import (
+	"boivie/ubergang/server/common"
+	"boivie/ubergang/server/models"
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const redacted = `"<redacted>"`
+
+// Fields whose values are never stored, only that they changed.
+var sensitiveFields = map[string]bool{
+	"pin":             true,
+	"signin_requests": true,
+	"totp_credential": true,
+}
+
+// Fields of nested messages whose values are never stored, by the field that
+// holds the messages. Backend headers often carry credentials to the upstream.
+var sensitiveNestedFields = map[string]string{
+	"headers": "value",
+}
+
+func isSensitive(field string) bool {
+	return sensitiveFields[field] ||
+		strings.Contains(field, "password") ||
+		strings.Contains(field, "secret")
+}
+
+// Actor is who made a change.
+type Actor struct {
+	Id        string
+	Name      string
+	SessionId string
+	Ip        string
+}
+
+// NewRecord creates an audit record of a change to an entity. `before` and
+// `after` are the entity before and after the change, and are nil if it was
+// created or deleted, respectively. The target type is the first part of
+// `action`, e.g. "backend" for "backend.update".
+func NewRecord(actor Actor, action, targetId string, before, after proto.Message) *models.AuditRecord {
+	targetType, _, _ := strings.Cut(action, ".")
+	return &models.AuditRecord{
+		Id:         common.MakeRandomID(),
+		Timestamp:  timestamppb.New(time.Now()),
+		ActorId:    actor.Id,
+		ActorName:  actor.Name,
+		SessionId:  actor.SessionId,
+		Ip:         actor.Ip,
+		Action:     action,
+		TargetType: targetType,
+		TargetId:   targetId,
+		Changes:    Diff(before, after),
+	}
+}
+
+// toFields returns the fields of `m` that are set, as JSON values.
+func toFields(m proto.Message) map[string]json.RawMessage {
+	fields := map[string]json.RawMessage{}
+	if m == nil || !m.ProtoReflect().IsValid() {
+		return fields
+	}
+	data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(m)
+	if err != nil {
+		return fields
+	}
+	_ = json.Unmarshal(data, &fields)
+	// The output of protojson isn't stable, so normalize it.
+	for field, value := range fields {
+		var buf bytes.Buffer
+		if json.Compact(&buf, value) == nil {
+			fields[field] = buf.Bytes()
+		}
+	}
+	return fields
+}
+
+// redact replaces the values of sensitive fields, also in nested messages.
+func redact(field string, value json.RawMessage) json.RawMessage {
+	if value == nil {
+		return nil
+	}
+	if isSensitive(field) {
+		return json.RawMessage(redacted)
+	}
+	var nested map[string]json.RawMessage
+	if json.Unmarshal(value, &nested) == nil {
+		for k, v := range nested {
+			if sensitiveNestedFields[field] == k {
+				nested[k] = json.RawMessage(redacted)
+			} else {
+				nested[k] = redact(k, v)
+			}
+		}
+		ret, _ := json.Marshal(nested)
+		return ret
+	}
+	var list []json.RawMessage
+	if json.Unmarshal(value, &list) == nil {
+		for i, v := range list {
+			list[i] = redact(field, v)
+		}
+		ret, _ := json.Marshal(list)
+		return ret
+	}
+	return value
+}
+
+// Diff returns the top-level fields that differ between `before` and
+// `after`, sorted by name. Either may be nil.
+func Diff(before, after proto.Message) []*models.AuditChange {
+	b := toFields(before)
+	a := toFields(after)
+	names := map[string]bool{}
+	for name := range b {
+		names[name] = true
+	}
+	for name := range a {
+		names[name] = true
+	}
+
+	var ret []*models.AuditChange
+	for name := range names {
+		if string(b[name]) == string(a[name]) {
+			continue
+		}
+		ret = append(ret, &models.AuditChange{
+			Field:  name,
+			Before: string(redact(name, b[name])),
+			After:  string(redact(name, a[name])),
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Field < ret[j].Field })
+	return ret
+}
@@ -0,0 +1,77 @@
+package audit
+
+import (
+	"boivie/ubergang/server/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	t.Run("created", func(t *testing.T) {
+		changes := Diff(nil, &models.Backend{Fqdn: "a.example.com", UpstreamUrl: "http://localhost:8080"})
+		require.Len(t, changes, 2)
+		assert.Equal(t, "fqdn", changes[0].Field)
+		assert.Equal(t, "", changes[0].Before)
+		assert.Equal(t, `"a.example.com"`, changes[0].After)
+		assert.Equal(t, "upstream_url", changes[1].Field)
+	})
+
+	t.Run("updated", func(t *testing.T) {
+		before := &models.Backend{Fqdn: "a.example.com", UpstreamUrl: "http://localhost:8080"}
+		after := &models.Backend{Fqdn: "a.example.com", UpstreamUrl: "http://localhost:9090"}
+		changes := Diff(before, after)
+		require.Len(t, changes, 1)
+		assert.Equal(t, "upstream_url", changes[0].Field)
+		assert.Equal(t, `"http://localhost:8080"`, changes[0].Before)
+		assert.Equal(t, `"http://localhost:9090"`, changes[0].After)
+	})
+
+	t.Run("deleted", func(t *testing.T) {
+		var after *models.Backend
+		changes := Diff(&models.Backend{Fqdn: "a.example.com"}, after)
+		require.Len(t, changes, 1)
+		assert.Equal(t, `"a.example.com"`, changes[0].Before)
+		assert.Equal(t, "", changes[0].After)
+	})
+
+	t.Run("redacts secrets", func(t *testing.T) {
+		before := &models.MqttClient{Id: "client", Password: "old"}
+		after := &models.MqttClient{Id: "client", Password: "new"}
+		changes := Diff(before, after)
+		require.Len(t, changes, 1)
+		assert.Equal(t, "password", changes[0].Field)
+		assert.Equal(t, redacted, changes[0].Before)
+		assert.Equal(t, redacted, changes[0].After)
+	})
+
+	t.Run("redacts nested secrets", func(t *testing.T) {
+		before := &models.User{Id: "user"}
+		after := &models.User{Id: "user", SigninRequests: []*models.SigninRequest{{Id: "token", Pin: "1234"}}}
+		changes := Diff(before, after)
+		require.Len(t, changes, 1)
+		assert.Equal(t, "signin_requests", changes[0].Field)
+		assert.NotContains(t, changes[0].After, "1234")
+	})
+
+	t.Run("redacts header values", func(t *testing.T) {
+		before := &models.Backend{Fqdn: "a.example.com"}
+		after := &models.Backend{Fqdn: "a.example.com", Headers: []*models.Header{{Name: "Authorization", Value: "Bearer token"}}}
+		changes := Diff(before, after)
+		require.Len(t, changes, 1)
+		assert.Equal(t, "headers", changes[0].Field)
+		assert.Contains(t, changes[0].After, "Authorization")
+		assert.NotContains(t, changes[0].After, "Bearer token")
+	})
+}
+
+func TestNewRecord(t *testing.T) {
+	record := NewRecord(Actor{Id: "admin", Name: "admin@example.com", SessionId: "session", Ip: "10.0.0.1"},
+		"backend.update", "a.example.com", nil, &models.Backend{Fqdn: "a.example.com"})
+	assert.NotEmpty(t, record.Id)
+	assert.Equal(t, "backend", record.TargetType)
+	assert.Equal(t, "a.example.com", record.TargetId)
+	assert.Equal(t, "admin@example.com", record.ActorName)
+	assert.Len(t, record.Changes, 1)
+}
@@ -0,0 +1,154 @@
+package auth
+
+import (
+	"boivie/ubergang/server/common"
+	"boivie/ubergang/server/models"
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// Allows accessing backends through the proxy.
+	ScopeProxy = "proxy"
+	// Allows provisioning users and groups through SCIM. Only for admins.
+	ScopeScim = "scim"
+	// Allows using the admin API, e.g. from scripts. Only for admins.
+	ScopeAdmin = "admin"
+)
+
+var AccessTokenScopes = []string{ScopeProxy, ScopeScim, ScopeAdmin}
+
+// IsAdminScope returns true if only admins may be granted `scope`.
+func IsAdminScope(scope string) bool {
+	return scope == ScopeScim || scope == ScopeAdmin
+}
+
+const accessTokenPrefix = "ugt_"
+
+// How often the last-used time of a token is written to the database.
+const accessTokenUsageResolution = 1 * time.Minute
+
+func hashAccessTokenSecret(secret string) []byte {
+	// The secret is long and random, so a fast hash is sufficient, and it's
+	// checked on every proxied request.
+	hash := sha256.Sum256([]byte(secret))
+	return hash[:]
+}
+
+// IsAccessToken returns true if `value` looks like a personal access token, as
+// opposed to other kinds of bearer tokens.
+func IsAccessToken(value string) bool {
+	return strings.HasPrefix(value, accessTokenPrefix)
+}
+
+// BearerToken returns the token from the Authorization header, if present. The
+// scheme is case-insensitive, see https://www.rfc-editor.org/rfc/rfc9110#section-11.1
+func BearerToken(r *http.Request) (string, bool) {
+	value := r.Header.Get("Authorization")
+	if len(value) < 7 || !strings.EqualFold(value[:7], "bearer ") {
+		return "", false
+	}
+	return strings.TrimSpace(value[7:]), true
+}
+
+func FormatAccessToken(id, secret string) string {
+	return accessTokenPrefix + id + "_" + secret
+}
+
+// CreateAccessToken creates a new token and returns it, along with the value
+// that is to be given to the user. That value can't be retrieved later.
+func (s *Auth) CreateAccessToken(userId, name string, scopes, allowedHosts []string, expiresAt time.Time) (*models.AccessToken, string, error) {
+	secret := common.MakeRandomSecret()
+	token := &models.AccessToken{
+		Id:           common.MakeRandomID(),
+		UserId:       userId,
+		Name:         name,
+		HashedSecret: hashAccessTokenSecret(secret),
+		Scopes:       scopes,
+		AllowedHosts: allowedHosts,
+		CreatedAt:    timestamppb.Now(),
+		ExpiresAt:    timestamppb.New(expiresAt),
+	}
+	err := s.db.UpdateAccessToken(token.Id, func(old *models.AccessToken) (*models.AccessToken, error) {
+		if old != nil {
+			return nil, errors.New("ID collision")
+		}
+		return token, nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	s.log.Infof("Created access token %s for user %s", token.Id, userId)
+	return token, FormatAccessToken(token.Id, secret), nil
+}
+
+// ValidateAccessToken returns the token and its user if `value` is a valid
+// and non-expired access token.
+func (s *Auth) ValidateAccessToken(value string, now time.Time) (*models.User, *models.AccessToken, error) {
+	if !strings.HasPrefix(value, accessTokenPrefix) {
+		return nil, nil, errors.New("not an access token")
+	}
+	id, secret, found := strings.Cut(value[len(accessTokenPrefix):], "_")
+	if !found {
+		return nil, nil, errors.New("malformed access token")
+	}
+
+	token, err := s.db.GetAccessToken(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if subtle.ConstantTimeCompare(token.HashedSecret, hashAccessTokenSecret(secret)) != 1 {
+		return nil, nil, errors.New("invalid access token secret")
+	}
+	if token.ExpiresAt != nil && now.After(token.ExpiresAt.AsTime()) {
+		return nil, nil, errors.New("access token has expired")
+	}
+
+	user, err := s.db.GetUserById(token.UserId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user.IsDisabled {
+		return nil, nil, errors.New("user is disabled")
+	}
+
+	if token.LastUsedAt == nil || now.Sub(token.LastUsedAt.AsTime()) > accessTokenUsageResolution {
+		token.LastUsedAt = timestamppb.New(now)
+		err = s.db.UpdateAccessToken(token.Id, func(old *models.AccessToken) (*models.AccessToken, error) {
+			if old == nil {
+				return nil, errors.New("access token was deleted")
+			}
+			old.LastUsedAt = token.LastUsedAt
+			return old, nil
+		})
+		if err != nil {
+			s.log.Warnf("Failed to update last use of access token %s: %v", token.Id, err)
+		}
+	}
+	return user, token, nil
+}
+
+func HasScope(token *models.AccessToken, scope string) bool {
+	for _, s := range token.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAllowed returns true if `user` may access `host`. If the user
+// authenticated using an access token, the token must allow it as well.
+func IsAllowed(user *models.User, token *models.AccessToken, host string) bool {
+	if token != nil && len(token.AllowedHosts) > 0 && !slices.Contains(token.AllowedHosts, host) {
+		return false
+	}
+	return user.IsAdmin || slices.Contains(user.AllowedHosts, host)
+}
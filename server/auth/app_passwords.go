@@ -0,0 +1,170 @@
+package auth
+
+import (
+	"boivie/ubergang/server/common"
+	"boivie/ubergang/server/models"
+	"crypto/sha256"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// How long a successfully verified app password is remembered. Clients using
+// HTTP Basic authentication send the password with every request, and bcrypt
+// is intentionally too slow to run that often.
+const appPasswordCacheTTL = 5 * time.Minute
+
+// How often the last-used time of an app password is written to the database,
+// unless it's used from a new IP address.
+const appPasswordUsageResolution = 1 * time.Minute
+
+type appPasswordCacheEntry struct {
+	appPasswordId string
+	expiresAt     time.Time
+}
+
+type appPasswordCache struct {
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]appPasswordCacheEntry
+}
+
+func newAppPasswordCache() *appPasswordCache {
+	return &appPasswordCache{entries: make(map[[sha256.Size]byte]appPasswordCacheEntry)}
+}
+
+func appPasswordCacheKey(host, username, password string) [sha256.Size]byte {
+	return sha256.Sum256([]byte(host + "\x00" + username + "\x00" + password))
+}
+
+func (c *appPasswordCache) get(key [sha256.Size]byte, now time.Time) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, found := c.entries[key]
+	if !found {
+		return "", false
+	}
+	if now.After(entry.expiresAt) {
+		delete(c.entries, key)
+		return "", false
+	}
+	return entry.appPasswordId, true
+}
+
+func (c *appPasswordCache) put(key [sha256.Size]byte, appPasswordId string, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = appPasswordCacheEntry{appPasswordId, now.Add(appPasswordCacheTTL)}
+}
+
+// makeAppPassword returns a random password that is easy to type on devices
+// without a proper keyboard, e.g. "abcd-efgh-ijkl-mnop".
+func makeAppPassword() string {
+	secret := strings.ToLower(common.MakeRandomSecret())
+	groups := make([]string, 0, 4)
+	for i := 0; i < 4; i++ {
+		groups = append(groups, secret[i*4:(i+1)*4])
+	}
+	return strings.Join(groups, "-")
+}
+
+// CreateAppPassword creates an app password for `backendFqdn` and returns it,
+// along with the password. The password can't be retrieved later.
+func (s *Auth) CreateAppPassword(userId, backendFqdn, name string) (*models.AppPassword, string, error) {
+	password := makeAppPassword()
+	hashed, err := common.HashPassword(password)
+	if err != nil {
+		return nil, "", err
+	}
+	appPassword := &models.AppPassword{
+		Id:             common.MakeRandomID(),
+		UserId:         userId,
+		BackendFqdn:    strings.ToLower(backendFqdn),
+		Name:           name,
+		HashedPassword: hashed,
+		CreatedAt:      timestamppb.Now(),
+	}
+	err = s.db.UpdateAppPassword(appPassword.Id, func(old *models.AppPassword) (*models.AppPassword, error) {
+		if old != nil {
+			return nil, errors.New("ID collision")
+		}
+		return appPassword, nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	s.log.Infof("Created app password %s for user %s and backend %s", appPassword.Id, userId, backendFqdn)
+	return appPassword, password, nil
+}
+
+// IsAppPasswordCached returns true if `password` has recently been verified
+// for `username` and `host`, so that validating it again is cheap.
+func (s *Auth) IsAppPasswordCached(host, username, password string, now time.Time) bool {
+	_, found := s.appPasswordCache.get(appPasswordCacheKey(strings.ToLower(host), username, password), now)
+	return found
+}
+
+// ValidateAppPassword returns the user and the app password if `username` (the
+// user's email address) and `password` are valid for `host`.
+func (s *Auth) ValidateAppPassword(host, username, password, remoteAddr string, now time.Time) (*models.User, *models.AppPassword, error) {
+	host = strings.ToLower(host)
+	user, err := s.db.GetUserByEmail(username)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user.IsDisabled {
+		return nil, nil, errors.New("user is disabled")
+	}
+
+	var appPassword *models.AppPassword
+	cacheKey := appPasswordCacheKey(host, username, password)
+	if id, found := s.appPasswordCache.get(cacheKey, now); found {
+		// It may have been revoked since it was cached.
+		appPassword, err = s.db.GetAppPassword(id)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		for _, candidate := range s.db.ListAppPasswords(user.Id) {
+			if candidate.BackendFqdn != host {
+				continue
+			}
+			if common.CheckPassword(candidate.HashedPassword, password) == nil {
+				appPassword = candidate
+				break
+			}
+		}
+		if appPassword == nil {
+			return nil, nil, errors.New("invalid app password")
+		}
+		s.appPasswordCache.put(cacheKey, appPassword.Id, now)
+	}
+
+	if appPassword.UserId != user.Id || appPassword.BackendFqdn != host {
+		return nil, nil, errors.New("invalid app password")
+	}
+
+	if appPassword.LastUsedAt == nil ||
+		now.Sub(appPassword.LastUsedAt.AsTime()) > appPasswordUsageResolution ||
+		appPassword.LastRemoteAddr != remoteAddr {
+		err = s.db.UpdateAppPassword(appPassword.Id, func(old *models.AppPassword) (*models.AppPassword, error) {
+			if old == nil {
+				return nil, errors.New("app password was deleted")
+			}
+			old.LastUsedAt = timestamppb.New(now)
+			old.LastRemoteAddr = remoteAddr
+			return old, nil
+		})
+		if err != nil {
+			s.log.Warnf("Failed to update last use of app password %s: %v", appPassword.Id, err)
+		}
+	}
+	return user, appPassword, nil
+}
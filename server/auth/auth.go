@@ -5,25 +5,44 @@ import (
 	"boivie/ubergang/server/db"
 	"boivie/ubergang/server/log"
 	"boivie/ubergang/server/models"
+	"crypto/ecdsa"
 	"net"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+const (
+	// How long the sign-in links of new users, and invitations, are valid.
+	InvitationLifetime = 7 * 24 * time.Hour
+	// The longest time an invitation may be valid.
+	MaxInvitationLifetime = 30 * 24 * time.Hour
+)
+
 type Auth struct {
 	log *log.Log
 	db  *db.DB
+
+	appPasswordCache *appPasswordCache
+
+	signingKeyMu sync.Mutex
+	signingKey   *ecdsa.PrivateKey
 }
 
 func New(log *log.Log, db *db.DB) *Auth {
-	return &Auth{log, db}
+	return &Auth{
+		log:              log,
+		db:               db,
+		appPasswordCache: newAppPasswordCache(),
+	}
 }
 
 func (s *Auth) CreateUser(email string, displayName string, admin bool, allowedHosts []string) (user *models.User, pollId string, err error) {
 	pollId = common.MakeSigninRequestToken()
 	userId := common.MakeRandomID()
+	now := time.Now()
 
 	user = &models.User{
 		Id:           userId,
@@ -34,8 +53,9 @@ func (s *Auth) CreateUser(email string, displayName string, admin bool, allowedH
 		SigninRequests: []*models.SigninRequest{
 			{
 				Id:        pollId,
-				ExpiresAt: timestamppb.New(time.Now().Add(7 * 24 * time.Hour)),
+				ExpiresAt: timestamppb.New(now.Add(InvitationLifetime)),
 				Confirmed: true,
+				CreatedAt: timestamppb.New(now),
 			},
 		},
 	}
@@ -52,10 +72,28 @@ func (s *Auth) CreateUser(email string, displayName string, admin bool, allowedH
 }
 
 func (s *Auth) CreateSession(userId, userAgent, remoteAddr string) (*models.Session, error) {
+	return s.createSession(userId, userAgent, remoteAddr, func(session *models.Session) {})
+}
+
+// CreateImpersonationSession creates a session that the administrator using
+// `impersonator` can use to view the site as the user `userId`. It expires
+// after `lifetime`.
+func (s *Auth) CreateImpersonationSession(userId string, impersonator *models.Session, userAgent, remoteAddr string, lifetime time.Duration) (*models.Session, error) {
+	return s.createSession(userId, userAgent, remoteAddr, func(session *models.Session) {
+		session.ImpersonatorId = impersonator.UserId
+		session.ImpersonatorSessionId = impersonator.Id
+		session.ExpiresAt = timestamppb.New(session.CreatedAt.AsTime().Add(lifetime))
+	})
+}
+
+func (s *Auth) createSession(userId, userAgent, remoteAddr string, fixup func(session *models.Session)) (*models.Session, error) {
 	user, err := s.db.GetUserById(userId)
 	if err != nil {
 		return nil, err
 	}
+	if user.IsDisabled {
+		return nil, errors.New("user is disabled")
+	}
 
 	ip, _, err := net.SplitHostPort(remoteAddr)
 	if err == nil {
@@ -64,13 +102,15 @@ func (s *Auth) CreateSession(userId, userAgent, remoteAddr string) (*models.Sess
 
 	now := time.Now()
 	session := &models.Session{
-		Id:         common.MakeRandomID(),
-		UserId:     user.Id,
-		Secret:     common.MakeRandomID(),
-		UserAgent:  userAgent,
-		RemoteAddr: remoteAddr,
-		CreatedAt:  timestamppb.New(now),
+		Id:              common.MakeRandomID(),
+		UserId:          user.Id,
+		Secret:          common.MakeRandomID(),
+		UserAgent:       userAgent,
+		RemoteAddr:      remoteAddr,
+		CreatedAt:       timestamppb.New(now),
+		AuthenticatedAt: timestamppb.New(now),
 	}
+	fixup(session)
 
 	err = s.db.UpdateSession(session.Id, func(old *models.Session) (*models.Session, error) {
 		if old != nil {
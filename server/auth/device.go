@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"boivie/ubergang/server/common"
+	"boivie/ubergang/server/models"
+	"crypto/subtle"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	DeviceAuthorizationLifetime = 10 * time.Minute
+	// How long clients must wait between polls, which is increased each time
+	// they poll too often.
+	devicePollInterval = 5 * time.Second
+)
+
+// Errors returned when polling a device authorization, which are named as the
+// corresponding OAuth error codes.
+var (
+	ErrAuthorizationPending = errors.New("authorization_pending")
+	ErrSlowDown             = errors.New("slow_down")
+	ErrAccessDenied         = errors.New("access_denied")
+	ErrExpiredToken         = errors.New("expired_token")
+)
+
+// NormalizeUserCode removes the separators that the user may have typed.
+func NormalizeUserCode(code string) string {
+	code = strings.ToUpper(code)
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, code)
+}
+
+// FormatUserCode returns the user code in the form that is shown to the user,
+// e.g. "BCDF-GHJK".
+func FormatUserCode(code string) string {
+	if len(code) != 8 {
+		return code
+	}
+	return code[:4] + "-" + code[4:]
+}
+
+// CreateDeviceAuthorization creates a pending device authorization request,
+// and returns it along with the device code that is to be given to the client.
+func (s *Auth) CreateDeviceAuthorization(clientId string, scopes []string, ip, userAgent string, now time.Time) (*models.DeviceAuthorization, string, error) {
+	userCode, err := common.MakeDeviceUserCode()
+	if err != nil {
+		return nil, "", err
+	}
+	secret := common.MakeRandomSecret()
+	authorization := &models.DeviceAuthorization{
+		Id:              common.MakeRandomID(),
+		HashedSecret:    hashAccessTokenSecret(secret),
+		UserCode:        userCode,
+		ClientId:        clientId,
+		Scopes:          scopes,
+		CreatedAt:       timestamppb.New(now),
+		ExpiresAt:       timestamppb.New(now.Add(DeviceAuthorizationLifetime)),
+		IntervalSeconds: int32(devicePollInterval.Seconds()),
+		Ip:              ip,
+		UserAgent:       userAgent,
+	}
+	err = s.db.UpdateDeviceAuthorization(authorization.Id, func(old *models.DeviceAuthorization) (*models.DeviceAuthorization, error) {
+		if old != nil {
+			return nil, errors.New("ID collision")
+		}
+		return authorization, nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return authorization, authorization.Id + "_" + secret, nil
+}
+
+// PollDeviceAuthorization returns the device authorization if it has been
+// approved, after which it can't be used again. Otherwise, one of the Err*
+// errors above is returned, or a generic error if the device code is invalid.
+func (s *Auth) PollDeviceAuthorization(deviceCode, clientId string, now time.Time) (*models.DeviceAuthorization, error) {
+	id, secret, found := strings.Cut(deviceCode, "_")
+	if !found {
+		return nil, errors.New("malformed device code")
+	}
+
+	var ret *models.DeviceAuthorization
+	var result error
+	err := s.db.UpdateDeviceAuthorization(id, func(old *models.DeviceAuthorization) (*models.DeviceAuthorization, error) {
+		if old == nil {
+			return nil, errors.New("device authorization not found")
+		}
+		if subtle.ConstantTimeCompare(old.HashedSecret, hashAccessTokenSecret(secret)) != 1 {
+			return nil, errors.New("invalid device code")
+		}
+		if old.ClientId != clientId {
+			return nil, errors.New("device code was not issued to this client")
+		}
+		switch {
+		case now.After(old.ExpiresAt.AsTime()):
+			result = ErrExpiredToken
+			return nil, nil
+		case old.Denied:
+			result = ErrAccessDenied
+			return nil, nil
+		case old.UserId != "":
+			ret = old
+			return nil, nil
+		}
+
+		interval := time.Duration(old.IntervalSeconds) * time.Second
+		if old.LastPolledAt != nil && now.Sub(old.LastPolledAt.AsTime()) < interval {
+			old.IntervalSeconds += int32(devicePollInterval.Seconds())
+			result = ErrSlowDown
+		} else {
+			result = ErrAuthorizationPending
+		}
+		old.LastPolledAt = timestamppb.New(now)
+		return old, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, result
+}
+
+// DecideDeviceAuthorization records that the user has approved or denied a
+// pending device authorization request.
+func (s *Auth) DecideDeviceAuthorization(id string, userId string, approved bool, now time.Time) error {
+	return s.db.UpdateDeviceAuthorization(id, func(old *models.DeviceAuthorization) (*models.DeviceAuthorization, error) {
+		if old == nil || now.After(old.ExpiresAt.AsTime()) {
+			return nil, errors.New("device authorization not found or expired")
+		}
+		if old.UserId != "" || old.Denied {
+			return nil, errors.New("device authorization has already been decided")
+		}
+		if approved {
+			old.UserId = userId
+			old.ApprovedAt = timestamppb.New(now)
+		} else {
+			old.Denied = true
+		}
+		return old, nil
+	})
+}
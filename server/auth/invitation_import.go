@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"boivie/ubergang/server/models"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// An invitee, as listed in an import file.
+type Invitee struct {
+	Email        string   `yaml:"email"`
+	DisplayName  string   `yaml:"display_name"`
+	Admin        bool     `yaml:"admin"`
+	AllowedHosts []string `yaml:"allowed_hosts"`
+	Groups       []string `yaml:"groups"`
+}
+
+func (i *Invitee) Invitation() *models.Invitation {
+	return &models.Invitation{
+		Email:        strings.TrimSpace(i.Email),
+		DisplayName:  strings.TrimSpace(i.DisplayName),
+		IsAdmin:      i.Admin,
+		AllowedHosts: i.AllowedHosts,
+		Groups:       i.Groups,
+	}
+}
+
+// splitList splits a CSV cell with values separated by semicolons or
+// whitespace.
+func splitList(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ';' || r == ' ' || r == '\t'
+	})
+}
+
+// parseInviteesCsv parses CSV with a header row. The "email" column is
+// required, and "display_name", "admin", "allowed_hosts" and "groups" are
+// optional.
+func parseInviteesCsv(data []byte) ([]Invitee, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.TrimLeadingSpace = true
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(header[i]))
+	}
+	if !slices.Contains(header, "email") {
+		return nil, fmt.Errorf("missing email column")
+	}
+	var ret []Invitee
+	for line := 2; ; line++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		var invitee Invitee
+		for i, value := range record {
+			if i >= len(header) {
+				break
+			}
+			value = strings.TrimSpace(value)
+			switch header[i] {
+			case "email":
+				invitee.Email = value
+			case "display_name":
+				invitee.DisplayName = value
+			case "admin":
+				if value != "" {
+					if invitee.Admin, err = strconv.ParseBool(value); err != nil {
+						return nil, fmt.Errorf("line %d: invalid admin value %q", line, value)
+					}
+				}
+			case "allowed_hosts":
+				invitee.AllowedHosts = splitList(value)
+			case "groups":
+				invitee.Groups = splitList(value)
+			}
+		}
+		ret = append(ret, invitee)
+	}
+	return ret, nil
+}
+
+// ParseInvitees parses a list of invitees, either as CSV if `isCsv` or
+// otherwise as a YAML list.
+func ParseInvitees(data []byte, isCsv bool) ([]Invitee, error) {
+	var invitees []Invitee
+	var err error
+	if isCsv {
+		invitees, err = parseInviteesCsv(data)
+	} else {
+		err = yaml.Unmarshal(data, &invitees)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i, invitee := range invitees {
+		if strings.TrimSpace(invitee.Email) == "" {
+			return nil, fmt.Errorf("invitee %d has no e-mail address", i+1)
+		}
+	}
+	return invitees, nil
+}
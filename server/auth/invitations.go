@@ -0,0 +1,153 @@
+package auth
+
+import (
+	"boivie/ubergang/server/common"
+	"boivie/ubergang/server/models"
+	"crypto/sha256"
+	"crypto/subtle"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	ErrInvitationInvalid  = errors.New("invalid invitation")
+	ErrInvitationExpired  = errors.New("invitation has expired")
+	ErrInvitationRevoked  = errors.New("invitation has been revoked")
+	ErrInvitationRedeemed = errors.New("invitation has already been used")
+	ErrUserExists         = errors.New("a user with this e-mail address already exists")
+)
+
+func hashInvitationSecret(secret string) []byte {
+	hash := sha256.Sum256([]byte(secret))
+	return hash[:]
+}
+
+// InvitationState returns "pending", "expired", "revoked" or "redeemed".
+func InvitationState(invitation *models.Invitation, now time.Time) string {
+	switch {
+	case invitation.RedeemedAt != nil:
+		return "redeemed"
+	case invitation.RevokedAt != nil:
+		return "revoked"
+	case now.After(invitation.ExpiresAt.AsTime()):
+		return "expired"
+	default:
+		return "pending"
+	}
+}
+
+// CreateInvitation stores `invitation`, which expires after `lifetime`, and
+// returns the token to put in the invitation link. The token can't be
+// retrieved later.
+func (s *Auth) CreateInvitation(invitation *models.Invitation, lifetime time.Duration, now time.Time) (string, error) {
+	invitation.Email = strings.TrimSpace(invitation.Email)
+	if invitation.Email == "" {
+		return "", errors.New("e-mail address is required")
+	}
+	if invitation.DisplayName == "" {
+		invitation.DisplayName = invitation.Email
+	}
+	if _, err := s.db.GetUserByEmail(invitation.Email); err == nil {
+		return "", ErrUserExists
+	}
+	secret := common.MakeRandomSecret()
+	invitation.Id = common.MakeRandomID()
+	invitation.HashedSecret = hashInvitationSecret(secret)
+	invitation.CreatedAt = timestamppb.New(now)
+	invitation.ExpiresAt = timestamppb.New(now.Add(lifetime))
+	err := s.db.UpdateInvitation(invitation.Id, func(old *models.Invitation) (*models.Invitation, error) {
+		if old != nil {
+			return nil, errors.New("ID collision")
+		}
+		return invitation, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	s.log.Infof("Created invitation %s for %s", invitation.Id, invitation.Email)
+	return invitation.Id + "_" + secret, nil
+}
+
+// RevokeInvitation makes a pending invitation unusable, and returns it as it
+// was before.
+func (s *Auth) RevokeInvitation(id string, now time.Time) (before *models.Invitation, after *models.Invitation, err error) {
+	err = s.db.UpdateInvitation(id, func(old *models.Invitation) (*models.Invitation, error) {
+		if old == nil {
+			return nil, ErrInvitationInvalid
+		}
+		if old.RedeemedAt != nil {
+			return nil, ErrInvitationRedeemed
+		}
+		before = proto.Clone(old).(*models.Invitation)
+		if old.RevokedAt == nil {
+			old.RevokedAt = timestamppb.New(now)
+		}
+		after = old
+		return old, nil
+	})
+	return
+}
+
+// RedeemInvitation creates the user that `token` invites. The invitation can
+// only be used once.
+func (s *Auth) RedeemInvitation(token string, now time.Time) (*models.User, *models.Invitation, error) {
+	id, secret, found := strings.Cut(token, "_")
+	if !found {
+		return nil, nil, ErrInvitationInvalid
+	}
+	var invitation *models.Invitation
+	err := s.db.UpdateInvitation(id, func(old *models.Invitation) (*models.Invitation, error) {
+		if old == nil || subtle.ConstantTimeCompare(old.HashedSecret, hashInvitationSecret(secret)) != 1 {
+			return nil, ErrInvitationInvalid
+		}
+		switch InvitationState(old, now) {
+		case "redeemed":
+			return nil, ErrInvitationRedeemed
+		case "revoked":
+			return nil, ErrInvitationRevoked
+		case "expired":
+			return nil, ErrInvitationExpired
+		}
+		old.RedeemedAt = timestamppb.New(now)
+		old.UserId = common.MakeRandomID()
+		invitation = old
+		return old, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user := &models.User{
+		Id:           invitation.UserId,
+		Email:        invitation.Email,
+		DisplayName:  invitation.DisplayName,
+		AllowedHosts: invitation.AllowedHosts,
+		Groups:       invitation.Groups,
+		IsAdmin:      invitation.IsAdmin,
+	}
+	err = s.db.UpdateUser(user.Id, func(old *models.User) (*models.User, error) {
+		if old != nil {
+			return nil, errors.New("user already exists")
+		}
+		return user, nil
+	})
+	if err != nil {
+		// Most likely, the e-mail address has been taken since the invitation
+		// was created. Let it be used again when that's been sorted out.
+		_ = s.db.UpdateInvitation(id, func(old *models.Invitation) (*models.Invitation, error) {
+			if old != nil {
+				old.RedeemedAt = nil
+				old.UserId = ""
+			}
+			return old, nil
+		})
+		s.log.Warnf("Failed to create user from invitation %s: %v", id, err)
+		return nil, nil, ErrUserExists
+	}
+	s.log.Infof("Created user %s from invitation %s", user.Email, id)
+	return user, invitation, nil
+}
@@ -0,0 +1,253 @@
+package auth
+
+import (
+	"boivie/ubergang/server/common"
+	"boivie/ubergang/server/models"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Scopes that OIDC clients can request.
+const (
+	OidcScopeOpenID  = "openid"
+	OidcScopeProfile = "profile"
+	OidcScopeEmail   = "email"
+	OidcScopeGroups  = "groups"
+)
+
+var OidcScopes = []string{OidcScopeOpenID, OidcScopeProfile, OidcScopeEmail, OidcScopeGroups}
+
+const oidcTokenLifetime = 1 * time.Hour
+
+// The path of the userinfo endpoint, relative to the issuer. It's also the
+// audience of the access tokens issued to OIDC clients.
+const OidcUserinfoPath = "/oauth/userinfo"
+
+type OidcAccessTokenClaims struct {
+	jwt.RegisteredClaims
+	Scope    string `json:"scope"`
+	ClientId string `json:"client_id"`
+}
+
+// SetOidcClientSecret generates a new client secret for the OIDC client,
+// replacing any previous one, and returns it.
+func (s *Auth) SetOidcClientSecret(id string) (string, error) {
+	secret := common.MakeRandomSecret()
+	hashed, err := common.HashPassword(secret)
+	if err != nil {
+		return "", err
+	}
+	err = s.db.UpdateOidcClient(id, func(old *models.OidcClient) (*models.OidcClient, error) {
+		if old == nil {
+			return nil, errors.New("OIDC client not found")
+		}
+		if old.IsPublic {
+			return nil, errors.New("public clients can't have a secret")
+		}
+		old.HashedClientSecret = hashed
+		old.UpdatedAt = timestamppb.Now()
+		return old, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	s.log.Infof("Created new client secret for OIDC client %s", id)
+	return secret, nil
+}
+
+// AuthenticateOidcClient validates the client credentials of an OIDC client.
+// Public clients are only identified, as they have no secret.
+func (s *Auth) AuthenticateOidcClient(clientId, clientSecret string) (*models.OidcClient, error) {
+	client, err := s.db.GetOidcClient(clientId)
+	if err != nil {
+		return nil, err
+	}
+	if client.IsPublic {
+		return client, nil
+	}
+	if err := common.CheckPassword(client.HashedClientSecret, clientSecret); err != nil {
+		return nil, errors.Wrap(err, "invalid client secret")
+	}
+	return client, nil
+}
+
+// CreateAuthorizationCode stores the authorization granted by the user, and
+// returns the authorization code that is to be given to the client.
+func (s *Auth) CreateAuthorizationCode(userId string, authorization *models.AuthenticationStateOidcAuthorize, expiresAt time.Time) (string, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return "", err
+	}
+	// The state ID alone is partly predictable, as it contains the time.
+	secret := common.MakeRandomSecret()
+	authorization.HashedCodeSecret = hashAccessTokenSecret(secret)
+	err = s.db.StoreAuthenticationState(&id, &models.AuthenticationState{
+		UserId:    userId,
+		ExpiresAt: timestamppb.New(expiresAt),
+		Type: &models.AuthenticationState_OidcAuthorize{
+			OidcAuthorize: authorization,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	return id.String() + "_" + secret, nil
+}
+
+// ConsumeAuthorizationCode returns the authorization of `code`, after which
+// the code can't be used again.
+func (s *Auth) ConsumeAuthorizationCode(code string) (*models.AuthenticationState, error) {
+	id, secret, found := strings.Cut(code, "_")
+	if !found {
+		return nil, errors.New("malformed authorization code")
+	}
+	state, err := s.db.ConsumeAuthenticationState(id)
+	if err != nil {
+		return nil, err
+	}
+	authorization := state.GetOidcAuthorize()
+	if authorization == nil ||
+		subtle.ConstantTimeCompare(authorization.HashedCodeSecret, hashAccessTokenSecret(secret)) != 1 {
+		return nil, errors.New("invalid authorization code")
+	}
+	return state, nil
+}
+
+// VerifyCodeChallenge returns true if `verifier` matches the S256 PKCE code
+// `challenge`. See https://www.rfc-editor.org/rfc/rfc7636#section-4.6
+func VerifyCodeChallenge(verifier, challenge string) bool {
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+	hash := sha256.Sum256([]byte(verifier))
+	computed := base64.RawURLEncoding.EncodeToString(hash[:])
+	return subtle.ConstantTimeCompare([]byte(computed), []byte(challenge)) == 1
+}
+
+// UserGroups returns the names of the groups that `user` is a member of, both
+// assigned and provisioned.
+func (s *Auth) UserGroups(user *models.User) []string {
+	groups := append([]string{}, user.Groups...)
+	for _, id := range user.GroupIds {
+		group, err := s.db.GetGroup(id)
+		if err != nil {
+			// Deleted groups are removed from their members.
+			continue
+		}
+		if !slices.Contains(groups, group.DisplayName) {
+			groups = append(groups, group.DisplayName)
+		}
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+// IsGroupAllowed returns true if `user` is allowed to sign in to `client`.
+func (s *Auth) IsGroupAllowed(client *models.OidcClient, user *models.User) bool {
+	if len(client.AllowedGroups) == 0 {
+		return true
+	}
+	for _, group := range s.UserGroups(user) {
+		if slices.Contains(client.AllowedGroups, group) {
+			return true
+		}
+	}
+	return false
+}
+
+// OidcUserClaims returns the claims about `user` that are released for the
+// given scopes.
+func (s *Auth) OidcUserClaims(user *models.User, scopes []string) map[string]interface{} {
+	claims := map[string]interface{}{
+		"sub": user.Id,
+	}
+	if slices.Contains(scopes, OidcScopeProfile) {
+		claims["name"] = user.DisplayName
+		claims["preferred_username"] = user.Email
+	}
+	if slices.Contains(scopes, OidcScopeEmail) {
+		claims["email"] = user.Email
+		// E-mail addresses are set by administrators.
+		claims["email_verified"] = true
+	}
+	if slices.Contains(scopes, OidcScopeGroups) {
+		claims["groups"] = s.UserGroups(user)
+	}
+	return claims
+}
+
+// IssueOidcTokens returns an ID token and an access token for the userinfo
+// endpoint, for an authorization granted by `user` to `client`.
+func (s *Auth) IssueOidcTokens(client *models.OidcClient, user *models.User, authorization *models.AuthenticationStateOidcAuthorize, issuer string, now time.Time) (idToken string, accessToken string, lifetime time.Duration, err error) {
+	expiresAt := now.Add(oidcTokenLifetime)
+
+	idClaims := jwt.MapClaims{}
+	for k, v := range s.OidcUserClaims(user, authorization.Scopes) {
+		idClaims[k] = v
+	}
+	idClaims["iss"] = issuer
+	idClaims["aud"] = client.Id
+	idClaims["iat"] = jwt.NewNumericDate(now)
+	idClaims["exp"] = jwt.NewNumericDate(expiresAt)
+	if authorization.AuthTime != nil {
+		idClaims["auth_time"] = jwt.NewNumericDate(authorization.AuthTime.AsTime())
+	}
+	if authorization.Nonce != "" {
+		idClaims["nonce"] = authorization.Nonce
+	}
+	idToken, err = s.SignToken(idClaims)
+	if err != nil {
+		return
+	}
+
+	accessToken, err = s.SignToken(&OidcAccessTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   user.Id,
+			Audience:  jwt.ClaimStrings{issuer + OidcUserinfoPath},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			ID:        common.MakeRandomID(),
+		},
+		Scope:    strings.Join(authorization.Scopes, " "),
+		ClientId: client.Id,
+	})
+	lifetime = oidcTokenLifetime
+	return
+}
+
+// ValidateOidcAccessToken returns the user and the granted scopes if `value`
+// is a valid access token issued by IssueOidcTokens.
+func (s *Auth) ValidateOidcAccessToken(value, issuer string, now time.Time) (*models.User, []string, error) {
+	claims := &OidcAccessTokenClaims{}
+	err := s.ParseToken(value, claims,
+		jwt.WithIssuer(issuer),
+		jwt.WithAudience(issuer+OidcUserinfoPath),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(func() time.Time { return now }))
+	if err != nil {
+		return nil, nil, err
+	}
+	// The client may have been deleted since the token was issued.
+	if _, err := s.db.GetOidcClient(claims.ClientId); err != nil {
+		return nil, nil, err
+	}
+	user, err := s.db.GetUserById(claims.Subject)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user.IsDisabled {
+		return nil, nil, errors.New("user is disabled")
+	}
+	return user, strings.Fields(claims.Scope), nil
+}
@@ -0,0 +1,217 @@
+package auth
+
+import (
+	"boivie/ubergang/server/common"
+	"boivie/ubergang/server/models"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// The audience of access tokens issued to service accounts, which separates
+// them from other tokens signed by the server.
+const ServiceAccountTokenAudience = "ubergang-proxy"
+
+const serviceAccountTokenLifetime = 1 * time.Hour
+
+// The longest time a JWT assertion may be valid for, counted from when it was
+// issued. Each assertion can only be used once.
+const MaxServiceAccountAssertionLifetime = 5 * time.Minute
+
+const serviceAccountSubjectPrefix = "svc:"
+
+type ServiceAccountClaims struct {
+	jwt.RegisteredClaims
+	Scope string `json:"scope,omitempty"`
+}
+
+// ParsePublicKey parses a PEM-encoded ECDSA, RSA or Ed25519 public key, as used
+// by service accounts to sign JWT assertions.
+func ParsePublicKey(data string) (interface{}, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	switch key.(type) {
+	case *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey:
+		return key, nil
+	}
+	return nil, errors.New("unsupported key type")
+}
+
+// SetServiceAccountSecret generates a new client secret for the service
+// account, replacing any previous one, and returns it.
+func (s *Auth) SetServiceAccountSecret(id string) (string, error) {
+	secret := common.MakeRandomSecret()
+	hashed, err := common.HashPassword(secret)
+	if err != nil {
+		return "", err
+	}
+	err = s.db.UpdateServiceAccount(id, func(old *models.ServiceAccount) (*models.ServiceAccount, error) {
+		if old == nil {
+			return nil, errors.New("service account not found")
+		}
+		old.HashedClientSecret = hashed
+		old.UpdatedAt = timestamppb.Now()
+		return old, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	s.log.Infof("Created new client secret for service account %s", id)
+	return secret, nil
+}
+
+var ErrServiceAccountDisabled = errors.New("service account is disabled")
+
+// getEnabledServiceAccount returns the service account, unless it's disabled.
+func (s *Auth) getEnabledServiceAccount(id string) (*models.ServiceAccount, error) {
+	account, err := s.db.GetServiceAccount(id)
+	if err != nil {
+		return nil, err
+	}
+	if account.Disabled {
+		return nil, ErrServiceAccountDisabled
+	}
+	return account, nil
+}
+
+// AuthenticateServiceAccount validates the client credentials of a service
+// account.
+func (s *Auth) AuthenticateServiceAccount(clientId, clientSecret string) (*models.ServiceAccount, error) {
+	account, err := s.getEnabledServiceAccount(clientId)
+	if err != nil {
+		return nil, err
+	}
+	if err := common.CheckPassword(account.HashedClientSecret, clientSecret); err != nil {
+		return nil, errors.Wrap(err, "invalid client secret")
+	}
+	return account, nil
+}
+
+// AuthenticateServiceAccountAssertion validates a JWT assertion (RFC 7523)
+// signed by the private key of a service account. `audience` is the URL of the
+// token endpoint. The assertion must have an ID, and can't be used again.
+func (s *Auth) AuthenticateServiceAccountAssertion(assertion, audience string, now time.Time) (*models.ServiceAccount, error) {
+	var account *models.ServiceAccount
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(assertion, claims, func(token *jwt.Token) (interface{}, error) {
+		issuer, err := token.Claims.GetIssuer()
+		if err != nil {
+			return nil, err
+		}
+		account, err = s.getEnabledServiceAccount(issuer)
+		if err != nil {
+			return nil, err
+		}
+		if account.PublicKey == "" {
+			return nil, errors.New("service account has no public key")
+		}
+		return ParsePublicKey(account.PublicKey)
+	},
+		jwt.WithValidMethods([]string{"ES256", "ES384", "ES512", "RS256", "RS384", "RS512", "PS256", "EdDSA"}),
+		jwt.WithAudience(audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithTimeFunc(func() time.Time { return now }))
+	if err != nil {
+		return nil, err
+	}
+	if claims.Subject != account.Id {
+		return nil, errors.New("subject must be the client ID")
+	}
+	if claims.ID == "" {
+		return nil, errors.New("assertion has no ID")
+	}
+	issuedAt := now
+	if claims.IssuedAt != nil {
+		issuedAt = claims.IssuedAt.Time
+	}
+	if claims.ExpiresAt.Sub(issuedAt) > MaxServiceAccountAssertionLifetime {
+		return nil, errors.Errorf("assertion must not be valid for more than %v", MaxServiceAccountAssertionLifetime)
+	}
+	if err := s.db.UseServiceAccountAssertion(account.Id, claims.ID, claims.ExpiresAt.Time); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// IssueServiceAccountToken returns an access token that the service account
+// can use with the proxy.
+func (s *Auth) IssueServiceAccountToken(account *models.ServiceAccount, issuer string, now time.Time) (string, time.Duration, error) {
+	claims := &ServiceAccountClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   serviceAccountSubjectPrefix + account.Id,
+			Audience:  jwt.ClaimStrings{ServiceAccountTokenAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(serviceAccountTokenLifetime)),
+			ID:        common.MakeRandomID(),
+		},
+		Scope: ScopeProxy,
+	}
+	token, err := s.SignToken(claims)
+	if err != nil {
+		return "", 0, err
+	}
+
+	err = s.db.UpdateServiceAccount(account.Id, func(old *models.ServiceAccount) (*models.ServiceAccount, error) {
+		if old == nil {
+			return nil, errors.New("service account was deleted")
+		}
+		old.LastUsedAt = timestamppb.New(now)
+		return old, nil
+	})
+	if err != nil {
+		return "", 0, err
+	}
+	return token, serviceAccountTokenLifetime, nil
+}
+
+// IsServiceAccountToken returns true if `value` looks like an access token
+// issued by IssueServiceAccountToken, without validating it. Other bearer
+// tokens may be meant for someone else.
+func IsServiceAccountToken(value, issuer string) bool {
+	claims := &ServiceAccountClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(value, claims); err != nil {
+		return false
+	}
+	for _, audience := range claims.Audience {
+		if audience == ServiceAccountTokenAudience {
+			return claims.Issuer == issuer
+		}
+	}
+	return false
+}
+
+// ValidateServiceAccountToken returns the service account if `value` is a
+// valid access token issued by IssueServiceAccountToken.
+func (s *Auth) ValidateServiceAccountToken(value, issuer string, now time.Time) (*models.ServiceAccount, error) {
+	claims := &ServiceAccountClaims{}
+	err := s.ParseToken(value, claims,
+		jwt.WithIssuer(issuer),
+		jwt.WithAudience(ServiceAccountTokenAudience),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(func() time.Time { return now }))
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(claims.Subject, serviceAccountSubjectPrefix) {
+		return nil, errors.New("not a service account token")
+	}
+	// The account may have been deleted or disabled since the token was
+	// issued.
+	return s.getEnabledServiceAccount(claims.Subject[len(serviceAccountSubjectPrefix):])
+}
@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"boivie/ubergang/server/models"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// A user can have at most this many sign-in requests created within
+	// signinRequestWindow.
+	maxSigninRequests   = 10
+	signinRequestWindow = 10 * time.Minute
+)
+
+var ErrTooManySigninRequests = errors.New("too many sign-in requests")
+var ErrSigninRequestUsed = errors.New("sign-in request has already been used")
+
+// AddSigninRequest adds a sign-in request to a user, unless too many have
+// been created recently. Expired requests are removed at the same time.
+func (s *Auth) AddSigninRequest(userId string, request *models.SigninRequest, now time.Time) error {
+	request.CreatedAt = timestamppb.New(now)
+	return s.db.UpdateUser(userId, func(old *models.User) (*models.User, error) {
+		if old == nil {
+			return nil, errors.New("user not found")
+		}
+		requests := make([]*models.SigninRequest, 0, len(old.SigninRequests)+1)
+		recent := 0
+		for _, r := range old.SigninRequests {
+			if r.ExpiresAt.AsTime().Before(now) {
+				continue
+			}
+			if r.CreatedAt != nil && now.Sub(r.CreatedAt.AsTime()) < signinRequestWindow {
+				recent++
+			}
+			requests = append(requests, r)
+		}
+		if recent >= maxSigninRequests {
+			s.log.Warnf("User %s has too many sign-in requests", userId)
+			return nil, ErrTooManySigninRequests
+		}
+		old.SigninRequests = append(requests, request)
+		return old, nil
+	})
+}
+
+// RemoveSigninRequest removes a sign-in request, e.g. when a one-time
+// request has been used.
+func (s *Auth) RemoveSigninRequest(userId, id string) error {
+	return s.db.UpdateUser(userId, func(old *models.User) (*models.User, error) {
+		if old == nil {
+			return nil, errors.New("user not found")
+		}
+		requests := make([]*models.SigninRequest, 0, len(old.SigninRequests))
+		for _, r := range old.SigninRequests {
+			if r.Id != id {
+				requests = append(requests, r)
+			}
+		}
+		old.SigninRequests = requests
+		return old, nil
+	})
+}
+
+// ConsumeSigninRequest removes a one-time sign-in request that is about to be
+// used. It fails if the request has already been removed, so that it can only
+// be used once even if it's polled concurrently.
+func (s *Auth) ConsumeSigninRequest(userId, id string) error {
+	return s.db.UpdateUser(userId, func(old *models.User) (*models.User, error) {
+		if old == nil {
+			return nil, errors.New("user not found")
+		}
+		for i, r := range old.SigninRequests {
+			if r.Id == id {
+				old.SigninRequests = append(old.SigninRequests[:i], old.SigninRequests[i+1:]...)
+				return old, nil
+			}
+		}
+		return nil, ErrSigninRequestUsed
+	})
+}
@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+)
+
+// SigningKey returns the key that the server uses to sign tokens, creating it
+// on first use.
+func (s *Auth) SigningKey() (*ecdsa.PrivateKey, error) {
+	s.signingKeyMu.Lock()
+	defer s.signingKeyMu.Unlock()
+	if s.signingKey != nil {
+		return s.signingKey, nil
+	}
+
+	data, err := s.db.GetSigningKey()
+	if err == nil {
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, errors.New("invalid signing key")
+		}
+		key, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		s.signingKey = key
+		return key, nil
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	err = s.db.UpdateSigningKey(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		return nil, err
+	}
+	s.log.Infof("Created new token signing key")
+	s.signingKey = key
+	return key, nil
+}
+
+// KeyID returns an identifier of the public part of `key`.
+func KeyID(key *ecdsa.PublicKey) string {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return ""
+	}
+	hash := sha256.Sum256(der)
+	return base64.RawURLEncoding.EncodeToString(hash[:12])
+}
+
+// SignToken signs `claims` using the server's signing key.
+func (s *Auth) SignToken(claims jwt.Claims) (string, error) {
+	key, err := s.SigningKey()
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["kid"] = KeyID(&key.PublicKey)
+	return token.SignedString(key)
+}
+
+// ParseToken verifies a token signed by the server's signing key and parses
+// it into `claims`.
+func (s *Auth) ParseToken(value string, claims jwt.Claims, options ...jwt.ParserOption) error {
+	key, err := s.SigningKey()
+	if err != nil {
+		return err
+	}
+	options = append(options, jwt.WithValidMethods([]string{jwt.SigningMethodES256.Alg()}))
+	_, err = jwt.ParseWithClaims(value, claims, func(token *jwt.Token) (interface{}, error) {
+		return &key.PublicKey, nil
+	}, options...)
+	return err
+}
@@ -71,6 +71,7 @@ func (s *Auth) UpdateSshKey(keyID, keySecret, pubKey string) (*models.SshKey, er
 		old.PublicKey = pubKey
 		old.Sha256Fingerprint = fingerprint[:]
 		old.ConfirmedAt = nil
+		old.ProposedAt = timestamppb.Now()
 		return old, nil
 	})
 }
@@ -0,0 +1,158 @@
+package auth
+
+import (
+	"boivie/ubergang/server/models"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// One-time passwords use the defaults of RFC 6238, as not all authenticator
+// apps support anything else.
+const (
+	totpDigits = 6
+	totpPeriod = 30 * time.Second
+	// The number of time steps that the client's clock may differ by.
+	totpSkew = 1
+	// After this many failed attempts, the credential is blocked for a while.
+	totpMaxFailedAttempts = 5
+	totpBlockDuration     = 5 * time.Minute
+)
+
+var (
+	ErrInvalidTotpCode = errors.New("invalid one-time password")
+	ErrTotpBlocked     = errors.New("too many failed attempts")
+	ErrTotpNotEnrolled = errors.New("no TOTP credential")
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTotpSecret returns a new random secret, of the size recommended by
+// RFC 4226 for HMAC-SHA1.
+func GenerateTotpSecret() []byte {
+	secret := make([]byte, 20)
+	_, _ = rand.Read(secret)
+	return secret
+}
+
+// EncodeTotpSecret returns the secret as it's entered in authenticator apps.
+func EncodeTotpSecret(secret []byte) string {
+	return totpEncoding.EncodeToString(secret)
+}
+
+// TotpUri returns the URI that authenticator apps scan as a QR code. See
+// https://github.com/google/google-authenticator/wiki/Key-Uri-Format
+func TotpUri(issuer, account string, secret []byte) string {
+	q := url.Values{}
+	q.Set("secret", EncodeTotpSecret(secret))
+	q.Set("issuer", issuer)
+	q.Set("algorithm", "SHA1")
+	q.Set("digits", fmt.Sprint(totpDigits))
+	q.Set("period", fmt.Sprint(int(totpPeriod.Seconds())))
+	return "otpauth://totp/" + url.PathEscape(issuer+":"+account) + "?" + q.Encode()
+}
+
+func totpStep(t time.Time) int64 {
+	return t.Unix() / int64(totpPeriod.Seconds())
+}
+
+func totpCodeAt(secret []byte, step int64) string {
+	mac := hmac.New(sha1.New, secret)
+	_ = binary.Write(mac, binary.BigEndian, step)
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%uint32(math.Pow10(totpDigits)))
+}
+
+// TotpCode returns the one-time password for `secret` at time `t`.
+func TotpCode(secret []byte, t time.Time) string {
+	return totpCodeAt(secret, totpStep(t))
+}
+
+// ValidateTotpCode returns the time step that `code` is valid for, if it's
+// valid at `now` and for a later time step than `lastUsedStep`.
+func ValidateTotpCode(secret []byte, code string, lastUsedStep int64, now time.Time) (int64, bool) {
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+	if len(code) != totpDigits {
+		return 0, false
+	}
+	current := totpStep(now)
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if step <= lastUsedStep {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(totpCodeAt(secret, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// FindTotpCredential returns the TOTP credential among `credentials`, if any.
+// A user has at most one.
+func FindTotpCredential(credentials []*models.Credential) *models.Credential {
+	for _, c := range credentials {
+		if c.GetTotpCredential() != nil {
+			return c
+		}
+	}
+	return nil
+}
+
+// VerifyTotp validates a one-time password of a user, and returns the
+// credential that it was valid for. Each code can only be used once, and
+// repeated failures will block the credential for a while.
+func (s *Auth) VerifyTotp(userId, code string, now time.Time) (*models.Credential, error) {
+	credential := FindTotpCredential(s.db.ListCredentials(userId))
+	if credential == nil {
+		return nil, ErrTotpNotEnrolled
+	}
+
+	var result error
+	err := s.db.UpdateCredential(credential.Id, func(old *models.Credential) (*models.Credential, error) {
+		if old == nil || old.GetTotpCredential() == nil {
+			return nil, ErrTotpNotEnrolled
+		}
+		totp := old.GetTotpCredential()
+		if totp.BlockedUntil != nil && now.Before(totp.BlockedUntil.AsTime()) {
+			result = ErrTotpBlocked
+			return old, nil
+		}
+		step, ok := ValidateTotpCode(totp.Secret, code, totp.LastUsedStep, now)
+		if !ok {
+			totp.FailedAttempts++
+			if totp.FailedAttempts >= totpMaxFailedAttempts {
+				s.log.Warnf("Blocking TOTP credential %s after %d failed attempts", old.Id, totp.FailedAttempts)
+				totp.FailedAttempts = 0
+				totp.BlockedUntil = timestamppb.New(now.Add(totpBlockDuration))
+			}
+			result = ErrInvalidTotpCode
+			return old, nil
+		}
+		totp.LastUsedStep = step
+		totp.FailedAttempts = 0
+		totp.BlockedUntil = nil
+		old.LastUsedAt = timestamppb.New(now)
+		credential = old
+		return old, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result != nil {
+		return nil, result
+	}
+	return credential, nil
+}
@@ -21,6 +21,15 @@ type Backend interface {
 	NeedsAuth() bool
 	URL() *url.URL
 	JsScript() *goja.Program
+	// SessionPolicy returns additional restrictions on sessions used to
+	// access this backend, or nil if there are none.
+	SessionPolicy() *models.SessionPolicy
+	// MaxAuthAge returns how recently the user must have signed in with a
+	// passkey to access this backend, or zero if there's no such limit.
+	MaxAuthAge() time.Duration
+	// AllowsBasicAuth returns true if app passwords can be used to access this
+	// backend.
+	AllowsBasicAuth() bool
 }
 
 type BackendManager struct {
@@ -47,6 +56,11 @@ func (b *localBackend) Headers() []*models.Header { return b.backend.Headers }
 func (b *localBackend) NeedsAuth() bool           { return b.backend.AccessLevel != models.AccessLevel_PUBLIC }
 func (b *localBackend) URL() *url.URL             { return b.url }
 func (b *localBackend) JsScript() *goja.Program   { return b.program }
+func (b *localBackend) SessionPolicy() *models.SessionPolicy {
+	return b.backend.SessionPolicy
+}
+func (b *localBackend) MaxAuthAge() time.Duration { return b.backend.MaxAuthAge.AsDuration() }
+func (b *localBackend) AllowsBasicAuth() bool     { return b.backend.AllowBasicAuth }
 
 func (b *localBackend) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
 	newAddress := b.url.Host
@@ -27,6 +27,20 @@ func MakeSigninRequestToken() string {
 	return string(b)
 }
 
+// MakeRandomSecret returns a string generated by a cryptographically secure
+// random number generator, suitable to use as a bearer secret.
+func MakeRandomSecret() string {
+	b := make([]rune, 32)
+	for i := range b {
+		n, err := crand.Int(crand.Reader, big.NewInt(int64(len(letterRunes))))
+		if err != nil {
+			panic(err)
+		}
+		b[i] = letterRunes[n.Int64()]
+	}
+	return string(b)
+}
+
 func MakeSigninRequestPin() (pin string, err error) {
 	pinNum, err := crand.Int(crand.Reader, big.NewInt(1_000_000))
 	if err != nil {
@@ -35,3 +49,21 @@ func MakeSigninRequestPin() (pin string, err error) {
 	pin = fmt.Sprintf("%06d", pinNum)
 	return
 }
+
+// Consonants only, so that the code can't form words, and without letters that
+// are easily confused. See https://www.rfc-editor.org/rfc/rfc8628#section-6.1
+var userCodeRunes = []rune("BCDFGHJKLMNPQRSTVWXZ")
+
+// MakeDeviceUserCode returns a code that the user types in to approve a device
+// authorization request. It has ~34 bits of entropy.
+func MakeDeviceUserCode() (string, error) {
+	b := make([]rune, 8)
+	for i := range b {
+		n, err := crand.Int(crand.Reader, big.NewInt(int64(len(userCodeRunes))))
+		if err != nil {
+			return "", err
+		}
+		b[i] = userCodeRunes[n.Int64()]
+	}
+	return string(b), nil
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"boivie/ubergang/server/models"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Store holds the server configuration, which is shared by all modules and can
+// be changed while the server is running. A configuration is never modified
+// after it's been published, so the one returned by Get can be read without
+// locking.
+type Store struct {
+	current atomic.Pointer[models.Configuration]
+	// Serializes updates, so that no update is lost.
+	mu sync.Mutex
+}
+
+func New(config *models.Configuration) *Store {
+	s := &Store{}
+	s.current.Store(config)
+	return s
+}
+
+// Get returns the current configuration. It must not be modified.
+func (s *Store) Get() *models.Configuration {
+	return s.current.Load()
+}
+
+// Set publishes `config`, which must not be modified afterwards.
+func (s *Store) Set(config *models.Configuration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.current.Store(config)
+}
+
+// Update publishes a copy of the current configuration, modified by
+// `updateFn`.
+func (s *Store) Update(updateFn func(config *models.Configuration)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	config := proto.Clone(s.current.Load()).(*models.Configuration)
+	updateFn(config)
+	s.current.Store(config)
+}
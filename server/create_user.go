@@ -1,8 +1,15 @@
 package server
 
 import (
+	"boivie/ubergang/server/auth"
+	"boivie/ubergang/server/mail"
+	"boivie/ubergang/server/models"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/huh"
 )
@@ -14,7 +21,7 @@ func (s *Server) CreateAccount() {
 	form := huh.NewForm(
 		huh.NewGroup(
 			huh.NewInput().
-				Title("Create User Account").
+				Title("Invite User").
 				Description("Enter the user's email address").
 				Placeholder("user@example.com").
 				Value(&email).
@@ -41,10 +48,44 @@ func (s *Server) CreateAccount() {
 		return
 	}
 
-	user, token, err := s.auth.CreateUser(email, email, admin, nil)
+	s.invite(&models.Invitation{Email: email, IsAdmin: admin})
+}
+
+// ImportAccounts invites the users listed in the CSV or YAML file at `path`.
+func (s *Server) ImportAccounts(path string) {
+	data, err := os.ReadFile(path)
 	if err != nil {
-		log.Fatalf("Error creating user: %v", err)
+		log.Fatalf("Error: %v", err)
 	}
+	invitees, err := auth.ParseInvitees(data, strings.EqualFold(filepath.Ext(path), ".csv"))
+	if err != nil {
+		log.Fatalf("Error parsing %s: %v", path, err)
+	}
+	for _, invitee := range invitees {
+		s.invite(invitee.Invitation())
+	}
+}
 
-	fmt.Printf("Success! %s has been created: https://%s/signin/%s\n", user.Email, s.config.AdminFqdn, token)
+// invite creates an invitation and prints the link to redeem it. It's also
+// e-mailed, if e-mail is configured.
+func (s *Server) invite(invitation *models.Invitation) {
+	config := s.config.Get()
+	token, err := s.auth.CreateInvitation(invitation, auth.InvitationLifetime, time.Now())
+	if err != nil {
+		fmt.Printf("Failed to invite %s: %v\n", invitation.Email, err)
+		return
+	}
+
+	url := fmt.Sprintf("https://%s/invite/%s", config.AdminFqdn, token)
+	fmt.Printf("Success! %s has been invited: %s\n", invitation.Email, url)
+
+	mailer := mail.New(s.log, s.config)
+	if mailer.IsConfigured() {
+		err = mailer.Send(invitation.Email, mail.Invitation(config.AdminFqdn, url, auth.InvitationLifetime))
+		if err != nil {
+			fmt.Printf("Failed to send the invitation by e-mail: %v\n", err)
+		} else {
+			fmt.Printf("The invitation has been sent to %s.\n", invitation.Email)
+		}
+	}
 }
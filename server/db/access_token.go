@@ -0,0 +1,90 @@
+package db
+
+import (
+	"boivie/ubergang/server/models"
+	"bytes"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/protobuf/proto"
+)
+
+func accessTokenKey(id string) []byte {
+	return []byte(fmt.Sprintf("access-token:%s", id))
+}
+
+func userAccessTokenKey(userId, id string) []byte {
+	return []byte(fmt.Sprintf("user-access-token:%s:%s", userId, id))
+}
+
+func (d *DB) GetAccessToken(id string) (ret *models.AccessToken, err error) {
+	err = d.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		v := b.Get(accessTokenKey(id))
+		if v == nil {
+			return fmt.Errorf("failed to find access token")
+		}
+		ret = &models.AccessToken{}
+		return proto.Unmarshal(v, ret)
+	})
+	return
+}
+
+func (d *DB) ListAccessTokens(userId string) (ret []*models.AccessToken) {
+	_ = d.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		c := b.Cursor()
+		prefix := []byte(fmt.Sprintf("user-access-token:%s:", userId))
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			tokenId := string(k[len(prefix):])
+			v := b.Get(accessTokenKey(tokenId))
+			if v != nil {
+				token := &models.AccessToken{}
+				err := proto.Unmarshal(v, token)
+				if err == nil {
+					ret = append(ret, token)
+				}
+			}
+		}
+		return nil
+	})
+	return
+}
+
+func (d *DB) UpdateAccessToken(id string, update_fn func(old *models.AccessToken) (*models.AccessToken, error)) error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		key := accessTokenKey(id)
+		v := b.Get(key)
+		var old_obj *models.AccessToken = nil
+		if v != nil {
+			old_obj = &models.AccessToken{}
+			err := proto.Unmarshal(v, old_obj)
+			if err != nil {
+				return err
+			}
+		}
+		new_obj, err := update_fn(old_obj)
+		if err != nil {
+			return err
+		}
+		if new_obj == nil {
+			// Token is to be deleted.
+			if old_obj != nil {
+				_ = b.Delete(userAccessTokenKey(old_obj.UserId, old_obj.Id))
+				_ = b.Delete(key)
+			}
+			return nil
+		}
+
+		serialized, err := proto.Marshal(new_obj)
+		if err != nil {
+			return err
+		}
+		err = b.Put(userAccessTokenKey(new_obj.UserId, new_obj.Id), []byte{})
+		if err != nil {
+			return err
+		}
+		return b.Put(key, serialized)
+	})
+}
@@ -0,0 +1,90 @@
+package db
+
+import (
+	"boivie/ubergang/server/models"
+	"bytes"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/protobuf/proto"
+)
+
+func appPasswordKey(id string) []byte {
+	return []byte(fmt.Sprintf("app-password:%s", id))
+}
+
+func userAppPasswordKey(userId, id string) []byte {
+	return []byte(fmt.Sprintf("user-app-password:%s:%s", userId, id))
+}
+
+func (d *DB) GetAppPassword(id string) (ret *models.AppPassword, err error) {
+	err = d.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		v := b.Get(appPasswordKey(id))
+		if v == nil {
+			return fmt.Errorf("failed to find app password")
+		}
+		ret = &models.AppPassword{}
+		return proto.Unmarshal(v, ret)
+	})
+	return
+}
+
+func (d *DB) ListAppPasswords(userId string) (ret []*models.AppPassword) {
+	_ = d.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		c := b.Cursor()
+		prefix := []byte(fmt.Sprintf("user-app-password:%s:", userId))
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			appPasswordId := string(k[len(prefix):])
+			v := b.Get(appPasswordKey(appPasswordId))
+			if v != nil {
+				appPassword := &models.AppPassword{}
+				err := proto.Unmarshal(v, appPassword)
+				if err == nil {
+					ret = append(ret, appPassword)
+				}
+			}
+		}
+		return nil
+	})
+	return
+}
+
+func (d *DB) UpdateAppPassword(id string, update_fn func(old *models.AppPassword) (*models.AppPassword, error)) error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		key := appPasswordKey(id)
+		v := b.Get(key)
+		var old_obj *models.AppPassword = nil
+		if v != nil {
+			old_obj = &models.AppPassword{}
+			err := proto.Unmarshal(v, old_obj)
+			if err != nil {
+				return err
+			}
+		}
+		new_obj, err := update_fn(old_obj)
+		if err != nil {
+			return err
+		}
+		if new_obj == nil {
+			// App password is to be deleted.
+			if old_obj != nil {
+				_ = b.Delete(userAppPasswordKey(old_obj.UserId, old_obj.Id))
+				_ = b.Delete(key)
+			}
+			return nil
+		}
+
+		serialized, err := proto.Marshal(new_obj)
+		if err != nil {
+			return err
+		}
+		err = b.Put(userAppPasswordKey(new_obj.UserId, new_obj.Id), []byte{})
+		if err != nil {
+			return err
+		}
+		return b.Put(key, serialized)
+	})
+}
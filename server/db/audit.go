@@ -0,0 +1,59 @@
+package db
+
+import (
+	"boivie/ubergang/server/models"
+	"bytes"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/protobuf/proto"
+)
+
+// Audit records are keyed by time, so that they are listed in order.
+func auditKey(record *models.AuditRecord) []byte {
+	return []byte(fmt.Sprintf("audit:%020d:%s", record.Timestamp.AsTime().UnixNano(), record.Id))
+}
+
+// AppendAuditRecord stores a new audit record. Records can't be changed once
+// they have been stored.
+func (d *DB) AppendAuditRecord(record *models.AuditRecord) error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		key := auditKey(record)
+		if b.Get(key) != nil {
+			return fmt.Errorf("audit record already exists")
+		}
+		serialized, err := proto.Marshal(record)
+		if err != nil {
+			return err
+		}
+		return b.Put(key, serialized)
+	})
+}
+
+// ListAuditRecords returns the records that `filter` accepts, newest first.
+// At most `limit` records are returned.
+func (d *DB) ListAuditRecords(filter func(record *models.AuditRecord) bool, limit int) (ret []*models.AuditRecord) {
+	_ = d.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(BucketName).Cursor()
+		prefix := []byte("audit:")
+		// Start after the last record, i.e. at the first key after the prefix.
+		k, v := c.Seek([]byte("audit;"))
+		if k == nil {
+			k, v = c.Last()
+		} else {
+			k, v = c.Prev()
+		}
+		for ; k != nil && bytes.HasPrefix(k, prefix) && len(ret) < limit; k, v = c.Prev() {
+			record := &models.AuditRecord{}
+			if err := proto.Unmarshal(v, record); err != nil {
+				continue
+			}
+			if filter(record) {
+				ret = append(ret, record)
+			}
+		}
+		return nil
+	})
+	return
+}
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"boivie/ubergang/server/models"
+	"bytes"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/protobuf/proto"
+)
+
+func banKey(ip string) []byte {
+	return []byte(fmt.Sprintf("ban:%s", ip))
+}
+
+func (d *DB) GetBan(ip string) (ret *models.Ban, err error) {
+	err = d.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		v := b.Get(banKey(ip))
+		if v == nil {
+			return fmt.Errorf("failed to find ban")
+		}
+		ret = &models.Ban{}
+		return proto.Unmarshal(v, ret)
+	})
+	return
+}
+
+func (d *DB) ListBans() (ret []*models.Ban) {
+	_ = d.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(BucketName).Cursor()
+		prefix := []byte("ban:")
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			ban := &models.Ban{}
+			if err := proto.Unmarshal(v, ban); err == nil {
+				ret = append(ret, ban)
+			}
+		}
+		return nil
+	})
+	return
+}
+
+func (d *DB) UpdateBan(ip string, update_fn func(old *models.Ban) (*models.Ban, error)) error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		key := banKey(ip)
+		v := b.Get(key)
+		var old_obj *models.Ban = nil
+		if v != nil {
+			old_obj = &models.Ban{}
+			err := proto.Unmarshal(v, old_obj)
+			if err != nil {
+				return err
+			}
+		}
+		new_obj, err := update_fn(old_obj)
+		if err != nil {
+			return err
+		}
+		if new_obj == nil {
+			return b.Delete(key)
+		}
+
+		serialized, err := proto.Marshal(new_obj)
+		if err != nil {
+			return err
+		}
+		return b.Put(key, serialized)
+	})
+}
@@ -36,11 +36,15 @@ func New(log *log.Log, dbFile string) (*DB, error) {
 		return nil, err
 	}
 	err = db.Update(func(tx *bolt.Tx) error {
-		_, err := tx.CreateBucketIfNotExists(BucketName)
+		if tx.Bucket(BucketName) != nil {
+			return nil
+		}
+		// New databases start out at the latest schema version.
+		b, err := tx.CreateBucket(BucketName)
 		if err != nil {
 			return err
 		}
-		return nil
+		return putSchemaVersion(b, SchemaVersion)
 	})
 	if err != nil {
 		return nil, err
@@ -58,6 +62,10 @@ func sshServertKeyKey() []byte {
 	return []byte("ssh-server-key")
 }
 
+func signingKeyKey() []byte {
+	return []byte("signing-key")
+}
+
 func selfSignedCertKey() []byte {
 	return []byte("self-signed-cert")
 }
@@ -107,6 +115,10 @@ func signinTokenKey(token string) []byte {
 	return []byte(fmt.Sprintf("signin:%s", token))
 }
 
+func federatedIdentityKey(providerId, subject string) []byte {
+	return []byte(fmt.Sprintf("fed-identity:%s:%s", providerId, subject))
+}
+
 func authenticationStateKey(stateId *uuid.UUID) []byte {
 	return []byte(fmt.Sprintf("auth-state:%s", stateId.String()))
 }
@@ -154,6 +166,27 @@ func (d *DB) UpdateSshServerKey(data []byte) error {
 	})
 }
 
+func (d *DB) GetSigningKey() (key []byte, err error) {
+	err = d.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		v := b.Get(signingKeyKey())
+		if v == nil {
+			return fmt.Errorf("failed to find key")
+		}
+		key = append([]byte{}, v...)
+		return nil
+	})
+
+	return
+}
+
+func (d *DB) UpdateSigningKey(data []byte) error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		return b.Put(signingKeyKey(), data)
+	})
+}
+
 func (d *DB) GetSelfSignedCert() (cert []byte, key []byte, err error) {
 	err = d.db.View(func(tx *bolt.Tx) error {
 		b := tx.Bucket(BucketName)
@@ -340,25 +373,32 @@ func (d *DB) DeleteBackend(fqdn string) error {
 }
 
 func (d *DB) DeleteUser(userId string) error {
-	return d.db.Update(func(tx *bolt.Tx) error {
-		b := tx.Bucket(BucketName)
-		key := userKey(userId)
+	return d.Update(func(tx *Tx) error {
+		return tx.DeleteUser(userId)
+	})
+}
 
-		v := b.Get(key)
-		if v == nil {
-			return errors.New("user not found")
-		}
+func (tx *Tx) DeleteUser(userId string) error {
+	b := tx.b
+	key := userKey(userId)
 
-		user := &models.User{}
-		if err := proto.Unmarshal(v, user); err == nil {
-			if user.Email != "" {
-				_ = b.Delete(emailKey(user.Email))
-			}
+	v := b.Get(key)
+	if v == nil {
+		return errors.New("user not found")
+	}
+
+	user := &models.User{}
+	if err := proto.Unmarshal(v, user); err == nil {
+		if user.Email != "" {
+			_ = b.Delete(emailKey(user.Email))
+		}
+		for _, identity := range user.FederatedIdentities {
+			_ = b.Delete(federatedIdentityKey(identity.ProviderId, identity.Subject))
 		}
-		// TODO: Delete associated objects like credentials, sessions etc
+	}
+	// TODO: Delete associated objects like credentials, sessions etc
 
-		return b.Delete(key)
-	})
+	return b.Delete(key)
 }
 
 func (d *DB) DeleteSession(sessionId string) error {
@@ -384,6 +424,35 @@ func (d *DB) DeleteSession(sessionId string) error {
 	})
 }
 
+// DeleteSessionsIf deletes all sessions for which `predicate` returns true, and
+// returns the number of deleted sessions.
+func (d *DB) DeleteSessionsIf(predicate func(session *models.Session) bool) (count int, err error) {
+	err = d.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		var toDelete []*models.Session
+		c := b.Cursor()
+		prefix := []byte("sess:")
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			session := &models.Session{}
+			if err := proto.Unmarshal(v, session); err != nil {
+				continue
+			}
+			if predicate(session) {
+				toDelete = append(toDelete, session)
+			}
+		}
+		for _, session := range toDelete {
+			if err := b.Delete(sessionKey(session.Id)); err != nil {
+				return err
+			}
+			_ = b.Delete([]byte(fmt.Sprintf("user-sess:%s:%s", session.UserId, session.Id)))
+		}
+		count = len(toDelete)
+		return nil
+	})
+	return
+}
+
 func (d *DB) UpdateSession(sessionId string, update_fn func(old *models.Session) (*models.Session, error)) error {
 	return d.db.Update(func(tx *bolt.Tx) error {
 		b := tx.Bucket(BucketName)
@@ -413,6 +482,37 @@ func (d *DB) UpdateSession(sessionId string, update_fn func(old *models.Session)
 	})
 }
 
+// UpdateSessions updates multiple sessions in a single transaction. Sessions
+// that don't exist are skipped.
+func (d *DB) UpdateSessions(sessionIds []string, update_fn func(old *models.Session) (*models.Session, error)) error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		for _, sessionId := range sessionIds {
+			key := sessionKey(sessionId)
+			v := b.Get(key)
+			if v == nil {
+				continue
+			}
+			old_obj := &models.Session{}
+			if err := proto.Unmarshal(v, old_obj); err != nil {
+				return err
+			}
+			new_obj, err := update_fn(old_obj)
+			if err != nil {
+				return err
+			}
+			serialized, err := proto.Marshal(new_obj)
+			if err != nil {
+				return err
+			}
+			if err := b.Put(key, serialized); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (d *DB) GetConfiguration() (ret *models.Configuration, err error) {
 	err = d.db.View(func(tx *bolt.Tx) error {
 		b := tx.Bucket(BucketName)
@@ -499,63 +599,86 @@ func diff(set1, set2 map[string]bool) map[string]bool {
 }
 
 func (d *DB) UpdateUser(userId string, update_fn func(old *models.User) (*models.User, error)) error {
-	return d.db.Update(func(tx *bolt.Tx) error {
-		b := tx.Bucket(BucketName)
-		key := userKey(userId)
-		v := b.Get(key)
-		var old_obj *models.User = nil
-		oldEmail := ""
-		oldTokens := make(map[string]bool)
-		newTokens := make(map[string]bool)
-		if v != nil {
-			old_obj = &models.User{}
-			err := proto.Unmarshal(v, old_obj)
-			if err != nil {
-				return err
-			}
-			oldEmail = old_obj.Email
-			for _, s := range old_obj.SigninRequests {
-				oldTokens[s.Id] = true
-			}
-		}
-		new_obj, err := update_fn(old_obj)
+	return d.Update(func(tx *Tx) error {
+		return tx.UpdateUser(userId, update_fn)
+	})
+}
+
+func (tx *Tx) UpdateUser(userId string, update_fn func(old *models.User) (*models.User, error)) error {
+	b := tx.b
+	key := userKey(userId)
+	v := b.Get(key)
+	var old_obj *models.User = nil
+	oldEmail := ""
+	oldTokens := make(map[string]bool)
+	newTokens := make(map[string]bool)
+	oldIdentities := make(map[string]bool)
+	newIdentities := make(map[string]bool)
+	if v != nil {
+		old_obj = &models.User{}
+		err := proto.Unmarshal(v, old_obj)
 		if err != nil {
 			return err
 		}
-		for _, s := range new_obj.SigninRequests {
-			newTokens[s.Id] = true
+		oldEmail = old_obj.Email
+		for _, s := range old_obj.SigninRequests {
+			oldTokens[s.Id] = true
 		}
-		// Validate that there isn't already a user with this e-mail address.
-		if new_obj.Email != "" {
-			existingUserId := b.Get(emailKey(new_obj.Email))
-			if existingUserId != nil && string(existingUserId) != userId {
-				return fmt.Errorf("e-mail address already mapped to another user: %s", string(existingUserId))
-			}
+		for _, identity := range old_obj.FederatedIdentities {
+			oldIdentities[string(federatedIdentityKey(identity.ProviderId, identity.Subject))] = true
 		}
-		if oldEmail != new_obj.Email {
-			if oldEmail != "" {
-				_ = b.Delete(emailKey(oldEmail))
-			}
-			if new_obj.Email != "" {
-				if err := b.Put(emailKey(new_obj.Email), []byte(userId)); err != nil {
-					return err
-				}
-			}
+	}
+	new_obj, err := update_fn(old_obj)
+	if err != nil {
+		return err
+	}
+	for _, s := range new_obj.SigninRequests {
+		newTokens[s.Id] = true
+	}
+	for _, identity := range new_obj.FederatedIdentities {
+		newIdentities[string(federatedIdentityKey(identity.ProviderId, identity.Subject))] = true
+	}
+	// Validate that there isn't already a user with this e-mail address.
+	if new_obj.Email != "" {
+		existingUserId := b.Get(emailKey(new_obj.Email))
+		if existingUserId != nil && string(existingUserId) != userId {
+			return fmt.Errorf("e-mail address already mapped to another user: %s", string(existingUserId))
 		}
-		for token := range diff(oldTokens, newTokens) {
-			_ = b.Delete(signinTokenKey(token))
+	}
+	if oldEmail != new_obj.Email {
+		if oldEmail != "" {
+			_ = b.Delete(emailKey(oldEmail))
 		}
-		for token := range diff(newTokens, oldTokens) {
-			if err := b.Put(signinTokenKey(token), []byte(userId)); err != nil {
+		if new_obj.Email != "" {
+			if err := b.Put(emailKey(new_obj.Email), []byte(userId)); err != nil {
 				return err
 			}
 		}
-		serialized, err := proto.Marshal(new_obj)
-		if err != nil {
+	}
+	for token := range diff(oldTokens, newTokens) {
+		_ = b.Delete(signinTokenKey(token))
+	}
+	for token := range diff(newTokens, oldTokens) {
+		if err := b.Put(signinTokenKey(token), []byte(userId)); err != nil {
 			return err
 		}
-		return b.Put(key, serialized)
-	})
+	}
+	for key := range diff(oldIdentities, newIdentities) {
+		_ = b.Delete([]byte(key))
+	}
+	for key := range diff(newIdentities, oldIdentities) {
+		if existingUserId := b.Get([]byte(key)); existingUserId != nil && string(existingUserId) != userId {
+			return fmt.Errorf("external identity already linked to another user: %s", string(existingUserId))
+		}
+		if err := b.Put([]byte(key), []byte(userId)); err != nil {
+			return err
+		}
+	}
+	serialized, err := proto.Marshal(new_obj)
+	if err != nil {
+		return err
+	}
+	return b.Put(key, serialized)
 }
 
 func (d *DB) GetCredential(id string) (ret *models.Credential, err error) {
@@ -588,6 +711,23 @@ func (d *DB) GetUserBySigninRequest(token string) (ret *models.User, err error)
 	return
 }
 
+func (d *DB) GetUserByFederatedIdentity(providerId, subject string) (ret *models.User, err error) {
+	err = d.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		v := b.Get(federatedIdentityKey(providerId, subject))
+		if v == nil {
+			return fmt.Errorf("failed to find external identity")
+		}
+		v = b.Get(userKey(string(v)))
+		if v == nil {
+			return fmt.Errorf("failed to find user")
+		}
+		ret = &models.User{}
+		return proto.Unmarshal(v, ret)
+	})
+	return
+}
+
 func (d *DB) ListCredentials(userId string) (ret []*models.Credential) {
 	_ = d.db.View(func(tx *bolt.Tx) error {
 		b := tx.Bucket(BucketName)
@@ -648,40 +788,44 @@ func (d *DB) UpdateCredential(credentialId string, update_fn func(old *models.Cr
 }
 
 func (d *DB) UpdateBackend(fqdn string, update_fn func(old *models.Backend) (*models.Backend, error)) error {
+	return d.Update(func(tx *Tx) error {
+		return tx.UpdateBackend(fqdn, update_fn)
+	})
+}
+
+func (tx *Tx) UpdateBackend(fqdn string, update_fn func(old *models.Backend) (*models.Backend, error)) error {
 	fqdn = normalizeFqdn(fqdn)
-	return d.db.Update(func(tx *bolt.Tx) error {
-		b := tx.Bucket(BucketName)
-		key := backendKey(fqdn)
-		v := b.Get(key)
-		var old_obj *models.Backend = nil
-		if v != nil {
-			old_obj = &models.Backend{}
-			err := proto.Unmarshal(v, old_obj)
-			if err != nil {
-				return err
-			}
-		}
-		new_obj, err := update_fn(old_obj)
+	b := tx.b
+	key := backendKey(fqdn)
+	v := b.Get(key)
+	var old_obj *models.Backend = nil
+	if v != nil {
+		old_obj = &models.Backend{}
+		err := proto.Unmarshal(v, old_obj)
 		if err != nil {
 			return err
 		}
-		if new_obj == nil {
-			// Backend is to be deleted.
-			if old_obj != nil {
-				_ = b.Delete(key)
-			}
-			return nil
-		}
-		if old_obj != nil && old_obj.Fqdn != new_obj.Fqdn {
-			return fmt.Errorf("changing FQDN is currently not supported")
+	}
+	new_obj, err := update_fn(old_obj)
+	if err != nil {
+		return err
+	}
+	if new_obj == nil {
+		// Backend is to be deleted.
+		if old_obj != nil {
+			_ = b.Delete(key)
 		}
+		return nil
+	}
+	if old_obj != nil && old_obj.Fqdn != new_obj.Fqdn {
+		return fmt.Errorf("changing FQDN is currently not supported")
+	}
 
-		serialized, err := proto.Marshal(new_obj)
-		if err != nil {
-			return err
-		}
-		return b.Put(key, serialized)
-	})
+	serialized, err := proto.Marshal(new_obj)
+	if err != nil {
+		return err
+	}
+	return b.Put(key, serialized)
 }
 
 func (d *DB) ListSshKeys(userId string) (ret []*models.SshKey) {
@@ -828,6 +972,8 @@ func (d *DB) ClearDatabase() error {
 				// The configruation is required.
 			} else if key == "ssh-server-key" {
 				// Keep the SSH server key.
+			} else if key == "schema-version" {
+				// The remaining data is still of this version.
 			} else {
 				keysToDelete = append(keysToDelete, k)
 			}
@@ -910,43 +1056,47 @@ func (d *DB) GetMqttProfile(id string) (ret *models.MqttProfile, err error) {
 }
 
 func (d *DB) UpdateMqttProfile(id string, update_fn func(old *models.MqttProfile) (*models.MqttProfile, error)) error {
-	return d.db.Update(func(tx *bolt.Tx) error {
-		b := tx.Bucket(BucketName)
-		key := mqttProfileKey(id)
-		v := b.Get(key)
-		var old_obj *models.MqttProfile = nil
-		if v != nil {
-			old_obj = &models.MqttProfile{}
-			err := proto.Unmarshal(v, old_obj)
-			if err != nil {
-				return err
-			}
-		}
-		new_obj, err := update_fn(old_obj)
+	return d.Update(func(tx *Tx) error {
+		return tx.UpdateMqttProfile(id, update_fn)
+	})
+}
+
+func (tx *Tx) UpdateMqttProfile(id string, update_fn func(old *models.MqttProfile) (*models.MqttProfile, error)) error {
+	b := tx.b
+	key := mqttProfileKey(id)
+	v := b.Get(key)
+	var old_obj *models.MqttProfile = nil
+	if v != nil {
+		old_obj = &models.MqttProfile{}
+		err := proto.Unmarshal(v, old_obj)
 		if err != nil {
 			return err
 		}
-		if new_obj == nil {
-			// Make sure that no clients refer to this profile
-			c := b.Cursor()
-			prefix := []byte("mqtt-client:")
-			for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
-				p := &models.MqttClient{}
-				err := proto.Unmarshal(v, p)
-				if err == nil {
-					if p.ProfileId == id {
-						return fmt.Errorf("cannot delete MQTT profile %s, it is in use by client %s", id, p.Id)
-					}
+	}
+	new_obj, err := update_fn(old_obj)
+	if err != nil {
+		return err
+	}
+	if new_obj == nil {
+		// Make sure that no clients refer to this profile
+		c := b.Cursor()
+		prefix := []byte("mqtt-client:")
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			p := &models.MqttClient{}
+			err := proto.Unmarshal(v, p)
+			if err == nil {
+				if p.ProfileId == id {
+					return fmt.Errorf("cannot delete MQTT profile %s, it is in use by client %s", id, p.Id)
 				}
 			}
-			return b.Delete(key)
 		}
-		serialized, err := proto.Marshal(new_obj)
-		if err != nil {
-			return err
-		}
-		return b.Put(key, serialized)
-	})
+		return b.Delete(key)
+	}
+	serialized, err := proto.Marshal(new_obj)
+	if err != nil {
+		return err
+	}
+	return b.Put(key, serialized)
 }
 
 func (d *DB) ListMqttProfiles() (ret []*models.MqttProfile) {
@@ -979,43 +1129,47 @@ func (d *DB) GetMqttClient(id string) (ret *models.MqttClient, err error) {
 }
 
 func (d *DB) UpdateMqttClient(id string, update_fn func(old *models.MqttClient) (*models.MqttClient, error)) error {
-	return d.db.Update(func(tx *bolt.Tx) error {
-		b := tx.Bucket(BucketName)
-		key := mqttClientKey(id)
-		v := b.Get(key)
-		var old_obj *models.MqttClient = nil
-		if v != nil {
-			old_obj = &models.MqttClient{}
-			err := proto.Unmarshal(v, old_obj)
-			if err != nil {
-				return err
-			}
-		}
-		new_obj, err := update_fn(old_obj)
+	return d.Update(func(tx *Tx) error {
+		return tx.UpdateMqttClient(id, update_fn)
+	})
+}
+
+func (tx *Tx) UpdateMqttClient(id string, update_fn func(old *models.MqttClient) (*models.MqttClient, error)) error {
+	b := tx.b
+	key := mqttClientKey(id)
+	v := b.Get(key)
+	var old_obj *models.MqttClient = nil
+	if v != nil {
+		old_obj = &models.MqttClient{}
+		err := proto.Unmarshal(v, old_obj)
 		if err != nil {
 			return err
 		}
-		if new_obj == nil {
-			return b.Delete(key)
-		}
-		// Validate that the profile exists.
-		v = b.Get(mqttProfileKey(new_obj.ProfileId))
-		if v == nil {
-			return fmt.Errorf("profile not found")
-		}
-		serialized, err := proto.Marshal(new_obj)
+	}
+	new_obj, err := update_fn(old_obj)
+	if err != nil {
+		return err
+	}
+	if new_obj == nil {
+		return b.Delete(key)
+	}
+	// Validate that the profile exists.
+	v = b.Get(mqttProfileKey(new_obj.ProfileId))
+	if v == nil {
+		return fmt.Errorf("profile not found")
+	}
+	serialized, err := proto.Marshal(new_obj)
+	if err != nil {
+		return err
+	}
+	// If ID changed, delete the old key
+	if new_obj.Id != id {
+		err = b.Delete(key)
 		if err != nil {
 			return err
 		}
-		// If ID changed, delete the old key
-		if new_obj.Id != id {
-			err = b.Delete(key)
-			if err != nil {
-				return err
-			}
-		}
-		return b.Put(mqttClientKey(new_obj.Id), serialized)
-	})
+	}
+	return b.Put(mqttClientKey(new_obj.Id), serialized)
 }
 
 func (d *DB) ListMqttClients() (ret []*models.MqttClient) {
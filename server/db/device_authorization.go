@@ -0,0 +1,95 @@
+package db
+
+import (
+	"boivie/ubergang/server/models"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/protobuf/proto"
+)
+
+func deviceAuthorizationKey(id string) []byte {
+	return []byte(fmt.Sprintf("device-auth:%s", id))
+}
+
+func deviceUserCodeKey(userCode string) []byte {
+	return []byte(fmt.Sprintf("device-user-code:%s", userCode))
+}
+
+func (d *DB) GetDeviceAuthorization(id string) (ret *models.DeviceAuthorization, err error) {
+	err = d.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		v := b.Get(deviceAuthorizationKey(id))
+		if v == nil {
+			return fmt.Errorf("failed to find device authorization")
+		}
+		ret = &models.DeviceAuthorization{}
+		return proto.Unmarshal(v, ret)
+	})
+	return
+}
+
+func (d *DB) GetDeviceAuthorizationByUserCode(userCode string) (ret *models.DeviceAuthorization, err error) {
+	err = d.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		id := b.Get(deviceUserCodeKey(userCode))
+		if id == nil {
+			return fmt.Errorf("failed to find device authorization")
+		}
+		v := b.Get(deviceAuthorizationKey(string(id)))
+		if v == nil {
+			return fmt.Errorf("failed to find device authorization")
+		}
+		ret = &models.DeviceAuthorization{}
+		return proto.Unmarshal(v, ret)
+	})
+	return
+}
+
+func (d *DB) UpdateDeviceAuthorization(id string, update_fn func(old *models.DeviceAuthorization) (*models.DeviceAuthorization, error)) error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		key := deviceAuthorizationKey(id)
+		v := b.Get(key)
+		var old_obj *models.DeviceAuthorization = nil
+		if v != nil {
+			old_obj = &models.DeviceAuthorization{}
+			err := proto.Unmarshal(v, old_obj)
+			if err != nil {
+				return err
+			}
+		}
+		new_obj, err := update_fn(old_obj)
+		if err != nil {
+			return err
+		}
+		if new_obj == nil {
+			// Authorization is to be deleted.
+			if old_obj != nil {
+				_ = b.Delete(deviceUserCodeKey(old_obj.UserCode))
+				_ = b.Delete(key)
+			}
+			return nil
+		}
+		if new_obj.Id != id {
+			return fmt.Errorf("changing ID is not supported")
+		}
+		if old_obj == nil || old_obj.UserCode != new_obj.UserCode {
+			if existing := b.Get(deviceUserCodeKey(new_obj.UserCode)); existing != nil {
+				return fmt.Errorf("user code already in use")
+			}
+			if old_obj != nil {
+				_ = b.Delete(deviceUserCodeKey(old_obj.UserCode))
+			}
+			if err := b.Put(deviceUserCodeKey(new_obj.UserCode), []byte(id)); err != nil {
+				return err
+			}
+		}
+
+		serialized, err := proto.Marshal(new_obj)
+		if err != nil {
+			return err
+		}
+		return b.Put(key, serialized)
+	})
+}
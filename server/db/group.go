@@ -0,0 +1,79 @@
+package db
+
+import (
+	"boivie/ubergang/server/models"
+	"bytes"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/protobuf/proto"
+)
+
+func groupKey(id string) []byte {
+	return []byte(fmt.Sprintf("group:%s", id))
+}
+
+func (d *DB) GetGroup(id string) (ret *models.Group, err error) {
+	err = d.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		v := b.Get(groupKey(id))
+		if v == nil {
+			return fmt.Errorf("failed to find group")
+		}
+		ret = &models.Group{}
+		return proto.Unmarshal(v, ret)
+	})
+	return
+}
+
+func (d *DB) ListGroups() (ret []*models.Group) {
+	_ = d.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(BucketName).Cursor()
+		prefix := []byte("group:")
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			p := &models.Group{}
+			err := proto.Unmarshal(v, p)
+			if err == nil {
+				ret = append(ret, p)
+			}
+		}
+		return nil
+	})
+	return
+}
+
+func (d *DB) UpdateGroup(id string, update_fn func(old *models.Group) (*models.Group, error)) error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		key := groupKey(id)
+		v := b.Get(key)
+		var old_obj *models.Group = nil
+		if v != nil {
+			old_obj = &models.Group{}
+			err := proto.Unmarshal(v, old_obj)
+			if err != nil {
+				return err
+			}
+		}
+		new_obj, err := update_fn(old_obj)
+		if err != nil {
+			return err
+		}
+		if new_obj == nil {
+			// Group is to be deleted.
+			if old_obj != nil {
+				_ = b.Delete(key)
+			}
+			return nil
+		}
+		if new_obj.Id != id {
+			return fmt.Errorf("changing ID is not supported")
+		}
+
+		serialized, err := proto.Marshal(new_obj)
+		if err != nil {
+			return err
+		}
+		return b.Put(key, serialized)
+	})
+}
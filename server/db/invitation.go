@@ -0,0 +1,71 @@
+package db
+
+import (
+	"boivie/ubergang/server/models"
+	"bytes"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/protobuf/proto"
+)
+
+func invitationKey(id string) []byte {
+	return []byte(fmt.Sprintf("invitation:%s", id))
+}
+
+func (d *DB) GetInvitation(id string) (ret *models.Invitation, err error) {
+	err = d.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		v := b.Get(invitationKey(id))
+		if v == nil {
+			return fmt.Errorf("failed to find invitation")
+		}
+		ret = &models.Invitation{}
+		return proto.Unmarshal(v, ret)
+	})
+	return
+}
+
+func (d *DB) ListInvitations() (ret []*models.Invitation) {
+	_ = d.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(BucketName).Cursor()
+		prefix := []byte("invitation:")
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			invitation := &models.Invitation{}
+			if err := proto.Unmarshal(v, invitation); err == nil {
+				ret = append(ret, invitation)
+			}
+		}
+		return nil
+	})
+	return
+}
+
+func (d *DB) UpdateInvitation(id string, update_fn func(old *models.Invitation) (*models.Invitation, error)) error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		key := invitationKey(id)
+		v := b.Get(key)
+		var old_obj *models.Invitation = nil
+		if v != nil {
+			old_obj = &models.Invitation{}
+			err := proto.Unmarshal(v, old_obj)
+			if err != nil {
+				return err
+			}
+		}
+		new_obj, err := update_fn(old_obj)
+		if err != nil {
+			return err
+		}
+		if new_obj == nil {
+			return b.Delete(key)
+		}
+
+		serialized, err := proto.Marshal(new_obj)
+		if err != nil {
+			return err
+		}
+		return b.Put(key, serialized)
+	})
+}
@@ -0,0 +1,269 @@
+package db
+
+import (
+	"boivie/ubergang/server/models"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// Authentication states are short-lived. The ones older than this are
+	// removed without looking at them.
+	MaxAuthenticationStateAge = 24 * time.Hour
+	// How long a SSH key may wait for its public key to be confirmed.
+	UnconfirmedSshKeyLifetime = 7 * 24 * time.Hour
+	// How long invitations are kept after they were redeemed, revoked or
+	// expired.
+	InvitationRetention = 30 * 24 * time.Hour
+)
+
+var purgedMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "ubergang_janitor_purged_total",
+	Help: "The total number of expired or stale entries removed from the database",
+}, []string{"kind"})
+
+// Purged is what the janitor removed in one run.
+type Purged struct {
+	AuthenticationStates int
+	SigninRequests       int
+	Sessions             int
+	SshKeys              int
+	Invitations          int
+	Assertions           int
+}
+
+// authenticationStateBound returns the key of an authentication state created
+// at `t`, which sorts before all states created after it.
+func authenticationStateBound(t time.Time) []byte {
+	var id uuid.UUID
+	var ms [8]byte
+	binary.BigEndian.PutUint64(ms[:], uint64(t.UnixMilli()))
+	copy(id[:6], ms[2:])
+	id[6] = 0x70 // Version 7.
+	id[8] = 0x80 // Variant.
+	return authenticationStateKey(&id)
+}
+
+// PurgeAuthenticationStates removes the authentication states that have
+// expired. As the state IDs are UUIDv7, they are sorted by creation time and
+// only the states created before `now` need to be looked at.
+func (d *DB) PurgeAuthenticationStates(now time.Time) (count int, err error) {
+	err = d.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		c := b.Cursor()
+		old := authenticationStateBound(now.Add(-MaxAuthenticationStateAge))
+		end := authenticationStateBound(now)
+		var toDelete [][]byte
+		for k, v := c.Seek([]byte("auth-state:")); k != nil && bytes.Compare(k, end) < 0; k, v = c.Next() {
+			if bytes.Compare(k, old) >= 0 {
+				state := &models.AuthenticationState{}
+				if err := proto.Unmarshal(v, state); err == nil && state.ExpiresAt != nil && now.Before(state.ExpiresAt.AsTime()) {
+					continue
+				}
+			}
+			toDelete = append(toDelete, bytes.Clone(k))
+		}
+		for _, k := range toDelete {
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+		}
+		count = len(toDelete)
+		return nil
+	})
+	return
+}
+
+// PurgeSigninRequests removes the expired sign-in requests of all users.
+func (d *DB) PurgeSigninRequests(now time.Time) (count int, err error) {
+	for _, user := range d.ListUsers() {
+		if !slices.ContainsFunc(user.SigninRequests, func(r *models.SigninRequest) bool {
+			return r.ExpiresAt.AsTime().Before(now)
+		}) {
+			continue
+		}
+		err = d.UpdateUser(user.Id, func(old *models.User) (*models.User, error) {
+			if old == nil {
+				return nil, fmt.Errorf("user %s not found", user.Id)
+			}
+			requests := make([]*models.SigninRequest, 0, len(old.SigninRequests))
+			for _, r := range old.SigninRequests {
+				if r.ExpiresAt.AsTime().Before(now) {
+					count++
+				} else {
+					requests = append(requests, r)
+				}
+			}
+			old.SigninRequests = requests
+			return old, nil
+		})
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// isUnconfirmedSshKey returns true if the public key of `key` hasn't been
+// confirmed within UnconfirmedSshKeyLifetime.
+func isUnconfirmedSshKey(key *models.SshKey, now time.Time) bool {
+	if key.ConfirmedAt != nil {
+		return false
+	}
+	since := key.CreatedAt
+	if key.ProposedAt != nil {
+		since = key.ProposedAt
+	}
+	return since != nil && now.Sub(since.AsTime()) > UnconfirmedSshKeyLifetime
+}
+
+// PurgeUnconfirmedSshKeys removes the SSH keys that were never confirmed.
+func (d *DB) PurgeUnconfirmedSshKeys(now time.Time) (count int, err error) {
+	err = d.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		var toDelete []*models.SshKey
+		c := b.Cursor()
+		prefix := []byte("ssh-key:")
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			key := &models.SshKey{}
+			if err := proto.Unmarshal(v, key); err != nil {
+				continue
+			}
+			if isUnconfirmedSshKey(key, now) {
+				toDelete = append(toDelete, key)
+			}
+		}
+		for _, key := range toDelete {
+			if err := b.Delete(sshKeyKey(key.Id)); err != nil {
+				return err
+			}
+			_ = b.Delete([]byte(fmt.Sprintf("user-ssh-key:%s:%s", key.UserId, key.Id)))
+			if key.Sha256Fingerprint != nil {
+				_ = b.Delete(sshFingerprintKey(key.Sha256Fingerprint))
+			}
+		}
+		count = len(toDelete)
+		return nil
+	})
+	return
+}
+
+// invitationEndedAt returns when `invitation` could no longer be used.
+func invitationEndedAt(invitation *models.Invitation) time.Time {
+	switch {
+	case invitation.RedeemedAt != nil:
+		return invitation.RedeemedAt.AsTime()
+	case invitation.RevokedAt != nil:
+		return invitation.RevokedAt.AsTime()
+	default:
+		return invitation.ExpiresAt.AsTime()
+	}
+}
+
+// PurgeInvitations removes the invitations that haven't been usable for
+// InvitationRetention.
+func (d *DB) PurgeInvitations(now time.Time) (count int, err error) {
+	err = d.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		var toDelete [][]byte
+		c := b.Cursor()
+		prefix := []byte("invitation:")
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			invitation := &models.Invitation{}
+			if err := proto.Unmarshal(v, invitation); err != nil {
+				continue
+			}
+			if now.Sub(invitationEndedAt(invitation)) > InvitationRetention {
+				toDelete = append(toDelete, bytes.Clone(k))
+			}
+		}
+		for _, k := range toDelete {
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+		}
+		count = len(toDelete)
+		return nil
+	})
+	return
+}
+
+// PurgeServiceAccountAssertions forgets the used JWT assertions of service
+// accounts that have expired.
+func (d *DB) PurgeServiceAccountAssertions(now time.Time) (count int, err error) {
+	err = d.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		var toDelete [][]byte
+		c := b.Cursor()
+		prefix := []byte("svc-jti:")
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			if len(v) != 8 || int64(binary.BigEndian.Uint64(v)) < now.UnixMilli() {
+				toDelete = append(toDelete, bytes.Clone(k))
+			}
+		}
+		for _, k := range toDelete {
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+		}
+		count = len(toDelete)
+		return nil
+	})
+	return
+}
+
+// Purge removes everything that has expired at `now`. The sessions for which
+// `isStaleSession` returns true are removed as well.
+func (d *DB) Purge(now time.Time, isStaleSession func(session *models.Session, now time.Time) bool) (ret Purged, err error) {
+	if ret.AuthenticationStates, err = d.PurgeAuthenticationStates(now); err != nil {
+		return
+	}
+	purgedMetric.WithLabelValues("authentication_state").Add(float64(ret.AuthenticationStates))
+	if ret.SigninRequests, err = d.PurgeSigninRequests(now); err != nil {
+		return
+	}
+	purgedMetric.WithLabelValues("signin_request").Add(float64(ret.SigninRequests))
+	ret.Sessions, err = d.DeleteSessionsIf(func(session *models.Session) bool {
+		return isStaleSession(session, now)
+	})
+	if err != nil {
+		return
+	}
+	purgedMetric.WithLabelValues("session").Add(float64(ret.Sessions))
+	if ret.SshKeys, err = d.PurgeUnconfirmedSshKeys(now); err != nil {
+		return
+	}
+	purgedMetric.WithLabelValues("ssh_key").Add(float64(ret.SshKeys))
+	if ret.Invitations, err = d.PurgeInvitations(now); err != nil {
+		return
+	}
+	purgedMetric.WithLabelValues("invitation").Add(float64(ret.Invitations))
+	if ret.Assertions, err = d.PurgeServiceAccountAssertions(now); err != nil {
+		return
+	}
+	purgedMetric.WithLabelValues("service_account_assertion").Add(float64(ret.Assertions))
+	return
+}
+
+func (d *DB) PerformPeriodicPurging(interval time.Duration, isStaleSession func(session *models.Session, now time.Time) bool) {
+	for range time.Tick(interval) {
+		purged, err := d.Purge(time.Now(), isStaleSession)
+		if err != nil {
+			d.log.Warnf("Failed to purge the database: %v", err)
+			continue
+		}
+		if purged != (Purged{}) {
+			d.log.Infof("Purged %d authentication states, %d sign-in requests, %d sessions, %d SSH keys, %d invitations and %d service account assertions",
+				purged.AuthenticationStates, purged.SigninRequests, purged.Sessions, purged.SshKeys, purged.Invitations, purged.Assertions)
+		}
+	}
+}
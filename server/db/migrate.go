@@ -0,0 +1,203 @@
+package db
+
+import (
+	"boivie/ubergang/server/models"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/protobuf/proto"
+)
+
+// A Migration upgrades the stored data from schema version `Version - 1` to
+// `Version`. All pending migrations are run in a single transaction, so either
+// all of them are applied or none.
+type Migration struct {
+	Version     int
+	Description string
+	Migrate     func(b *bolt.Bucket) error
+}
+
+// The migrations, in order. Append new ones at the end and never change or
+// remove the ones that have been released.
+var migrations = []Migration{
+	{1, "Remove the retired field 5 from the configuration", dropConfigurationUnknownFields},
+	{2, "Store memberships of provisioned groups by group ID", moveGroupMembershipsToIds},
+}
+
+// SchemaVersion is the version of the stored data that this build expects.
+var SchemaVersion = len(migrations)
+
+// Returned by a dry run to roll back the transaction.
+var errDryRun = errors.New("dry run")
+
+func schemaVersionKey() []byte {
+	return []byte("schema-version")
+}
+
+func getSchemaVersion(b *bolt.Bucket) int {
+	v := b.Get(schemaVersionKey())
+	if len(v) != 8 {
+		// Databases created before migrations were introduced.
+		return 0
+	}
+	return int(binary.BigEndian.Uint64(v))
+}
+
+func putSchemaVersion(b *bolt.Bucket, version int) error {
+	var v [8]byte
+	binary.BigEndian.PutUint64(v[:], uint64(version))
+	return b.Put(schemaVersionKey(), v[:])
+}
+
+func pendingMigrations(b *bolt.Bucket) ([]Migration, error) {
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			return nil, fmt.Errorf("migration %q has version %d, expected %d", m.Description, m.Version, i+1)
+		}
+	}
+	version := getSchemaVersion(b)
+	if version > SchemaVersion {
+		return nil, fmt.Errorf("the database has schema version %d, but this build only supports up to %d", version, SchemaVersion)
+	}
+	return migrations[version:], nil
+}
+
+// isEmpty returns true if nothing but the schema version is stored, e.g. if the
+// database was created by a build without migrations, and never used.
+func isEmpty(b *bolt.Bucket) bool {
+	c := b.Cursor()
+	for k, _ := c.First(); k != nil; k, _ = c.Next() {
+		if !bytes.Equal(k, schemaVersionKey()) {
+			return false
+		}
+	}
+	return true
+}
+
+// MigrationBackupFile returns the file that the database is copied to before
+// it's migrated from schema version `version`.
+func (d *DB) MigrationBackupFile(version int) string {
+	return fmt.Sprintf("%s.v%d.bak", d.db.Path(), version)
+}
+
+// Migrate runs the pending migrations and returns them. The database is first
+// copied to MigrationBackupFile. With `dryRun`, the migrations are run but
+// rolled back, and no backup is made. Databases without data have nothing to
+// migrate, and are set to the latest schema version without a backup.
+func (d *DB) Migrate(dryRun bool) (pending []Migration, err error) {
+	err = d.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		pending, err = pendingMigrations(b)
+		if err != nil || len(pending) == 0 {
+			return err
+		}
+		if isEmpty(b) {
+			pending = nil
+			if dryRun {
+				return nil
+			}
+			d.log.Infof("Database is empty, setting schema version %d", SchemaVersion)
+			return putSchemaVersion(b, SchemaVersion)
+		}
+
+		from := getSchemaVersion(b)
+		if !dryRun {
+			backupFile := d.MigrationBackupFile(from)
+			if err := tx.CopyFile(backupFile, 0600); err != nil {
+				return fmt.Errorf("failed to backup database: %w", err)
+			}
+			d.log.Infof("Database backed up as %s before migrating", backupFile)
+		}
+
+		for _, m := range pending {
+			d.log.Infof("Migrating database to schema version %d: %s", m.Version, m.Description)
+			if err := m.Migrate(b); err != nil {
+				return fmt.Errorf("migration to schema version %d failed: %w", m.Version, err)
+			}
+		}
+		if err := putSchemaVersion(b, SchemaVersion); err != nil {
+			return err
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err == errDryRun {
+		err = nil
+	}
+	return
+}
+
+// dropConfigurationUnknownFields rewrites the configuration without the fields
+// that are no longer in the model, such as the retired field 5.
+func dropConfigurationUnknownFields(b *bolt.Bucket) error {
+	v := b.Get(configKey())
+	if v == nil {
+		return nil
+	}
+	config := &models.Configuration{}
+	if err := (proto.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(v, config); err != nil {
+		return err
+	}
+	data, err := proto.Marshal(config)
+	if err != nil {
+		return err
+	}
+	return b.Put(configKey(), data)
+}
+
+// moveGroupMembershipsToIds replaces the names of provisioned groups in
+// `User.groups` with their IDs in `User.group_ids`.
+func moveGroupMembershipsToIds(b *bolt.Bucket) error {
+	groupIds := map[string]string{}
+	c := b.Cursor()
+	prefix := []byte("group:")
+	for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+		group := &models.Group{}
+		if err := proto.Unmarshal(v, group); err != nil {
+			return err
+		}
+		groupIds[group.DisplayName] = group.Id
+	}
+	if len(groupIds) == 0 {
+		return nil
+	}
+
+	users := map[string]*models.User{}
+	prefix = []byte("user:")
+	for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+		user := &models.User{}
+		if err := proto.Unmarshal(v, user); err != nil {
+			return err
+		}
+		users[string(k)] = user
+	}
+	// Keys can't be modified while iterating.
+	for key, user := range users {
+		groups := make([]string, 0, len(user.Groups))
+		for _, name := range user.Groups {
+			if id, ok := groupIds[name]; ok {
+				user.GroupIds = append(user.GroupIds, id)
+			} else {
+				groups = append(groups, name)
+			}
+		}
+		if len(groups) == len(user.Groups) {
+			continue
+		}
+		user.Groups = groups
+		data, err := proto.Marshal(user)
+		if err != nil {
+			return err
+		}
+		if err := b.Put([]byte(key), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
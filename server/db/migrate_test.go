@@ -0,0 +1,231 @@
+package db
+
+import (
+	"boivie/ubergang/server/log"
+	"boivie/ubergang/server/models"
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/protobuf/proto"
+)
+
+func createDb(t *testing.T) *DB {
+	t.Helper()
+	d, err := New(log.NewLogger(log.Fields{}), path.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(d.Close)
+	return d
+}
+
+// withMigrations replaces the migrations for the duration of the test.
+func withMigrations(t *testing.T, replacement ...Migration) {
+	t.Helper()
+	oldMigrations, oldVersion := migrations, SchemaVersion
+	migrations, SchemaVersion = replacement, len(replacement)
+	t.Cleanup(func() {
+		migrations, SchemaVersion = oldMigrations, oldVersion
+	})
+}
+
+func (d *DB) schemaVersion(t *testing.T) int {
+	t.Helper()
+	version := 0
+	require.NoError(t, d.db.View(func(tx *bolt.Tx) error {
+		version = getSchemaVersion(tx.Bucket(BucketName))
+		return nil
+	}))
+	return version
+}
+
+func (d *DB) setSchemaVersion(t *testing.T, version int) {
+	t.Helper()
+	require.NoError(t, d.db.Update(func(tx *bolt.Tx) error {
+		return putSchemaVersion(tx.Bucket(BucketName), version)
+	}))
+}
+
+func (d *DB) has(t *testing.T, key string) bool {
+	t.Helper()
+	found := false
+	require.NoError(t, d.db.View(func(tx *bolt.Tx) error {
+		found = tx.Bucket(BucketName).Get([]byte(key)) != nil
+		return nil
+	}))
+	return found
+}
+
+// createOldDb returns a database with some data, at schema `version`.
+func createOldDb(t *testing.T, version int) *DB {
+	t.Helper()
+	d := createDb(t)
+	d.setSchemaVersion(t, version)
+	require.NoError(t, d.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(BucketName).Put([]byte("data"), []byte{1})
+	}))
+	return d
+}
+
+func (d *DB) hasBackups(t *testing.T) bool {
+	t.Helper()
+	backups, err := filepath.Glob(d.db.Path() + ".v*.bak")
+	require.NoError(t, err)
+	return len(backups) > 0
+}
+
+// putKey returns a migration that stores `key`.
+func putKey(version int, key string) Migration {
+	return Migration{version, "Store " + key, func(b *bolt.Bucket) error {
+		return b.Put([]byte(key), []byte{1})
+	}}
+}
+
+func TestMigrate(t *testing.T) {
+	t.Run("new databases start at the latest schema version", func(t *testing.T) {
+		d := createDb(t)
+		assert.Equal(t, SchemaVersion, d.schemaVersion(t))
+
+		pending, err := d.Migrate(false)
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+		assert.False(t, d.hasBackups(t))
+	})
+
+	t.Run("empty databases are set to the latest schema version", func(t *testing.T) {
+		withMigrations(t, putKey(1, "migrated"), putKey(2, "migrated-again"))
+		d := createDb(t)
+		d.setSchemaVersion(t, 0)
+
+		pending, err := d.Migrate(true)
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+		assert.Equal(t, 0, d.schemaVersion(t))
+
+		pending, err = d.Migrate(false)
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+		assert.Equal(t, 2, d.schemaVersion(t))
+		assert.False(t, d.has(t, "migrated"))
+		assert.False(t, d.hasBackups(t))
+	})
+
+	t.Run("runs pending migrations in version order", func(t *testing.T) {
+		var ran []int
+		record := func(version int) Migration {
+			return Migration{version, "Record", func(b *bolt.Bucket) error {
+				ran = append(ran, version)
+				return nil
+			}}
+		}
+		withMigrations(t, record(1), record(2), record(3))
+		d := createOldDb(t, 1)
+
+		pending, err := d.Migrate(false)
+		require.NoError(t, err)
+		assert.Len(t, pending, 2)
+		assert.Equal(t, []int{2, 3}, ran)
+		assert.Equal(t, 3, d.schemaVersion(t))
+
+		// Nothing is left to run.
+		pending, err = d.Migrate(false)
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+		assert.Equal(t, []int{2, 3}, ran)
+	})
+
+	t.Run("refuses migrations that are out of order", func(t *testing.T) {
+		withMigrations(t, putKey(2, "first"), putKey(1, "second"))
+		d := createOldDb(t, 0)
+
+		_, err := d.Migrate(false)
+		assert.Error(t, err)
+		assert.False(t, d.has(t, "first"))
+	})
+
+	t.Run("backs up the database before migrating", func(t *testing.T) {
+		d := createOldDb(t, 0)
+		backupFile := d.MigrationBackupFile(0)
+		withMigrations(t, Migration{1, "Check backup", func(b *bolt.Bucket) error {
+			_, err := os.Stat(backupFile)
+			return err
+		}}, putKey(2, "migrated"))
+
+		_, err := d.Migrate(false)
+		require.NoError(t, err)
+		assert.True(t, d.has(t, "migrated"))
+
+		// The backup has the data from before the migration.
+		backup, err := New(d.log, backupFile)
+		require.NoError(t, err)
+		defer backup.Close()
+		assert.Equal(t, 0, backup.schemaVersion(t))
+		assert.False(t, backup.has(t, "migrated"))
+	})
+
+	t.Run("dry run rolls back", func(t *testing.T) {
+		withMigrations(t, putKey(1, "migrated"))
+		d := createOldDb(t, 0)
+
+		pending, err := d.Migrate(true)
+		require.NoError(t, err)
+		assert.Len(t, pending, 1)
+		assert.False(t, d.has(t, "migrated"))
+		assert.Equal(t, 0, d.schemaVersion(t))
+		assert.False(t, d.hasBackups(t))
+	})
+
+	t.Run("rolls back all migrations if one fails", func(t *testing.T) {
+		withMigrations(t, putKey(1, "migrated"), Migration{2, "Fail", func(b *bolt.Bucket) error {
+			return errors.New("failed")
+		}})
+		d := createOldDb(t, 0)
+
+		_, err := d.Migrate(false)
+		assert.Error(t, err)
+		assert.False(t, d.has(t, "migrated"))
+		assert.Equal(t, 0, d.schemaVersion(t))
+	})
+
+	t.Run("refuses a database with a newer schema version", func(t *testing.T) {
+		withMigrations(t, putKey(1, "migrated"))
+		d := createOldDb(t, 2)
+
+		_, err := d.Migrate(false)
+		assert.Error(t, err)
+		assert.False(t, d.has(t, "migrated"))
+		assert.False(t, d.hasBackups(t))
+	})
+
+	t.Run("clearing the database keeps its schema version", func(t *testing.T) {
+		d := createDb(t)
+		require.NoError(t, d.ClearDatabase())
+		assert.Equal(t, SchemaVersion, d.schemaVersion(t))
+	})
+}
+
+func TestMoveGroupMembershipsToIds(t *testing.T) {
+	d := createDb(t)
+	put := func(key string, m proto.Message) {
+		data, err := proto.Marshal(m)
+		require.NoError(t, err)
+		require.NoError(t, d.db.Update(func(tx *bolt.Tx) error {
+			return tx.Bucket(BucketName).Put([]byte(key), data)
+		}))
+	}
+	put("group:g1", &models.Group{Id: "g1", DisplayName: "Engineering"})
+	put("user:u1", &models.User{Id: "u1", Groups: []string{"Engineering", "admins"}})
+	d.setSchemaVersion(t, 1)
+
+	_, err := d.Migrate(false)
+	require.NoError(t, err)
+
+	user, err := d.GetUserById("u1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"admins"}, user.Groups)
+	assert.Equal(t, []string{"g1"}, user.GroupIds)
+}
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"boivie/ubergang/server/models"
+	"bytes"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/protobuf/proto"
+)
+
+func oidcClientKey(id string) []byte {
+	return []byte(fmt.Sprintf("oidc-client:%s", id))
+}
+
+func (d *DB) GetOidcClient(id string) (ret *models.OidcClient, err error) {
+	err = d.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		v := b.Get(oidcClientKey(id))
+		if v == nil {
+			return fmt.Errorf("failed to find OIDC client")
+		}
+		ret = &models.OidcClient{}
+		return proto.Unmarshal(v, ret)
+	})
+	return
+}
+
+func (d *DB) ListOidcClients() (ret []*models.OidcClient) {
+	_ = d.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(BucketName).Cursor()
+		prefix := []byte("oidc-client:")
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			p := &models.OidcClient{}
+			err := proto.Unmarshal(v, p)
+			if err == nil {
+				ret = append(ret, p)
+			}
+		}
+		return nil
+	})
+	return
+}
+
+func (d *DB) UpdateOidcClient(id string, update_fn func(old *models.OidcClient) (*models.OidcClient, error)) error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		key := oidcClientKey(id)
+		v := b.Get(key)
+		var old_obj *models.OidcClient = nil
+		if v != nil {
+			old_obj = &models.OidcClient{}
+			err := proto.Unmarshal(v, old_obj)
+			if err != nil {
+				return err
+			}
+		}
+		new_obj, err := update_fn(old_obj)
+		if err != nil {
+			return err
+		}
+		if new_obj == nil {
+			// Client is to be deleted.
+			if old_obj != nil {
+				_ = b.Delete(key)
+			}
+			return nil
+		}
+		if new_obj.Id != id {
+			return fmt.Errorf("changing ID is not supported")
+		}
+
+		serialized, err := proto.Marshal(new_obj)
+		if err != nil {
+			return err
+		}
+		return b.Put(key, serialized)
+	})
+}
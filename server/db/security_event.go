@@ -0,0 +1,76 @@
+package db
+
+import (
+	"boivie/ubergang/server/models"
+	"bytes"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/protobuf/proto"
+)
+
+var securityEventPrefix = []byte("security-event:")
+
+// Security events are keyed by time, so that they are listed in order and the
+// oldest ones are easy to find.
+func securityEventKey(event *models.SecurityEvent) []byte {
+	return []byte(fmt.Sprintf("security-event:%020d:%s", event.Timestamp.AsTime().UnixNano(), event.Id))
+}
+
+// AppendSecurityEvent stores a new security event, and deletes the oldest
+// events so that at most `max` are kept.
+func (d *DB) AppendSecurityEvent(event *models.SecurityEvent, max int) error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		serialized, err := proto.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if err := b.Put(securityEventKey(event), serialized); err != nil {
+			return err
+		}
+
+		c := b.Cursor()
+		count := 0
+		for k, _ := c.Seek(securityEventPrefix); k != nil && bytes.HasPrefix(k, securityEventPrefix); k, _ = c.Next() {
+			count++
+		}
+		var expired [][]byte
+		for k, _ := c.Seek(securityEventPrefix); k != nil && bytes.HasPrefix(k, securityEventPrefix) && count > max; k, _ = c.Next() {
+			expired = append(expired, bytes.Clone(k))
+			count--
+		}
+		for _, k := range expired {
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListSecurityEvents returns the events that `filter` accepts, newest first.
+// At most `limit` events are returned.
+func (d *DB) ListSecurityEvents(filter func(event *models.SecurityEvent) bool, limit int) (ret []*models.SecurityEvent) {
+	_ = d.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(BucketName).Cursor()
+		// Start after the last event, i.e. at the first key after the prefix.
+		k, v := c.Seek([]byte("security-event;"))
+		if k == nil {
+			k, v = c.Last()
+		} else {
+			k, v = c.Prev()
+		}
+		for ; k != nil && bytes.HasPrefix(k, securityEventPrefix) && len(ret) < limit; k, v = c.Prev() {
+			event := &models.SecurityEvent{}
+			if err := proto.Unmarshal(v, event); err != nil {
+				continue
+			}
+			if filter(event) {
+				ret = append(ret, event)
+			}
+		}
+		return nil
+	})
+	return
+}
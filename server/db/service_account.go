@@ -0,0 +1,141 @@
+package db
+
+import (
+	"boivie/ubergang/server/models"
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/protobuf/proto"
+)
+
+func serviceAccountKey(id string) []byte {
+	return []byte(fmt.Sprintf("svc-account:%s", id))
+}
+
+func serviceAccountSshFingerprintKey(fingerprint []byte) []byte {
+	return []byte(fmt.Sprintf("svc-ssh-fp:%s",
+		base64.RawURLEncoding.EncodeToString(fingerprint)))
+}
+
+func serviceAccountAssertionKey(id, jti string) []byte {
+	return []byte(fmt.Sprintf("svc-jti:%s:%s", id, jti))
+}
+
+func (d *DB) GetServiceAccount(id string) (ret *models.ServiceAccount, err error) {
+	err = d.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		v := b.Get(serviceAccountKey(id))
+		if v == nil {
+			return fmt.Errorf("failed to find service account")
+		}
+		ret = &models.ServiceAccount{}
+		return proto.Unmarshal(v, ret)
+	})
+	return
+}
+
+func (d *DB) GetServiceAccountBySshFingerprint(sha256Fingerprint []byte) (ret *models.ServiceAccount, err error) {
+	err = d.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		id := b.Get(serviceAccountSshFingerprintKey(sha256Fingerprint))
+		if id == nil {
+			return fmt.Errorf("failed to find fingerprint")
+		}
+		v := b.Get(serviceAccountKey(string(id)))
+		if v == nil {
+			return fmt.Errorf("failed to find service account")
+		}
+		ret = &models.ServiceAccount{}
+		return proto.Unmarshal(v, ret)
+	})
+	return
+}
+
+func (d *DB) ListServiceAccounts() (ret []*models.ServiceAccount) {
+	_ = d.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(BucketName).Cursor()
+		prefix := []byte("svc-account:")
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			p := &models.ServiceAccount{}
+			err := proto.Unmarshal(v, p)
+			if err == nil {
+				ret = append(ret, p)
+			}
+		}
+		return nil
+	})
+	return
+}
+
+func (d *DB) UpdateServiceAccount(id string, update_fn func(old *models.ServiceAccount) (*models.ServiceAccount, error)) error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		key := serviceAccountKey(id)
+		v := b.Get(key)
+		var old_obj *models.ServiceAccount = nil
+		if v != nil {
+			old_obj = &models.ServiceAccount{}
+			err := proto.Unmarshal(v, old_obj)
+			if err != nil {
+				return err
+			}
+		}
+		new_obj, err := update_fn(old_obj)
+		if err != nil {
+			return err
+		}
+
+		if old_obj != nil {
+			for _, sshKey := range old_obj.SshKeys {
+				_ = b.Delete(serviceAccountSshFingerprintKey(sshKey.Sha256Fingerprint))
+			}
+		}
+		if new_obj == nil {
+			// Service account is to be deleted.
+			if old_obj != nil {
+				_ = b.Delete(key)
+			}
+			return nil
+		}
+		if new_obj.Id != id {
+			return fmt.Errorf("changing ID is not supported")
+		}
+
+		for _, sshKey := range new_obj.SshKeys {
+			fpKey := serviceAccountSshFingerprintKey(sshKey.Sha256Fingerprint)
+			if existing := b.Get(fpKey); existing != nil && string(existing) != id {
+				return fmt.Errorf("SSH key is already used by another service account")
+			}
+			if err := b.Put(fpKey, []byte(id)); err != nil {
+				return err
+			}
+		}
+
+		serialized, err := proto.Marshal(new_obj)
+		if err != nil {
+			return err
+		}
+		return b.Put(key, serialized)
+	})
+}
+
+// UseServiceAccountAssertion records that the JWT assertion with ID `jti` of
+// the service account has been used, and fails if it already was. It's
+// remembered until `expiresAt`, when the assertion can't be used anyway.
+func (d *DB) UseServiceAccountAssertion(id, jti string, expiresAt time.Time) error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		key := serviceAccountAssertionKey(id, jti)
+		if b.Get(key) != nil {
+			return errors.New("assertion has already been used")
+		}
+		var v [8]byte
+		binary.BigEndian.PutUint64(v[:], uint64(expiresAt.UnixMilli()))
+		return b.Put(key, v[:])
+	})
+}
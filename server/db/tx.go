@@ -0,0 +1,31 @@
+package db
+
+import (
+	"boivie/ubergang/server/models"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/protobuf/proto"
+)
+
+// Tx is a transaction in which several changes can be made atomically.
+type Tx struct {
+	b *bolt.Bucket
+}
+
+// Update calls `fn` in a single transaction, which is rolled back if `fn`
+// returns an error.
+func (d *DB) Update(fn func(tx *Tx) error) error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		return fn(&Tx{b: tx.Bucket(BucketName)})
+	})
+}
+
+func (tx *Tx) GetUserById(userId string) (ret *models.User, err error) {
+	v := tx.b.Get(userKey(userId))
+	if v == nil {
+		return nil, fmt.Errorf("failed to find user")
+	}
+	ret = &models.User{}
+	return ret, proto.Unmarshal(v, ret)
+}
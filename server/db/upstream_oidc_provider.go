@@ -0,0 +1,79 @@
+package db
+
+import (
+	"boivie/ubergang/server/models"
+	"bytes"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/protobuf/proto"
+)
+
+func upstreamOidcProviderKey(id string) []byte {
+	return []byte(fmt.Sprintf("upstream-oidc:%s", id))
+}
+
+func (d *DB) GetUpstreamOidcProvider(id string) (ret *models.UpstreamOidcProvider, err error) {
+	err = d.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		v := b.Get(upstreamOidcProviderKey(id))
+		if v == nil {
+			return fmt.Errorf("failed to find upstream OIDC provider")
+		}
+		ret = &models.UpstreamOidcProvider{}
+		return proto.Unmarshal(v, ret)
+	})
+	return
+}
+
+func (d *DB) ListUpstreamOidcProviders() (ret []*models.UpstreamOidcProvider) {
+	_ = d.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(BucketName).Cursor()
+		prefix := []byte("upstream-oidc:")
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			p := &models.UpstreamOidcProvider{}
+			err := proto.Unmarshal(v, p)
+			if err == nil {
+				ret = append(ret, p)
+			}
+		}
+		return nil
+	})
+	return
+}
+
+func (d *DB) UpdateUpstreamOidcProvider(id string, update_fn func(old *models.UpstreamOidcProvider) (*models.UpstreamOidcProvider, error)) error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(BucketName)
+		key := upstreamOidcProviderKey(id)
+		v := b.Get(key)
+		var old_obj *models.UpstreamOidcProvider = nil
+		if v != nil {
+			old_obj = &models.UpstreamOidcProvider{}
+			err := proto.Unmarshal(v, old_obj)
+			if err != nil {
+				return err
+			}
+		}
+		new_obj, err := update_fn(old_obj)
+		if err != nil {
+			return err
+		}
+		if new_obj == nil {
+			// Provider is to be deleted.
+			if old_obj != nil {
+				_ = b.Delete(key)
+			}
+			return nil
+		}
+		if new_obj.Id != id {
+			return fmt.Errorf("changing ID is not supported")
+		}
+
+		serialized, err := proto.Marshal(new_obj)
+		if err != nil {
+			return err
+		}
+		return b.Put(key, serialized)
+	})
+}
@@ -0,0 +1,212 @@
+// Package federation signs users in using upstream OpenID Connect providers.
+package federation
+
+import (
+	"boivie/ubergang/server/log"
+	"boivie/ubergang/server/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+)
+
+// How long discovered provider metadata and keys are cached.
+const metadataCacheDuration = 1 * time.Hour
+
+// https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderMetadata
+type providerMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksUri               string `json:"jwks_uri"`
+}
+
+type discoveredProvider struct {
+	metadata  *providerMetadata
+	keys      map[string]interface{}
+	fetchedAt time.Time
+}
+
+// Identity is what an upstream provider asserts about the signed in user.
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce string `json:"nonce"`
+	Email string `json:"email"`
+	// Some providers send this as a string.
+	EmailVerified interface{} `json:"email_verified"`
+	Name          string      `json:"name"`
+}
+
+type Federation struct {
+	log    *log.Log
+	client *http.Client
+
+	mu        sync.Mutex
+	providers map[string]*discoveredProvider
+}
+
+func New(log *log.Log, client *http.Client) *Federation {
+	return &Federation{
+		log:       log,
+		client:    client,
+		providers: make(map[string]*discoveredProvider),
+	}
+}
+
+func (f *Federation) getJson(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// discover returns the metadata and keys of `issuer`. If `refresh` is set,
+// cached keys are fetched again, e.g. when the provider has rotated them.
+func (f *Federation) discover(ctx context.Context, issuer string, refresh bool) (*discoveredProvider, error) {
+	f.mu.Lock()
+	cached, found := f.providers[issuer]
+	f.mu.Unlock()
+	if found && !refresh && time.Since(cached.fetchedAt) < metadataCacheDuration {
+		return cached, nil
+	}
+
+	metadata := &providerMetadata{}
+	err := f.getJson(ctx, strings.TrimSuffix(issuer, "/")+"/.well-known/openid-configuration", metadata)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to discover provider")
+	}
+	// https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderConfigurationValidation
+	if metadata.Issuer != issuer {
+		return nil, fmt.Errorf("issuer mismatch: %s", metadata.Issuer)
+	}
+
+	jwks := &jsonWebKeySet{}
+	if err := f.getJson(ctx, metadata.JwksUri, jwks); err != nil {
+		return nil, errors.Wrap(err, "failed to fetch keys")
+	}
+	keys, err := jwks.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	discovered := &discoveredProvider{
+		metadata:  metadata,
+		keys:      keys,
+		fetchedAt: time.Now(),
+	}
+	f.mu.Lock()
+	f.providers[issuer] = discovered
+	f.mu.Unlock()
+	return discovered, nil
+}
+
+func oauth2Config(provider *models.UpstreamOidcProvider, metadata *providerMetadata, redirectUri string) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     provider.ClientId,
+		ClientSecret: provider.ClientSecret,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:   metadata.AuthorizationEndpoint,
+			TokenURL:  metadata.TokenEndpoint,
+			AuthStyle: oauth2.AuthStyleInHeader,
+		},
+		RedirectURL: redirectUri,
+		Scopes:      []string{"openid", "email", "profile"},
+	}
+}
+
+// AuthCodeURL returns the URL to send the user to, to sign in at `provider`.
+// The `verifier` is a PKCE code verifier, as created by oauth2.GenerateVerifier.
+func (f *Federation) AuthCodeURL(ctx context.Context, provider *models.UpstreamOidcProvider, redirectUri, state, nonce, verifier string) (string, error) {
+	discovered, err := f.discover(ctx, provider.Issuer, false)
+	if err != nil {
+		return "", err
+	}
+	config := oauth2Config(provider, discovered.metadata, redirectUri)
+	return config.AuthCodeURL(state,
+		oauth2.SetAuthURLParam("nonce", nonce),
+		oauth2.S256ChallengeOption(verifier)), nil
+}
+
+// Exchange redeems the authorization code returned by `provider`, and returns
+// the identity asserted by its ID token.
+func (f *Federation) Exchange(ctx context.Context, provider *models.UpstreamOidcProvider, redirectUri, code, verifier, nonce string) (*Identity, error) {
+	discovered, err := f.discover(ctx, provider.Issuer, false)
+	if err != nil {
+		return nil, err
+	}
+	config := oauth2Config(provider, discovered.metadata, redirectUri)
+	token, err := config.Exchange(context.WithValue(ctx, oauth2.HTTPClient, f.client), code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to exchange code")
+	}
+	rawIdToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIdToken == "" {
+		return nil, errors.New("no ID token returned")
+	}
+
+	claims := &idTokenClaims{}
+	_, err = jwt.ParseWithClaims(rawIdToken, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		if key, found := discovered.keys[kid]; found {
+			return key, nil
+		}
+		if kid == "" && len(discovered.keys) == 1 {
+			for _, key := range discovered.keys {
+				return key, nil
+			}
+		}
+		// The provider may have rotated its keys.
+		refreshed, err := f.discover(ctx, provider.Issuer, true)
+		if err != nil {
+			return nil, err
+		}
+		if key, found := refreshed.keys[kid]; found {
+			return key, nil
+		}
+		return nil, fmt.Errorf("unknown key: %s", kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(provider.Issuer),
+		jwt.WithAudience(provider.ClientId),
+		jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid ID token")
+	}
+	if claims.Nonce != nonce {
+		return nil, errors.New("nonce mismatch")
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("no subject in ID token")
+	}
+
+	return &Identity{
+		Subject:       claims.Subject,
+		Email:         strings.ToLower(claims.Email),
+		EmailVerified: claims.EmailVerified == true || claims.EmailVerified == "true",
+		Name:          claims.Name,
+	}, nil
+}
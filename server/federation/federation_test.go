@@ -0,0 +1,233 @@
+package federation
+
+import (
+	"boivie/ubergang/server/log"
+	"boivie/ubergang/server/models"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProvider is an upstream OIDC provider that issues an ID token with
+// `claims` for any authorization code.
+type fakeProvider struct {
+	t      *testing.T
+	server *httptest.Server
+
+	mu sync.Mutex
+	// The issuer in the discovery document, if not the server's URL.
+	issuer string
+	key    *rsa.PrivateKey
+	kid    string
+	claims jwt.MapClaims
+	// If set, ID tokens are signed with this key instead of the published one.
+	signingKey *rsa.PrivateKey
+	signingKid string
+	// How many times the keys have been fetched.
+	jwksFetches int
+}
+
+func newFakeProvider(t *testing.T) *fakeProvider {
+	t.Helper()
+	p := &fakeProvider{t: t}
+	p.rotateKey("key-1")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		issuer := p.issuer
+		p.mu.Unlock()
+		if issuer == "" {
+			issuer = p.server.URL
+		}
+		writeJson(w, map[string]string{
+			"issuer":                 issuer,
+			"authorization_endpoint": p.server.URL + "/authorize",
+			"token_endpoint":         p.server.URL + "/token",
+			"jwks_uri":               p.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.jwksFetches++
+		writeJson(w, map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": p.kid,
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(p.key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		key, kid := p.key, p.kid
+		if p.signingKey != nil {
+			key, kid = p.signingKey, p.signingKid
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, p.claims)
+		token.Header["kid"] = kid
+		idToken, err := token.SignedString(key)
+		require.NoError(p.t, err)
+		writeJson(w, map[string]interface{}{
+			"access_token": "access-token",
+			"token_type":   "Bearer",
+			"expires_in":   300,
+			"id_token":     idToken,
+		})
+	})
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+
+	p.claims = jwt.MapClaims{
+		"iss":            p.server.URL,
+		"aud":            "client",
+		"sub":            "subject",
+		"exp":            time.Now().Add(5 * time.Minute).Unix(),
+		"nonce":          "nonce",
+		"email":          "User@Example.com",
+		"email_verified": true,
+		"name":           "User",
+	}
+	return p
+}
+
+func writeJson(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func (p *fakeProvider) rotateKey(kid string) {
+	p.t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(p.t, err)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.key, p.kid = key, kid
+}
+
+func (p *fakeProvider) setClaim(name string, value interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.claims[name] = value
+}
+
+func (p *fakeProvider) model() *models.UpstreamOidcProvider {
+	return &models.UpstreamOidcProvider{
+		Id:           "upstream",
+		Issuer:       p.server.URL,
+		ClientId:     "client",
+		ClientSecret: "secret",
+	}
+}
+
+func (p *fakeProvider) exchange(f *Federation) (*Identity, error) {
+	return f.Exchange(context.Background(), p.model(), "https://ubergang.example.com/callback",
+		"code", "verifier", "nonce")
+}
+
+func TestExchange(t *testing.T) {
+	setup := func(t *testing.T) (*fakeProvider, *Federation) {
+		p := newFakeProvider(t)
+		return p, New(log.NewLogger(log.Fields{}), p.server.Client())
+	}
+
+	t.Run("returns the identity", func(t *testing.T) {
+		p, f := setup(t)
+		identity, err := p.exchange(f)
+		require.NoError(t, err)
+		assert.Equal(t, &Identity{
+			Subject:       "subject",
+			Email:         "user@example.com",
+			EmailVerified: true,
+			Name:          "User",
+		}, identity)
+	})
+
+	t.Run("accepts email_verified as a string", func(t *testing.T) {
+		for value, verified := range map[interface{}]bool{
+			"true":  true,
+			"false": false,
+			false:   false,
+		} {
+			p, f := setup(t)
+			p.setClaim("email_verified", value)
+			identity, err := p.exchange(f)
+			require.NoError(t, err)
+			assert.Equal(t, verified, identity.EmailVerified, "email_verified: %#v", value)
+		}
+	})
+
+	t.Run("refuses a discovery document for another issuer", func(t *testing.T) {
+		p, f := setup(t)
+		p.issuer = "https://other.example.com"
+		_, err := p.exchange(f)
+		assert.ErrorContains(t, err, "issuer mismatch")
+	})
+
+	t.Run("refuses tokens with invalid claims", func(t *testing.T) {
+		for name, value := range map[string]interface{}{
+			"iss":   "https://other.example.com",
+			"aud":   "other-client",
+			"nonce": "other-nonce",
+			"exp":   time.Now().Add(-time.Minute).Unix(),
+			"sub":   "",
+		} {
+			p, f := setup(t)
+			p.setClaim(name, value)
+			_, err := p.exchange(f)
+			assert.Error(t, err, "claim: %s", name)
+		}
+	})
+
+	t.Run("requires an expiry time", func(t *testing.T) {
+		p, f := setup(t)
+		delete(p.claims, "exp")
+		_, err := p.exchange(f)
+		assert.Error(t, err)
+	})
+
+	t.Run("fetches the keys again when they are rotated", func(t *testing.T) {
+		p, f := setup(t)
+		_, err := p.exchange(f)
+		require.NoError(t, err)
+		assert.Equal(t, 1, p.jwksFetches)
+
+		p.rotateKey("key-2")
+		identity, err := p.exchange(f)
+		require.NoError(t, err)
+		assert.Equal(t, "subject", identity.Subject)
+		assert.Equal(t, 2, p.jwksFetches)
+
+		// The new key is cached.
+		_, err = p.exchange(f)
+		require.NoError(t, err)
+		assert.Equal(t, 2, p.jwksFetches)
+	})
+
+	t.Run("refuses tokens signed with an unpublished key", func(t *testing.T) {
+		for _, kid := range []string{"key-1", "unknown"} {
+			p, f := setup(t)
+			key, err := rsa.GenerateKey(rand.Reader, 2048)
+			require.NoError(t, err)
+			p.signingKey, p.signingKid = key, kid
+			_, err = p.exchange(f)
+			assert.ErrorContains(t, err, "invalid ID token", "kid: %s", kid)
+		}
+	})
+}
@@ -0,0 +1,171 @@
+package gitops
+
+import (
+	"boivie/ubergang/server/db"
+	"boivie/ubergang/server/models"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the declarative configuration of the gateway, as kept in git.
+// Lists that are left out aren't managed, so that e.g. users can be
+// provisioned by other means. Lists that are present are authoritative: an
+// empty list removes all such entities.
+type Config struct {
+	Backends []Backend  `yaml:"backends"`
+	Users    []User     `yaml:"users"`
+	Mqtt     MqttConfig `yaml:"mqtt"`
+}
+
+type Header struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
+type SessionPolicy struct {
+	AbsoluteLifetime time.Duration `yaml:"absolute_lifetime,omitempty"`
+	IdleTimeout      time.Duration `yaml:"idle_timeout,omitempty"`
+}
+
+type Backend struct {
+	Fqdn        string `yaml:"fqdn"`
+	UpstreamUrl string `yaml:"upstream_url"`
+	// "NORMAL" or "PUBLIC". Defaults to "NORMAL".
+	AccessLevel    string         `yaml:"access_level"`
+	Headers        []Header       `yaml:"headers,omitempty"`
+	JsScript       string         `yaml:"js_script,omitempty"`
+	SessionPolicy  *SessionPolicy `yaml:"session_policy,omitempty"`
+	MaxAuthAge     time.Duration  `yaml:"max_auth_age,omitempty"`
+	AllowBasicAuth bool           `yaml:"allow_basic_auth,omitempty"`
+}
+
+// User is identified by its e-mail address. Credentials aren't part of the
+// configuration, so created users have to sign in using a link or an
+// invitation.
+type User struct {
+	Email        string   `yaml:"email"`
+	DisplayName  string   `yaml:"display_name,omitempty"`
+	Admin        bool     `yaml:"admin,omitempty"`
+	Disabled     bool     `yaml:"disabled,omitempty"`
+	AllowedHosts []string `yaml:"allowed_hosts,omitempty"`
+	Groups       []string `yaml:"groups,omitempty"`
+}
+
+type MqttClient struct {
+	Name     string            `yaml:"name"`
+	Password string            `yaml:"password"`
+	Profile  string            `yaml:"profile"`
+	Values   map[string]string `yaml:"values,omitempty"`
+}
+
+type MqttProfile struct {
+	Name           string   `yaml:"name"`
+	AllowPublish   []string `yaml:"allow_publish"`
+	AllowSubscribe []string `yaml:"allow_subscribe"`
+}
+
+type MqttConfig struct {
+	Clients  []MqttClient  `yaml:"clients"`
+	Profiles []MqttProfile `yaml:"profiles"`
+}
+
+// Parse reads a configuration in YAML.
+func Parse(data []byte) (*Config, error) {
+	config := &Config{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Marshal writes a configuration as YAML.
+func (c *Config) Marshal() ([]byte, error) {
+	return yaml.Marshal(c)
+}
+
+func toBackend(b *models.Backend) Backend {
+	ret := Backend{
+		Fqdn:           b.Fqdn,
+		UpstreamUrl:    b.UpstreamUrl,
+		AccessLevel:    models.AccessLevel_NORMAL.String(),
+		JsScript:       b.ScriptHandler.GetJsScript(),
+		MaxAuthAge:     b.MaxAuthAge.AsDuration(),
+		AllowBasicAuth: b.AllowBasicAuth,
+	}
+	if b.AccessLevel == models.AccessLevel_PUBLIC {
+		ret.AccessLevel = models.AccessLevel_PUBLIC.String()
+	}
+	for _, h := range b.Headers {
+		ret.Headers = append(ret.Headers, Header{Name: h.Name, Value: h.Value})
+	}
+	if p := b.SessionPolicy; p != nil {
+		ret.SessionPolicy = &SessionPolicy{
+			AbsoluteLifetime: p.AbsoluteLifetime.AsDuration(),
+			IdleTimeout:      p.IdleTimeout.AsDuration(),
+		}
+	}
+	return ret
+}
+
+func toUser(u *models.User) User {
+	return User{
+		Email:        u.Email,
+		DisplayName:  u.DisplayName,
+		Admin:        u.IsAdmin,
+		Disabled:     u.IsDisabled,
+		AllowedHosts: u.AllowedHosts,
+		Groups:       u.Groups,
+	}
+}
+
+// ExportMqtt returns all MQTT profiles and clients.
+func ExportMqtt(d *db.DB) MqttConfig {
+	profiles := d.ListMqttProfiles()
+	config := MqttConfig{
+		Profiles: make([]MqttProfile, 0, len(profiles)),
+	}
+	for _, p := range profiles {
+		config.Profiles = append(config.Profiles, MqttProfile{
+			Name:           p.Id,
+			AllowPublish:   p.AllowPublish,
+			AllowSubscribe: p.AllowSubscribe,
+		})
+	}
+
+	clients := d.ListMqttClients()
+	config.Clients = make([]MqttClient, 0, len(clients))
+	for _, c := range clients {
+		config.Clients = append(config.Clients, MqttClient{
+			Name:     c.Id,
+			Password: c.Password,
+			Profile:  c.ProfileId,
+			Values:   c.Values,
+		})
+	}
+	return config
+}
+
+// Export returns the current configuration. Applying it to the database it
+// was exported from results in no changes.
+func Export(d *db.DB) *Config {
+	backends := d.ListBackends()
+	config := &Config{
+		Backends: make([]Backend, 0, len(backends)),
+		Mqtt:     ExportMqtt(d),
+	}
+	for _, b := range backends {
+		config.Backends = append(config.Backends, toBackend(b))
+	}
+
+	users := d.ListUsers()
+	config.Users = make([]User, 0, len(users))
+	for _, u := range users {
+		config.Users = append(config.Users, toUser(u))
+	}
+	sort.Slice(config.Users, func(i, j int) bool {
+		return config.Users[i].Email < config.Users[j].Email
+	})
+	return config
+}
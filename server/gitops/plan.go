@@ -0,0 +1,415 @@
+package gitops
+
+import (
+	"boivie/ubergang/server/audit"
+	"boivie/ubergang/server/common"
+	"boivie/ubergang/server/db"
+	"boivie/ubergang/server/models"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ErrChanged is returned when applying a plan if an entity has been changed
+// since the plan was made.
+var ErrChanged = errors.New("changed since the plan was made")
+
+// ErrSelfRemoval is returned by CheckActor if the plan would remove the
+// administrator access of the user applying it.
+var ErrSelfRemoval = errors.New("cannot remove your own administrator access")
+
+type Action string
+
+const (
+	Create Action = "create"
+	Update Action = "update"
+	Delete Action = "delete"
+)
+
+// The kinds of entities in a configuration, which are also the target types
+// of their audit records.
+const (
+	KindBackend     = "backend"
+	KindUser        = "user"
+	KindMqttProfile = "mqtt-profile"
+	KindMqttClient  = "mqtt-client"
+)
+
+// Change is a change to a single entity. `Before` is nil if it's created, and
+// `After` is nil if it's deleted.
+type Change struct {
+	Kind   string
+	Id     string
+	Action Action
+	Before proto.Message
+	After  proto.Message
+}
+
+// Plan is the changes needed to make the database match a configuration, in
+// the order they are to be applied.
+type Plan struct {
+	Changes []*Change
+}
+
+func (p *Plan) add(kind, id string, before, after proto.Message) {
+	change := &Change{Kind: kind, Id: id, Before: before, After: after}
+	switch {
+	case !before.ProtoReflect().IsValid():
+		change.Action, change.Before = Create, nil
+	case !after.ProtoReflect().IsValid():
+		change.Action, change.After = Delete, nil
+	case proto.Equal(before, after):
+		return
+	default:
+		change.Action = Update
+	}
+	p.Changes = append(p.Changes, change)
+}
+
+// TargetId returns what audit records of the change refer to, which for users
+// is their id rather than their e-mail address.
+func (c *Change) TargetId() string {
+	if c.Kind == KindUser {
+		if user, ok := c.After.(*models.User); ok {
+			return user.Id
+		}
+		return c.Before.(*models.User).Id
+	}
+	return c.Id
+}
+
+// CheckActor returns ErrSelfRemoval if the plan would delete, disable or demote
+// the user with `userId`, as they would lock themselves out.
+func (p *Plan) CheckActor(userId string) error {
+	for _, c := range p.Changes {
+		if c.Kind != KindUser || c.TargetId() != userId {
+			continue
+		}
+		after, _ := c.After.(*models.User)
+		if after == nil || !after.IsAdmin || after.IsDisabled {
+			return ErrSelfRemoval
+		}
+	}
+	return nil
+}
+
+// Fields that change on every update, and are not part of the configuration.
+var ignoredFields = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+}
+
+// Diff returns the fields that the change modifies, with secrets redacted.
+func (c *Change) Diff() []*models.AuditChange {
+	ret := make([]*models.AuditChange, 0)
+	for _, change := range audit.Diff(c.Before, c.After) {
+		if !ignoredFields[change.Field] {
+			ret = append(ret, change)
+		}
+	}
+	return ret
+}
+
+func orUnset(value string) string {
+	if value == "" {
+		return "(unset)"
+	}
+	return value
+}
+
+// String returns the plan in a human readable form.
+func (p *Plan) String() string {
+	if len(p.Changes) == 0 {
+		return "No changes.\n"
+	}
+	symbols := map[Action]string{Create: "+", Update: "~", Delete: "-"}
+	var sb strings.Builder
+	for _, c := range p.Changes {
+		fmt.Fprintf(&sb, "%s %s %s\n", symbols[c.Action], c.Kind, c.Id)
+		if c.Action == Update {
+			for _, d := range c.Diff() {
+				fmt.Fprintf(&sb, "    %s: %s -> %s\n", d.Field, orUnset(d.Before), orUnset(d.After))
+			}
+		}
+	}
+	fmt.Fprintf(&sb, "%d changes.\n", len(p.Changes))
+	return sb.String()
+}
+
+func toAccessLevel(level string) (models.AccessLevel, error) {
+	switch level {
+	case "", models.AccessLevel_NORMAL.String():
+		return models.AccessLevel_NORMAL, nil
+	case models.AccessLevel_PUBLIC.String():
+		return models.AccessLevel_PUBLIC, nil
+	}
+	return models.AccessLevel_ACCESS_LEVEL_UNSPECIFIED, fmt.Errorf("invalid access level '%s'", level)
+}
+
+// backend returns `old` updated to match `b`, or a new backend if `old` is
+// nil.
+func (b *Backend) backend(old *models.Backend) (*models.Backend, error) {
+	accessLevel, err := toAccessLevel(b.AccessLevel)
+	if err != nil {
+		return nil, fmt.Errorf("backend '%s': %w", b.Fqdn, err)
+	}
+	if b.MaxAuthAge < 0 {
+		return nil, fmt.Errorf("backend '%s': invalid max auth age", b.Fqdn)
+	}
+	ret := &models.Backend{Fqdn: strings.ToLower(b.Fqdn)}
+	if old != nil {
+		ret = proto.Clone(old).(*models.Backend)
+	}
+	ret.UpstreamUrl = b.UpstreamUrl
+	ret.AccessLevel = accessLevel
+	ret.Headers = nil
+	for _, h := range b.Headers {
+		ret.Headers = append(ret.Headers, &models.Header{Name: h.Name, Value: h.Value})
+	}
+	ret.ScriptHandler = nil
+	if b.JsScript != "" {
+		ret.ScriptHandler = &models.ScriptHandler{JsScript: b.JsScript}
+	}
+	ret.SessionPolicy = nil
+	if p := b.SessionPolicy; p != nil && (p.AbsoluteLifetime != 0 || p.IdleTimeout != 0) {
+		if p.AbsoluteLifetime < 0 || p.IdleTimeout < 0 {
+			return nil, fmt.Errorf("backend '%s': invalid session policy", b.Fqdn)
+		}
+		ret.SessionPolicy = &models.SessionPolicy{}
+		if p.AbsoluteLifetime > 0 {
+			ret.SessionPolicy.AbsoluteLifetime = durationpb.New(p.AbsoluteLifetime)
+		}
+		if p.IdleTimeout > 0 {
+			ret.SessionPolicy.IdleTimeout = durationpb.New(p.IdleTimeout)
+		}
+	}
+	ret.MaxAuthAge = nil
+	if b.MaxAuthAge > 0 {
+		ret.MaxAuthAge = durationpb.New(b.MaxAuthAge)
+	}
+	ret.AllowBasicAuth = b.AllowBasicAuth
+	return ret, nil
+}
+
+// user returns `old` updated to match `u`, or a new user if `old` is nil.
+func (u *User) user(old *models.User) *models.User {
+	ret := &models.User{Id: common.MakeRandomID(), Email: u.Email}
+	if old != nil {
+		ret = proto.Clone(old).(*models.User)
+	}
+	ret.DisplayName = u.DisplayName
+	if ret.DisplayName == "" {
+		ret.DisplayName = ret.Email
+	}
+	ret.IsAdmin = u.Admin
+	ret.IsDisabled = u.Disabled
+	ret.AllowedHosts = u.AllowedHosts
+	ret.Groups = u.Groups
+	return ret
+}
+
+func planBackends(d *db.DB, plan *Plan, backends []Backend) error {
+	existing := make(map[string]*models.Backend)
+	for _, b := range d.ListBackends() {
+		existing[b.Fqdn] = b
+	}
+	seen := make(map[string]bool)
+	for _, b := range backends {
+		fqdn := strings.ToLower(b.Fqdn)
+		if fqdn == "" {
+			return errors.New("backend fqdn cannot be empty")
+		}
+		if seen[fqdn] {
+			return fmt.Errorf("backend '%s' is listed more than once", fqdn)
+		}
+		seen[fqdn] = true
+		after, err := b.backend(existing[fqdn])
+		if err != nil {
+			return err
+		}
+		plan.add(KindBackend, fqdn, existing[fqdn], after)
+	}
+	for _, b := range d.ListBackends() {
+		if !seen[b.Fqdn] {
+			plan.add(KindBackend, b.Fqdn, b, (*models.Backend)(nil))
+		}
+	}
+	return nil
+}
+
+// planUsers matches users by e-mail address, ignoring case, as the addresses
+// in the database are stored as they were entered.
+func planUsers(d *db.DB, plan *Plan, users []User) error {
+	existing := make(map[string]*models.User)
+	for _, u := range d.ListUsers() {
+		existing[strings.ToLower(u.Email)] = u
+	}
+	seen := make(map[string]bool)
+	for _, u := range users {
+		u.Email = strings.TrimSpace(u.Email)
+		if u.Email == "" {
+			return errors.New("user email cannot be empty")
+		}
+		email := strings.ToLower(u.Email)
+		if seen[email] {
+			return fmt.Errorf("user '%s' is listed more than once", u.Email)
+		}
+		seen[email] = true
+		before := existing[email]
+		id := u.Email
+		if before != nil {
+			id = before.Email
+		}
+		plan.add(KindUser, id, before, u.user(before))
+	}
+	for _, u := range d.ListUsers() {
+		if !seen[strings.ToLower(u.Email)] {
+			plan.add(KindUser, u.Email, u, (*models.User)(nil))
+		}
+	}
+	return nil
+}
+
+// planMqtt creates and updates profiles before clients, and deletes clients
+// before profiles, as profiles can't be deleted while in use.
+func planMqtt(d *db.DB, plan *Plan, config MqttConfig) error {
+	profiles := make(map[string]bool)
+	if config.Profiles == nil {
+		for _, p := range d.ListMqttProfiles() {
+			profiles[p.Id] = true
+		}
+	}
+	for _, p := range config.Profiles {
+		if p.Name == "" {
+			return errors.New("profile name cannot be empty")
+		}
+		if profiles[p.Name] {
+			return fmt.Errorf("profile '%s' is listed more than once", p.Name)
+		}
+		profiles[p.Name] = true
+		before, _ := d.GetMqttProfile(p.Name)
+		plan.add(KindMqttProfile, p.Name, before, &models.MqttProfile{
+			Id:             p.Name,
+			AllowPublish:   p.AllowPublish,
+			AllowSubscribe: p.AllowSubscribe,
+		})
+	}
+
+	clients := make(map[string]bool)
+	for _, c := range config.Clients {
+		if c.Name == "" {
+			return errors.New("client name cannot be empty")
+		}
+		if c.Profile == "" {
+			return fmt.Errorf("client '%s' must have a profile", c.Name)
+		}
+		if !profiles[c.Profile] {
+			return fmt.Errorf("client '%s' references unknown profile '%s'", c.Name, c.Profile)
+		}
+		if clients[c.Name] {
+			return fmt.Errorf("client '%s' is listed more than once", c.Name)
+		}
+		clients[c.Name] = true
+		before, _ := d.GetMqttClient(c.Name)
+		plan.add(KindMqttClient, c.Name, before, &models.MqttClient{
+			Id:        c.Name,
+			ProfileId: c.Profile,
+			Password:  c.Password,
+			Values:    c.Values,
+		})
+	}
+	if config.Clients != nil {
+		for _, c := range d.ListMqttClients() {
+			if !clients[c.Id] {
+				plan.add(KindMqttClient, c.Id, c, (*models.MqttClient)(nil))
+			}
+		}
+	}
+
+	if config.Profiles != nil {
+		for _, p := range d.ListMqttProfiles() {
+			if !profiles[p.Id] {
+				plan.add(KindMqttProfile, p.Id, p, (*models.MqttProfile)(nil))
+			}
+		}
+	}
+	return nil
+}
+
+// MakePlan returns the changes needed to make the database match `config`.
+func MakePlan(d *db.DB, config *Config) (*Plan, error) {
+	plan := &Plan{Changes: make([]*Change, 0)}
+	if config.Backends != nil {
+		if err := planBackends(d, plan, config.Backends); err != nil {
+			return nil, err
+		}
+	}
+	if config.Users != nil {
+		if err := planUsers(d, plan, config.Users); err != nil {
+			return nil, err
+		}
+	}
+	if err := planMqtt(d, plan, config.Mqtt); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// update returns a function for the database's update methods, that makes
+// `change`, unless the entity has been changed since the plan was made.
+func update[T proto.Message](change *Change) func(old T) (T, error) {
+	return func(old T) (T, error) {
+		before, _ := change.Before.(T)
+		after, _ := change.After.(T)
+		if !proto.Equal(old, before) {
+			return old, ErrChanged
+		}
+		return after, nil
+	}
+}
+
+func applyChange(tx *db.Tx, change *Change, now time.Time) error {
+	switch change.Kind {
+	case KindBackend:
+		if after, ok := change.After.(*models.Backend); ok && after != nil {
+			if after.CreatedAt == nil {
+				after.CreatedAt = timestamppb.New(now)
+			}
+			after.UpdatedAt = timestamppb.New(now)
+		}
+		return tx.UpdateBackend(change.Id, update[*models.Backend](change))
+	case KindUser:
+		if change.Action == Delete {
+			user := change.Before.(*models.User)
+			current, err := tx.GetUserById(user.Id)
+			if err != nil || !proto.Equal(current, user) {
+				return ErrChanged
+			}
+			return tx.DeleteUser(user.Id)
+		}
+		return tx.UpdateUser(change.After.(*models.User).Id, update[*models.User](change))
+	case KindMqttProfile:
+		return tx.UpdateMqttProfile(change.Id, update[*models.MqttProfile](change))
+	case KindMqttClient:
+		return tx.UpdateMqttClient(change.Id, update[*models.MqttClient](change))
+	}
+	return fmt.Errorf("unknown kind '%s'", change.Kind)
+}
+
+// Apply makes the changes in `plan` in a single transaction, so that either
+// all of them are made, or none if any of them fails.
+func Apply(d *db.DB, plan *Plan, now time.Time) error {
+	return d.Update(func(tx *db.Tx) error {
+		for _, change := range plan.Changes {
+			if err := applyChange(tx, change, now); err != nil {
+				return fmt.Errorf("%s %s '%s': %w", change.Action, change.Kind, change.Id, err)
+			}
+		}
+		return nil
+	})
+}
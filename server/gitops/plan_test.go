@@ -0,0 +1,302 @@
+package gitops
+
+import (
+	"boivie/ubergang/server/db"
+	"boivie/ubergang/server/log"
+	"boivie/ubergang/server/models"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createDb(t *testing.T) *db.DB {
+	t.Helper()
+	d, err := db.New(log.NewLogger(log.Fields{}), path.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	return d
+}
+
+func parse(t *testing.T, yaml string) *Config {
+	t.Helper()
+	config, err := Parse([]byte(yaml))
+	require.NoError(t, err)
+	return config
+}
+
+func apply(t *testing.T, d *db.DB, yaml string) *Plan {
+	t.Helper()
+	plan, err := MakePlan(d, parse(t, yaml))
+	require.NoError(t, err)
+	require.NoError(t, Apply(d, plan, time.Now()))
+	return plan
+}
+
+func actions(plan *Plan) []string {
+	ret := make([]string, 0)
+	for _, c := range plan.Changes {
+		ret = append(ret, string(c.Action)+" "+c.Kind+" "+c.Id)
+	}
+	return ret
+}
+
+const exampleConfig = `
+backends:
+  - fqdn: App.example.com
+    upstream_url: http://localhost:8080
+    headers:
+      - name: X-Api-Key
+        value: secret
+    js_script: "function handle(req) {}"
+    session_policy:
+      idle_timeout: 1h
+    max_auth_age: 15m
+  - fqdn: public.example.com
+    upstream_url: http://localhost:8081
+    access_level: PUBLIC
+users:
+  - email: alice@example.com
+    admin: true
+  - email: bob@example.com
+    display_name: Bob
+    allowed_hosts: [app.example.com]
+    groups: [staff]
+mqtt:
+  profiles:
+    - name: sensors
+      allow_publish: [sensors/#]
+      allow_subscribe: []
+  clients:
+    - name: sensor1
+      password: secret
+      profile: sensors
+`
+
+func TestMakePlan(t *testing.T) {
+	t.Run("creates entities", func(t *testing.T) {
+		d := createDb(t)
+
+		plan := apply(t, d, exampleConfig)
+		assert.Equal(t, []string{
+			"create backend app.example.com",
+			"create backend public.example.com",
+			"create user alice@example.com",
+			"create user bob@example.com",
+			"create mqtt-profile sensors",
+			"create mqtt-client sensor1",
+		}, actions(plan))
+
+		backend, err := d.GetBackend("app.example.com")
+		require.NoError(t, err)
+		assert.Equal(t, "http://localhost:8080", backend.UpstreamUrl)
+		assert.Equal(t, models.AccessLevel_NORMAL, backend.AccessLevel)
+		require.Len(t, backend.Headers, 1)
+		assert.Equal(t, "X-Api-Key", backend.Headers[0].Name)
+		assert.Equal(t, "function handle(req) {}", backend.ScriptHandler.GetJsScript())
+		assert.Equal(t, time.Hour, backend.SessionPolicy.IdleTimeout.AsDuration())
+		assert.Nil(t, backend.SessionPolicy.AbsoluteLifetime)
+		assert.Equal(t, 15*time.Minute, backend.MaxAuthAge.AsDuration())
+		assert.NotNil(t, backend.CreatedAt)
+
+		user, err := d.GetUserByEmail("bob@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, "Bob", user.DisplayName)
+		assert.Equal(t, []string{"app.example.com"}, user.AllowedHosts)
+		assert.Equal(t, []string{"staff"}, user.Groups)
+		assert.False(t, user.IsAdmin)
+
+		client, err := d.GetMqttClient("sensor1")
+		require.NoError(t, err)
+		assert.Equal(t, "sensors", client.ProfileId)
+	})
+
+	t.Run("has no changes when applied again", func(t *testing.T) {
+		d := createDb(t)
+		apply(t, d, exampleConfig)
+
+		plan, err := MakePlan(d, parse(t, exampleConfig))
+		require.NoError(t, err)
+		assert.Empty(t, plan.Changes)
+		assert.Equal(t, "No changes.\n", plan.String())
+	})
+
+	t.Run("has no changes after export", func(t *testing.T) {
+		d := createDb(t)
+		apply(t, d, exampleConfig)
+
+		data, err := Export(d).Marshal()
+		require.NoError(t, err)
+		plan, err := MakePlan(d, parse(t, string(data)))
+		require.NoError(t, err)
+		assert.Empty(t, plan.Changes, string(data))
+	})
+
+	t.Run("updates and deletes entities", func(t *testing.T) {
+		d := createDb(t)
+		apply(t, d, exampleConfig)
+		alice, err := d.GetUserByEmail("alice@example.com")
+		require.NoError(t, err)
+		alice.FederatedIdentities = []*models.FederatedIdentity{{ProviderId: "idp", Subject: "alice"}}
+		require.NoError(t, d.UpdateUser(alice.Id, func(old *models.User) (*models.User, error) {
+			return alice, nil
+		}))
+
+		plan := apply(t, d, `
+backends:
+  - fqdn: app.example.com
+    upstream_url: http://localhost:9090
+users:
+  - email: alice@example.com
+    admin: true
+    groups: [admins]
+mqtt:
+  profiles: []
+  clients: []
+`)
+		assert.Equal(t, []string{
+			"update backend app.example.com",
+			"delete backend public.example.com",
+			"update user alice@example.com",
+			"delete user bob@example.com",
+			"delete mqtt-client sensor1",
+			"delete mqtt-profile sensors",
+		}, actions(plan))
+		assert.Contains(t, plan.String(), "    groups: (unset) -> [\"admins\"]\n")
+
+		backend, err := d.GetBackend("app.example.com")
+		require.NoError(t, err)
+		assert.Equal(t, "http://localhost:9090", backend.UpstreamUrl)
+		assert.Empty(t, backend.Headers)
+		assert.Nil(t, backend.ScriptHandler)
+		assert.Nil(t, backend.MaxAuthAge)
+
+		// Fields that aren't part of the configuration are kept.
+		user, err := d.GetUserById(alice.Id)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"admins"}, user.Groups)
+		assert.Len(t, user.FederatedIdentities, 1)
+
+		_, err = d.GetUserByEmail("bob@example.com")
+		assert.Error(t, err)
+		assert.Empty(t, d.ListMqttProfiles())
+	})
+
+	t.Run("leaves out lists that are not managed", func(t *testing.T) {
+		d := createDb(t)
+		apply(t, d, exampleConfig)
+
+		plan := apply(t, d, `
+backends:
+  - fqdn: app.example.com
+    upstream_url: http://localhost:9090
+`)
+		assert.Equal(t, []string{
+			"update backend app.example.com",
+			"delete backend public.example.com",
+		}, actions(plan))
+		assert.Len(t, d.ListUsers(), 2)
+		assert.Len(t, d.ListMqttClients(), 1)
+	})
+
+	t.Run("matches users by e-mail address regardless of case", func(t *testing.T) {
+		d := createDb(t)
+		apply(t, d, "users: [{email: Alice@Example.com, admin: true}, {email: bob@example.com}]")
+		alice, err := d.GetUserByEmail("Alice@Example.com")
+		require.NoError(t, err)
+
+		plan := apply(t, d, "users: [{email: alice@example.com, admin: true}, {email: BOB@example.com}]")
+		assert.Empty(t, plan.Changes)
+
+		plan = apply(t, d, "users: [{email: ALICE@example.com}]")
+		assert.Equal(t, []string{
+			"update user Alice@Example.com",
+			"delete user bob@example.com",
+		}, actions(plan))
+		user, err := d.GetUserById(alice.Id)
+		require.NoError(t, err)
+		assert.Equal(t, "Alice@Example.com", user.Email)
+		assert.False(t, user.IsAdmin)
+		assert.Len(t, d.ListUsers(), 1)
+	})
+
+	t.Run("rejects invalid configuration", func(t *testing.T) {
+		d := createDb(t)
+		for _, yaml := range []string{
+			"backends: [{fqdn: ''}]",
+			"backends: [{fqdn: a.example.com}, {fqdn: A.example.com}]",
+			"backends: [{fqdn: a.example.com, access_level: SECRET}]",
+			"backends: [{fqdn: a.example.com, max_auth_age: -1h}]",
+			"users: [{email: ''}]",
+			"users: [{email: a@example.com}, {email: a@example.com}]",
+			"users: [{email: a@example.com}, {email: A@example.com}]",
+			"mqtt: {clients: [{name: c, profile: unknown}]}",
+			"mqtt: {clients: [{name: c}]}",
+			"mqtt: {profiles: [{name: ''}]}",
+		} {
+			_, err := MakePlan(d, parse(t, yaml))
+			assert.Error(t, err, yaml)
+		}
+	})
+}
+
+func TestApply(t *testing.T) {
+	t.Run("makes no changes if changed since the plan was made", func(t *testing.T) {
+		d := createDb(t)
+		plan, err := MakePlan(d, parse(t, exampleConfig))
+		require.NoError(t, err)
+		require.NoError(t, d.UpdateBackend("public.example.com", func(old *models.Backend) (*models.Backend, error) {
+			return &models.Backend{Fqdn: "public.example.com"}, nil
+		}))
+
+		err = Apply(d, plan, time.Now())
+		assert.ErrorIs(t, err, ErrChanged)
+		// Not even the changes planned before the failing one.
+		_, err = d.GetBackend("app.example.com")
+		assert.Error(t, err)
+		backend, err := d.GetBackend("public.example.com")
+		require.NoError(t, err)
+		assert.Empty(t, backend.UpstreamUrl)
+	})
+
+	t.Run("makes no changes if one fails", func(t *testing.T) {
+		d := createDb(t)
+		apply(t, d, exampleConfig)
+		plan, err := MakePlan(d, parse(t, `
+backends: []
+users: []
+mqtt: {profiles: [], clients: []}
+`))
+		require.NoError(t, err)
+		// A client is added, so that the profile can't be deleted.
+		require.NoError(t, d.UpdateMqttClient("sensor2", func(old *models.MqttClient) (*models.MqttClient, error) {
+			return &models.MqttClient{Id: "sensor2", ProfileId: "sensors"}, nil
+		}))
+
+		assert.Error(t, Apply(d, plan, time.Now()))
+		assert.Len(t, d.ListBackends(), 2)
+		assert.Len(t, d.ListUsers(), 2)
+		assert.Len(t, d.ListMqttClients(), 2)
+		assert.Len(t, d.ListMqttProfiles(), 1)
+	})
+}
+
+func TestCheckActor(t *testing.T) {
+	d := createDb(t)
+	apply(t, d, "users: [{email: Admin@Example.com, admin: true}, {email: user@example.com}]")
+	admin, err := d.GetUserByEmail("Admin@Example.com")
+	require.NoError(t, err)
+
+	for yaml, expected := range map[string]error{
+		"users: []":                           ErrSelfRemoval,
+		"users: [{email: admin@example.com}]": ErrSelfRemoval,
+		"users: [{email: admin@example.com, admin: true, disabled: true}]": ErrSelfRemoval,
+		"users: [{email: admin@example.com, admin: true}]":                 nil,
+		"backends: []": nil,
+	} {
+		plan, err := MakePlan(d, parse(t, yaml))
+		require.NoError(t, err)
+		assert.Equal(t, expected, plan.CheckActor(admin.Id), yaml)
+	}
+}
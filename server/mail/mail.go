@@ -0,0 +1,152 @@
+package mail
+
+import (
+	"boivie/ubergang/server/common"
+	"boivie/ubergang/server/config"
+	"boivie/ubergang/server/log"
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultSmtpPort = 587
+	// How long sending an e-mail may take in total.
+	smtpTimeout = 10 * time.Second
+)
+
+var ErrNotConfigured = errors.New("sending e-mails is not configured")
+
+// Mailer sends e-mails using the SMTP server in the configuration. As the
+// configuration is shared, changes to it apply immediately.
+type Mailer struct {
+	log    *log.Log
+	config *config.Store
+}
+
+func New(log *log.Log, config *config.Store) *Mailer {
+	return &Mailer{log: log, config: config}
+}
+
+// IsConfigured returns true if e-mails can be sent.
+func (m *Mailer) IsConfigured() bool {
+	config := m.config.Get()
+	return config.Smtp != nil && config.Smtp.Host != ""
+}
+
+// Send sends a plain text e-mail to a single recipient.
+func (m *Mailer) Send(to string, message Message) error {
+	if !m.IsConfigured() {
+		return ErrNotConfigured
+	}
+	cfg := m.config.Get().Smtp
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return errors.Wrap(err, "invalid sender address")
+	}
+	rcpt, err := mail.ParseAddress(to)
+	if err != nil {
+		return errors.Wrap(err, "invalid recipient address")
+	}
+	msg, err := formatMessage(from, rcpt, message, time.Now())
+	if err != nil {
+		return err
+	}
+
+	port := int(cfg.Port)
+	if port == 0 {
+		port = defaultSmtpPort
+	}
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(port))
+	tlsConfig := &tls.Config{ServerName: cfg.Host}
+	dialer := &net.Dialer{Timeout: smtpTimeout}
+
+	var conn net.Conn
+	if cfg.ImplicitTls {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to SMTP server")
+	}
+	_ = conn.SetDeadline(time.Now().Add(smtpTimeout))
+
+	c, err := smtp.NewClient(conn, cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if !cfg.ImplicitTls {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err := c.StartTLS(tlsConfig); err != nil {
+				return errors.Wrap(err, "failed to start TLS")
+			}
+		}
+	}
+	if cfg.Username != "" {
+		// PlainAuth refuses to send the password without TLS, except to
+		// localhost.
+		if err := c.Auth(smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)); err != nil {
+			return errors.Wrap(err, "failed to authenticate")
+		}
+	}
+	if err := c.Mail(from.Address); err != nil {
+		return err
+	}
+	if err := c.Rcpt(rcpt.Address); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	m.log.Infof("Sent e-mail \"%s\" to %s", message.Subject, rcpt.Address)
+	return c.Quit()
+}
+
+func formatMessage(from, to *mail.Address, message Message, now time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", common.MakeRandomID(), domainOf(from.Address))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n")
+	buf.WriteString("\r\n")
+	qp := quotedprintable.NewWriter(&buf)
+	if _, err := qp.Write([]byte(message.Body)); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func domainOf(address string) string {
+	if i := strings.LastIndex(address, "@"); i >= 0 {
+		return address[i+1:]
+	}
+	return "localhost"
+}
@@ -0,0 +1,72 @@
+package mail
+
+import (
+	"boivie/ubergang/server/config"
+	"boivie/ubergang/server/log"
+	"boivie/ubergang/server/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSend(t *testing.T) {
+	t.Run("sends message", func(t *testing.T) {
+		server := StartTestServer(t)
+		config := config.New(&models.Configuration{Smtp: server.Settings()})
+		mailer := New(log.NewLogger(log.Fields{}), config)
+
+		err := mailer.Send("Jane Doe <jane@example.com>", Message{
+			Subject: "Välkommen",
+			Body:    "Hello, Jane!\n",
+		})
+		require.NoError(t, err)
+
+		messages := server.Messages()
+		require.Len(t, messages, 1)
+		msg := messages[0]
+		assert.Equal(t, "noreply@test.example.com", msg.From)
+		assert.Equal(t, []string{"jane@example.com"}, msg.To)
+		assert.Equal(t, "Välkommen", msg.Subject)
+		assert.Equal(t, "Hello, Jane!\n", msg.Body)
+		assert.Equal(t, `"Jane Doe" <jane@example.com>`, msg.Header.Get("To"))
+		assert.Empty(t, msg.Username)
+	})
+
+	t.Run("authenticates", func(t *testing.T) {
+		server := StartTestServer(t)
+		settings := server.Settings()
+		settings.Username = "user"
+		settings.Password = "secret"
+		mailer := New(log.NewLogger(log.Fields{}), config.New(&models.Configuration{Smtp: settings}))
+
+		require.NoError(t, mailer.Send("jane@example.com", Message{Subject: "Hi", Body: "Hi"}))
+
+		messages := server.Messages()
+		require.Len(t, messages, 1)
+		assert.Equal(t, "user", messages[0].Username)
+		assert.Equal(t, "secret", messages[0].Password)
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		mailer := New(log.NewLogger(log.Fields{}), config.New(&models.Configuration{}))
+
+		assert.False(t, mailer.IsConfigured())
+		assert.ErrorIs(t, mailer.Send("jane@example.com", Message{}), ErrNotConfigured)
+	})
+
+	t.Run("invalid recipient", func(t *testing.T) {
+		server := StartTestServer(t)
+		mailer := New(log.NewLogger(log.Fields{}), config.New(&models.Configuration{Smtp: server.Settings()}))
+
+		assert.Error(t, mailer.Send("not an address", Message{}))
+		assert.Empty(t, server.Messages())
+	})
+}
+
+func TestFormatValidity(t *testing.T) {
+	assert.Equal(t, "30 minutes", formatValidity(30*time.Minute))
+	assert.Equal(t, "24 hours", formatValidity(24*time.Hour))
+	assert.Equal(t, "7 days", formatValidity(7*24*time.Hour))
+}
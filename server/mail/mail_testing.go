@@ -0,0 +1,153 @@
+package mail
+
+import (
+	"boivie/ubergang/server/models"
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// ReceivedMessage is an e-mail that TestServer has received.
+type ReceivedMessage struct {
+	From     string
+	To       []string
+	Username string
+	Password string
+	Header   mail.Header
+	Subject  string
+	Body     string
+}
+
+// TestServer is a minimal SMTP server for tests, which records the messages
+// that it receives.
+type TestServer struct {
+	listener net.Listener
+	mu       sync.Mutex
+	messages []*ReceivedMessage
+}
+
+func StartTestServer(t *testing.T) *TestServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to start SMTP server: %v", err)
+	}
+	s := &TestServer{listener: listener}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+// Settings returns the configuration for sending e-mails to this server.
+func (s *TestServer) Settings() *models.SmtpSettings {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return &models.SmtpSettings{
+		Host: addr.IP.String(),
+		Port: uint32(addr.Port),
+		From: "Ubergang <noreply@test.example.com>",
+	}
+}
+
+func (s *TestServer) Messages() []*ReceivedMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*ReceivedMessage{}, s.messages...)
+}
+
+func (s *TestServer) serve(conn net.Conn) {
+	defer conn.Close()
+	tc := textproto.NewConn(conn)
+	_ = tc.PrintfLine("220 localhost ESMTP")
+	msg := &ReceivedMessage{}
+	for {
+		line, err := tc.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(cmd) {
+		case "EHLO":
+			_ = tc.PrintfLine("250-localhost")
+			_ = tc.PrintfLine("250 AUTH PLAIN")
+		case "HELO", "NOOP", "RSET":
+			_ = tc.PrintfLine("250 OK")
+		case "AUTH":
+			_, initial, _ := strings.Cut(arg, " ")
+			decoded, _ := base64.StdEncoding.DecodeString(initial)
+			if parts := strings.Split(string(decoded), "\x00"); len(parts) == 3 {
+				msg.Username, msg.Password = parts[1], parts[2]
+			}
+			_ = tc.PrintfLine("235 Authenticated")
+		case "MAIL":
+			msg.From = parsePath(arg)
+			_ = tc.PrintfLine("250 OK")
+		case "RCPT":
+			msg.To = append(msg.To, parsePath(arg))
+			_ = tc.PrintfLine("250 OK")
+		case "DATA":
+			_ = tc.PrintfLine("354 Go ahead")
+			data, err := tc.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			if err := msg.parse(data); err != nil {
+				_ = tc.PrintfLine("554 %v", err)
+				continue
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, msg)
+			s.mu.Unlock()
+			msg = &ReceivedMessage{Username: msg.Username, Password: msg.Password}
+			_ = tc.PrintfLine("250 OK")
+		case "QUIT":
+			_ = tc.PrintfLine("221 Bye")
+			return
+		default:
+			_ = tc.PrintfLine("502 Not implemented")
+		}
+	}
+}
+
+// parsePath returns the address of "FROM:<address>" or "TO:<address>".
+func parsePath(arg string) string {
+	_, path, _ := strings.Cut(arg, ":")
+	path, _, _ = strings.Cut(path, " ")
+	return strings.Trim(path, "<>")
+}
+
+func (m *ReceivedMessage) parse(data []byte) error {
+	parsed, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	m.Header = parsed.Header
+	m.Subject, err = new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil {
+		return err
+	}
+	body := parsed.Body
+	if strings.EqualFold(parsed.Header.Get("Content-Transfer-Encoding"), "quoted-printable") {
+		body = quotedprintable.NewReader(body)
+	}
+	decoded, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	m.Body = string(decoded)
+	return nil
+}
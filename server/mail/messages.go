@@ -0,0 +1,72 @@
+package mail
+
+import (
+	"fmt"
+	"time"
+)
+
+// An e-mail to send, as created by the functions below.
+type Message struct {
+	Subject string
+	Body    string
+}
+
+func formatValidity(d time.Duration) string {
+	if d >= 48*time.Hour {
+		return fmt.Sprintf("%d days", int(d.Hours()/24))
+	}
+	if d >= 2*time.Hour {
+		return fmt.Sprintf("%d hours", int(d.Hours()))
+	}
+	return fmt.Sprintf("%d minutes", int(d.Minutes()))
+}
+
+// Invitation is sent to new users, to sign in and create a passkey.
+func Invitation(site, url string, validity time.Duration) Message {
+	return Message{
+		Subject: "You have been invited to " + site,
+		Body: fmt.Sprintf(`Hi,
+
+You have been invited to %s. Open the link below to sign in and create a
+passkey:
+
+%s
+
+The link is valid for %s.
+`, site, url, formatValidity(validity)),
+	}
+}
+
+// Recovery is sent when an administrator has created a recovery link, e.g.
+// when the user has lost their passkeys.
+func Recovery(site, url string, validity time.Duration) Message {
+	return Message{
+		Subject: "Recover your account at " + site,
+		Body: fmt.Sprintf(`Hi,
+
+An administrator has created a link for you to sign in to %s and create a
+new passkey:
+
+%s
+
+The link is valid for %s. If you didn't ask for this, please contact the
+administrator.
+`, site, url, formatValidity(validity)),
+	}
+}
+
+// SigninLink is sent when the user asks to sign in by e-mail.
+func SigninLink(site, url string, validity time.Duration) Message {
+	return Message{
+		Subject: "Sign in to " + site,
+		Body: fmt.Sprintf(`Hi,
+
+Open the link below to sign in to %s:
+
+%s
+
+The link can only be used once, and is valid for %s. If you didn't try to
+sign in, you can ignore this e-mail.
+`, site, url, formatValidity(validity)),
+	}
+}
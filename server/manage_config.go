@@ -0,0 +1,80 @@
+package server
+
+import (
+	"boivie/ubergang/server/audit"
+	"boivie/ubergang/server/gitops"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+)
+
+// readConfig reads the configuration in `args`, or from stdin if no file is
+// given.
+func readConfig(args []string) *gitops.Config {
+	var data []byte
+	var err error
+	if len(args) == 0 || args[0] == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(args[0])
+	}
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	config, err := gitops.Parse(data)
+	if err != nil {
+		log.Fatalf("Error parsing configuration: %v", err)
+	}
+	return config
+}
+
+// ManageConfig runs `ubergang config export|plan|apply [file]`. Export writes
+// the configuration to the file, or stdout, and plan and apply read it from
+// the file, or stdin.
+func (s *Server) ManageConfig(args []string) {
+	if len(args) == 0 {
+		log.Fatalf("Usage: ubergang config export|plan|apply [file]")
+	}
+	command, args := args[0], args[1:]
+
+	switch command {
+	case "export":
+		data, err := gitops.Export(s.db).Marshal()
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		if len(args) == 0 || args[0] == "-" {
+			_, err = os.Stdout.Write(data)
+		} else {
+			err = os.WriteFile(args[0], data, 0600)
+		}
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+	case "plan", "apply":
+		plan, err := gitops.MakePlan(s.db, readConfig(args))
+		if err != nil {
+			log.Fatalf("Invalid configuration: %v", err)
+		}
+		fmt.Print(plan.String())
+		if command == "plan" || len(plan.Changes) == 0 {
+			return
+		}
+
+		if err := gitops.Apply(s.db, plan, time.Now()); err != nil {
+			log.Fatalf("Failed to apply changes: %v", err)
+		}
+		actor := audit.Actor{Name: "ubergang config apply"}
+		for _, c := range plan.Changes {
+			record := audit.NewRecord(actor, c.Kind+"."+string(c.Action), c.TargetId(), c.Before, c.After)
+			if err := s.db.AppendAuditRecord(record); err != nil {
+				fmt.Printf("Failed to store audit record: %v\n", err)
+			}
+		}
+		fmt.Printf("Applied %d changes.\n", len(plan.Changes))
+	default:
+		log.Fatalf("Unknown config command '%s'. Use export, plan or apply.", command)
+	}
+}
@@ -0,0 +1,197 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        v5.29.3
+// source: protos/app_password.proto
+
+package models
+
+import (
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// A password that can be used with HTTP Basic authentication to access a
+// single backend, for clients that can't use any other authentication method.
+// Ref: "app-password:$id" -> AppPassword
+// Ref: "user-app-password:$user_id:$id" -> []
+type AppPassword struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId      string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	BackendFqdn string                 `protobuf:"bytes,3,opt,name=backend_fqdn,json=backendFqdn,proto3" json:"backend_fqdn,omitempty"`
+	Name        string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	// Hashed using bcrypt.
+	HashedPassword string                 `protobuf:"bytes,5,opt,name=hashed_password,json=hashedPassword,proto3" json:"hashed_password,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	LastUsedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=last_used_at,json=lastUsedAt,proto3" json:"last_used_at,omitempty"`
+	LastRemoteAddr string                 `protobuf:"bytes,8,opt,name=last_remote_addr,json=lastRemoteAddr,proto3" json:"last_remote_addr,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *AppPassword) Reset() {
+	*x = AppPassword{}
+	mi := &file_protos_app_password_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AppPassword) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppPassword) ProtoMessage() {}
+
+func (x *AppPassword) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_app_password_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppPassword.ProtoReflect.Descriptor instead.
+func (*AppPassword) Descriptor() ([]byte, []int) {
+	return file_protos_app_password_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AppPassword) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AppPassword) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AppPassword) GetBackendFqdn() string {
+	if x != nil {
+		return x.BackendFqdn
+	}
+	return ""
+}
+
+func (x *AppPassword) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AppPassword) GetHashedPassword() string {
+	if x != nil {
+		return x.HashedPassword
+	}
+	return ""
+}
+
+func (x *AppPassword) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *AppPassword) GetLastUsedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUsedAt
+	}
+	return nil
+}
+
+func (x *AppPassword) GetLastRemoteAddr() string {
+	if x != nil {
+		return x.LastRemoteAddr
+	}
+	return ""
+}
+
+var File_protos_app_password_proto protoreflect.FileDescriptor
+
+const file_protos_app_password_proto_rawDesc = "" +
+	"\n" +
+	"\x19protos/app_password.proto\x12\x06models\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb9\x02\n" +
+	"\vAppPassword\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12!\n" +
+	"\fbackend_fqdn\x18\x03 \x01(\tR\vbackendFqdn\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\x12'\n" +
+	"\x0fhashed_password\x18\x05 \x01(\tR\x0ehashedPassword\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12<\n" +
+	"\flast_used_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"lastUsedAt\x12(\n" +
+	"\x10last_remote_addr\x18\b \x01(\tR\x0elastRemoteAddrB\x11Z\x0f./server/modelsb\x06proto3"
+
+var (
+	file_protos_app_password_proto_rawDescOnce sync.Once
+	file_protos_app_password_proto_rawDescData []byte
+)
+
+func file_protos_app_password_proto_rawDescGZIP() []byte {
+	file_protos_app_password_proto_rawDescOnce.Do(func() {
+		file_protos_app_password_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_protos_app_password_proto_rawDesc), len(file_protos_app_password_proto_rawDesc)))
+	})
+	return file_protos_app_password_proto_rawDescData
+}
+
+var file_protos_app_password_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_protos_app_password_proto_goTypes = []any{
+	(*AppPassword)(nil),           // 0: models.AppPassword
+	(*timestamppb.Timestamp)(nil), // 1: google.protobuf.Timestamp
+}
+var file_protos_app_password_proto_depIdxs = []int32{
+	1, // 0: models.AppPassword.created_at:type_name -> google.protobuf.Timestamp
+	1, // 1: models.AppPassword.last_used_at:type_name -> google.protobuf.Timestamp
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_protos_app_password_proto_init() }
+func file_protos_app_password_proto_init() {
+	if File_protos_app_password_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_protos_app_password_proto_rawDesc), len(file_protos_app_password_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_protos_app_password_proto_goTypes,
+		DependencyIndexes: file_protos_app_password_proto_depIdxs,
+		MessageInfos:      file_protos_app_password_proto_msgTypes,
+	}.Build()
+	File_protos_app_password_proto = out.File
+	file_protos_app_password_proto_goTypes = nil
+	file_protos_app_password_proto_depIdxs = nil
+}
@@ -1,7 +1,7 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.32.0
-// 	protoc        v4.25.2
+// 	protoc-gen-go v1.36.7
+// 	protoc        v5.29.3
 // source: protos/authentication_state.proto
 
 package models
@@ -9,6 +9,7 @@ package models
 import (
 	reflect "reflect"
 	sync "sync"
+	unsafe "unsafe"
 
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
@@ -27,31 +28,41 @@ const (
 // to be ordered and expired by only observing the key.
 // Ref: "auth-state:$id"
 type AuthenticationState struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
+	state              protoimpl.MessageState `protogen:"open.v1"`
 	UserVerification   string                 `protobuf:"bytes,1,opt,name=user_verification,json=userVerification,proto3" json:"user_verification,omitempty"`
 	UserId             string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	Challenge          string                 `protobuf:"bytes,3,opt,name=challenge,proto3" json:"challenge,omitempty"`
 	AllowedCredentials [][]byte               `protobuf:"bytes,4,rep,name=allowed_credentials,json=allowedCredentials,proto3" json:"allowed_credentials,omitempty"`
 	ExpiresAt          *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
-	// Types that are assignable to Type:
+	// The relying party ID that the challenge was made for. If empty, it's the
+	// admin FQDN, without port.
+	RpId string `protobuf:"bytes,6,opt,name=rp_id,json=rpId,proto3" json:"rp_id,omitempty"`
+	// Another relying party ID that the challenge may be signed for, by passkeys
+	// created for it. Used while passkeys may have been created for either the
+	// admin FQDN or the site.
+	FallbackRpId string `protobuf:"bytes,7,opt,name=fallback_rp_id,json=fallbackRpId,proto3" json:"fallback_rp_id,omitempty"`
+	// Types that are valid to be assigned to Type:
 	//
 	//	*AuthenticationState_Enroll
 	//	*AuthenticationState_SignIn
 	//	*AuthenticationState_ConfirmSshKey
 	//	*AuthenticationState_ConfirmSignin
-	Type isAuthenticationState_Type `protobuf_oneof:"type"`
+	//	*AuthenticationState_CreateAccessToken
+	//	*AuthenticationState_OidcAuthorize
+	//	*AuthenticationState_UpstreamOidc
+	//	*AuthenticationState_ConfirmDevice
+	//	*AuthenticationState_EnrollTotp
+	//	*AuthenticationState_SignInTotp
+	Type          isAuthenticationState_Type `protobuf_oneof:"type"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *AuthenticationState) Reset() {
 	*x = AuthenticationState{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_authentication_state_proto_msgTypes[0]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_protos_authentication_state_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *AuthenticationState) String() string {
@@ -62,7 +73,7 @@ func (*AuthenticationState) ProtoMessage() {}
 
 func (x *AuthenticationState) ProtoReflect() protoreflect.Message {
 	mi := &file_protos_authentication_state_proto_msgTypes[0]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -112,37 +123,113 @@ func (x *AuthenticationState) GetExpiresAt() *timestamppb.Timestamp {
 	return nil
 }
 
-func (m *AuthenticationState) GetType() isAuthenticationState_Type {
-	if m != nil {
-		return m.Type
+func (x *AuthenticationState) GetRpId() string {
+	if x != nil {
+		return x.RpId
+	}
+	return ""
+}
+
+func (x *AuthenticationState) GetFallbackRpId() string {
+	if x != nil {
+		return x.FallbackRpId
+	}
+	return ""
+}
+
+func (x *AuthenticationState) GetType() isAuthenticationState_Type {
+	if x != nil {
+		return x.Type
 	}
 	return nil
 }
 
 func (x *AuthenticationState) GetEnroll() *AuthenticationStateEnroll {
-	if x, ok := x.GetType().(*AuthenticationState_Enroll); ok {
-		return x.Enroll
+	if x != nil {
+		if x, ok := x.Type.(*AuthenticationState_Enroll); ok {
+			return x.Enroll
+		}
 	}
 	return nil
 }
 
 func (x *AuthenticationState) GetSignIn() *AthenticationStateSignIn {
-	if x, ok := x.GetType().(*AuthenticationState_SignIn); ok {
-		return x.SignIn
+	if x != nil {
+		if x, ok := x.Type.(*AuthenticationState_SignIn); ok {
+			return x.SignIn
+		}
 	}
 	return nil
 }
 
 func (x *AuthenticationState) GetConfirmSshKey() *AthenticationStateConfirmSshKey {
-	if x, ok := x.GetType().(*AuthenticationState_ConfirmSshKey); ok {
-		return x.ConfirmSshKey
+	if x != nil {
+		if x, ok := x.Type.(*AuthenticationState_ConfirmSshKey); ok {
+			return x.ConfirmSshKey
+		}
 	}
 	return nil
 }
 
 func (x *AuthenticationState) GetConfirmSignin() *AuthenticationStateConfirmSignin {
-	if x, ok := x.GetType().(*AuthenticationState_ConfirmSignin); ok {
-		return x.ConfirmSignin
+	if x != nil {
+		if x, ok := x.Type.(*AuthenticationState_ConfirmSignin); ok {
+			return x.ConfirmSignin
+		}
+	}
+	return nil
+}
+
+func (x *AuthenticationState) GetCreateAccessToken() *AuthenticationStateCreateAccessToken {
+	if x != nil {
+		if x, ok := x.Type.(*AuthenticationState_CreateAccessToken); ok {
+			return x.CreateAccessToken
+		}
+	}
+	return nil
+}
+
+func (x *AuthenticationState) GetOidcAuthorize() *AuthenticationStateOidcAuthorize {
+	if x != nil {
+		if x, ok := x.Type.(*AuthenticationState_OidcAuthorize); ok {
+			return x.OidcAuthorize
+		}
+	}
+	return nil
+}
+
+func (x *AuthenticationState) GetUpstreamOidc() *AuthenticationStateUpstreamOidc {
+	if x != nil {
+		if x, ok := x.Type.(*AuthenticationState_UpstreamOidc); ok {
+			return x.UpstreamOidc
+		}
+	}
+	return nil
+}
+
+func (x *AuthenticationState) GetConfirmDevice() *AuthenticationStateConfirmDevice {
+	if x != nil {
+		if x, ok := x.Type.(*AuthenticationState_ConfirmDevice); ok {
+			return x.ConfirmDevice
+		}
+	}
+	return nil
+}
+
+func (x *AuthenticationState) GetEnrollTotp() *AuthenticationStateEnrollTotp {
+	if x != nil {
+		if x, ok := x.Type.(*AuthenticationState_EnrollTotp); ok {
+			return x.EnrollTotp
+		}
+	}
+	return nil
+}
+
+func (x *AuthenticationState) GetSignInTotp() *AuthenticationStateSignInTotp {
+	if x != nil {
+		if x, ok := x.Type.(*AuthenticationState_SignInTotp); ok {
+			return x.SignInTotp
+		}
 	}
 	return nil
 }
@@ -167,6 +254,30 @@ type AuthenticationState_ConfirmSignin struct {
 	ConfirmSignin *AuthenticationStateConfirmSignin `protobuf:"bytes,14,opt,name=confirm_signin,json=confirmSignin,proto3,oneof"`
 }
 
+type AuthenticationState_CreateAccessToken struct {
+	CreateAccessToken *AuthenticationStateCreateAccessToken `protobuf:"bytes,15,opt,name=create_access_token,json=createAccessToken,proto3,oneof"`
+}
+
+type AuthenticationState_OidcAuthorize struct {
+	OidcAuthorize *AuthenticationStateOidcAuthorize `protobuf:"bytes,16,opt,name=oidc_authorize,json=oidcAuthorize,proto3,oneof"`
+}
+
+type AuthenticationState_UpstreamOidc struct {
+	UpstreamOidc *AuthenticationStateUpstreamOidc `protobuf:"bytes,17,opt,name=upstream_oidc,json=upstreamOidc,proto3,oneof"`
+}
+
+type AuthenticationState_ConfirmDevice struct {
+	ConfirmDevice *AuthenticationStateConfirmDevice `protobuf:"bytes,18,opt,name=confirm_device,json=confirmDevice,proto3,oneof"`
+}
+
+type AuthenticationState_EnrollTotp struct {
+	EnrollTotp *AuthenticationStateEnrollTotp `protobuf:"bytes,19,opt,name=enroll_totp,json=enrollTotp,proto3,oneof"`
+}
+
+type AuthenticationState_SignInTotp struct {
+	SignInTotp *AuthenticationStateSignInTotp `protobuf:"bytes,20,opt,name=sign_in_totp,json=signInTotp,proto3,oneof"`
+}
+
 func (*AuthenticationState_Enroll) isAuthenticationState_Type() {}
 
 func (*AuthenticationState_SignIn) isAuthenticationState_Type() {}
@@ -175,22 +286,34 @@ func (*AuthenticationState_ConfirmSshKey) isAuthenticationState_Type() {}
 
 func (*AuthenticationState_ConfirmSignin) isAuthenticationState_Type() {}
 
+func (*AuthenticationState_CreateAccessToken) isAuthenticationState_Type() {}
+
+func (*AuthenticationState_OidcAuthorize) isAuthenticationState_Type() {}
+
+func (*AuthenticationState_UpstreamOidc) isAuthenticationState_Type() {}
+
+func (*AuthenticationState_ConfirmDevice) isAuthenticationState_Type() {}
+
+func (*AuthenticationState_EnrollTotp) isAuthenticationState_Type() {}
+
+func (*AuthenticationState_SignInTotp) isAuthenticationState_Type() {}
+
 // User is enrolling a new credential.
 type AuthenticationStateEnroll struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	SessionId string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	// Where the user chose to store the passkey, e.g. "cross-platform", if
+	// they chose.
+	Attachment    string `protobuf:"bytes,2,opt,name=attachment,proto3" json:"attachment,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	SessionId string `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *AuthenticationStateEnroll) Reset() {
 	*x = AuthenticationStateEnroll{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_authentication_state_proto_msgTypes[1]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_protos_authentication_state_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *AuthenticationStateEnroll) String() string {
@@ -201,7 +324,7 @@ func (*AuthenticationStateEnroll) ProtoMessage() {}
 
 func (x *AuthenticationStateEnroll) ProtoReflect() protoreflect.Message {
 	mi := &file_protos_authentication_state_proto_msgTypes[1]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -223,23 +346,27 @@ func (x *AuthenticationStateEnroll) GetSessionId() string {
 	return ""
 }
 
+func (x *AuthenticationStateEnroll) GetAttachment() string {
+	if x != nil {
+		return x.Attachment
+	}
+	return ""
+}
+
 // User is confirming an enroll request
 type AuthenticationStateConfirmSignin struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	SigninRequestId string `protobuf:"bytes,1,opt,name=signin_request_id,json=signinRequestId,proto3" json:"signin_request_id,omitempty"`
-	SessionId       string `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	SigninRequestId string                 `protobuf:"bytes,1,opt,name=signin_request_id,json=signinRequestId,proto3" json:"signin_request_id,omitempty"`
+	SessionId       string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *AuthenticationStateConfirmSignin) Reset() {
 	*x = AuthenticationStateConfirmSignin{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_authentication_state_proto_msgTypes[2]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_protos_authentication_state_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *AuthenticationStateConfirmSignin) String() string {
@@ -250,7 +377,7 @@ func (*AuthenticationStateConfirmSignin) ProtoMessage() {}
 
 func (x *AuthenticationStateConfirmSignin) ProtoReflect() protoreflect.Message {
 	mi := &file_protos_authentication_state_proto_msgTypes[2]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -281,18 +408,16 @@ func (x *AuthenticationStateConfirmSignin) GetSessionId() string {
 
 // The user has asked to sign in.
 type AthenticationStateSignIn struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *AthenticationStateSignIn) Reset() {
 	*x = AthenticationStateSignIn{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_authentication_state_proto_msgTypes[3]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_protos_authentication_state_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *AthenticationStateSignIn) String() string {
@@ -303,7 +428,7 @@ func (*AthenticationStateSignIn) ProtoMessage() {}
 
 func (x *AthenticationStateSignIn) ProtoReflect() protoreflect.Message {
 	mi := &file_protos_authentication_state_proto_msgTypes[3]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -320,20 +445,17 @@ func (*AthenticationStateSignIn) Descriptor() ([]byte, []int) {
 
 // The user has asked to confirm a SSH key.
 type AthenticationStateConfirmSshKey struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	KeyId         string                 `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	KeyId string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *AthenticationStateConfirmSshKey) Reset() {
 	*x = AthenticationStateConfirmSshKey{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_authentication_state_proto_msgTypes[4]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_protos_authentication_state_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *AthenticationStateConfirmSshKey) String() string {
@@ -344,7 +466,7 @@ func (*AthenticationStateConfirmSshKey) ProtoMessage() {}
 
 func (x *AthenticationStateConfirmSshKey) ProtoReflect() protoreflect.Message {
 	mi := &file_protos_authentication_state_proto_msgTypes[4]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -366,99 +488,543 @@ func (x *AthenticationStateConfirmSshKey) GetKeyId() string {
 	return ""
 }
 
-var File_protos_authentication_state_proto protoreflect.FileDescriptor
+// The user has asked to create a personal access token.
+type AuthenticationStateCreateAccessToken struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Scopes        []string               `protobuf:"bytes,2,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	AllowedHosts  []string               `protobuf:"bytes,3,rep,name=allowed_hosts,json=allowedHosts,proto3" json:"allowed_hosts,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthenticationStateCreateAccessToken) Reset() {
+	*x = AuthenticationStateCreateAccessToken{}
+	mi := &file_protos_authentication_state_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthenticationStateCreateAccessToken) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthenticationStateCreateAccessToken) ProtoMessage() {}
+
+func (x *AuthenticationStateCreateAccessToken) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_authentication_state_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthenticationStateCreateAccessToken.ProtoReflect.Descriptor instead.
+func (*AuthenticationStateCreateAccessToken) Descriptor() ([]byte, []int) {
+	return file_protos_authentication_state_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AuthenticationStateCreateAccessToken) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AuthenticationStateCreateAccessToken) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *AuthenticationStateCreateAccessToken) GetAllowedHosts() []string {
+	if x != nil {
+		return x.AllowedHosts
+	}
+	return nil
+}
+
+func (x *AuthenticationStateCreateAccessToken) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+// The user has authorized an OIDC client. The authorization code given to the
+// client is the state ID followed by a random secret.
+type AuthenticationStateOidcAuthorize struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	ClientId    string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	RedirectUri string                 `protobuf:"bytes,2,opt,name=redirect_uri,json=redirectUri,proto3" json:"redirect_uri,omitempty"`
+	Scopes      []string               `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	Nonce       string                 `protobuf:"bytes,4,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	// S256 PKCE code challenge, if provided.
+	CodeChallenge string                 `protobuf:"bytes,5,opt,name=code_challenge,json=codeChallenge,proto3" json:"code_challenge,omitempty"`
+	SessionId     string                 `protobuf:"bytes,6,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	AuthTime      *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=auth_time,json=authTime,proto3" json:"auth_time,omitempty"`
+	// SHA-256 of the secret part of the authorization code.
+	HashedCodeSecret []byte `protobuf:"bytes,8,opt,name=hashed_code_secret,json=hashedCodeSecret,proto3" json:"hashed_code_secret,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *AuthenticationStateOidcAuthorize) Reset() {
+	*x = AuthenticationStateOidcAuthorize{}
+	mi := &file_protos_authentication_state_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthenticationStateOidcAuthorize) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthenticationStateOidcAuthorize) ProtoMessage() {}
+
+func (x *AuthenticationStateOidcAuthorize) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_authentication_state_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthenticationStateOidcAuthorize.ProtoReflect.Descriptor instead.
+func (*AuthenticationStateOidcAuthorize) Descriptor() ([]byte, []int) {
+	return file_protos_authentication_state_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AuthenticationStateOidcAuthorize) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *AuthenticationStateOidcAuthorize) GetRedirectUri() string {
+	if x != nil {
+		return x.RedirectUri
+	}
+	return ""
+}
+
+func (x *AuthenticationStateOidcAuthorize) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *AuthenticationStateOidcAuthorize) GetNonce() string {
+	if x != nil {
+		return x.Nonce
+	}
+	return ""
+}
+
+func (x *AuthenticationStateOidcAuthorize) GetCodeChallenge() string {
+	if x != nil {
+		return x.CodeChallenge
+	}
+	return ""
+}
+
+func (x *AuthenticationStateOidcAuthorize) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *AuthenticationStateOidcAuthorize) GetAuthTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AuthTime
+	}
+	return nil
+}
+
+func (x *AuthenticationStateOidcAuthorize) GetHashedCodeSecret() []byte {
+	if x != nil {
+		return x.HashedCodeSecret
+	}
+	return nil
+}
+
+// The user is signing in using an upstream OIDC provider. The state ID is
+// passed as `state` to the provider.
+type AuthenticationStateUpstreamOidc struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	ProviderId   string                 `protobuf:"bytes,1,opt,name=provider_id,json=providerId,proto3" json:"provider_id,omitempty"`
+	Nonce        string                 `protobuf:"bytes,2,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	CodeVerifier string                 `protobuf:"bytes,3,opt,name=code_verifier,json=codeVerifier,proto3" json:"code_verifier,omitempty"`
+	// Where to go after signing in.
+	Redirect string `protobuf:"bytes,4,opt,name=redirect,proto3" json:"redirect,omitempty"`
+	// If set, the external identity is linked to the user of this session
+	// instead of signing in.
+	LinkSessionId string `protobuf:"bytes,5,opt,name=link_session_id,json=linkSessionId,proto3" json:"link_session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthenticationStateUpstreamOidc) Reset() {
+	*x = AuthenticationStateUpstreamOidc{}
+	mi := &file_protos_authentication_state_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthenticationStateUpstreamOidc) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthenticationStateUpstreamOidc) ProtoMessage() {}
+
+func (x *AuthenticationStateUpstreamOidc) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_authentication_state_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthenticationStateUpstreamOidc.ProtoReflect.Descriptor instead.
+func (*AuthenticationStateUpstreamOidc) Descriptor() ([]byte, []int) {
+	return file_protos_authentication_state_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AuthenticationStateUpstreamOidc) GetProviderId() string {
+	if x != nil {
+		return x.ProviderId
+	}
+	return ""
+}
+
+func (x *AuthenticationStateUpstreamOidc) GetNonce() string {
+	if x != nil {
+		return x.Nonce
+	}
+	return ""
+}
+
+func (x *AuthenticationStateUpstreamOidc) GetCodeVerifier() string {
+	if x != nil {
+		return x.CodeVerifier
+	}
+	return ""
+}
+
+func (x *AuthenticationStateUpstreamOidc) GetRedirect() string {
+	if x != nil {
+		return x.Redirect
+	}
+	return ""
+}
+
+func (x *AuthenticationStateUpstreamOidc) GetLinkSessionId() string {
+	if x != nil {
+		return x.LinkSessionId
+	}
+	return ""
+}
+
+// The user is approving a device authorization request.
+type AuthenticationStateConfirmDevice struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	DeviceAuthorizationId string                 `protobuf:"bytes,1,opt,name=device_authorization_id,json=deviceAuthorizationId,proto3" json:"device_authorization_id,omitempty"`
+	SessionId             string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *AuthenticationStateConfirmDevice) Reset() {
+	*x = AuthenticationStateConfirmDevice{}
+	mi := &file_protos_authentication_state_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthenticationStateConfirmDevice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthenticationStateConfirmDevice) ProtoMessage() {}
+
+func (x *AuthenticationStateConfirmDevice) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_authentication_state_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthenticationStateConfirmDevice.ProtoReflect.Descriptor instead.
+func (*AuthenticationStateConfirmDevice) Descriptor() ([]byte, []int) {
+	return file_protos_authentication_state_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AuthenticationStateConfirmDevice) GetDeviceAuthorizationId() string {
+	if x != nil {
+		return x.DeviceAuthorizationId
+	}
+	return ""
+}
+
+func (x *AuthenticationStateConfirmDevice) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+// User is enrolling a TOTP credential.
+type AuthenticationStateEnrollTotp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Secret        []byte                 `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthenticationStateEnrollTotp) Reset() {
+	*x = AuthenticationStateEnrollTotp{}
+	mi := &file_protos_authentication_state_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthenticationStateEnrollTotp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthenticationStateEnrollTotp) ProtoMessage() {}
+
+func (x *AuthenticationStateEnrollTotp) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_authentication_state_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthenticationStateEnrollTotp.ProtoReflect.Descriptor instead.
+func (*AuthenticationStateEnrollTotp) Descriptor() ([]byte, []int) {
+	return file_protos_authentication_state_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AuthenticationStateEnrollTotp) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *AuthenticationStateEnrollTotp) GetSecret() []byte {
+	if x != nil {
+		return x.Secret
+	}
+	return nil
+}
+
+// The user has signed in, but must also provide a one-time password.
+type AuthenticationStateSignInTotp struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Set if the user signed in using a passkey.
+	PasskeyCredentialId string `protobuf:"bytes,1,opt,name=passkey_credential_id,json=passkeyCredentialId,proto3" json:"passkey_credential_id,omitempty"`
+	// Where to go after signing in.
+	Redirect      string `protobuf:"bytes,2,opt,name=redirect,proto3" json:"redirect,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthenticationStateSignInTotp) Reset() {
+	*x = AuthenticationStateSignInTotp{}
+	mi := &file_protos_authentication_state_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthenticationStateSignInTotp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthenticationStateSignInTotp) ProtoMessage() {}
+
+func (x *AuthenticationStateSignInTotp) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_authentication_state_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthenticationStateSignInTotp.ProtoReflect.Descriptor instead.
+func (*AuthenticationStateSignInTotp) Descriptor() ([]byte, []int) {
+	return file_protos_authentication_state_proto_rawDescGZIP(), []int{10}
+}
 
-var file_protos_authentication_state_proto_rawDesc = []byte{
-	0x0a, 0x21, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2f, 0x61, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74,
-	0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x12, 0x06, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x1a, 0x1f, 0x67, 0x6f, 0x6f,
-	0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d,
-	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x8d, 0x04, 0x0a,
-	0x13, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53,
-	0x74, 0x61, 0x74, 0x65, 0x12, 0x2b, 0x0a, 0x11, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x76, 0x65, 0x72,
-	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x10, 0x75, 0x73, 0x65, 0x72, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x68,
-	0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63,
-	0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x12, 0x2f, 0x0a, 0x13, 0x61, 0x6c, 0x6c, 0x6f,
-	0x77, 0x65, 0x64, 0x5f, 0x63, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x18,
-	0x04, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x12, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x43, 0x72,
-	0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x12, 0x39, 0x0a, 0x0a, 0x65, 0x78, 0x70,
-	0x69, 0x72, 0x65, 0x73, 0x5f, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
-	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
-	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x65, 0x78, 0x70, 0x69, 0x72,
-	0x65, 0x73, 0x41, 0x74, 0x12, 0x3b, 0x0a, 0x06, 0x65, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x18, 0x0a,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x2e, 0x41, 0x75,
-	0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74,
-	0x65, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x48, 0x00, 0x52, 0x06, 0x65, 0x6e, 0x72, 0x6f, 0x6c,
-	0x6c, 0x12, 0x3b, 0x0a, 0x07, 0x73, 0x69, 0x67, 0x6e, 0x5f, 0x69, 0x6e, 0x18, 0x0b, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x20, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x2e, 0x41, 0x74, 0x68, 0x65,
-	0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x53, 0x69,
-	0x67, 0x6e, 0x49, 0x6e, 0x48, 0x00, 0x52, 0x06, 0x73, 0x69, 0x67, 0x6e, 0x49, 0x6e, 0x12, 0x51,
-	0x0a, 0x0f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x5f, 0x73, 0x73, 0x68, 0x5f, 0x6b, 0x65,
-	0x79, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73,
-	0x2e, 0x41, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74,
-	0x61, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x53, 0x73, 0x68, 0x4b, 0x65, 0x79,
-	0x48, 0x00, 0x52, 0x0d, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x53, 0x73, 0x68, 0x4b, 0x65,
-	0x79, 0x12, 0x51, 0x0a, 0x0e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x5f, 0x73, 0x69, 0x67,
-	0x6e, 0x69, 0x6e, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x6d, 0x6f, 0x64, 0x65,
-	0x6c, 0x73, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x53, 0x69, 0x67,
-	0x6e, 0x69, 0x6e, 0x48, 0x00, 0x52, 0x0d, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x53, 0x69,
-	0x67, 0x6e, 0x69, 0x6e, 0x42, 0x06, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x22, 0x3a, 0x0a, 0x19,
-	0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74,
-	0x61, 0x74, 0x65, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73,
-	0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73,
-	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x22, 0x6d, 0x0a, 0x20, 0x41, 0x75, 0x74, 0x68,
-	0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x43,
-	0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x53, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x12, 0x2a, 0x0a, 0x11,
-	0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69,
-	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73,
-	0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65,
-	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x22, 0x1a, 0x0a, 0x18, 0x41, 0x74, 0x68, 0x65, 0x6e,
-	0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x53, 0x69, 0x67,
-	0x6e, 0x49, 0x6e, 0x22, 0x38, 0x0a, 0x1f, 0x41, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d,
-	0x53, 0x73, 0x68, 0x4b, 0x65, 0x79, 0x12, 0x15, 0x0a, 0x06, 0x6b, 0x65, 0x79, 0x5f, 0x69, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6b, 0x65, 0x79, 0x49, 0x64, 0x42, 0x11, 0x5a,
-	0x0f, 0x2e, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+func (x *AuthenticationStateSignInTotp) GetPasskeyCredentialId() string {
+	if x != nil {
+		return x.PasskeyCredentialId
+	}
+	return ""
 }
 
+func (x *AuthenticationStateSignInTotp) GetRedirect() string {
+	if x != nil {
+		return x.Redirect
+	}
+	return ""
+}
+
+var File_protos_authentication_state_proto protoreflect.FileDescriptor
+
+const file_protos_authentication_state_proto_rawDesc = "" +
+	"\n" +
+	"!protos/authentication_state.proto\x12\x06models\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb3\b\n" +
+	"\x13AuthenticationState\x12+\n" +
+	"\x11user_verification\x18\x01 \x01(\tR\x10userVerification\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1c\n" +
+	"\tchallenge\x18\x03 \x01(\tR\tchallenge\x12/\n" +
+	"\x13allowed_credentials\x18\x04 \x03(\fR\x12allowedCredentials\x129\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x13\n" +
+	"\x05rp_id\x18\x06 \x01(\tR\x04rpId\x12$\n" +
+	"\x0efallback_rp_id\x18\a \x01(\tR\ffallbackRpId\x12;\n" +
+	"\x06enroll\x18\n" +
+	" \x01(\v2!.models.AuthenticationStateEnrollH\x00R\x06enroll\x12;\n" +
+	"\asign_in\x18\v \x01(\v2 .models.AthenticationStateSignInH\x00R\x06signIn\x12Q\n" +
+	"\x0fconfirm_ssh_key\x18\f \x01(\v2'.models.AthenticationStateConfirmSshKeyH\x00R\rconfirmSshKey\x12Q\n" +
+	"\x0econfirm_signin\x18\x0e \x01(\v2(.models.AuthenticationStateConfirmSigninH\x00R\rconfirmSignin\x12^\n" +
+	"\x13create_access_token\x18\x0f \x01(\v2,.models.AuthenticationStateCreateAccessTokenH\x00R\x11createAccessToken\x12Q\n" +
+	"\x0eoidc_authorize\x18\x10 \x01(\v2(.models.AuthenticationStateOidcAuthorizeH\x00R\roidcAuthorize\x12N\n" +
+	"\rupstream_oidc\x18\x11 \x01(\v2'.models.AuthenticationStateUpstreamOidcH\x00R\fupstreamOidc\x12Q\n" +
+	"\x0econfirm_device\x18\x12 \x01(\v2(.models.AuthenticationStateConfirmDeviceH\x00R\rconfirmDevice\x12H\n" +
+	"\venroll_totp\x18\x13 \x01(\v2%.models.AuthenticationStateEnrollTotpH\x00R\n" +
+	"enrollTotp\x12I\n" +
+	"\fsign_in_totp\x18\x14 \x01(\v2%.models.AuthenticationStateSignInTotpH\x00R\n" +
+	"signInTotpB\x06\n" +
+	"\x04type\"Z\n" +
+	"\x19AuthenticationStateEnroll\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x1e\n" +
+	"\n" +
+	"attachment\x18\x02 \x01(\tR\n" +
+	"attachment\"m\n" +
+	" AuthenticationStateConfirmSignin\x12*\n" +
+	"\x11signin_request_id\x18\x01 \x01(\tR\x0fsigninRequestId\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\"\x1a\n" +
+	"\x18AthenticationStateSignIn\"8\n" +
+	"\x1fAthenticationStateConfirmSshKey\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\"\xb2\x01\n" +
+	"$AuthenticationStateCreateAccessToken\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06scopes\x18\x02 \x03(\tR\x06scopes\x12#\n" +
+	"\rallowed_hosts\x18\x03 \x03(\tR\fallowedHosts\x129\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"\xbd\x02\n" +
+	" AuthenticationStateOidcAuthorize\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\tR\bclientId\x12!\n" +
+	"\fredirect_uri\x18\x02 \x01(\tR\vredirectUri\x12\x16\n" +
+	"\x06scopes\x18\x03 \x03(\tR\x06scopes\x12\x14\n" +
+	"\x05nonce\x18\x04 \x01(\tR\x05nonce\x12%\n" +
+	"\x0ecode_challenge\x18\x05 \x01(\tR\rcodeChallenge\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x06 \x01(\tR\tsessionId\x127\n" +
+	"\tauth_time\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\bauthTime\x12,\n" +
+	"\x12hashed_code_secret\x18\b \x01(\fR\x10hashedCodeSecret\"\xc1\x01\n" +
+	"\x1fAuthenticationStateUpstreamOidc\x12\x1f\n" +
+	"\vprovider_id\x18\x01 \x01(\tR\n" +
+	"providerId\x12\x14\n" +
+	"\x05nonce\x18\x02 \x01(\tR\x05nonce\x12#\n" +
+	"\rcode_verifier\x18\x03 \x01(\tR\fcodeVerifier\x12\x1a\n" +
+	"\bredirect\x18\x04 \x01(\tR\bredirect\x12&\n" +
+	"\x0flink_session_id\x18\x05 \x01(\tR\rlinkSessionId\"y\n" +
+	" AuthenticationStateConfirmDevice\x126\n" +
+	"\x17device_authorization_id\x18\x01 \x01(\tR\x15deviceAuthorizationId\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\"V\n" +
+	"\x1dAuthenticationStateEnrollTotp\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x16\n" +
+	"\x06secret\x18\x02 \x01(\fR\x06secret\"o\n" +
+	"\x1dAuthenticationStateSignInTotp\x122\n" +
+	"\x15passkey_credential_id\x18\x01 \x01(\tR\x13passkeyCredentialId\x12\x1a\n" +
+	"\bredirect\x18\x02 \x01(\tR\bredirectB\x11Z\x0f./server/modelsb\x06proto3"
+
 var (
 	file_protos_authentication_state_proto_rawDescOnce sync.Once
-	file_protos_authentication_state_proto_rawDescData = file_protos_authentication_state_proto_rawDesc
+	file_protos_authentication_state_proto_rawDescData []byte
 )
 
 func file_protos_authentication_state_proto_rawDescGZIP() []byte {
 	file_protos_authentication_state_proto_rawDescOnce.Do(func() {
-		file_protos_authentication_state_proto_rawDescData = protoimpl.X.CompressGZIP(file_protos_authentication_state_proto_rawDescData)
+		file_protos_authentication_state_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_protos_authentication_state_proto_rawDesc), len(file_protos_authentication_state_proto_rawDesc)))
 	})
 	return file_protos_authentication_state_proto_rawDescData
 }
 
-var file_protos_authentication_state_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
-var file_protos_authentication_state_proto_goTypes = []interface{}{
-	(*AuthenticationState)(nil),              // 0: models.AuthenticationState
-	(*AuthenticationStateEnroll)(nil),        // 1: models.AuthenticationStateEnroll
-	(*AuthenticationStateConfirmSignin)(nil), // 2: models.AuthenticationStateConfirmSignin
-	(*AthenticationStateSignIn)(nil),         // 3: models.AthenticationStateSignIn
-	(*AthenticationStateConfirmSshKey)(nil),  // 4: models.AthenticationStateConfirmSshKey
-	(*timestamppb.Timestamp)(nil),            // 5: google.protobuf.Timestamp
+var file_protos_authentication_state_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_protos_authentication_state_proto_goTypes = []any{
+	(*AuthenticationState)(nil),                  // 0: models.AuthenticationState
+	(*AuthenticationStateEnroll)(nil),            // 1: models.AuthenticationStateEnroll
+	(*AuthenticationStateConfirmSignin)(nil),     // 2: models.AuthenticationStateConfirmSignin
+	(*AthenticationStateSignIn)(nil),             // 3: models.AthenticationStateSignIn
+	(*AthenticationStateConfirmSshKey)(nil),      // 4: models.AthenticationStateConfirmSshKey
+	(*AuthenticationStateCreateAccessToken)(nil), // 5: models.AuthenticationStateCreateAccessToken
+	(*AuthenticationStateOidcAuthorize)(nil),     // 6: models.AuthenticationStateOidcAuthorize
+	(*AuthenticationStateUpstreamOidc)(nil),      // 7: models.AuthenticationStateUpstreamOidc
+	(*AuthenticationStateConfirmDevice)(nil),     // 8: models.AuthenticationStateConfirmDevice
+	(*AuthenticationStateEnrollTotp)(nil),        // 9: models.AuthenticationStateEnrollTotp
+	(*AuthenticationStateSignInTotp)(nil),        // 10: models.AuthenticationStateSignInTotp
+	(*timestamppb.Timestamp)(nil),                // 11: google.protobuf.Timestamp
 }
 var file_protos_authentication_state_proto_depIdxs = []int32{
-	5, // 0: models.AuthenticationState.expires_at:type_name -> google.protobuf.Timestamp
-	1, // 1: models.AuthenticationState.enroll:type_name -> models.AuthenticationStateEnroll
-	3, // 2: models.AuthenticationState.sign_in:type_name -> models.AthenticationStateSignIn
-	4, // 3: models.AuthenticationState.confirm_ssh_key:type_name -> models.AthenticationStateConfirmSshKey
-	2, // 4: models.AuthenticationState.confirm_signin:type_name -> models.AuthenticationStateConfirmSignin
-	5, // [5:5] is the sub-list for method output_type
-	5, // [5:5] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	11, // 0: models.AuthenticationState.expires_at:type_name -> google.protobuf.Timestamp
+	1,  // 1: models.AuthenticationState.enroll:type_name -> models.AuthenticationStateEnroll
+	3,  // 2: models.AuthenticationState.sign_in:type_name -> models.AthenticationStateSignIn
+	4,  // 3: models.AuthenticationState.confirm_ssh_key:type_name -> models.AthenticationStateConfirmSshKey
+	2,  // 4: models.AuthenticationState.confirm_signin:type_name -> models.AuthenticationStateConfirmSignin
+	5,  // 5: models.AuthenticationState.create_access_token:type_name -> models.AuthenticationStateCreateAccessToken
+	6,  // 6: models.AuthenticationState.oidc_authorize:type_name -> models.AuthenticationStateOidcAuthorize
+	7,  // 7: models.AuthenticationState.upstream_oidc:type_name -> models.AuthenticationStateUpstreamOidc
+	8,  // 8: models.AuthenticationState.confirm_device:type_name -> models.AuthenticationStateConfirmDevice
+	9,  // 9: models.AuthenticationState.enroll_totp:type_name -> models.AuthenticationStateEnrollTotp
+	10, // 10: models.AuthenticationState.sign_in_totp:type_name -> models.AuthenticationStateSignInTotp
+	11, // 11: models.AuthenticationStateCreateAccessToken.expires_at:type_name -> google.protobuf.Timestamp
+	11, // 12: models.AuthenticationStateOidcAuthorize.auth_time:type_name -> google.protobuf.Timestamp
+	13, // [13:13] is the sub-list for method output_type
+	13, // [13:13] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
 }
 
 func init() { file_protos_authentication_state_proto_init() }
@@ -466,81 +1032,25 @@ func file_protos_authentication_state_proto_init() {
 	if File_protos_authentication_state_proto != nil {
 		return
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_protos_authentication_state_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AuthenticationState); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_protos_authentication_state_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AuthenticationStateEnroll); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_protos_authentication_state_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AuthenticationStateConfirmSignin); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_protos_authentication_state_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AthenticationStateSignIn); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_protos_authentication_state_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AthenticationStateConfirmSshKey); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-	}
-	file_protos_authentication_state_proto_msgTypes[0].OneofWrappers = []interface{}{
+	file_protos_authentication_state_proto_msgTypes[0].OneofWrappers = []any{
 		(*AuthenticationState_Enroll)(nil),
 		(*AuthenticationState_SignIn)(nil),
 		(*AuthenticationState_ConfirmSshKey)(nil),
 		(*AuthenticationState_ConfirmSignin)(nil),
+		(*AuthenticationState_CreateAccessToken)(nil),
+		(*AuthenticationState_OidcAuthorize)(nil),
+		(*AuthenticationState_UpstreamOidc)(nil),
+		(*AuthenticationState_ConfirmDevice)(nil),
+		(*AuthenticationState_EnrollTotp)(nil),
+		(*AuthenticationState_SignInTotp)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_protos_authentication_state_proto_rawDesc,
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_protos_authentication_state_proto_rawDesc), len(file_protos_authentication_state_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -549,7 +1059,6 @@ func file_protos_authentication_state_proto_init() {
 		MessageInfos:      file_protos_authentication_state_proto_msgTypes,
 	}.Build()
 	File_protos_authentication_state_proto = out.File
-	file_protos_authentication_state_proto_rawDesc = nil
 	file_protos_authentication_state_proto_goTypes = nil
 	file_protos_authentication_state_proto_depIdxs = nil
 }
@@ -13,6 +13,7 @@ import (
 
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -181,8 +182,17 @@ type Backend struct {
 	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
 	AccessLevel   AccessLevel            `protobuf:"varint,6,opt,name=access_level,json=accessLevel,proto3,enum=models.AccessLevel" json:"access_level,omitempty"`
 	ScriptHandler *ScriptHandler         `protobuf:"bytes,7,opt,name=script_handler,json=scriptHandler,proto3" json:"script_handler,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	// Additional limits for sessions used with this backend. When exceeded,
+	// the user has to sign in again to access the backend.
+	SessionPolicy *SessionPolicy `protobuf:"bytes,8,opt,name=session_policy,json=sessionPolicy,proto3" json:"session_policy,omitempty"`
+	// When set, requires the session to have been verified by a passkey
+	// assertion within this duration.
+	MaxAuthAge *durationpb.Duration `protobuf:"bytes,9,opt,name=max_auth_age,json=maxAuthAge,proto3" json:"max_auth_age,omitempty"`
+	// Accept app passwords using HTTP Basic authentication, in addition to
+	// sessions.
+	AllowBasicAuth bool `protobuf:"varint,10,opt,name=allow_basic_auth,json=allowBasicAuth,proto3" json:"allow_basic_auth,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *Backend) Reset() {
@@ -264,16 +274,37 @@ func (x *Backend) GetScriptHandler() *ScriptHandler {
 	return nil
 }
 
+func (x *Backend) GetSessionPolicy() *SessionPolicy {
+	if x != nil {
+		return x.SessionPolicy
+	}
+	return nil
+}
+
+func (x *Backend) GetMaxAuthAge() *durationpb.Duration {
+	if x != nil {
+		return x.MaxAuthAge
+	}
+	return nil
+}
+
+func (x *Backend) GetAllowBasicAuth() bool {
+	if x != nil {
+		return x.AllowBasicAuth
+	}
+	return false
+}
+
 var File_protos_backend_proto protoreflect.FileDescriptor
 
 const file_protos_backend_proto_rawDesc = "" +
 	"\n" +
-	"\x14protos/backend.proto\x12\x06models\x1a\x1fgoogle/protobuf/timestamp.proto\"2\n" +
+	"\x14protos/backend.proto\x12\x06models\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x14protos/session.proto\"2\n" +
 	"\x06Header\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value\",\n" +
 	"\rScriptHandler\x12\x1b\n" +
-	"\tjs_script\x18\x01 \x01(\tR\bjsScript\"\xd6\x02\n" +
+	"\tjs_script\x18\x01 \x01(\tR\bjsScript\"\xfb\x03\n" +
 	"\aBackend\x12\x12\n" +
 	"\x04fqdn\x18\x01 \x01(\tR\x04fqdn\x12!\n" +
 	"\fupstream_url\x18\x02 \x01(\tR\vupstreamUrl\x12(\n" +
@@ -283,7 +314,12 @@ const file_protos_backend_proto_rawDesc = "" +
 	"\n" +
 	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x126\n" +
 	"\faccess_level\x18\x06 \x01(\x0e2\x13.models.AccessLevelR\vaccessLevel\x12<\n" +
-	"\x0escript_handler\x18\a \x01(\v2\x15.models.ScriptHandlerR\rscriptHandler*C\n" +
+	"\x0escript_handler\x18\a \x01(\v2\x15.models.ScriptHandlerR\rscriptHandler\x12<\n" +
+	"\x0esession_policy\x18\b \x01(\v2\x15.models.SessionPolicyR\rsessionPolicy\x12;\n" +
+	"\fmax_auth_age\x18\t \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"maxAuthAge\x12(\n" +
+	"\x10allow_basic_auth\x18\n" +
+	" \x01(\bR\x0eallowBasicAuth*C\n" +
 	"\vAccessLevel\x12\x1c\n" +
 	"\x18ACCESS_LEVEL_UNSPECIFIED\x10\x00\x12\n" +
 	"\n" +
@@ -311,6 +347,8 @@ var file_protos_backend_proto_goTypes = []any{
 	(*ScriptHandler)(nil),         // 2: models.ScriptHandler
 	(*Backend)(nil),               // 3: models.Backend
 	(*timestamppb.Timestamp)(nil), // 4: google.protobuf.Timestamp
+	(*SessionPolicy)(nil),         // 5: models.SessionPolicy
+	(*durationpb.Duration)(nil),   // 6: google.protobuf.Duration
 }
 var file_protos_backend_proto_depIdxs = []int32{
 	1, // 0: models.Backend.headers:type_name -> models.Header
@@ -318,11 +356,13 @@ var file_protos_backend_proto_depIdxs = []int32{
 	4, // 2: models.Backend.updated_at:type_name -> google.protobuf.Timestamp
 	0, // 3: models.Backend.access_level:type_name -> models.AccessLevel
 	2, // 4: models.Backend.script_handler:type_name -> models.ScriptHandler
-	5, // [5:5] is the sub-list for method output_type
-	5, // [5:5] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	5, // 5: models.Backend.session_policy:type_name -> models.SessionPolicy
+	6, // 6: models.Backend.max_auth_age:type_name -> google.protobuf.Duration
+	7, // [7:7] is the sub-list for method output_type
+	7, // [7:7] is the sub-list for method input_type
+	7, // [7:7] is the sub-list for extension type_name
+	7, // [7:7] is the sub-list for extension extendee
+	0, // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_protos_backend_proto_init() }
@@ -330,6 +370,7 @@ func file_protos_backend_proto_init() {
 	if File_protos_backend_proto != nil {
 		return
 	}
+	file_protos_session_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
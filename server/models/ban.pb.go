@@ -0,0 +1,179 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        v5.29.3
+// source: protos/ban.proto
+
+package models
+
+import (
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Tracks failed attempts to authenticate from an IP address, and whether it's
+// banned.
+// Ref: "ban:$ip" -> Ban
+type Ban struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Ip    string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	// Failures since `window_start`.
+	Failures    uint32                 `protobuf:"varint,2,opt,name=failures,proto3" json:"failures,omitempty"`
+	WindowStart *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=window_start,json=windowStart,proto3" json:"window_start,omitempty"`
+	// Unset if the IP address has never been banned.
+	BannedUntil *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=banned_until,json=bannedUntil,proto3" json:"banned_until,omitempty"`
+	// The number of bans in a row, which decides how long the next one is.
+	BanCount uint32 `protobuf:"varint,5,opt,name=ban_count,json=banCount,proto3" json:"ban_count,omitempty"`
+	// What caused the latest ban, e.g. "ssh".
+	Source        string `protobuf:"bytes,6,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Ban) Reset() {
+	*x = Ban{}
+	mi := &file_protos_ban_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Ban) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Ban) ProtoMessage() {}
+
+func (x *Ban) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_ban_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Ban.ProtoReflect.Descriptor instead.
+func (*Ban) Descriptor() ([]byte, []int) {
+	return file_protos_ban_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Ban) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *Ban) GetFailures() uint32 {
+	if x != nil {
+		return x.Failures
+	}
+	return 0
+}
+
+func (x *Ban) GetWindowStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.WindowStart
+	}
+	return nil
+}
+
+func (x *Ban) GetBannedUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.BannedUntil
+	}
+	return nil
+}
+
+func (x *Ban) GetBanCount() uint32 {
+	if x != nil {
+		return x.BanCount
+	}
+	return 0
+}
+
+func (x *Ban) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+var File_protos_ban_proto protoreflect.FileDescriptor
+
+const file_protos_ban_proto_rawDesc = "" +
+	"\n" +
+	"\x10protos/ban.proto\x12\x06models\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe4\x01\n" +
+	"\x03Ban\x12\x0e\n" +
+	"\x02ip\x18\x01 \x01(\tR\x02ip\x12\x1a\n" +
+	"\bfailures\x18\x02 \x01(\rR\bfailures\x12=\n" +
+	"\fwindow_start\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\vwindowStart\x12=\n" +
+	"\fbanned_until\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\vbannedUntil\x12\x1b\n" +
+	"\tban_count\x18\x05 \x01(\rR\bbanCount\x12\x16\n" +
+	"\x06source\x18\x06 \x01(\tR\x06sourceB\x11Z\x0f./server/modelsb\x06proto3"
+
+var (
+	file_protos_ban_proto_rawDescOnce sync.Once
+	file_protos_ban_proto_rawDescData []byte
+)
+
+func file_protos_ban_proto_rawDescGZIP() []byte {
+	file_protos_ban_proto_rawDescOnce.Do(func() {
+		file_protos_ban_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_protos_ban_proto_rawDesc), len(file_protos_ban_proto_rawDesc)))
+	})
+	return file_protos_ban_proto_rawDescData
+}
+
+var file_protos_ban_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_protos_ban_proto_goTypes = []any{
+	(*Ban)(nil),                   // 0: models.Ban
+	(*timestamppb.Timestamp)(nil), // 1: google.protobuf.Timestamp
+}
+var file_protos_ban_proto_depIdxs = []int32{
+	1, // 0: models.Ban.window_start:type_name -> google.protobuf.Timestamp
+	1, // 1: models.Ban.banned_until:type_name -> google.protobuf.Timestamp
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_protos_ban_proto_init() }
+func file_protos_ban_proto_init() {
+	if File_protos_ban_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_protos_ban_proto_rawDesc), len(file_protos_ban_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_protos_ban_proto_goTypes,
+		DependencyIndexes: file_protos_ban_proto_depIdxs,
+		MessageInfos:      file_protos_ban_proto_msgTypes,
+	}.Build()
+	File_protos_ban_proto = out.File
+	file_protos_ban_proto_goTypes = nil
+	file_protos_ban_proto_depIdxs = nil
+}
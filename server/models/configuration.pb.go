@@ -13,6 +13,7 @@ import (
 
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 )
 
 const (
@@ -22,20 +23,527 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// What to do with a passkey whose signature counter has gone backwards, which
+// indicates that it may have been cloned.
+type CloneAction int32
+
+const (
+	// The passkey is flagged, but can still be used.
+	CloneAction_CLONE_ACTION_FLAG CloneAction = 0
+	// The passkey can no longer be used.
+	CloneAction_CLONE_ACTION_BLOCK CloneAction = 1
+)
+
+// Enum value maps for CloneAction.
+var (
+	CloneAction_name = map[int32]string{
+		0: "CLONE_ACTION_FLAG",
+		1: "CLONE_ACTION_BLOCK",
+	}
+	CloneAction_value = map[string]int32{
+		"CLONE_ACTION_FLAG":  0,
+		"CLONE_ACTION_BLOCK": 1,
+	}
+)
+
+func (x CloneAction) Enum() *CloneAction {
+	p := new(CloneAction)
+	*p = x
+	return p
+}
+
+func (x CloneAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CloneAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_protos_configuration_proto_enumTypes[0].Descriptor()
+}
+
+func (CloneAction) Type() protoreflect.EnumType {
+	return &file_protos_configuration_proto_enumTypes[0]
+}
+
+func (x CloneAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CloneAction.Descriptor instead.
+func (CloneAction) EnumDescriptor() ([]byte, []int) {
+	return file_protos_configuration_proto_rawDescGZIP(), []int{0}
+}
+
+// How e-mails, such as sign-in links, are sent.
+type SmtpSettings struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Host  string                 `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	Port  uint32                 `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	// Authentication is only used if a username is set.
+	Username string `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
+	Password string `protobuf:"bytes,4,opt,name=password,proto3" json:"password,omitempty"`
+	// The sender, e.g. "Ubergang <noreply@example.com>".
+	From string `protobuf:"bytes,5,opt,name=from,proto3" json:"from,omitempty"`
+	// Connect using TLS (usually on port 465) instead of using STARTTLS.
+	ImplicitTls   bool `protobuf:"varint,6,opt,name=implicit_tls,json=implicitTls,proto3" json:"implicit_tls,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SmtpSettings) Reset() {
+	*x = SmtpSettings{}
+	mi := &file_protos_configuration_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SmtpSettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SmtpSettings) ProtoMessage() {}
+
+func (x *SmtpSettings) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_configuration_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SmtpSettings.ProtoReflect.Descriptor instead.
+func (*SmtpSettings) Descriptor() ([]byte, []int) {
+	return file_protos_configuration_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SmtpSettings) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *SmtpSettings) GetPort() uint32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *SmtpSettings) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *SmtpSettings) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *SmtpSettings) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *SmtpSettings) GetImplicitTls() bool {
+	if x != nil {
+		return x.ImplicitTls
+	}
+	return false
+}
+
+// Where security notifications, e.g. about new sign-ins and passkeys, are
+// sent. Each destination is optional.
+type NotificationSettings struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// E-mail the user that the event concerns.
+	EmailUser bool `protobuf:"varint,1,opt,name=email_user,json=emailUser,proto3" json:"email_user,omitempty"`
+	// E-mail all administrators.
+	EmailAdmins bool `protobuf:"varint,2,opt,name=email_admins,json=emailAdmins,proto3" json:"email_admins,omitempty"`
+	// Events are POSTed as JSON to this URL.
+	WebhookUrl string `protobuf:"bytes,3,opt,name=webhook_url,json=webhookUrl,proto3" json:"webhook_url,omitempty"`
+	// If set, webhook requests are signed using HMAC-SHA256, in the
+	// X-Ubergang-Signature header.
+	WebhookSecret string `protobuf:"bytes,4,opt,name=webhook_secret,json=webhookSecret,proto3" json:"webhook_secret,omitempty"`
+	// Events are published as JSON to "<mqtt_topic>/<event type>".
+	MqttTopic     string `protobuf:"bytes,5,opt,name=mqtt_topic,json=mqttTopic,proto3" json:"mqtt_topic,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NotificationSettings) Reset() {
+	*x = NotificationSettings{}
+	mi := &file_protos_configuration_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationSettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationSettings) ProtoMessage() {}
+
+func (x *NotificationSettings) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_configuration_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationSettings.ProtoReflect.Descriptor instead.
+func (*NotificationSettings) Descriptor() ([]byte, []int) {
+	return file_protos_configuration_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *NotificationSettings) GetEmailUser() bool {
+	if x != nil {
+		return x.EmailUser
+	}
+	return false
+}
+
+func (x *NotificationSettings) GetEmailAdmins() bool {
+	if x != nil {
+		return x.EmailAdmins
+	}
+	return false
+}
+
+func (x *NotificationSettings) GetWebhookUrl() string {
+	if x != nil {
+		return x.WebhookUrl
+	}
+	return ""
+}
+
+func (x *NotificationSettings) GetWebhookSecret() string {
+	if x != nil {
+		return x.WebhookSecret
+	}
+	return ""
+}
+
+func (x *NotificationSettings) GetMqttTopic() string {
+	if x != nil {
+		return x.MqttTopic
+	}
+	return ""
+}
+
+// Limits how often clients may try to authenticate. Clients that fail too
+// often are banned for a while, longer each time. Unset values use defaults.
+type BanPolicy struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Disabled bool                   `protobuf:"varint,1,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	// Clients are banned after this many failures within `failure_window`.
+	MaxFailures   uint32               `protobuf:"varint,2,opt,name=max_failures,json=maxFailures,proto3" json:"max_failures,omitempty"`
+	FailureWindow *durationpb.Duration `protobuf:"bytes,3,opt,name=failure_window,json=failureWindow,proto3" json:"failure_window,omitempty"`
+	// The first ban. It's doubled for each following ban, up to
+	// `max_ban_duration`.
+	BanDuration    *durationpb.Duration `protobuf:"bytes,4,opt,name=ban_duration,json=banDuration,proto3" json:"ban_duration,omitempty"`
+	MaxBanDuration *durationpb.Duration `protobuf:"bytes,5,opt,name=max_ban_duration,json=maxBanDuration,proto3" json:"max_ban_duration,omitempty"`
+	// How many requests a client may make to the sign-in endpoints per minute.
+	SigninRequestsPerMinute uint32 `protobuf:"varint,6,opt,name=signin_requests_per_minute,json=signinRequestsPerMinute,proto3" json:"signin_requests_per_minute,omitempty"`
+	// How many requests a client may make per minute to the endpoints that are
+	// polled or used by machines, such as the token endpoint.
+	TokenRequestsPerMinute uint32 `protobuf:"varint,7,opt,name=token_requests_per_minute,json=tokenRequestsPerMinute,proto3" json:"token_requests_per_minute,omitempty"`
+	// Reverse proxies in front of the server, as addresses or CIDR ranges. The
+	// client's address is only taken from X-Forwarded-For if it connects through
+	// one of these.
+	TrustedProxies []string `protobuf:"bytes,8,rep,name=trusted_proxies,json=trustedProxies,proto3" json:"trusted_proxies,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *BanPolicy) Reset() {
+	*x = BanPolicy{}
+	mi := &file_protos_configuration_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BanPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BanPolicy) ProtoMessage() {}
+
+func (x *BanPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_configuration_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BanPolicy.ProtoReflect.Descriptor instead.
+func (*BanPolicy) Descriptor() ([]byte, []int) {
+	return file_protos_configuration_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BanPolicy) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+func (x *BanPolicy) GetMaxFailures() uint32 {
+	if x != nil {
+		return x.MaxFailures
+	}
+	return 0
+}
+
+func (x *BanPolicy) GetFailureWindow() *durationpb.Duration {
+	if x != nil {
+		return x.FailureWindow
+	}
+	return nil
+}
+
+func (x *BanPolicy) GetBanDuration() *durationpb.Duration {
+	if x != nil {
+		return x.BanDuration
+	}
+	return nil
+}
+
+func (x *BanPolicy) GetMaxBanDuration() *durationpb.Duration {
+	if x != nil {
+		return x.MaxBanDuration
+	}
+	return nil
+}
+
+func (x *BanPolicy) GetSigninRequestsPerMinute() uint32 {
+	if x != nil {
+		return x.SigninRequestsPerMinute
+	}
+	return 0
+}
+
+func (x *BanPolicy) GetTokenRequestsPerMinute() uint32 {
+	if x != nil {
+		return x.TokenRequestsPerMinute
+	}
+	return 0
+}
+
+func (x *BanPolicy) GetTrustedProxies() []string {
+	if x != nil {
+		return x.TrustedProxies
+	}
+	return nil
+}
+
+// Which passkeys users may have. Authenticators are identified by their
+// AAGUID, e.g. "adce0002-35bc-c60a-648b-0b25f1f05503".
+type PasskeyPolicy struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// If set, only passkeys from these authenticators may be enrolled and used.
+	AllowedAaguids []string `protobuf:"bytes,1,rep,name=allowed_aaguids,json=allowedAaguids,proto3" json:"allowed_aaguids,omitempty"`
+	// Passkeys from these authenticators may never be enrolled or used.
+	DeniedAaguids []string    `protobuf:"bytes,2,rep,name=denied_aaguids,json=deniedAaguids,proto3" json:"denied_aaguids,omitempty"`
+	CloneAction   CloneAction `protobuf:"varint,3,opt,name=clone_action,json=cloneAction,proto3,enum=models.CloneAction" json:"clone_action,omitempty"`
+	// Users are expected to have at least this many passkeys, so that they
+	// don't get locked out if one is lost.
+	MinPasskeys   uint32 `protobuf:"varint,4,opt,name=min_passkeys,json=minPasskeys,proto3" json:"min_passkeys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PasskeyPolicy) Reset() {
+	*x = PasskeyPolicy{}
+	mi := &file_protos_configuration_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PasskeyPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PasskeyPolicy) ProtoMessage() {}
+
+func (x *PasskeyPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_configuration_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PasskeyPolicy.ProtoReflect.Descriptor instead.
+func (*PasskeyPolicy) Descriptor() ([]byte, []int) {
+	return file_protos_configuration_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PasskeyPolicy) GetAllowedAaguids() []string {
+	if x != nil {
+		return x.AllowedAaguids
+	}
+	return nil
+}
+
+func (x *PasskeyPolicy) GetDeniedAaguids() []string {
+	if x != nil {
+		return x.DeniedAaguids
+	}
+	return nil
+}
+
+func (x *PasskeyPolicy) GetCloneAction() CloneAction {
+	if x != nil {
+		return x.CloneAction
+	}
+	return CloneAction_CLONE_ACTION_FLAG
+}
+
+func (x *PasskeyPolicy) GetMinPasskeys() uint32 {
+	if x != nil {
+		return x.MinPasskeys
+	}
+	return 0
+}
+
+// How passkeys are created and used. Unset values use the defaults, which are
+// for discoverable passkeys on the user's device, with user verification.
+type WebauthnSettings struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Where passkeys may be stored: "platform" (default) for the user's device,
+	// "cross-platform" for security keys such as YubiKeys, or "any" to let the
+	// user choose.
+	Attachment string `protobuf:"bytes,1,opt,name=attachment,proto3" json:"attachment,omitempty"`
+	// "required" (default), "preferred" or "discouraged".
+	UserVerification string `protobuf:"bytes,2,opt,name=user_verification,json=userVerification,proto3" json:"user_verification,omitempty"`
+	// Discoverable passkeys are needed to sign in without first entering an
+	// e-mail address. "required" (default), "preferred" or "discouraged".
+	ResidentKey string `protobuf:"bytes,3,opt,name=resident_key,json=residentKey,proto3" json:"resident_key,omitempty"`
+	// Use `site_fqdn` as the relying party ID instead of `admin_fqdn`, so that
+	// new passkeys can be used on all of the site's subdomains. Passkeys that
+	// were created before keep using the relying party ID they were created
+	// for.
+	UseSiteRpId bool `protobuf:"varint,4,opt,name=use_site_rp_id,json=useSiteRpId,proto3" json:"use_site_rp_id,omitempty"`
+	// List the backends that aren't public at /.well-known/webauthn, so that
+	// browsers let them use the site's passkeys for step-up authentication
+	// within `/_ubergang/`. Requires `use_site_rp_id`. Signing in to the admin
+	// FQDN only ever accepts the admin origin.
+	RelatedOrigins bool `protobuf:"varint,5,opt,name=related_origins,json=relatedOrigins,proto3" json:"related_origins,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *WebauthnSettings) Reset() {
+	*x = WebauthnSettings{}
+	mi := &file_protos_configuration_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebauthnSettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebauthnSettings) ProtoMessage() {}
+
+func (x *WebauthnSettings) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_configuration_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebauthnSettings.ProtoReflect.Descriptor instead.
+func (*WebauthnSettings) Descriptor() ([]byte, []int) {
+	return file_protos_configuration_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *WebauthnSettings) GetAttachment() string {
+	if x != nil {
+		return x.Attachment
+	}
+	return ""
+}
+
+func (x *WebauthnSettings) GetUserVerification() string {
+	if x != nil {
+		return x.UserVerification
+	}
+	return ""
+}
+
+func (x *WebauthnSettings) GetResidentKey() string {
+	if x != nil {
+		return x.ResidentKey
+	}
+	return ""
+}
+
+func (x *WebauthnSettings) GetUseSiteRpId() bool {
+	if x != nil {
+		return x.UseSiteRpId
+	}
+	return false
+}
+
+func (x *WebauthnSettings) GetRelatedOrigins() bool {
+	if x != nil {
+		return x.RelatedOrigins
+	}
+	return false
+}
+
 // Ref: config -> Configuration (singleton)
 type Configuration struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
-	SiteFqdn      string                 `protobuf:"bytes,2,opt,name=site_fqdn,json=siteFqdn,proto3" json:"site_fqdn,omitempty"`
-	AdminFqdn     string                 `protobuf:"bytes,3,opt,name=admin_fqdn,json=adminFqdn,proto3" json:"admin_fqdn,omitempty"`
-	IsInTestMode  bool                   `protobuf:"varint,4,opt,name=is_in_test_mode,json=isInTestMode,proto3" json:"is_in_test_mode,omitempty"`
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Email        string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	SiteFqdn     string                 `protobuf:"bytes,2,opt,name=site_fqdn,json=siteFqdn,proto3" json:"site_fqdn,omitempty"`
+	AdminFqdn    string                 `protobuf:"bytes,3,opt,name=admin_fqdn,json=adminFqdn,proto3" json:"admin_fqdn,omitempty"`
+	IsInTestMode bool                   `protobuf:"varint,4,opt,name=is_in_test_mode,json=isInTestMode,proto3" json:"is_in_test_mode,omitempty"`
+	// Applies to all sessions. Backends can add stricter limits.
+	SessionPolicy *SessionPolicy `protobuf:"bytes,6,opt,name=session_policy,json=sessionPolicy,proto3" json:"session_policy,omitempty"`
+	// If unset, no e-mails are sent.
+	Smtp          *SmtpSettings         `protobuf:"bytes,7,opt,name=smtp,proto3" json:"smtp,omitempty"`
+	Notifications *NotificationSettings `protobuf:"bytes,8,opt,name=notifications,proto3" json:"notifications,omitempty"`
+	BanPolicy     *BanPolicy            `protobuf:"bytes,9,opt,name=ban_policy,json=banPolicy,proto3" json:"ban_policy,omitempty"`
+	PasskeyPolicy *PasskeyPolicy        `protobuf:"bytes,10,opt,name=passkey_policy,json=passkeyPolicy,proto3" json:"passkey_policy,omitempty"`
+	Webauthn      *WebauthnSettings     `protobuf:"bytes,11,opt,name=webauthn,proto3" json:"webauthn,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Configuration) Reset() {
 	*x = Configuration{}
-	mi := &file_protos_configuration_proto_msgTypes[0]
+	mi := &file_protos_configuration_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -47,7 +555,7 @@ func (x *Configuration) String() string {
 func (*Configuration) ProtoMessage() {}
 
 func (x *Configuration) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_configuration_proto_msgTypes[0]
+	mi := &file_protos_configuration_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -60,7 +568,7 @@ func (x *Configuration) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Configuration.ProtoReflect.Descriptor instead.
 func (*Configuration) Descriptor() ([]byte, []int) {
-	return file_protos_configuration_proto_rawDescGZIP(), []int{0}
+	return file_protos_configuration_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *Configuration) GetEmail() string {
@@ -91,17 +599,108 @@ func (x *Configuration) GetIsInTestMode() bool {
 	return false
 }
 
+func (x *Configuration) GetSessionPolicy() *SessionPolicy {
+	if x != nil {
+		return x.SessionPolicy
+	}
+	return nil
+}
+
+func (x *Configuration) GetSmtp() *SmtpSettings {
+	if x != nil {
+		return x.Smtp
+	}
+	return nil
+}
+
+func (x *Configuration) GetNotifications() *NotificationSettings {
+	if x != nil {
+		return x.Notifications
+	}
+	return nil
+}
+
+func (x *Configuration) GetBanPolicy() *BanPolicy {
+	if x != nil {
+		return x.BanPolicy
+	}
+	return nil
+}
+
+func (x *Configuration) GetPasskeyPolicy() *PasskeyPolicy {
+	if x != nil {
+		return x.PasskeyPolicy
+	}
+	return nil
+}
+
+func (x *Configuration) GetWebauthn() *WebauthnSettings {
+	if x != nil {
+		return x.Webauthn
+	}
+	return nil
+}
+
 var File_protos_configuration_proto protoreflect.FileDescriptor
 
 const file_protos_configuration_proto_rawDesc = "" +
 	"\n" +
-	"\x1aprotos/configuration.proto\x12\x06models\"\x8e\x01\n" +
+	"\x1aprotos/configuration.proto\x12\x06models\x1a\x1egoogle/protobuf/duration.proto\x1a\x14protos/session.proto\"\xa5\x01\n" +
+	"\fSmtpSettings\x12\x12\n" +
+	"\x04host\x18\x01 \x01(\tR\x04host\x12\x12\n" +
+	"\x04port\x18\x02 \x01(\rR\x04port\x12\x1a\n" +
+	"\busername\x18\x03 \x01(\tR\busername\x12\x1a\n" +
+	"\bpassword\x18\x04 \x01(\tR\bpassword\x12\x12\n" +
+	"\x04from\x18\x05 \x01(\tR\x04from\x12!\n" +
+	"\fimplicit_tls\x18\x06 \x01(\bR\vimplicitTls\"\xbf\x01\n" +
+	"\x14NotificationSettings\x12\x1d\n" +
+	"\n" +
+	"email_user\x18\x01 \x01(\bR\temailUser\x12!\n" +
+	"\femail_admins\x18\x02 \x01(\bR\vemailAdmins\x12\x1f\n" +
+	"\vwebhook_url\x18\x03 \x01(\tR\n" +
+	"webhookUrl\x12%\n" +
+	"\x0ewebhook_secret\x18\x04 \x01(\tR\rwebhookSecret\x12\x1d\n" +
+	"\n" +
+	"mqtt_topic\x18\x05 \x01(\tR\tmqttTopic\"\xb0\x03\n" +
+	"\tBanPolicy\x12\x1a\n" +
+	"\bdisabled\x18\x01 \x01(\bR\bdisabled\x12!\n" +
+	"\fmax_failures\x18\x02 \x01(\rR\vmaxFailures\x12@\n" +
+	"\x0efailure_window\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\rfailureWindow\x12<\n" +
+	"\fban_duration\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\vbanDuration\x12C\n" +
+	"\x10max_ban_duration\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\x0emaxBanDuration\x12;\n" +
+	"\x1asignin_requests_per_minute\x18\x06 \x01(\rR\x17signinRequestsPerMinute\x129\n" +
+	"\x19token_requests_per_minute\x18\a \x01(\rR\x16tokenRequestsPerMinute\x12'\n" +
+	"\x0ftrusted_proxies\x18\b \x03(\tR\x0etrustedProxies\"\xba\x01\n" +
+	"\rPasskeyPolicy\x12'\n" +
+	"\x0fallowed_aaguids\x18\x01 \x03(\tR\x0eallowedAaguids\x12%\n" +
+	"\x0edenied_aaguids\x18\x02 \x03(\tR\rdeniedAaguids\x126\n" +
+	"\fclone_action\x18\x03 \x01(\x0e2\x13.models.CloneActionR\vcloneAction\x12!\n" +
+	"\fmin_passkeys\x18\x04 \x01(\rR\vminPasskeys\"\xd0\x01\n" +
+	"\x10WebauthnSettings\x12\x1e\n" +
+	"\n" +
+	"attachment\x18\x01 \x01(\tR\n" +
+	"attachment\x12+\n" +
+	"\x11user_verification\x18\x02 \x01(\tR\x10userVerification\x12!\n" +
+	"\fresident_key\x18\x03 \x01(\tR\vresidentKey\x12#\n" +
+	"\x0euse_site_rp_id\x18\x04 \x01(\bR\vuseSiteRpId\x12'\n" +
+	"\x0frelated_origins\x18\x05 \x01(\bR\x0erelatedOrigins\"\xe0\x03\n" +
 	"\rConfiguration\x12\x14\n" +
 	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1b\n" +
 	"\tsite_fqdn\x18\x02 \x01(\tR\bsiteFqdn\x12\x1d\n" +
 	"\n" +
 	"admin_fqdn\x18\x03 \x01(\tR\tadminFqdn\x12%\n" +
-	"\x0fis_in_test_mode\x18\x04 \x01(\bR\fisInTestModeJ\x04\b\x05\x10\x06B\x11Z\x0f./server/modelsb\x06proto3"
+	"\x0fis_in_test_mode\x18\x04 \x01(\bR\fisInTestMode\x12<\n" +
+	"\x0esession_policy\x18\x06 \x01(\v2\x15.models.SessionPolicyR\rsessionPolicy\x12(\n" +
+	"\x04smtp\x18\a \x01(\v2\x14.models.SmtpSettingsR\x04smtp\x12B\n" +
+	"\rnotifications\x18\b \x01(\v2\x1c.models.NotificationSettingsR\rnotifications\x120\n" +
+	"\n" +
+	"ban_policy\x18\t \x01(\v2\x11.models.BanPolicyR\tbanPolicy\x12<\n" +
+	"\x0epasskey_policy\x18\n" +
+	" \x01(\v2\x15.models.PasskeyPolicyR\rpasskeyPolicy\x124\n" +
+	"\bwebauthn\x18\v \x01(\v2\x18.models.WebauthnSettingsR\bwebauthnJ\x04\b\x05\x10\x06*<\n" +
+	"\vCloneAction\x12\x15\n" +
+	"\x11CLONE_ACTION_FLAG\x10\x00\x12\x16\n" +
+	"\x12CLONE_ACTION_BLOCK\x10\x01B\x11Z\x0f./server/modelsb\x06proto3"
 
 var (
 	file_protos_configuration_proto_rawDescOnce sync.Once
@@ -115,16 +714,35 @@ func file_protos_configuration_proto_rawDescGZIP() []byte {
 	return file_protos_configuration_proto_rawDescData
 }
 
-var file_protos_configuration_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_protos_configuration_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_protos_configuration_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
 var file_protos_configuration_proto_goTypes = []any{
-	(*Configuration)(nil), // 0: models.Configuration
+	(CloneAction)(0),             // 0: models.CloneAction
+	(*SmtpSettings)(nil),         // 1: models.SmtpSettings
+	(*NotificationSettings)(nil), // 2: models.NotificationSettings
+	(*BanPolicy)(nil),            // 3: models.BanPolicy
+	(*PasskeyPolicy)(nil),        // 4: models.PasskeyPolicy
+	(*WebauthnSettings)(nil),     // 5: models.WebauthnSettings
+	(*Configuration)(nil),        // 6: models.Configuration
+	(*durationpb.Duration)(nil),  // 7: google.protobuf.Duration
+	(*SessionPolicy)(nil),        // 8: models.SessionPolicy
 }
 var file_protos_configuration_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	7,  // 0: models.BanPolicy.failure_window:type_name -> google.protobuf.Duration
+	7,  // 1: models.BanPolicy.ban_duration:type_name -> google.protobuf.Duration
+	7,  // 2: models.BanPolicy.max_ban_duration:type_name -> google.protobuf.Duration
+	0,  // 3: models.PasskeyPolicy.clone_action:type_name -> models.CloneAction
+	8,  // 4: models.Configuration.session_policy:type_name -> models.SessionPolicy
+	1,  // 5: models.Configuration.smtp:type_name -> models.SmtpSettings
+	2,  // 6: models.Configuration.notifications:type_name -> models.NotificationSettings
+	3,  // 7: models.Configuration.ban_policy:type_name -> models.BanPolicy
+	4,  // 8: models.Configuration.passkey_policy:type_name -> models.PasskeyPolicy
+	5,  // 9: models.Configuration.webauthn:type_name -> models.WebauthnSettings
+	10, // [10:10] is the sub-list for method output_type
+	10, // [10:10] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_protos_configuration_proto_init() }
@@ -132,18 +750,20 @@ func file_protos_configuration_proto_init() {
 	if File_protos_configuration_proto != nil {
 		return
 	}
+	file_protos_session_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_protos_configuration_proto_rawDesc), len(file_protos_configuration_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   1,
+			NumEnums:      1,
+			NumMessages:   6,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_protos_configuration_proto_goTypes,
 		DependencyIndexes: file_protos_configuration_proto_depIdxs,
+		EnumInfos:         file_protos_configuration_proto_enumTypes,
 		MessageInfos:      file_protos_configuration_proto_msgTypes,
 	}.Build()
 	File_protos_configuration_proto = out.File
@@ -38,8 +38,11 @@ type WebAuthnCredential struct {
 	FlagUserVerified   bool `protobuf:"varint,9,opt,name=flag_user_verified,json=flagUserVerified,proto3" json:"flag_user_verified,omitempty"`
 	FlagBackupEligible bool `protobuf:"varint,10,opt,name=flag_backup_eligible,json=flagBackupEligible,proto3" json:"flag_backup_eligible,omitempty"`
 	FlagBackupState    bool `protobuf:"varint,11,opt,name=flag_backup_state,json=flagBackupState,proto3" json:"flag_backup_state,omitempty"`
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+	// The relying party ID that the passkey was created for. Passkeys created
+	// before this was recorded are for the admin FQDN, without port.
+	RpId          string `protobuf:"bytes,12,opt,name=rp_id,json=rpId,proto3" json:"rp_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *WebAuthnCredential) Reset() {
@@ -149,6 +152,86 @@ func (x *WebAuthnCredential) GetFlagBackupState() bool {
 	return false
 }
 
+func (x *WebAuthnCredential) GetRpId() string {
+	if x != nil {
+		return x.RpId
+	}
+	return ""
+}
+
+// A time-based one-time password (RFC 6238) generator, such as an
+// authenticator app.
+type TotpCredential struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Secret []byte                 `protobuf:"bytes,1,opt,name=secret,proto3" json:"secret,omitempty"`
+	// The last time step that a code was accepted for, so that codes can't be
+	// replayed.
+	LastUsedStep int64 `protobuf:"varint,2,opt,name=last_used_step,json=lastUsedStep,proto3" json:"last_used_step,omitempty"`
+	// Failed attempts since the last successful one, for rate limiting.
+	FailedAttempts uint32                 `protobuf:"varint,3,opt,name=failed_attempts,json=failedAttempts,proto3" json:"failed_attempts,omitempty"`
+	BlockedUntil   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=blocked_until,json=blockedUntil,proto3" json:"blocked_until,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *TotpCredential) Reset() {
+	*x = TotpCredential{}
+	mi := &file_protos_credential_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TotpCredential) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TotpCredential) ProtoMessage() {}
+
+func (x *TotpCredential) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_credential_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TotpCredential.ProtoReflect.Descriptor instead.
+func (*TotpCredential) Descriptor() ([]byte, []int) {
+	return file_protos_credential_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TotpCredential) GetSecret() []byte {
+	if x != nil {
+		return x.Secret
+	}
+	return nil
+}
+
+func (x *TotpCredential) GetLastUsedStep() int64 {
+	if x != nil {
+		return x.LastUsedStep
+	}
+	return 0
+}
+
+func (x *TotpCredential) GetFailedAttempts() uint32 {
+	if x != nil {
+		return x.FailedAttempts
+	}
+	return 0
+}
+
+func (x *TotpCredential) GetBlockedUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.BlockedUntil
+	}
+	return nil
+}
+
 // Ref: "cred:$id" -> Credential
 // Ref: "user:$user_id:cred:$id" -> []
 type Credential struct {
@@ -163,6 +246,7 @@ type Credential struct {
 	// Types that are valid to be assigned to Type:
 	//
 	//	*Credential_WebauthnCredential
+	//	*Credential_TotpCredential
 	Type          isCredential_Type `protobuf_oneof:"type"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -170,7 +254,7 @@ type Credential struct {
 
 func (x *Credential) Reset() {
 	*x = Credential{}
-	mi := &file_protos_credential_proto_msgTypes[1]
+	mi := &file_protos_credential_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -182,7 +266,7 @@ func (x *Credential) String() string {
 func (*Credential) ProtoMessage() {}
 
 func (x *Credential) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_credential_proto_msgTypes[1]
+	mi := &file_protos_credential_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -195,7 +279,7 @@ func (x *Credential) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Credential.ProtoReflect.Descriptor instead.
 func (*Credential) Descriptor() ([]byte, []int) {
-	return file_protos_credential_proto_rawDescGZIP(), []int{1}
+	return file_protos_credential_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *Credential) GetId() string {
@@ -263,6 +347,15 @@ func (x *Credential) GetWebauthnCredential() *WebAuthnCredential {
 	return nil
 }
 
+func (x *Credential) GetTotpCredential() *TotpCredential {
+	if x != nil {
+		if x, ok := x.Type.(*Credential_TotpCredential); ok {
+			return x.TotpCredential
+		}
+	}
+	return nil
+}
+
 type isCredential_Type interface {
 	isCredential_Type()
 }
@@ -271,13 +364,19 @@ type Credential_WebauthnCredential struct {
 	WebauthnCredential *WebAuthnCredential `protobuf:"bytes,5,opt,name=webauthn_credential,json=webauthnCredential,proto3,oneof"`
 }
 
+type Credential_TotpCredential struct {
+	TotpCredential *TotpCredential `protobuf:"bytes,9,opt,name=totp_credential,json=totpCredential,proto3,oneof"`
+}
+
 func (*Credential_WebauthnCredential) isCredential_Type() {}
 
+func (*Credential_TotpCredential) isCredential_Type() {}
+
 var File_protos_credential_proto protoreflect.FileDescriptor
 
 const file_protos_credential_proto_rawDesc = "" +
 	"\n" +
-	"\x17protos/credential.proto\x12\x06models\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb3\x03\n" +
+	"\x17protos/credential.proto\x12\x06models\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc8\x03\n" +
 	"\x12WebAuthnCredential\x12#\n" +
 	"\rcredential_id\x18\x01 \x01(\fR\fcredentialId\x12$\n" +
 	"\x0epublic_key_der\x18\x02 \x01(\fR\fpublicKeyDer\x12\x1e\n" +
@@ -295,7 +394,13 @@ const file_protos_credential_proto_rawDesc = "" +
 	"\x12flag_user_verified\x18\t \x01(\bR\x10flagUserVerified\x120\n" +
 	"\x14flag_backup_eligible\x18\n" +
 	" \x01(\bR\x12flagBackupEligible\x12*\n" +
-	"\x11flag_backup_state\x18\v \x01(\bR\x0fflagBackupState\"\xfb\x02\n" +
+	"\x11flag_backup_state\x18\v \x01(\bR\x0fflagBackupState\x12\x13\n" +
+	"\x05rp_id\x18\f \x01(\tR\x04rpId\"\xb8\x01\n" +
+	"\x0eTotpCredential\x12\x16\n" +
+	"\x06secret\x18\x01 \x01(\fR\x06secret\x12$\n" +
+	"\x0elast_used_step\x18\x02 \x01(\x03R\flastUsedStep\x12'\n" +
+	"\x0ffailed_attempts\x18\x03 \x01(\rR\x0efailedAttempts\x12?\n" +
+	"\rblocked_until\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\fblockedUntil\"\xbe\x03\n" +
 	"\n" +
 	"Credential\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
@@ -307,7 +412,8 @@ const file_protos_credential_proto_rawDesc = "" +
 	"\flast_used_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\n" +
 	"lastUsedAt\x12-\n" +
 	"\x13used_by_session_ids\x18\b \x03(\tR\x10usedBySessionIds\x12M\n" +
-	"\x13webauthn_credential\x18\x05 \x01(\v2\x1a.models.WebAuthnCredentialH\x00R\x12webauthnCredentialB\x06\n" +
+	"\x13webauthn_credential\x18\x05 \x01(\v2\x1a.models.WebAuthnCredentialH\x00R\x12webauthnCredential\x12A\n" +
+	"\x0ftotp_credential\x18\t \x01(\v2\x16.models.TotpCredentialH\x00R\x0etotpCredentialB\x06\n" +
 	"\x04typeB\x11Z\x0f./server/modelsb\x06proto3"
 
 var (
@@ -322,21 +428,24 @@ func file_protos_credential_proto_rawDescGZIP() []byte {
 	return file_protos_credential_proto_rawDescData
 }
 
-var file_protos_credential_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_protos_credential_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_protos_credential_proto_goTypes = []any{
 	(*WebAuthnCredential)(nil),    // 0: models.WebAuthnCredential
-	(*Credential)(nil),            // 1: models.Credential
-	(*timestamppb.Timestamp)(nil), // 2: google.protobuf.Timestamp
+	(*TotpCredential)(nil),        // 1: models.TotpCredential
+	(*Credential)(nil),            // 2: models.Credential
+	(*timestamppb.Timestamp)(nil), // 3: google.protobuf.Timestamp
 }
 var file_protos_credential_proto_depIdxs = []int32{
-	2, // 0: models.Credential.created_at:type_name -> google.protobuf.Timestamp
-	2, // 1: models.Credential.last_used_at:type_name -> google.protobuf.Timestamp
-	0, // 2: models.Credential.webauthn_credential:type_name -> models.WebAuthnCredential
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	3, // 0: models.TotpCredential.blocked_until:type_name -> google.protobuf.Timestamp
+	3, // 1: models.Credential.created_at:type_name -> google.protobuf.Timestamp
+	3, // 2: models.Credential.last_used_at:type_name -> google.protobuf.Timestamp
+	0, // 3: models.Credential.webauthn_credential:type_name -> models.WebAuthnCredential
+	1, // 4: models.Credential.totp_credential:type_name -> models.TotpCredential
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_protos_credential_proto_init() }
@@ -344,8 +453,9 @@ func file_protos_credential_proto_init() {
 	if File_protos_credential_proto != nil {
 		return
 	}
-	file_protos_credential_proto_msgTypes[1].OneofWrappers = []any{
+	file_protos_credential_proto_msgTypes[2].OneofWrappers = []any{
 		(*Credential_WebauthnCredential)(nil),
+		(*Credential_TotpCredential)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -353,7 +463,7 @@ func file_protos_credential_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_protos_credential_proto_rawDesc), len(file_protos_credential_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,261 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        v5.29.3
+// source: protos/device_authorization.proto
+
+package models
+
+import (
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// An OAuth 2.0 device authorization request, see RFC 8628. The device code
+// given to the client is formatted as "$id_$secret".
+// Ref: "device-auth:$id" -> DeviceAuthorization
+// Ref: "device-user-code:$user_code" -> $id
+type DeviceAuthorization struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// SHA-256 hash of the secret part of the device code.
+	HashedSecret []byte `protobuf:"bytes,2,opt,name=hashed_secret,json=hashedSecret,proto3" json:"hashed_secret,omitempty"`
+	// The code that the user enters, without any separators.
+	UserCode  string                 `protobuf:"bytes,3,opt,name=user_code,json=userCode,proto3" json:"user_code,omitempty"`
+	ClientId  string                 `protobuf:"bytes,4,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	Scopes    []string               `protobuf:"bytes,5,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ExpiresAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// The minimum number of seconds between polls, which increases if the
+	// client polls too often.
+	IntervalSeconds int32                  `protobuf:"varint,8,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"`
+	LastPolledAt    *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=last_polled_at,json=lastPolledAt,proto3" json:"last_polled_at,omitempty"`
+	// The requesting device, as shown to the user.
+	Ip        string `protobuf:"bytes,10,opt,name=ip,proto3" json:"ip,omitempty"`
+	UserAgent string `protobuf:"bytes,11,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	// Set when the user has approved the request.
+	UserId        string                 `protobuf:"bytes,12,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ApprovedAt    *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=approved_at,json=approvedAt,proto3" json:"approved_at,omitempty"`
+	Denied        bool                   `protobuf:"varint,14,opt,name=denied,proto3" json:"denied,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeviceAuthorization) Reset() {
+	*x = DeviceAuthorization{}
+	mi := &file_protos_device_authorization_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeviceAuthorization) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeviceAuthorization) ProtoMessage() {}
+
+func (x *DeviceAuthorization) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_device_authorization_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeviceAuthorization.ProtoReflect.Descriptor instead.
+func (*DeviceAuthorization) Descriptor() ([]byte, []int) {
+	return file_protos_device_authorization_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DeviceAuthorization) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeviceAuthorization) GetHashedSecret() []byte {
+	if x != nil {
+		return x.HashedSecret
+	}
+	return nil
+}
+
+func (x *DeviceAuthorization) GetUserCode() string {
+	if x != nil {
+		return x.UserCode
+	}
+	return ""
+}
+
+func (x *DeviceAuthorization) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *DeviceAuthorization) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *DeviceAuthorization) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *DeviceAuthorization) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *DeviceAuthorization) GetIntervalSeconds() int32 {
+	if x != nil {
+		return x.IntervalSeconds
+	}
+	return 0
+}
+
+func (x *DeviceAuthorization) GetLastPolledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastPolledAt
+	}
+	return nil
+}
+
+func (x *DeviceAuthorization) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *DeviceAuthorization) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *DeviceAuthorization) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *DeviceAuthorization) GetApprovedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ApprovedAt
+	}
+	return nil
+}
+
+func (x *DeviceAuthorization) GetDenied() bool {
+	if x != nil {
+		return x.Denied
+	}
+	return false
+}
+
+var File_protos_device_authorization_proto protoreflect.FileDescriptor
+
+const file_protos_device_authorization_proto_rawDesc = "" +
+	"\n" +
+	"!protos/device_authorization.proto\x12\x06models\x1a\x1fgoogle/protobuf/timestamp.proto\"\x9c\x04\n" +
+	"\x13DeviceAuthorization\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12#\n" +
+	"\rhashed_secret\x18\x02 \x01(\fR\fhashedSecret\x12\x1b\n" +
+	"\tuser_code\x18\x03 \x01(\tR\buserCode\x12\x1b\n" +
+	"\tclient_id\x18\x04 \x01(\tR\bclientId\x12\x16\n" +
+	"\x06scopes\x18\x05 \x03(\tR\x06scopes\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"expires_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12)\n" +
+	"\x10interval_seconds\x18\b \x01(\x05R\x0fintervalSeconds\x12@\n" +
+	"\x0elast_polled_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\flastPolledAt\x12\x0e\n" +
+	"\x02ip\x18\n" +
+	" \x01(\tR\x02ip\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\v \x01(\tR\tuserAgent\x12\x17\n" +
+	"\auser_id\x18\f \x01(\tR\x06userId\x12;\n" +
+	"\vapproved_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"approvedAt\x12\x16\n" +
+	"\x06denied\x18\x0e \x01(\bR\x06deniedB\x11Z\x0f./server/modelsb\x06proto3"
+
+var (
+	file_protos_device_authorization_proto_rawDescOnce sync.Once
+	file_protos_device_authorization_proto_rawDescData []byte
+)
+
+func file_protos_device_authorization_proto_rawDescGZIP() []byte {
+	file_protos_device_authorization_proto_rawDescOnce.Do(func() {
+		file_protos_device_authorization_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_protos_device_authorization_proto_rawDesc), len(file_protos_device_authorization_proto_rawDesc)))
+	})
+	return file_protos_device_authorization_proto_rawDescData
+}
+
+var file_protos_device_authorization_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_protos_device_authorization_proto_goTypes = []any{
+	(*DeviceAuthorization)(nil),   // 0: models.DeviceAuthorization
+	(*timestamppb.Timestamp)(nil), // 1: google.protobuf.Timestamp
+}
+var file_protos_device_authorization_proto_depIdxs = []int32{
+	1, // 0: models.DeviceAuthorization.created_at:type_name -> google.protobuf.Timestamp
+	1, // 1: models.DeviceAuthorization.expires_at:type_name -> google.protobuf.Timestamp
+	1, // 2: models.DeviceAuthorization.last_polled_at:type_name -> google.protobuf.Timestamp
+	1, // 3: models.DeviceAuthorization.approved_at:type_name -> google.protobuf.Timestamp
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_protos_device_authorization_proto_init() }
+func file_protos_device_authorization_proto_init() {
+	if File_protos_device_authorization_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_protos_device_authorization_proto_rawDesc), len(file_protos_device_authorization_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_protos_device_authorization_proto_goTypes,
+		DependencyIndexes: file_protos_device_authorization_proto_depIdxs,
+		MessageInfos:      file_protos_device_authorization_proto_msgTypes,
+	}.Build()
+	File_protos_device_authorization_proto = out.File
+	file_protos_device_authorization_proto_goTypes = nil
+	file_protos_device_authorization_proto_depIdxs = nil
+}
@@ -0,0 +1,200 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        v5.29.3
+// source: protos/oidc_client.proto
+
+package models
+
+import (
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// An application that uses ubergang as its OpenID Connect provider. The ID is
+// used as OAuth client ID.
+// Ref: "oidc-client:$id" -> OidcClient
+type OidcClient struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Hashed using bcrypt. Empty for public clients.
+	HashedClientSecret string `protobuf:"bytes,3,opt,name=hashed_client_secret,json=hashedClientSecret,proto3" json:"hashed_client_secret,omitempty"`
+	// Exact redirect URIs that authorization responses may be sent to.
+	RedirectUris []string `protobuf:"bytes,4,rep,name=redirect_uris,json=redirectUris,proto3" json:"redirect_uris,omitempty"`
+	// Public clients can't keep a secret, and must use PKCE instead.
+	IsPublic bool `protobuf:"varint,5,opt,name=is_public,json=isPublic,proto3" json:"is_public,omitempty"`
+	// If set, only members of any of these groups can sign in to the client.
+	AllowedGroups []string               `protobuf:"bytes,6,rep,name=allowed_groups,json=allowedGroups,proto3" json:"allowed_groups,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OidcClient) Reset() {
+	*x = OidcClient{}
+	mi := &file_protos_oidc_client_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OidcClient) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OidcClient) ProtoMessage() {}
+
+func (x *OidcClient) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_oidc_client_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OidcClient.ProtoReflect.Descriptor instead.
+func (*OidcClient) Descriptor() ([]byte, []int) {
+	return file_protos_oidc_client_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *OidcClient) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *OidcClient) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *OidcClient) GetHashedClientSecret() string {
+	if x != nil {
+		return x.HashedClientSecret
+	}
+	return ""
+}
+
+func (x *OidcClient) GetRedirectUris() []string {
+	if x != nil {
+		return x.RedirectUris
+	}
+	return nil
+}
+
+func (x *OidcClient) GetIsPublic() bool {
+	if x != nil {
+		return x.IsPublic
+	}
+	return false
+}
+
+func (x *OidcClient) GetAllowedGroups() []string {
+	if x != nil {
+		return x.AllowedGroups
+	}
+	return nil
+}
+
+func (x *OidcClient) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *OidcClient) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+var File_protos_oidc_client_proto protoreflect.FileDescriptor
+
+const file_protos_oidc_client_proto_rawDesc = "" +
+	"\n" +
+	"\x18protos/oidc_client.proto\x12\x06models\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc1\x02\n" +
+	"\n" +
+	"OidcClient\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x120\n" +
+	"\x14hashed_client_secret\x18\x03 \x01(\tR\x12hashedClientSecret\x12#\n" +
+	"\rredirect_uris\x18\x04 \x03(\tR\fredirectUris\x12\x1b\n" +
+	"\tis_public\x18\x05 \x01(\bR\bisPublic\x12%\n" +
+	"\x0eallowed_groups\x18\x06 \x03(\tR\rallowedGroups\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAtB\x11Z\x0f./server/modelsb\x06proto3"
+
+var (
+	file_protos_oidc_client_proto_rawDescOnce sync.Once
+	file_protos_oidc_client_proto_rawDescData []byte
+)
+
+func file_protos_oidc_client_proto_rawDescGZIP() []byte {
+	file_protos_oidc_client_proto_rawDescOnce.Do(func() {
+		file_protos_oidc_client_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_protos_oidc_client_proto_rawDesc), len(file_protos_oidc_client_proto_rawDesc)))
+	})
+	return file_protos_oidc_client_proto_rawDescData
+}
+
+var file_protos_oidc_client_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_protos_oidc_client_proto_goTypes = []any{
+	(*OidcClient)(nil),            // 0: models.OidcClient
+	(*timestamppb.Timestamp)(nil), // 1: google.protobuf.Timestamp
+}
+var file_protos_oidc_client_proto_depIdxs = []int32{
+	1, // 0: models.OidcClient.created_at:type_name -> google.protobuf.Timestamp
+	1, // 1: models.OidcClient.updated_at:type_name -> google.protobuf.Timestamp
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_protos_oidc_client_proto_init() }
+func file_protos_oidc_client_proto_init() {
+	if File_protos_oidc_client_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_protos_oidc_client_proto_rawDesc), len(file_protos_oidc_client_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_protos_oidc_client_proto_goTypes,
+		DependencyIndexes: file_protos_oidc_client_proto_depIdxs,
+		MessageInfos:      file_protos_oidc_client_proto_msgTypes,
+	}.Build()
+	File_protos_oidc_client_proto = out.File
+	file_protos_oidc_client_proto_goTypes = nil
+	file_protos_oidc_client_proto_depIdxs = nil
+}
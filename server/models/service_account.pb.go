@@ -0,0 +1,319 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        v5.29.3
+// source: protos/service_account.proto
+
+package models
+
+import (
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ServiceAccountSshKey struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// In authorized_keys format.
+	PublicKey string `protobuf:"bytes,1,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	// The SHA256 fingerprint of the `public_key`.
+	Sha256Fingerprint []byte `protobuf:"bytes,2,opt,name=sha256_fingerprint,json=sha256Fingerprint,proto3" json:"sha256_fingerprint,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ServiceAccountSshKey) Reset() {
+	*x = ServiceAccountSshKey{}
+	mi := &file_protos_service_account_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServiceAccountSshKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServiceAccountSshKey) ProtoMessage() {}
+
+func (x *ServiceAccountSshKey) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_service_account_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServiceAccountSshKey.ProtoReflect.Descriptor instead.
+func (*ServiceAccountSshKey) Descriptor() ([]byte, []int) {
+	return file_protos_service_account_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ServiceAccountSshKey) GetPublicKey() string {
+	if x != nil {
+		return x.PublicKey
+	}
+	return ""
+}
+
+func (x *ServiceAccountSshKey) GetSha256Fingerprint() []byte {
+	if x != nil {
+		return x.Sha256Fingerprint
+	}
+	return nil
+}
+
+// A non-human principal, used for machine-to-machine access. It has no
+// passkeys, and authenticates using its client secret or a JWT assertion
+// signed by its private key. The ID is used as OAuth client ID.
+// Ref: "svc-account:$id" -> ServiceAccount
+// Ref: "svc-ssh-fp:$fingerprint@b64" -> $id
+type ServiceAccount struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	// Hashed using bcrypt. Empty if client secrets can't be used.
+	HashedClientSecret string `protobuf:"bytes,4,opt,name=hashed_client_secret,json=hashedClientSecret,proto3" json:"hashed_client_secret,omitempty"`
+	// PEM-encoded public key, used to verify signed JWT assertions. Empty if
+	// assertions can't be used.
+	PublicKey    string   `protobuf:"bytes,5,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	AllowedHosts []string `protobuf:"bytes,6,rep,name=allowed_hosts,json=allowedHosts,proto3" json:"allowed_hosts,omitempty"`
+	// If set, the account can connect to MQTT using its ID and client secret,
+	// with the permissions of this profile.
+	MqttProfileId string                  `protobuf:"bytes,7,opt,name=mqtt_profile_id,json=mqttProfileId,proto3" json:"mqtt_profile_id,omitempty"`
+	MqttValues    map[string]string       `protobuf:"bytes,8,rep,name=mqtt_values,json=mqttValues,proto3" json:"mqtt_values,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	SshKeys       []*ServiceAccountSshKey `protobuf:"bytes,9,rep,name=ssh_keys,json=sshKeys,proto3" json:"ssh_keys,omitempty"`
+	CreatedAt     *timestamppb.Timestamp  `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp  `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	LastUsedAt    *timestamppb.Timestamp  `protobuf:"bytes,12,opt,name=last_used_at,json=lastUsedAt,proto3" json:"last_used_at,omitempty"`
+	// Disabled accounts can't authenticate, and the access tokens that were
+	// issued to them can't be used.
+	Disabled      bool `protobuf:"varint,13,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServiceAccount) Reset() {
+	*x = ServiceAccount{}
+	mi := &file_protos_service_account_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServiceAccount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServiceAccount) ProtoMessage() {}
+
+func (x *ServiceAccount) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_service_account_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServiceAccount.ProtoReflect.Descriptor instead.
+func (*ServiceAccount) Descriptor() ([]byte, []int) {
+	return file_protos_service_account_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ServiceAccount) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ServiceAccount) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ServiceAccount) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ServiceAccount) GetHashedClientSecret() string {
+	if x != nil {
+		return x.HashedClientSecret
+	}
+	return ""
+}
+
+func (x *ServiceAccount) GetPublicKey() string {
+	if x != nil {
+		return x.PublicKey
+	}
+	return ""
+}
+
+func (x *ServiceAccount) GetAllowedHosts() []string {
+	if x != nil {
+		return x.AllowedHosts
+	}
+	return nil
+}
+
+func (x *ServiceAccount) GetMqttProfileId() string {
+	if x != nil {
+		return x.MqttProfileId
+	}
+	return ""
+}
+
+func (x *ServiceAccount) GetMqttValues() map[string]string {
+	if x != nil {
+		return x.MqttValues
+	}
+	return nil
+}
+
+func (x *ServiceAccount) GetSshKeys() []*ServiceAccountSshKey {
+	if x != nil {
+		return x.SshKeys
+	}
+	return nil
+}
+
+func (x *ServiceAccount) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ServiceAccount) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *ServiceAccount) GetLastUsedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUsedAt
+	}
+	return nil
+}
+
+func (x *ServiceAccount) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+var File_protos_service_account_proto protoreflect.FileDescriptor
+
+const file_protos_service_account_proto_rawDesc = "" +
+	"\n" +
+	"\x1cprotos/service_account.proto\x12\x06models\x1a\x1fgoogle/protobuf/timestamp.proto\"d\n" +
+	"\x14ServiceAccountSshKey\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x01 \x01(\tR\tpublicKey\x12-\n" +
+	"\x12sha256_fingerprint\x18\x02 \x01(\fR\x11sha256Fingerprint\"\x85\x05\n" +
+	"\x0eServiceAccount\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x120\n" +
+	"\x14hashed_client_secret\x18\x04 \x01(\tR\x12hashedClientSecret\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x05 \x01(\tR\tpublicKey\x12#\n" +
+	"\rallowed_hosts\x18\x06 \x03(\tR\fallowedHosts\x12&\n" +
+	"\x0fmqtt_profile_id\x18\a \x01(\tR\rmqttProfileId\x12G\n" +
+	"\vmqtt_values\x18\b \x03(\v2&.models.ServiceAccount.MqttValuesEntryR\n" +
+	"mqttValues\x127\n" +
+	"\bssh_keys\x18\t \x03(\v2\x1c.models.ServiceAccountSshKeyR\asshKeys\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12<\n" +
+	"\flast_used_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"lastUsedAt\x12\x1a\n" +
+	"\bdisabled\x18\r \x01(\bR\bdisabled\x1a=\n" +
+	"\x0fMqttValuesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\x11Z\x0f./server/modelsb\x06proto3"
+
+var (
+	file_protos_service_account_proto_rawDescOnce sync.Once
+	file_protos_service_account_proto_rawDescData []byte
+)
+
+func file_protos_service_account_proto_rawDescGZIP() []byte {
+	file_protos_service_account_proto_rawDescOnce.Do(func() {
+		file_protos_service_account_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_protos_service_account_proto_rawDesc), len(file_protos_service_account_proto_rawDesc)))
+	})
+	return file_protos_service_account_proto_rawDescData
+}
+
+var file_protos_service_account_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_protos_service_account_proto_goTypes = []any{
+	(*ServiceAccountSshKey)(nil),  // 0: models.ServiceAccountSshKey
+	(*ServiceAccount)(nil),        // 1: models.ServiceAccount
+	nil,                           // 2: models.ServiceAccount.MqttValuesEntry
+	(*timestamppb.Timestamp)(nil), // 3: google.protobuf.Timestamp
+}
+var file_protos_service_account_proto_depIdxs = []int32{
+	2, // 0: models.ServiceAccount.mqtt_values:type_name -> models.ServiceAccount.MqttValuesEntry
+	0, // 1: models.ServiceAccount.ssh_keys:type_name -> models.ServiceAccountSshKey
+	3, // 2: models.ServiceAccount.created_at:type_name -> google.protobuf.Timestamp
+	3, // 3: models.ServiceAccount.updated_at:type_name -> google.protobuf.Timestamp
+	3, // 4: models.ServiceAccount.last_used_at:type_name -> google.protobuf.Timestamp
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_protos_service_account_proto_init() }
+func file_protos_service_account_proto_init() {
+	if File_protos_service_account_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_protos_service_account_proto_rawDesc), len(file_protos_service_account_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_protos_service_account_proto_goTypes,
+		DependencyIndexes: file_protos_service_account_proto_depIdxs,
+		MessageInfos:      file_protos_service_account_proto_msgTypes,
+	}.Build()
+	File_protos_service_account_proto = out.File
+	file_protos_service_account_proto_goTypes = nil
+	file_protos_service_account_proto_depIdxs = nil
+}
@@ -1,7 +1,7 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.32.0
-// 	protoc        v4.25.2
+// 	protoc-gen-go v1.36.7
+// 	protoc        v5.29.3
 // source: protos/session.proto
 
 package models
@@ -9,9 +9,11 @@ package models
 import (
 	reflect "reflect"
 	sync "sync"
+	unsafe "unsafe"
 
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -22,28 +24,172 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Limits for how long a session can be used. An unset duration means that
+// there is no limit.
+type SessionPolicy struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Maximum time since the user last signed in.
+	AbsoluteLifetime *durationpb.Duration `protobuf:"bytes,1,opt,name=absolute_lifetime,json=absoluteLifetime,proto3" json:"absolute_lifetime,omitempty"`
+	// Maximum time since the session was last used.
+	IdleTimeout   *durationpb.Duration `protobuf:"bytes,2,opt,name=idle_timeout,json=idleTimeout,proto3" json:"idle_timeout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionPolicy) Reset() {
+	*x = SessionPolicy{}
+	mi := &file_protos_session_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionPolicy) ProtoMessage() {}
+
+func (x *SessionPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_session_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionPolicy.ProtoReflect.Descriptor instead.
+func (*SessionPolicy) Descriptor() ([]byte, []int) {
+	return file_protos_session_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SessionPolicy) GetAbsoluteLifetime() *durationpb.Duration {
+	if x != nil {
+		return x.AbsoluteLifetime
+	}
+	return nil
+}
+
+func (x *SessionPolicy) GetIdleTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.IdleTimeout
+	}
+	return nil
+}
+
+// A period during which a session was used from the same IP address and user
+// agent.
+type SessionAccess struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	FirstAccessedAt *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=first_accessed_at,json=firstAccessedAt,proto3" json:"first_accessed_at,omitempty"`
+	LastAccessedAt  *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=last_accessed_at,json=lastAccessedAt,proto3" json:"last_accessed_at,omitempty"`
+	RemoteAddr      string                 `protobuf:"bytes,3,opt,name=remote_addr,json=remoteAddr,proto3" json:"remote_addr,omitempty"`
+	UserAgent       string                 `protobuf:"bytes,4,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SessionAccess) Reset() {
+	*x = SessionAccess{}
+	mi := &file_protos_session_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionAccess) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionAccess) ProtoMessage() {}
+
+func (x *SessionAccess) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_session_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionAccess.ProtoReflect.Descriptor instead.
+func (*SessionAccess) Descriptor() ([]byte, []int) {
+	return file_protos_session_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SessionAccess) GetFirstAccessedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FirstAccessedAt
+	}
+	return nil
+}
+
+func (x *SessionAccess) GetLastAccessedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastAccessedAt
+	}
+	return nil
+}
+
+func (x *SessionAccess) GetRemoteAddr() string {
+	if x != nil {
+		return x.RemoteAddr
+	}
+	return ""
+}
+
+func (x *SessionAccess) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
 // Ref: "sess:$id" -> Session
 // Ref: "user:$user_id:sess:$id" -> []
 type Session struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
+	state      protoimpl.MessageState `protogen:"open.v1"`
 	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	UserId     string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	Secret     string                 `protobuf:"bytes,3,opt,name=secret,proto3" json:"secret,omitempty"`
 	UserAgent  string                 `protobuf:"bytes,4,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
 	RemoteAddr string                 `protobuf:"bytes,5,opt,name=remote_addr,json=remoteAddr,proto3" json:"remote_addr,omitempty"`
 	CreatedAt  *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// When the user last signed in using this session, by any method.
+	AuthenticatedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=authenticated_at,json=authenticatedAt,proto3" json:"authenticated_at,omitempty"`
+	// When the user last signed in using this session with a passkey assertion.
+	VerifiedAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=verified_at,json=verifiedAt,proto3" json:"verified_at,omitempty"`
+	AccessedAt *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=accessed_at,json=accessedAt,proto3" json:"accessed_at,omitempty"`
+	// The IP address and user agent that last used the session. `remote_addr`
+	// and `user_agent` are from when the user last signed in.
+	LastRemoteAddr string `protobuf:"bytes,10,opt,name=last_remote_addr,json=lastRemoteAddr,proto3" json:"last_remote_addr,omitempty"`
+	LastUserAgent  string `protobuf:"bytes,11,opt,name=last_user_agent,json=lastUserAgent,proto3" json:"last_user_agent,omitempty"`
+	// Most recent first, and bounded in size.
+	AccessHistory []*SessionAccess `protobuf:"bytes,12,rep,name=access_history,json=accessHistory,proto3" json:"access_history,omitempty"`
+	// Set if an administrator uses the session to view the site as the user.
+	// Such sessions can't be used to make changes.
+	ImpersonatorId string `protobuf:"bytes,13,opt,name=impersonator_id,json=impersonatorId,proto3" json:"impersonator_id,omitempty"`
+	// The administrator's own session, which is used again when they stop.
+	ImpersonatorSessionId string `protobuf:"bytes,14,opt,name=impersonator_session_id,json=impersonatorSessionId,proto3" json:"impersonator_session_id,omitempty"`
+	// If set, the session can't be used after this time.
+	ExpiresAt *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// When the session was last used for each backend, by FQDN. Backends with
+	// an idle timeout of their own are checked against these.
+	BackendAccessedAt map[string]*timestamppb.Timestamp `protobuf:"bytes,16,rep,name=backend_accessed_at,json=backendAccessedAt,proto3" json:"backend_accessed_at,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *Session) Reset() {
 	*x = Session{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_session_proto_msgTypes[0]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_protos_session_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *Session) String() string {
@@ -53,8 +199,8 @@ func (x *Session) String() string {
 func (*Session) ProtoMessage() {}
 
 func (x *Session) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_session_proto_msgTypes[0]
-	if protoimpl.UnsafeEnabled && x != nil {
+	mi := &file_protos_session_proto_msgTypes[2]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -66,7 +212,7 @@ func (x *Session) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Session.ProtoReflect.Descriptor instead.
 func (*Session) Descriptor() ([]byte, []int) {
-	return file_protos_session_proto_rawDescGZIP(), []int{0}
+	return file_protos_session_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *Session) GetId() string {
@@ -111,54 +257,158 @@ func (x *Session) GetCreatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
-var File_protos_session_proto protoreflect.FileDescriptor
+func (x *Session) GetAuthenticatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AuthenticatedAt
+	}
+	return nil
+}
 
-var file_protos_session_proto_rawDesc = []byte{
-	0x0a, 0x14, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2f, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x1a, 0x1f,
-	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f,
-	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22,
-	0xc5, 0x01, 0x0a, 0x07, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69,
-	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x75,
-	0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73,
-	0x65, 0x72, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x1d, 0x0a, 0x0a,
-	0x75, 0x73, 0x65, 0x72, 0x5f, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x09, 0x75, 0x73, 0x65, 0x72, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x72,
-	0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0a, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x41, 0x64, 0x64, 0x72, 0x12, 0x39, 0x0a, 0x0a,
-	0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
-	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72,
-	0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x42, 0x11, 0x5a, 0x0f, 0x2e, 0x2f, 0x73, 0x65, 0x72,
-	0x76, 0x65, 0x72, 0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x33,
+func (x *Session) GetVerifiedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.VerifiedAt
+	}
+	return nil
 }
 
+func (x *Session) GetAccessedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AccessedAt
+	}
+	return nil
+}
+
+func (x *Session) GetLastRemoteAddr() string {
+	if x != nil {
+		return x.LastRemoteAddr
+	}
+	return ""
+}
+
+func (x *Session) GetLastUserAgent() string {
+	if x != nil {
+		return x.LastUserAgent
+	}
+	return ""
+}
+
+func (x *Session) GetAccessHistory() []*SessionAccess {
+	if x != nil {
+		return x.AccessHistory
+	}
+	return nil
+}
+
+func (x *Session) GetImpersonatorId() string {
+	if x != nil {
+		return x.ImpersonatorId
+	}
+	return ""
+}
+
+func (x *Session) GetImpersonatorSessionId() string {
+	if x != nil {
+		return x.ImpersonatorSessionId
+	}
+	return ""
+}
+
+func (x *Session) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *Session) GetBackendAccessedAt() map[string]*timestamppb.Timestamp {
+	if x != nil {
+		return x.BackendAccessedAt
+	}
+	return nil
+}
+
+var File_protos_session_proto protoreflect.FileDescriptor
+
+const file_protos_session_proto_rawDesc = "" +
+	"\n" +
+	"\x14protos/session.proto\x12\x06models\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x95\x01\n" +
+	"\rSessionPolicy\x12F\n" +
+	"\x11absolute_lifetime\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\x10absoluteLifetime\x12<\n" +
+	"\fidle_timeout\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\vidleTimeout\"\xdd\x01\n" +
+	"\rSessionAccess\x12F\n" +
+	"\x11first_accessed_at\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x0ffirstAccessedAt\x12D\n" +
+	"\x10last_accessed_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x0elastAccessedAt\x12\x1f\n" +
+	"\vremote_addr\x18\x03 \x01(\tR\n" +
+	"remoteAddr\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\x04 \x01(\tR\tuserAgent\"\xec\x06\n" +
+	"\aSession\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06secret\x18\x03 \x01(\tR\x06secret\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\x04 \x01(\tR\tuserAgent\x12\x1f\n" +
+	"\vremote_addr\x18\x05 \x01(\tR\n" +
+	"remoteAddr\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12E\n" +
+	"\x10authenticated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\x0fauthenticatedAt\x12;\n" +
+	"\vverified_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"verifiedAt\x12;\n" +
+	"\vaccessed_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"accessedAt\x12(\n" +
+	"\x10last_remote_addr\x18\n" +
+	" \x01(\tR\x0elastRemoteAddr\x12&\n" +
+	"\x0flast_user_agent\x18\v \x01(\tR\rlastUserAgent\x12<\n" +
+	"\x0eaccess_history\x18\f \x03(\v2\x15.models.SessionAccessR\raccessHistory\x12'\n" +
+	"\x0fimpersonator_id\x18\r \x01(\tR\x0eimpersonatorId\x126\n" +
+	"\x17impersonator_session_id\x18\x0e \x01(\tR\x15impersonatorSessionId\x129\n" +
+	"\n" +
+	"expires_at\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12V\n" +
+	"\x13backend_accessed_at\x18\x10 \x03(\v2&.models.Session.BackendAccessedAtEntryR\x11backendAccessedAt\x1a`\n" +
+	"\x16BackendAccessedAtEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x120\n" +
+	"\x05value\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x05value:\x028\x01B\x11Z\x0f./server/modelsb\x06proto3"
+
 var (
 	file_protos_session_proto_rawDescOnce sync.Once
-	file_protos_session_proto_rawDescData = file_protos_session_proto_rawDesc
+	file_protos_session_proto_rawDescData []byte
 )
 
 func file_protos_session_proto_rawDescGZIP() []byte {
 	file_protos_session_proto_rawDescOnce.Do(func() {
-		file_protos_session_proto_rawDescData = protoimpl.X.CompressGZIP(file_protos_session_proto_rawDescData)
+		file_protos_session_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_protos_session_proto_rawDesc), len(file_protos_session_proto_rawDesc)))
 	})
 	return file_protos_session_proto_rawDescData
 }
 
-var file_protos_session_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
-var file_protos_session_proto_goTypes = []interface{}{
-	(*Session)(nil),               // 0: models.Session
-	(*timestamppb.Timestamp)(nil), // 1: google.protobuf.Timestamp
+var file_protos_session_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_protos_session_proto_goTypes = []any{
+	(*SessionPolicy)(nil),         // 0: models.SessionPolicy
+	(*SessionAccess)(nil),         // 1: models.SessionAccess
+	(*Session)(nil),               // 2: models.Session
+	nil,                           // 3: models.Session.BackendAccessedAtEntry
+	(*durationpb.Duration)(nil),   // 4: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil), // 5: google.protobuf.Timestamp
 }
 var file_protos_session_proto_depIdxs = []int32{
-	1, // 0: models.Session.created_at:type_name -> google.protobuf.Timestamp
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	4,  // 0: models.SessionPolicy.absolute_lifetime:type_name -> google.protobuf.Duration
+	4,  // 1: models.SessionPolicy.idle_timeout:type_name -> google.protobuf.Duration
+	5,  // 2: models.SessionAccess.first_accessed_at:type_name -> google.protobuf.Timestamp
+	5,  // 3: models.SessionAccess.last_accessed_at:type_name -> google.protobuf.Timestamp
+	5,  // 4: models.Session.created_at:type_name -> google.protobuf.Timestamp
+	5,  // 5: models.Session.authenticated_at:type_name -> google.protobuf.Timestamp
+	5,  // 6: models.Session.verified_at:type_name -> google.protobuf.Timestamp
+	5,  // 7: models.Session.accessed_at:type_name -> google.protobuf.Timestamp
+	1,  // 8: models.Session.access_history:type_name -> models.SessionAccess
+	5,  // 9: models.Session.expires_at:type_name -> google.protobuf.Timestamp
+	3,  // 10: models.Session.backend_accessed_at:type_name -> models.Session.BackendAccessedAtEntry
+	5,  // 11: models.Session.BackendAccessedAtEntry.value:type_name -> google.protobuf.Timestamp
+	12, // [12:12] is the sub-list for method output_type
+	12, // [12:12] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
 }
 
 func init() { file_protos_session_proto_init() }
@@ -166,27 +416,13 @@ func file_protos_session_proto_init() {
 	if File_protos_session_proto != nil {
 		return
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_protos_session_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Session); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_protos_session_proto_rawDesc,
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_protos_session_proto_rawDesc), len(file_protos_session_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   1,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -195,7 +431,6 @@ func file_protos_session_proto_init() {
 		MessageInfos:      file_protos_session_proto_msgTypes,
 	}.Build()
 	File_protos_session_proto = out.File
-	file_protos_session_proto_rawDesc = nil
 	file_protos_session_proto_goTypes = nil
 	file_protos_session_proto_depIdxs = nil
 }
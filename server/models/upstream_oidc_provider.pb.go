@@ -0,0 +1,200 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        v5.29.3
+// source: protos/upstream_oidc_provider.proto
+
+package models
+
+import (
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// An external OpenID Connect identity provider that users can sign in with.
+// Ref: "upstream-oidc:$id" -> UpstreamOidcProvider
+type UpstreamOidcProvider struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Shown on the sign-in page.
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// The issuer URL, used for discovery.
+	Issuer   string `protobuf:"bytes,3,opt,name=issuer,proto3" json:"issuer,omitempty"`
+	ClientId string `protobuf:"bytes,4,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	// Needed in plain text to authenticate to the provider.
+	ClientSecret string `protobuf:"bytes,5,opt,name=client_secret,json=clientSecret,proto3" json:"client_secret,omitempty"`
+	// If set, an external identity with a verified e-mail address is linked to
+	// the user with the same e-mail address on first sign-in. Otherwise, users
+	// must link their external identity explicitly.
+	LinkByEmail   bool                   `protobuf:"varint,6,opt,name=link_by_email,json=linkByEmail,proto3" json:"link_by_email,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpstreamOidcProvider) Reset() {
+	*x = UpstreamOidcProvider{}
+	mi := &file_protos_upstream_oidc_provider_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpstreamOidcProvider) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpstreamOidcProvider) ProtoMessage() {}
+
+func (x *UpstreamOidcProvider) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_upstream_oidc_provider_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpstreamOidcProvider.ProtoReflect.Descriptor instead.
+func (*UpstreamOidcProvider) Descriptor() ([]byte, []int) {
+	return file_protos_upstream_oidc_provider_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *UpstreamOidcProvider) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpstreamOidcProvider) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpstreamOidcProvider) GetIssuer() string {
+	if x != nil {
+		return x.Issuer
+	}
+	return ""
+}
+
+func (x *UpstreamOidcProvider) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *UpstreamOidcProvider) GetClientSecret() string {
+	if x != nil {
+		return x.ClientSecret
+	}
+	return ""
+}
+
+func (x *UpstreamOidcProvider) GetLinkByEmail() bool {
+	if x != nil {
+		return x.LinkByEmail
+	}
+	return false
+}
+
+func (x *UpstreamOidcProvider) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *UpstreamOidcProvider) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+var File_protos_upstream_oidc_provider_proto protoreflect.FileDescriptor
+
+const file_protos_upstream_oidc_provider_proto_rawDesc = "" +
+	"\n" +
+	"#protos/upstream_oidc_provider.proto\x12\x06models\x1a\x1fgoogle/protobuf/timestamp.proto\"\xae\x02\n" +
+	"\x14UpstreamOidcProvider\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06issuer\x18\x03 \x01(\tR\x06issuer\x12\x1b\n" +
+	"\tclient_id\x18\x04 \x01(\tR\bclientId\x12#\n" +
+	"\rclient_secret\x18\x05 \x01(\tR\fclientSecret\x12\"\n" +
+	"\rlink_by_email\x18\x06 \x01(\bR\vlinkByEmail\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAtB\x11Z\x0f./server/modelsb\x06proto3"
+
+var (
+	file_protos_upstream_oidc_provider_proto_rawDescOnce sync.Once
+	file_protos_upstream_oidc_provider_proto_rawDescData []byte
+)
+
+func file_protos_upstream_oidc_provider_proto_rawDescGZIP() []byte {
+	file_protos_upstream_oidc_provider_proto_rawDescOnce.Do(func() {
+		file_protos_upstream_oidc_provider_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_protos_upstream_oidc_provider_proto_rawDesc), len(file_protos_upstream_oidc_provider_proto_rawDesc)))
+	})
+	return file_protos_upstream_oidc_provider_proto_rawDescData
+}
+
+var file_protos_upstream_oidc_provider_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_protos_upstream_oidc_provider_proto_goTypes = []any{
+	(*UpstreamOidcProvider)(nil),  // 0: models.UpstreamOidcProvider
+	(*timestamppb.Timestamp)(nil), // 1: google.protobuf.Timestamp
+}
+var file_protos_upstream_oidc_provider_proto_depIdxs = []int32{
+	1, // 0: models.UpstreamOidcProvider.created_at:type_name -> google.protobuf.Timestamp
+	1, // 1: models.UpstreamOidcProvider.updated_at:type_name -> google.protobuf.Timestamp
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_protos_upstream_oidc_provider_proto_init() }
+func file_protos_upstream_oidc_provider_proto_init() {
+	if File_protos_upstream_oidc_provider_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_protos_upstream_oidc_provider_proto_rawDesc), len(file_protos_upstream_oidc_provider_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_protos_upstream_oidc_provider_proto_goTypes,
+		DependencyIndexes: file_protos_upstream_oidc_provider_proto_depIdxs,
+		MessageInfos:      file_protos_upstream_oidc_provider_proto_msgTypes,
+	}.Build()
+	File_protos_upstream_oidc_provider_proto = out.File
+	file_protos_upstream_oidc_provider_proto_goTypes = nil
+	file_protos_upstream_oidc_provider_proto_depIdxs = nil
+}
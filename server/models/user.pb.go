@@ -23,14 +23,71 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// How a user may use one-time passwords (TOTP) to sign in.
+type TotpPolicy int32
+
+const (
+	// One-time passwords can't be used.
+	TotpPolicy_TOTP_POLICY_DISABLED TotpPolicy = 0
+	// One-time passwords can be used instead of a passkey.
+	TotpPolicy_TOTP_POLICY_FALLBACK TotpPolicy = 1
+	// A one-time password is required in addition to a passkey or an upstream
+	// OIDC provider.
+	TotpPolicy_TOTP_POLICY_REQUIRED TotpPolicy = 2
+)
+
+// Enum value maps for TotpPolicy.
+var (
+	TotpPolicy_name = map[int32]string{
+		0: "TOTP_POLICY_DISABLED",
+		1: "TOTP_POLICY_FALLBACK",
+		2: "TOTP_POLICY_REQUIRED",
+	}
+	TotpPolicy_value = map[string]int32{
+		"TOTP_POLICY_DISABLED": 0,
+		"TOTP_POLICY_FALLBACK": 1,
+		"TOTP_POLICY_REQUIRED": 2,
+	}
+)
+
+func (x TotpPolicy) Enum() *TotpPolicy {
+	p := new(TotpPolicy)
+	*p = x
+	return p
+}
+
+func (x TotpPolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TotpPolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_protos_user_proto_enumTypes[0].Descriptor()
+}
+
+func (TotpPolicy) Type() protoreflect.EnumType {
+	return &file_protos_user_proto_enumTypes[0]
+}
+
+func (x TotpPolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TotpPolicy.Descriptor instead.
+func (TotpPolicy) EnumDescriptor() ([]byte, []int) {
+	return file_protos_user_proto_rawDescGZIP(), []int{0}
+}
+
 type SigninRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Pin           string                 `protobuf:"bytes,2,opt,name=pin,proto3" json:"pin,omitempty"`
-	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
-	Confirmed     bool                   `protobuf:"varint,4,opt,name=confirmed,proto3" json:"confirmed,omitempty"`
-	UserAgent     string                 `protobuf:"bytes,5,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
-	Ip            string                 `protobuf:"bytes,6,opt,name=ip,proto3" json:"ip,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Pin       string                 `protobuf:"bytes,2,opt,name=pin,proto3" json:"pin,omitempty"`
+	ExpiresAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Confirmed bool                   `protobuf:"varint,4,opt,name=confirmed,proto3" json:"confirmed,omitempty"`
+	UserAgent string                 `protobuf:"bytes,5,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	Ip        string                 `protobuf:"bytes,6,opt,name=ip,proto3" json:"ip,omitempty"`
+	// Removed once used, e.g. for links that are sent by e-mail.
+	OneTime       bool                   `protobuf:"varint,7,opt,name=one_time,json=oneTime,proto3" json:"one_time,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -107,8 +164,102 @@ func (x *SigninRequest) GetIp() string {
 	return ""
 }
 
+func (x *SigninRequest) GetOneTime() bool {
+	if x != nil {
+		return x.OneTime
+	}
+	return false
+}
+
+func (x *SigninRequest) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// An identity at an upstream OIDC provider that is linked to a user.
+type FederatedIdentity struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	ProviderId string                 `protobuf:"bytes,1,opt,name=provider_id,json=providerId,proto3" json:"provider_id,omitempty"`
+	// The `sub` claim, which is unique per provider.
+	Subject string `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
+	// The e-mail address reported by the provider, for display only.
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	LinkedAt      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=linked_at,json=linkedAt,proto3" json:"linked_at,omitempty"`
+	LastUsedAt    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=last_used_at,json=lastUsedAt,proto3" json:"last_used_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FederatedIdentity) Reset() {
+	*x = FederatedIdentity{}
+	mi := &file_protos_user_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FederatedIdentity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FederatedIdentity) ProtoMessage() {}
+
+func (x *FederatedIdentity) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_user_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FederatedIdentity.ProtoReflect.Descriptor instead.
+func (*FederatedIdentity) Descriptor() ([]byte, []int) {
+	return file_protos_user_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *FederatedIdentity) GetProviderId() string {
+	if x != nil {
+		return x.ProviderId
+	}
+	return ""
+}
+
+func (x *FederatedIdentity) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *FederatedIdentity) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *FederatedIdentity) GetLinkedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LinkedAt
+	}
+	return nil
+}
+
+func (x *FederatedIdentity) GetLastUsedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUsedAt
+	}
+	return nil
+}
+
 // Ref: user:$id -> User
 // Ref: email:$email -> $id
+// Ref: fed-identity:$provider_id:$subject -> $id
 type User struct {
 	state        protoimpl.MessageState `protogen:"open.v1"`
 	Id           string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -116,6 +267,17 @@ type User struct {
 	DisplayName  string                 `protobuf:"bytes,3,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
 	AllowedHosts []string               `protobuf:"bytes,4,rep,name=allowed_hosts,json=allowedHosts,proto3" json:"allowed_hosts,omitempty"`
 	IsAdmin      bool                   `protobuf:"varint,6,opt,name=is_admin,json=isAdmin,proto3" json:"is_admin,omitempty"`
+	// Groups that the user has been assigned by administrators, by name. The
+	// user is also a member of the groups in `group_ids`.
+	Groups              []string             `protobuf:"bytes,7,rep,name=groups,proto3" json:"groups,omitempty"`
+	FederatedIdentities []*FederatedIdentity `protobuf:"bytes,8,rep,name=federated_identities,json=federatedIdentities,proto3" json:"federated_identities,omitempty"`
+	// Disabled users can't sign in, and their sessions and tokens are invalid.
+	IsDisabled bool `protobuf:"varint,9,opt,name=is_disabled,json=isDisabled,proto3" json:"is_disabled,omitempty"`
+	// The identifier used by the SCIM client that provisioned the user.
+	ExternalId string     `protobuf:"bytes,10,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"`
+	TotpPolicy TotpPolicy `protobuf:"varint,11,opt,name=totp_policy,json=totpPolicy,proto3,enum=models.TotpPolicy" json:"totp_policy,omitempty"`
+	// IDs of the provisioned groups (`Group.id`) that the user is a member of.
+	GroupIds []string `protobuf:"bytes,12,rep,name=group_ids,json=groupIds,proto3" json:"group_ids,omitempty"`
 	// Signin requests, max 10 per 10 minutes.
 	SigninRequests []*SigninRequest `protobuf:"bytes,5,rep,name=signin_requests,json=signinRequests,proto3" json:"signin_requests,omitempty"`
 	unknownFields  protoimpl.UnknownFields
@@ -124,7 +286,7 @@ type User struct {
 
 func (x *User) Reset() {
 	*x = User{}
-	mi := &file_protos_user_proto_msgTypes[1]
+	mi := &file_protos_user_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -136,7 +298,7 @@ func (x *User) String() string {
 func (*User) ProtoMessage() {}
 
 func (x *User) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_user_proto_msgTypes[1]
+	mi := &file_protos_user_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -149,7 +311,7 @@ func (x *User) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use User.ProtoReflect.Descriptor instead.
 func (*User) Descriptor() ([]byte, []int) {
-	return file_protos_user_proto_rawDescGZIP(), []int{1}
+	return file_protos_user_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *User) GetId() string {
@@ -187,6 +349,48 @@ func (x *User) GetIsAdmin() bool {
 	return false
 }
 
+func (x *User) GetGroups() []string {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+func (x *User) GetFederatedIdentities() []*FederatedIdentity {
+	if x != nil {
+		return x.FederatedIdentities
+	}
+	return nil
+}
+
+func (x *User) GetIsDisabled() bool {
+	if x != nil {
+		return x.IsDisabled
+	}
+	return false
+}
+
+func (x *User) GetExternalId() string {
+	if x != nil {
+		return x.ExternalId
+	}
+	return ""
+}
+
+func (x *User) GetTotpPolicy() TotpPolicy {
+	if x != nil {
+		return x.TotpPolicy
+	}
+	return TotpPolicy_TOTP_POLICY_DISABLED
+}
+
+func (x *User) GetGroupIds() []string {
+	if x != nil {
+		return x.GroupIds
+	}
+	return nil
+}
+
 func (x *User) GetSigninRequests() []*SigninRequest {
 	if x != nil {
 		return x.SigninRequests
@@ -198,7 +402,7 @@ var File_protos_user_proto protoreflect.FileDescriptor
 
 const file_protos_user_proto_rawDesc = "" +
 	"\n" +
-	"\x11protos/user.proto\x12\x06models\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb9\x01\n" +
+	"\x11protos/user.proto\x12\x06models\x1a\x1fgoogle/protobuf/timestamp.proto\"\x8f\x02\n" +
 	"\rSigninRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
 	"\x03pin\x18\x02 \x01(\tR\x03pin\x129\n" +
@@ -207,14 +411,40 @@ const file_protos_user_proto_rawDesc = "" +
 	"\tconfirmed\x18\x04 \x01(\bR\tconfirmed\x12\x1d\n" +
 	"\n" +
 	"user_agent\x18\x05 \x01(\tR\tuserAgent\x12\x0e\n" +
-	"\x02ip\x18\x06 \x01(\tR\x02ip\"\xcf\x01\n" +
+	"\x02ip\x18\x06 \x01(\tR\x02ip\x12\x19\n" +
+	"\bone_time\x18\a \x01(\bR\aoneTime\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xdb\x01\n" +
+	"\x11FederatedIdentity\x12\x1f\n" +
+	"\vprovider_id\x18\x01 \x01(\tR\n" +
+	"providerId\x12\x18\n" +
+	"\asubject\x18\x02 \x01(\tR\asubject\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x127\n" +
+	"\tlinked_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\blinkedAt\x12<\n" +
+	"\flast_used_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"lastUsedAt\"\xc9\x03\n" +
 	"\x04User\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05email\x18\x02 \x01(\tR\x05email\x12!\n" +
 	"\fdisplay_name\x18\x03 \x01(\tR\vdisplayName\x12#\n" +
 	"\rallowed_hosts\x18\x04 \x03(\tR\fallowedHosts\x12\x19\n" +
-	"\bis_admin\x18\x06 \x01(\bR\aisAdmin\x12>\n" +
-	"\x0fsignin_requests\x18\x05 \x03(\v2\x15.models.SigninRequestR\x0esigninRequestsB\x11Z\x0f./server/modelsb\x06proto3"
+	"\bis_admin\x18\x06 \x01(\bR\aisAdmin\x12\x16\n" +
+	"\x06groups\x18\a \x03(\tR\x06groups\x12L\n" +
+	"\x14federated_identities\x18\b \x03(\v2\x19.models.FederatedIdentityR\x13federatedIdentities\x12\x1f\n" +
+	"\vis_disabled\x18\t \x01(\bR\n" +
+	"isDisabled\x12\x1f\n" +
+	"\vexternal_id\x18\n" +
+	" \x01(\tR\n" +
+	"externalId\x123\n" +
+	"\vtotp_policy\x18\v \x01(\x0e2\x12.models.TotpPolicyR\n" +
+	"totpPolicy\x12\x1b\n" +
+	"\tgroup_ids\x18\f \x03(\tR\bgroupIds\x12>\n" +
+	"\x0fsignin_requests\x18\x05 \x03(\v2\x15.models.SigninRequestR\x0esigninRequests*Z\n" +
+	"\n" +
+	"TotpPolicy\x12\x18\n" +
+	"\x14TOTP_POLICY_DISABLED\x10\x00\x12\x18\n" +
+	"\x14TOTP_POLICY_FALLBACK\x10\x01\x12\x18\n" +
+	"\x14TOTP_POLICY_REQUIRED\x10\x02B\x11Z\x0f./server/modelsb\x06proto3"
 
 var (
 	file_protos_user_proto_rawDescOnce sync.Once
@@ -228,20 +458,28 @@ func file_protos_user_proto_rawDescGZIP() []byte {
 	return file_protos_user_proto_rawDescData
 }
 
-var file_protos_user_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_protos_user_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_protos_user_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_protos_user_proto_goTypes = []any{
-	(*SigninRequest)(nil),         // 0: models.SigninRequest
-	(*User)(nil),                  // 1: models.User
-	(*timestamppb.Timestamp)(nil), // 2: google.protobuf.Timestamp
+	(TotpPolicy)(0),               // 0: models.TotpPolicy
+	(*SigninRequest)(nil),         // 1: models.SigninRequest
+	(*FederatedIdentity)(nil),     // 2: models.FederatedIdentity
+	(*User)(nil),                  // 3: models.User
+	(*timestamppb.Timestamp)(nil), // 4: google.protobuf.Timestamp
 }
 var file_protos_user_proto_depIdxs = []int32{
-	2, // 0: models.SigninRequest.expires_at:type_name -> google.protobuf.Timestamp
-	0, // 1: models.User.signin_requests:type_name -> models.SigninRequest
-	2, // [2:2] is the sub-list for method output_type
-	2, // [2:2] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	4, // 0: models.SigninRequest.expires_at:type_name -> google.protobuf.Timestamp
+	4, // 1: models.SigninRequest.created_at:type_name -> google.protobuf.Timestamp
+	4, // 2: models.FederatedIdentity.linked_at:type_name -> google.protobuf.Timestamp
+	4, // 3: models.FederatedIdentity.last_used_at:type_name -> google.protobuf.Timestamp
+	2, // 4: models.User.federated_identities:type_name -> models.FederatedIdentity
+	0, // 5: models.User.totp_policy:type_name -> models.TotpPolicy
+	1, // 6: models.User.signin_requests:type_name -> models.SigninRequest
+	7, // [7:7] is the sub-list for method output_type
+	7, // [7:7] is the sub-list for method input_type
+	7, // [7:7] is the sub-list for extension type_name
+	7, // [7:7] is the sub-list for extension extendee
+	0, // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_protos_user_proto_init() }
@@ -254,13 +492,14 @@ func file_protos_user_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_protos_user_proto_rawDesc), len(file_protos_user_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   2,
+			NumEnums:      1,
+			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_protos_user_proto_goTypes,
 		DependencyIndexes: file_protos_user_proto_depIdxs,
+		EnumInfos:         file_protos_user_proto_enumTypes,
 		MessageInfos:      file_protos_user_proto_msgTypes,
 	}.Build()
 	File_protos_user_proto = out.File
@@ -1,9 +1,12 @@
 package mqtt
 
 import (
+	"boivie/ubergang/server/common"
+	"boivie/ubergang/server/config"
 	"boivie/ubergang/server/db"
 	"boivie/ubergang/server/log"
 	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/security"
 	ugtls "boivie/ubergang/server/tls"
 	"crypto/tls"
 	"errors"
@@ -17,15 +20,16 @@ import (
 
 type MqttProxy struct {
 	log           *log.Log
-	config        *models.Configuration
+	config        *config.Store
 	db            *db.DB
 	tracker       *Tracker
 	certManager   ugtls.TlsManager
 	brokerAddress string
+	events        *security.Events
 }
 
-func New(log *log.Log, config *models.Configuration, db *db.DB, tlsManager ugtls.TlsManager, brokerAddress string) *MqttProxy {
-	return &MqttProxy{log, config, db, NewTracker(db, log), tlsManager, brokerAddress}
+func New(log *log.Log, config *config.Store, db *db.DB, events *security.Events, tlsManager ugtls.TlsManager, brokerAddress string) *MqttProxy {
+	return &MqttProxy{log, config, db, NewTracker(db, log), tlsManager, brokerAddress, events}
 }
 
 type pendingSubscription struct {
@@ -103,6 +107,10 @@ func (s *MqttProxy) handleClientConnection(connectionType ConnectionType, client
 	defer func() {
 		_ = clientConn.Close()
 	}()
+	if s.events.Banned(security.SourceMqtt, security.Host(clientConn.RemoteAddr())) {
+		connectionErrorMetric.WithLabelValues("banned").Inc()
+		return
+	}
 	s.log.Infof("mqtt: Accepted %s connection from %s", connectionType, clientConn.RemoteAddr())
 
 	connect, err := handleClientConnect(clientConn)
@@ -115,7 +123,7 @@ func (s *MqttProxy) handleClientConnection(connectionType ConnectionType, client
 	password := string(connect.Password)
 	acl, clientConfig, err := s.authorizeConnection(connect.Username, password)
 	if err != nil {
-		s.log.Warnf("mqtt: Failed to authorize connection (%s/%s): %v from %s", connect.Username, password, err, clientConn.RemoteAddr())
+		s.events.Record(security.SourceMqtt, security.Host(clientConn.RemoteAddr()), connect.Username, err.Error())
 		connectionErrorMetric.WithLabelValues("failed_auth").Inc()
 		_ = connectionRefused().Write(clientConn)
 		return
@@ -155,15 +163,41 @@ func (s *MqttProxy) Start(port int, tlsPort int) {
 	go s.listenTLS(port)
 }
 
-func (c *MqttProxy) authorizeConnection(username, password string) (acl *ACL, clientConfig *models.MqttClient, err error) {
-	clientConfig, err = c.db.GetMqttClient(username)
+// serviceAccountClient returns the MQTT client configuration of a service
+// account, if it's allowed to connect to MQTT using `password`.
+func (c *MqttProxy) serviceAccountClient(username, password string) (*models.MqttClient, error) {
+	account, err := c.db.GetServiceAccount(username)
 	if err != nil {
-		return
+		return nil, err
+	}
+	if account.Disabled {
+		return nil, errors.New("service account is disabled")
+	}
+	if account.MqttProfileId == "" {
+		return nil, errors.New("service account can't use MQTT")
+	}
+	if err := common.CheckPassword(account.HashedClientSecret, password); err != nil {
+		return nil, err
 	}
+	return &models.MqttClient{
+		Id:        account.Id,
+		ProfileId: account.MqttProfileId,
+		Values:    account.MqttValues,
+	}, nil
+}
 
-	if clientConfig.Password != password {
-		err = errors.New("invalid password")
-		return
+func (c *MqttProxy) authorizeConnection(username, password string) (acl *ACL, clientConfig *models.MqttClient, err error) {
+	clientConfig, err = c.db.GetMqttClient(username)
+	if err == nil {
+		if clientConfig.Password != password {
+			err = errors.New("invalid password")
+			return
+		}
+	} else {
+		clientConfig, err = c.serviceAccountClient(username, password)
+		if err != nil {
+			return
+		}
 	}
 
 	clientProfile, err := c.db.GetMqttProfile(clientConfig.ProfileId)
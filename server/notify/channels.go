@@ -0,0 +1,149 @@
+package notify
+
+import (
+	"boivie/ubergang/server/db"
+	"boivie/ubergang/server/mail"
+	"boivie/ubergang/server/mqtt"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const SignatureHeader = "X-Ubergang-Signature"
+
+// emailChannel e-mails the user that the event concerns, and/or all
+// administrators.
+type emailChannel struct {
+	mailer *mail.Mailer
+	db     *db.DB
+	site   string
+	user   bool
+	admins bool
+}
+
+func (c *emailChannel) Name() string { return "e-mail" }
+
+func (c *emailChannel) Send(event *Event) error {
+	if !c.mailer.IsConfigured() {
+		return mail.ErrNotConfigured
+	}
+	var recipients []string
+	if c.user && event.UserEmail != "" {
+		recipients = append(recipients, event.UserEmail)
+	}
+	if c.admins {
+		for _, user := range c.db.ListUsers() {
+			if user.IsAdmin && !user.IsDisabled && user.Email != event.UserEmail {
+				recipients = append(recipients, user.Email)
+			}
+		}
+	}
+	var lastErr error
+	for _, to := range recipients {
+		if err := c.mailer.Send(to, Message(c.site, event)); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// Message creates the e-mail for an event.
+func Message(site string, event *Event) mail.Message {
+	var subject, what string
+	switch event.Type {
+	case EventNewSession:
+		subject = "New sign-in to " + site
+		what = "A new sign-in to the account"
+	case EventCredentialEnrolled:
+		subject = "New sign-in method added at " + site
+		what = fmt.Sprintf("The sign-in method %q was added to the account", event.Name)
+	case EventSshKeyConfirmed:
+		subject = "New SSH key confirmed at " + site
+		what = fmt.Sprintf("The SSH key %q was confirmed for the account", event.Name)
+	case EventSigninApproved:
+		subject = "Sign-in approved at " + site
+		what = "A sign-in on another device was approved for the account"
+	case EventImpersonated:
+		subject = "An administrator is viewing your account at " + site
+		what = fmt.Sprintf("The administrator %s started viewing the site as the account", event.Name)
+	default:
+		subject = "Security notification from " + site
+		what = string(event.Type) + " for the account"
+	}
+	return mail.Message{
+		Subject: subject,
+		Body: fmt.Sprintf(`Hi,
+
+%s %s at %s.
+
+Time:       %s
+Device:     %s
+IP address: %s
+User agent: %s
+
+If this wasn't you, please contact the administrator immediately.
+`, what, event.UserEmail, site, event.Time.UTC().Format(time.RFC1123),
+			event.Device, event.Ip, event.UserAgent),
+	}
+}
+
+// webhookChannel POSTs events as JSON.
+type webhookChannel struct {
+	client *http.Client
+	url    string
+	secret string
+}
+
+func (c *webhookChannel) Name() string { return "webhook" }
+
+// Sign returns the signature of a webhook request's body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (c *webhookChannel) Send(event *Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.secret != "" {
+		req.Header.Set(SignatureHeader, Sign(c.secret, body))
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// mqttChannel publishes events as JSON, to a topic per event type.
+type mqttChannel struct {
+	publisher mqtt.MQTTPublisher
+	topic     string
+}
+
+func (c *mqttChannel) Name() string { return "MQTT" }
+
+func (c *mqttChannel) Send(event *Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return c.publisher.Publish(c.topic+"/"+string(event.Type), payload, 1, false)
+}
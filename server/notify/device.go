@@ -0,0 +1,56 @@
+package notify
+
+import "strings"
+
+// DescribeDevice returns a short description of the device that sent
+// `userAgent`, such as "Firefox on Windows".
+func DescribeDevice(userAgent string) string {
+	if userAgent == "" {
+		return "Unknown device"
+	}
+
+	var os string
+	switch {
+	case strings.Contains(userAgent, "iPhone"):
+		os = "iPhone"
+	case strings.Contains(userAgent, "iPad"):
+		os = "iPad"
+	case strings.Contains(userAgent, "Android"):
+		os = "Android"
+	case strings.Contains(userAgent, "CrOS"):
+		os = "ChromeOS"
+	case strings.Contains(userAgent, "Mac OS X"), strings.Contains(userAgent, "Macintosh"):
+		os = "macOS"
+	case strings.Contains(userAgent, "Windows"):
+		os = "Windows"
+	case strings.Contains(userAgent, "Linux"):
+		os = "Linux"
+	}
+
+	// The order matters, as e.g. Edge also claims to be Chrome and Safari.
+	var browser string
+	switch {
+	case strings.Contains(userAgent, "Edg/"):
+		browser = "Edge"
+	case strings.Contains(userAgent, "OPR/"):
+		browser = "Opera"
+	case strings.Contains(userAgent, "Firefox/"), strings.Contains(userAgent, "FxiOS/"):
+		browser = "Firefox"
+	case strings.Contains(userAgent, "Chrome/"), strings.Contains(userAgent, "CriOS/"):
+		browser = "Chrome"
+	case strings.Contains(userAgent, "Safari/"):
+		browser = "Safari"
+	}
+
+	switch {
+	case browser != "" && os != "":
+		return browser + " on " + os
+	case browser != "":
+		return browser
+	case os != "":
+		return os
+	}
+	// E.g. command line tools.
+	name, _, _ := strings.Cut(userAgent, " ")
+	return name
+}
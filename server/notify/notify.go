@@ -0,0 +1,141 @@
+package notify
+
+import (
+	"boivie/ubergang/server/config"
+	"boivie/ubergang/server/db"
+	"boivie/ubergang/server/log"
+	"boivie/ubergang/server/mail"
+	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/mqtt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type EventType string
+
+const (
+	// A new session has been created, i.e. the user has signed in.
+	EventNewSession EventType = "new_session"
+	// A passkey or an authenticator app has been added to the account.
+	EventCredentialEnrolled EventType = "credential_enrolled"
+	// An SSH key has been confirmed, and can now be used.
+	EventSshKeyConfirmed EventType = "ssh_key_confirmed"
+	// A sign-in on another device has been approved using a PIN.
+	EventSigninApproved EventType = "signin_approved"
+	// An administrator has started viewing the site as the user.
+	EventImpersonated EventType = "impersonated"
+)
+
+// Event is a security relevant change to a user's account. The device, IP
+// and user agent are those of the device that the event concerns, e.g. the
+// one that signed in.
+type Event struct {
+	Type      EventType `json:"type"`
+	Time      time.Time `json:"time"`
+	UserId    string    `json:"userId"`
+	UserEmail string    `json:"userEmail"`
+	// The name of the passkey or SSH key, or the e-mail address of the
+	// administrator, if any.
+	Name      string `json:"name,omitempty"`
+	Device    string `json:"device"`
+	Ip        string `json:"ip"`
+	UserAgent string `json:"userAgent"`
+}
+
+func NewEvent(eventType EventType, user *models.User, ip, userAgent string) *Event {
+	return &Event{
+		Type:      eventType,
+		Time:      time.Now(),
+		UserId:    user.Id,
+		UserEmail: user.Email,
+		Device:    DescribeDevice(userAgent),
+		Ip:        ip,
+		UserAgent: userAgent,
+	}
+}
+
+// Channel is a destination for notifications.
+type Channel interface {
+	Name() string
+	Send(event *Event) error
+}
+
+// Notifier sends events to the channels in the configuration. As the
+// configuration is shared, changes to it apply immediately.
+type Notifier struct {
+	log       *log.Log
+	config    *config.Store
+	db        *db.DB
+	mailer    *mail.Mailer
+	publisher mqtt.MQTTPublisher
+	client    *http.Client
+	extra     []Channel
+	wg        sync.WaitGroup
+}
+
+// New creates a notifier. `publisher` may be nil if there is no MQTT broker.
+func New(log *log.Log, config *config.Store, db *db.DB, mailer *mail.Mailer, publisher mqtt.MQTTPublisher) *Notifier {
+	return &Notifier{
+		log:       log,
+		config:    config,
+		db:        db,
+		mailer:    mailer,
+		publisher: publisher,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// AddChannel adds a channel that receives all events, in addition to the
+// configured ones.
+func (n *Notifier) AddChannel(channel Channel) {
+	n.extra = append(n.extra, channel)
+}
+
+func (n *Notifier) channels() []Channel {
+	config := n.config.Get()
+	settings := config.Notifications
+	var ret []Channel
+	if settings.GetEmailUser() || settings.GetEmailAdmins() {
+		ret = append(ret, &emailChannel{
+			mailer: n.mailer,
+			db:     n.db,
+			site:   config.AdminFqdn,
+			user:   settings.GetEmailUser(),
+			admins: settings.GetEmailAdmins(),
+		})
+	}
+	if settings.GetWebhookUrl() != "" {
+		ret = append(ret, &webhookChannel{
+			client: n.client,
+			url:    settings.GetWebhookUrl(),
+			secret: settings.GetWebhookSecret(),
+		})
+	}
+	if settings.GetMqttTopic() != "" && n.publisher != nil {
+		ret = append(ret, &mqttChannel{
+			publisher: n.publisher,
+			topic:     settings.GetMqttTopic(),
+		})
+	}
+	return append(ret, n.extra...)
+}
+
+// Notify sends `event` to all channels in the background, so that a slow
+// channel doesn't delay the request that caused it.
+func (n *Notifier) Notify(event *Event) {
+	for _, channel := range n.channels() {
+		n.wg.Add(1)
+		go func(channel Channel) {
+			defer n.wg.Done()
+			if err := channel.Send(event); err != nil {
+				n.log.Warnf("Failed to send %s notification to %s: %v", event.Type, channel.Name(), err)
+			}
+		}(channel)
+	}
+}
+
+// Wait waits until all notifications have been sent.
+func (n *Notifier) Wait() {
+	n.wg.Wait()
+}
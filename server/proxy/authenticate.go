@@ -0,0 +1,160 @@
+package proxy
+
+import (
+	"boivie/ubergang/server/auth"
+	"boivie/ubergang/server/backends"
+	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/security"
+	"boivie/ubergang/server/session"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// issuer returns the issuer of the tokens signed by the server.
+func (s *Proxy) issuer() string {
+	return "https://" + s.config.Get().AdminFqdn
+}
+
+// acceptsHTML returns true if the request is likely made by a browser, which
+// should be sent to the sign-in page rather than be asked for a password.
+func acceptsHTML(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/html")
+}
+
+func (s *Proxy) requestBasicAuth(w http.ResponseWriter, backend backends.Backend) {
+	w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Basic realm="%s", charset="UTF-8"`, backend.Host()))
+	http.Error(w, "Unauthorized", http.StatusUnauthorized)
+}
+
+// Identity is who made a request. Exactly one of `User` and `ServiceAccount`
+// is set.
+type Identity struct {
+	User *models.User
+	// Only set if the user authenticated using a session.
+	Session *models.Session
+	// The administrator viewing the site as `User`, if any.
+	Impersonator   *models.User
+	ServiceAccount *models.ServiceAccount
+}
+
+// The authenticate* methods return the identity that made the request, or nil
+// if the request has been responded to.
+
+func (s *Proxy) authenticateAccessToken(w http.ResponseWriter, r *http.Request, backend backends.Backend, bearer string) *Identity {
+	user, token, err := s.auth.ValidateAccessToken(bearer, time.Now())
+	if err != nil || !auth.HasScope(token, auth.ScopeProxy) {
+		s.log.Warnf("Invalid access token for %s: %v", backend.Host(), err)
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil
+	}
+	if !auth.IsAllowed(user, token, backend.Host()) {
+		s.log.Warnf("Access token %s is not allowed to access %s", token.Id, backend.Host())
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return nil
+	}
+	if backend.MaxAuthAge() > 0 {
+		// Access tokens can't prove a recent passkey verification.
+		s.log.Warnf("Access token %s can't be used for %s, which requires recent sign in", token.Id, backend.Host())
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return nil
+	}
+	// Don't leak the token to the backend.
+	r.Header.Del("Authorization")
+	return &Identity{User: user}
+}
+
+func (s *Proxy) authenticateServiceAccount(w http.ResponseWriter, r *http.Request, backend backends.Backend, bearer string) *Identity {
+	account, err := s.auth.ValidateServiceAccountToken(bearer, s.issuer(), time.Now())
+	if err != nil {
+		s.log.Warnf("Invalid service account token for %s: %v", backend.Host(), err)
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil
+	}
+	if !contains(account.AllowedHosts, backend.Host()) || backend.MaxAuthAge() > 0 {
+		s.log.Warnf("Service account %s is not allowed to access %s", account.Id, backend.Host())
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return nil
+	}
+	// Don't leak the token to the backend.
+	r.Header.Del("Authorization")
+	return &Identity{ServiceAccount: account}
+}
+
+func (s *Proxy) authenticateAppPassword(w http.ResponseWriter, r *http.Request, backend backends.Backend, username, password string) *Identity {
+	ip := s.events.ClientIP(r)
+	if s.events.Banned(security.SourceHttp, ip) {
+		http.Error(w, "Too many requests", http.StatusTooManyRequests)
+		return nil
+	}
+	now := time.Now()
+	// Only passwords that haven't been verified recently are rate limited, as
+	// they are checked using bcrypt, which is slow. Guesses are never verified,
+	// so they are always limited.
+	if !s.auth.IsAppPasswordCached(backend.Host(), username, password, now) && !s.events.AllowTokenRequest(ip) {
+		http.Error(w, "Too many requests", http.StatusTooManyRequests)
+		return nil
+	}
+	user, appPassword, err := s.auth.ValidateAppPassword(backend.Host(), username, password, ip, now)
+	if err != nil {
+		s.events.Record(security.SourceHttp, ip, username, "invalid app password for "+backend.Host()+": "+err.Error())
+		s.requestBasicAuth(w, backend)
+		return nil
+	}
+	if !auth.IsAllowed(user, nil, backend.Host()) {
+		s.log.Warnf("User %s is not allowed to access %s", user.Email, backend.Host())
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return nil
+	}
+	if backend.MaxAuthAge() > 0 {
+		// App passwords can't prove a recent passkey verification.
+		s.log.Warnf("App password %s can't be used for %s, which requires recent sign in", appPassword.Id, backend.Host())
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return nil
+	}
+	// Don't leak the password to the backend.
+	r.Header.Del("Authorization")
+	return &Identity{User: user}
+}
+
+func (s *Proxy) authenticateSession(w http.ResponseWriter, r *http.Request, backend backends.Backend) *Identity {
+	user, sess, err := s.session.Get(r)
+	if err != nil {
+		if backend.AllowsBasicAuth() && !acceptsHTML(r) {
+			s.requestBasicAuth(w, backend)
+		} else {
+			s.redirectAuthorizeInvalidSession(w, r)
+		}
+		return nil
+	}
+	if !auth.IsAllowed(user, nil, backend.Host()) {
+		s.log.Warnf("User %s is not allowed to access %s", user.Email, backend.Host())
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return nil
+	}
+	now := time.Now()
+	if err := session.CheckBackendPolicy(backend.SessionPolicy(), backend.Host(), sess, now); err != nil {
+		s.log.Infof("Session %s can't be used for %s: %v", sess.Id, backend.Host(), err)
+		s.redirectReauthenticate(w, r)
+		return nil
+	}
+	if err := session.CheckAuthAge(backend.MaxAuthAge(), sess, now); err != nil {
+		s.log.Infof("Session %s can't be used for %s: %v", sess.Id, backend.Host(), err)
+		s.redirectReauthenticate(w, r)
+		return nil
+	}
+	s.session.TouchBackend(sess, r, backend.Host())
+	identity := &Identity{User: user, Session: sess}
+	if sess.ImpersonatorId != "" {
+		identity.Impersonator, err = s.session.Impersonator(sess)
+		if err != nil {
+			s.redirectAuthorizeInvalidSession(w, r)
+			return nil
+		}
+		s.log.Infof("%s is viewing %s as %s", identity.Impersonator.Email, backend.Host(), user.Email)
+	}
+	return identity
+}
@@ -8,7 +8,7 @@ import (
 
 func (s *Proxy) redirectsigninInvalidSession(w http.ResponseWriter, r *http.Request) {
 	redirect := r.URL.Query().Get("rd")
-	url := fmt.Sprintf("https://%s/signin?rd=%s", s.config.AdminFqdn, redirect)
+	url := fmt.Sprintf("https://%s/signin?rd=%s", s.config.Get().AdminFqdn, redirect)
 	http.Redirect(w, r, url, http.StatusFound)
 }
 
@@ -19,7 +19,12 @@ func (s *Proxy) HandleAuthorize(w http.ResponseWriter, r *http.Request) {
 		s.redirectsigninInvalidSession(w, r)
 		return
 	}
-	s.updateAccessed <- session
+	if r.URL.Query().Get("prompt") == "login" {
+		// The backend requires the user to sign in again.
+		s.redirectsigninInvalidSession(w, r)
+		return
+	}
+	s.session.Touch(session, r)
 
 	// Redirect to the trampoline, so that it can set the cookie and bind it to the correct domain.
 	redirect := r.URL.Query().Get("rd")
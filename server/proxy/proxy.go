@@ -1,11 +1,13 @@
 package proxy
 
 import (
+	"boivie/ubergang/server/auth"
 	"boivie/ubergang/server/backends"
+	"boivie/ubergang/server/config"
 	"boivie/ubergang/server/log"
-	"boivie/ubergang/server/models"
 	"boivie/ubergang/server/mqtt"
 	"boivie/ubergang/server/scripting"
+	"boivie/ubergang/server/security"
 	"boivie/ubergang/server/session"
 	"crypto/tls"
 	"fmt"
@@ -24,28 +26,39 @@ var backendConnectionErrorsTotalMetric = promauto.NewCounterVec(prometheus.Count
 }, []string{"host", "backend"})
 
 type Proxy struct {
-	config         *models.Configuration
-	backends       *backends.BackendManager
-	log            *log.Log
-	session        *session.SessionStore
-	updateAccessed chan *models.Session
-	mqttPublisher  mqtt.MQTTPublisher
+	config        *config.Store
+	backends      *backends.BackendManager
+	log           *log.Log
+	session       *session.SessionStore
+	auth          *auth.Auth
+	events        *security.Events
+	mqttPublisher mqtt.MQTTPublisher
 }
 
 func New(
-	config *models.Configuration,
+	config *config.Store,
 	log *log.Log,
 	session *session.SessionStore,
-	updateAccessed chan *models.Session,
+	auth *auth.Auth,
+	events *security.Events,
 	backends *backends.BackendManager,
 	mqttPublisher mqtt.MQTTPublisher) *Proxy {
-	return &Proxy{config, backends, log, session, updateAccessed, mqttPublisher}
+	return &Proxy{config, backends, log, session, auth, events, mqttPublisher}
 }
 
 func (s *Proxy) redirectAuthorizeInvalidSession(w http.ResponseWriter, r *http.Request) {
 	redirect := r.URL.RequestURI()
 	url := fmt.Sprintf("https://%s/authorize?rd=https://%s%s",
-		s.config.AdminFqdn, r.Host, redirect)
+		s.config.Get().AdminFqdn, r.Host, redirect)
+	http.Redirect(w, r, url, http.StatusFound)
+}
+
+// redirectReauthenticate makes the user sign in again, even if the session is
+// still valid.
+func (s *Proxy) redirectReauthenticate(w http.ResponseWriter, r *http.Request) {
+	redirect := r.URL.RequestURI()
+	url := fmt.Sprintf("https://%s/authorize?prompt=login&rd=https://%s%s",
+		s.config.Get().AdminFqdn, r.Host, redirect)
 	http.Redirect(w, r, url, http.StatusFound)
 }
 
@@ -57,6 +70,7 @@ func (s *Proxy) serveHandleTrampoline(w http.ResponseWriter, r *http.Request) bo
 	_, session, err := s.session.DecodeSessionCookie(value, true)
 	if err != nil {
 		s.log.Warnf("Failed to find session from trampoline: %v", err)
+		s.session.RecordInvalid(r, err)
 		s.redirectsigninInvalidSession(w, r)
 		return false
 	}
@@ -71,12 +85,9 @@ func (s *Proxy) serveHandleTrampoline(w http.ResponseWriter, r *http.Request) bo
 	return true
 }
 
-func isAllowed(user *models.User, host string) bool {
-	if user.IsAdmin {
-		return true
-	}
-	for _, allowed := range user.AllowedHosts {
-		if allowed == host {
+func contains(haystack []string, needle string) bool {
+	for _, v := range haystack {
+		if v == needle {
 			return true
 		}
 	}
@@ -96,18 +107,22 @@ func (s *Proxy) ProxyHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	s.log.Debugf("Resolved %s to %s backend (%s)", r.Host, backend.Type(), backend.URL())
 
-	var user *models.User = nil
-	var session *models.Session = nil
+	var identity *Identity = nil
 
 	if backend.NeedsAuth() {
-		user, session, err = s.session.Get(r)
-		if err != nil {
-			s.redirectAuthorizeInvalidSession(w, r)
-			return
+		// Bearer tokens that weren't issued by this server may be meant for the
+		// backend itself, and are passed on to it unchanged.
+		bearer, _ := auth.BearerToken(r)
+		if auth.IsAccessToken(bearer) {
+			identity = s.authenticateAccessToken(w, r, backend, bearer)
+		} else if auth.IsServiceAccountToken(bearer, s.issuer()) {
+			identity = s.authenticateServiceAccount(w, r, backend, bearer)
+		} else if username, password, found := r.BasicAuth(); found && backend.AllowsBasicAuth() {
+			identity = s.authenticateAppPassword(w, r, backend, username, password)
+		} else {
+			identity = s.authenticateSession(w, r, backend)
 		}
-		if !isAllowed(user, backend.Host()) {
-			s.log.Warnf("User %s is not allowed to access %s", user.Email, backend.Host())
-			http.Error(w, "Forbidden", http.StatusForbidden)
+		if identity == nil {
 			return
 		}
 	}
@@ -125,7 +140,7 @@ func (s *Proxy) ProxyHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	s.ProxyRequest(w, r, backend, user, session)
+	s.ProxyRequest(w, r, backend, identity)
 }
 
 func evaluate(value string, variables map[string]string) string {
@@ -138,7 +153,9 @@ func evaluate(value string, variables map[string]string) string {
 	return value
 }
 
-func (s *Proxy) ProxyRequest(w http.ResponseWriter, r *http.Request, backend backends.Backend, user *models.User, session *models.Session) {
+// ProxyRequest forwards the request to `backend`. `identity` is nil if the
+// request isn't authenticated.
+func (s *Proxy) ProxyRequest(w http.ResponseWriter, r *http.Request, backend backends.Backend, identity *Identity) {
 	upstream := backend.URL()
 
 	director := func(req *http.Request) {
@@ -148,8 +165,18 @@ func (s *Proxy) ProxyRequest(w http.ResponseWriter, r *http.Request, backend bac
 		}
 		req.Header.Set("X-Forwarded-Host", req.Host)
 		req.Header.Set("X-Forwarded-Proto", "https")
-		if user != nil {
-			req.Header.Set("X-Forwarded-Email", user.Email)
+		// Never trust identity headers from the client.
+		req.Header.Del("X-Forwarded-Email")
+		req.Header.Del("X-Forwarded-Service-Account")
+		req.Header.Del("X-Forwarded-Impersonator")
+		if identity != nil && identity.User != nil {
+			req.Header.Set("X-Forwarded-Email", identity.User.Email)
+		}
+		if identity != nil && identity.Impersonator != nil {
+			req.Header.Set("X-Forwarded-Impersonator", identity.Impersonator.Email)
+		}
+		if identity != nil && identity.ServiceAccount != nil {
+			req.Header.Set("X-Forwarded-Service-Account", identity.ServiceAccount.Id)
 		}
 		if _, ok := req.Header["User-Agent"]; !ok {
 			// explicitly disable User-Agent so it's not set to default value
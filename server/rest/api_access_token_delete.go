@@ -0,0 +1,39 @@
+package rest
+
+import (
+	"boivie/ubergang/server/models"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func (s *ApiModule) handleAccessTokenDelete(w http.ResponseWriter, r *http.Request) {
+	user, session, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+
+	var before *models.AccessToken
+	err = s.db.UpdateAccessToken(id, func(old *models.AccessToken) (*models.AccessToken, error) {
+		if old == nil {
+			return nil, errors.New("access token not found")
+		}
+		if !user.IsAdmin && old.UserId != user.Id {
+			return nil, errors.New("not authorized")
+		}
+		before = old
+		return nil, nil
+	})
+
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	s.audit(r, user, session, "access-token.delete", id, before, nil)
+
+	s.log.Infof("User %s revoked access token %s", user.Email, id)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,66 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteAccessToken(t *testing.T) {
+	t.Run("revokes own token", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("test@example.com")
+		result := f.createAccessToken(t, cookie, &api.ApiStartCreateAccessTokenRequest{
+			Name:   "CLI",
+			Scopes: []string{"proxy"},
+		})
+
+		rr := f.request("DELETE", "/api/access-token/"+result.AccessToken.ID, nil, cookie, nil)
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+
+		_, _, err := f.Auth.ValidateAccessToken(result.Secret, time.Now())
+		assert.Error(t, err)
+		assert.Empty(t, f.getUser(cookie, "me").AccessTokens)
+	})
+
+	t.Run("cannot revoke another user's token", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookieA, _ := f.CreateUser("user.a@example.com")
+		cookieB, _ := f.CreateUser("user.b@example.com")
+		result := f.createAccessToken(t, cookieB, &api.ApiStartCreateAccessTokenRequest{
+			Name:   "CLI",
+			Scopes: []string{"proxy"},
+		})
+
+		rr := f.request("DELETE", "/api/access-token/"+result.AccessToken.ID, nil, cookieA, nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+
+		_, _, err := f.Auth.ValidateAccessToken(result.Secret, time.Now())
+		require.NoError(t, err)
+	})
+
+	t.Run("admin can revoke any token", func(t *testing.T) {
+		f := CreateFixture(t)
+		adminCookie, _ := f.CreateAdmin("admin@example.com")
+		cookie, _ := f.CreateUser("user@example.com")
+		result := f.createAccessToken(t, cookie, &api.ApiStartCreateAccessTokenRequest{
+			Name:   "CLI",
+			Scopes: []string{"proxy"},
+		})
+
+		rr := f.request("DELETE", "/api/access-token/"+result.AccessToken.ID, nil, adminCookie, nil)
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+	})
+
+	t.Run("returns not found for non-existent token", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("test@example.com")
+
+		rr := f.request("DELETE", "/api/access-token/non-existent", nil, cookie, nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
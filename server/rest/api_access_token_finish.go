@@ -0,0 +1,53 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/wa"
+	"net/http"
+)
+
+func (s *ApiModule) handleAccessTokenFinish(w http.ResponseWriter, r *http.Request) {
+	user, session, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	var req api.ApiFinishCreateAccessTokenRequest
+	err = parseJsonRequest(w, r, &req)
+	if err != nil {
+		return
+	}
+
+	state, err := s.db.ConsumeAuthenticationState(req.Token)
+	if err != nil || state.GetCreateAccessToken() == nil || state.UserId != user.Id {
+		s.log.Warnf("Token not found or not intended for this user or signing type")
+		jsonify(w, api.ApiFinishCreateAccessTokenResponse{
+			Error: &api.ApiFinishCreateAccessTokenError{
+				FailedAuthentication: true}})
+		return
+	}
+
+	_, err = s.webauthn.ValidateAssertion(&req.Credential, state, wa.NewUser(user, s.db.ListCredentials(user.Id)))
+	if err != nil {
+		s.recordFailedAssertion(r, user, err)
+		jsonify(w, api.ApiFinishCreateAccessTokenResponse{
+			Error: &api.ApiFinishCreateAccessTokenError{
+				FailedAuthentication: true}})
+		return
+	}
+
+	request := state.GetCreateAccessToken()
+	token, secret, err := s.auth.CreateAccessToken(user.Id, request.Name, request.Scopes, request.AllowedHosts, request.ExpiresAt.AsTime())
+	if err != nil {
+		s.log.Warnf("Failed to create access token: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	s.audit(r, user, session, "access-token.create", token.Id, nil, token)
+
+	jsonify(w, api.ApiFinishCreateAccessTokenResponse{
+		Result: &api.ApiFinishCreateAccessTokenResult{
+			AccessToken: ToApiAccessToken(token),
+			Secret:      secret,
+		}})
+}
@@ -0,0 +1,176 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createAccessToken creates an access token for the user, who must have
+// enrolled a passkey, and returns the result.
+func (f *Fixture) createAccessToken(t *testing.T, cookie *http.Cookie, req *api.ApiStartCreateAccessTokenRequest) *api.ApiFinishCreateAccessTokenResult {
+	t.Helper()
+	cred, userHandle := f.enrollPasskey(t, cookie)
+	start := f.startCreateAccessToken(t, cookie, req)
+	require.NotNil(t, start.Authenticate)
+
+	assertion := f.SignAssertionRequest(&start.Authenticate.AssertionRequest, userHandle, &cred)
+	resp := &api.ApiFinishCreateAccessTokenResponse{}
+	rr := f.request("POST", "/api/access-token/finish", &api.ApiFinishCreateAccessTokenRequest{
+		Token:      start.Authenticate.Token,
+		Credential: *assertion,
+	}, cookie, resp)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Nil(t, resp.Error)
+	require.NotNil(t, resp.Result)
+	return resp.Result
+}
+
+func TestFinishCreateAccessToken(t *testing.T) {
+	t.Run("creates token", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+
+		result := f.createAccessToken(t, cookie, &api.ApiStartCreateAccessTokenRequest{
+			Name:          "CLI",
+			Scopes:        []string{"proxy"},
+			AllowedHosts:  []string{"app.example.com"},
+			ExpiresInDays: 7,
+		})
+		assert.NotEmpty(t, result.Secret)
+		assert.Equal(t, "CLI", result.AccessToken.Name)
+		assert.Equal(t, []string{"proxy"}, result.AccessToken.Scopes)
+		assert.Equal(t, []string{"app.example.com"}, result.AccessToken.AllowedHosts)
+
+		user := f.getUser(cookie, "me")
+		require.Len(t, user.AccessTokens, 1)
+		assert.Equal(t, result.AccessToken.ID, user.AccessTokens[0].ID)
+
+		records := f.listAuditRecords(t, cookie, "?action=access-token.create")
+		require.Len(t, records, 1)
+		assert.Equal(t, result.AccessToken.ID, records[0].TargetId)
+		assert.Equal(t, user.ID, records[0].ActorId)
+
+		// Only a hash of the secret is stored.
+		stored, err := f.Db.GetAccessToken(result.AccessToken.ID)
+		require.NoError(t, err)
+		assert.NotContains(t, string(stored.HashedSecret), result.Secret)
+
+		tokenUser, token, err := f.Auth.ValidateAccessToken(result.Secret, time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, tokenUser.Id)
+		assert.Equal(t, result.AccessToken.ID, token.Id)
+
+		_, _, err = f.Auth.ValidateAccessToken(result.Secret, time.Now().Add(8*24*time.Hour))
+		assert.Error(t, err, "token should have expired")
+
+		_, _, err = f.Auth.ValidateAccessToken(result.Secret+"x", time.Now())
+		assert.Error(t, err)
+	})
+
+	t.Run("records last use", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("test@example.com")
+
+		result := f.createAccessToken(t, cookie, &api.ApiStartCreateAccessTokenRequest{
+			Name:   "CLI",
+			Scopes: []string{"proxy"},
+		})
+		assert.Empty(t, result.AccessToken.LastUsedAt)
+
+		_, _, err := f.Auth.ValidateAccessToken(result.Secret, time.Now())
+		require.NoError(t, err)
+
+		user := f.getUser(cookie, "me")
+		require.Len(t, user.AccessTokens, 1)
+		assert.NotEmpty(t, user.AccessTokens[0].LastUsedAt)
+	})
+
+	t.Run("can use the admin API with the admin scope", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+		admin := f.createAccessToken(t, cookie, &api.ApiStartCreateAccessTokenRequest{
+			Name:   "Scripts",
+			Scopes: []string{"admin"},
+		})
+		proxy := f.createAccessToken(t, cookie, &api.ApiStartCreateAccessTokenRequest{
+			Name:   "CLI",
+			Scopes: []string{"proxy"},
+		})
+
+		listUsersAs := func(authorization string) int {
+			req := httptest.NewRequest("GET", "/api/user", nil)
+			req.Host = "test.example.com"
+			req.Header.Set("Authorization", authorization)
+			rr := httptest.NewRecorder()
+			f.router.ServeHTTP(rr, req)
+			return rr.Code
+		}
+		listUsers := func(token string) int {
+			return listUsersAs("Bearer " + token)
+		}
+		assert.Equal(t, http.StatusOK, listUsers(admin.Secret))
+		// The scheme is case-insensitive.
+		assert.Equal(t, http.StatusOK, listUsersAs("bearer "+admin.Secret))
+		assert.Equal(t, http.StatusForbidden, listUsers(proxy.Secret))
+		assert.Equal(t, http.StatusForbidden, listUsers("ugt_invalid_secret"))
+
+		// The token stops working if the user is no longer an admin.
+		me := f.getUser(cookie, "me")
+		err := f.Db.UpdateUser(me.ID, func(old *models.User) (*models.User, error) {
+			old.IsAdmin = false
+			return old, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, listUsers(admin.Secret))
+	})
+
+	t.Run("can't be used for requests bound to a session", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+		_, userId := f.CreateUserGetId("test@example.com")
+		token := f.createAccessToken(t, cookie, &api.ApiStartCreateAccessTokenRequest{
+			Name:   "Scripts",
+			Scopes: []string{"admin"},
+		})
+
+		post := func(path string) int {
+			req := httptest.NewRequest("POST", path, strings.NewReader("{}"))
+			req.Host = "test.example.com"
+			req.Header.Set("Authorization", "Bearer "+token.Secret)
+			rr := httptest.NewRecorder()
+			f.router.ServeHTTP(rr, req)
+			return rr.Code
+		}
+		assert.Equal(t, http.StatusForbidden, post("/api/enroll/start"))
+		assert.Equal(t, http.StatusForbidden, post("/api/totp/enroll/start"))
+		assert.Equal(t, http.StatusForbidden, post("/api/user/"+userId+"/impersonate"))
+	})
+
+	t.Run("fails with invalid token", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("test@example.com")
+
+		resp := &api.ApiFinishCreateAccessTokenResponse{}
+		rr := f.request("POST", "/api/access-token/finish", &api.ApiFinishCreateAccessTokenRequest{
+			Token: "0190a2b4-5c6d-7e8f-9a0b-1c2d3e4f5a6b",
+		}, cookie, resp)
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.FailedAuthentication)
+	})
+
+	t.Run("requires authentication", func(t *testing.T) {
+		f := CreateFixture(t)
+
+		rr := f.request("POST", "/api/access-token/finish", &api.ApiFinishCreateAccessTokenRequest{}, nil, nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+}
@@ -0,0 +1,113 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/auth"
+	"boivie/ubergang/server/models"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultAccessTokenExpiryDays = 30
+	maxAccessTokenExpiryDays     = 365
+)
+
+func ToApiAccessToken(token *models.AccessToken) api.ApiAccessToken {
+	obj := api.ApiAccessToken{
+		ID:           token.Id,
+		Name:         token.Name,
+		Scopes:       token.Scopes,
+		AllowedHosts: token.AllowedHosts,
+		CreatedAt:    token.CreatedAt.AsTime().Format(time.RFC3339),
+		ExpiresAt:    token.ExpiresAt.AsTime().Format(time.RFC3339),
+	}
+	if obj.Scopes == nil {
+		obj.Scopes = []string{}
+	}
+	if obj.AllowedHosts == nil {
+		obj.AllowedHosts = []string{}
+	}
+	if token.LastUsedAt != nil {
+		obj.LastUsedAt = token.LastUsedAt.AsTime().Format(time.RFC3339)
+	}
+	return obj
+}
+
+func (s *ApiModule) handleAccessTokenStart(w http.ResponseWriter, r *http.Request) {
+	user, _, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	var req api.ApiStartCreateAccessTokenRequest
+	err = parseJsonRequest(w, r, &req)
+	if err != nil {
+		return
+	}
+
+	respondErr := func(e api.ApiStartCreateAccessTokenError) {
+		jsonify(w, api.ApiStartCreateAccessTokenResponse{Error: &e})
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		respondErr(api.ApiStartCreateAccessTokenError{InvalidName: true})
+		return
+	}
+	if len(req.Scopes) == 0 {
+		respondErr(api.ApiStartCreateAccessTokenError{InvalidScope: true})
+		return
+	}
+	for _, scope := range req.Scopes {
+		if !contains(auth.AccessTokenScopes, scope) || (auth.IsAdminScope(scope) && !user.IsAdmin) {
+			respondErr(api.ApiStartCreateAccessTokenError{InvalidScope: true})
+			return
+		}
+	}
+	allowedHosts := make([]string, 0)
+	for _, host := range req.AllowedHosts {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if !user.IsAdmin && !contains(user.AllowedHosts, host) {
+			respondErr(api.ApiStartCreateAccessTokenError{InvalidHost: true})
+			return
+		}
+		allowedHosts = append(allowedHosts, host)
+	}
+	expiresInDays := req.ExpiresInDays
+	if expiresInDays == 0 {
+		expiresInDays = defaultAccessTokenExpiryDays
+	}
+	if expiresInDays < 0 || expiresInDays > maxAccessTokenExpiryDays {
+		respondErr(api.ApiStartCreateAccessTokenError{InvalidExpiry: true})
+		return
+	}
+	expiresAt := time.Now().Add(time.Duration(expiresInDays) * 24 * time.Hour)
+
+	credentials := s.db.ListCredentials(user.Id)
+	token, credentialAssertion, err :=
+		s.webauthn.CreateAssertion(user, credentials, func(state *models.AuthenticationState) {
+			state.Type = &models.AuthenticationState_CreateAccessToken{
+				CreateAccessToken: &models.AuthenticationStateCreateAccessToken{
+					Name:         name,
+					Scopes:       req.Scopes,
+					AllowedHosts: allowedHosts,
+					ExpiresAt:    timestamppb.New(expiresAt),
+				},
+			}
+		})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	jsonify(w, api.ApiStartCreateAccessTokenResponse{
+		Authenticate: &api.ApiStartCreateAccessTokenAuthenticate{
+			Token:            token,
+			AssertionRequest: *credentialAssertion,
+		},
+	})
+}
@@ -0,0 +1,133 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"testing"
+
+	"github.com/descope/virtualwebauthn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// enrollPasskey enrolls a passkey for the user, and returns it along with the
+// user handle needed to sign assertions.
+func (f *Fixture) enrollPasskey(t *testing.T, cookie *http.Cookie) (virtualwebauthn.Credential, string) {
+	t.Helper()
+	resp, err := f.StartEnroll(cookie)
+	require.NoError(t, err)
+	request := resp.EnrollRequest
+	cred, res := f.GenerateCredential(request)
+	_, err = f.FinishEnroll(cookie, request.Token, res)
+	require.NoError(t, err)
+	return cred, request.Options.User.ID
+}
+
+func (f *Fixture) startCreateAccessToken(t *testing.T, cookie *http.Cookie, req *api.ApiStartCreateAccessTokenRequest) *api.ApiStartCreateAccessTokenResponse {
+	t.Helper()
+	resp := &api.ApiStartCreateAccessTokenResponse{}
+	rr := f.request("POST", "/api/access-token/start", req, cookie, resp)
+	require.Equal(t, http.StatusOK, rr.Code)
+	return resp
+}
+
+func TestStartCreateAccessToken(t *testing.T) {
+	t.Run("returns assertion request", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("test@example.com")
+		f.enrollPasskey(t, cookie)
+
+		resp := f.startCreateAccessToken(t, cookie, &api.ApiStartCreateAccessTokenRequest{
+			Name:   "CLI",
+			Scopes: []string{"proxy"},
+		})
+		require.Nil(t, resp.Error)
+		require.NotNil(t, resp.Authenticate)
+		assert.NotEmpty(t, resp.Authenticate.Token)
+		assert.NotEmpty(t, resp.Authenticate.AssertionRequest.Challenge)
+	})
+
+	t.Run("rejects empty name", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("test@example.com")
+
+		resp := f.startCreateAccessToken(t, cookie, &api.ApiStartCreateAccessTokenRequest{
+			Name:   " ",
+			Scopes: []string{"proxy"},
+		})
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.InvalidName)
+	})
+
+	t.Run("rejects unknown scope", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("test@example.com")
+
+		resp := f.startCreateAccessToken(t, cookie, &api.ApiStartCreateAccessTokenRequest{
+			Name:   "CLI",
+			Scopes: []string{"everything"},
+		})
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.InvalidScope)
+	})
+
+	t.Run("rejects SCIM scope for non-admins", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("test@example.com")
+
+		resp := f.startCreateAccessToken(t, cookie, &api.ApiStartCreateAccessTokenRequest{
+			Name:   "Directory",
+			Scopes: []string{"scim"},
+		})
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.InvalidScope)
+	})
+
+	t.Run("rejects admin scope for non-admins", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("test@example.com")
+
+		resp := f.startCreateAccessToken(t, cookie, &api.ApiStartCreateAccessTokenRequest{
+			Name:   "Scripts",
+			Scopes: []string{"admin"},
+		})
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.InvalidScope)
+	})
+
+	t.Run("rejects hosts the user can't access", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("test@example.com")
+
+		resp := f.startCreateAccessToken(t, cookie, &api.ApiStartCreateAccessTokenRequest{
+			Name:         "CLI",
+			Scopes:       []string{"proxy"},
+			AllowedHosts: []string{"secret.example.com"},
+		})
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.InvalidHost)
+	})
+
+	t.Run("rejects too long expiry", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("test@example.com")
+
+		resp := f.startCreateAccessToken(t, cookie, &api.ApiStartCreateAccessTokenRequest{
+			Name:          "CLI",
+			Scopes:        []string{"proxy"},
+			ExpiresInDays: 1000,
+		})
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.InvalidExpiry)
+	})
+
+	t.Run("requires authentication", func(t *testing.T) {
+		f := CreateFixture(t)
+
+		rr := f.request("POST", "/api/access-token/start", &api.ApiStartCreateAccessTokenRequest{
+			Name:   "CLI",
+			Scopes: []string{"proxy"},
+		}, nil, nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+}
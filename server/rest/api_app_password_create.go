@@ -0,0 +1,72 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/models"
+	"net/http"
+	"strings"
+	"time"
+)
+
+func ToApiAppPassword(appPassword *models.AppPassword) api.ApiAppPassword {
+	obj := api.ApiAppPassword{
+		ID:             appPassword.Id,
+		Name:           appPassword.Name,
+		BackendFqdn:    appPassword.BackendFqdn,
+		CreatedAt:      appPassword.CreatedAt.AsTime().Format(time.RFC3339),
+		LastRemoteAddr: appPassword.LastRemoteAddr,
+	}
+	if appPassword.LastUsedAt != nil {
+		obj.LastUsedAt = appPassword.LastUsedAt.AsTime().Format(time.RFC3339)
+	}
+	return obj
+}
+
+func (s *ApiModule) handleAppPasswordCreate(w http.ResponseWriter, r *http.Request) {
+	user, session, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	var req api.ApiCreateAppPasswordRequest
+	err = parseJsonRequest(w, r, &req)
+	if err != nil {
+		return
+	}
+
+	respondErr := func(e api.ApiCreateAppPasswordError) {
+		jsonify(w, api.ApiCreateAppPasswordResponse{Error: &e})
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		respondErr(api.ApiCreateAppPasswordError{InvalidName: true})
+		return
+	}
+
+	fqdn := strings.ToLower(strings.TrimSpace(req.BackendFqdn))
+	backend, err := s.db.GetBackend(fqdn)
+	if err != nil || !backend.AllowBasicAuth {
+		respondErr(api.ApiCreateAppPasswordError{InvalidBackend: true})
+		return
+	}
+	if !user.IsAdmin && !contains(user.AllowedHosts, fqdn) {
+		respondErr(api.ApiCreateAppPasswordError{InvalidBackend: true})
+		return
+	}
+
+	appPassword, password, err := s.auth.CreateAppPassword(user.Id, fqdn, name)
+	if err != nil {
+		s.log.Warnf("Failed to create app password: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	s.audit(r, user, session, "app-password.create", appPassword.Id, nil, appPassword)
+
+	jsonify(w, api.ApiCreateAppPasswordResponse{
+		Result: &api.ApiCreateAppPasswordResult{
+			AppPassword: ToApiAppPassword(appPassword),
+			Username:    user.Email,
+			Password:    password,
+		}})
+}
@@ -0,0 +1,121 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (f *Fixture) createAppPassword(t *testing.T, cookie *http.Cookie, req *api.ApiCreateAppPasswordRequest) *api.ApiCreateAppPasswordResponse {
+	t.Helper()
+	resp := &api.ApiCreateAppPasswordResponse{}
+	rr := f.request("POST", "/api/app-password", req, cookie, resp)
+	require.Equal(t, http.StatusOK, rr.Code)
+	return resp
+}
+
+// setupAppPasswordTest creates a backend that allows basic auth, and a user
+// that can access it.
+func setupAppPasswordTest(t *testing.T) (f *Fixture, adminCookie *http.Cookie, userCookie *http.Cookie) {
+	t.Helper()
+	f = CreateFixture(t)
+	adminCookie, _ = f.CreateAdmin("admin@example.com")
+	rr := f.CreateBackend(adminCookie, &api.ApiBackend{
+		Fqdn:        "dav.example.com",
+		UpstreamUrl: "http://localhost:8080",
+	})
+	require.Equal(t, http.StatusOK, rr.Code)
+	allowBasicAuth := true
+	rr = f.request("POST", "/api/backend/dav.example.com", &api.ApiUpdateBackendRequest{AllowBasicAuth: &allowBasicAuth}, adminCookie, nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	userCookie, userId := f.CreateUserGetId("user@example.com")
+	allowedHosts := []string{"dav.example.com"}
+	rr = f.request("POST", "/api/user/"+userId, &api.ApiUpdateUserRequest{AllowedHosts: &allowedHosts}, adminCookie, nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	return
+}
+
+func TestCreateAppPassword(t *testing.T) {
+	t.Run("creates app password", func(t *testing.T) {
+		f, _, cookie := setupAppPasswordTest(t)
+
+		resp := f.createAppPassword(t, cookie, &api.ApiCreateAppPasswordRequest{
+			Name:        "Phone calendar",
+			BackendFqdn: "dav.example.com",
+		})
+		require.Nil(t, resp.Error)
+		require.NotNil(t, resp.Result)
+		assert.Equal(t, "user@example.com", resp.Result.Username)
+		assert.NotEmpty(t, resp.Result.Password)
+		assert.Equal(t, "dav.example.com", resp.Result.AppPassword.BackendFqdn)
+
+		user := f.getUser(cookie, "me")
+		require.Len(t, user.AppPasswords, 1)
+		assert.Equal(t, "Phone calendar", user.AppPasswords[0].Name)
+		assert.Empty(t, user.AppPasswords[0].LastUsedAt)
+
+		validated, _, err := f.Auth.ValidateAppPassword("dav.example.com", "user@example.com", resp.Result.Password, "192.0.2.1", time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, validated.Id)
+
+		user = f.getUser(cookie, "me")
+		assert.NotEmpty(t, user.AppPasswords[0].LastUsedAt)
+		assert.Equal(t, "192.0.2.1", user.AppPasswords[0].LastRemoteAddr)
+
+		_, _, err = f.Auth.ValidateAppPassword("dav.example.com", "user@example.com", "wrong", "192.0.2.1", time.Now())
+		assert.Error(t, err)
+
+		_, _, err = f.Auth.ValidateAppPassword("other.example.com", "user@example.com", resp.Result.Password, "192.0.2.1", time.Now())
+		assert.Error(t, err, "app passwords are only valid for their backend")
+	})
+
+	t.Run("rejects backend without basic auth", func(t *testing.T) {
+		f, adminCookie, _ := setupAppPasswordTest(t)
+		rr := f.CreateBackend(adminCookie, &api.ApiBackend{
+			Fqdn:        "app.example.com",
+			UpstreamUrl: "http://localhost:8080",
+		})
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		resp := f.createAppPassword(t, adminCookie, &api.ApiCreateAppPasswordRequest{
+			Name:        "Device",
+			BackendFqdn: "app.example.com",
+		})
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.InvalidBackend)
+	})
+
+	t.Run("rejects backend the user can't access", func(t *testing.T) {
+		f, _, _ := setupAppPasswordTest(t)
+		cookie, _ := f.CreateUser("other@example.com")
+
+		resp := f.createAppPassword(t, cookie, &api.ApiCreateAppPasswordRequest{
+			Name:        "Device",
+			BackendFqdn: "dav.example.com",
+		})
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.InvalidBackend)
+	})
+
+	t.Run("rejects empty name", func(t *testing.T) {
+		f, _, cookie := setupAppPasswordTest(t)
+
+		resp := f.createAppPassword(t, cookie, &api.ApiCreateAppPasswordRequest{
+			BackendFqdn: "dav.example.com",
+		})
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.InvalidName)
+	})
+
+	t.Run("requires authentication", func(t *testing.T) {
+		f := CreateFixture(t)
+
+		rr := f.request("POST", "/api/app-password", &api.ApiCreateAppPasswordRequest{}, nil, nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+}
@@ -0,0 +1,59 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteAppPassword(t *testing.T) {
+	t.Run("revokes own app password", func(t *testing.T) {
+		f, _, cookie := setupAppPasswordTest(t)
+		resp := f.createAppPassword(t, cookie, &api.ApiCreateAppPasswordRequest{
+			Name:        "Phone calendar",
+			BackendFqdn: "dav.example.com",
+		})
+		require.NotNil(t, resp.Result)
+
+		// Make sure that it's cached.
+		_, _, err := f.Auth.ValidateAppPassword("dav.example.com", "user@example.com", resp.Result.Password, "192.0.2.1", time.Now())
+		require.NoError(t, err)
+
+		rr := f.request("DELETE", "/api/app-password/"+resp.Result.AppPassword.ID, nil, cookie, nil)
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+
+		_, _, err = f.Auth.ValidateAppPassword("dav.example.com", "user@example.com", resp.Result.Password, "192.0.2.1", time.Now())
+		assert.Error(t, err)
+		assert.Empty(t, f.getUser(cookie, "me").AppPasswords)
+	})
+
+	t.Run("cannot revoke another user's app password", func(t *testing.T) {
+		f, _, cookie := setupAppPasswordTest(t)
+		otherCookie, _ := f.CreateUser("other@example.com")
+		resp := f.createAppPassword(t, cookie, &api.ApiCreateAppPasswordRequest{
+			Name:        "Phone calendar",
+			BackendFqdn: "dav.example.com",
+		})
+		require.NotNil(t, resp.Result)
+
+		rr := f.request("DELETE", "/api/app-password/"+resp.Result.AppPassword.ID, nil, otherCookie, nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		assert.Len(t, f.getUser(cookie, "me").AppPasswords, 1)
+	})
+
+	t.Run("admin can revoke any app password", func(t *testing.T) {
+		f, adminCookie, cookie := setupAppPasswordTest(t)
+		resp := f.createAppPassword(t, cookie, &api.ApiCreateAppPasswordRequest{
+			Name:        "Phone calendar",
+			BackendFqdn: "dav.example.com",
+		})
+		require.NotNil(t, resp.Result)
+
+		rr := f.request("DELETE", "/api/app-password/"+resp.Result.AppPassword.ID, nil, adminCookie, nil)
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+	})
+}
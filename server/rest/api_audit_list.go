@@ -0,0 +1,118 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/audit"
+	"boivie/ubergang/server/models"
+	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultAuditRecords = 100
+	maxAuditRecords     = 1000
+)
+
+// audit records a change that `user` made using `session`. `before` and
+// `after` are the changed entity, and nil if it was created or deleted. As the
+// change has already been made, failures are only logged.
+func (s *ApiModule) audit(r *http.Request, user *models.User, session *models.Session, action, targetId string, before, after proto.Message) {
+	actor := audit.Actor{
+		Id:        user.Id,
+		Name:      user.Email,
+		SessionId: session.GetId(),
+		Ip:        s.events.ClientIP(r),
+	}
+	record := audit.NewRecord(actor, action, targetId, before, after)
+	if err := s.db.AppendAuditRecord(record); err != nil {
+		s.log.Warnf("Failed to store audit record of %s %s: %v", action, targetId, err)
+	}
+}
+
+func ToApiAuditRecord(record *models.AuditRecord) api.ApiAuditRecord {
+	changes := make([]api.ApiAuditChange, 0, len(record.Changes))
+	for _, change := range record.Changes {
+		changes = append(changes, api.ApiAuditChange{
+			Field:  change.Field,
+			Before: change.Before,
+			After:  change.After,
+		})
+	}
+	return api.ApiAuditRecord{
+		ID:         record.Id,
+		Timestamp:  record.Timestamp.AsTime().Format(time.RFC3339Nano),
+		ActorId:    record.ActorId,
+		ActorName:  record.ActorName,
+		SessionId:  record.SessionId,
+		Ip:         record.Ip,
+		Action:     record.Action,
+		TargetType: record.TargetType,
+		TargetId:   record.TargetId,
+		Changes:    changes,
+	}
+}
+
+// parseTime parses an optional RFC 3339 timestamp.
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func (s *ApiModule) handleAuditList(w http.ResponseWriter, r *http.Request) {
+	user, _, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	if !user.IsAdmin {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	// All filters are optional. The actor is matched by ID or name.
+	q := r.URL.Query()
+	actor := q.Get("actor")
+	action := q.Get("action")
+	targetType := q.Get("targetType")
+	targetId := q.Get("targetId")
+	since, err := parseTime(q.Get("since"))
+	if err != nil {
+		http.Error(w, "Invalid since", http.StatusBadRequest)
+		return
+	}
+	until, err := parseTime(q.Get("until"))
+	if err != nil {
+		http.Error(w, "Invalid until", http.StatusBadRequest)
+		return
+	}
+	limit := defaultAuditRecords
+	if v := q.Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = min(limit, maxAuditRecords)
+	}
+
+	records := s.db.ListAuditRecords(func(record *models.AuditRecord) bool {
+		ts := record.Timestamp.AsTime()
+		return (actor == "" || record.ActorId == actor || record.ActorName == actor) &&
+			(action == "" || record.Action == action) &&
+			(targetType == "" || record.TargetType == targetType) &&
+			(targetId == "" || record.TargetId == targetId) &&
+			(since.IsZero() || !ts.Before(since)) &&
+			(until.IsZero() || ts.Before(until))
+	}, limit)
+
+	ret := make([]api.ApiAuditRecord, 0, len(records))
+	for _, record := range records {
+		ret = append(ret, ToApiAuditRecord(record))
+	}
+	jsonify(w, api.ApiListAuditRecordsResponse{Records: ret})
+}
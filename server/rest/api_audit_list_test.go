@@ -0,0 +1,159 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func (f *Fixture) listAuditRecords(t *testing.T, cookie *http.Cookie, query string) []api.ApiAuditRecord {
+	t.Helper()
+	resp := &api.ApiListAuditRecordsResponse{}
+	rr := f.request("GET", "/api/audit"+query, nil, cookie, resp)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("list audit records failed with status %d: %s", rr.Code, rr.Body.String())
+	}
+	return resp.Records
+}
+
+func findChange(record api.ApiAuditRecord, field string) *api.ApiAuditChange {
+	for _, change := range record.Changes {
+		if change.Field == field {
+			return &change
+		}
+	}
+	return nil
+}
+
+func TestAuditList(t *testing.T) {
+	t.Run("records who changed a backend's upstream", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, adminId := f.CreateAdminGetId("admin@example.com")
+		f.CreateBackend(cookie, &api.ApiBackend{Fqdn: "app.example.com", UpstreamUrl: "http://localhost:8080"})
+
+		updatedURL := "http://localhost:9090"
+		rr := f.request("POST", "/api/backend/app.example.com", &api.ApiUpdateBackendRequest{UpstreamUrl: &updatedURL}, cookie, nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("request failed with status %d: %s", rr.Code, rr.Body.String())
+		}
+
+		records := f.listAuditRecords(t, cookie, "?targetId=app.example.com")
+		if len(records) != 2 {
+			t.Fatalf("Expected 2 records, got %d", len(records))
+		}
+		// Newest first.
+		record := records[0]
+		if record.Action != "backend.update" || record.TargetType != "backend" {
+			t.Errorf("Unexpected action %q on %q", record.Action, record.TargetType)
+		}
+		if record.ActorId != adminId || record.ActorName != "admin@example.com" {
+			t.Errorf("Unexpected actor %q (%q)", record.ActorId, record.ActorName)
+		}
+		if record.SessionId == "" {
+			t.Error("Expected a session ID")
+		}
+		change := findChange(record, "upstream_url")
+		if change == nil {
+			t.Fatalf("Expected upstream_url to be changed, got %+v", record.Changes)
+		}
+		if change.Before != `"http://localhost:8080"` || change.After != `"http://localhost:9090"` {
+			t.Errorf("Unexpected change %q -> %q", change.Before, change.After)
+		}
+	})
+
+	t.Run("filters records", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+		f.CreateBackend(cookie, &api.ApiBackend{Fqdn: "a.example.com", UpstreamUrl: "http://localhost:8080"})
+		f.CreateBackend(cookie, &api.ApiBackend{Fqdn: "b.example.com", UpstreamUrl: "http://localhost:8080"})
+		f.request("DELETE", "/api/backend/a.example.com", nil, cookie, nil)
+
+		if records := f.listAuditRecords(t, cookie, "?action=backend.delete"); len(records) != 1 || records[0].TargetId != "a.example.com" {
+			t.Errorf("Expected one deletion of a.example.com, got %+v", records)
+		}
+		if records := f.listAuditRecords(t, cookie, "?targetType=backend"); len(records) != 3 {
+			t.Errorf("Expected 3 backend records, got %d", len(records))
+		}
+		if records := f.listAuditRecords(t, cookie, "?actor=admin@example.com&limit=2"); len(records) != 2 {
+			t.Errorf("Expected 2 records, got %d", len(records))
+		}
+		if records := f.listAuditRecords(t, cookie, "?actor=someone@example.com"); len(records) != 0 {
+			t.Errorf("Expected no records, got %d", len(records))
+		}
+		if records := f.listAuditRecords(t, cookie, "?until=2000-01-01T00:00:00Z"); len(records) != 0 {
+			t.Errorf("Expected no records, got %d", len(records))
+		}
+
+		rr := f.request("GET", "/api/audit?since=yesterday", nil, cookie, nil)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, rr.Code)
+		}
+	})
+
+	t.Run("deleting a missing entity isn't recorded", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+		f.request("DELETE", "/api/mqtt-client/missing", nil, cookie, nil)
+
+		if records := f.listAuditRecords(t, cookie, ""); len(records) != 0 {
+			t.Errorf("Expected no records, got %+v", records)
+		}
+	})
+
+	t.Run("redacts secrets", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+		f.CreateMqttProfile(cookie, &api.ApiMqttProfile{Id: "profile"})
+		f.CreateMqttClient(cookie, &api.ApiMqttClient{Id: "client", ProfileId: "profile", Password: "hunter2"})
+
+		records := f.listAuditRecords(t, cookie, "?action=mqtt-client.update")
+		if len(records) != 1 {
+			t.Fatalf("Expected 1 record, got %d", len(records))
+		}
+		for _, change := range records[0].Changes {
+			if strings.Contains(change.After, "hunter2") {
+				t.Errorf("Expected %s to be redacted, got %q", change.Field, change.After)
+			}
+		}
+	})
+
+	t.Run("ignores forwarded addresses from untrusted clients", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+
+		body, _ := json.Marshal(&api.ApiUpdateMqttProfileRequest{})
+		req := httptest.NewRequest("POST", "/api/mqtt-profile/profile", bytes.NewReader(body))
+		req.Host = "test.example.com"
+		req.RemoteAddr = "192.0.2.1:1234"
+		req.Header.Set("X-Forwarded-For", "198.51.100.1")
+		req.Header.Set("CF-Connecting-IP", "198.51.100.2")
+		req.AddCookie(cookie)
+		rr := httptest.NewRecorder()
+		f.router.ServeHTTP(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("request failed with status %d: %s", rr.Code, rr.Body.String())
+		}
+
+		records := f.listAuditRecords(t, cookie, "?targetId=profile")
+		if len(records) != 1 {
+			t.Fatalf("Expected 1 record, got %d", len(records))
+		}
+		if records[0].Ip != "192.0.2.1" {
+			t.Errorf("Expected the peer's address, got %q", records[0].Ip)
+		}
+	})
+
+	t.Run("requires admin", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("user@example.com")
+
+		rr := f.request("GET", "/api/audit", nil, cookie, nil)
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("Expected status %d, got %d", http.StatusForbidden, rr.Code)
+		}
+	})
+}
@@ -0,0 +1,26 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+)
+
+// handleAuthenticatorList lists the authenticators with known names, e.g. to
+// pick from when editing the passkey policy.
+func (s *ApiModule) handleAuthenticatorList(w http.ResponseWriter, r *http.Request) {
+	user, _, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	if !user.IsAdmin {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	ret := make([]api.ApiAuthenticator, 0)
+	for _, a := range s.webauthn.KnownAuthenticators() {
+		ret = append(ret, api.ApiAuthenticator{Aaguid: a.Aaguid, Name: a.Name})
+	}
+	jsonify(w, api.ApiListAuthenticatorsResponse{Authenticators: ret})
+}
@@ -0,0 +1,33 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticatorList(t *testing.T) {
+	t.Run("lists known authenticators", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+
+		resp := &api.ApiListAuthenticatorsResponse{}
+		rr := f.request("GET", "/api/authenticators", nil, cookie, resp)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, resp.Authenticators, api.ApiAuthenticator{
+			Aaguid: "adce0002-35bc-c60a-648b-0b25f1f05503",
+			Name:   "Chrome on Mac",
+		})
+	})
+
+	t.Run("requires admin", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("user@example.com")
+
+		rr := f.request("GET", "/api/authenticators", nil, cookie, nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+}
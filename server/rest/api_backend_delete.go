@@ -8,7 +8,7 @@ import (
 )
 
 func (s *ApiModule) handleBackendDelete(w http.ResponseWriter, r *http.Request) {
-	user, _, err := s.session.GetAndValidate(w, r)
+	user, session, err := s.session.GetAndValidate(w, r)
 	if err != nil {
 		return
 	}
@@ -20,12 +20,16 @@ func (s *ApiModule) handleBackendDelete(w http.ResponseWriter, r *http.Request)
 
 	fqdn := strings.ToLower(mux.Vars(r)["fqdn"])
 
+	before, _ := s.db.GetBackend(fqdn)
 	err = s.db.DeleteBackend(fqdn)
 
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	if before != nil {
+		s.audit(r, user, session, "backend.delete", fqdn, before, nil)
+	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -48,6 +48,10 @@ func ToBackend(b *models.Backend) api.ApiBackend {
 		UpdatedAt:   updatedAt,
 		AccessLevel: accessLevel,
 		JsScript:    jsScript,
+
+		SessionPolicy:     ToApiSessionPolicy(b.SessionPolicy),
+		MaxAuthAgeSeconds: int64(b.MaxAuthAge.AsDuration().Seconds()),
+		AllowBasicAuth:    b.AllowBasicAuth,
 	}
 }
 
@@ -8,11 +8,13 @@ import (
 	"time"
 
 	"github.com/gorilla/mux"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func (s *ApiModule) handleBackendUpdate(w http.ResponseWriter, r *http.Request) {
-	user, _, err := s.session.GetAndValidate(w, r)
+	user, session, err := s.session.GetAndValidate(w, r)
 	if err != nil {
 		return
 	}
@@ -28,9 +30,24 @@ func (s *ApiModule) handleBackendUpdate(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	var sessionPolicy *models.SessionPolicy
+	if req.SessionPolicy != nil {
+		sessionPolicy, err = toSessionPolicy(*req.SessionPolicy)
+		if err != nil {
+			http.Error(w, "Invalid session policy", http.StatusBadRequest)
+			return
+		}
+	}
+	if req.MaxAuthAgeSeconds != nil && *req.MaxAuthAgeSeconds < 0 {
+		http.Error(w, "Invalid max auth age", http.StatusBadRequest)
+		return
+	}
+
 	fqdn := strings.ToLower(mux.Vars(r)["fqdn"])
 
+	var before, after *models.Backend
 	err = s.db.UpdateBackend(fqdn, func(old *models.Backend) (*models.Backend, error) {
+		before = proto.Clone(old).(*models.Backend)
 		now := time.Now()
 		if old == nil {
 			old = &models.Backend{
@@ -73,7 +90,24 @@ func (s *ApiModule) handleBackendUpdate(w http.ResponseWriter, r *http.Request)
 			old.ScriptHandler = nil
 		}
 
+		if req.SessionPolicy != nil {
+			old.SessionPolicy = sessionPolicy
+		}
+
+		if req.MaxAuthAgeSeconds != nil {
+			if *req.MaxAuthAgeSeconds == 0 {
+				old.MaxAuthAge = nil
+			} else {
+				old.MaxAuthAge = durationpb.New(time.Duration(*req.MaxAuthAgeSeconds) * time.Second)
+			}
+		}
+
+		if req.AllowBasicAuth != nil {
+			old.AllowBasicAuth = *req.AllowBasicAuth
+		}
+
 		old.UpdatedAt = timestamppb.New(now)
+		after = old
 		return old, nil
 	})
 
@@ -81,6 +115,7 @@ func (s *ApiModule) handleBackendUpdate(w http.ResponseWriter, r *http.Request)
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
+	s.audit(r, user, session, "backend.update", fqdn, before, after)
 
 	jsonify(w, api.ApiUpdateBackendResponse{})
 }
@@ -233,4 +233,45 @@ func TestUpdateBackend(t *testing.T) {
 			t.Errorf("Expected jsScript to be empty after clearing, got %q", backends[0].JsScript)
 		}
 	})
+
+	t.Run("update session policy and max auth age", func(t *testing.T) {
+		f, cookie := setupBackendTest(t)
+		rr := f.CreateBackend(cookie, &api.ApiBackend{
+			Fqdn:        "test.example.com",
+			UpstreamUrl: "http://localhost:8080",
+		})
+		if rr.Code != http.StatusOK {
+			t.Fatalf("pre-condition: create backend failed with status %d: %s", rr.Code, rr.Body.String())
+		}
+
+		policy := api.ApiSessionPolicy{IdleTimeoutSeconds: 600}
+		maxAuthAge := int64(300)
+		req := &api.ApiUpdateBackendRequest{
+			SessionPolicy:     &policy,
+			MaxAuthAgeSeconds: &maxAuthAge,
+		}
+		rr = f.request("POST", "/api/backend/test.example.com", req, cookie, nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("request failed with status %d: %s", rr.Code, rr.Body.String())
+		}
+
+		backends := f.ListBackends(cookie)
+		if backends[0].SessionPolicy != policy {
+			t.Errorf("Expected sessionPolicy to be %v, got %v", policy, backends[0].SessionPolicy)
+		}
+		if backends[0].MaxAuthAgeSeconds != maxAuthAge {
+			t.Errorf("Expected maxAuthAgeSeconds to be %d, got %d", maxAuthAge, backends[0].MaxAuthAgeSeconds)
+		}
+
+		// Other updates leave them unchanged.
+		updatedURL := "http://new-upstream:9090"
+		rr = f.request("POST", "/api/backend/test.example.com", &api.ApiUpdateBackendRequest{UpstreamUrl: &updatedURL}, cookie, nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("request failed with status %d: %s", rr.Code, rr.Body.String())
+		}
+		backends = f.ListBackends(cookie)
+		if backends[0].MaxAuthAgeSeconds != maxAuthAge {
+			t.Errorf("Expected maxAuthAgeSeconds to be %d, got %d", maxAuthAge, backends[0].MaxAuthAgeSeconds)
+		}
+	})
 }
@@ -0,0 +1,37 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// handleBanDelete lifts the ban of an IP address. Its earlier bans are
+// forgotten as well, so that the next one is short again.
+func (s *ApiModule) handleBanDelete(w http.ResponseWriter, r *http.Request) {
+	user, session, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	if !user.IsAdmin {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	ip := mux.Vars(r)["ip"]
+	before, err := s.events.Unban(ip)
+	if err != nil {
+		s.log.Warnf("Failed to unban %s: %v", ip, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if before == nil {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	s.audit(r, user, session, "ban.delete", ip, before, nil)
+	s.log.Infof("User %s unbanned %s", user.Email, ip)
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,54 @@
+package rest
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestBanDelete(t *testing.T) {
+	t.Run("unbans", func(t *testing.T) {
+		f, cookie := setupBanTest(t)
+		f.failFrom(cookie, "192.0.2.1", 3)
+
+		rr := f.request("DELETE", "/api/ban/192.0.2.1", nil, cookie, nil)
+		if rr.Code != http.StatusNoContent {
+			t.Fatalf("Expected status %d, got %d", http.StatusNoContent, rr.Code)
+		}
+		if bans := f.listBans(t, cookie); len(bans) != 0 {
+			t.Errorf("Expected no bans, got %+v", bans)
+		}
+		if records := f.listAuditRecords(t, cookie, "?action=ban.delete"); len(records) != 1 {
+			t.Errorf("Expected 1 audit record, got %d", len(records))
+		}
+	})
+
+	t.Run("unbans IPv6 addresses", func(t *testing.T) {
+		f, cookie := setupBanTest(t)
+		f.failFrom(cookie, "2001:db8::1", 3)
+
+		rr := f.request("DELETE", "/api/ban/2001:db8::1", nil, cookie, nil)
+		if rr.Code != http.StatusNoContent {
+			t.Fatalf("Expected status %d, got %d", http.StatusNoContent, rr.Code)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		f, cookie := setupBanTest(t)
+
+		rr := f.request("DELETE", "/api/ban/192.0.2.1", nil, cookie, nil)
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("Expected status %d, got %d", http.StatusNotFound, rr.Code)
+		}
+	})
+
+	t.Run("requires admin", func(t *testing.T) {
+		f, adminCookie := setupBanTest(t)
+		f.failFrom(adminCookie, "192.0.2.1", 3)
+		cookie, _ := f.CreateUser("user@example.com")
+
+		rr := f.request("DELETE", "/api/ban/192.0.2.1", nil, cookie, nil)
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("Expected status %d, got %d", http.StatusForbidden, rr.Code)
+		}
+	})
+}
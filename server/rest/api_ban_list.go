@@ -0,0 +1,40 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/security"
+	"net/http"
+	"time"
+)
+
+func ToApiBan(ban *models.Ban) api.ApiBan {
+	return api.ApiBan{
+		Ip:          ban.Ip,
+		BannedUntil: ban.BannedUntil.AsTime().Format(time.RFC3339),
+		BanCount:    ban.BanCount,
+		Source:      ban.Source,
+	}
+}
+
+// handleBanList lists the IP addresses that are currently banned.
+func (s *ApiModule) handleBanList(w http.ResponseWriter, r *http.Request) {
+	user, _, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	if !user.IsAdmin {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	now := time.Now()
+	ret := make([]api.ApiBan, 0)
+	for _, ban := range s.db.ListBans() {
+		if security.IsBanned(ban, now) {
+			ret = append(ret, ToApiBan(ban))
+		}
+	}
+	jsonify(w, api.ApiListBansResponse{Bans: ret})
+}
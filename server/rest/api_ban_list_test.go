@@ -0,0 +1,115 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/models"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// setupBanTest creates a fixture where clients are banned after three
+// failures, and an administrator.
+func setupBanTest(t *testing.T) (*Fixture, *http.Cookie) {
+	t.Helper()
+	f := CreateFixture(t)
+	f.Config.Update(func(c *models.Configuration) {
+		c.BanPolicy = &models.BanPolicy{
+			MaxFailures: 3,
+			BanDuration: durationpb.New(time.Hour),
+		}
+	})
+	cookie, _ := f.CreateAdmin("admin@example.com")
+	return f, cookie
+}
+
+// failFrom makes requests from `ip` with a forged cookie for the session of
+// `cookie`.
+func (f *Fixture) failFrom(cookie *http.Cookie, ip string, count int) {
+	for i := 0; i < count; i++ {
+		f.requestFrom(ip, "GET", "/api/user/me", nil, forgeCookie(cookie), nil)
+	}
+}
+
+func (f *Fixture) listBans(t *testing.T, cookie *http.Cookie) []api.ApiBan {
+	t.Helper()
+	resp := &api.ApiListBansResponse{}
+	rr := f.request("GET", "/api/ban", nil, cookie, resp)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("list bans failed with status %d: %s", rr.Code, rr.Body.String())
+	}
+	return resp.Bans
+}
+
+func TestBanList(t *testing.T) {
+	t.Run("lists banned addresses", func(t *testing.T) {
+		f, cookie := setupBanTest(t)
+		f.failFrom(cookie, "192.0.2.1", 3)
+		f.failFrom(cookie, "192.0.2.2", 2)
+
+		bans := f.listBans(t, cookie)
+		if len(bans) != 1 {
+			t.Fatalf("Expected 1 ban, got %+v", bans)
+		}
+		if bans[0].Ip != "192.0.2.1" || bans[0].Source != "http" || bans[0].BanCount != 1 {
+			t.Errorf("Unexpected ban %+v", bans[0])
+		}
+	})
+
+	t.Run("banned addresses can't sign in", func(t *testing.T) {
+		f, cookie := setupBanTest(t)
+		f.failFrom(cookie, "192.0.2.1", 3)
+
+		req := &api.ApiSignInEmailRequest{Email: "admin@example.com"}
+		rr := f.requestFrom("192.0.2.1", "POST", "/api/signin/email", req, nil, nil)
+		if rr.Code != http.StatusTooManyRequests {
+			t.Errorf("Expected status %d, got %d", http.StatusTooManyRequests, rr.Code)
+		}
+		rr = f.requestFrom("192.0.2.2", "POST", "/api/signin/email", req, nil, nil)
+		if rr.Code != http.StatusOK {
+			t.Errorf("Expected status %d, got %d", http.StatusOK, rr.Code)
+		}
+	})
+
+	t.Run("sign-in requests are rate limited", func(t *testing.T) {
+		f, _ := setupBanTest(t)
+		f.Config.Update(func(c *models.Configuration) {
+			c.BanPolicy.SigninRequestsPerMinute = 2
+		})
+
+		req := &api.ApiRequestSigninPinRequest{Email: "admin@example.com"}
+		for i, expected := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+			rr := f.requestFrom("192.0.2.1", "POST", "/api/signin/pin/request", req, nil, nil)
+			if rr.Code != expected {
+				t.Errorf("Request %d: expected status %d, got %d", i, expected, rr.Code)
+			}
+		}
+	})
+
+	t.Run("token requests are rate limited", func(t *testing.T) {
+		f, _ := setupBanTest(t)
+		f.Config.Update(func(c *models.Configuration) {
+			c.BanPolicy.TokenRequestsPerMinute = 2
+		})
+
+		req := &api.ApiPollSigninPinRequest{Id: "unknown"}
+		for i, expected := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+			rr := f.requestFrom("192.0.2.1", "POST", "/api/signin/pin/poll", req, nil, nil)
+			if rr.Code != expected {
+				t.Errorf("Request %d: expected status %d, got %d", i, expected, rr.Code)
+			}
+		}
+	})
+
+	t.Run("requires admin", func(t *testing.T) {
+		f, _ := setupBanTest(t)
+		cookie, _ := f.CreateUser("user@example.com")
+
+		rr := f.request("GET", "/api/ban", nil, cookie, nil)
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("Expected status %d, got %d", http.StatusForbidden, rr.Code)
+		}
+	})
+}
@@ -7,8 +7,9 @@ import (
 )
 
 func (s *ApiModule) handleBootstrapStatus(w http.ResponseWriter, r *http.Request) {
+	config := s.config.Get()
 	// Check if server is configured
-	isConfigured := s.config.Email != "" && s.config.SiteFqdn != "" && s.config.AdminFqdn != ""
+	isConfigured := config.Email != "" && config.SiteFqdn != "" && config.AdminFqdn != ""
 
 	jsonify(w, api.ApiBootstrapStatusResponse{
 		IsConfigured: isConfigured,
@@ -16,6 +17,7 @@ func (s *ApiModule) handleBootstrapStatus(w http.ResponseWriter, r *http.Request
 }
 
 func (s *ApiModule) handleBootstrapConfigure(w http.ResponseWriter, r *http.Request) {
+	config := s.config.Get()
 	// Parse the request
 	var req api.ApiBootstrapConfigureRequest
 	err := parseJsonRequest(w, r, &req)
@@ -35,7 +37,7 @@ func (s *ApiModule) handleBootstrapConfigure(w http.ResponseWriter, r *http.Requ
 	}
 
 	// Check if already configured
-	isConfigured := s.config.Email != "" && s.config.SiteFqdn != "" && s.config.AdminFqdn != ""
+	isConfigured := config.Email != "" && config.SiteFqdn != "" && config.AdminFqdn != ""
 	if isConfigured {
 		http.Error(w, "Server is already configured", http.StatusBadRequest)
 		return
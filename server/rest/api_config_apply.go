@@ -0,0 +1,37 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/gitops"
+	"net/http"
+	"time"
+)
+
+func (s *ApiModule) handleConfigApply(w http.ResponseWriter, r *http.Request) {
+	user, session, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	if !user.IsAdmin {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	plan := s.planConfig(w, r, user)
+	if plan == nil {
+		return
+	}
+
+	if err := gitops.Apply(s.db, plan, time.Now()); err != nil {
+		s.log.Warnf("Failed to apply configuration: %v", err)
+		jsonify(w, api.ApiConfigApplyResponse{Changes: []api.ApiConfigChange{}, Error: err.Error()})
+		return
+	}
+	for _, c := range plan.Changes {
+		s.audit(r, user, session, c.Kind+"."+string(c.Action), c.TargetId(), c.Before, c.After)
+	}
+	s.log.Infof("Applied %d configuration changes", len(plan.Changes))
+
+	jsonify(w, api.ApiConfigApplyResponse{Changes: toApiConfigChanges(plan.Changes)})
+}
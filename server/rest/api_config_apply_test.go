@@ -0,0 +1,114 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/models"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigApply(t *testing.T) {
+	t.Run("makes and audits changes", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+		_, userId := f.CreateUserGetId("user@example.com")
+
+		resp := &api.ApiConfigApplyResponse{}
+		rr := f.postConfig(t, "/api/config/apply", cookie, `
+backends:
+  - fqdn: app.example.com
+    upstream_url: http://localhost:8080
+    headers:
+      - name: X-Forwarded-Proto
+        value: https
+users:
+  - email: admin@example.com
+    admin: true
+  - email: new@example.com
+    allowed_hosts: [app.example.com]
+mqtt:
+  profiles:
+    - name: sensors
+      allow_publish: [sensors/#]
+  clients:
+    - name: sensor1
+      password: secret
+      profile: sensors
+`, resp)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		assert.Empty(t, resp.Error)
+		require.Len(t, resp.Changes, 5)
+
+		backends := f.ListBackends(cookie)
+		require.Len(t, backends, 1)
+		assert.Equal(t, "http://localhost:8080", backends[0].UpstreamUrl)
+		require.Len(t, backends[0].Headers, 1)
+
+		user, err := f.Db.GetUserByEmail("new@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"app.example.com"}, user.AllowedHosts)
+		_, err = f.Db.GetUserById(userId)
+		assert.Error(t, err)
+		assert.Len(t, f.ListMqttProfiles(cookie), 1)
+
+		records := f.Db.ListAuditRecords(func(record *models.AuditRecord) bool {
+			return record.Action == "user.delete" || record.Action == "user.create"
+		}, 10)
+		require.Len(t, records, 2)
+		targets := []string{records[0].TargetId, records[1].TargetId}
+		assert.ElementsMatch(t, []string{userId, user.Id}, targets)
+
+		// The password isn't stored in the audit log.
+		records = f.Db.ListAuditRecords(func(record *models.AuditRecord) bool {
+			return record.Action == "mqtt-client.create"
+		}, 10)
+		require.Len(t, records, 1)
+		assert.NotContains(t, records[0].String(), "secret")
+	})
+
+	t.Run("has no changes when applying export", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+		f.CreateBackend(cookie, &api.ApiBackend{Fqdn: "app.example.com", UpstreamUrl: "http://localhost:8080"})
+		exported := f.request("GET", "/api/config", nil, cookie, nil).Body.String()
+
+		resp := &api.ApiConfigApplyResponse{}
+		rr := f.postConfig(t, "/api/config/apply", cookie, exported, resp)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		assert.Empty(t, resp.Changes)
+	})
+
+	t.Run("makes no changes if one fails", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+		f.CreateBackend(cookie, &api.ApiBackend{Fqdn: "app.example.com", UpstreamUrl: "http://localhost:8080"})
+		f.CreateMqttProfile(cookie, &api.ApiMqttProfile{Id: "sensors"})
+		f.CreateMqttClient(cookie, &api.ApiMqttClient{Id: "sensor1", ProfileId: "sensors", Password: "secret"})
+
+		// The profile can't be deleted, as the client isn't.
+		resp := &api.ApiConfigApplyResponse{}
+		rr := f.postConfig(t, "/api/config/apply", cookie, "backends: []\nmqtt: {profiles: []}", resp)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		assert.NotEmpty(t, resp.Error)
+		assert.Empty(t, resp.Changes)
+
+		assert.Len(t, f.ListBackends(cookie), 1)
+		assert.Len(t, f.ListMqttProfiles(cookie), 1)
+		assert.Empty(t, f.Db.ListAuditRecords(func(record *models.AuditRecord) bool {
+			return record.Action == "backend.delete"
+		}, 10))
+	})
+
+	t.Run("is forbidden for non-admin user", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("user@example.com")
+
+		rr := f.postConfig(t, "/api/config/apply", cookie, "backends: [{fqdn: a.example.com}]", nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		_, err := f.Db.GetBackend("a.example.com")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,30 @@
+package rest
+
+import (
+	"boivie/ubergang/server/gitops"
+	"net/http"
+)
+
+func (s *ApiModule) handleConfigExport(w http.ResponseWriter, r *http.Request) {
+	user, _, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	if !user.IsAdmin {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	data, err := gitops.Export(s.db).Marshal()
+	if err != nil {
+		s.log.Warnf("Failed to export configuration: %v", err)
+		http.Error(w, "Failed to export configuration", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-yaml")
+	w.Header().Set("Content-Disposition", "attachment; filename=ubergang.yaml")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
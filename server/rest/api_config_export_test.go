@@ -0,0 +1,44 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/gitops"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigExport(t *testing.T) {
+	t.Run("exports configuration", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+		f.CreateBackend(cookie, &api.ApiBackend{
+			Fqdn:        "app.example.com",
+			UpstreamUrl: "http://localhost:8080",
+			AccessLevel: "PUBLIC",
+		})
+
+		rr := f.request("GET", "/api/config", nil, cookie, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "application/x-yaml", rr.Header().Get("Content-Type"))
+
+		config, err := gitops.Parse(rr.Body.Bytes())
+		require.NoError(t, err)
+		require.Len(t, config.Backends, 1)
+		assert.Equal(t, "app.example.com", config.Backends[0].Fqdn)
+		assert.Equal(t, "PUBLIC", config.Backends[0].AccessLevel)
+		require.Len(t, config.Users, 1)
+		assert.Equal(t, "admin@example.com", config.Users[0].Email)
+		assert.True(t, config.Users[0].Admin)
+	})
+
+	t.Run("is forbidden for non-admin user", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("user@example.com")
+
+		rr := f.request("GET", "/api/config", nil, cookie, nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+}
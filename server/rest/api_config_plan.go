@@ -0,0 +1,81 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/gitops"
+	"boivie/ubergang/server/models"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// The largest configuration that is accepted.
+const maxConfigSize = 4 << 20
+
+func toApiConfigChanges(changes []*gitops.Change) []api.ApiConfigChange {
+	ret := make([]api.ApiConfigChange, 0, len(changes))
+	for _, c := range changes {
+		diff := make([]api.ApiAuditChange, 0)
+		for _, d := range c.Diff() {
+			diff = append(diff, api.ApiAuditChange{
+				Field:  d.Field,
+				Before: d.Before,
+				After:  d.After,
+			})
+		}
+		ret = append(ret, api.ApiConfigChange{
+			Kind:    c.Kind,
+			ID:      c.Id,
+			Action:  string(c.Action),
+			Changes: diff,
+		})
+	}
+	return ret
+}
+
+// planConfig returns the changes needed to apply the configuration in the
+// request body. On failure, it responds to the request and returns nil.
+func (s *ApiModule) planConfig(w http.ResponseWriter, r *http.Request, user *models.User) *gitops.Plan {
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxConfigSize+1))
+	if err != nil || len(data) > maxConfigSize {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return nil
+	}
+	config, err := gitops.Parse(data)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to parse YAML: %v", err), http.StatusBadRequest)
+		return nil
+	}
+	plan, err := gitops.MakePlan(s.db, config)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid configuration: %v", err), http.StatusBadRequest)
+		return nil
+	}
+
+	if err := plan.CheckActor(user.Id); err != nil {
+		http.Error(w, "You cannot remove your own administrator access", http.StatusBadRequest)
+		return nil
+	}
+	return plan
+}
+
+func (s *ApiModule) handleConfigPlan(w http.ResponseWriter, r *http.Request) {
+	user, _, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	if !user.IsAdmin {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	plan := s.planConfig(w, r, user)
+	if plan == nil {
+		return
+	}
+
+	jsonify(w, api.ApiConfigPlanResponse{
+		Changes: toApiConfigChanges(plan.Changes),
+	})
+}
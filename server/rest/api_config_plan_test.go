@@ -0,0 +1,91 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// postConfig posts a YAML configuration to `url`, and decodes the response
+// into `res` if the request succeeded.
+func (f *Fixture) postConfig(t *testing.T, url string, cookie *http.Cookie, yaml string, res interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest("POST", url, strings.NewReader(yaml))
+	req.Host = "test.example.com"
+	req.Header.Set("Content-Type", "application/x-yaml")
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	rr := httptest.NewRecorder()
+	f.router.ServeHTTP(rr, req)
+	if rr.Code == http.StatusOK && res != nil {
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), res))
+	}
+	return rr
+}
+
+func TestConfigPlan(t *testing.T) {
+	t.Run("returns changes without making them", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+		f.CreateBackend(cookie, &api.ApiBackend{Fqdn: "old.example.com", UpstreamUrl: "http://localhost:8080"})
+
+		resp := &api.ApiConfigPlanResponse{}
+		rr := f.postConfig(t, "/api/config/plan", cookie, `
+backends:
+  - fqdn: new.example.com
+    upstream_url: http://localhost:8081
+`, resp)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+		require.Len(t, resp.Changes, 2)
+		assert.Equal(t, "create", resp.Changes[0].Action)
+		assert.Equal(t, "backend", resp.Changes[0].Kind)
+		assert.Equal(t, "new.example.com", resp.Changes[0].ID)
+		assert.Equal(t, "delete", resp.Changes[1].Action)
+		assert.Equal(t, "old.example.com", resp.Changes[1].ID)
+
+		backends := f.ListBackends(cookie)
+		require.Len(t, backends, 1)
+		assert.Equal(t, "old.example.com", backends[0].Fqdn)
+	})
+
+	t.Run("rejects invalid configuration", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+
+		rr := f.postConfig(t, "/api/config/plan", cookie, "backends: [{fqdn: a.example.com, access_level: SECRET}]", nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		rr = f.postConfig(t, "/api/config/plan", cookie, "backends: {", nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("rejects removing your own administrator access", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+
+		for _, yaml := range []string{
+			"users: []",
+			"users: [{email: admin@example.com}]",
+			"users: [{email: admin@example.com, admin: true, disabled: true}]",
+			"users: [{email: Admin@Example.com}]",
+		} {
+			rr := f.postConfig(t, "/api/config/plan", cookie, yaml, nil)
+			assert.Equal(t, http.StatusBadRequest, rr.Code, yaml)
+		}
+	})
+
+	t.Run("is forbidden for non-admin user", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("user@example.com")
+
+		rr := f.postConfig(t, "/api/config/plan", cookie, "backends: []", nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+}
@@ -2,20 +2,36 @@ package rest
 
 import (
 	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/wa"
 	"errors"
 	"net/http"
+	"slices"
 
 	"github.com/gorilla/mux"
 )
 
 func (s *ApiModule) handleCredentialDelete(w http.ResponseWriter, r *http.Request) {
-	user, _, err := s.session.GetAndValidate(w, r)
+	user, session, err := s.session.GetAndValidate(w, r)
 	if err != nil {
 		return
 	}
 
 	id := mux.Vars(r)["id"]
 
+	// Users can't go below the minimum number of passkeys themselves, but
+	// administrators can do it for them, e.g. if a passkey is lost.
+	if !user.IsAdmin {
+		policy := s.config.Get().PasskeyPolicy
+		credentials := s.db.ListCredentials(user.Id)
+		i := slices.IndexFunc(credentials, func(c *models.Credential) bool { return c.Id == id })
+		if i >= 0 && credentials[i].GetWebauthnCredential() != nil && !wa.IsBlocked(policy, credentials[i]) &&
+			wa.CountUsablePasskeys(policy, credentials)-1 < int(policy.GetMinPasskeys()) {
+			http.Error(w, "Too few passkeys would remain", http.StatusConflict)
+			return
+		}
+	}
+
+	var before *models.Credential
 	err = s.db.UpdateCredential(id, func(old *models.Credential) (*models.Credential, error) {
 		if old == nil {
 			return nil, errors.New("credential not found")
@@ -23,6 +39,7 @@ func (s *ApiModule) handleCredentialDelete(w http.ResponseWriter, r *http.Reques
 		if !user.IsAdmin && old.UserId != user.Id {
 			return nil, errors.New("not authorized")
 		}
+		before = old
 		return nil, nil
 	})
 
@@ -30,6 +47,7 @@ func (s *ApiModule) handleCredentialDelete(w http.ResponseWriter, r *http.Reques
 		w.WriteHeader(http.StatusNotFound)
 		return
 	}
+	s.audit(r, user, session, "credential.delete", id, before, nil)
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -36,6 +36,25 @@ func TestDeleteCredential(t *testing.T) {
 		assert.Error(t, err, "Expected credential to be deleted, but it was found")
 	})
 
+	t.Run("keeps the minimum number of passkeys", func(t *testing.T) {
+		f, cookie, _, _ := setupUserWithCredential(t)
+		f.Config.Update(func(c *models.Configuration) {
+			c.PasskeyPolicy = &models.PasskeyPolicy{MinPasskeys: 1}
+		})
+		user := f.getUser(cookie, "me")
+		require.Len(t, user.Credentials, 1)
+		assert.Empty(t, user.PasskeyPolicyViolations)
+
+		rr := f.request("DELETE", "/api/credential/"+user.Credentials[0].ID, nil, cookie, nil)
+		assert.Equal(t, http.StatusConflict, rr.Code)
+
+		// An administrator can do it.
+		adminCookie, _ := f.CreateAdmin("admin@example.com")
+		rr = f.request("DELETE", "/api/credential/"+user.Credentials[0].ID, nil, adminCookie, nil)
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+		assert.Equal(t, []string{"too_few_passkeys"}, f.getUser(cookie, "me").PasskeyPolicyViolations)
+	})
+
 	t.Run("returns not found for non-existent credential", func(t *testing.T) {
 		f := CreateFixture(t)
 		cookie, _ := f.CreateUser("test@example.com")
@@ -7,10 +7,11 @@ import (
 	"net/http"
 
 	"github.com/gorilla/mux"
+	"google.golang.org/protobuf/proto"
 )
 
 func (s *ApiModule) handleCredentialUpdate(w http.ResponseWriter, r *http.Request) {
-	user, _, err := s.session.GetAndValidate(w, r)
+	user, session, err := s.session.GetAndValidate(w, r)
 	if err != nil {
 		return
 	}
@@ -23,6 +24,7 @@ func (s *ApiModule) handleCredentialUpdate(w http.ResponseWriter, r *http.Reques
 
 	credId := mux.Vars(r)["id"]
 
+	var before, after *models.Credential
 	err = s.db.UpdateCredential(credId, func(old *models.Credential) (*models.Credential, error) {
 		if old == nil {
 			return nil, errors.New("credential not found")
@@ -32,15 +34,18 @@ func (s *ApiModule) handleCredentialUpdate(w http.ResponseWriter, r *http.Reques
 			// Return the same error to avoid leaking information about credential existence.
 			return nil, errors.New("credential not found")
 		}
+		before = proto.Clone(old).(*models.Credential)
 		if req.Name != nil {
 			old.Name = *req.Name
 		}
+		after = old
 		return old, nil
 	})
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
+	s.audit(r, user, session, "credential.update", credId, before, after)
 
 	jsonify(w, api.ApiUpdateCredentialResponse{})
 }
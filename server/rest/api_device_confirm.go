@@ -0,0 +1,55 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/wa"
+	"net/http"
+	"time"
+)
+
+func (s *ApiModule) handleDeviceConfirm(w http.ResponseWriter, r *http.Request) {
+	user, session, err := s.session.GetAndValidateSession(w, r)
+	if err != nil {
+		return
+	}
+
+	var req api.ApiConfirmDeviceRequest
+	err = parseJsonRequest(w, r, &req)
+	if err != nil {
+		return
+	}
+
+	state, err := s.db.ConsumeAuthenticationState(req.Token)
+	if err != nil || state.GetConfirmDevice() == nil {
+		jsonify(w, api.ApiConfirmDeviceResponse{
+			Error: &api.ApiConfirmDeviceError{InvalidToken: true}})
+		return
+	}
+
+	if state.UserId != user.Id ||
+		state.GetConfirmDevice().SessionId != session.Id {
+		s.log.Warnf("Token not intended for this user or session")
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	_, err = s.webauthn.ValidateAssertion(&req.Credential, state, wa.NewUser(user, s.db.ListCredentials(user.Id)))
+	if err != nil {
+		s.recordFailedAssertion(r, user, err)
+		jsonify(w, api.ApiConfirmDeviceResponse{
+			Error: &api.ApiConfirmDeviceError{InvalidCredential: true}})
+		return
+	}
+
+	id := state.GetConfirmDevice().DeviceAuthorizationId
+	err = s.auth.DecideDeviceAuthorization(id, user.Id, true, time.Now())
+	if err != nil {
+		s.log.Warnf("Failed to approve device authorization %s: %v", id, err)
+		jsonify(w, api.ApiConfirmDeviceResponse{
+			Error: &api.ApiConfirmDeviceError{InvalidToken: true}})
+		return
+	}
+
+	s.log.Infof("User %s approved device authorization %s", user.Id, id)
+	jsonify(w, api.ApiConfirmDeviceResponse{})
+}
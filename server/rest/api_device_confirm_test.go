@@ -0,0 +1,58 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceConfirm(t *testing.T) {
+	t.Run("successful confirmation", func(t *testing.T) {
+		f, cookie, cred, enrollReq, device := setupDeviceTest(t, "openid")
+		query := f.queryDevice(cookie, device.UserCode)
+		require.Nil(t, query.Error)
+
+		assertion := f.SignAssertionRequest(query.AssertionRequest, enrollReq.Options.User.ID, &cred)
+		resp := &api.ApiConfirmDeviceResponse{}
+		rr := f.request("POST", "/api/device/confirm", &api.ApiConfirmDeviceRequest{
+			Token:      query.Token,
+			Credential: *assertion,
+		}, cookie, resp)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Nil(t, resp.Error)
+
+		token := decodeOAuthToken(t, f.requestOAuthToken(deviceCodeGrant(device.DeviceCode), nil))
+		assert.NotEmpty(t, token.IdToken)
+
+		// The code can't be used again.
+		query = f.queryDevice(cookie, device.UserCode)
+		require.NotNil(t, query.Error)
+		assert.True(t, query.Error.InvalidCode)
+	})
+
+	t.Run("token can only be used once", func(t *testing.T) {
+		f, cookie, cred, enrollReq, device := setupDeviceTest(t, "openid")
+		query := f.queryDevice(cookie, device.UserCode)
+		require.Nil(t, query.Error)
+
+		assertion := f.SignAssertionRequest(query.AssertionRequest, enrollReq.Options.User.ID, &cred)
+		req := &api.ApiConfirmDeviceRequest{Token: query.Token, Credential: *assertion}
+		f.request("POST", "/api/device/confirm", req, cookie, nil)
+
+		resp := &api.ApiConfirmDeviceResponse{}
+		f.request("POST", "/api/device/confirm", req, cookie, resp)
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.InvalidToken)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		f, cookie, _, _, _ := setupDeviceTest(t, "openid")
+		resp := &api.ApiConfirmDeviceResponse{}
+		f.request("POST", "/api/device/confirm", &api.ApiConfirmDeviceRequest{Token: "invalid"}, cookie, resp)
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.InvalidToken)
+	})
+}
@@ -0,0 +1,37 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/auth"
+	"net/http"
+	"time"
+)
+
+func (s *ApiModule) handleDeviceDeny(w http.ResponseWriter, r *http.Request) {
+	user, _, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	var req api.ApiDenyDeviceRequest
+	err = parseJsonRequest(w, r, &req)
+	if err != nil {
+		return
+	}
+
+	authorization, err := s.db.GetDeviceAuthorizationByUserCode(auth.NormalizeUserCode(req.UserCode))
+	if err != nil {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+
+	err = s.auth.DecideDeviceAuthorization(authorization.Id, user.Id, false, time.Now())
+	if err != nil {
+		s.log.Warnf("Failed to deny device authorization %s: %v", authorization.Id, err)
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+
+	s.log.Infof("User %s denied device authorization %s", user.Id, authorization.Id)
+	jsonify(w, api.ApiDenyDeviceResponse{})
+}
@@ -0,0 +1,26 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeviceDeny(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		f, cookie, _, _, device := setupDeviceTest(t, "openid")
+		rr := f.request("POST", "/api/device/deny", &api.ApiDenyDeviceRequest{UserCode: device.UserCode}, cookie, nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		rr = f.requestOAuthToken(deviceCodeGrant(device.DeviceCode), nil)
+		assert.Equal(t, "access_denied", decodeOAuthError(t, rr))
+	})
+
+	t.Run("unknown code", func(t *testing.T) {
+		f, cookie, _, _, _ := setupDeviceTest(t, "openid")
+		rr := f.request("POST", "/api/device/deny", &api.ApiDenyDeviceRequest{UserCode: "BBBB-BBBB"}, cookie, nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
@@ -0,0 +1,65 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/auth"
+	"boivie/ubergang/server/models"
+	"net/http"
+	"slices"
+	"time"
+)
+
+func (s *ApiModule) handleDeviceQuery(w http.ResponseWriter, r *http.Request) {
+	user, session, err := s.session.GetAndValidateSession(w, r)
+	if err != nil {
+		return
+	}
+
+	var req api.ApiQueryDeviceRequest
+	err = parseJsonRequest(w, r, &req)
+	if err != nil {
+		return
+	}
+
+	authorization, err := s.db.GetDeviceAuthorizationByUserCode(auth.NormalizeUserCode(req.UserCode))
+	if err != nil || authorization.UserId != "" || authorization.Denied ||
+		time.Now().After(authorization.ExpiresAt.AsTime()) {
+		jsonify(w, api.ApiQueryDeviceResponse{
+			Error: &api.ApiQueryDeviceError{InvalidCode: true}})
+		return
+	}
+
+	client, err := s.db.GetOidcClient(authorization.ClientId)
+	if err != nil || !s.auth.IsGroupAllowed(client, user) ||
+		(slices.ContainsFunc(authorization.Scopes, auth.IsAdminScope) && !user.IsAdmin) {
+		jsonify(w, api.ApiQueryDeviceResponse{
+			Error: &api.ApiQueryDeviceError{NotAllowed: true}})
+		return
+	}
+
+	credentials := s.db.ListCredentials(user.Id)
+	token, assertionRequest, err :=
+		s.webauthn.CreateAssertion(user, credentials, func(state *models.AuthenticationState) {
+			state.Type = &models.AuthenticationState_ConfirmDevice{
+				ConfirmDevice: &models.AuthenticationStateConfirmDevice{
+					DeviceAuthorizationId: authorization.Id,
+					SessionId:             session.Id,
+				},
+			}
+		})
+	if err != nil {
+		jsonify(w, api.ApiQueryDeviceResponse{
+			Error: &api.ApiQueryDeviceError{InvalidCredentials: true}})
+		return
+	}
+
+	jsonify(w, api.ApiQueryDeviceResponse{
+		UserCode:           auth.FormatUserCode(authorization.UserCode),
+		ClientName:         client.Name,
+		Scopes:             authorization.Scopes,
+		RequestorUserAgent: authorization.UserAgent,
+		RequestorIP:        authorization.Ip,
+		Token:              token,
+		AssertionRequest:   assertionRequest,
+	})
+}
@@ -0,0 +1,75 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/descope/virtualwebauthn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupDeviceTest creates a client "app", and a signed in user with an
+// enrolled credential, and starts a device authorization for `scope`.
+func setupDeviceTest(t *testing.T, scope string) (*Fixture, *http.Cookie, virtualwebauthn.Credential, *api.ApiEnrollRequest, *api.ApiDeviceAuthorizationResponse) {
+	t.Helper()
+	f, _, userCookie := setupOidcTest(t, true)
+	enrollResp, err := f.StartEnroll(userCookie)
+	require.NoError(t, err)
+	enrollReq := enrollResp.EnrollRequest
+	cred, res := f.GenerateCredential(enrollReq)
+	_, err = f.FinishEnroll(userCookie, enrollReq.Token, res)
+	require.NoError(t, err)
+
+	return f, userCookie, cred, enrollReq, f.startDeviceAuthorization(t, scope)
+}
+
+func (f *Fixture) queryDevice(cookie *http.Cookie, userCode string) *api.ApiQueryDeviceResponse {
+	resp := &api.ApiQueryDeviceResponse{}
+	f.request("POST", "/api/device/query", &api.ApiQueryDeviceRequest{UserCode: userCode}, cookie, resp)
+	return resp
+}
+
+func TestDeviceQuery(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		f, cookie, _, _, device := setupDeviceTest(t, "openid proxy")
+
+		// The user code is accepted as typed, e.g. in lower case.
+		resp := f.queryDevice(cookie, strings.ToLower(strings.ReplaceAll(device.UserCode, "-", " ")))
+		require.Nil(t, resp.Error)
+		assert.Equal(t, device.UserCode, resp.UserCode)
+		assert.Equal(t, []string{"openid", "proxy"}, resp.Scopes)
+		assert.Equal(t, "test-cli", resp.RequestorUserAgent)
+		assert.NotEmpty(t, resp.Token)
+		assert.NotNil(t, resp.AssertionRequest)
+	})
+
+	t.Run("invalid code", func(t *testing.T) {
+		f, cookie, _, _, _ := setupDeviceTest(t, "openid")
+		resp := f.queryDevice(cookie, "BBBB-BBBB")
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.InvalidCode)
+	})
+
+	t.Run("scim scope requires admin", func(t *testing.T) {
+		f, cookie, _, _, device := setupDeviceTest(t, "scim")
+		resp := f.queryDevice(cookie, device.UserCode)
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.NotAllowed)
+	})
+
+	t.Run("admin scope requires admin", func(t *testing.T) {
+		f, cookie, _, _, device := setupDeviceTest(t, "admin")
+		resp := f.queryDevice(cookie, device.UserCode)
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.NotAllowed)
+	})
+
+	t.Run("not signed in", func(t *testing.T) {
+		f, _, _, _, device := setupDeviceTest(t, "openid")
+		rr := f.request("POST", "/api/device/query", &api.ApiQueryDeviceRequest{UserCode: device.UserCode}, nil, nil)
+		assert.NotEqual(t, http.StatusOK, rr.Code)
+	})
+}
@@ -3,13 +3,15 @@ package rest
 import (
 	"boivie/ubergang/server/api"
 	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/notify"
+	"boivie/ubergang/server/wa"
 
 	"errors"
 	"net/http"
 )
 
 func (s *ApiModule) handleEnrollFinish(w http.ResponseWriter, r *http.Request) {
-	user, session, err := s.session.GetAndValidate(w, r)
+	user, session, err := s.session.GetAndValidateSession(w, r)
 	if err != nil {
 		return
 	}
@@ -33,7 +35,12 @@ func (s *ApiModule) handleEnrollFinish(w http.ResponseWriter, r *http.Request) {
 	}
 
 	cred, err := s.webauthn.CreateCredential(user, session, state, &req.AttestationResponse)
-	if err != nil {
+	if errors.Is(err, wa.ErrAuthenticatorNotAllowed) {
+		s.log.Infof("User %s tried to enroll a passkey that isn't allowed", user.Id)
+		jsonify(w, api.ApiFinishEnrollResponse{
+			Error: &api.ApiFinishEnrollError{AuthenticatorNotAllowed: true}})
+		return
+	} else if err != nil {
 		jsonify(w, api.ApiFinishEnrollResponse{
 			Error: &api.ApiFinishEnrollError{InvalidEnrollment: true}})
 		return
@@ -49,6 +56,10 @@ func (s *ApiModule) handleEnrollFinish(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	apiCredential := ToApiCredential(cred)
+	event := notify.NewEvent(notify.EventCredentialEnrolled, user, s.events.ClientIP(r), r.UserAgent())
+	event.Name = cred.Name
+	s.notifier.Notify(event)
+
+	apiCredential := ToApiCredential(cred, s.config.Get().PasskeyPolicy)
 	jsonify(w, api.ApiFinishEnrollResponse{Credential: &apiCredential})
 }
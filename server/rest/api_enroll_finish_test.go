@@ -2,6 +2,8 @@ package rest
 
 import (
 	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/notify"
 	"net/http"
 	"testing"
 
@@ -76,4 +78,39 @@ func TestFinishEnroll(t *testing.T) {
 		_, err := f.FinishEnroll(nil, request.Token, res)
 		require.Error(t, err, "Expected error when trying to enroll without being logged in")
 	})
+
+	t.Run("rejects authenticators that aren't allowed", func(t *testing.T) {
+		f, cookie, request := setupEnrollment(t)
+		f.Config.Update(func(c *models.Configuration) {
+			c.PasskeyPolicy = &models.PasskeyPolicy{
+				AllowedAaguids: []string{"adce0002-35bc-c60a-648b-0b25f1f05503"},
+			}
+		})
+
+		_, res := f.GenerateCredential(request)
+		resp := &api.ApiFinishEnrollResponse{}
+		rr := f.request("POST", "/api/enroll/finish", &api.ApiFinishEnrollRequest{
+			Token:               request.Token,
+			AttestationResponse: *res,
+		}, cookie, resp)
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.AuthenticatorNotAllowed)
+		assert.Empty(t, f.getUser(cookie, "me").Credentials)
+	})
+
+	t.Run("notifies about the new credential", func(t *testing.T) {
+		f, cookie, request := setupEnrollment(t)
+		recorder := f.RecordNotifications()
+
+		_, res := f.GenerateCredential(request)
+		enrollResp, err := f.FinishEnroll(cookie, request.Token, res)
+		require.NoError(t, err)
+
+		events := recorder.Events()
+		require.Len(t, events, 1)
+		assert.Equal(t, notify.EventCredentialEnrolled, events[0].Type)
+		assert.Equal(t, "test", events[0].UserEmail)
+		assert.Equal(t, enrollResp.Credential.Name, events[0].Name)
+	})
 }
@@ -2,13 +2,15 @@ package rest
 
 import (
 	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/wa"
+	"errors"
 	"net/http"
 
 	"github.com/google/uuid"
 )
 
 func (s *ApiModule) handleEnrollStart(w http.ResponseWriter, r *http.Request) {
-	user, session, err := s.session.GetAndValidate(w, r)
+	user, session, err := s.session.GetAndValidateSession(w, r)
 	if err != nil {
 		return
 	}
@@ -19,8 +21,13 @@ func (s *ApiModule) handleEnrollStart(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	state, options, err := s.webauthn.CreateEnrollRequest(user, session.Id, s.db.ListCredentials(user.Id))
-	if err != nil {
+	state, options, err := s.webauthn.CreateEnrollRequest(user, session.Id, s.db.ListCredentials(user.Id), req.Attachment)
+	if errors.Is(err, wa.ErrAttachmentNotAllowed) {
+		jsonify(w, api.ApiStartEnrollResponse{
+			Error: &api.ApiEnrollStartError{AttachmentNotAllowed: true},
+		})
+		return
+	} else if err != nil {
 		s.log.Warn("Failed to create enroll request")
 		w.WriteHeader(http.StatusInternalServerError)
 		return
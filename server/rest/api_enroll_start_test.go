@@ -1,6 +1,9 @@
 package rest
 
 import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/models"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -33,4 +36,71 @@ func TestStartEnroll(t *testing.T) {
 		assert.Nil(t, resp.EnrollRequest)
 		assert.Nil(t, resp.Error)
 	})
+
+	t.Run("uses the WebAuthn settings", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("test@example.com")
+
+		resp, err := f.StartEnroll(cookie)
+		require.NoError(t, err)
+		selection := resp.EnrollRequest.Options.AuthenticatorSelection
+		assert.Equal(t, "platform", selection.AuthenticatorAttachment)
+		assert.Equal(t, "required", selection.ResidentKey)
+		assert.Equal(t, "required", selection.UserVerification)
+		assert.Equal(t, []string{"client-device"}, resp.EnrollRequest.Options.Hints)
+
+		f.Config.Update(func(c *models.Configuration) {
+			c.Webauthn = &models.WebauthnSettings{
+				Attachment:       "cross-platform",
+				UserVerification: "discouraged",
+				ResidentKey:      "preferred",
+			}
+		})
+		resp, err = f.StartEnroll(cookie)
+		require.NoError(t, err)
+		selection = resp.EnrollRequest.Options.AuthenticatorSelection
+		assert.Equal(t, "cross-platform", selection.AuthenticatorAttachment)
+		assert.Equal(t, "preferred", selection.ResidentKey)
+		assert.Equal(t, "discouraged", selection.UserVerification)
+		assert.Equal(t, []string{"security-key"}, resp.EnrollRequest.Options.Hints)
+	})
+
+	t.Run("lets the user choose where to store the passkey", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("test@example.com")
+		f.Config.Update(func(c *models.Configuration) {
+			c.Webauthn = &models.WebauthnSettings{Attachment: "any"}
+		})
+
+		resp := &api.ApiStartEnrollResponse{}
+		rr := f.request("POST", "/api/enroll/start", &api.ApiStartEnrollRequest{}, cookie, resp)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Empty(t, resp.EnrollRequest.Options.AuthenticatorSelection.AuthenticatorAttachment)
+		assert.Empty(t, resp.EnrollRequest.Options.Hints)
+
+		resp = &api.ApiStartEnrollResponse{}
+		rr = f.request("POST", "/api/enroll/start", &api.ApiStartEnrollRequest{Attachment: "cross-platform"}, cookie, resp)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "cross-platform", resp.EnrollRequest.Options.AuthenticatorSelection.AuthenticatorAttachment)
+
+		// The chosen attachment is stored with the passkey.
+		_, attestation := f.GenerateCredential(resp.EnrollRequest)
+		finishResp, err := f.FinishEnroll(cookie, resp.EnrollRequest.Token, attestation)
+		require.NoError(t, err)
+		assert.Equal(t, "cross-platform", finishResp.Credential.Attachment)
+	})
+
+	t.Run("rejects attachments not allowed by the settings", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("test@example.com")
+
+		for _, attachment := range []string{"cross-platform", "any", "usb"} {
+			resp := &api.ApiStartEnrollResponse{}
+			rr := f.request("POST", "/api/enroll/start", &api.ApiStartEnrollRequest{Attachment: attachment}, cookie, resp)
+			require.Equal(t, http.StatusOK, rr.Code)
+			require.NotNil(t, resp.Error)
+			assert.True(t, resp.Error.AttachmentNotAllowed)
+			assert.Nil(t, resp.EnrollRequest)
+		}
+	})
 }
@@ -0,0 +1,56 @@
+package rest
+
+import (
+	"boivie/ubergang/server/models"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"google.golang.org/protobuf/proto"
+)
+
+var errUserNotFound = errors.New("user not found")
+
+func removeFederatedIdentity(identities []*models.FederatedIdentity, providerId string) []*models.FederatedIdentity {
+	ret := make([]*models.FederatedIdentity, 0)
+	for _, identity := range identities {
+		if identity.ProviderId != providerId {
+			ret = append(ret, identity)
+		}
+	}
+	return ret
+}
+
+// handleFederatedIdentityDelete unlinks the user's identity at an upstream
+// provider. Users can unlink their own identities.
+func (s *ApiModule) handleFederatedIdentityDelete(w http.ResponseWriter, r *http.Request) {
+	sessionUser, session, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	userId := mux.Vars(r)["id"]
+	if !sessionUser.IsAdmin && sessionUser.Id != userId {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	providerId := mux.Vars(r)["provider"]
+	var before, after *models.User
+	err = s.db.UpdateUser(userId, func(old *models.User) (*models.User, error) {
+		if old == nil {
+			return nil, errUserNotFound
+		}
+		before = proto.Clone(old).(*models.User)
+		after = old
+		old.FederatedIdentities = removeFederatedIdentity(old.FederatedIdentities, providerId)
+		return old, nil
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	s.audit(r, sessionUser, session, "user.unlink-identity", userId, before, after)
+
+	w.WriteHeader(http.StatusNoContent)
+}
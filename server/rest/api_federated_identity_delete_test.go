@@ -0,0 +1,23 @@
+package rest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteFederatedIdentity(t *testing.T) {
+	f, issuer, _ := setupUpstreamTest(t, false)
+	userCookie, userId := f.CreateUserGetId("user@example.com")
+	f.signinUpstream(t, issuer, userCookie, "link=true")
+	require.Len(t, f.getUser(userCookie, "me").FederatedIdentities, 1)
+
+	rr := f.request("DELETE", "/api/user/"+userId+"/federated-identity/idp", nil, userCookie, nil)
+	require.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Empty(t, f.getUser(userCookie, "me").FederatedIdentities)
+
+	rr = f.signinUpstream(t, issuer, nil, "")
+	assert.Equal(t, "/signin?error=not_linked", rr.Header().Get("Location"))
+}
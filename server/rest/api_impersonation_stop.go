@@ -0,0 +1,42 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"time"
+)
+
+func (s *ApiModule) handleImpersonationStop(w http.ResponseWriter, r *http.Request) {
+	// Impersonation sessions can't be used to make changes, except ending
+	// them.
+	user, session, err := s.session.Get(r)
+	if err != nil {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+	if session.ImpersonatorId == "" {
+		http.Error(w, "Not viewing the site as another user", http.StatusBadRequest)
+		return
+	}
+	admin, err := s.session.Impersonator(session)
+	if err != nil {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	if err := s.db.DeleteSession(session.Id); err != nil {
+		s.log.Errorf("Error deleting session %s: %v", session.Id, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	s.log.Infof("User %s stopped viewing the site as user %s", admin.Id, user.Id)
+
+	var resp api.ApiImpersonationStopResponse
+	_, adminSession, err := s.db.GetSession(session.ImpersonatorSessionId)
+	if err == nil && adminSession.UserId == admin.Id && !s.session.IsStale(adminSession, time.Now()) {
+		resp.Cookie = s.session.CreateSessionCookie(adminSession).String()
+	}
+	s.audit(r, admin, adminSession, "user.impersonate.stop", user.Id, session, nil)
+
+	jsonify(w, resp)
+}
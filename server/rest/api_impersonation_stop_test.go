@@ -0,0 +1,65 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/models"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImpersonationStop(t *testing.T) {
+	t.Run("restores the administrator's session", func(t *testing.T) {
+		f := CreateFixture(t)
+		adminCookie, adminId := f.CreateAdminGetId("admin@example.com")
+		_, userId := f.CreateUserGetId("user@example.com")
+		cookie := f.impersonate(t, adminCookie, userId)
+
+		resp := &api.ApiImpersonationStopResponse{}
+		rr := f.request("DELETE", "/api/impersonation", nil, cookie, resp)
+		require.Equal(t, http.StatusOK, rr.Code)
+		cookies := (&http.Response{Header: http.Header{"Set-Cookie": {resp.Cookie}}}).Cookies()
+		require.NotEmpty(t, cookies)
+
+		me := f.getUser(cookies[0], "me")
+		assert.Equal(t, adminId, me.ID)
+		assert.Nil(t, me.Impersonation)
+
+		// The impersonation session is gone.
+		assert.Len(t, f.Db.ListSessions(userId), 1)
+		rr = f.request("GET", "/api/user/me", nil, cookie, nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+
+		records := f.Db.ListAuditRecords(func(record *models.AuditRecord) bool {
+			return record.Action == "user.impersonate.stop"
+		}, 10)
+		require.Len(t, records, 1)
+		assert.Equal(t, adminId, records[0].ActorId)
+	})
+
+	t.Run("returns no cookie if the administrator's session is gone", func(t *testing.T) {
+		f := CreateFixture(t)
+		adminCookie, adminId := f.CreateAdminGetId("admin@example.com")
+		_, userId := f.CreateUserGetId("user@example.com")
+		cookie := f.impersonate(t, adminCookie, userId)
+		for _, session := range f.Db.ListSessions(adminId) {
+			require.NoError(t, f.Db.DeleteSession(session.Id))
+		}
+
+		resp := &api.ApiImpersonationStopResponse{}
+		rr := f.request("DELETE", "/api/impersonation", nil, cookie, resp)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Empty(t, resp.Cookie)
+		assert.Len(t, f.Db.ListSessions(userId), 1)
+	})
+
+	t.Run("requires an impersonation session", func(t *testing.T) {
+		f := CreateFixture(t)
+		adminCookie, _ := f.CreateAdmin("admin@example.com")
+
+		rr := f.request("DELETE", "/api/impersonation", nil, adminCookie, nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
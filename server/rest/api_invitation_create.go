@@ -0,0 +1,100 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/auth"
+	"boivie/ubergang/server/mail"
+	"boivie/ubergang/server/models"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+func (s *ApiModule) invitationUrl(token string) string {
+	return fmt.Sprintf("https://%s/invite/%s", s.config.Get().AdminFqdn, token)
+}
+
+// invitationLifetime returns how long invitations are valid, given the
+// requested number of seconds.
+func invitationLifetime(seconds int64) (time.Duration, error) {
+	if seconds == 0 {
+		return auth.InvitationLifetime, nil
+	}
+	lifetime := time.Duration(seconds) * time.Second
+	if seconds < 0 || lifetime > auth.MaxInvitationLifetime {
+		return 0, errors.New("invalid lifetime")
+	}
+	return lifetime, nil
+}
+
+// invite creates `invitation` on behalf of `admin`, and returns the link to
+// redeem it.
+func (s *ApiModule) invite(r *http.Request, admin *models.User, session *models.Session, invitation *models.Invitation, lifetime time.Duration, sendEmail bool) (url string, emailSent bool, err error) {
+	invitation.CreatedBy = admin.Id
+	token, err := s.auth.CreateInvitation(invitation, lifetime, time.Now())
+	if err != nil {
+		return
+	}
+	s.audit(r, admin, session, "invitation.create", invitation.Id, nil, invitation)
+	url = s.invitationUrl(token)
+	if sendEmail {
+		if err := s.mailer.Send(invitation.Email, mail.Invitation(s.config.Get().AdminFqdn, url, lifetime)); err != nil {
+			s.log.Warnf("Failed to send invitation %s: %v", invitation.Id, err)
+		} else {
+			emailSent = true
+		}
+	}
+	return
+}
+
+func (s *ApiModule) handleInvitationCreate(w http.ResponseWriter, r *http.Request) {
+	user, session, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	if !user.IsAdmin {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	var req api.ApiCreateInvitationRequest
+	err = parseJsonRequest(w, r, &req)
+	if err != nil {
+		return
+	}
+
+	if req.Email == "" {
+		http.Error(w, "Email is required", http.StatusBadRequest)
+		return
+	}
+	lifetime, err := invitationLifetime(req.LifetimeSeconds)
+	if err != nil {
+		http.Error(w, "Invalid lifetime", http.StatusBadRequest)
+		return
+	}
+
+	invitation := &models.Invitation{
+		Email:        req.Email,
+		DisplayName:  req.DisplayName,
+		IsAdmin:      req.Admin,
+		AllowedHosts: req.AllowedHosts,
+		Groups:       req.Groups,
+	}
+	url, emailSent, err := s.invite(r, user, session, invitation, lifetime, req.SendEmail)
+	if errors.Is(err, auth.ErrUserExists) {
+		http.Error(w, "User already exists", http.StatusConflict)
+		return
+	} else if err != nil {
+		s.log.Warnf("Failed to create invitation: %v", err)
+		http.Error(w, "Failed to create invitation", http.StatusBadRequest)
+		return
+	}
+
+	jsonify(w, api.ApiCreateInvitationResponse{
+		Invitation: ToApiInvitation(invitation, time.Now()),
+		Url:        url,
+		EmailSent:  emailSent,
+	})
+}
@@ -0,0 +1,135 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/auth"
+	"boivie/ubergang/server/models"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (f *Fixture) createInvitation(t *testing.T, cookie *http.Cookie, req *api.ApiCreateInvitationRequest) *api.ApiCreateInvitationResponse {
+	t.Helper()
+	resp := &api.ApiCreateInvitationResponse{}
+	rr := f.request("POST", "/api/invitation", req, cookie, resp)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	return resp
+}
+
+// invitationToken returns the token in an invitation link.
+func invitationToken(t *testing.T, url string) string {
+	t.Helper()
+	token, found := strings.CutPrefix(url, "https://test.example.com/invite/")
+	require.True(t, found, "unexpected invitation link %s", url)
+	return token
+}
+
+func TestInvitationCreate(t *testing.T) {
+	t.Run("creates pending invitation", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, adminId := f.CreateAdminGetId("admin@example.com")
+
+		resp := f.createInvitation(t, cookie, &api.ApiCreateInvitationRequest{
+			Email:        " user@example.com ",
+			Admin:        true,
+			AllowedHosts: []string{"app.example.com"},
+			Groups:       []string{"staff"},
+		})
+
+		assert.Equal(t, "user@example.com", resp.Invitation.Email)
+		assert.Equal(t, "user@example.com", resp.Invitation.DisplayName)
+		assert.True(t, resp.Invitation.IsAdmin)
+		assert.Equal(t, []string{"app.example.com"}, resp.Invitation.AllowedHosts)
+		assert.Equal(t, []string{"staff"}, resp.Invitation.Groups)
+		assert.Equal(t, "pending", resp.Invitation.State)
+		assert.Equal(t, adminId, resp.Invitation.CreatedBy)
+		assert.False(t, resp.EmailSent)
+		expiresAt, err := time.Parse(time.RFC3339, resp.Invitation.ExpiresAt)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(auth.InvitationLifetime), expiresAt, time.Minute)
+
+		// The user doesn't exist until the invitation is redeemed.
+		invitationToken(t, resp.Url)
+		_, err = f.Db.GetUserByEmail("user@example.com")
+		assert.Error(t, err)
+
+		records := f.Db.ListAuditRecords(func(record *models.AuditRecord) bool {
+			return record.Action == "invitation.create"
+		}, 10)
+		require.Len(t, records, 1)
+		assert.Equal(t, resp.Invitation.ID, records[0].TargetId)
+	})
+
+	t.Run("sends e-mail", func(t *testing.T) {
+		f := CreateFixture(t)
+		server := f.startMailServer(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+
+		resp := f.createInvitation(t, cookie, &api.ApiCreateInvitationRequest{
+			Email:     "user@example.com",
+			SendEmail: true,
+		})
+
+		assert.True(t, resp.EmailSent)
+		messages := server.Messages()
+		require.Len(t, messages, 1)
+		assert.Equal(t, []string{"user@example.com"}, messages[0].To)
+		assert.Contains(t, messages[0].Body, resp.Url)
+	})
+
+	t.Run("uses requested lifetime", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+
+		resp := f.createInvitation(t, cookie, &api.ApiCreateInvitationRequest{
+			Email:           "user@example.com",
+			LifetimeSeconds: 3600,
+		})
+
+		expiresAt, err := time.Parse(time.RFC3339, resp.Invitation.ExpiresAt)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Minute)
+	})
+
+	t.Run("rejects invalid lifetime", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+
+		for _, seconds := range []int64{-1, int64((auth.MaxInvitationLifetime + time.Hour).Seconds())} {
+			req := &api.ApiCreateInvitationRequest{Email: "user@example.com", LifetimeSeconds: seconds}
+			rr := f.request("POST", "/api/invitation", req, cookie, nil)
+			assert.Equal(t, http.StatusBadRequest, rr.Code, "lifetime %d", seconds)
+		}
+	})
+
+	t.Run("requires e-mail", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+
+		rr := f.request("POST", "/api/invitation", &api.ApiCreateInvitationRequest{}, cookie, nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("rejects existing user", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+		f.CreateUser("user@example.com")
+
+		rr := f.request("POST", "/api/invitation", &api.ApiCreateInvitationRequest{Email: "user@example.com"}, cookie, nil)
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+
+	t.Run("is forbidden for non-admin user", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("user@example.com")
+
+		rr := f.request("POST", "/api/invitation", &api.ApiCreateInvitationRequest{Email: "other@example.com"}, cookie, nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		assert.Empty(t, f.Db.ListInvitations())
+	})
+}
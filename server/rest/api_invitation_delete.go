@@ -0,0 +1,41 @@
+package rest
+
+import (
+	"boivie/ubergang/server/auth"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// handleInvitationDelete revokes an invitation. It's kept, so that it's clear
+// why the link no longer works.
+func (s *ApiModule) handleInvitationDelete(w http.ResponseWriter, r *http.Request) {
+	user, session, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	if !user.IsAdmin {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	before, after, err := s.auth.RevokeInvitation(id, time.Now())
+	if errors.Is(err, auth.ErrInvitationInvalid) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	} else if errors.Is(err, auth.ErrInvitationRedeemed) {
+		http.Error(w, "Invitation has already been used", http.StatusConflict)
+		return
+	} else if err != nil {
+		s.log.Errorf("Error revoking invitation %s: %v", id, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	s.audit(r, user, session, "invitation.revoke", id, before, after)
+
+	w.WriteHeader(http.StatusNoContent)
+}
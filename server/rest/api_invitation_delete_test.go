@@ -0,0 +1,65 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/models"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvitationDelete(t *testing.T) {
+	t.Run("revokes invitation", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+		created := f.createInvitation(t, cookie, &api.ApiCreateInvitationRequest{Email: "user@example.com"})
+
+		rr := f.request("DELETE", "/api/invitation/"+created.Invitation.ID, nil, cookie, nil)
+		require.Equal(t, http.StatusNoContent, rr.Code)
+
+		invitations := f.listInvitations(t, cookie)
+		require.Len(t, invitations, 1)
+		assert.Equal(t, "revoked", invitations[0].State)
+
+		resp := f.redeemInvitation(t, invitationToken(t, created.Url))
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.Revoked)
+
+		records := f.Db.ListAuditRecords(func(record *models.AuditRecord) bool {
+			return record.Action == "invitation.revoke"
+		}, 10)
+		require.Len(t, records, 1)
+		assert.Equal(t, created.Invitation.ID, records[0].TargetId)
+	})
+
+	t.Run("can't revoke redeemed invitation", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+		created := f.createInvitation(t, cookie, &api.ApiCreateInvitationRequest{Email: "user@example.com"})
+		require.NotNil(t, f.redeemInvitation(t, invitationToken(t, created.Url)).Success)
+
+		rr := f.request("DELETE", "/api/invitation/"+created.Invitation.ID, nil, cookie, nil)
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+
+	t.Run("returns not found for non-existent invitation", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+
+		rr := f.request("DELETE", "/api/invitation/non-existent-id", nil, cookie, nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("is forbidden for non-admin user", func(t *testing.T) {
+		f := CreateFixture(t)
+		adminCookie, _ := f.CreateAdmin("admin@example.com")
+		cookie, _ := f.CreateUser("user@example.com")
+		created := f.createInvitation(t, adminCookie, &api.ApiCreateInvitationRequest{Email: "other@example.com"})
+
+		rr := f.request("DELETE", "/api/invitation/"+created.Invitation.ID, nil, cookie, nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		assert.Equal(t, "pending", f.listInvitations(t, adminCookie)[0].State)
+	})
+}
@@ -0,0 +1,67 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/auth"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+// The largest file of invitees that is accepted.
+const maxInvitationImportSize = 1 << 20
+
+func (s *ApiModule) handleInvitationImport(w http.ResponseWriter, r *http.Request) {
+	user, session, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	if !user.IsAdmin {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	q := r.URL.Query()
+	sendEmail := q.Get("sendEmail") == "true"
+	var seconds int64
+	if v := q.Get("lifetimeSeconds"); v != "" {
+		if seconds, err = strconv.ParseInt(v, 10, 64); err != nil {
+			http.Error(w, "Invalid lifetime", http.StatusBadRequest)
+			return
+		}
+	}
+	lifetime, err := invitationLifetime(seconds)
+	if err != nil {
+		http.Error(w, "Invalid lifetime", http.StatusBadRequest)
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxInvitationImportSize+1))
+	if err != nil || len(data) > maxInvitationImportSize {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	invitees, err := auth.ParseInvitees(data, mediaType == "text/csv")
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to parse invitees: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	resp := api.ApiImportInvitationsResponse{Invitations: make([]api.ApiImportedInvitation, 0, len(invitees))}
+	for _, invitee := range invitees {
+		invitation := invitee.Invitation()
+		result := api.ApiImportedInvitation{Email: invitation.Email}
+		result.Url, result.EmailSent, err = s.invite(r, user, session, invitation, lifetime, sendEmail)
+		if err != nil {
+			result.Error = err.Error()
+		}
+		resp.Invitations = append(resp.Invitations, result)
+	}
+	s.log.Infof("Imported %d invitations", len(invitees))
+
+	jsonify(w, resp)
+}
@@ -0,0 +1,117 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (f *Fixture) importInvitations(t *testing.T, cookie *http.Cookie, query, contentType, body string, res *api.ApiImportInvitationsResponse) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest("POST", "/api/invitation/import"+query, strings.NewReader(body))
+	req.Host = "test.example.com"
+	req.Header.Set("Content-Type", contentType)
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	rr := httptest.NewRecorder()
+	f.router.ServeHTTP(rr, req)
+	if rr.Code == http.StatusOK && res != nil {
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), res))
+	}
+	return rr
+}
+
+func TestInvitationImport(t *testing.T) {
+	t.Run("imports CSV", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+
+		csv := "email,display_name,admin,allowed_hosts,groups\n" +
+			"alice@example.com,Alice,true,,\n" +
+			"bob@example.com,,false,app.example.com;wiki.example.com,staff\n"
+		resp := &api.ApiImportInvitationsResponse{}
+		rr := f.importInvitations(t, cookie, "", "text/csv", csv, resp)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+		require.Len(t, resp.Invitations, 2)
+		for _, inv := range resp.Invitations {
+			assert.Empty(t, inv.Error)
+			invitationToken(t, inv.Url)
+		}
+
+		invitations := f.listInvitations(t, cookie)
+		require.Len(t, invitations, 2)
+		assert.Equal(t, "Alice", invitations[0].DisplayName)
+		assert.True(t, invitations[0].IsAdmin)
+		assert.Equal(t, []string{"app.example.com", "wiki.example.com"}, invitations[1].AllowedHosts)
+		assert.Equal(t, []string{"staff"}, invitations[1].Groups)
+	})
+
+	t.Run("imports YAML", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+
+		yaml := `
+- email: alice@example.com
+  admin: true
+- email: bob@example.com
+  allowed_hosts: [app.example.com]
+  groups: [staff]
+`
+		resp := &api.ApiImportInvitationsResponse{}
+		rr := f.importInvitations(t, cookie, "?lifetimeSeconds=3600", "application/x-yaml", yaml, resp)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		require.Len(t, resp.Invitations, 2)
+
+		redeemed := f.redeemInvitation(t, invitationToken(t, resp.Invitations[1].Url))
+		require.NotNil(t, redeemed.Success)
+		user, err := f.Db.GetUserByEmail("bob@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"app.example.com"}, user.AllowedHosts)
+		assert.Equal(t, []string{"staff"}, user.Groups)
+		assert.False(t, user.IsAdmin)
+	})
+
+	t.Run("reports invitees that can't be invited", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+		f.CreateUser("bob@example.com")
+
+		resp := &api.ApiImportInvitationsResponse{}
+		rr := f.importInvitations(t, cookie, "", "text/csv", "email\nalice@example.com\nbob@example.com\n", resp)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+		require.Len(t, resp.Invitations, 2)
+		assert.Empty(t, resp.Invitations[0].Error)
+		assert.NotEmpty(t, resp.Invitations[0].Url)
+		assert.NotEmpty(t, resp.Invitations[1].Error)
+		assert.Empty(t, resp.Invitations[1].Url)
+	})
+
+	t.Run("rejects invalid file", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+
+		rr := f.importInvitations(t, cookie, "", "text/csv", "name\nAlice\n", nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		rr = f.importInvitations(t, cookie, "", "application/x-yaml", "- display_name: Alice\n", nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Empty(t, f.Db.ListInvitations())
+	})
+
+	t.Run("is forbidden for non-admin user", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("user@example.com")
+
+		rr := f.importInvitations(t, cookie, "", "text/csv", "email\nother@example.com\n", nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		assert.Empty(t, f.Db.ListInvitations())
+	})
+}
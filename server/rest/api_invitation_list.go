@@ -0,0 +1,50 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/auth"
+	"boivie/ubergang/server/models"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+func ToApiInvitation(invitation *models.Invitation, now time.Time) api.ApiInvitation {
+	return api.ApiInvitation{
+		ID:           invitation.Id,
+		Email:        invitation.Email,
+		DisplayName:  invitation.DisplayName,
+		IsAdmin:      invitation.IsAdmin,
+		AllowedHosts: append([]string{}, invitation.AllowedHosts...),
+		Groups:       append([]string{}, invitation.Groups...),
+		CreatedAt:    invitation.CreatedAt.AsTime().Format(time.RFC3339),
+		ExpiresAt:    invitation.ExpiresAt.AsTime().Format(time.RFC3339),
+		CreatedBy:    invitation.CreatedBy,
+		State:        auth.InvitationState(invitation, now),
+		UserID:       invitation.UserId,
+	}
+}
+
+func (s *ApiModule) handleInvitationList(w http.ResponseWriter, r *http.Request) {
+	user, _, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	if !user.IsAdmin {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	invitations := s.db.ListInvitations()
+	slices.SortFunc(invitations, func(a, b *models.Invitation) int {
+		return strings.Compare(a.Email, b.Email)
+	})
+	now := time.Now()
+	resp := api.ApiListInvitationsResponse{Invitations: make([]api.ApiInvitation, 0, len(invitations))}
+	for _, invitation := range invitations {
+		resp.Invitations = append(resp.Invitations, ToApiInvitation(invitation, now))
+	}
+	jsonify(w, resp)
+}
@@ -0,0 +1,41 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (f *Fixture) listInvitations(t *testing.T, cookie *http.Cookie) []api.ApiInvitation {
+	t.Helper()
+	resp := &api.ApiListInvitationsResponse{}
+	rr := f.request("GET", "/api/invitation", nil, cookie, resp)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	return resp.Invitations
+}
+
+func TestInvitationList(t *testing.T) {
+	t.Run("lists invitations by e-mail", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+		f.createInvitation(t, cookie, &api.ApiCreateInvitationRequest{Email: "b@example.com"})
+		f.createInvitation(t, cookie, &api.ApiCreateInvitationRequest{Email: "a@example.com"})
+
+		invitations := f.listInvitations(t, cookie)
+		require.Len(t, invitations, 2)
+		assert.Equal(t, "a@example.com", invitations[0].Email)
+		assert.Equal(t, "b@example.com", invitations[1].Email)
+		assert.Equal(t, "pending", invitations[0].State)
+	})
+
+	t.Run("is forbidden for non-admin user", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("user@example.com")
+
+		rr := f.request("GET", "/api/invitation", nil, cookie, nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+}
@@ -0,0 +1,59 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/auth"
+	"boivie/ubergang/server/security"
+	"errors"
+	"net/http"
+	"time"
+)
+
+func (s *ApiModule) handleInvitationRedeem(w http.ResponseWriter, r *http.Request) {
+	// Note: This endpoint should not be authenticated, as the user doesn't
+	// exist yet.
+
+	respondErr := func(err api.ApiRedeemInvitationError) {
+		jsonify(w, api.ApiRedeemInvitationResponse{Error: &err})
+	}
+
+	var req api.ApiRedeemInvitationRequest
+	err := parseJsonRequest(w, r, &req)
+	if err != nil {
+		return
+	}
+
+	user, invitation, err := s.auth.RedeemInvitation(req.Token, time.Now())
+	switch {
+	case errors.Is(err, auth.ErrInvitationExpired):
+		respondErr(api.ApiRedeemInvitationError{Expired: true})
+		return
+	case errors.Is(err, auth.ErrInvitationRevoked):
+		respondErr(api.ApiRedeemInvitationError{Revoked: true})
+		return
+	case errors.Is(err, auth.ErrInvitationRedeemed):
+		respondErr(api.ApiRedeemInvitationError{Redeemed: true})
+		return
+	case errors.Is(err, auth.ErrUserExists):
+		respondErr(api.ApiRedeemInvitationError{UserExists: true})
+		return
+	case err != nil:
+		s.events.Record(security.SourceHttp, s.events.ClientIP(r), "", "invalid invitation: "+err.Error())
+		respondErr(api.ApiRedeemInvitationError{InvalidToken: true})
+		return
+	}
+
+	session, err := s.signin(r, user, false)
+	if err != nil {
+		s.log.Warnf("Failed to sign in user %s: %v", user.Id, err)
+		http.Error(w, "Failed to create session", http.StatusInternalServerError)
+		return
+	}
+	s.audit(r, user, session, "invitation.redeem", invitation.Id, nil, user)
+
+	jsonify(w, api.ApiRedeemInvitationResponse{
+		Success: &api.ApiRedeemInvitationSuccess{
+			Cookie:   s.session.CreateSessionCookie(session).String(),
+			Redirect: "/",
+		}})
+}
@@ -0,0 +1,114 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/models"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (f *Fixture) redeemInvitation(t *testing.T, token string) *api.ApiRedeemInvitationResponse {
+	t.Helper()
+	resp := &api.ApiRedeemInvitationResponse{}
+	rr := f.request("POST", "/api/invitation/redeem", &api.ApiRedeemInvitationRequest{Token: token}, nil, resp)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	return resp
+}
+
+func TestInvitationRedeem(t *testing.T) {
+	t.Run("creates user and signs in", func(t *testing.T) {
+		f := CreateFixture(t)
+		adminCookie, _ := f.CreateAdmin("admin@example.com")
+		created := f.createInvitation(t, adminCookie, &api.ApiCreateInvitationRequest{
+			Email:        "user@example.com",
+			DisplayName:  "User",
+			Admin:        true,
+			AllowedHosts: []string{"app.example.com"},
+			Groups:       []string{"staff"},
+		})
+
+		resp := f.redeemInvitation(t, invitationToken(t, created.Url))
+		require.Nil(t, resp.Error)
+		require.NotNil(t, resp.Success)
+		assert.Equal(t, "/", resp.Success.Redirect)
+
+		cookies := (&http.Response{Header: http.Header{"Set-Cookie": {resp.Success.Cookie}}}).Cookies()
+		require.NotEmpty(t, cookies)
+		me := f.getUser(cookies[0], "me")
+		assert.Equal(t, "user@example.com", me.Email)
+		assert.Equal(t, "User", me.DisplayName)
+		assert.True(t, me.IsAdmin)
+		assert.Equal(t, []string{"app.example.com"}, me.AllowedHosts)
+		assert.Equal(t, []string{"staff"}, me.Groups)
+
+		invitations := f.listInvitations(t, adminCookie)
+		require.Len(t, invitations, 1)
+		assert.Equal(t, "redeemed", invitations[0].State)
+		assert.Equal(t, me.ID, invitations[0].UserID)
+
+		records := f.Db.ListAuditRecords(func(record *models.AuditRecord) bool {
+			return record.Action == "invitation.redeem"
+		}, 10)
+		require.Len(t, records, 1)
+		assert.Equal(t, created.Invitation.ID, records[0].TargetId)
+	})
+
+	t.Run("can only be used once", func(t *testing.T) {
+		f := CreateFixture(t)
+		adminCookie, _ := f.CreateAdmin("admin@example.com")
+		created := f.createInvitation(t, adminCookie, &api.ApiCreateInvitationRequest{Email: "user@example.com"})
+		token := invitationToken(t, created.Url)
+		require.NotNil(t, f.redeemInvitation(t, token).Success)
+
+		resp := f.redeemInvitation(t, token)
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.Redeemed)
+	})
+
+	t.Run("expires", func(t *testing.T) {
+		f := CreateFixture(t)
+		adminCookie, _ := f.CreateAdmin("admin@example.com")
+		created := f.createInvitation(t, adminCookie, &api.ApiCreateInvitationRequest{Email: "user@example.com"})
+		err := f.Db.UpdateInvitation(created.Invitation.ID, func(old *models.Invitation) (*models.Invitation, error) {
+			old.ExpiresAt = timestamppb.New(time.Now().Add(-time.Second))
+			return old, nil
+		})
+		require.NoError(t, err)
+
+		resp := f.redeemInvitation(t, invitationToken(t, created.Url))
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.Expired)
+		_, err = f.Db.GetUserByEmail("user@example.com")
+		assert.Error(t, err)
+	})
+
+	t.Run("fails if the user has been created since", func(t *testing.T) {
+		f := CreateFixture(t)
+		adminCookie, _ := f.CreateAdmin("admin@example.com")
+		created := f.createInvitation(t, adminCookie, &api.ApiCreateInvitationRequest{Email: "user@example.com"})
+		f.CreateUser("user@example.com")
+
+		resp := f.redeemInvitation(t, invitationToken(t, created.Url))
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.UserExists)
+		assert.Equal(t, "pending", f.listInvitations(t, adminCookie)[0].State)
+	})
+
+	t.Run("rejects invalid token", func(t *testing.T) {
+		f := CreateFixture(t)
+		adminCookie, _ := f.CreateAdmin("admin@example.com")
+		created := f.createInvitation(t, adminCookie, &api.ApiCreateInvitationRequest{Email: "user@example.com"})
+
+		for _, token := range []string{"invalid", created.Invitation.ID + "_wrong-secret"} {
+			resp := f.redeemInvitation(t, token)
+			require.NotNil(t, resp.Error, token)
+			assert.True(t, resp.Error.InvalidToken, token)
+		}
+		assert.Equal(t, "pending", f.listInvitations(t, adminCookie)[0].State)
+	})
+}
@@ -2,91 +2,222 @@ package rest
 
 import (
 	"boivie/ubergang/server/auth"
+	"boivie/ubergang/server/config"
 	"boivie/ubergang/server/db"
+	"boivie/ubergang/server/federation"
 	"boivie/ubergang/server/log"
-	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/mail"
 	"boivie/ubergang/server/mqtt"
+	"boivie/ubergang/server/notify"
+	"boivie/ubergang/server/security"
 	"boivie/ubergang/server/session"
 	"boivie/ubergang/server/wa"
+	"net/http"
 
 	"github.com/gorilla/mux"
 )
 
 type ApiModule struct {
-	config    *models.Configuration
-	log       *log.Log
-	db        *db.DB
-	session   *session.SessionStore
-	auth      *auth.Auth
-	webauthn  *wa.WA
-	mqttProxy mqtt.ConnectionTracker
+	config     *config.Store
+	log        *log.Log
+	db         *db.DB
+	session    *session.SessionStore
+	auth       *auth.Auth
+	webauthn   *wa.WA
+	mqttProxy  mqtt.ConnectionTracker
+	federation *federation.Federation
+	mailer     *mail.Mailer
+	notifier   *notify.Notifier
+	events     *security.Events
 }
 
-func New(config *models.Configuration,
+func New(config *config.Store,
 	db *db.DB,
 	log *log.Log,
 	session *session.SessionStore,
 	auth *auth.Auth,
-	mqttProxy mqtt.ConnectionTracker) *ApiModule {
+	events *security.Events,
+	mqttProxy mqtt.ConnectionTracker,
+	mqttPublisher mqtt.MQTTPublisher) *ApiModule {
 
+	mailer := mail.New(log, config)
 	return &ApiModule{
 		config, log, db, session, auth, wa.New(config, db), mqttProxy,
+		federation.New(log, http.DefaultClient), mailer,
+		notify.New(log, config, db, mailer, mqttPublisher),
+		events,
+	}
+}
+
+// limitSignin rejects requests from clients that are banned, or that try to
+// sign in too often.
+func (a *ApiModule) limitSignin(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !a.events.AllowSignin(a.events.ClientIP(r)) {
+			http.Error(w, "Too many requests", http.StatusTooManyRequests)
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// limitTokens rejects requests from clients that are banned, or that make too
+// many requests to endpoints that are polled or used by machines.
+func (a *ApiModule) limitTokens(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !a.events.AllowTokenRequest(a.events.ClientIP(r)) {
+			http.Error(w, "Too many requests", http.StatusTooManyRequests)
+			return
+		}
+		handler(w, r)
 	}
 }
 
 func (a *ApiModule) RegisterEndpoints(r *mux.Router) {
+	config := a.config.Get()
 	// Enrolling
-	r.Host(a.config.AdminFqdn).Methods("POST").Path("/api/enroll/start").HandlerFunc(a.handleEnrollStart)
-	r.Host(a.config.AdminFqdn).Methods("POST").Path("/api/enroll/finish").HandlerFunc(a.handleEnrollFinish)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/enroll/start").HandlerFunc(a.handleEnrollStart)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/enroll/finish").HandlerFunc(a.handleEnrollFinish)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/totp/enroll/start").HandlerFunc(a.handleTotpEnrollStart)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/totp/enroll/finish").HandlerFunc(a.handleTotpEnrollFinish)
 	// Signing in
-	r.Host(a.config.AdminFqdn).Methods("GET").Path("/api/signin/start").HandlerFunc(a.handleSigninStart)
-	r.Host(a.config.AdminFqdn).Methods("POST").Path("/api/signin/email").HandlerFunc(a.handleSigninEmail)
-	r.Host(a.config.AdminFqdn).Methods("POST").Path("/api/signin/webauthn").HandlerFunc(a.handleSigninWebauthn)
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/signin/start").HandlerFunc(a.handleSigninStart)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/signin/email").HandlerFunc(a.limitSignin(a.handleSigninEmail))
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/signin/webauthn").HandlerFunc(a.limitSignin(a.handleSigninWebauthn))
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/signin/totp").HandlerFunc(a.limitSignin(a.handleSigninTotp))
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/signin/link").HandlerFunc(a.limitSignin(a.handleSigninLink))
 	// Signing in, pin flow
-	r.Host(a.config.AdminFqdn).Methods("POST").Path("/api/signin/pin/request").HandlerFunc(a.handleSigninPinRequest)
-	r.Host(a.config.AdminFqdn).Methods("POST").Path("/api/signin/pin/poll").HandlerFunc(a.handleSigninPinPoll)
-	r.Host(a.config.AdminFqdn).Methods("POST").Path("/api/signin/pin/query").HandlerFunc(a.handleSigninPinQuery)
-	r.Host(a.config.AdminFqdn).Methods("POST").Path("/api/signin/pin/confirm").HandlerFunc(a.handleSigninPinConfirm)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/signin/pin/request").HandlerFunc(a.limitSignin(a.handleSigninPinRequest))
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/signin/pin/poll").HandlerFunc(a.limitTokens(a.handleSigninPinPoll))
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/signin/pin/query").HandlerFunc(a.handleSigninPinQuery)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/signin/pin/confirm").HandlerFunc(a.handleSigninPinConfirm)
+	// Signing in, upstream OIDC providers
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/signin/upstream").HandlerFunc(a.handleSigninUpstreamList)
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/signin/upstream/{id}/start").HandlerFunc(a.handleSigninUpstreamStart)
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/signin/upstream/{id}/callback").HandlerFunc(a.handleSigninUpstreamCallback)
 	// SSH keys
-	r.Host(a.config.AdminFqdn).Methods("GET").Path("/api/ssh-key/{id}/confirm").HandlerFunc(a.handleGetSshKeyConfirm)
-	r.Host(a.config.AdminFqdn).Methods("POST").Path("/api/ssh-key/{id}/confirm").HandlerFunc(a.handlePostSshKeyConfirm)
-	r.Host(a.config.AdminFqdn).Methods("GET").Path("/api/ssh-key/{id}").HandlerFunc(a.handleSshKeyGet)
-	r.Host(a.config.AdminFqdn).Methods("POST").Path("/api/ssh-key/{id}").HandlerFunc(a.handleUpdateSshKey)
-	r.Host(a.config.AdminFqdn).Methods("POST").Path("/api/ssh-key").HandlerFunc(a.handleSshKeyCreate)
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/ssh-key/{id}/confirm").HandlerFunc(a.handleGetSshKeyConfirm)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/ssh-key/{id}/confirm").HandlerFunc(a.handlePostSshKeyConfirm)
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/ssh-key/{id}").HandlerFunc(a.handleSshKeyGet)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/ssh-key/{id}").HandlerFunc(a.handleUpdateSshKey)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/ssh-key").HandlerFunc(a.handleSshKeyCreate)
 	// Backends
-	r.Host(a.config.AdminFqdn).Methods("POST").Path("/api/backend/{fqdn}").HandlerFunc(a.handleBackendUpdate)
-	r.Host(a.config.AdminFqdn).Methods("GET").Path("/api/backend/{fqdn}").HandlerFunc(a.handleBackendGet)
-	r.Host(a.config.AdminFqdn).Methods("GET").Path("/api/backend").HandlerFunc(a.handleBackendList)
-	r.Host(a.config.AdminFqdn).Methods("DELETE").Path("/api/backend/{fqdn}").HandlerFunc(a.handleBackendDelete)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/backend/{fqdn}").HandlerFunc(a.handleBackendUpdate)
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/backend/{fqdn}").HandlerFunc(a.handleBackendGet)
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/backend").HandlerFunc(a.handleBackendList)
+	r.Host(config.AdminFqdn).Methods("DELETE").Path("/api/backend/{fqdn}").HandlerFunc(a.handleBackendDelete)
 	// MQTT Profiles
-	r.Host(a.config.AdminFqdn).Methods("POST").Path("/api/mqtt-profile/{id}").HandlerFunc(a.handleMqttProfileUpdate)
-	r.Host(a.config.AdminFqdn).Methods("GET").Path("/api/mqtt-profile/{id}").HandlerFunc(a.handleMqttProfileGet)
-	r.Host(a.config.AdminFqdn).Methods("GET").Path("/api/mqtt-profile").HandlerFunc(a.handleMqttProfileList)
-	r.Host(a.config.AdminFqdn).Methods("DELETE").Path("/api/mqtt-profile/{id}").HandlerFunc(a.handleMqttProfileDelete)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/mqtt-profile/{id}").HandlerFunc(a.handleMqttProfileUpdate)
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/mqtt-profile/{id}").HandlerFunc(a.handleMqttProfileGet)
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/mqtt-profile").HandlerFunc(a.handleMqttProfileList)
+	r.Host(config.AdminFqdn).Methods("DELETE").Path("/api/mqtt-profile/{id}").HandlerFunc(a.handleMqttProfileDelete)
 	// MQTT Clients
-	r.Host(a.config.AdminFqdn).Methods("POST").Path("/api/mqtt-client/{id}").HandlerFunc(a.handleMqttClientUpdate)
-	r.Host(a.config.AdminFqdn).Methods("GET").Path("/api/mqtt-client/{id}").HandlerFunc(a.handleMqttClientGet)
-	r.Host(a.config.AdminFqdn).Methods("GET").Path("/api/mqtt-client").HandlerFunc(a.handleMqttClientList)
-	r.Host(a.config.AdminFqdn).Methods("DELETE").Path("/api/mqtt-client/{id}").HandlerFunc(a.handleMqttClientDelete)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/mqtt-client/{id}").HandlerFunc(a.handleMqttClientUpdate)
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/mqtt-client/{id}").HandlerFunc(a.handleMqttClientGet)
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/mqtt-client").HandlerFunc(a.handleMqttClientList)
+	r.Host(config.AdminFqdn).Methods("DELETE").Path("/api/mqtt-client/{id}").HandlerFunc(a.handleMqttClientDelete)
+	// Service accounts
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/service-account/{id}/secret").HandlerFunc(a.handleServiceAccountSecret)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/service-account/{id}").HandlerFunc(a.handleServiceAccountUpdate)
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/service-account/{id}").HandlerFunc(a.handleServiceAccountGet)
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/service-account").HandlerFunc(a.handleServiceAccountList)
+	r.Host(config.AdminFqdn).Methods("DELETE").Path("/api/service-account/{id}").HandlerFunc(a.handleServiceAccountDelete)
+	// OIDC clients
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/oidc-client/{id}/secret").HandlerFunc(a.handleOidcClientSecret)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/oidc-client/{id}").HandlerFunc(a.handleOidcClientUpdate)
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/oidc-client/{id}").HandlerFunc(a.handleOidcClientGet)
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/oidc-client").HandlerFunc(a.handleOidcClientList)
+	r.Host(config.AdminFqdn).Methods("DELETE").Path("/api/oidc-client/{id}").HandlerFunc(a.handleOidcClientDelete)
+	// Upstream OIDC providers
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/upstream-oidc/{id}").HandlerFunc(a.handleUpstreamOidcUpdate)
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/upstream-oidc/{id}").HandlerFunc(a.handleUpstreamOidcGet)
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/upstream-oidc").HandlerFunc(a.handleUpstreamOidcList)
+	r.Host(config.AdminFqdn).Methods("DELETE").Path("/api/upstream-oidc/{id}").HandlerFunc(a.handleUpstreamOidcDelete)
+	// OAuth 2.0 and OpenID Connect
+	r.Host(config.AdminFqdn).Methods("GET").Path("/.well-known/openid-configuration").HandlerFunc(a.handleOidcDiscovery)
+	// WebAuthn related origins, at both relying party IDs that can be used.
+	r.Host(config.AdminFqdn).Methods("GET").Path("/.well-known/webauthn").HandlerFunc(a.handleWebauthnWellKnown)
+	if config.SiteFqdn != "" && config.SiteFqdn != config.AdminFqdn {
+		r.Host(config.SiteFqdn).Methods("GET").Path("/.well-known/webauthn").HandlerFunc(a.handleWebauthnWellKnown)
+	}
+	r.Host(config.AdminFqdn).Methods("GET").Path("/oauth/jwks").HandlerFunc(a.handleOidcJwks)
+	r.Host(config.AdminFqdn).Methods("GET").Path("/oauth/authorize").HandlerFunc(a.handleOidcAuthorize)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/oauth/token").HandlerFunc(a.limitTokens(a.handleOAuthToken))
+	r.Host(config.AdminFqdn).Methods("POST").Path("/oauth/device_authorization").HandlerFunc(a.limitTokens(a.handleOAuthDeviceAuthorization))
+	r.Host(config.AdminFqdn).Methods("GET", "POST").Path("/oauth/userinfo").HandlerFunc(a.handleOidcUserinfo)
+	// Device authorization
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/device/query").HandlerFunc(a.handleDeviceQuery)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/device/confirm").HandlerFunc(a.handleDeviceConfirm)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/device/deny").HandlerFunc(a.handleDeviceDeny)
+	// SCIM 2.0 provisioning
+	r.Host(config.AdminFqdn).Methods("GET").Path("/scim/v2/ServiceProviderConfig").HandlerFunc(a.handleScimConfig)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/scim/v2/Users").HandlerFunc(a.handleScimUserCreate)
+	r.Host(config.AdminFqdn).Methods("GET").Path("/scim/v2/Users").HandlerFunc(a.handleScimUserList)
+	r.Host(config.AdminFqdn).Methods("GET").Path("/scim/v2/Users/{id}").HandlerFunc(a.handleScimUserGet)
+	r.Host(config.AdminFqdn).Methods("PUT").Path("/scim/v2/Users/{id}").HandlerFunc(a.handleScimUserReplace)
+	r.Host(config.AdminFqdn).Methods("PATCH").Path("/scim/v2/Users/{id}").HandlerFunc(a.handleScimUserPatch)
+	r.Host(config.AdminFqdn).Methods("DELETE").Path("/scim/v2/Users/{id}").HandlerFunc(a.handleScimUserDelete)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/scim/v2/Groups").HandlerFunc(a.handleScimGroupCreate)
+	r.Host(config.AdminFqdn).Methods("GET").Path("/scim/v2/Groups").HandlerFunc(a.handleScimGroupList)
+	r.Host(config.AdminFqdn).Methods("GET").Path("/scim/v2/Groups/{id}").HandlerFunc(a.handleScimGroupGet)
+	r.Host(config.AdminFqdn).Methods("PUT").Path("/scim/v2/Groups/{id}").HandlerFunc(a.handleScimGroupReplace)
+	r.Host(config.AdminFqdn).Methods("PATCH").Path("/scim/v2/Groups/{id}").HandlerFunc(a.handleScimGroupPatch)
+	r.Host(config.AdminFqdn).Methods("DELETE").Path("/scim/v2/Groups/{id}").HandlerFunc(a.handleScimGroupDelete)
 	// MQTT Import/Export
-	r.Host(a.config.AdminFqdn).Methods("POST").Path("/api/mqtt/import").HandlerFunc(a.handleMqttImport)
-	r.Host(a.config.AdminFqdn).Methods("GET").Path("/api/mqtt/export").HandlerFunc(a.handleMqttExport)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/mqtt/import").HandlerFunc(a.handleMqttImport)
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/mqtt/export").HandlerFunc(a.handleMqttExport)
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/config").HandlerFunc(a.handleConfigExport)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/config/plan").HandlerFunc(a.handleConfigPlan)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/config/apply").HandlerFunc(a.handleConfigApply)
 	// Credentials
-	r.Host(a.config.AdminFqdn).Methods("POST").Path("/api/credential/{id}").HandlerFunc(a.handleCredentialUpdate)
-	r.Host(a.config.AdminFqdn).Methods("DELETE").Path("/api/credential/{id}").HandlerFunc(a.handleCredentialDelete)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/credential/{id}").HandlerFunc(a.handleCredentialUpdate)
+	r.Host(config.AdminFqdn).Methods("DELETE").Path("/api/credential/{id}").HandlerFunc(a.handleCredentialDelete)
 	// Sessions
-	r.Host(a.config.AdminFqdn).Methods("DELETE").Path("/api/session/{id}").HandlerFunc(a.handleSessionDelete)
+	r.Host(config.AdminFqdn).Methods("DELETE").Path("/api/session/{id}").HandlerFunc(a.handleSessionDelete)
+
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/access-token/start").HandlerFunc(a.handleAccessTokenStart)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/access-token/finish").HandlerFunc(a.handleAccessTokenFinish)
+	r.Host(config.AdminFqdn).Methods("DELETE").Path("/api/access-token/{id}").HandlerFunc(a.handleAccessTokenDelete)
+
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/app-password").HandlerFunc(a.handleAppPasswordCreate)
+	r.Host(config.AdminFqdn).Methods("DELETE").Path("/api/app-password/{id}").HandlerFunc(a.handleAppPasswordDelete)
 	// Users
-	r.Host(a.config.AdminFqdn).Methods("POST").Path("/api/user").HandlerFunc(a.handleUserCreate)
-	r.Host(a.config.AdminFqdn).Methods("GET").Path("/api/user").HandlerFunc(a.handleUserList)
-	r.Host(a.config.AdminFqdn).Methods("GET").Path("/api/user/{id}").HandlerFunc(a.handleUserGet)
-	r.Host(a.config.AdminFqdn).Methods("POST").Path("/api/user/{id}").HandlerFunc(a.handleUserUpdate)
-	r.Host(a.config.AdminFqdn).Methods("DELETE").Path("/api/user/{id}").HandlerFunc(a.handleUserDelete)
-	r.Host(a.config.AdminFqdn).Methods("POST").Path("/api/user/{id}/recover").HandlerFunc(a.handleUserRecover)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/user").HandlerFunc(a.handleUserCreate)
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/user").HandlerFunc(a.handleUserList)
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/user/{id}").HandlerFunc(a.handleUserGet)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/user/{id}").HandlerFunc(a.handleUserUpdate)
+	r.Host(config.AdminFqdn).Methods("DELETE").Path("/api/user/{id}").HandlerFunc(a.handleUserDelete)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/user/{id}/recover").HandlerFunc(a.handleUserRecover)
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/user/{id}/activity").HandlerFunc(a.handleUserActivity)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/user/{id}/impersonate").HandlerFunc(a.handleUserImpersonate)
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/user/{id}/access").HandlerFunc(a.handleUserAccess)
+	r.Host(config.AdminFqdn).Methods("DELETE").Path("/api/impersonation").HandlerFunc(a.handleImpersonationStop)
+	// Invitations
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/invitation").HandlerFunc(a.handleInvitationList)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/invitation").HandlerFunc(a.handleInvitationCreate)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/invitation/import").HandlerFunc(a.handleInvitationImport)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/invitation/redeem").HandlerFunc(a.limitSignin(a.handleInvitationRedeem))
+	r.Host(config.AdminFqdn).Methods("DELETE").Path("/api/invitation/{id}").HandlerFunc(a.handleInvitationDelete)
+	r.Host(config.AdminFqdn).Methods("DELETE").Path("/api/user/{id}/federated-identity/{provider}").HandlerFunc(a.handleFederatedIdentityDelete)
+	// Settings
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/settings").HandlerFunc(a.handleSettingsGet)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/settings").HandlerFunc(a.handleSettingsUpdate)
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/authenticators").HandlerFunc(a.handleAuthenticatorList)
+	// Audit log
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/audit").HandlerFunc(a.handleAuditList)
+	// Security events
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/security-events").HandlerFunc(a.handleSecurityEventsList)
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/security-events/fail2ban").HandlerFunc(a.handleSecurityEventsFail2ban)
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/ban").HandlerFunc(a.handleBanList)
+	r.Host(config.AdminFqdn).Methods("DELETE").Path("/api/ban/{ip}").HandlerFunc(a.handleBanDelete)
+
+	// Documentation
+	r.Host(config.AdminFqdn).Methods("GET").Path("/api/openapi.json").HandlerFunc(a.handleOpenApi)
+
 	// Testing
-	r.Host(a.config.AdminFqdn).Methods("POST").Path("/api/testing/setup").HandlerFunc(a.handleTestingSetup)
+	r.Host(config.AdminFqdn).Methods("POST").Path("/api/testing/setup").HandlerFunc(a.handleTestingSetup)
 	// Webauthn Images
-	r.Host(a.config.AdminFqdn).Path("/passkey-image/{aaguid}").HandlerFunc(a.webauthn.PasskeyImageHandler)
+	r.Host(config.AdminFqdn).Path("/passkey-image/{aaguid}").HandlerFunc(a.webauthn.PasskeyImageHandler)
 }
 
 // RegisterBootstrapEndpoints registers API endpoints for bootstrap mode (no Host requirement)
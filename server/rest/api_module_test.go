@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"boivie/ubergang/server/config"
 	"boivie/ubergang/server/models"
 	"net/http"
 	"net/http/httptest"
@@ -155,14 +156,14 @@ func TestApiModuleNew(t *testing.T) {
 		// This test verifies the New function works correctly
 		// We use the same setup as CreateFixture to test module creation
 
-		config := &models.Configuration{
+		config := config.New(&models.Configuration{
 			AdminFqdn: "test.example.com",
-		}
+		})
 
 		f := CreateFixture(t)
 
 		// Create a new API module using the same components
-		apiModule := New(config, f.Db, nil, f.Session, f.Auth, &FakeMqttConnectionTracker{})
+		apiModule := New(config, f.Db, nil, f.Session, f.Auth, nil, &FakeMqttConnectionTracker{}, nil)
 
 		assert.NotNil(t, apiModule)
 
@@ -8,7 +8,7 @@ import (
 )
 
 func (s *ApiModule) handleMqttClientDelete(w http.ResponseWriter, r *http.Request) {
-	user, _, err := s.session.GetAndValidate(w, r)
+	user, session, err := s.session.GetAndValidate(w, r)
 	if err != nil {
 		return
 	}
@@ -20,7 +20,9 @@ func (s *ApiModule) handleMqttClientDelete(w http.ResponseWriter, r *http.Reques
 
 	id := mux.Vars(r)["id"]
 
+	var before *models.MqttClient
 	err = s.db.UpdateMqttClient(id, func(old *models.MqttClient) (*models.MqttClient, error) {
+		before = old
 		return nil, nil
 	})
 
@@ -28,6 +30,9 @@ func (s *ApiModule) handleMqttClientDelete(w http.ResponseWriter, r *http.Reques
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	if before != nil {
+		s.audit(r, user, session, "mqtt-client.delete", id, before, nil)
+	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
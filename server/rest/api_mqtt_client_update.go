@@ -6,10 +6,11 @@ import (
 	"net/http"
 
 	"github.com/gorilla/mux"
+	"google.golang.org/protobuf/proto"
 )
 
 func (s *ApiModule) handleMqttClientUpdate(w http.ResponseWriter, r *http.Request) {
-	user, _, err := s.session.GetAndValidate(w, r)
+	user, session, err := s.session.GetAndValidate(w, r)
 	if err != nil {
 		return
 	}
@@ -27,7 +28,9 @@ func (s *ApiModule) handleMqttClientUpdate(w http.ResponseWriter, r *http.Reques
 
 	id := mux.Vars(r)["id"]
 
+	var before, after *models.MqttClient
 	err = s.db.UpdateMqttClient(id, func(old *models.MqttClient) (*models.MqttClient, error) {
+		before = proto.Clone(old).(*models.MqttClient)
 		if old == nil {
 			old = &models.MqttClient{
 				Id: id,
@@ -45,6 +48,7 @@ func (s *ApiModule) handleMqttClientUpdate(w http.ResponseWriter, r *http.Reques
 		if req.Values != nil {
 			old.Values = *req.Values
 		}
+		after = old
 		return old, nil
 	})
 
@@ -52,6 +56,7 @@ func (s *ApiModule) handleMqttClientUpdate(w http.ResponseWriter, r *http.Reques
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
+	s.audit(r, user, session, "mqtt-client.update", id, before, after)
 
 	jsonify(w, api.ApiUpdateMqttClientResponse{})
 }
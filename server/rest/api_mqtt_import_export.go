@@ -1,6 +1,8 @@
 package rest
 
 import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/gitops"
 	"boivie/ubergang/server/models"
 	"fmt"
 	"net/http"
@@ -8,25 +10,6 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
-// YAML structures for import/export
-type YamlMqttClient struct {
-	Name     string            `yaml:"name"`
-	Password string            `yaml:"password"`
-	Profile  string            `yaml:"profile"`
-	Values   map[string]string `yaml:"values,omitempty"`
-}
-
-type YamlMqttProfile struct {
-	Name           string   `yaml:"name"`
-	AllowPublish   []string `yaml:"allow_publish"`
-	AllowSubscribe []string `yaml:"allow_subscribe"`
-}
-
-type YamlMqttConfig struct {
-	Clients  []YamlMqttClient  `yaml:"clients"`
-	Profiles []YamlMqttProfile `yaml:"profiles"`
-}
-
 func (s *ApiModule) handleMqttExport(w http.ResponseWriter, r *http.Request) {
 	user, _, err := s.session.GetAndValidate(w, r)
 	if err != nil {
@@ -38,33 +21,7 @@ func (s *ApiModule) handleMqttExport(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Fetch all profiles
-	profiles := s.db.ListMqttProfiles()
-	yamlProfiles := make([]YamlMqttProfile, 0, len(profiles))
-	for _, p := range profiles {
-		yamlProfiles = append(yamlProfiles, YamlMqttProfile{
-			Name:           p.Id,
-			AllowPublish:   p.AllowPublish,
-			AllowSubscribe: p.AllowSubscribe,
-		})
-	}
-
-	// Fetch all clients
-	clients := s.db.ListMqttClients()
-	yamlClients := make([]YamlMqttClient, 0, len(clients))
-	for _, c := range clients {
-		yamlClients = append(yamlClients, YamlMqttClient{
-			Name:     c.Id,
-			Password: c.Password,
-			Profile:  c.ProfileId,
-			Values:   c.Values,
-		})
-	}
-
-	config := YamlMqttConfig{
-		Clients:  yamlClients,
-		Profiles: yamlProfiles,
-	}
+	config := gitops.ExportMqtt(s.db)
 
 	// Marshal to YAML
 	yamlData, err := yaml.Marshal(&config)
@@ -82,7 +39,7 @@ func (s *ApiModule) handleMqttExport(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *ApiModule) handleMqttImport(w http.ResponseWriter, r *http.Request) {
-	user, _, err := s.session.GetAndValidate(w, r)
+	user, session, err := s.session.GetAndValidate(w, r)
 	if err != nil {
 		return
 	}
@@ -93,7 +50,7 @@ func (s *ApiModule) handleMqttImport(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Parse YAML from request body
-	var config YamlMqttConfig
+	var config gitops.MqttConfig
 	decoder := yaml.NewDecoder(r.Body)
 	if err := decoder.Decode(&config); err != nil {
 		s.log.Error("Failed to parse YAML", "error", err)
@@ -132,35 +89,43 @@ func (s *ApiModule) handleMqttImport(w http.ResponseWriter, r *http.Request) {
 
 	// Import profiles first
 	for _, p := range config.Profiles {
+		var before, after *models.MqttProfile
 		err := s.db.UpdateMqttProfile(p.Name, func(old *models.MqttProfile) (*models.MqttProfile, error) {
-			return &models.MqttProfile{
+			before = old
+			after = &models.MqttProfile{
 				Id:             p.Name,
 				AllowPublish:   p.AllowPublish,
 				AllowSubscribe: p.AllowSubscribe,
-			}, nil
+			}
+			return after, nil
 		})
 		if err != nil {
 			s.log.Error("Failed to import profile", "profile", p.Name, "error", err)
 			http.Error(w, fmt.Sprintf("Failed to import profile '%s': %v", p.Name, err), http.StatusInternalServerError)
 			return
 		}
+		s.audit(r, user, session, "mqtt-profile.import", p.Name, before, after)
 	}
 
 	// Import clients
 	for _, c := range config.Clients {
+		var before, after *models.MqttClient
 		err := s.db.UpdateMqttClient(c.Name, func(old *models.MqttClient) (*models.MqttClient, error) {
-			return &models.MqttClient{
+			before = old
+			after = &models.MqttClient{
 				Id:        c.Name,
 				ProfileId: c.Profile,
 				Password:  c.Password,
 				Values:    c.Values,
-			}, nil
+			}
+			return after, nil
 		})
 		if err != nil {
 			s.log.Error("Failed to import client", "client", c.Name, "error", err)
 			http.Error(w, fmt.Sprintf("Failed to import client '%s': %v", c.Name, err), http.StatusInternalServerError)
 			return
 		}
+		s.audit(r, user, session, "mqtt-client.import", c.Name, before, after)
 	}
 
 	s.log.Info("Successfully imported MQTT configuration",
@@ -168,9 +133,9 @@ func (s *ApiModule) handleMqttImport(w http.ResponseWriter, r *http.Request) {
 		"clients", len(config.Clients))
 
 	w.WriteHeader(http.StatusOK)
-	jsonify(w, map[string]interface{}{
-		"success":        true,
-		"profiles_count": len(config.Profiles),
-		"clients_count":  len(config.Clients),
+	jsonify(w, api.ApiMqttImportResponse{
+		Success:       true,
+		ProfilesCount: len(config.Profiles),
+		ClientsCount:  len(config.Clients),
 	})
 }
@@ -8,7 +8,7 @@ import (
 )
 
 func (s *ApiModule) handleMqttProfileDelete(w http.ResponseWriter, r *http.Request) {
-	user, _, err := s.session.GetAndValidate(w, r)
+	user, session, err := s.session.GetAndValidate(w, r)
 	if err != nil {
 		return
 	}
@@ -20,7 +20,9 @@ func (s *ApiModule) handleMqttProfileDelete(w http.ResponseWriter, r *http.Reque
 
 	id := mux.Vars(r)["id"]
 
+	var before *models.MqttProfile
 	err = s.db.UpdateMqttProfile(id, func(old *models.MqttProfile) (*models.MqttProfile, error) {
+		before = old
 		return nil, nil
 	})
 
@@ -28,6 +30,9 @@ func (s *ApiModule) handleMqttProfileDelete(w http.ResponseWriter, r *http.Reque
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	if before != nil {
+		s.audit(r, user, session, "mqtt-profile.delete", id, before, nil)
+	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
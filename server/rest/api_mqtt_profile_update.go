@@ -6,10 +6,11 @@ import (
 	"net/http"
 
 	"github.com/gorilla/mux"
+	"google.golang.org/protobuf/proto"
 )
 
 func (s *ApiModule) handleMqttProfileUpdate(w http.ResponseWriter, r *http.Request) {
-	user, _, err := s.session.GetAndValidate(w, r)
+	user, session, err := s.session.GetAndValidate(w, r)
 	if err != nil {
 		return
 	}
@@ -27,7 +28,9 @@ func (s *ApiModule) handleMqttProfileUpdate(w http.ResponseWriter, r *http.Reque
 
 	id := mux.Vars(r)["id"]
 
+	var before, after *models.MqttProfile
 	err = s.db.UpdateMqttProfile(id, func(old *models.MqttProfile) (*models.MqttProfile, error) {
+		before = proto.Clone(old).(*models.MqttProfile)
 		if old == nil {
 			old = &models.MqttProfile{
 				Id: id,
@@ -39,6 +42,7 @@ func (s *ApiModule) handleMqttProfileUpdate(w http.ResponseWriter, r *http.Reque
 		if req.AllowSubscribe != nil {
 			old.AllowSubscribe = *req.AllowSubscribe
 		}
+		after = old
 		return old, nil
 	})
 
@@ -46,6 +50,7 @@ func (s *ApiModule) handleMqttProfileUpdate(w http.ResponseWriter, r *http.Reque
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
+	s.audit(r, user, session, "mqtt-profile.update", id, before, after)
 
 	jsonify(w, api.ApiUpdateMqttProfileResponse{})
 }
@@ -0,0 +1,78 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/auth"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// validateDeviceScopes returns the requested scopes if they are all supported.
+// Either the openid scope or an access token scope must be requested.
+func validateDeviceScopes(scope string) ([]string, bool) {
+	scopes := strings.Fields(scope)
+	usable := false
+	for _, s := range scopes {
+		if s == auth.OidcScopeOpenID || slices.Contains(auth.AccessTokenScopes, s) {
+			usable = true
+		} else if !slices.Contains(auth.OidcScopes, s) {
+			return nil, false
+		}
+	}
+	return scopes, usable
+}
+
+// https://www.rfc-editor.org/rfc/rfc8628#section-3.1
+func (s *ApiModule) handleOAuthDeviceAuthorization(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		respondOAuthError(w, http.StatusBadRequest, "invalid_request", "malformed request")
+		return
+	}
+
+	client, err := s.authenticateOidcClient(r)
+	if err != nil {
+		s.log.Warnf("Failed to authenticate OIDC client: %v", err)
+		respondOAuthError(w, http.StatusUnauthorized, "invalid_client", "")
+		return
+	}
+
+	scopes, ok := validateDeviceScopes(r.PostForm.Get("scope"))
+	if !ok {
+		respondOAuthError(w, http.StatusBadRequest, "invalid_scope", "")
+		return
+	}
+
+	authorization, deviceCode, err := s.auth.CreateDeviceAuthorization(client.Id, scopes, s.events.ClientIP(r), r.UserAgent(), time.Now())
+	if err != nil {
+		s.log.Warnf("Failed to create device authorization: %v", err)
+		respondOAuthError(w, http.StatusInternalServerError, "server_error", "")
+		return
+	}
+
+	userCode := auth.FormatUserCode(authorization.UserCode)
+	verificationUri := s.issuer() + "/device"
+	verificationUriComplete := verificationUri + "?user_code=" + url.QueryEscape(userCode)
+	png, err := qrcode.Encode(verificationUriComplete, qrcode.Low, 256)
+	if err != nil {
+		s.log.Warnf("Failed to create QR code: %v", err)
+		respondOAuthError(w, http.StatusInternalServerError, "server_error", "")
+		return
+	}
+
+	s.log.Infof("Created device authorization %s for OIDC client %s", authorization.Id, client.Id)
+	respondOAuthToken(w, api.ApiDeviceAuthorizationResponse{
+		DeviceCode:              deviceCode,
+		UserCode:                userCode,
+		VerificationUri:         verificationUri,
+		VerificationUriComplete: verificationUriComplete,
+		ExpiresIn:               int64(auth.DeviceAuthorizationLifetime.Seconds()),
+		Interval:                int64(authorization.IntervalSeconds),
+		QrCodeUrl:               "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+	})
+}
@@ -0,0 +1,87 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/models"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (f *Fixture) requestDeviceAuthorization(form url.Values) *httptest.ResponseRecorder {
+	httpReq, _ := http.NewRequest("POST", "/oauth/device_authorization", strings.NewReader(form.Encode()))
+	httpReq.Host = "test.example.com"
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	httpReq.Header.Set("User-Agent", "test-cli")
+	httpReq.RemoteAddr = "192.0.2.1:1234"
+	rr := httptest.NewRecorder()
+	f.router.ServeHTTP(rr, httpReq)
+	return rr
+}
+
+// startDeviceAuthorization starts a device authorization for the client "app".
+func (f *Fixture) startDeviceAuthorization(t *testing.T, scope string) *api.ApiDeviceAuthorizationResponse {
+	t.Helper()
+	rr := f.requestDeviceAuthorization(url.Values{"client_id": {"app"}, "scope": {scope}})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	resp := &api.ApiDeviceAuthorizationResponse{}
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(resp))
+	return resp
+}
+
+func TestOAuthDeviceAuthorization(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		f, _, _ := setupOidcTest(t, true)
+		resp := f.startDeviceAuthorization(t, "openid proxy")
+
+		assert.NotEmpty(t, resp.DeviceCode)
+		assert.Regexp(t, `^[A-Z]{4}-[A-Z]{4}$`, resp.UserCode)
+		assert.Equal(t, "https://test.example.com/device", resp.VerificationUri)
+		assert.Equal(t, "https://test.example.com/device?user_code="+resp.UserCode, resp.VerificationUriComplete)
+		assert.Equal(t, int64(600), resp.ExpiresIn)
+		assert.Equal(t, int64(5), resp.Interval)
+		assert.True(t, strings.HasPrefix(resp.QrCodeUrl, "data:image/png;base64,"))
+	})
+
+	t.Run("unknown client", func(t *testing.T) {
+		f, _, _ := setupOidcTest(t, true)
+		rr := f.requestDeviceAuthorization(url.Values{"client_id": {"unknown"}, "scope": {"openid"}})
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Equal(t, "invalid_client", decodeOAuthError(t, rr))
+	})
+
+	t.Run("confidential client without secret", func(t *testing.T) {
+		f, adminCookie, _ := setupOidcTest(t, false)
+		f.createOidcClientSecret(t, adminCookie, "app")
+		rr := f.requestDeviceAuthorization(url.Values{"client_id": {"app"}, "scope": {"openid"}})
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+
+	t.Run("invalid scopes", func(t *testing.T) {
+		f, _, _ := setupOidcTest(t, true)
+		for _, scope := range []string{"", "email", "openid unknown"} {
+			rr := f.requestDeviceAuthorization(url.Values{"client_id": {"app"}, "scope": {scope}})
+			assert.Equal(t, http.StatusBadRequest, rr.Code, scope)
+			assert.Equal(t, "invalid_scope", decodeOAuthError(t, rr), scope)
+		}
+	})
+
+	t.Run("is rate limited", func(t *testing.T) {
+		f, _, _ := setupOidcTest(t, true)
+		f.Config.Update(func(c *models.Configuration) {
+			c.BanPolicy = &models.BanPolicy{MaxFailures: 3, TokenRequestsPerMinute: 2}
+		})
+
+		form := url.Values{"client_id": {"app"}, "scope": {"openid"}}
+		for i, expected := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+			rr := f.requestDeviceAuthorization(form)
+			assert.Equal(t, expected, rr.Code, "request %d", i)
+		}
+	})
+}
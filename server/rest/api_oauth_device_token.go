@@ -0,0 +1,96 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/auth"
+	"boivie/ubergang/server/models"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+const deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// https://www.rfc-editor.org/rfc/rfc8628#section-3.4
+func (s *ApiModule) handleDeviceCodeGrant(w http.ResponseWriter, r *http.Request) {
+	client, err := s.authenticateOidcClient(r)
+	if err != nil {
+		s.recordTokenFailure(r, "invalid OIDC client credentials: "+err.Error())
+		respondOAuthError(w, http.StatusUnauthorized, "invalid_client", "")
+		return
+	}
+
+	now := time.Now()
+	authorization, err := s.auth.PollDeviceAuthorization(r.PostForm.Get("device_code"), client.Id, now)
+	switch {
+	case errors.Is(err, auth.ErrAuthorizationPending),
+		errors.Is(err, auth.ErrSlowDown),
+		errors.Is(err, auth.ErrAccessDenied),
+		errors.Is(err, auth.ErrExpiredToken):
+		respondOAuthError(w, http.StatusBadRequest, err.Error(), "")
+		return
+	case err != nil:
+		s.recordTokenFailure(r, "invalid device code: "+err.Error())
+		respondOAuthError(w, http.StatusBadRequest, "invalid_grant", "invalid device code")
+		return
+	}
+
+	user, err := s.db.GetUserById(authorization.UserId)
+	if err != nil || user.IsDisabled || !s.auth.IsGroupAllowed(client, user) {
+		respondOAuthError(w, http.StatusBadRequest, "invalid_grant", "the user is not allowed to use this client")
+		return
+	}
+	// The user may no longer be an admin since approving the device.
+	if slices.ContainsFunc(authorization.Scopes, auth.IsAdminScope) && !user.IsAdmin {
+		respondOAuthError(w, http.StatusBadRequest, "invalid_scope", "the user can't be granted the requested scopes")
+		return
+	}
+
+	response := api.ApiOAuthTokenResponse{
+		TokenType: "Bearer",
+		Scope:     strings.Join(authorization.Scopes, " "),
+	}
+
+	// Scopes for the reverse proxy are granted as a personal access token,
+	// named after the client so that the user can find and revoke it.
+	var tokenScopes []string
+	for _, scope := range authorization.Scopes {
+		if slices.Contains(auth.AccessTokenScopes, scope) {
+			tokenScopes = append(tokenScopes, scope)
+		}
+	}
+	if len(tokenScopes) > 0 {
+		expiresAt := now.Add(defaultAccessTokenExpiryDays * 24 * time.Hour)
+		_, value, err := s.auth.CreateAccessToken(user.Id, client.Name, tokenScopes, nil, expiresAt)
+		if err != nil {
+			s.log.Warnf("Failed to create access token for user %s: %v", user.Id, err)
+			respondOAuthError(w, http.StatusInternalServerError, "server_error", "")
+			return
+		}
+		response.AccessToken = value
+		response.ExpiresIn = int64(expiresAt.Sub(now).Seconds())
+	}
+
+	if slices.Contains(authorization.Scopes, auth.OidcScopeOpenID) {
+		idToken, accessToken, lifetime, err := s.auth.IssueOidcTokens(client, user, &models.AuthenticationStateOidcAuthorize{
+			ClientId: client.Id,
+			Scopes:   authorization.Scopes,
+			AuthTime: authorization.ApprovedAt,
+		}, s.issuer(), now)
+		if err != nil {
+			s.log.Warnf("Failed to issue tokens for OIDC client %s: %v", client.Id, err)
+			respondOAuthError(w, http.StatusInternalServerError, "server_error", "")
+			return
+		}
+		response.IdToken = idToken
+		if response.AccessToken == "" {
+			response.AccessToken = accessToken
+			response.ExpiresIn = int64(lifetime.Seconds())
+		}
+	}
+
+	s.log.Infof("Issued tokens for user %s to device of OIDC client %s", user.Id, client.Id)
+	respondOAuthToken(w, response)
+}
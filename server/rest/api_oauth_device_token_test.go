@@ -0,0 +1,157 @@
+package rest
+
+import (
+	"boivie/ubergang/server/models"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func deviceCodeGrant(deviceCode string) url.Values {
+	return url.Values{
+		"grant_type":  {deviceCodeGrantType},
+		"client_id":   {"app"},
+		"device_code": {deviceCode},
+	}
+}
+
+// updateDeviceAuthorization modifies the device authorization of a device code.
+func (f *Fixture) updateDeviceAuthorization(t *testing.T, deviceCode string, update func(a *models.DeviceAuthorization)) {
+	t.Helper()
+	id, _, _ := strings.Cut(deviceCode, "_")
+	require.NoError(t, f.Db.UpdateDeviceAuthorization(id, func(old *models.DeviceAuthorization) (*models.DeviceAuthorization, error) {
+		update(old)
+		return old, nil
+	}))
+}
+
+func (f *Fixture) approveDevice(t *testing.T, deviceCode string, userCookie *http.Cookie) {
+	t.Helper()
+	id, _, _ := strings.Cut(deviceCode, "_")
+	user := f.getUser(userCookie, "me")
+	require.NoError(t, f.Auth.DecideDeviceAuthorization(id, user.ID, true, time.Now()))
+}
+
+func TestOAuthDeviceCodeGrant(t *testing.T) {
+	t.Run("authorization pending", func(t *testing.T) {
+		f, _, _ := setupOidcTest(t, true)
+		device := f.startDeviceAuthorization(t, "openid")
+
+		rr := f.requestOAuthToken(deviceCodeGrant(device.DeviceCode), nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, "authorization_pending", decodeOAuthError(t, rr))
+	})
+
+	t.Run("polling too often", func(t *testing.T) {
+		f, _, _ := setupOidcTest(t, true)
+		device := f.startDeviceAuthorization(t, "openid")
+
+		f.requestOAuthToken(deviceCodeGrant(device.DeviceCode), nil)
+		rr := f.requestOAuthToken(deviceCodeGrant(device.DeviceCode), nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, "slow_down", decodeOAuthError(t, rr))
+	})
+
+	t.Run("approved with openid", func(t *testing.T) {
+		f, _, userCookie := setupOidcTest(t, true)
+		device := f.startDeviceAuthorization(t, "openid email")
+		f.approveDevice(t, device.DeviceCode, userCookie)
+
+		resp := decodeOAuthToken(t, f.requestOAuthToken(deviceCodeGrant(device.DeviceCode), nil))
+		assert.Equal(t, "openid email", resp.Scope)
+		assert.NotEmpty(t, resp.AccessToken)
+
+		claims := jwt.MapClaims{}
+		err := f.Auth.ParseToken(resp.IdToken, claims,
+			jwt.WithIssuer("https://test.example.com"),
+			jwt.WithAudience("app"))
+		require.NoError(t, err)
+		assert.Equal(t, "user@example.com", claims["email"])
+		assert.NotNil(t, claims["auth_time"])
+
+		// The device code can only be used once.
+		rr := f.requestOAuthToken(deviceCodeGrant(device.DeviceCode), nil)
+		assert.Equal(t, "invalid_grant", decodeOAuthError(t, rr))
+	})
+
+	t.Run("approved with proxy scope", func(t *testing.T) {
+		f, _, userCookie := setupOidcTest(t, true)
+		device := f.startDeviceAuthorization(t, "proxy")
+		f.approveDevice(t, device.DeviceCode, userCookie)
+
+		resp := decodeOAuthToken(t, f.requestOAuthToken(deviceCodeGrant(device.DeviceCode), nil))
+		assert.Empty(t, resp.IdToken)
+		user, token, err := f.Auth.ValidateAccessToken(resp.AccessToken, time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, "user@example.com", user.Email)
+		assert.Equal(t, []string{"proxy"}, token.Scopes)
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		f, _, _ := setupOidcTest(t, true)
+		device := f.startDeviceAuthorization(t, "openid")
+		f.updateDeviceAuthorization(t, device.DeviceCode, func(a *models.DeviceAuthorization) {
+			a.Denied = true
+		})
+
+		rr := f.requestOAuthToken(deviceCodeGrant(device.DeviceCode), nil)
+		assert.Equal(t, "access_denied", decodeOAuthError(t, rr))
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		f, _, _ := setupOidcTest(t, true)
+		device := f.startDeviceAuthorization(t, "openid")
+		f.updateDeviceAuthorization(t, device.DeviceCode, func(a *models.DeviceAuthorization) {
+			a.ExpiresAt = timestamppb.New(time.Now().Add(-time.Minute))
+		})
+
+		rr := f.requestOAuthToken(deviceCodeGrant(device.DeviceCode), nil)
+		assert.Equal(t, "expired_token", decodeOAuthError(t, rr))
+	})
+
+	t.Run("invalid device code", func(t *testing.T) {
+		f, _, _ := setupOidcTest(t, true)
+		device := f.startDeviceAuthorization(t, "openid")
+
+		rr := f.requestOAuthToken(deviceCodeGrant(device.DeviceCode+"x"), nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, "invalid_grant", decodeOAuthError(t, rr))
+	})
+
+	t.Run("disabled user", func(t *testing.T) {
+		f, _, userCookie := setupOidcTest(t, true)
+		device := f.startDeviceAuthorization(t, "openid")
+		f.approveDevice(t, device.DeviceCode, userCookie)
+		user := f.getUser(userCookie, "me")
+		require.NoError(t, f.Db.UpdateUser(user.ID, func(old *models.User) (*models.User, error) {
+			old.IsDisabled = true
+			return old, nil
+		}))
+
+		rr := f.requestOAuthToken(deviceCodeGrant(device.DeviceCode), nil)
+		assert.Equal(t, "invalid_grant", decodeOAuthError(t, rr))
+	})
+
+	t.Run("user is no longer an admin", func(t *testing.T) {
+		f, adminCookie, _ := setupOidcTest(t, true)
+		device := f.startDeviceAuthorization(t, "admin")
+		f.approveDevice(t, device.DeviceCode, adminCookie)
+		admin := f.getUser(adminCookie, "me")
+		require.NoError(t, f.Db.UpdateUser(admin.ID, func(old *models.User) (*models.User, error) {
+			old.IsAdmin = false
+			return old, nil
+		}))
+
+		rr := f.requestOAuthToken(deviceCodeGrant(device.DeviceCode), nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, "invalid_scope", decodeOAuthError(t, rr))
+		assert.Empty(t, f.getUser(adminCookie, "me").AccessTokens)
+	})
+}
@@ -0,0 +1,114 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/auth"
+	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/security"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
+const clientAssertionTypeJwtBearer = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+var errUnsupportedAssertionType = errors.New("unsupported client assertion type")
+
+func (s *ApiModule) tokenEndpoint() string {
+	return s.issuer() + "/oauth/token"
+}
+
+func respondOAuthError(w http.ResponseWriter, status int, code, description string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if status == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", `Basic realm="oauth"`)
+	}
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(api.ApiOAuthErrorResponse{
+		Error:            code,
+		ErrorDescription: description,
+	})
+}
+
+// recordTokenFailure records that the client of a token request failed to
+// authenticate, or presented a grant that isn't valid.
+func (s *ApiModule) recordTokenFailure(r *http.Request, reason string) {
+	clientId, _, found := r.BasicAuth()
+	if !found {
+		clientId = r.PostForm.Get("client_id")
+	}
+	s.events.Record(security.SourceHttp, s.events.ClientIP(r), clientId, reason)
+}
+
+func respondOAuthToken(w http.ResponseWriter, response any) {
+	w.Header().Set("Cache-Control", "no-store")
+	jsonify(w, response)
+}
+
+// authenticateServiceAccountClient authenticates the client of a token
+// request, using either a client secret or a signed JWT assertion.
+func (s *ApiModule) authenticateServiceAccountClient(r *http.Request) (*models.ServiceAccount, error) {
+	if assertionType := r.PostForm.Get("client_assertion_type"); assertionType != "" {
+		if assertionType != clientAssertionTypeJwtBearer {
+			return nil, errUnsupportedAssertionType
+		}
+		return s.auth.AuthenticateServiceAccountAssertion(r.PostForm.Get("client_assertion"), s.tokenEndpoint(), time.Now())
+	}
+	clientId, clientSecret, found := r.BasicAuth()
+	if !found {
+		clientId = r.PostForm.Get("client_id")
+		clientSecret = r.PostForm.Get("client_secret")
+	}
+	return s.auth.AuthenticateServiceAccount(clientId, clientSecret)
+}
+
+// handleOAuthToken is the OAuth 2.0 token endpoint.
+func (s *ApiModule) handleOAuthToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		respondOAuthError(w, http.StatusBadRequest, "invalid_request", "malformed request")
+		return
+	}
+
+	switch r.PostForm.Get("grant_type") {
+	case "client_credentials":
+		s.handleClientCredentialsGrant(w, r)
+	case "authorization_code":
+		s.handleAuthorizationCodeGrant(w, r)
+	case deviceCodeGrantType:
+		s.handleDeviceCodeGrant(w, r)
+	default:
+		respondOAuthError(w, http.StatusBadRequest, "unsupported_grant_type", "")
+	}
+}
+
+// https://www.rfc-editor.org/rfc/rfc6749#section-4.4
+func (s *ApiModule) handleClientCredentialsGrant(w http.ResponseWriter, r *http.Request) {
+	account, err := s.authenticateServiceAccountClient(r)
+	if err != nil {
+		s.recordTokenFailure(r, "invalid service account credentials: "+err.Error())
+		respondOAuthError(w, http.StatusUnauthorized, "invalid_client", "")
+		return
+	}
+
+	if scope := r.PostForm.Get("scope"); scope != "" && scope != auth.ScopeProxy {
+		respondOAuthError(w, http.StatusBadRequest, "invalid_scope", "")
+		return
+	}
+
+	token, lifetime, err := s.auth.IssueServiceAccountToken(account, s.issuer(), time.Now())
+	if err != nil {
+		s.log.Warnf("Failed to issue token for service account %s: %v", account.Id, err)
+		respondOAuthError(w, http.StatusInternalServerError, "server_error", "")
+		return
+	}
+
+	s.log.Infof("Issued token for service account %s", account.Id)
+	respondOAuthToken(w, api.ApiOAuthTokenResponse{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(lifetime.Seconds()),
+		Scope:       auth.ScopeProxy,
+	})
+}
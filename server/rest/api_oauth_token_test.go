@@ -0,0 +1,243 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/auth"
+	"crypto/ecdsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (f *Fixture) requestOAuthToken(form url.Values, configure func(r *http.Request)) *httptest.ResponseRecorder {
+	httpReq, _ := http.NewRequest("POST", "/oauth/token", strings.NewReader(form.Encode()))
+	httpReq.Host = "test.example.com"
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if configure != nil {
+		configure(httpReq)
+	}
+	rr := httptest.NewRecorder()
+	f.router.ServeHTTP(rr, httpReq)
+	return rr
+}
+
+func decodeOAuthToken(t *testing.T, rr *httptest.ResponseRecorder) *api.ApiOAuthTokenResponse {
+	t.Helper()
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+	resp := &api.ApiOAuthTokenResponse{}
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(resp))
+	return resp
+}
+
+func decodeOAuthError(t *testing.T, rr *httptest.ResponseRecorder) string {
+	t.Helper()
+	resp := &api.ApiOAuthErrorResponse{}
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(resp))
+	return resp.Error
+}
+
+func signAssertion(t *testing.T, key *ecdsa.PrivateKey, claims jwt.Claims) string {
+	t.Helper()
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodES256, claims).SignedString(key)
+	require.NoError(t, err)
+	return assertion
+}
+
+func TestOAuthToken(t *testing.T) {
+	t.Run("client secret in form", func(t *testing.T) {
+		f, cookie := setupServiceAccountTest(t)
+		require.Equal(t, http.StatusOK, f.updateServiceAccount(cookie, "backup", &api.ApiUpdateServiceAccountRequest{}))
+		secret := f.createServiceAccountSecret(t, cookie, "backup")
+
+		resp := decodeOAuthToken(t, f.requestOAuthToken(url.Values{
+			"grant_type":    {"client_credentials"},
+			"client_id":     {"backup"},
+			"client_secret": {secret.ClientSecret},
+		}, nil))
+		assert.Equal(t, "Bearer", resp.TokenType)
+		assert.Equal(t, int64(3600), resp.ExpiresIn)
+		assert.Equal(t, "proxy", resp.Scope)
+
+		account, err := f.Auth.ValidateServiceAccountToken(resp.AccessToken, "https://test.example.com", time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, "backup", account.Id)
+		assert.NotNil(t, account.LastUsedAt)
+	})
+
+	t.Run("client secret in basic auth", func(t *testing.T) {
+		f, cookie := setupServiceAccountTest(t)
+		require.Equal(t, http.StatusOK, f.updateServiceAccount(cookie, "backup", &api.ApiUpdateServiceAccountRequest{}))
+		secret := f.createServiceAccountSecret(t, cookie, "backup")
+
+		resp := decodeOAuthToken(t, f.requestOAuthToken(url.Values{
+			"grant_type": {"client_credentials"},
+		}, func(r *http.Request) {
+			r.SetBasicAuth("backup", secret.ClientSecret)
+		}))
+		assert.NotEmpty(t, resp.AccessToken)
+	})
+
+	t.Run("signed assertion", func(t *testing.T) {
+		f, cookie := setupServiceAccountTest(t)
+		key, publicKey := generateServiceAccountKey(t)
+		require.Equal(t, http.StatusOK, f.updateServiceAccount(cookie, "backup", &api.ApiUpdateServiceAccountRequest{
+			PublicKey: &publicKey,
+		}))
+
+		assertion := signAssertion(t, key, jwt.RegisteredClaims{
+			Issuer:    "backup",
+			Subject:   "backup",
+			Audience:  jwt.ClaimStrings{"https://test.example.com/oauth/token"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			ID:        "1",
+		})
+		resp := decodeOAuthToken(t, f.requestOAuthToken(url.Values{
+			"grant_type":            {"client_credentials"},
+			"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+			"client_assertion":      {assertion},
+		}, nil))
+
+		account, err := f.Auth.ValidateServiceAccountToken(resp.AccessToken, "https://test.example.com", time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, "backup", account.Id)
+	})
+
+	t.Run("assertion can't be reused", func(t *testing.T) {
+		f, cookie := setupServiceAccountTest(t)
+		key, publicKey := generateServiceAccountKey(t)
+		require.Equal(t, http.StatusOK, f.updateServiceAccount(cookie, "backup", &api.ApiUpdateServiceAccountRequest{
+			PublicKey: &publicKey,
+		}))
+
+		assertion := signAssertion(t, key, jwt.RegisteredClaims{
+			Issuer:    "backup",
+			Subject:   "backup",
+			Audience:  jwt.ClaimStrings{"https://test.example.com/oauth/token"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			ID:        "1",
+		})
+		form := url.Values{
+			"grant_type":            {"client_credentials"},
+			"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+			"client_assertion":      {assertion},
+		}
+		assert.Equal(t, http.StatusOK, f.requestOAuthToken(form, nil).Code)
+		rr := f.requestOAuthToken(form, nil)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Equal(t, "invalid_client", decodeOAuthError(t, rr))
+
+		count, err := f.Db.PurgeServiceAccountAssertions(time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+		count, err = f.Db.PurgeServiceAccountAssertions(time.Now().Add(2 * time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("assertion must be short-lived and have an ID", func(t *testing.T) {
+		f, cookie := setupServiceAccountTest(t)
+		key, publicKey := generateServiceAccountKey(t)
+		require.Equal(t, http.StatusOK, f.updateServiceAccount(cookie, "backup", &api.ApiUpdateServiceAccountRequest{
+			PublicKey: &publicKey,
+		}))
+
+		now := time.Now()
+		for _, claims := range []jwt.RegisteredClaims{
+			{ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)), ID: "1"},
+			{IssuedAt: jwt.NewNumericDate(now.Add(-time.Minute)), ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)), ID: "2"},
+			{IssuedAt: jwt.NewNumericDate(now.Add(time.Hour)), ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour + time.Minute)), ID: "3"},
+			{ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute))},
+		} {
+			claims.Issuer = "backup"
+			claims.Subject = "backup"
+			claims.Audience = jwt.ClaimStrings{"https://test.example.com/oauth/token"}
+			rr := f.requestOAuthToken(url.Values{
+				"grant_type":            {"client_credentials"},
+				"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+				"client_assertion":      {signAssertion(t, key, claims)},
+			}, nil)
+			assert.Equal(t, http.StatusUnauthorized, rr.Code, "assertion %s", claims.ID)
+		}
+	})
+
+	t.Run("assertion signed by other key", func(t *testing.T) {
+		f, cookie := setupServiceAccountTest(t)
+		_, publicKey := generateServiceAccountKey(t)
+		otherKey, _ := generateServiceAccountKey(t)
+		require.Equal(t, http.StatusOK, f.updateServiceAccount(cookie, "backup", &api.ApiUpdateServiceAccountRequest{
+			PublicKey: &publicKey,
+		}))
+
+		assertion := signAssertion(t, otherKey, jwt.RegisteredClaims{
+			Issuer:    "backup",
+			Subject:   "backup",
+			Audience:  jwt.ClaimStrings{"https://test.example.com/oauth/token"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			ID:        "1",
+		})
+		rr := f.requestOAuthToken(url.Values{
+			"grant_type":            {"client_credentials"},
+			"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+			"client_assertion":      {assertion},
+		}, nil)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Equal(t, "invalid_client", decodeOAuthError(t, rr))
+	})
+
+	t.Run("disabled service account", func(t *testing.T) {
+		f, cookie := setupServiceAccountTest(t)
+		require.Equal(t, http.StatusOK, f.updateServiceAccount(cookie, "backup", &api.ApiUpdateServiceAccountRequest{}))
+		secret := f.createServiceAccountSecret(t, cookie, "backup")
+		form := url.Values{
+			"grant_type":    {"client_credentials"},
+			"client_id":     {"backup"},
+			"client_secret": {secret.ClientSecret},
+		}
+		resp := decodeOAuthToken(t, f.requestOAuthToken(form, nil))
+
+		disabled := true
+		require.Equal(t, http.StatusOK, f.updateServiceAccount(cookie, "backup", &api.ApiUpdateServiceAccountRequest{Disabled: &disabled}))
+		rr := f.requestOAuthToken(form, nil)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Equal(t, "invalid_client", decodeOAuthError(t, rr))
+		_, err := f.Auth.ValidateServiceAccountToken(resp.AccessToken, "https://test.example.com", time.Now())
+		assert.ErrorIs(t, err, auth.ErrServiceAccountDisabled)
+	})
+
+	t.Run("invalid secret", func(t *testing.T) {
+		f, cookie := setupServiceAccountTest(t)
+		require.Equal(t, http.StatusOK, f.updateServiceAccount(cookie, "backup", &api.ApiUpdateServiceAccountRequest{}))
+		f.createServiceAccountSecret(t, cookie, "backup")
+
+		rr := f.requestOAuthToken(url.Values{
+			"grant_type":    {"client_credentials"},
+			"client_id":     {"backup"},
+			"client_secret": {"wrong"},
+		}, nil)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Equal(t, "invalid_client", decodeOAuthError(t, rr))
+
+		events := f.listSecurityEvents(t, cookie, "")
+		require.Len(t, events, 1)
+		assert.Equal(t, "backup", events[0].Principal)
+	})
+
+	t.Run("unsupported grant type", func(t *testing.T) {
+		f := CreateFixture(t)
+
+		rr := f.requestOAuthToken(url.Values{
+			"grant_type": {"password"},
+		}, nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, "unsupported_grant_type", decodeOAuthError(t, rr))
+	})
+}
@@ -0,0 +1,154 @@
+package rest
+
+import (
+	"boivie/ubergang/server/auth"
+	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/session"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const oidcAuthorizationCodeLifetime = 1 * time.Minute
+
+// redirectOidcSignin lets the user sign in, and then returns to the
+// authorization endpoint to continue the request.
+func (s *ApiModule) redirectOidcSignin(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	// Signing in satisfies these, and keeping them would cause a loop.
+	q.Del("prompt")
+	q.Del("max_age")
+	rd := "/oauth/authorize?" + q.Encode()
+	http.Redirect(w, r, "/signin?rd="+url.QueryEscape(rd), http.StatusFound)
+}
+
+func redirectAuthorizeResponse(w http.ResponseWriter, r *http.Request, redirectUri string, params url.Values) {
+	u, err := url.Parse(redirectUri)
+	if err != nil {
+		http.Error(w, "Invalid redirect URI", http.StatusBadRequest)
+		return
+	}
+	q := u.Query()
+	for k, v := range params {
+		q[k] = v
+	}
+	u.RawQuery = q.Encode()
+	http.Redirect(w, r, u.String(), http.StatusFound)
+}
+
+// needsSignin returns true if the user must sign in again before authorizing
+// the client, as requested using the `prompt` and `max_age` parameters.
+func needsSignin(q url.Values, sess *models.Session, now time.Time) bool {
+	if slices.Contains(strings.Fields(q.Get("prompt")), "login") {
+		return true
+	}
+	if maxAge := q.Get("max_age"); maxAge != "" {
+		seconds, err := strconv.Atoi(maxAge)
+		if err == nil && now.Sub(session.AuthenticatedAt(sess)) > time.Duration(seconds)*time.Second {
+			return true
+		}
+	}
+	return false
+}
+
+// handleOidcAuthorize is the OAuth 2.0 authorization endpoint, supporting the
+// authorization code flow with PKCE.
+// https://openid.net/specs/openid-connect-core-1_0.html#AuthorizationEndpoint
+func (s *ApiModule) handleOidcAuthorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	// Errors about the client or redirect URI must not be redirected.
+	client, err := s.db.GetOidcClient(q.Get("client_id"))
+	if err != nil {
+		http.Error(w, "Unknown client", http.StatusBadRequest)
+		return
+	}
+	redirectUri := q.Get("redirect_uri")
+	if !slices.Contains(client.RedirectUris, redirectUri) {
+		http.Error(w, "Invalid redirect URI", http.StatusBadRequest)
+		return
+	}
+
+	fail := func(code, description string) {
+		params := url.Values{"error": {code}, "iss": {s.issuer()}}
+		if description != "" {
+			params.Set("error_description", description)
+		}
+		if state := q.Get("state"); state != "" {
+			params.Set("state", state)
+		}
+		redirectAuthorizeResponse(w, r, redirectUri, params)
+	}
+
+	if q.Get("response_type") != "code" {
+		fail("unsupported_response_type", "")
+		return
+	}
+	scopes := strings.Fields(q.Get("scope"))
+	if !slices.Contains(scopes, auth.OidcScopeOpenID) {
+		fail("invalid_scope", "the openid scope is required")
+		return
+	}
+	scopes = slices.DeleteFunc(scopes, func(scope string) bool {
+		return !slices.Contains(auth.OidcScopes, scope)
+	})
+	codeChallenge := q.Get("code_challenge")
+	if codeChallenge != "" && q.Get("code_challenge_method") != "S256" {
+		fail("invalid_request", "only the S256 code challenge method is supported")
+		return
+	}
+	if codeChallenge == "" && client.IsPublic {
+		fail("invalid_request", "PKCE is required for public clients")
+		return
+	}
+
+	now := time.Now()
+	user, sess, err := s.session.Get(r)
+	if err != nil || needsSignin(q, sess, now) {
+		if slices.Contains(strings.Fields(q.Get("prompt")), "none") {
+			fail("login_required", "")
+			return
+		}
+		s.redirectOidcSignin(w, r)
+		return
+	}
+	if sess.ImpersonatorId != "" {
+		// Clients would be given tokens that can be used to make changes.
+		fail("access_denied", "not allowed while viewing as another user")
+		return
+	}
+	s.session.Touch(sess, r)
+
+	if !s.auth.IsGroupAllowed(client, user) {
+		s.log.Infof("User %s is not allowed to sign in to OIDC client %s", user.Id, client.Id)
+		fail("access_denied", "the user is not a member of an allowed group")
+		return
+	}
+
+	code, err := s.auth.CreateAuthorizationCode(user.Id, &models.AuthenticationStateOidcAuthorize{
+		ClientId:      client.Id,
+		RedirectUri:   redirectUri,
+		Scopes:        scopes,
+		Nonce:         q.Get("nonce"),
+		CodeChallenge: codeChallenge,
+		SessionId:     sess.Id,
+		AuthTime:      timestamppb.New(session.AuthenticatedAt(sess)),
+	}, now.Add(oidcAuthorizationCodeLifetime))
+	if err != nil {
+		s.log.Warnf("Failed to store authorization code: %v", err)
+		fail("server_error", "")
+		return
+	}
+
+	s.log.Infof("User %s authorized OIDC client %s", user.Id, client.Id)
+	params := url.Values{"code": {code}, "iss": {s.issuer()}}
+	if state := q.Get("state"); state != "" {
+		params.Set("state", state)
+	}
+	redirectAuthorizeResponse(w, r, redirectUri, params)
+}
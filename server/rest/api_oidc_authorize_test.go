@@ -0,0 +1,207 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/models"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const testRedirectUri = "https://app.example.com/callback"
+
+// A PKCE code verifier and its S256 challenge.
+const testCodeVerifier = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+
+func testCodeChallenge() string {
+	hash := sha256.Sum256([]byte(testCodeVerifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+// setupOidcTest creates a client "app", which is public if `isPublic`, and a
+// signed in user.
+func setupOidcTest(t *testing.T, isPublic bool) (f *Fixture, adminCookie *http.Cookie, userCookie *http.Cookie) {
+	t.Helper()
+	f = CreateFixture(t)
+	adminCookie, _ = f.CreateAdmin("admin@example.com")
+	redirectUris := []string{testRedirectUri}
+	require.Equal(t, http.StatusOK, f.updateOidcClient(adminCookie, "app", &api.ApiUpdateOidcClientRequest{
+		RedirectUris: &redirectUris,
+		IsPublic:     &isPublic,
+	}))
+	userCookie, _ = f.CreateUser("user@example.com")
+	return
+}
+
+func (f *Fixture) authorize(cookie *http.Cookie, params url.Values) *httptest.ResponseRecorder {
+	return f.request("GET", "/oauth/authorize?"+params.Encode(), nil, cookie, nil)
+}
+
+func authorizeParams() url.Values {
+	return url.Values{
+		"client_id":             {"app"},
+		"redirect_uri":          {testRedirectUri},
+		"response_type":         {"code"},
+		"scope":                 {"openid email profile groups"},
+		"state":                 {"xyz"},
+		"nonce":                 {"n-0S6_WzA2Mj"},
+		"code_challenge":        {testCodeChallenge()},
+		"code_challenge_method": {"S256"},
+	}
+}
+
+// redirectQuery returns the query of the redirect, which must go to the
+// registered redirect URI.
+func redirectQuery(t *testing.T, rr *httptest.ResponseRecorder) url.Values {
+	t.Helper()
+	require.Equal(t, http.StatusFound, rr.Code, rr.Body.String())
+	u, err := url.Parse(rr.Header().Get("Location"))
+	require.NoError(t, err)
+	require.Equal(t, testRedirectUri, u.Scheme+"://"+u.Host+u.Path)
+	return u.Query()
+}
+
+func TestOidcAuthorize(t *testing.T) {
+	t.Run("issues code", func(t *testing.T) {
+		f, _, userCookie := setupOidcTest(t, true)
+
+		q := redirectQuery(t, f.authorize(userCookie, authorizeParams()))
+		assert.NotEmpty(t, q.Get("code"))
+		assert.Equal(t, "xyz", q.Get("state"))
+		assert.Equal(t, "https://test.example.com", q.Get("iss"))
+	})
+
+	t.Run("redirects to signin without session", func(t *testing.T) {
+		f, _, _ := setupOidcTest(t, true)
+
+		rr := f.authorize(nil, authorizeParams())
+		require.Equal(t, http.StatusFound, rr.Code)
+		u, err := url.Parse(rr.Header().Get("Location"))
+		require.NoError(t, err)
+		assert.Equal(t, "/signin", u.Path)
+		assert.Contains(t, u.Query().Get("rd"), "/oauth/authorize?")
+	})
+
+	t.Run("prompt=login redirects to signin", func(t *testing.T) {
+		f, _, userCookie := setupOidcTest(t, true)
+
+		params := authorizeParams()
+		params.Set("prompt", "login")
+		rr := f.authorize(userCookie, params)
+		require.Equal(t, http.StatusFound, rr.Code)
+		u, err := url.Parse(rr.Header().Get("Location"))
+		require.NoError(t, err)
+		assert.Equal(t, "/signin", u.Path)
+		assert.NotContains(t, u.Query().Get("rd"), "prompt")
+	})
+
+	t.Run("max_age redirects to signin", func(t *testing.T) {
+		f, _, userCookie := setupOidcTest(t, true)
+		req := httptest.NewRequest("GET", "/", nil)
+		req.AddCookie(userCookie)
+		_, session, err := f.Session.Get(req)
+		require.NoError(t, err)
+		require.NoError(t, f.Db.UpdateSession(session.Id, func(old *models.Session) (*models.Session, error) {
+			old.AuthenticatedAt = timestamppb.New(time.Now().Add(-time.Hour))
+			return old, nil
+		}))
+
+		params := authorizeParams()
+		params.Set("max_age", "60")
+		rr := f.authorize(userCookie, params)
+		require.Equal(t, http.StatusFound, rr.Code)
+		assert.Contains(t, rr.Header().Get("Location"), "/signin?")
+	})
+
+	t.Run("prompt=none without session", func(t *testing.T) {
+		f, _, _ := setupOidcTest(t, true)
+
+		params := authorizeParams()
+		params.Set("prompt", "none")
+		q := redirectQuery(t, f.authorize(nil, params))
+		assert.Equal(t, "login_required", q.Get("error"))
+	})
+
+	t.Run("public client requires PKCE", func(t *testing.T) {
+		f, _, userCookie := setupOidcTest(t, true)
+
+		params := authorizeParams()
+		params.Del("code_challenge")
+		params.Del("code_challenge_method")
+		q := redirectQuery(t, f.authorize(userCookie, params))
+		assert.Equal(t, "invalid_request", q.Get("error"))
+		assert.Empty(t, q.Get("code"))
+	})
+
+	t.Run("rejects plain code challenge", func(t *testing.T) {
+		f, _, userCookie := setupOidcTest(t, true)
+
+		params := authorizeParams()
+		params.Set("code_challenge_method", "plain")
+		q := redirectQuery(t, f.authorize(userCookie, params))
+		assert.Equal(t, "invalid_request", q.Get("error"))
+	})
+
+	t.Run("requires openid scope", func(t *testing.T) {
+		f, _, userCookie := setupOidcTest(t, true)
+
+		params := authorizeParams()
+		params.Set("scope", "email")
+		q := redirectQuery(t, f.authorize(userCookie, params))
+		assert.Equal(t, "invalid_scope", q.Get("error"))
+	})
+
+	t.Run("does not redirect to unregistered URI", func(t *testing.T) {
+		f, _, userCookie := setupOidcTest(t, true)
+
+		params := authorizeParams()
+		params.Set("redirect_uri", "https://evil.example.com/callback")
+		rr := f.authorize(userCookie, params)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Empty(t, rr.Header().Get("Location"))
+	})
+
+	t.Run("unknown client", func(t *testing.T) {
+		f, _, userCookie := setupOidcTest(t, true)
+
+		params := authorizeParams()
+		params.Set("client_id", "other")
+		rr := f.authorize(userCookie, params)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("denies users outside allowed groups", func(t *testing.T) {
+		f, adminCookie, userCookie := setupOidcTest(t, true)
+		groups := []string{"family"}
+		require.Equal(t, http.StatusOK, f.updateOidcClient(adminCookie, "app", &api.ApiUpdateOidcClientRequest{
+			AllowedGroups: &groups,
+		}))
+
+		q := redirectQuery(t, f.authorize(userCookie, authorizeParams()))
+		assert.Equal(t, "access_denied", q.Get("error"))
+
+		user := f.getUser(userCookie, "me")
+		require.Equal(t, http.StatusOK, f.request("POST", "/api/user/"+user.ID, &api.ApiUpdateUserRequest{
+			Groups: &groups,
+		}, adminCookie, nil).Code)
+
+		q = redirectQuery(t, f.authorize(userCookie, authorizeParams()))
+		assert.NotEmpty(t, q.Get("code"))
+	})
+
+	t.Run("denies administrators viewing the site as the user", func(t *testing.T) {
+		f, adminCookie, userCookie := setupOidcTest(t, true)
+		cookie := f.impersonate(t, adminCookie, f.getUser(userCookie, "me").ID)
+
+		q := redirectQuery(t, f.authorize(cookie, authorizeParams()))
+		assert.Equal(t, "access_denied", q.Get("error"))
+	})
+}
@@ -0,0 +1,20 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteOidcClient(t *testing.T) {
+	f, cookie := setupOidcClientTest(t)
+	require.Equal(t, http.StatusOK, f.updateOidcClient(cookie, "app", &api.ApiUpdateOidcClientRequest{}))
+
+	rr := f.request("DELETE", "/api/oidc-client/app", nil, cookie, nil)
+	require.Equal(t, http.StatusNoContent, rr.Code)
+
+	assert.Nil(t, f.getOidcClient(cookie, "app"))
+}
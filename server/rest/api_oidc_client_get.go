@@ -0,0 +1,50 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/models"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+func ToApiOidcClient(client *models.OidcClient) api.ApiOidcClient {
+	obj := api.ApiOidcClient{
+		ID:              client.Id,
+		Name:            client.Name,
+		RedirectUris:    client.RedirectUris,
+		IsPublic:        client.IsPublic,
+		HasClientSecret: client.HashedClientSecret != "",
+		AllowedGroups:   client.AllowedGroups,
+		CreatedAt:       client.CreatedAt.AsTime().Format(time.RFC3339),
+		UpdatedAt:       client.UpdatedAt.AsTime().Format(time.RFC3339),
+	}
+	if obj.RedirectUris == nil {
+		obj.RedirectUris = []string{}
+	}
+	if obj.AllowedGroups == nil {
+		obj.AllowedGroups = []string{}
+	}
+	return obj
+}
+
+func (s *ApiModule) handleOidcClientGet(w http.ResponseWriter, r *http.Request) {
+	user, _, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	if !user.IsAdmin {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	client, err := s.db.GetOidcClient(mux.Vars(r)["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	jsonify(w, ToApiOidcClient(client))
+}
@@ -0,0 +1,29 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOidcClient(t *testing.T) {
+	t.Run("returns client", func(t *testing.T) {
+		f, cookie := setupOidcClientTest(t)
+		require.Equal(t, http.StatusOK, f.updateOidcClient(cookie, "app", &api.ApiUpdateOidcClientRequest{}))
+
+		client := f.getOidcClient(cookie, "app")
+		require.NotNil(t, client)
+		assert.Equal(t, "app", client.ID)
+		assert.Equal(t, []string{}, client.RedirectUris)
+	})
+
+	t.Run("returns not found", func(t *testing.T) {
+		f, cookie := setupOidcClientTest(t)
+
+		rr := f.request("GET", "/api/oidc-client/missing", nil, cookie, nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
@@ -0,0 +1,31 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"sort"
+)
+
+func (s *ApiModule) handleOidcClientList(w http.ResponseWriter, r *http.Request) {
+	user, _, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	if !user.IsAdmin {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	clients := make([]api.ApiOidcClient, 0)
+	for _, client := range s.db.ListOidcClients() {
+		clients = append(clients, ToApiOidcClient(client))
+	}
+	sort.Slice(clients, func(i, j int) bool {
+		return clients[i].ID < clients[j].ID
+	})
+
+	jsonify(w, api.ApiListOidcClientsResponse{
+		OidcClients: clients,
+	})
+}
@@ -0,0 +1,23 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListOidcClients(t *testing.T) {
+	f, cookie := setupOidcClientTest(t)
+	require.Equal(t, http.StatusOK, f.updateOidcClient(cookie, "b", &api.ApiUpdateOidcClientRequest{}))
+	require.Equal(t, http.StatusOK, f.updateOidcClient(cookie, "a", &api.ApiUpdateOidcClientRequest{}))
+
+	resp := &api.ApiListOidcClientsResponse{}
+	rr := f.request("GET", "/api/oidc-client", nil, cookie, resp)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Len(t, resp.OidcClients, 2)
+	assert.Equal(t, "a", resp.OidcClients[0].ID)
+	assert.Equal(t, "b", resp.OidcClients[1].ID)
+}
@@ -0,0 +1,36 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func (s *ApiModule) handleOidcClientSecret(w http.ResponseWriter, r *http.Request) {
+	user, session, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	if !user.IsAdmin {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	before, _ := s.db.GetOidcClient(id)
+	secret, err := s.auth.SetOidcClientSecret(id)
+	if err != nil {
+		s.log.Warnf("Failed to create secret for OIDC client %s: %v", id, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	after, _ := s.db.GetOidcClient(id)
+	s.audit(r, user, session, "oidc-client.secret", id, before, after)
+
+	jsonify(w, api.ApiCreateOidcClientSecretResponse{
+		ClientId:     id,
+		ClientSecret: secret,
+	})
+}
@@ -0,0 +1,48 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (f *Fixture) createOidcClientSecret(t *testing.T, cookie *http.Cookie, id string) *api.ApiCreateOidcClientSecretResponse {
+	t.Helper()
+	resp := &api.ApiCreateOidcClientSecretResponse{}
+	rr := f.request("POST", "/api/oidc-client/"+id+"/secret", nil, cookie, resp)
+	require.Equal(t, http.StatusOK, rr.Code)
+	return resp
+}
+
+func TestCreateOidcClientSecret(t *testing.T) {
+	t.Run("creates secret", func(t *testing.T) {
+		f, cookie := setupOidcClientTest(t)
+		require.Equal(t, http.StatusOK, f.updateOidcClient(cookie, "app", &api.ApiUpdateOidcClientRequest{}))
+
+		resp := f.createOidcClientSecret(t, cookie, "app")
+		assert.Equal(t, "app", resp.ClientId)
+
+		client := f.getOidcClient(cookie, "app")
+		require.NotNil(t, client)
+		assert.True(t, client.HasClientSecret)
+
+		_, err := f.Auth.AuthenticateOidcClient("app", resp.ClientSecret)
+		assert.NoError(t, err)
+		_, err = f.Auth.AuthenticateOidcClient("app", "wrong")
+		assert.Error(t, err)
+	})
+
+	t.Run("public clients have no secret", func(t *testing.T) {
+		f, cookie := setupOidcClientTest(t)
+		isPublic := true
+		require.Equal(t, http.StatusOK, f.updateOidcClient(cookie, "app", &api.ApiUpdateOidcClientRequest{
+			IsPublic: &isPublic,
+		}))
+
+		rr := f.request("POST", "/api/oidc-client/app/secret", nil, cookie, nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
@@ -0,0 +1,102 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/models"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/mux"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// https://www.rfc-editor.org/rfc/rfc6749#section-3.1.2
+func validateRedirectUri(redirectUri string) error {
+	u, err := url.Parse(redirectUri)
+	if err != nil {
+		return err
+	}
+	if !u.IsAbs() {
+		return errors.New("redirect URI must be absolute")
+	}
+	if u.Fragment != "" {
+		return errors.New("redirect URI must not contain a fragment")
+	}
+	return nil
+}
+
+func (s *ApiModule) handleOidcClientUpdate(w http.ResponseWriter, r *http.Request) {
+	user, session, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	if !user.IsAdmin {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	var req api.ApiUpdateOidcClientRequest
+	err = parseJsonRequest(w, r, &req)
+	if err != nil {
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		http.Error(w, "ID is required", http.StatusBadRequest)
+		return
+	}
+
+	if req.RedirectUris != nil {
+		for _, redirectUri := range *req.RedirectUris {
+			if err := validateRedirectUri(redirectUri); err != nil {
+				http.Error(w, "Invalid redirect URI", http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	var before, after *models.OidcClient
+	err = s.db.UpdateOidcClient(id, func(old *models.OidcClient) (*models.OidcClient, error) {
+		before = proto.Clone(old).(*models.OidcClient)
+		now := time.Now()
+		if old == nil {
+			old = &models.OidcClient{
+				Id:        id,
+				Name:      id,
+				CreatedAt: timestamppb.New(now),
+			}
+		}
+		if req.Name != nil {
+			old.Name = *req.Name
+		}
+		if req.RedirectUris != nil {
+			old.RedirectUris = *req.RedirectUris
+		}
+		if req.IsPublic != nil {
+			old.IsPublic = *req.IsPublic
+			if old.IsPublic {
+				old.HashedClientSecret = ""
+			}
+		}
+		if req.AllowedGroups != nil {
+			old.AllowedGroups = *req.AllowedGroups
+		}
+		old.UpdatedAt = timestamppb.New(now)
+		after = old
+		return old, nil
+	})
+
+	if err != nil {
+		s.log.Warnf("Failed to update OIDC client %s: %v", id, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.audit(r, user, session, "oidc-client.update", id, before, after)
+
+	jsonify(w, api.ApiUpdateOidcClientResponse{})
+}
@@ -0,0 +1,92 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupOidcClientTest(t *testing.T) (*Fixture, *http.Cookie) {
+	t.Helper()
+	f := CreateFixture(t)
+	cookie, _ := f.CreateAdmin("admin@example.com")
+	return f, cookie
+}
+
+func (f *Fixture) updateOidcClient(cookie *http.Cookie, id string, req *api.ApiUpdateOidcClientRequest) int {
+	rr := f.request("POST", "/api/oidc-client/"+id, req, cookie, &api.ApiUpdateOidcClientResponse{})
+	return rr.Code
+}
+
+func (f *Fixture) getOidcClient(cookie *http.Cookie, id string) *api.ApiOidcClient {
+	resp := &api.ApiOidcClient{}
+	rr := f.request("GET", "/api/oidc-client/"+id, nil, cookie, resp)
+	if rr.Code != http.StatusOK {
+		return nil
+	}
+	return resp
+}
+
+func TestUpdateOidcClient(t *testing.T) {
+	t.Run("creates client", func(t *testing.T) {
+		f, cookie := setupOidcClientTest(t)
+
+		name := "Grafana"
+		redirectUris := []string{"https://grafana.example.com/login/generic_oauth"}
+		groups := []string{"admins"}
+		code := f.updateOidcClient(cookie, "grafana", &api.ApiUpdateOidcClientRequest{
+			Name:          &name,
+			RedirectUris:  &redirectUris,
+			AllowedGroups: &groups,
+		})
+		require.Equal(t, http.StatusOK, code)
+
+		client := f.getOidcClient(cookie, "grafana")
+		require.NotNil(t, client)
+		assert.Equal(t, "Grafana", client.Name)
+		assert.Equal(t, redirectUris, client.RedirectUris)
+		assert.Equal(t, groups, client.AllowedGroups)
+		assert.False(t, client.IsPublic)
+		assert.False(t, client.HasClientSecret)
+	})
+
+	t.Run("making client public removes secret", func(t *testing.T) {
+		f, cookie := setupOidcClientTest(t)
+		require.Equal(t, http.StatusOK, f.updateOidcClient(cookie, "app", &api.ApiUpdateOidcClientRequest{}))
+		f.createOidcClientSecret(t, cookie, "app")
+
+		isPublic := true
+		require.Equal(t, http.StatusOK, f.updateOidcClient(cookie, "app", &api.ApiUpdateOidcClientRequest{
+			IsPublic: &isPublic,
+		}))
+
+		client := f.getOidcClient(cookie, "app")
+		require.NotNil(t, client)
+		assert.True(t, client.IsPublic)
+		assert.False(t, client.HasClientSecret)
+	})
+
+	t.Run("rejects invalid redirect URI", func(t *testing.T) {
+		f, cookie := setupOidcClientTest(t)
+
+		for _, redirectUri := range []string{"/relative", "https://app.example.com/cb#fragment"} {
+			redirectUris := []string{redirectUri}
+			code := f.updateOidcClient(cookie, "app", &api.ApiUpdateOidcClientRequest{
+				RedirectUris: &redirectUris,
+			})
+			assert.Equal(t, http.StatusBadRequest, code, redirectUri)
+		}
+		assert.Nil(t, f.getOidcClient(cookie, "app"))
+	})
+
+	t.Run("requires admin", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("user@example.com")
+
+		code := f.updateOidcClient(cookie, "app", &api.ApiUpdateOidcClientRequest{})
+		assert.Equal(t, http.StatusForbidden, code)
+	})
+}
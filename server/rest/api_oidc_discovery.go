@@ -0,0 +1,53 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/auth"
+	"encoding/base64"
+	"net/http"
+)
+
+func (s *ApiModule) issuer() string {
+	return "https://" + s.config.Get().AdminFqdn
+}
+
+func (s *ApiModule) handleOidcDiscovery(w http.ResponseWriter, r *http.Request) {
+	issuer := s.issuer()
+	jsonify(w, api.ApiOidcDiscoveryResponse{
+		Issuer:                            issuer,
+		AuthorizationEndpoint:             issuer + "/oauth/authorize",
+		TokenEndpoint:                     issuer + "/oauth/token",
+		DeviceAuthorizationEndpoint:       issuer + "/oauth/device_authorization",
+		UserinfoEndpoint:                  issuer + auth.OidcUserinfoPath,
+		JwksUri:                           issuer + "/oauth/jwks",
+		ScopesSupported:                   auth.OidcScopes,
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{"authorization_code", "client_credentials", deviceCodeGrantType},
+		SubjectTypesSupported:             []string{"public"},
+		IdTokenSigningAlgValuesSupported:  []string{"ES256"},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{"S256"},
+		ClaimsSupported: []string{"iss", "sub", "aud", "exp", "iat", "auth_time", "nonce",
+			"name", "preferred_username", "email", "email_verified", "groups"},
+	})
+}
+
+func (s *ApiModule) handleOidcJwks(w http.ResponseWriter, r *http.Request) {
+	key, err := s.auth.SigningKey()
+	if err != nil {
+		s.log.Warnf("Failed to load signing key: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	jsonify(w, api.ApiJsonWebKeySet{
+		Keys: []api.ApiJsonWebKey{{
+			Kty: "EC",
+			Crv: "P-256",
+			X:   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+			Y:   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+			Use: "sig",
+			Alg: "ES256",
+			Kid: auth.KeyID(&key.PublicKey),
+		}},
+	})
+}
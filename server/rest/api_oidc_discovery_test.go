@@ -0,0 +1,44 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOidcDiscovery(t *testing.T) {
+	f := CreateFixture(t)
+
+	resp := &api.ApiOidcDiscoveryResponse{}
+	rr := f.request("GET", "/.well-known/openid-configuration", nil, nil, resp)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "https://test.example.com", resp.Issuer)
+	assert.Equal(t, "https://test.example.com/oauth/authorize", resp.AuthorizationEndpoint)
+	assert.Equal(t, "https://test.example.com/oauth/token", resp.TokenEndpoint)
+	assert.Equal(t, "https://test.example.com/oauth/userinfo", resp.UserinfoEndpoint)
+	assert.Equal(t, "https://test.example.com/oauth/jwks", resp.JwksUri)
+	assert.Contains(t, resp.ScopesSupported, "groups")
+	assert.Equal(t, []string{"S256"}, resp.CodeChallengeMethodsSupported)
+}
+
+func TestOidcJwks(t *testing.T) {
+	f := CreateFixture(t)
+
+	resp := &api.ApiJsonWebKeySet{}
+	rr := f.request("GET", "/oauth/jwks", nil, nil, resp)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Len(t, resp.Keys, 1)
+	assert.Equal(t, "EC", resp.Keys[0].Kty)
+	assert.Equal(t, "ES256", resp.Keys[0].Alg)
+
+	// Tokens signed by the server refer to the published key.
+	signed, err := f.Auth.SignToken(jwt.RegisteredClaims{Subject: "test"})
+	require.NoError(t, err)
+	token, _, err := jwt.NewParser().ParseUnverified(signed, &jwt.RegisteredClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, resp.Keys[0].Kid, token.Header["kid"])
+}
@@ -0,0 +1,82 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/auth"
+	"boivie/ubergang/server/models"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// authenticateOidcClient authenticates the OIDC client of a token request,
+// using HTTP Basic authentication or the client_id and client_secret parameters.
+func (s *ApiModule) authenticateOidcClient(r *http.Request) (*models.OidcClient, error) {
+	clientId, clientSecret, found := r.BasicAuth()
+	if !found {
+		clientId = r.PostForm.Get("client_id")
+		clientSecret = r.PostForm.Get("client_secret")
+	}
+	return s.auth.AuthenticateOidcClient(clientId, clientSecret)
+}
+
+// https://openid.net/specs/openid-connect-core-1_0.html#TokenEndpoint
+func (s *ApiModule) handleAuthorizationCodeGrant(w http.ResponseWriter, r *http.Request) {
+	client, err := s.authenticateOidcClient(r)
+	if err != nil {
+		s.recordTokenFailure(r, "invalid OIDC client credentials: "+err.Error())
+		respondOAuthError(w, http.StatusUnauthorized, "invalid_client", "")
+		return
+	}
+
+	now := time.Now()
+	state, err := s.auth.ConsumeAuthorizationCode(r.PostForm.Get("code"))
+	if err != nil {
+		s.recordTokenFailure(r, "invalid authorization code")
+		respondOAuthError(w, http.StatusBadRequest, "invalid_grant", "invalid or expired code")
+		return
+	}
+	if state.ExpiresAt.AsTime().Before(now) {
+		respondOAuthError(w, http.StatusBadRequest, "invalid_grant", "invalid or expired code")
+		return
+	}
+	authorization := state.GetOidcAuthorize()
+	if authorization.ClientId != client.Id || authorization.RedirectUri != r.PostForm.Get("redirect_uri") {
+		respondOAuthError(w, http.StatusBadRequest, "invalid_grant", "code was not issued to this client")
+		return
+	}
+	if authorization.CodeChallenge != "" &&
+		!auth.VerifyCodeChallenge(r.PostForm.Get("code_verifier"), authorization.CodeChallenge) {
+		s.recordTokenFailure(r, "invalid code verifier")
+		respondOAuthError(w, http.StatusBadRequest, "invalid_grant", "invalid code verifier")
+		return
+	}
+
+	// The user may have signed out after authorizing the client.
+	user, _, err := s.db.GetSession(authorization.SessionId)
+	if err != nil || user.Id != state.UserId {
+		respondOAuthError(w, http.StatusBadRequest, "invalid_grant", "the session has ended")
+		return
+	}
+	// Or have been disabled.
+	if user.IsDisabled {
+		respondOAuthError(w, http.StatusBadRequest, "invalid_grant", "the user is disabled")
+		return
+	}
+
+	idToken, accessToken, lifetime, err := s.auth.IssueOidcTokens(client, user, authorization, s.issuer(), now)
+	if err != nil {
+		s.log.Warnf("Failed to issue tokens for OIDC client %s: %v", client.Id, err)
+		respondOAuthError(w, http.StatusInternalServerError, "server_error", "")
+		return
+	}
+
+	s.log.Infof("Issued tokens for user %s to OIDC client %s", user.Id, client.Id)
+	respondOAuthToken(w, api.ApiOAuthTokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(lifetime.Seconds()),
+		Scope:       strings.Join(authorization.Scopes, " "),
+		IdToken:     idToken,
+	})
+}
@@ -0,0 +1,162 @@
+package rest
+
+import (
+	"boivie/ubergang/server/models"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (f *Fixture) authorizeCode(t *testing.T, cookie *http.Cookie) string {
+	t.Helper()
+	q := redirectQuery(t, f.authorize(cookie, authorizeParams()))
+	require.NotEmpty(t, q.Get("code"), q.Get("error"))
+	return q.Get("code")
+}
+
+func codeGrant(code string) url.Values {
+	return url.Values{
+		"grant_type":    {"authorization_code"},
+		"client_id":     {"app"},
+		"code":          {code},
+		"redirect_uri":  {testRedirectUri},
+		"code_verifier": {testCodeVerifier},
+	}
+}
+
+func TestOidcAuthorizationCodeGrant(t *testing.T) {
+	t.Run("public client with PKCE", func(t *testing.T) {
+		f, _, userCookie := setupOidcTest(t, true)
+		code := f.authorizeCode(t, userCookie)
+
+		resp := decodeOAuthToken(t, f.requestOAuthToken(codeGrant(code), nil))
+		assert.Equal(t, "Bearer", resp.TokenType)
+		assert.Equal(t, "openid email profile groups", resp.Scope)
+		assert.NotEmpty(t, resp.AccessToken)
+
+		claims := jwt.MapClaims{}
+		err := f.Auth.ParseToken(resp.IdToken, claims,
+			jwt.WithIssuer("https://test.example.com"),
+			jwt.WithAudience("app"))
+		require.NoError(t, err)
+		user := f.getUser(userCookie, "me")
+		assert.Equal(t, user.ID, claims["sub"])
+		assert.Equal(t, "user@example.com", claims["email"])
+		assert.Equal(t, "n-0S6_WzA2Mj", claims["nonce"])
+		assert.NotNil(t, claims["auth_time"])
+	})
+
+	t.Run("confidential client", func(t *testing.T) {
+		f, adminCookie, userCookie := setupOidcTest(t, false)
+		secret := f.createOidcClientSecret(t, adminCookie, "app")
+		code := f.authorizeCode(t, userCookie)
+
+		form := codeGrant(code)
+		form.Del("client_id")
+		resp := decodeOAuthToken(t, f.requestOAuthToken(form, func(r *http.Request) {
+			r.SetBasicAuth("app", secret.ClientSecret)
+		}))
+		assert.NotEmpty(t, resp.IdToken)
+	})
+
+	t.Run("confidential client with wrong secret", func(t *testing.T) {
+		f, adminCookie, userCookie := setupOidcTest(t, false)
+		f.createOidcClientSecret(t, adminCookie, "app")
+		code := f.authorizeCode(t, userCookie)
+
+		form := codeGrant(code)
+		form.Set("client_secret", "wrong")
+		rr := f.requestOAuthToken(form, nil)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Equal(t, "invalid_client", decodeOAuthError(t, rr))
+	})
+
+	t.Run("wrong code verifier", func(t *testing.T) {
+		f, _, userCookie := setupOidcTest(t, true)
+		code := f.authorizeCode(t, userCookie)
+
+		form := codeGrant(code)
+		form.Set("code_verifier", "wrong-verifier-that-is-long-enough-to-be-considered-valid")
+		rr := f.requestOAuthToken(form, nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, "invalid_grant", decodeOAuthError(t, rr))
+	})
+
+	t.Run("wrong redirect URI", func(t *testing.T) {
+		f, _, userCookie := setupOidcTest(t, true)
+		code := f.authorizeCode(t, userCookie)
+
+		form := codeGrant(code)
+		form.Set("redirect_uri", "https://app.example.com/other")
+		rr := f.requestOAuthToken(form, nil)
+		assert.Equal(t, "invalid_grant", decodeOAuthError(t, rr))
+	})
+
+	t.Run("code can only be used once", func(t *testing.T) {
+		f, _, userCookie := setupOidcTest(t, true)
+		code := f.authorizeCode(t, userCookie)
+
+		decodeOAuthToken(t, f.requestOAuthToken(codeGrant(code), nil))
+		rr := f.requestOAuthToken(codeGrant(code), nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, "invalid_grant", decodeOAuthError(t, rr))
+	})
+
+	t.Run("code must have its secret", func(t *testing.T) {
+		f, _, userCookie := setupOidcTest(t, true)
+		for _, tamper := range []func(id, secret string) string{
+			func(id, secret string) string { return id },
+			func(id, secret string) string { return id + "_" },
+			func(id, secret string) string { return id + "_" + strings.ToUpper(secret) },
+		} {
+			id, secret, found := strings.Cut(f.authorizeCode(t, userCookie), "_")
+			require.True(t, found)
+			assert.Len(t, secret, 32)
+
+			rr := f.requestOAuthToken(codeGrant(tamper(id, secret)), nil)
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			assert.Equal(t, "invalid_grant", decodeOAuthError(t, rr))
+		}
+	})
+
+	t.Run("session ended", func(t *testing.T) {
+		f, _, userCookie := setupOidcTest(t, true)
+		code := f.authorizeCode(t, userCookie)
+
+		user := f.getUser(userCookie, "me")
+		require.NoError(t, f.Db.DeleteSession(user.CurrentSession.ID))
+
+		rr := f.requestOAuthToken(codeGrant(code), nil)
+		assert.Equal(t, "invalid_grant", decodeOAuthError(t, rr))
+	})
+
+	t.Run("user disabled", func(t *testing.T) {
+		f, _, userCookie := setupOidcTest(t, true)
+		code := f.authorizeCode(t, userCookie)
+
+		user := f.getUser(userCookie, "me")
+		require.NoError(t, f.Db.UpdateUser(user.ID, func(old *models.User) (*models.User, error) {
+			old.IsDisabled = true
+			return old, nil
+		}))
+
+		rr := f.requestOAuthToken(codeGrant(code), nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, "invalid_grant", decodeOAuthError(t, rr))
+	})
+
+	t.Run("access token is rejected after expiry", func(t *testing.T) {
+		f, _, userCookie := setupOidcTest(t, true)
+		code := f.authorizeCode(t, userCookie)
+		resp := decodeOAuthToken(t, f.requestOAuthToken(codeGrant(code), nil))
+
+		_, _, err := f.Auth.ValidateOidcAccessToken(resp.AccessToken, "https://test.example.com", time.Now().Add(2*time.Hour))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,28 @@
+package rest
+
+import (
+	"boivie/ubergang/server/auth"
+	"net/http"
+	"time"
+)
+
+// https://openid.net/specs/openid-connect-core-1_0.html#UserInfo
+func (s *ApiModule) handleOidcUserinfo(w http.ResponseWriter, r *http.Request) {
+	token, found := auth.BearerToken(r)
+	if !found {
+		w.Header().Set("WWW-Authenticate", `Bearer`)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	user, scopes, err := s.auth.ValidateOidcAccessToken(token, s.issuer(), time.Now())
+	if err != nil {
+		s.log.Infof("Invalid userinfo access token: %v", err)
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	jsonify(w, s.auth.OidcUserClaims(user, scopes))
+}
@@ -0,0 +1,84 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (f *Fixture) userinfo(token string) *httptest.ResponseRecorder {
+	httpReq, _ := http.NewRequest("GET", "/oauth/userinfo", nil)
+	httpReq.Host = "test.example.com"
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	f.router.ServeHTTP(rr, httpReq)
+	return rr
+}
+
+func (f *Fixture) oidcAccessToken(t *testing.T, cookie *http.Cookie, scope string) string {
+	t.Helper()
+	params := authorizeParams()
+	params.Set("scope", scope)
+	q := redirectQuery(t, f.authorize(cookie, params))
+	resp := decodeOAuthToken(t, f.requestOAuthToken(codeGrant(q.Get("code")), nil))
+	return resp.AccessToken
+}
+
+func TestOidcUserinfo(t *testing.T) {
+	t.Run("returns claims for granted scopes", func(t *testing.T) {
+		f, adminCookie, userCookie := setupOidcTest(t, true)
+		user := f.getUser(userCookie, "me")
+		groups := []string{"family"}
+		require.Equal(t, http.StatusOK, f.request("POST", "/api/user/"+user.ID, &api.ApiUpdateUserRequest{
+			Groups: &groups,
+		}, adminCookie, nil).Code)
+
+		rr := f.userinfo(f.oidcAccessToken(t, userCookie, "openid email groups"))
+		require.Equal(t, http.StatusOK, rr.Code)
+		claims := map[string]interface{}{}
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&claims))
+		assert.Equal(t, user.ID, claims["sub"])
+		assert.Equal(t, "user@example.com", claims["email"])
+		assert.Equal(t, true, claims["email_verified"])
+		assert.Equal(t, []interface{}{"family"}, claims["groups"])
+		assert.NotContains(t, claims, "name")
+	})
+
+	t.Run("rejects invalid token", func(t *testing.T) {
+		f, _, _ := setupOidcTest(t, true)
+
+		rr := f.userinfo("invalid")
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Contains(t, rr.Header().Get("WWW-Authenticate"), "invalid_token")
+	})
+
+	t.Run("rejects service account token", func(t *testing.T) {
+		f, adminCookie, _ := setupOidcTest(t, true)
+		require.Equal(t, http.StatusOK, f.updateServiceAccount(adminCookie, "backup", &api.ApiUpdateServiceAccountRequest{}))
+		secret := f.createServiceAccountSecret(t, adminCookie, "backup")
+		resp := decodeOAuthToken(t, f.requestOAuthToken(url.Values{
+			"grant_type":    {"client_credentials"},
+			"client_id":     {"backup"},
+			"client_secret": {secret.ClientSecret},
+		}, nil))
+
+		rr := f.userinfo(resp.AccessToken)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+
+	t.Run("rejects token of deleted client", func(t *testing.T) {
+		f, adminCookie, userCookie := setupOidcTest(t, true)
+		token := f.oidcAccessToken(t, userCookie, "openid")
+
+		require.Equal(t, http.StatusNoContent, f.request("DELETE", "/api/oidc-client/app", nil, adminCookie, nil).Code)
+
+		rr := f.userinfo(token)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+}
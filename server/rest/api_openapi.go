@@ -0,0 +1,12 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api/openapi"
+	"net/http"
+)
+
+// handleOpenApi serves the OpenAPI document of the JSON API.
+func (s *ApiModule) handleOpenApi(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(openapi.Json)
+}
@@ -0,0 +1,130 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/api/client"
+	"boivie/ubergang/server/api/openapi"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// routerTransport serves the requests of an API client with the fixture's
+// router, as the user signed in with `cookie`.
+type routerTransport struct {
+	f      *Fixture
+	cookie *http.Cookie
+}
+
+func (t *routerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Host = "test.example.com"
+	if t.cookie != nil {
+		req.AddCookie(t.cookie)
+	}
+	rr := httptest.NewRecorder()
+	t.f.router.ServeHTTP(rr, req)
+	return rr.Result(), nil
+}
+
+func (f *Fixture) client(cookie *http.Cookie) *client.Client {
+	c := client.New("https://test.example.com", "")
+	c.HttpClient = &http.Client{Transport: &routerTransport{f, cookie}}
+	return c
+}
+
+func TestOpenApi(t *testing.T) {
+	t.Run("serves the document", func(t *testing.T) {
+		f := CreateFixture(t)
+		rr := f.request("GET", "/api/openapi.json", nil, nil, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+		assert.Equal(t, openapi.Json, rr.Body.Bytes())
+	})
+
+	t.Run("documents all endpoints", func(t *testing.T) {
+		f := CreateFixture(t)
+		r := mux.NewRouter()
+		(&ApiModule{}).RegisterBootstrapEndpoints(r)
+
+		registered := make(map[string]bool)
+		for _, router := range []*mux.Router{f.router, r} {
+			err := router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+				path, err := route.GetPathTemplate()
+				if err != nil || !strings.HasPrefix(path, "/api/") {
+					return nil
+				}
+				methods, err := route.GetMethods()
+				require.NoError(t, err)
+				for _, method := range methods {
+					registered[method+" "+path] = true
+				}
+				return nil
+			})
+			require.NoError(t, err)
+		}
+
+		documented := make(map[string]bool)
+		for _, e := range api.Endpoints {
+			documented[e.Method+" "+e.Path] = true
+		}
+		for endpoint := range registered {
+			assert.True(t, documented[endpoint], "%s is missing in api.Endpoints", endpoint)
+		}
+		for endpoint := range documented {
+			assert.True(t, registered[endpoint], "%s in api.Endpoints isn't registered", endpoint)
+		}
+	})
+
+	t.Run("generated client", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+		c := f.client(cookie)
+		ctx := context.Background()
+
+		upstream := "http://localhost:3000"
+		_, err := c.UpdateBackend(ctx, "grafana.example.com", &api.ApiUpdateBackendRequest{UpstreamUrl: &upstream})
+		require.NoError(t, err)
+
+		backend, err := c.GetBackend(ctx, "grafana.example.com")
+		require.NoError(t, err)
+		assert.Equal(t, upstream, backend.UpstreamUrl)
+
+		list, err := c.ListBackends(ctx)
+		require.NoError(t, err)
+		assert.Len(t, list.Backends, 1)
+
+		config, err := c.ExportConfig(ctx)
+		require.NoError(t, err)
+		assert.Contains(t, string(config), "grafana.example.com")
+
+		plan, err := c.PlanConfig(ctx, []byte("backends: []\n"))
+		require.NoError(t, err)
+		require.Len(t, plan.Changes, 1)
+		assert.Equal(t, "delete", plan.Changes[0].Action)
+
+		require.NoError(t, c.DeleteBackend(ctx, "grafana.example.com"))
+		_, err = c.GetBackend(ctx, "grafana.example.com")
+		var apiErr *client.Error
+		require.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	})
+
+	t.Run("generated client reports errors", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("user@example.com")
+
+		_, err := f.client(cookie).ListAuditRecords(context.Background(), nil)
+		var apiErr *client.Error
+		require.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+		assert.Equal(t, "Not authorized", apiErr.Message)
+	})
+}
@@ -0,0 +1,257 @@
+package rest
+
+import (
+	"boivie/ubergang/server/auth"
+	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/scim"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	scimPathPrefix = "/scim/v2"
+	// The maximum number of resources returned in one page.
+	scimMaxResults = 1000
+)
+
+func (s *ApiModule) scimLocation(resourceType, id string) string {
+	return s.issuer() + scimPathPrefix + "/" + resourceType + "/" + id
+}
+
+func respondScim(w http.ResponseWriter, status int, response interface{}) {
+	w.Header().Set("Content-Type", scim.ContentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func respondScimError(w http.ResponseWriter, status int, scimType, detail string) {
+	respondScim(w, status, scim.ErrorResponse{
+		Schemas:  []string{scim.SchemaError},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	})
+}
+
+// respondScimErr responds with a 400 Bad Request if `err` is a *scim.Error,
+// and a 500 Internal Server Error otherwise.
+func (s *ApiModule) respondScimErr(w http.ResponseWriter, err error) {
+	var scimErr *scim.Error
+	if errors.As(err, &scimErr) {
+		status := http.StatusBadRequest
+		if scimErr.Type == scim.ErrUniqueness {
+			status = http.StatusConflict
+		}
+		respondScimError(w, status, scimErr.Type, scimErr.Detail)
+		return
+	}
+	s.log.Warnf("SCIM request failed: %v", err)
+	respondScimError(w, http.StatusInternalServerError, "", "Internal error")
+}
+
+// authenticateScim checks that the request has a personal access token with
+// the SCIM scope, belonging to an admin, and returns the admin. If not, it
+// responds and returns nil.
+func (s *ApiModule) authenticateScim(w http.ResponseWriter, r *http.Request) *models.User {
+	token, found := auth.BearerToken(r)
+	if !found {
+		w.Header().Set("WWW-Authenticate", `Bearer`)
+		respondScimError(w, http.StatusUnauthorized, "", "Missing access token")
+		return nil
+	}
+	user, accessToken, err := s.auth.ValidateAccessToken(token, time.Now())
+	if err != nil {
+		s.log.Warnf("Invalid SCIM access token: %v", err)
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		respondScimError(w, http.StatusUnauthorized, "", "Invalid access token")
+		return nil
+	}
+	if !auth.HasScope(accessToken, auth.ScopeScim) || !user.IsAdmin {
+		s.log.Warnf("Access token %s can't be used for SCIM", accessToken.Id)
+		respondScimError(w, http.StatusForbidden, "", "Not authorized")
+		return nil
+	}
+	return user
+}
+
+func parseScimRequest(w http.ResponseWriter, r *http.Request, req interface{}) error {
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		respondScimError(w, http.StatusBadRequest, scim.ErrInvalidSyntax, err.Error())
+		return err
+	}
+	return nil
+}
+
+// respondScimList filters and paginates resources, as requested by the
+// "filter", "startIndex" and "count" query parameters.
+func (s *ApiModule) respondScimList(w http.ResponseWriter, r *http.Request, resources []interface{}) {
+	var filter scim.Filter
+	if expr := r.URL.Query().Get("filter"); expr != "" {
+		var err error
+		filter, err = scim.ParseFilter(expr)
+		if err != nil {
+			s.respondScimErr(w, err)
+			return
+		}
+	}
+
+	matching := make([]interface{}, 0)
+	for _, resource := range resources {
+		if filter != nil {
+			m, err := scim.ToMap(resource)
+			if err != nil || !filter.Matches(m) {
+				continue
+			}
+		}
+		matching = append(matching, resource)
+	}
+
+	// Both are 1-based, and invalid values are to be treated as the defaults.
+	startIndex, err := strconv.Atoi(r.URL.Query().Get("startIndex"))
+	if err != nil || startIndex < 1 {
+		startIndex = 1
+	}
+	count, err := strconv.Atoi(r.URL.Query().Get("count"))
+	if err != nil || count < 0 || count > scimMaxResults {
+		count = scimMaxResults
+	}
+	page := matching[min(startIndex-1, len(matching)):]
+	page = page[:min(count, len(page))]
+
+	respondScim(w, http.StatusOK, scim.ListResponse{
+		Schemas:      []string{scim.SchemaListResponse},
+		TotalResults: len(matching),
+		StartIndex:   startIndex,
+		ItemsPerPage: len(page),
+		Resources:    page,
+	})
+}
+
+// scimEmail returns the user's e-mail address, which is the SCIM user name.
+// Clients that only update the e-mail addresses are also supported.
+func scimEmail(before, after *scim.User) string {
+	if before != nil && after.UserName == before.UserName && after.PrimaryEmail() != before.PrimaryEmail() && after.PrimaryEmail() != "" {
+		return after.PrimaryEmail()
+	}
+	return after.UserName
+}
+
+// applyScimUser updates `user` with the attributes of a SCIM user.
+func applyScimUser(user *models.User, before, after *scim.User) error {
+	email := strings.ToLower(strings.TrimSpace(scimEmail(before, after)))
+	if email == "" {
+		return &scim.Error{Type: scim.ErrInvalidValue, Detail: "userName is required"}
+	}
+	user.Email = email
+	user.ExternalId = after.ExternalID
+
+	user.DisplayName = after.DisplayName
+	if user.DisplayName == "" && after.Name != nil {
+		user.DisplayName = after.Name.Formatted
+		if user.DisplayName == "" {
+			user.DisplayName = strings.TrimSpace(after.Name.GivenName + " " + after.Name.FamilyName)
+		}
+	}
+	if user.DisplayName == "" {
+		user.DisplayName = email
+	}
+
+	if after.Active != nil {
+		user.IsDisabled = !bool(*after.Active)
+	}
+	// The extension is left as is if not provided, so that clients that don't
+	// know about it don't remove admin rights or allowed hosts.
+	if after.Extension != nil {
+		user.IsAdmin = bool(after.Extension.IsAdmin)
+		user.AllowedHosts = make([]string, 0, len(after.Extension.AllowedHosts))
+		for _, host := range after.Extension.AllowedHosts {
+			user.AllowedHosts = append(user.AllowedHosts, strings.ToLower(strings.TrimSpace(host)))
+		}
+	}
+	return nil
+}
+
+// saveScimUser stores a user that has been updated through SCIM. It returns the
+// user from before and after the update.
+func (s *ApiModule) saveScimUser(id string, update func(user *models.User) error) (before, ret *models.User, err error) {
+	err = s.db.UpdateUser(id, func(old *models.User) (*models.User, error) {
+		if old == nil {
+			return nil, errUserNotFound
+		}
+		before = proto.Clone(old).(*models.User)
+		if err := update(old); err != nil {
+			return nil, err
+		}
+		ret = old
+		return old, nil
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "already mapped to another user") {
+			return nil, nil, &scim.Error{Type: scim.ErrUniqueness, Detail: "userName is already in use"}
+		}
+		return nil, nil, err
+	}
+	if ret.IsDisabled {
+		// Disabled users can't use their sessions, so remove them.
+		_, err = s.db.DeleteSessionsIf(func(session *models.Session) bool {
+			return session.UserId == id
+		})
+		if err != nil {
+			s.log.Warnf("Failed to remove sessions of disabled user %s: %v", id, err)
+		}
+	}
+	return before, ret, nil
+}
+
+// setGroupMembers makes the users in `memberIds` the only members of the group
+// with ID `groupId`, and records the changed users as changed by `actor`.
+func (s *ApiModule) setGroupMembers(r *http.Request, actor *models.User, groupId string, memberIds []string) error {
+	for _, memberId := range memberIds {
+		if _, err := s.db.GetUserById(memberId); err != nil {
+			return &scim.Error{Type: scim.ErrInvalidValue, Detail: "unknown member " + memberId}
+		}
+	}
+	for _, user := range s.db.ListUsers() {
+		isMember := slices.Contains(memberIds, user.Id)
+		if isMember == slices.Contains(user.GroupIds, groupId) {
+			continue
+		}
+		var before, after *models.User
+		err := s.db.UpdateUser(user.Id, func(old *models.User) (*models.User, error) {
+			if old == nil {
+				return nil, errUserNotFound
+			}
+			before = proto.Clone(old).(*models.User)
+			old.GroupIds = slices.DeleteFunc(old.GroupIds, func(id string) bool {
+				return id == groupId
+			})
+			if isMember {
+				old.GroupIds = append(old.GroupIds, groupId)
+			}
+			after = old
+			return old, nil
+		})
+		if err != nil {
+			return err
+		}
+		s.audit(r, actor, nil, "user.update", user.Id, before, after)
+	}
+	return nil
+}
+
+// groupMemberIds returns the IDs of the members of a SCIM group.
+func groupMemberIds(group *scim.Group) []string {
+	ids := make([]string, 0, len(group.Members))
+	for _, member := range group.Members {
+		ids = append(ids, member.Value)
+	}
+	return ids
+}
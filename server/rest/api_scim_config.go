@@ -0,0 +1,49 @@
+package rest
+
+import (
+	"boivie/ubergang/server/scim"
+	"net/http"
+)
+
+type scimSupported struct {
+	Supported bool `json:"supported"`
+}
+
+type scimFilterSupported struct {
+	Supported  bool `json:"supported"`
+	MaxResults int  `json:"maxResults"`
+}
+
+type scimAuthenticationScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type scimServiceProviderConfig struct {
+	Schemas               []string                   `json:"schemas"`
+	Patch                 scimSupported              `json:"patch"`
+	Bulk                  scimSupported              `json:"bulk"`
+	Filter                scimFilterSupported        `json:"filter"`
+	ChangePassword        scimSupported              `json:"changePassword"`
+	Sort                  scimSupported              `json:"sort"`
+	Etag                  scimSupported              `json:"etag"`
+	AuthenticationSchemes []scimAuthenticationScheme `json:"authenticationSchemes"`
+}
+
+func (s *ApiModule) handleScimConfig(w http.ResponseWriter, r *http.Request) {
+	if s.authenticateScim(w, r) == nil {
+		return
+	}
+
+	respondScim(w, http.StatusOK, scimServiceProviderConfig{
+		Schemas: []string{scim.SchemaConfig},
+		Patch:   scimSupported{Supported: true},
+		Filter:  scimFilterSupported{Supported: true, MaxResults: scimMaxResults},
+		AuthenticationSchemes: []scimAuthenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "Personal access token",
+			Description: "A personal access token with the scim scope, created by an admin",
+		}},
+	})
+}
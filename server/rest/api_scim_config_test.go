@@ -0,0 +1,20 @@
+package rest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScimConfig(t *testing.T) {
+	f, token := setupScimTest(t)
+
+	resp := &scimServiceProviderConfig{}
+	rr := f.scim(t, "GET", "/scim/v2/ServiceProviderConfig", token, "", resp)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.True(t, resp.Patch.Supported)
+	assert.True(t, resp.Filter.Supported)
+	assert.False(t, resp.Bulk.Supported)
+}
@@ -0,0 +1,75 @@
+package rest
+
+import (
+	"boivie/ubergang/server/common"
+	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/scim"
+	"errors"
+	"net/http"
+	"strings"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// findGroupByName returns the group with the given display name, if any.
+func (s *ApiModule) findGroupByName(name string) *models.Group {
+	for _, group := range s.db.ListGroups() {
+		if group.DisplayName == name {
+			return group
+		}
+	}
+	return nil
+}
+
+func (s *ApiModule) handleScimGroupCreate(w http.ResponseWriter, r *http.Request) {
+	actor := s.authenticateScim(w, r)
+	if actor == nil {
+		return
+	}
+
+	var req scim.Group
+	if parseScimRequest(w, r, &req) != nil {
+		return
+	}
+
+	name := strings.TrimSpace(req.DisplayName)
+	if name == "" {
+		respondScimError(w, http.StatusBadRequest, scim.ErrInvalidValue, "displayName is required")
+		return
+	}
+	if s.findGroupByName(name) != nil {
+		respondScimError(w, http.StatusConflict, scim.ErrUniqueness, "displayName is already in use")
+		return
+	}
+
+	group := &models.Group{
+		Id:          common.MakeRandomID(),
+		DisplayName: name,
+		ExternalId:  req.ExternalID,
+		CreatedAt:   timestamppb.Now(),
+		UpdatedAt:   timestamppb.Now(),
+	}
+	err := s.db.UpdateGroup(group.Id, func(old *models.Group) (*models.Group, error) {
+		if old != nil {
+			return nil, errors.New("ID collision")
+		}
+		return group, nil
+	})
+	if err == nil {
+		err = s.setGroupMembers(r, actor, group.Id, groupMemberIds(&req))
+		if err != nil {
+			_ = s.db.UpdateGroup(group.Id, func(old *models.Group) (*models.Group, error) {
+				return nil, nil
+			})
+		}
+	}
+	if err != nil {
+		s.respondScimErr(w, err)
+		return
+	}
+	s.log.Infof("Provisioned group %s through SCIM", name)
+	s.audit(r, actor, nil, "group.create", group.Id, nil, group)
+
+	w.Header().Set("Location", s.scimLocation("Groups", group.Id))
+	respondScim(w, http.StatusCreated, s.toScimGroup(group, s.db.ListUsers()))
+}
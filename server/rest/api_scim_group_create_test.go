@@ -0,0 +1,68 @@
+package rest
+
+import (
+	"boivie/ubergang/server/scim"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createScimGroup provisions a group with the given members, and returns it.
+func (f *Fixture) createScimGroup(t *testing.T, token, name string, memberIds ...string) *scim.Group {
+	t.Helper()
+	body := `{"schemas": ["urn:ietf:params:scim:schemas:core:2.0:Group"], "displayName": "` + name + `", "members": [`
+	for i, id := range memberIds {
+		if i > 0 {
+			body += ","
+		}
+		body += `{"value": "` + id + `"}`
+	}
+	body += `]}`
+	group := &scim.Group{}
+	rr := f.scim(t, "POST", "/scim/v2/Groups", token, body, group)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	return group
+}
+
+func TestScimGroupCreate(t *testing.T) {
+	t.Run("creates group with members", func(t *testing.T) {
+		f, token := setupScimTest(t)
+		user := f.createScimUser(t, token)
+
+		group := f.createScimGroup(t, token, "engineering", user.ID)
+		assert.NotEmpty(t, group.ID)
+		assert.Equal(t, "engineering", group.DisplayName)
+		require.Len(t, group.Members, 1)
+		assert.Equal(t, user.ID, group.Members[0].Value)
+
+		stored, err := f.Db.GetUserById(user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{group.ID}, stored.GroupIds)
+		assert.Equal(t, []string{"engineering"}, f.Auth.UserGroups(stored))
+	})
+
+	t.Run("creates empty group", func(t *testing.T) {
+		f, token := setupScimTest(t)
+
+		group := f.createScimGroup(t, token, "empty")
+		assert.Empty(t, group.Members)
+	})
+
+	t.Run("rejects existing name", func(t *testing.T) {
+		f, token := setupScimTest(t)
+		f.createScimGroup(t, token, "engineering")
+
+		rr := f.scim(t, "POST", "/scim/v2/Groups", token, `{"displayName": "engineering"}`, nil)
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+
+	t.Run("rejects unknown member", func(t *testing.T) {
+		f, token := setupScimTest(t)
+
+		rr := f.scim(t, "POST", "/scim/v2/Groups", token, `{"displayName": "engineering", "members": [{"value": "missing"}]}`, nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Nil(t, f.findGroupByName(t, "engineering"))
+	})
+}
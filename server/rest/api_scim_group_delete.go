@@ -0,0 +1,39 @@
+package rest
+
+import (
+	"boivie/ubergang/server/models"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+var errGroupNotFound = errors.New("group not found")
+
+func (s *ApiModule) handleScimGroupDelete(w http.ResponseWriter, r *http.Request) {
+	actor := s.authenticateScim(w, r)
+	if actor == nil {
+		return
+	}
+
+	group, err := s.db.GetGroup(mux.Vars(r)["id"])
+	if err != nil {
+		respondScimError(w, http.StatusNotFound, "", "Group not found")
+		return
+	}
+
+	err = s.setGroupMembers(r, actor, group.Id, nil)
+	if err == nil {
+		err = s.db.UpdateGroup(group.Id, func(old *models.Group) (*models.Group, error) {
+			return nil, nil
+		})
+	}
+	if err != nil {
+		s.respondScimErr(w, err)
+		return
+	}
+	s.log.Infof("Deleted group %s through SCIM", group.DisplayName)
+	s.audit(r, actor, nil, "group.delete", group.Id, group, nil)
+
+	w.WriteHeader(http.StatusNoContent)
+}
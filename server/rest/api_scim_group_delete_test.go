@@ -0,0 +1,45 @@
+package rest
+
+import (
+	"boivie/ubergang/server/models"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// findGroupByName is a test helper that looks up a group by its name.
+func (f *Fixture) findGroupByName(t *testing.T, name string) *models.Group {
+	t.Helper()
+	for _, group := range f.Db.ListGroups() {
+		if group.DisplayName == name {
+			return group
+		}
+	}
+	return nil
+}
+
+func TestScimGroupDelete(t *testing.T) {
+	f, token := setupScimTest(t)
+	user := f.createScimUser(t, token)
+	// Groups assigned by administrators are kept, even if they have the same
+	// name.
+	require.NoError(t, f.Db.UpdateUser(user.ID, func(old *models.User) (*models.User, error) {
+		old.Groups = []string{"engineering"}
+		return old, nil
+	}))
+	created := f.createScimGroup(t, token, "engineering", user.ID)
+
+	rr := f.scim(t, "DELETE", "/scim/v2/Groups/"+created.ID, token, "", nil)
+	require.Equal(t, http.StatusNoContent, rr.Code)
+
+	assert.Nil(t, f.findGroupByName(t, "engineering"))
+	stored, err := f.Db.GetUserById(user.ID)
+	require.NoError(t, err)
+	assert.Empty(t, stored.GroupIds)
+	assert.Equal(t, []string{"engineering"}, stored.Groups)
+
+	rr = f.scim(t, "DELETE", "/scim/v2/Groups/"+created.ID, token, "", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -0,0 +1,56 @@
+package rest
+
+import (
+	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/scim"
+	"net/http"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+func (s *ApiModule) toScimGroup(group *models.Group, users []*models.User) *scim.Group {
+	obj := &scim.Group{
+		Schemas:     []string{scim.SchemaGroup},
+		ID:          group.Id,
+		ExternalID:  group.ExternalId,
+		DisplayName: group.DisplayName,
+		Members:     []scim.Reference{},
+		Meta: &scim.Meta{
+			ResourceType: "Group",
+			Created:      group.CreatedAt.AsTime().Format(time.RFC3339),
+			LastModified: group.UpdatedAt.AsTime().Format(time.RFC3339),
+			Location:     s.scimLocation("Groups", group.Id),
+		},
+	}
+	for _, user := range users {
+		if slices.Contains(user.GroupIds, group.Id) {
+			obj.Members = append(obj.Members, scim.Reference{
+				Value:   user.Id,
+				Display: user.Email,
+				Ref:     s.scimLocation("Users", user.Id),
+			})
+		}
+	}
+	sort.Slice(obj.Members, func(i, j int) bool {
+		return obj.Members[i].Value < obj.Members[j].Value
+	})
+	return obj
+}
+
+func (s *ApiModule) handleScimGroupGet(w http.ResponseWriter, r *http.Request) {
+	if s.authenticateScim(w, r) == nil {
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	group, err := s.db.GetGroup(id)
+	if err != nil {
+		respondScimError(w, http.StatusNotFound, "", "Group not found")
+		return
+	}
+
+	respondScim(w, http.StatusOK, s.toScimGroup(group, s.db.ListUsers()))
+}
@@ -0,0 +1,37 @@
+package rest
+
+import (
+	"boivie/ubergang/server/scim"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScimGroupGet(t *testing.T) {
+	t.Run("returns group", func(t *testing.T) {
+		f, token := setupScimTest(t)
+		user := f.createScimUser(t, token)
+		created := f.createScimGroup(t, token, "engineering", user.ID)
+
+		group := &scim.Group{}
+		rr := f.scim(t, "GET", "/scim/v2/Groups/"+created.ID, token, "", group)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, created, group)
+
+		// Users list the groups that they are members of.
+		scimUser := &scim.User{}
+		f.scim(t, "GET", "/scim/v2/Users/"+user.ID, token, "", scimUser)
+		require.Len(t, scimUser.Groups, 1)
+		assert.Equal(t, created.ID, scimUser.Groups[0].Value)
+		assert.Equal(t, "engineering", scimUser.Groups[0].Display)
+	})
+
+	t.Run("returns not found", func(t *testing.T) {
+		f, token := setupScimTest(t)
+
+		rr := f.scim(t, "GET", "/scim/v2/Groups/missing", token, "", nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
@@ -0,0 +1,24 @@
+package rest
+
+import (
+	"net/http"
+	"sort"
+)
+
+func (s *ApiModule) handleScimGroupList(w http.ResponseWriter, r *http.Request) {
+	if s.authenticateScim(w, r) == nil {
+		return
+	}
+
+	groups := s.db.ListGroups()
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Id < groups[j].Id
+	})
+	users := s.db.ListUsers()
+	resources := make([]interface{}, 0, len(groups))
+	for _, group := range groups {
+		resources = append(resources, s.toScimGroup(group, users))
+	}
+
+	s.respondScimList(w, r, resources)
+}
@@ -0,0 +1,31 @@
+package rest
+
+import (
+	"boivie/ubergang/server/scim"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScimGroupList(t *testing.T) {
+	f, token := setupScimTest(t)
+	f.createScimGroup(t, token, "engineering")
+	sales := f.createScimGroup(t, token, "sales")
+
+	var resp struct {
+		scim.ListResponse
+		Resources []scim.Group `json:"Resources"`
+	}
+	rr := f.scim(t, "GET", "/scim/v2/Groups", token, "", &resp)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, 2, resp.TotalResults)
+
+	filter := url.Values{"filter": {`displayName eq "sales"`}}.Encode()
+	rr = f.scim(t, "GET", "/scim/v2/Groups?"+filter, token, "", &resp)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Len(t, resp.Resources, 1)
+	assert.Equal(t, sales.ID, resp.Resources[0].ID)
+}
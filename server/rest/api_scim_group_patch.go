@@ -0,0 +1,41 @@
+package rest
+
+import (
+	"boivie/ubergang/server/scim"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func (s *ApiModule) handleScimGroupPatch(w http.ResponseWriter, r *http.Request) {
+	actor := s.authenticateScim(w, r)
+	if actor == nil {
+		return
+	}
+
+	var req scim.PatchRequest
+	if parseScimRequest(w, r, &req) != nil {
+		return
+	}
+
+	group, err := s.db.GetGroup(mux.Vars(r)["id"])
+	if err != nil {
+		respondScimError(w, http.StatusNotFound, "", "Group not found")
+		return
+	}
+
+	resource, err := scim.ToMap(s.toScimGroup(group, s.db.ListUsers()))
+	if err == nil {
+		err = scim.ApplyPatch(resource, req.Operations)
+	}
+	after := &scim.Group{}
+	if err == nil {
+		err = scim.FromMap(resource, after)
+	}
+	if err != nil {
+		s.respondScimErr(w, err)
+		return
+	}
+
+	s.saveScimGroup(w, r, actor, group, after)
+}
@@ -0,0 +1,67 @@
+package rest
+
+import (
+	"boivie/ubergang/server/scim"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScimGroupPatch(t *testing.T) {
+	t.Run("adds and removes members", func(t *testing.T) {
+		f, token := setupScimTest(t)
+		user := f.createScimUser(t, token)
+		_, otherId := f.CreateUserGetId("other@example.com")
+		created := f.createScimGroup(t, token, "engineering", user.ID)
+
+		group := &scim.Group{}
+		rr := f.scim(t, "PATCH", "/scim/v2/Groups/"+created.ID, token, `{
+			"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+			"Operations": [
+				{"op": "add", "path": "members", "value": [{"value": "`+otherId+`"}]},
+				{"op": "remove", "path": "members[value eq \"`+user.ID+`\"]"}
+			]
+		}`, group)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		require.Len(t, group.Members, 1)
+		assert.Equal(t, otherId, group.Members[0].Value)
+
+		stored, err := f.Db.GetUserById(otherId)
+		require.NoError(t, err)
+		assert.Equal(t, []string{created.ID}, stored.GroupIds)
+		stored, err = f.Db.GetUserById(user.ID)
+		require.NoError(t, err)
+		assert.Empty(t, stored.GroupIds)
+
+		// Each membership change is recorded on the user.
+		records := f.scimAuditRecords("user.update")
+		require.Len(t, records, 3)
+		assert.ElementsMatch(t, []string{otherId, user.ID}, []string{records[0].TargetId, records[1].TargetId})
+		assert.Len(t, f.scimAuditRecords("group.update"), 1)
+	})
+
+	t.Run("renames group", func(t *testing.T) {
+		f, token := setupScimTest(t)
+		user := f.createScimUser(t, token)
+		created := f.createScimGroup(t, token, "engineering", user.ID)
+
+		rr := f.scim(t, "PATCH", "/scim/v2/Groups/"+created.ID, token, `{
+			"Operations": [{"op": "replace", "value": {"displayName": "developers"}}]
+		}`, nil)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+		stored, err := f.Db.GetUserById(user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{created.ID}, stored.GroupIds)
+		assert.Equal(t, []string{"developers"}, f.Auth.UserGroups(stored))
+	})
+
+	t.Run("returns not found", func(t *testing.T) {
+		f, token := setupScimTest(t)
+
+		rr := f.scim(t, "PATCH", "/scim/v2/Groups/missing", token, `{"Operations": []}`, nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
@@ -0,0 +1,67 @@
+package rest
+
+import (
+	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/scim"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// saveScimGroup stores `after` as the new state of `group`, renaming it and
+// updating its members as needed.
+func (s *ApiModule) saveScimGroup(w http.ResponseWriter, r *http.Request, actor *models.User, group *models.Group, after *scim.Group) {
+	name := strings.TrimSpace(after.DisplayName)
+	if name == "" {
+		respondScimError(w, http.StatusBadRequest, scim.ErrInvalidValue, "displayName is required")
+		return
+	}
+	if existing := s.findGroupByName(name); existing != nil && existing.Id != group.Id {
+		respondScimError(w, http.StatusConflict, scim.ErrUniqueness, "displayName is already in use")
+		return
+	}
+
+	before := group
+	err := s.setGroupMembers(r, actor, group.Id, groupMemberIds(after))
+	if err == nil {
+		err = s.db.UpdateGroup(group.Id, func(old *models.Group) (*models.Group, error) {
+			if old == nil {
+				return nil, errGroupNotFound
+			}
+			old.DisplayName = name
+			old.ExternalId = after.ExternalID
+			old.UpdatedAt = timestamppb.Now()
+			group = old
+			return old, nil
+		})
+	}
+	if err != nil {
+		s.respondScimErr(w, err)
+		return
+	}
+	s.audit(r, actor, nil, "group.update", group.Id, before, group)
+
+	respondScim(w, http.StatusOK, s.toScimGroup(group, s.db.ListUsers()))
+}
+
+func (s *ApiModule) handleScimGroupReplace(w http.ResponseWriter, r *http.Request) {
+	actor := s.authenticateScim(w, r)
+	if actor == nil {
+		return
+	}
+
+	var req scim.Group
+	if parseScimRequest(w, r, &req) != nil {
+		return
+	}
+
+	group, err := s.db.GetGroup(mux.Vars(r)["id"])
+	if err != nil {
+		respondScimError(w, http.StatusNotFound, "", "Group not found")
+		return
+	}
+
+	s.saveScimGroup(w, r, actor, group, &req)
+}
@@ -0,0 +1,51 @@
+package rest
+
+import (
+	"boivie/ubergang/server/scim"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScimGroupReplace(t *testing.T) {
+	t.Run("renames group and replaces members", func(t *testing.T) {
+		f, token := setupScimTest(t)
+		user := f.createScimUser(t, token)
+		_, adminId := f.CreateAdminGetId("other@example.com")
+		created := f.createScimGroup(t, token, "engineering", user.ID)
+
+		group := &scim.Group{}
+		rr := f.scim(t, "PUT", "/scim/v2/Groups/"+created.ID, token,
+			`{"displayName": "developers", "members": [{"value": "`+adminId+`"}]}`, group)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		assert.Equal(t, "developers", group.DisplayName)
+		require.Len(t, group.Members, 1)
+		assert.Equal(t, adminId, group.Members[0].Value)
+
+		stored, err := f.Db.GetUserById(user.ID)
+		require.NoError(t, err)
+		assert.Empty(t, stored.GroupIds)
+		stored, err = f.Db.GetUserById(adminId)
+		require.NoError(t, err)
+		assert.Equal(t, []string{created.ID}, stored.GroupIds)
+		assert.Equal(t, []string{"developers"}, f.Auth.UserGroups(stored))
+	})
+
+	t.Run("rejects name of other group", func(t *testing.T) {
+		f, token := setupScimTest(t)
+		f.createScimGroup(t, token, "sales")
+		created := f.createScimGroup(t, token, "engineering")
+
+		rr := f.scim(t, "PUT", "/scim/v2/Groups/"+created.ID, token, `{"displayName": "sales"}`, nil)
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+
+	t.Run("returns not found", func(t *testing.T) {
+		f, token := setupScimTest(t)
+
+		rr := f.scim(t, "PUT", "/scim/v2/Groups/missing", token, `{"displayName": "sales"}`, nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
@@ -0,0 +1,105 @@
+package rest
+
+import (
+	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/scim"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupScimTest returns a fixture and a SCIM access token of an admin.
+func setupScimTest(t *testing.T) (*Fixture, string) {
+	t.Helper()
+	f := CreateFixture(t)
+	_, adminId := f.CreateAdminGetId("admin@example.com")
+	_, token, err := f.Auth.CreateAccessToken(adminId, "Directory", []string{"scim"}, nil, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	return f, token
+}
+
+// scimAuditRecords returns the audit records of `action`, newest first.
+func (f *Fixture) scimAuditRecords(action string) []*models.AuditRecord {
+	return f.Db.ListAuditRecords(func(record *models.AuditRecord) bool {
+		return record.Action == action
+	}, 100)
+}
+
+// scim makes a SCIM request, and decodes the response into `res` if the
+// request succeeded.
+func (f *Fixture) scim(t *testing.T, method, url, token, body string, res interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, url, strings.NewReader(body))
+	req.Host = "test.example.com"
+	req.Header.Set("Content-Type", scim.ContentType)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rr := httptest.NewRecorder()
+	f.router.ServeHTTP(rr, req)
+	if rr.Code < 300 && res != nil {
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), res))
+	}
+	return rr
+}
+
+func scimErrorType(t *testing.T, rr *httptest.ResponseRecorder) string {
+	t.Helper()
+	var resp scim.ErrorResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	return resp.ScimType
+}
+
+func TestScimAuthentication(t *testing.T) {
+	t.Run("requires token", func(t *testing.T) {
+		f, _ := setupScimTest(t)
+
+		rr := f.scim(t, "GET", "/scim/v2/Users", "", "", nil)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Equal(t, scim.ContentType, rr.Header().Get("Content-Type"))
+	})
+
+	t.Run("accepts any case of the scheme", func(t *testing.T) {
+		f, token := setupScimTest(t)
+
+		req := httptest.NewRequest("GET", "/scim/v2/Users", nil)
+		req.Host = "test.example.com"
+		req.Header.Set("Authorization", "BEARER "+token)
+		rr := httptest.NewRecorder()
+		f.router.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	})
+
+	t.Run("rejects invalid token", func(t *testing.T) {
+		f, _ := setupScimTest(t)
+
+		rr := f.scim(t, "GET", "/scim/v2/Users", "ugt_invalid_token", "", nil)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+
+	t.Run("requires SCIM scope", func(t *testing.T) {
+		f, _ := setupScimTest(t)
+		_, adminId := f.CreateAdminGetId("other@example.com")
+		_, token, err := f.Auth.CreateAccessToken(adminId, "CLI", []string{"proxy"}, nil, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+
+		rr := f.scim(t, "GET", "/scim/v2/Users", token, "", nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("requires admin", func(t *testing.T) {
+		f, _ := setupScimTest(t)
+		_, userId := f.CreateUserGetId("user@example.com")
+		_, token, err := f.Auth.CreateAccessToken(userId, "Directory", []string{"scim"}, nil, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+
+		rr := f.scim(t, "GET", "/scim/v2/Users", token, "", nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+}
@@ -0,0 +1,49 @@
+package rest
+
+import (
+	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/scim"
+	"net/http"
+	"strings"
+)
+
+func (s *ApiModule) handleScimUserCreate(w http.ResponseWriter, r *http.Request) {
+	actor := s.authenticateScim(w, r)
+	if actor == nil {
+		return
+	}
+
+	var req scim.User
+	if parseScimRequest(w, r, &req) != nil {
+		return
+	}
+
+	email := strings.ToLower(strings.TrimSpace(scimEmail(nil, &req)))
+	if email == "" {
+		respondScimError(w, http.StatusBadRequest, scim.ErrInvalidValue, "userName is required")
+		return
+	}
+	if _, err := s.db.GetUserByEmail(email); err == nil {
+		respondScimError(w, http.StatusConflict, scim.ErrUniqueness, "userName is already in use")
+		return
+	}
+
+	created, _, err := s.auth.CreateUser(email, email, false, []string{})
+	if err != nil {
+		s.respondScimErr(w, err)
+		return
+	}
+	_, user, err := s.saveScimUser(created.Id, func(user *models.User) error {
+		return applyScimUser(user, nil, &req)
+	})
+	if err != nil {
+		_ = s.db.DeleteUser(created.Id)
+		s.respondScimErr(w, err)
+		return
+	}
+	s.log.Infof("Provisioned user %s through SCIM", user.Email)
+	s.audit(r, actor, nil, "user.create", user.Id, nil, user)
+
+	w.Header().Set("Location", s.scimLocation("Users", user.Id))
+	respondScim(w, http.StatusCreated, s.toScimUser(user, s.db.ListGroups()))
+}
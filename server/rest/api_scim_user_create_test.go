@@ -0,0 +1,86 @@
+package rest
+
+import (
+	"boivie/ubergang/server/scim"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const scimTestUser = `{
+	"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"],
+	"externalId": "00u1",
+	"userName": "Alice@Example.com",
+	"name": {"givenName": "Alice", "familyName": "Smith"},
+	"emails": [{"value": "alice@example.com", "type": "work", "primary": true}],
+	"active": true
+}`
+
+// createScimUser provisions the test user, and returns it.
+func (f *Fixture) createScimUser(t *testing.T, token string) *scim.User {
+	t.Helper()
+	user := &scim.User{}
+	rr := f.scim(t, "POST", "/scim/v2/Users", token, scimTestUser, user)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	return user
+}
+
+func TestScimUserCreate(t *testing.T) {
+	t.Run("creates user", func(t *testing.T) {
+		f, token := setupScimTest(t)
+
+		user := f.createScimUser(t, token)
+		assert.NotEmpty(t, user.ID)
+		assert.Equal(t, "00u1", user.ExternalID)
+		assert.Equal(t, "alice@example.com", user.UserName)
+		assert.Equal(t, "Alice Smith", user.DisplayName)
+		assert.True(t, bool(*user.Active))
+		assert.False(t, bool(user.Extension.IsAdmin))
+		assert.Equal(t, "https://test.example.com/scim/v2/Users/"+user.ID, user.Meta.Location)
+
+		stored, err := f.Db.GetUserByEmail("alice@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, stored.Id)
+		assert.Equal(t, "Alice Smith", stored.DisplayName)
+		assert.False(t, stored.IsDisabled)
+	})
+
+	t.Run("maps extension", func(t *testing.T) {
+		f, token := setupScimTest(t)
+
+		rr := f.scim(t, "POST", "/scim/v2/Users", token, `{
+			"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User", "urn:ietf:params:scim:schemas:extension:ubergang:2.0:User"],
+			"userName": "bob@example.com",
+			"displayName": "Bob",
+			"active": "False",
+			"urn:ietf:params:scim:schemas:extension:ubergang:2.0:User": {"isAdmin": true, "allowedHosts": ["App.example.com"]}
+		}`, nil)
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+		stored, err := f.Db.GetUserByEmail("bob@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, "Bob", stored.DisplayName)
+		assert.True(t, stored.IsAdmin)
+		assert.True(t, stored.IsDisabled)
+		assert.Equal(t, []string{"app.example.com"}, stored.AllowedHosts)
+	})
+
+	t.Run("rejects existing user name", func(t *testing.T) {
+		f, token := setupScimTest(t)
+		f.createScimUser(t, token)
+
+		rr := f.scim(t, "POST", "/scim/v2/Users", token, scimTestUser, nil)
+		assert.Equal(t, http.StatusConflict, rr.Code)
+		assert.Equal(t, scim.ErrUniqueness, scimErrorType(t, rr))
+	})
+
+	t.Run("requires user name", func(t *testing.T) {
+		f, token := setupScimTest(t)
+
+		rr := f.scim(t, "POST", "/scim/v2/Users", token, `{"displayName": "Nobody"}`, nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, scim.ErrInvalidValue, scimErrorType(t, rr))
+	})
+}
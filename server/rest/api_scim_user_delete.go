@@ -0,0 +1,28 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func (s *ApiModule) handleScimUserDelete(w http.ResponseWriter, r *http.Request) {
+	actor := s.authenticateScim(w, r)
+	if actor == nil {
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	before, err := s.db.GetUserById(id)
+	if err == nil {
+		err = s.db.DeleteUser(id)
+	}
+	if err != nil {
+		respondScimError(w, http.StatusNotFound, "", "User not found")
+		return
+	}
+	s.log.Infof("Deleted user %s through SCIM", id)
+	s.audit(r, actor, nil, "user.delete", id, before, nil)
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,26 @@
+package rest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScimUserDelete(t *testing.T) {
+	f, token := setupScimTest(t)
+	created := f.createScimUser(t, token)
+
+	rr := f.scim(t, "DELETE", "/scim/v2/Users/"+created.ID, token, "", nil)
+	require.Equal(t, http.StatusNoContent, rr.Code)
+
+	_, err := f.Db.GetUserById(created.ID)
+	assert.Error(t, err)
+	records := f.scimAuditRecords("user.delete")
+	require.Len(t, records, 1)
+	assert.Equal(t, created.ID, records[0].TargetId)
+
+	rr = f.scim(t, "DELETE", "/scim/v2/Users/"+created.ID, token, "", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
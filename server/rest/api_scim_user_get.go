@@ -0,0 +1,60 @@
+package rest
+
+import (
+	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/scim"
+	"net/http"
+	"slices"
+
+	"github.com/gorilla/mux"
+)
+
+func (s *ApiModule) toScimUser(user *models.User, groups []*models.Group) *scim.User {
+	active := scim.Bool(!user.IsDisabled)
+	obj := &scim.User{
+		Schemas:     []string{scim.SchemaUser, scim.SchemaUserExtension},
+		ID:          user.Id,
+		ExternalID:  user.ExternalId,
+		UserName:    user.Email,
+		DisplayName: user.DisplayName,
+		Name:        &scim.Name{Formatted: user.DisplayName},
+		Emails:      []scim.Email{{Value: user.Email, Type: "work", Primary: true}},
+		Active:      &active,
+		Extension: &scim.UserExtension{
+			IsAdmin:      scim.Bool(user.IsAdmin),
+			AllowedHosts: user.AllowedHosts,
+		},
+		Meta: &scim.Meta{
+			ResourceType: "User",
+			Location:     s.scimLocation("Users", user.Id),
+		},
+	}
+	if obj.Extension.AllowedHosts == nil {
+		obj.Extension.AllowedHosts = []string{}
+	}
+	for _, group := range groups {
+		if slices.Contains(user.GroupIds, group.Id) {
+			obj.Groups = append(obj.Groups, scim.Reference{
+				Value:   group.Id,
+				Display: group.DisplayName,
+				Ref:     s.scimLocation("Groups", group.Id),
+			})
+		}
+	}
+	return obj
+}
+
+func (s *ApiModule) handleScimUserGet(w http.ResponseWriter, r *http.Request) {
+	if s.authenticateScim(w, r) == nil {
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	user, err := s.db.GetUserById(id)
+	if err != nil {
+		respondScimError(w, http.StatusNotFound, "", "User not found")
+		return
+	}
+
+	respondScim(w, http.StatusOK, s.toScimUser(user, s.db.ListGroups()))
+}
@@ -0,0 +1,29 @@
+package rest
+
+import (
+	"boivie/ubergang/server/scim"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScimUserGet(t *testing.T) {
+	t.Run("returns user", func(t *testing.T) {
+		f, token := setupScimTest(t)
+		created := f.createScimUser(t, token)
+
+		user := &scim.User{}
+		rr := f.scim(t, "GET", "/scim/v2/Users/"+created.ID, token, "", user)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, created, user)
+	})
+
+	t.Run("returns not found", func(t *testing.T) {
+		f, token := setupScimTest(t)
+
+		rr := f.scim(t, "GET", "/scim/v2/Users/missing", token, "", nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
@@ -0,0 +1,24 @@
+package rest
+
+import (
+	"net/http"
+	"sort"
+)
+
+func (s *ApiModule) handleScimUserList(w http.ResponseWriter, r *http.Request) {
+	if s.authenticateScim(w, r) == nil {
+		return
+	}
+
+	users := s.db.ListUsers()
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Id < users[j].Id
+	})
+	groups := s.db.ListGroups()
+	resources := make([]interface{}, 0, len(users))
+	for _, user := range users {
+		resources = append(resources, s.toScimUser(user, groups))
+	}
+
+	s.respondScimList(w, r, resources)
+}
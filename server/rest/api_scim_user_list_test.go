@@ -0,0 +1,66 @@
+package rest
+
+import (
+	"boivie/ubergang/server/scim"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (f *Fixture) listScimUsers(t *testing.T, token, query string) []scim.User {
+	t.Helper()
+	var resp struct {
+		scim.ListResponse
+		Resources []scim.User `json:"Resources"`
+	}
+	rr := f.scim(t, "GET", "/scim/v2/Users?"+query, token, "", &resp)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Equal(t, len(resp.Resources), resp.ItemsPerPage)
+	return resp.Resources
+}
+
+func TestScimUserList(t *testing.T) {
+	t.Run("lists users", func(t *testing.T) {
+		f, token := setupScimTest(t)
+		f.createScimUser(t, token)
+
+		users := f.listScimUsers(t, token, "")
+		assert.Len(t, users, 2)
+	})
+
+	t.Run("filters users", func(t *testing.T) {
+		f, token := setupScimTest(t)
+		created := f.createScimUser(t, token)
+
+		filter := url.Values{"filter": {`userName eq "ALICE@example.com"`}}.Encode()
+		users := f.listScimUsers(t, token, filter)
+		require.Len(t, users, 1)
+		assert.Equal(t, created.ID, users[0].ID)
+
+		filter = url.Values{"filter": {`externalId eq "unknown"`}}.Encode()
+		assert.Empty(t, f.listScimUsers(t, token, filter))
+	})
+
+	t.Run("paginates", func(t *testing.T) {
+		f, token := setupScimTest(t)
+		f.createScimUser(t, token)
+
+		var resp scim.ListResponse
+		rr := f.scim(t, "GET", "/scim/v2/Users?startIndex=2&count=1", token, "", &resp)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, 2, resp.TotalResults)
+		assert.Equal(t, 2, resp.StartIndex)
+		assert.Equal(t, 1, resp.ItemsPerPage)
+	})
+
+	t.Run("rejects invalid filter", func(t *testing.T) {
+		f, token := setupScimTest(t)
+
+		rr := f.scim(t, "GET", "/scim/v2/Users?filter="+url.QueryEscape(`userName eq`), token, "", nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, scim.ErrInvalidFilter, scimErrorType(t, rr))
+	})
+}
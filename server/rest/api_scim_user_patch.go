@@ -0,0 +1,50 @@
+package rest
+
+import (
+	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/scim"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func (s *ApiModule) handleScimUserPatch(w http.ResponseWriter, r *http.Request) {
+	actor := s.authenticateScim(w, r)
+	if actor == nil {
+		return
+	}
+
+	var req scim.PatchRequest
+	if parseScimRequest(w, r, &req) != nil {
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	groups := s.db.ListGroups()
+	before, user, err := s.saveScimUser(id, func(user *models.User) error {
+		before := s.toScimUser(user, groups)
+		resource, err := scim.ToMap(before)
+		if err != nil {
+			return err
+		}
+		if err := scim.ApplyPatch(resource, req.Operations); err != nil {
+			return err
+		}
+		after := &scim.User{}
+		if err := scim.FromMap(resource, after); err != nil {
+			return err
+		}
+		return applyScimUser(user, before, after)
+	})
+	if errors.Is(err, errUserNotFound) {
+		respondScimError(w, http.StatusNotFound, "", "User not found")
+		return
+	} else if err != nil {
+		s.respondScimErr(w, err)
+		return
+	}
+	s.audit(r, actor, nil, "user.update", id, before, user)
+
+	respondScim(w, http.StatusOK, s.toScimUser(user, groups))
+}
@@ -0,0 +1,95 @@
+package rest
+
+import (
+	"boivie/ubergang/server/scim"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScimUserPatch(t *testing.T) {
+	t.Run("deactivates user", func(t *testing.T) {
+		f, token := setupScimTest(t)
+		created := f.createScimUser(t, token)
+		session, err := f.Auth.CreateSession(created.ID, "user-agent", "remote-addr")
+		require.NoError(t, err)
+		cookie := f.Session.CreateSessionCookie(session)
+		require.Equal(t, created.ID, f.getUser(cookie, "me").ID)
+
+		user := &scim.User{}
+		rr := f.scim(t, "PATCH", "/scim/v2/Users/"+created.ID, token, `{
+			"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+			"Operations": [{"op": "replace", "value": {"active": false}}]
+		}`, user)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		assert.False(t, bool(*user.Active))
+
+		// The admin that owns the token made the change.
+		records := f.scimAuditRecords("user.update")
+		require.Len(t, records, 1)
+		assert.Equal(t, created.ID, records[0].TargetId)
+		assert.Equal(t, "admin@example.com", records[0].ActorName)
+		require.Len(t, records[0].Changes, 1)
+		assert.Equal(t, "is_disabled", records[0].Changes[0].Field)
+
+		// The user's sessions no longer work.
+		rr = f.request("GET", "/api/user/me", nil, cookie, nil)
+		assert.NotEqual(t, http.StatusOK, rr.Code)
+		_, err = f.Auth.CreateSession(created.ID, "user-agent", "remote-addr")
+		assert.Error(t, err)
+	})
+
+	t.Run("updates e-mail address", func(t *testing.T) {
+		f, token := setupScimTest(t)
+		created := f.createScimUser(t, token)
+
+		user := &scim.User{}
+		rr := f.scim(t, "PATCH", "/scim/v2/Users/"+created.ID, token, `{
+			"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+			"Operations": [
+				{"op": "Replace", "path": "emails[type eq \"work\"].value", "value": "alice@corp.example.com"},
+				{"op": "Replace", "path": "displayName", "value": "Alice S."}
+			]
+		}`, user)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		assert.Equal(t, "alice@corp.example.com", user.UserName)
+		assert.Equal(t, "Alice S.", user.DisplayName)
+	})
+
+	t.Run("updates extension", func(t *testing.T) {
+		f, token := setupScimTest(t)
+		created := f.createScimUser(t, token)
+
+		rr := f.scim(t, "PATCH", "/scim/v2/Users/"+created.ID, token, `{
+			"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+			"Operations": [
+				{"op": "add", "path": "urn:ietf:params:scim:schemas:extension:ubergang:2.0:User:allowedHosts", "value": ["app.example.com"]}
+			]
+		}`, nil)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+		stored, err := f.Db.GetUserById(created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"app.example.com"}, stored.AllowedHosts)
+		assert.False(t, stored.IsAdmin)
+	})
+
+	t.Run("rejects invalid operation", func(t *testing.T) {
+		f, token := setupScimTest(t)
+		created := f.createScimUser(t, token)
+
+		rr := f.scim(t, "PATCH", "/scim/v2/Users/"+created.ID, token, `{
+			"Operations": [{"op": "remove", "path": "emails[type eq"}]
+		}`, nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("returns not found", func(t *testing.T) {
+		f, token := setupScimTest(t)
+
+		rr := f.scim(t, "PATCH", "/scim/v2/Users/missing", token, `{"Operations": []}`, nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
@@ -0,0 +1,37 @@
+package rest
+
+import (
+	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/scim"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func (s *ApiModule) handleScimUserReplace(w http.ResponseWriter, r *http.Request) {
+	actor := s.authenticateScim(w, r)
+	if actor == nil {
+		return
+	}
+
+	var req scim.User
+	if parseScimRequest(w, r, &req) != nil {
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	before, user, err := s.saveScimUser(id, func(user *models.User) error {
+		return applyScimUser(user, nil, &req)
+	})
+	if errors.Is(err, errUserNotFound) {
+		respondScimError(w, http.StatusNotFound, "", "User not found")
+		return
+	} else if err != nil {
+		s.respondScimErr(w, err)
+		return
+	}
+	s.audit(r, actor, nil, "user.update", id, before, user)
+
+	respondScim(w, http.StatusOK, s.toScimUser(user, s.db.ListGroups()))
+}
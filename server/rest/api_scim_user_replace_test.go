@@ -0,0 +1,66 @@
+package rest
+
+import (
+	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/scim"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScimUserReplace(t *testing.T) {
+	t.Run("replaces user", func(t *testing.T) {
+		f, token := setupScimTest(t)
+		created := f.createScimUser(t, token)
+
+		user := &scim.User{}
+		rr := f.scim(t, "PUT", "/scim/v2/Users/"+created.ID, token, `{
+			"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"],
+			"userName": "alice.smith@example.com",
+			"displayName": "Alice Smith",
+			"active": false
+		}`, user)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		assert.Equal(t, "alice.smith@example.com", user.UserName)
+		assert.Empty(t, user.ExternalID)
+		assert.False(t, bool(*user.Active))
+
+		_, err := f.Db.GetUserByEmail("alice@example.com")
+		assert.Error(t, err)
+		stored, err := f.Db.GetUserByEmail("alice.smith@example.com")
+		require.NoError(t, err)
+		assert.True(t, stored.IsDisabled)
+	})
+
+	t.Run("keeps extension if omitted", func(t *testing.T) {
+		f, token := setupScimTest(t)
+		created := f.createScimUser(t, token)
+		require.NoError(t, f.Db.UpdateUser(created.ID, func(old *models.User) (*models.User, error) {
+			old.IsAdmin = true
+			return old, nil
+		}))
+
+		rr := f.scim(t, "PUT", "/scim/v2/Users/"+created.ID, token, scimTestUser, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+		stored, err := f.Db.GetUserById(created.ID)
+		require.NoError(t, err)
+		assert.True(t, stored.IsAdmin)
+	})
+
+	t.Run("rejects user name of other user", func(t *testing.T) {
+		f, token := setupScimTest(t)
+		created := f.createScimUser(t, token)
+
+		rr := f.scim(t, "PUT", "/scim/v2/Users/"+created.ID, token, `{"userName": "admin@example.com"}`, nil)
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+
+	t.Run("returns not found", func(t *testing.T) {
+		f, token := setupScimTest(t)
+
+		rr := f.scim(t, "PUT", "/scim/v2/Users/missing", token, scimTestUser, nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
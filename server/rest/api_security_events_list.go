@@ -0,0 +1,93 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/security"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const defaultSecurityEvents = 100
+
+func ToApiSecurityEvent(event *models.SecurityEvent) api.ApiSecurityEvent {
+	return api.ApiSecurityEvent{
+		ID:        event.Id,
+		Timestamp: event.Timestamp.AsTime().Format(time.RFC3339Nano),
+		Source:    event.Source,
+		Ip:        event.Ip,
+		Principal: event.Principal,
+		Reason:    event.Reason,
+	}
+}
+
+// listSecurityEvents returns the events matching the filters in the query,
+// newest first. All filters are optional.
+func (s *ApiModule) listSecurityEvents(w http.ResponseWriter, r *http.Request) ([]*models.SecurityEvent, bool) {
+	user, _, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return nil, false
+	}
+
+	if !user.IsAdmin {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return nil, false
+	}
+
+	q := r.URL.Query()
+	source := q.Get("source")
+	ip := q.Get("ip")
+	principal := q.Get("principal")
+	since, err := parseTime(q.Get("since"))
+	if err != nil {
+		http.Error(w, "Invalid since", http.StatusBadRequest)
+		return nil, false
+	}
+	limit := defaultSecurityEvents
+	if v := q.Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return nil, false
+		}
+		limit = min(limit, security.MaxEvents)
+	}
+
+	return s.db.ListSecurityEvents(func(event *models.SecurityEvent) bool {
+		return (source == "" || event.Source == source) &&
+			(ip == "" || event.Ip == ip) &&
+			(principal == "" || event.Principal == principal) &&
+			(since.IsZero() || !event.Timestamp.AsTime().Before(since))
+	}, limit), true
+}
+
+func (s *ApiModule) handleSecurityEventsList(w http.ResponseWriter, r *http.Request) {
+	events, ok := s.listSecurityEvents(w, r)
+	if !ok {
+		return
+	}
+
+	ret := make([]api.ApiSecurityEvent, 0, len(events))
+	for _, event := range events {
+		ret = append(ret, ToApiSecurityEvent(event))
+	}
+	jsonify(w, api.ApiListSecurityEventsResponse{Events: ret})
+}
+
+// handleSecurityEventsFail2ban exports events as a log file that fail2ban can
+// parse, oldest first.
+func (s *ApiModule) handleSecurityEventsFail2ban(w http.ResponseWriter, r *http.Request) {
+	events, ok := s.listSecurityEvents(w, r)
+	if !ok {
+		return
+	}
+
+	slices.Reverse(events)
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	for _, event := range events {
+		fmt.Fprintln(w, security.Fail2ban(event))
+	}
+}
@@ -0,0 +1,106 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func (f *Fixture) listSecurityEvents(t *testing.T, cookie *http.Cookie, query string) []api.ApiSecurityEvent {
+	t.Helper()
+	resp := &api.ApiListSecurityEventsResponse{}
+	rr := f.request("GET", "/api/security-events"+query, nil, cookie, resp)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("list security events failed with status %d: %s", rr.Code, rr.Body.String())
+	}
+	return resp.Events
+}
+
+// forgeCookie returns a session cookie for the same session as `cookie`, but
+// with the wrong secret.
+func forgeCookie(cookie *http.Cookie) *http.Cookie {
+	id, _, _ := strings.Cut(cookie.Value, ":")
+	return &http.Cookie{Name: cookie.Name, Value: id + ":forged"}
+}
+
+func TestSecurityEventsList(t *testing.T) {
+	t.Run("records invalid session cookies", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+
+		rr := f.request("GET", "/api/user/me", nil, forgeCookie(cookie), nil)
+		if rr.Code != http.StatusForbidden {
+			t.Fatalf("Expected status %d, got %d", http.StatusForbidden, rr.Code)
+		}
+		// Not having a cookie at all, or one of a session that has been
+		// deleted, is expected.
+		f.request("GET", "/api/user/me", nil, nil, nil)
+		rr = f.request("GET", "/api/user/me", nil, &http.Cookie{Name: "__ug_sess", Value: "unknown:secret"}, nil)
+		if rr.Code != http.StatusForbidden {
+			t.Fatalf("Expected status %d, got %d", http.StatusForbidden, rr.Code)
+		}
+
+		events := f.listSecurityEvents(t, cookie, "")
+		if len(events) != 1 {
+			t.Fatalf("Expected 1 event, got %+v", events)
+		}
+		if events[0].Source != "http" || !strings.HasPrefix(events[0].Reason, "invalid session cookie") {
+			t.Errorf("Unexpected event %+v", events[0])
+		}
+	})
+
+	t.Run("filters events", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+		f.request("GET", "/api/user/me", nil, forgeCookie(cookie), nil)
+
+		if events := f.listSecurityEvents(t, cookie, "?source=http"); len(events) != 1 {
+			t.Errorf("Expected 1 event, got %d", len(events))
+		}
+		if events := f.listSecurityEvents(t, cookie, "?source=ssh"); len(events) != 0 {
+			t.Errorf("Expected no events, got %d", len(events))
+		}
+		rr := f.request("GET", "/api/security-events?limit=0", nil, cookie, nil)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, rr.Code)
+		}
+	})
+
+	t.Run("exports for fail2ban", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+		for i := 0; i < 2; i++ {
+			f.request("GET", "/api/user/me", nil, forgeCookie(cookie), nil)
+		}
+
+		rr := f.request("GET", "/api/security-events/fail2ban", nil, cookie, nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("request failed with status %d: %s", rr.Code, rr.Body.String())
+		}
+		if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+			t.Errorf("Unexpected content type %q", ct)
+		}
+		lines := strings.Split(strings.TrimSpace(rr.Body.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("Expected 2 lines, got %q", rr.Body.String())
+		}
+		for _, line := range lines {
+			if !strings.Contains(line, " ubergang: authentication failure; source=http ip=") {
+				t.Errorf("Unexpected line %q", line)
+			}
+		}
+	})
+
+	t.Run("requires admin", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("user@example.com")
+
+		for _, path := range []string{"/api/security-events", "/api/security-events/fail2ban"} {
+			rr := f.request("GET", path, nil, cookie, nil)
+			if rr.Code != http.StatusForbidden {
+				t.Errorf("%s: expected status %d, got %d", path, http.StatusForbidden, rr.Code)
+			}
+		}
+	})
+}
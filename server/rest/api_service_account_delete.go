@@ -0,0 +1,38 @@
+package rest
+
+import (
+	"boivie/ubergang/server/models"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func (s *ApiModule) handleServiceAccountDelete(w http.ResponseWriter, r *http.Request) {
+	user, session, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	if !user.IsAdmin {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+
+	var before *models.ServiceAccount
+	err = s.db.UpdateServiceAccount(id, func(old *models.ServiceAccount) (*models.ServiceAccount, error) {
+		before = old
+		return nil, nil
+	})
+
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if before != nil {
+		s.audit(r, user, session, "service-account.delete", id, before, nil)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,27 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteServiceAccount(t *testing.T) {
+	f, cookie := setupServiceAccountTest(t)
+	sshKeys := []string{generateAuthorizedKey(t)}
+	require.Equal(t, http.StatusOK, f.updateServiceAccount(cookie, "backup", &api.ApiUpdateServiceAccountRequest{
+		SshAuthorizedKeys: &sshKeys,
+	}))
+	stored, err := f.Db.GetServiceAccount("backup")
+	require.NoError(t, err)
+
+	rr := f.request("DELETE", "/api/service-account/backup", nil, cookie, nil)
+	require.Equal(t, http.StatusNoContent, rr.Code)
+
+	assert.Nil(t, f.getServiceAccount(cookie, "backup"))
+	_, err = f.Db.GetServiceAccountBySshFingerprint(stored.SshKeys[0].Sha256Fingerprint)
+	assert.Error(t, err)
+}
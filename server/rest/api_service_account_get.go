@@ -0,0 +1,60 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/models"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+func ToApiServiceAccount(account *models.ServiceAccount) api.ApiServiceAccount {
+	obj := api.ApiServiceAccount{
+		ID:                account.Id,
+		Name:              account.Name,
+		Description:       account.Description,
+		AllowedHosts:      account.AllowedHosts,
+		HasClientSecret:   account.HashedClientSecret != "",
+		PublicKey:         account.PublicKey,
+		MqttProfileId:     account.MqttProfileId,
+		MqttValues:        account.MqttValues,
+		SshAuthorizedKeys: make([]string, 0),
+		CreatedAt:         account.CreatedAt.AsTime().Format(time.RFC3339),
+		UpdatedAt:         account.UpdatedAt.AsTime().Format(time.RFC3339),
+		Disabled:          account.Disabled,
+	}
+	if obj.AllowedHosts == nil {
+		obj.AllowedHosts = []string{}
+	}
+	if obj.MqttValues == nil {
+		obj.MqttValues = map[string]string{}
+	}
+	for _, key := range account.SshKeys {
+		obj.SshAuthorizedKeys = append(obj.SshAuthorizedKeys, key.PublicKey)
+	}
+	if account.LastUsedAt != nil {
+		obj.LastUsedAt = account.LastUsedAt.AsTime().Format(time.RFC3339)
+	}
+	return obj
+}
+
+func (s *ApiModule) handleServiceAccountGet(w http.ResponseWriter, r *http.Request) {
+	user, _, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	if !user.IsAdmin {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	account, err := s.db.GetServiceAccount(mux.Vars(r)["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	jsonify(w, ToApiServiceAccount(account))
+}
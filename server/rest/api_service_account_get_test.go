@@ -0,0 +1,29 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetServiceAccount(t *testing.T) {
+	t.Run("returns service account", func(t *testing.T) {
+		f, cookie := setupServiceAccountTest(t)
+		require.Equal(t, http.StatusOK, f.updateServiceAccount(cookie, "backup", &api.ApiUpdateServiceAccountRequest{}))
+
+		account := f.getServiceAccount(cookie, "backup")
+		require.NotNil(t, account)
+		assert.Equal(t, "backup", account.ID)
+		assert.Empty(t, account.LastUsedAt)
+	})
+
+	t.Run("returns not found", func(t *testing.T) {
+		f, cookie := setupServiceAccountTest(t)
+
+		rr := f.request("GET", "/api/service-account/missing", nil, cookie, nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
@@ -0,0 +1,31 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"sort"
+)
+
+func (s *ApiModule) handleServiceAccountList(w http.ResponseWriter, r *http.Request) {
+	user, _, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	if !user.IsAdmin {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	accounts := make([]api.ApiServiceAccount, 0)
+	for _, account := range s.db.ListServiceAccounts() {
+		accounts = append(accounts, ToApiServiceAccount(account))
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].ID < accounts[j].ID
+	})
+
+	jsonify(w, api.ApiListServiceAccountsResponse{
+		ServiceAccounts: accounts,
+	})
+}
@@ -0,0 +1,23 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListServiceAccounts(t *testing.T) {
+	f, cookie := setupServiceAccountTest(t)
+	require.Equal(t, http.StatusOK, f.updateServiceAccount(cookie, "b", &api.ApiUpdateServiceAccountRequest{}))
+	require.Equal(t, http.StatusOK, f.updateServiceAccount(cookie, "a", &api.ApiUpdateServiceAccountRequest{}))
+
+	resp := &api.ApiListServiceAccountsResponse{}
+	rr := f.request("GET", "/api/service-account", nil, cookie, resp)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Len(t, resp.ServiceAccounts, 2)
+	assert.Equal(t, "a", resp.ServiceAccounts[0].ID)
+	assert.Equal(t, "b", resp.ServiceAccounts[1].ID)
+}
@@ -0,0 +1,35 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func (s *ApiModule) handleServiceAccountSecret(w http.ResponseWriter, r *http.Request) {
+	user, session, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	if !user.IsAdmin {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	before, _ := s.db.GetServiceAccount(id)
+	secret, err := s.auth.SetServiceAccountSecret(id)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	after, _ := s.db.GetServiceAccount(id)
+	s.audit(r, user, session, "service-account.secret", id, before, after)
+
+	jsonify(w, api.ApiCreateServiceAccountSecretResponse{
+		ClientId:     id,
+		ClientSecret: secret,
+	})
+}
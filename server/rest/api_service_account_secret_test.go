@@ -0,0 +1,56 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (f *Fixture) createServiceAccountSecret(t *testing.T, cookie *http.Cookie, id string) *api.ApiCreateServiceAccountSecretResponse {
+	t.Helper()
+	resp := &api.ApiCreateServiceAccountSecretResponse{}
+	rr := f.request("POST", "/api/service-account/"+id+"/secret", nil, cookie, resp)
+	require.Equal(t, http.StatusOK, rr.Code)
+	return resp
+}
+
+func TestCreateServiceAccountSecret(t *testing.T) {
+	t.Run("creates secret", func(t *testing.T) {
+		f, cookie := setupServiceAccountTest(t)
+		require.Equal(t, http.StatusOK, f.updateServiceAccount(cookie, "backup", &api.ApiUpdateServiceAccountRequest{}))
+
+		resp := f.createServiceAccountSecret(t, cookie, "backup")
+		assert.Equal(t, "backup", resp.ClientId)
+		assert.NotEmpty(t, resp.ClientSecret)
+
+		account := f.getServiceAccount(cookie, "backup")
+		require.NotNil(t, account)
+		assert.True(t, account.HasClientSecret)
+
+		_, err := f.Auth.AuthenticateServiceAccount("backup", resp.ClientSecret)
+		assert.NoError(t, err)
+	})
+
+	t.Run("rotating invalidates old secret", func(t *testing.T) {
+		f, cookie := setupServiceAccountTest(t)
+		require.Equal(t, http.StatusOK, f.updateServiceAccount(cookie, "backup", &api.ApiUpdateServiceAccountRequest{}))
+
+		first := f.createServiceAccountSecret(t, cookie, "backup")
+		second := f.createServiceAccountSecret(t, cookie, "backup")
+
+		_, err := f.Auth.AuthenticateServiceAccount("backup", first.ClientSecret)
+		assert.Error(t, err)
+		_, err = f.Auth.AuthenticateServiceAccount("backup", second.ClientSecret)
+		assert.NoError(t, err)
+	})
+
+	t.Run("unknown service account", func(t *testing.T) {
+		f, cookie := setupServiceAccountTest(t)
+
+		rr := f.request("POST", "/api/service-account/missing/secret", nil, cookie, nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
@@ -0,0 +1,132 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/auth"
+	"boivie/ubergang/server/models"
+	"crypto/sha256"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"golang.org/x/crypto/ssh"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func toServiceAccountSshKeys(authorizedKeys []string) ([]*models.ServiceAccountSshKey, error) {
+	ret := make([]*models.ServiceAccountSshKey, 0)
+	for _, authorizedKey := range authorizedKeys {
+		authorizedKey = strings.TrimSpace(authorizedKey)
+		parsed, _, _, _, err := ssh.ParseAuthorizedKey([]byte(authorizedKey))
+		if err != nil {
+			return nil, err
+		}
+		fingerprint := sha256.Sum256(parsed.Marshal())
+		ret = append(ret, &models.ServiceAccountSshKey{
+			PublicKey:         authorizedKey,
+			Sha256Fingerprint: fingerprint[:],
+		})
+	}
+	return ret, nil
+}
+
+func (s *ApiModule) handleServiceAccountUpdate(w http.ResponseWriter, r *http.Request) {
+	user, session, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	if !user.IsAdmin {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	var req api.ApiUpdateServiceAccountRequest
+	err = parseJsonRequest(w, r, &req)
+	if err != nil {
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		http.Error(w, "ID is required", http.StatusBadRequest)
+		return
+	}
+
+	if req.PublicKey != nil && *req.PublicKey != "" {
+		if _, err := auth.ParsePublicKey(*req.PublicKey); err != nil {
+			http.Error(w, "Invalid public key", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var sshKeys []*models.ServiceAccountSshKey
+	if req.SshAuthorizedKeys != nil {
+		sshKeys, err = toServiceAccountSshKeys(*req.SshAuthorizedKeys)
+		if err != nil {
+			http.Error(w, "Invalid SSH key", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if req.MqttProfileId != nil && *req.MqttProfileId != "" {
+		if _, err := s.db.GetMqttProfile(*req.MqttProfileId); err != nil {
+			http.Error(w, "Unknown MQTT profile", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var before, after *models.ServiceAccount
+	err = s.db.UpdateServiceAccount(id, func(old *models.ServiceAccount) (*models.ServiceAccount, error) {
+		before = proto.Clone(old).(*models.ServiceAccount)
+		now := time.Now()
+		if old == nil {
+			old = &models.ServiceAccount{
+				Id:        id,
+				Name:      id,
+				CreatedAt: timestamppb.New(now),
+			}
+		}
+		if req.Name != nil {
+			old.Name = *req.Name
+		}
+		if req.Description != nil {
+			old.Description = *req.Description
+		}
+		if req.AllowedHosts != nil {
+			old.AllowedHosts = make([]string, 0)
+			for _, host := range *req.AllowedHosts {
+				old.AllowedHosts = append(old.AllowedHosts, strings.ToLower(strings.TrimSpace(host)))
+			}
+		}
+		if req.PublicKey != nil {
+			old.PublicKey = *req.PublicKey
+		}
+		if req.MqttProfileId != nil {
+			old.MqttProfileId = *req.MqttProfileId
+		}
+		if req.MqttValues != nil {
+			old.MqttValues = *req.MqttValues
+		}
+		if req.SshAuthorizedKeys != nil {
+			old.SshKeys = sshKeys
+		}
+		if req.Disabled != nil {
+			old.Disabled = *req.Disabled
+		}
+		old.UpdatedAt = timestamppb.New(now)
+		after = old
+		return old, nil
+	})
+
+	if err != nil {
+		s.log.Warnf("Failed to update service account %s: %v", id, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.audit(r, user, session, "service-account.update", id, before, after)
+
+	jsonify(w, api.ApiUpdateServiceAccountResponse{})
+}
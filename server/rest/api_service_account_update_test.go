@@ -0,0 +1,131 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func setupServiceAccountTest(t *testing.T) (*Fixture, *http.Cookie) {
+	t.Helper()
+	f := CreateFixture(t)
+	cookie, _ := f.CreateAdmin("admin@example.com")
+	return f, cookie
+}
+
+func (f *Fixture) updateServiceAccount(cookie *http.Cookie, id string, req *api.ApiUpdateServiceAccountRequest) int {
+	rr := f.request("POST", "/api/service-account/"+id, req, cookie, &api.ApiUpdateServiceAccountResponse{})
+	return rr.Code
+}
+
+func (f *Fixture) getServiceAccount(cookie *http.Cookie, id string) *api.ApiServiceAccount {
+	resp := &api.ApiServiceAccount{}
+	rr := f.request("GET", "/api/service-account/"+id, nil, cookie, resp)
+	if rr.Code != http.StatusOK {
+		return nil
+	}
+	return resp
+}
+
+func generateServiceAccountKey(t *testing.T) (*ecdsa.PrivateKey, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	return key, string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func generateAuthorizedKey(t *testing.T) string {
+	t.Helper()
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	sshPub, err := ssh.NewPublicKey(pub)
+	require.NoError(t, err)
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub)))
+}
+
+func TestUpdateServiceAccount(t *testing.T) {
+	t.Run("creates service account", func(t *testing.T) {
+		f, cookie := setupServiceAccountTest(t)
+
+		name := "Backup job"
+		hosts := []string{"Backup.Example.com"}
+		code := f.updateServiceAccount(cookie, "backup", &api.ApiUpdateServiceAccountRequest{
+			Name:         &name,
+			AllowedHosts: &hosts,
+		})
+		require.Equal(t, http.StatusOK, code)
+
+		account := f.getServiceAccount(cookie, "backup")
+		require.NotNil(t, account)
+		assert.Equal(t, "Backup job", account.Name)
+		assert.Equal(t, []string{"backup.example.com"}, account.AllowedHosts)
+		assert.False(t, account.HasClientSecret)
+		assert.NotEmpty(t, account.CreatedAt)
+	})
+
+	t.Run("updates keys", func(t *testing.T) {
+		f, cookie := setupServiceAccountTest(t)
+		require.Equal(t, http.StatusOK, f.updateServiceAccount(cookie, "backup", &api.ApiUpdateServiceAccountRequest{}))
+
+		_, publicKey := generateServiceAccountKey(t)
+		sshKeys := []string{generateAuthorizedKey(t)}
+		code := f.updateServiceAccount(cookie, "backup", &api.ApiUpdateServiceAccountRequest{
+			PublicKey:         &publicKey,
+			SshAuthorizedKeys: &sshKeys,
+		})
+		require.Equal(t, http.StatusOK, code)
+
+		account := f.getServiceAccount(cookie, "backup")
+		require.NotNil(t, account)
+		assert.Equal(t, publicKey, account.PublicKey)
+		assert.Equal(t, sshKeys, account.SshAuthorizedKeys)
+
+		stored, err := f.Db.GetServiceAccount("backup")
+		require.NoError(t, err)
+		found, err := f.Db.GetServiceAccountBySshFingerprint(stored.SshKeys[0].Sha256Fingerprint)
+		require.NoError(t, err)
+		assert.Equal(t, "backup", found.Id)
+	})
+
+	t.Run("rejects invalid public key", func(t *testing.T) {
+		f, cookie := setupServiceAccountTest(t)
+
+		publicKey := "not a key"
+		code := f.updateServiceAccount(cookie, "backup", &api.ApiUpdateServiceAccountRequest{
+			PublicKey: &publicKey,
+		})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Nil(t, f.getServiceAccount(cookie, "backup"))
+	})
+
+	t.Run("rejects unknown MQTT profile", func(t *testing.T) {
+		f, cookie := setupServiceAccountTest(t)
+
+		profile := "missing"
+		code := f.updateServiceAccount(cookie, "backup", &api.ApiUpdateServiceAccountRequest{
+			MqttProfileId: &profile,
+		})
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("requires admin", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("user@example.com")
+
+		code := f.updateServiceAccount(cookie, "backup", &api.ApiUpdateServiceAccountRequest{})
+		assert.Equal(t, http.StatusForbidden, code)
+	})
+}
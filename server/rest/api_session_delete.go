@@ -7,7 +7,7 @@ import (
 )
 
 func (s *ApiModule) handleSessionDelete(w http.ResponseWriter, r *http.Request) {
-	user, _, err := s.session.GetAndValidate(w, r)
+	user, session, err := s.session.GetAndValidate(w, r)
 	if err != nil {
 		return
 	}
@@ -37,6 +37,7 @@ func (s *ApiModule) handleSessionDelete(w http.ResponseWriter, r *http.Request)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	s.audit(r, user, session, "session.delete", sessionId, sessionToDelete, nil)
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -0,0 +1,115 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/wa"
+	"net/http"
+)
+
+func ToApiSessionPolicy(p *models.SessionPolicy) api.ApiSessionPolicy {
+	if p == nil {
+		return api.ApiSessionPolicy{}
+	}
+	return api.ApiSessionPolicy{
+		AbsoluteLifetimeSeconds: int64(p.AbsoluteLifetime.AsDuration().Seconds()),
+		IdleTimeoutSeconds:      int64(p.IdleTimeout.AsDuration().Seconds()),
+	}
+}
+
+func ToApiSmtpSettings(p *models.SmtpSettings) api.ApiSmtpSettings {
+	if p == nil {
+		return api.ApiSmtpSettings{}
+	}
+	return api.ApiSmtpSettings{
+		Host:        p.Host,
+		Port:        p.Port,
+		Username:    p.Username,
+		PasswordSet: p.Password != "",
+		From:        p.From,
+		ImplicitTls: p.ImplicitTls,
+	}
+}
+
+func ToApiNotificationSettings(p *models.NotificationSettings) api.ApiNotificationSettings {
+	if p == nil {
+		return api.ApiNotificationSettings{}
+	}
+	return api.ApiNotificationSettings{
+		EmailUser:        p.EmailUser,
+		EmailAdmins:      p.EmailAdmins,
+		WebhookUrl:       p.WebhookUrl,
+		WebhookSecretSet: p.WebhookSecret != "",
+		MqttTopic:        p.MqttTopic,
+	}
+}
+
+func ToApiBanPolicy(p *models.BanPolicy) api.ApiBanPolicy {
+	if p == nil {
+		return api.ApiBanPolicy{TrustedProxies: []string{}}
+	}
+	return api.ApiBanPolicy{
+		Disabled:                p.Disabled,
+		MaxFailures:             p.MaxFailures,
+		FailureWindowSeconds:    int64(p.FailureWindow.AsDuration().Seconds()),
+		BanDurationSeconds:      int64(p.BanDuration.AsDuration().Seconds()),
+		MaxBanDurationSeconds:   int64(p.MaxBanDuration.AsDuration().Seconds()),
+		SigninRequestsPerMinute: p.SigninRequestsPerMinute,
+		TokenRequestsPerMinute:  p.TokenRequestsPerMinute,
+		TrustedProxies:          append([]string{}, p.TrustedProxies...),
+	}
+}
+
+// The names of the clone actions in the API.
+var cloneActionNames = map[models.CloneAction]string{
+	models.CloneAction_CLONE_ACTION_FLAG:  "flag",
+	models.CloneAction_CLONE_ACTION_BLOCK: "block",
+}
+
+func ToApiPasskeyPolicy(p *models.PasskeyPolicy, webauthn *wa.WA) api.ApiPasskeyPolicy {
+	toApiAuthenticators := func(aaguids []string) []api.ApiAuthenticator {
+		ret := make([]api.ApiAuthenticator, 0, len(aaguids))
+		for _, aaguid := range aaguids {
+			ret = append(ret, api.ApiAuthenticator{Aaguid: aaguid, Name: webauthn.AuthenticatorName(aaguid)})
+		}
+		return ret
+	}
+	return api.ApiPasskeyPolicy{
+		AllowedAuthenticators: toApiAuthenticators(p.GetAllowedAaguids()),
+		DeniedAuthenticators:  toApiAuthenticators(p.GetDeniedAaguids()),
+		CloneAction:           cloneActionNames[p.GetCloneAction()],
+		MinPasskeys:           p.GetMinPasskeys(),
+	}
+}
+
+func ToApiWebauthnSettings(p *models.WebauthnSettings) api.ApiWebauthnSettings {
+	return api.ApiWebauthnSettings{
+		Attachment:       p.GetAttachment(),
+		UserVerification: p.GetUserVerification(),
+		ResidentKey:      p.GetResidentKey(),
+		UseSiteRpId:      p.GetUseSiteRpId(),
+		RelatedOrigins:   p.GetRelatedOrigins(),
+	}
+}
+
+func (s *ApiModule) handleSettingsGet(w http.ResponseWriter, r *http.Request) {
+	config := s.config.Get()
+	user, _, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	if !user.IsAdmin {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	jsonify(w, api.ApiSettings{
+		SessionPolicy: ToApiSessionPolicy(config.SessionPolicy),
+		Smtp:          ToApiSmtpSettings(config.Smtp),
+		Notifications: ToApiNotificationSettings(config.Notifications),
+		BanPolicy:     ToApiBanPolicy(config.BanPolicy),
+		PasskeyPolicy: ToApiPasskeyPolicy(config.PasskeyPolicy, s.webauthn),
+		Webauthn:      ToApiWebauthnSettings(config.Webauthn),
+	})
+}
@@ -0,0 +1,37 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSettings(t *testing.T) {
+	t.Run("returns defaults", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+
+		resp := &api.ApiSettings{}
+		rr := f.request("GET", "/api/settings", nil, cookie, resp)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, api.ApiSessionPolicy{}, resp.SessionPolicy)
+	})
+
+	t.Run("requires admin", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("user@example.com")
+
+		rr := f.request("GET", "/api/settings", nil, cookie, nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("requires authentication", func(t *testing.T) {
+		f := CreateFixture(t)
+
+		rr := f.request("GET", "/api/settings", nil, nil, nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+}
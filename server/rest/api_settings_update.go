@@ -0,0 +1,345 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/security"
+	"boivie/ubergang/server/wa"
+	"errors"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// toSessionPolicy converts a session policy from the API. It returns nil if
+// there are no limits.
+func toSessionPolicy(p api.ApiSessionPolicy) (*models.SessionPolicy, error) {
+	if p.AbsoluteLifetimeSeconds < 0 || p.IdleTimeoutSeconds < 0 {
+		return nil, errors.New("negative duration")
+	}
+	if p.AbsoluteLifetimeSeconds == 0 && p.IdleTimeoutSeconds == 0 {
+		return nil, nil
+	}
+	ret := &models.SessionPolicy{}
+	if p.AbsoluteLifetimeSeconds > 0 {
+		ret.AbsoluteLifetime = durationpb.New(time.Duration(p.AbsoluteLifetimeSeconds) * time.Second)
+	}
+	if p.IdleTimeoutSeconds > 0 {
+		ret.IdleTimeout = durationpb.New(time.Duration(p.IdleTimeoutSeconds) * time.Second)
+	}
+	return ret, nil
+}
+
+// toSmtpSettings converts SMTP settings from the API. It returns nil if no
+// e-mails are to be sent. An empty password keeps the one in `current`.
+func toSmtpSettings(p api.ApiSmtpSettings, current *models.SmtpSettings) (*models.SmtpSettings, error) {
+	host := strings.TrimSpace(p.Host)
+	if host == "" {
+		return nil, nil
+	}
+	if _, err := mail.ParseAddress(p.From); err != nil {
+		return nil, errors.New("invalid sender address")
+	}
+	password := p.Password
+	if password == "" {
+		password = current.GetPassword()
+	}
+	return &models.SmtpSettings{
+		Host:        host,
+		Port:        p.Port,
+		Username:    p.Username,
+		Password:    password,
+		From:        p.From,
+		ImplicitTls: p.ImplicitTls,
+	}, nil
+}
+
+// toNotificationSettings converts notification settings from the API. It
+// returns nil if no notifications are to be sent. An empty webhook secret
+// keeps the one in `current`.
+func toNotificationSettings(p api.ApiNotificationSettings, current *models.NotificationSettings) (*models.NotificationSettings, error) {
+	webhookUrl := strings.TrimSpace(p.WebhookUrl)
+	if webhookUrl != "" {
+		u, err := url.Parse(webhookUrl)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return nil, errors.New("invalid webhook URL")
+		}
+	}
+	mqttTopic := strings.Trim(strings.TrimSpace(p.MqttTopic), "/")
+	if strings.ContainsAny(mqttTopic, "+#") {
+		return nil, errors.New("invalid MQTT topic")
+	}
+	if !p.EmailUser && !p.EmailAdmins && webhookUrl == "" && mqttTopic == "" {
+		return nil, nil
+	}
+	secret := p.WebhookSecret
+	if secret == "" {
+		secret = current.GetWebhookSecret()
+	}
+	return &models.NotificationSettings{
+		EmailUser:     p.EmailUser,
+		EmailAdmins:   p.EmailAdmins,
+		WebhookUrl:    webhookUrl,
+		WebhookSecret: secret,
+		MqttTopic:     mqttTopic,
+	}, nil
+}
+
+// toBanPolicy converts a ban policy from the API. It returns nil if only
+// defaults are used.
+func toBanPolicy(p api.ApiBanPolicy) (*models.BanPolicy, error) {
+	if p.FailureWindowSeconds < 0 || p.BanDurationSeconds < 0 || p.MaxBanDurationSeconds < 0 {
+		return nil, errors.New("negative duration")
+	}
+	var trustedProxies []string
+	for _, proxy := range p.TrustedProxies {
+		proxy = strings.TrimSpace(proxy)
+		if _, err := security.ParseProxy(proxy); err != nil {
+			return nil, err
+		}
+		trustedProxies = append(trustedProxies, proxy)
+	}
+	seconds := func(s int64) *durationpb.Duration {
+		if s == 0 {
+			return nil
+		}
+		return durationpb.New(time.Duration(s) * time.Second)
+	}
+	ret := &models.BanPolicy{
+		Disabled:                p.Disabled,
+		MaxFailures:             p.MaxFailures,
+		FailureWindow:           seconds(p.FailureWindowSeconds),
+		BanDuration:             seconds(p.BanDurationSeconds),
+		MaxBanDuration:          seconds(p.MaxBanDurationSeconds),
+		SigninRequestsPerMinute: p.SigninRequestsPerMinute,
+		TokenRequestsPerMinute:  p.TokenRequestsPerMinute,
+		TrustedProxies:          trustedProxies,
+	}
+	if proto.Equal(ret, &models.BanPolicy{}) {
+		return nil, nil
+	}
+	return ret, nil
+}
+
+// toPasskeyPolicy converts a passkey policy from the API. It returns nil if
+// there are no restrictions.
+func toPasskeyPolicy(p api.ApiPasskeyPolicy) (*models.PasskeyPolicy, error) {
+	toAaguids := func(authenticators []api.ApiAuthenticator) ([]string, error) {
+		var ret []string
+		for _, a := range authenticators {
+			id, err := uuid.Parse(a.Aaguid)
+			if err != nil {
+				return nil, errors.New("invalid AAGUID")
+			}
+			if !slices.Contains(ret, id.String()) {
+				ret = append(ret, id.String())
+			}
+		}
+		return ret, nil
+	}
+	allowed, err := toAaguids(p.AllowedAuthenticators)
+	if err != nil {
+		return nil, err
+	}
+	denied, err := toAaguids(p.DeniedAuthenticators)
+	if err != nil {
+		return nil, err
+	}
+	cloneAction := models.CloneAction_CLONE_ACTION_FLAG
+	if p.CloneAction != "" {
+		found := false
+		for action, name := range cloneActionNames {
+			if name == p.CloneAction {
+				cloneAction, found = action, true
+			}
+		}
+		if !found {
+			return nil, errors.New("invalid clone action")
+		}
+	}
+	if len(allowed) == 0 && len(denied) == 0 && cloneAction == models.CloneAction_CLONE_ACTION_FLAG && p.MinPasskeys == 0 {
+		return nil, nil
+	}
+	return &models.PasskeyPolicy{
+		AllowedAaguids: allowed,
+		DeniedAaguids:  denied,
+		CloneAction:    cloneAction,
+		MinPasskeys:    p.MinPasskeys,
+	}, nil
+}
+
+// toWebauthnSettings converts WebAuthn settings from the API. It returns nil if
+// only defaults are used.
+func toWebauthnSettings(p api.ApiWebauthnSettings, config *models.Configuration) (*models.WebauthnSettings, error) {
+	requirements := []string{"", "required", "preferred", "discouraged"}
+	if !slices.Contains([]string{"", wa.AttachmentPlatform, wa.AttachmentCrossPlatform, wa.AttachmentAny}, p.Attachment) {
+		return nil, errors.New("invalid attachment")
+	}
+	if !slices.Contains(requirements, p.UserVerification) {
+		return nil, errors.New("invalid user verification")
+	}
+	if !slices.Contains(requirements, p.ResidentKey) {
+		return nil, errors.New("invalid resident key requirement")
+	}
+	if p.UseSiteRpId {
+		// The relying party ID must be a suffix of the admin FQDN, as passkeys
+		// are created there.
+		site := strings.Split(config.SiteFqdn, ":")[0]
+		admin := strings.Split(config.AdminFqdn, ":")[0]
+		if site == "" || (admin != site && !strings.HasSuffix(admin, "."+site)) {
+			return nil, errors.New("the admin FQDN is not within the site FQDN")
+		}
+	}
+	if p.RelatedOrigins && !p.UseSiteRpId {
+		return nil, errors.New("related origins require the site's relying party ID")
+	}
+	if p == (api.ApiWebauthnSettings{}) {
+		return nil, nil
+	}
+	return &models.WebauthnSettings{
+		Attachment:       p.Attachment,
+		UserVerification: p.UserVerification,
+		ResidentKey:      p.ResidentKey,
+		UseSiteRpId:      p.UseSiteRpId,
+		RelatedOrigins:   p.RelatedOrigins,
+	}, nil
+}
+
+func (s *ApiModule) handleSettingsUpdate(w http.ResponseWriter, r *http.Request) {
+	user, session, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	if !user.IsAdmin {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	var req api.ApiUpdateSettingsRequest
+	err = parseJsonRequest(w, r, &req)
+	if err != nil {
+		return
+	}
+
+	var sessionPolicy *models.SessionPolicy
+	if req.SessionPolicy != nil {
+		sessionPolicy, err = toSessionPolicy(*req.SessionPolicy)
+		if err != nil {
+			http.Error(w, "Invalid session policy", http.StatusBadRequest)
+			return
+		}
+	}
+
+	config := s.config.Get()
+	var smtp *models.SmtpSettings
+	if req.Smtp != nil {
+		smtp, err = toSmtpSettings(*req.Smtp, config.Smtp)
+		if err != nil {
+			http.Error(w, "Invalid SMTP settings", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var notifications *models.NotificationSettings
+	if req.Notifications != nil {
+		notifications, err = toNotificationSettings(*req.Notifications, config.Notifications)
+		if err != nil {
+			http.Error(w, "Invalid notification settings", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var banPolicy *models.BanPolicy
+	if req.BanPolicy != nil {
+		banPolicy, err = toBanPolicy(*req.BanPolicy)
+		if err != nil {
+			http.Error(w, "Invalid ban policy", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var passkeyPolicy *models.PasskeyPolicy
+	if req.PasskeyPolicy != nil {
+		passkeyPolicy, err = toPasskeyPolicy(*req.PasskeyPolicy)
+		if err != nil {
+			http.Error(w, "Invalid passkey policy", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var webauthn *models.WebauthnSettings
+	if req.Webauthn != nil {
+		webauthn, err = toWebauthnSettings(*req.Webauthn, config)
+		if err != nil {
+			http.Error(w, "Invalid WebAuthn settings", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var before, after *models.Configuration
+	err = s.db.UpdateConfiguration(func(old *models.Configuration) (*models.Configuration, error) {
+		if old == nil {
+			old = proto.Clone(config).(*models.Configuration)
+		}
+		before = proto.Clone(old).(*models.Configuration)
+		after = old
+		if req.SessionPolicy != nil {
+			old.SessionPolicy = sessionPolicy
+		}
+		if req.Smtp != nil {
+			old.Smtp = smtp
+		}
+		if req.Notifications != nil {
+			old.Notifications = notifications
+		}
+		if req.BanPolicy != nil {
+			old.BanPolicy = banPolicy
+		}
+		if req.PasskeyPolicy != nil {
+			old.PasskeyPolicy = passkeyPolicy
+		}
+		if req.Webauthn != nil {
+			old.Webauthn = webauthn
+		}
+		return old, nil
+	})
+	if err != nil {
+		s.log.Warnf("Failed to update settings: %v", err)
+		http.Error(w, "Failed to update settings", http.StatusInternalServerError)
+		return
+	}
+
+	// The configuration is shared with the rest of the server, so that the
+	// new settings apply immediately.
+	s.config.Update(func(config *models.Configuration) {
+		if req.SessionPolicy != nil {
+			config.SessionPolicy = sessionPolicy
+		}
+		if req.Smtp != nil {
+			config.Smtp = smtp
+		}
+		if req.Notifications != nil {
+			config.Notifications = notifications
+		}
+		if req.BanPolicy != nil {
+			config.BanPolicy = banPolicy
+		}
+		if req.PasskeyPolicy != nil {
+			config.PasskeyPolicy = passkeyPolicy
+		}
+		if req.Webauthn != nil {
+			config.Webauthn = webauthn
+		}
+	})
+	s.audit(r, user, session, "settings.update", "", before, after)
+
+	jsonify(w, api.ApiUpdateSettingsResponse{})
+}
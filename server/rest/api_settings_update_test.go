@@ -0,0 +1,392 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/models"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestUpdateSettings(t *testing.T) {
+	t.Run("updates session policy", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+
+		policy := api.ApiSessionPolicy{
+			AbsoluteLifetimeSeconds: 7 * 24 * 3600,
+			IdleTimeoutSeconds:      3600,
+		}
+		rr := f.request("POST", "/api/settings", &api.ApiUpdateSettingsRequest{SessionPolicy: &policy}, cookie, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		resp := &api.ApiSettings{}
+		rr = f.request("GET", "/api/settings", nil, cookie, resp)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, policy, resp.SessionPolicy)
+
+		config, err := f.Db.GetConfiguration()
+		require.NoError(t, err)
+		assert.Equal(t, 7*24*time.Hour, config.SessionPolicy.AbsoluteLifetime.AsDuration())
+		assert.Equal(t, time.Hour, config.SessionPolicy.IdleTimeout.AsDuration())
+	})
+
+	t.Run("clears session policy", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+
+		policy := api.ApiSessionPolicy{IdleTimeoutSeconds: 3600}
+		rr := f.request("POST", "/api/settings", &api.ApiUpdateSettingsRequest{SessionPolicy: &policy}, cookie, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		rr = f.request("POST", "/api/settings", &api.ApiUpdateSettingsRequest{SessionPolicy: &api.ApiSessionPolicy{}}, cookie, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		config, err := f.Db.GetConfiguration()
+		require.NoError(t, err)
+		assert.Nil(t, config.SessionPolicy)
+	})
+
+	t.Run("idle sessions are rejected", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, adminId := f.CreateAdminGetId("admin@example.com")
+
+		policy := api.ApiSessionPolicy{IdleTimeoutSeconds: 3600}
+		rr := f.request("POST", "/api/settings", &api.ApiUpdateSettingsRequest{SessionPolicy: &policy}, cookie, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		sessions := f.Db.ListSessions(adminId)
+		require.Len(t, sessions, 1)
+		err := f.Db.UpdateSession(sessions[0].Id, func(old *models.Session) (*models.Session, error) {
+			old.AuthenticatedAt = timestamppb.New(time.Now().Add(-2 * time.Hour))
+			old.AccessedAt = timestamppb.New(time.Now().Add(-2 * time.Hour))
+			return old, nil
+		})
+		require.NoError(t, err)
+
+		rr = f.request("GET", "/api/settings", nil, cookie, nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("expired sessions are reaped", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, adminId := f.CreateAdminGetId("admin@example.com")
+		_, userId := f.CreateUserGetId("user@example.com")
+
+		policy := api.ApiSessionPolicy{AbsoluteLifetimeSeconds: 3600}
+		rr := f.request("POST", "/api/settings", &api.ApiUpdateSettingsRequest{SessionPolicy: &policy}, cookie, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		sessions := f.Db.ListSessions(userId)
+		require.Len(t, sessions, 1)
+		err := f.Db.UpdateSession(sessions[0].Id, func(old *models.Session) (*models.Session, error) {
+			old.AuthenticatedAt = timestamppb.New(time.Now().Add(-2 * time.Hour))
+			return old, nil
+		})
+		require.NoError(t, err)
+
+		count, err := f.Session.ReapExpired()
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.Empty(t, f.Db.ListSessions(userId))
+		assert.Len(t, f.Db.ListSessions(adminId), 1)
+	})
+
+	t.Run("rejects negative durations", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+
+		policy := api.ApiSessionPolicy{IdleTimeoutSeconds: -1}
+		rr := f.request("POST", "/api/settings", &api.ApiUpdateSettingsRequest{SessionPolicy: &policy}, cookie, nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("requires admin", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("user@example.com")
+
+		policy := api.ApiSessionPolicy{IdleTimeoutSeconds: 3600}
+		rr := f.request("POST", "/api/settings", &api.ApiUpdateSettingsRequest{SessionPolicy: &policy}, cookie, nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("updates SMTP settings", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+
+		smtp := api.ApiSmtpSettings{
+			Host:     "smtp.example.com",
+			Port:     587,
+			Username: "user",
+			Password: "secret",
+			From:     "Ubergang <noreply@example.com>",
+		}
+		rr := f.request("POST", "/api/settings", &api.ApiUpdateSettingsRequest{Smtp: &smtp}, cookie, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		resp := &api.ApiSettings{}
+		f.request("GET", "/api/settings", nil, cookie, resp)
+		assert.Equal(t, "smtp.example.com", resp.Smtp.Host)
+		assert.Empty(t, resp.Smtp.Password)
+		assert.True(t, resp.Smtp.PasswordSet)
+
+		// An empty password keeps the current one.
+		smtp.Password = ""
+		smtp.Port = 465
+		smtp.ImplicitTls = true
+		rr = f.request("POST", "/api/settings", &api.ApiUpdateSettingsRequest{Smtp: &smtp}, cookie, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		config, err := f.Db.GetConfiguration()
+		require.NoError(t, err)
+		assert.Equal(t, "secret", config.Smtp.Password)
+		assert.Equal(t, uint32(465), config.Smtp.Port)
+		assert.True(t, config.Smtp.ImplicitTls)
+		assert.Equal(t, "secret", f.Config.Get().Smtp.Password)
+	})
+
+	t.Run("clears SMTP settings", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+		f.Config.Update(func(c *models.Configuration) {
+			c.Smtp = &models.SmtpSettings{Host: "smtp.example.com"}
+		})
+
+		rr := f.request("POST", "/api/settings", &api.ApiUpdateSettingsRequest{Smtp: &api.ApiSmtpSettings{}}, cookie, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Nil(t, f.Config.Get().Smtp)
+	})
+
+	t.Run("rejects invalid sender", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+
+		smtp := api.ApiSmtpSettings{Host: "smtp.example.com", From: "not an address"}
+		rr := f.request("POST", "/api/settings", &api.ApiUpdateSettingsRequest{Smtp: &smtp}, cookie, nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("updates notification settings", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+
+		notifications := api.ApiNotificationSettings{
+			EmailUser:     true,
+			WebhookUrl:    "https://hooks.example.com/ubergang",
+			WebhookSecret: "secret",
+			MqttTopic:     "ubergang/security/",
+		}
+		rr := f.request("POST", "/api/settings", &api.ApiUpdateSettingsRequest{Notifications: &notifications}, cookie, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		resp := &api.ApiSettings{}
+		f.request("GET", "/api/settings", nil, cookie, resp)
+		assert.True(t, resp.Notifications.EmailUser)
+		assert.Equal(t, "https://hooks.example.com/ubergang", resp.Notifications.WebhookUrl)
+		assert.Empty(t, resp.Notifications.WebhookSecret)
+		assert.True(t, resp.Notifications.WebhookSecretSet)
+		assert.Equal(t, "ubergang/security", resp.Notifications.MqttTopic)
+
+		// An empty secret keeps the current one.
+		notifications.WebhookSecret = ""
+		rr = f.request("POST", "/api/settings", &api.ApiUpdateSettingsRequest{Notifications: &notifications}, cookie, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "secret", f.Config.Get().Notifications.WebhookSecret)
+
+		rr = f.request("POST", "/api/settings", &api.ApiUpdateSettingsRequest{Notifications: &api.ApiNotificationSettings{}}, cookie, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Nil(t, f.Config.Get().Notifications)
+	})
+
+	t.Run("rejects invalid notification settings", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+
+		for _, notifications := range []api.ApiNotificationSettings{
+			{WebhookUrl: "ftp://example.com"},
+			{WebhookUrl: "not a url"},
+			{MqttTopic: "ubergang/#"},
+		} {
+			rr := f.request("POST", "/api/settings", &api.ApiUpdateSettingsRequest{Notifications: &notifications}, cookie, nil)
+			assert.Equal(t, http.StatusBadRequest, rr.Code, "%+v", notifications)
+		}
+	})
+
+	t.Run("updates ban policy", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+
+		policy := api.ApiBanPolicy{
+			MaxFailures:             5,
+			FailureWindowSeconds:    60,
+			BanDurationSeconds:      600,
+			SigninRequestsPerMinute: 10,
+			TokenRequestsPerMinute:  60,
+			TrustedProxies:          []string{"10.0.0.1", "192.168.0.0/16"},
+		}
+		rr := f.request("POST", "/api/settings", &api.ApiUpdateSettingsRequest{BanPolicy: &policy}, cookie, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		resp := &api.ApiSettings{}
+		rr = f.request("GET", "/api/settings", nil, cookie, resp)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, policy, resp.BanPolicy)
+		assert.Equal(t, 10*time.Minute, f.Config.Get().BanPolicy.BanDuration.AsDuration())
+		assert.Nil(t, f.Config.Get().BanPolicy.MaxBanDuration)
+
+		rr = f.request("POST", "/api/settings", &api.ApiUpdateSettingsRequest{BanPolicy: &api.ApiBanPolicy{}}, cookie, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Nil(t, f.Config.Get().BanPolicy)
+
+		rr = f.request("POST", "/api/settings", &api.ApiUpdateSettingsRequest{BanPolicy: &api.ApiBanPolicy{BanDurationSeconds: -1}}, cookie, nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		rr = f.request("POST", "/api/settings", &api.ApiUpdateSettingsRequest{BanPolicy: &api.ApiBanPolicy{TrustedProxies: []string{"proxy.example.com"}}}, cookie, nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("updates passkey policy", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+
+		policy := api.ApiPasskeyPolicy{
+			AllowedAuthenticators: []api.ApiAuthenticator{
+				{Aaguid: "ADCE0002-35BC-C60A-648B-0B25F1F05503"},
+				{Aaguid: "adce0002-35bc-c60a-648b-0b25f1f05503"},
+			},
+			CloneAction: "block",
+			MinPasskeys: 2,
+		}
+		rr := f.request("POST", "/api/settings", &api.ApiUpdateSettingsRequest{PasskeyPolicy: &policy}, cookie, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, []string{"adce0002-35bc-c60a-648b-0b25f1f05503"}, f.Config.Get().PasskeyPolicy.AllowedAaguids)
+		assert.Equal(t, models.CloneAction_CLONE_ACTION_BLOCK, f.Config.Get().PasskeyPolicy.CloneAction)
+
+		resp := &api.ApiSettings{}
+		rr = f.request("GET", "/api/settings", nil, cookie, resp)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, api.ApiPasskeyPolicy{
+			AllowedAuthenticators: []api.ApiAuthenticator{
+				{Aaguid: "adce0002-35bc-c60a-648b-0b25f1f05503", Name: "Chrome on Mac"},
+			},
+			DeniedAuthenticators: []api.ApiAuthenticator{},
+			CloneAction:          "block",
+			MinPasskeys:          2,
+		}, resp.PasskeyPolicy)
+
+		rr = f.request("POST", "/api/settings", &api.ApiUpdateSettingsRequest{PasskeyPolicy: &api.ApiPasskeyPolicy{CloneAction: "flag"}}, cookie, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Nil(t, f.Config.Get().PasskeyPolicy)
+
+		for _, invalid := range []api.ApiPasskeyPolicy{
+			{DeniedAuthenticators: []api.ApiAuthenticator{{Aaguid: "Chrome on Mac"}}},
+			{CloneAction: "ignore"},
+		} {
+			rr = f.request("POST", "/api/settings", &api.ApiUpdateSettingsRequest{PasskeyPolicy: &invalid}, cookie, nil)
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+		}
+	})
+
+	t.Run("updates WebAuthn settings", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+
+		settings := api.ApiWebauthnSettings{
+			Attachment:       "any",
+			UserVerification: "preferred",
+		}
+		rr := f.request("POST", "/api/settings", &api.ApiUpdateSettingsRequest{Webauthn: &settings}, cookie, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "any", f.Config.Get().Webauthn.Attachment)
+
+		resp := &api.ApiSettings{}
+		rr = f.request("GET", "/api/settings", nil, cookie, resp)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, settings, resp.Webauthn)
+
+		rr = f.request("POST", "/api/settings", &api.ApiUpdateSettingsRequest{Webauthn: &api.ApiWebauthnSettings{}}, cookie, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Nil(t, f.Config.Get().Webauthn)
+
+		for _, invalid := range []api.ApiWebauthnSettings{
+			{Attachment: "usb"},
+			{UserVerification: "always"},
+			{ResidentKey: "optional"},
+		} {
+			rr = f.request("POST", "/api/settings", &api.ApiUpdateSettingsRequest{Webauthn: &invalid}, cookie, nil)
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+		}
+	})
+
+	t.Run("uses the site FQDN as relying party ID only if it contains the admin FQDN", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+		settings := &api.ApiWebauthnSettings{UseSiteRpId: true}
+
+		rr := f.request("POST", "/api/settings", &api.ApiUpdateSettingsRequest{Webauthn: settings}, cookie, nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+		f.Config.Update(func(c *models.Configuration) {
+			c.SiteFqdn = "other.com"
+		})
+		rr = f.request("POST", "/api/settings", &api.ApiUpdateSettingsRequest{Webauthn: settings}, cookie, nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+		f.Config.Update(func(c *models.Configuration) {
+			c.SiteFqdn = "example.com"
+		})
+		rr = f.request("POST", "/api/settings", &api.ApiUpdateSettingsRequest{Webauthn: settings}, cookie, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.True(t, f.Config.Get().Webauthn.UseSiteRpId)
+	})
+
+	t.Run("requires the site's relying party ID for related origins", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+		f.Config.Update(func(c *models.Configuration) {
+			c.SiteFqdn = "example.com"
+		})
+
+		settings := &api.ApiWebauthnSettings{RelatedOrigins: true}
+		rr := f.request("POST", "/api/settings", &api.ApiUpdateSettingsRequest{Webauthn: settings}, cookie, nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+		settings.UseSiteRpId = true
+		rr = f.request("POST", "/api/settings", &api.ApiUpdateSettingsRequest{Webauthn: settings}, cookie, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.True(t, f.Config.Get().Webauthn.RelatedOrigins)
+	})
+
+	t.Run("sends notifications to configured channels", func(t *testing.T) {
+		f := CreateFixture(t)
+		server := f.startMailServer(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+
+		notifications := api.ApiNotificationSettings{
+			EmailUser:   true,
+			EmailAdmins: true,
+			MqttTopic:   "ubergang/security",
+		}
+		rr := f.request("POST", "/api/settings", &api.ApiUpdateSettingsRequest{Notifications: &notifications}, cookie, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		_, signinSecret := f.CreateUser("user@example.com")
+		f.SigninFromConfirmedPollId(signinSecret)
+		f.Notifier.Wait()
+
+		var recipients []string
+		for _, msg := range server.Messages() {
+			assert.Equal(t, "New sign-in to test.example.com", msg.Subject)
+			assert.Contains(t, msg.Body, "user@example.com")
+			recipients = append(recipients, msg.To...)
+		}
+		assert.ElementsMatch(t, []string{"user@example.com", "admin@example.com"}, recipients)
+
+		require.Len(t, f.Publisher.Messages, 1)
+		assert.Equal(t, "ubergang/security/new_session", f.Publisher.Messages[0].Topic)
+		assert.Contains(t, string(f.Publisher.Messages[0].Payload), `"userEmail":"user@example.com"`)
+	})
+}
@@ -33,9 +33,20 @@ func (s *ApiModule) handleSigninEmail(w http.ResponseWriter, r *http.Request) {
 		respondErr(api.ApiSignInEmailError{WrongEmail: true})
 		return
 	}
+	if user.IsDisabled {
+		s.log.Infof("User %s is disabled", req.Email)
+		respondErr(api.ApiSignInEmailError{WrongEmail: true})
+		return
+	}
 
 	credentials := s.db.ListCredentials(user.Id)
-	if len(credentials) == 0 {
+	hasPasskey := false
+	for _, c := range credentials {
+		if c.GetWebauthnCredential() != nil {
+			hasPasskey = true
+		}
+	}
+	if !hasPasskey {
 		respondErr(api.ApiSignInEmailError{NoCredentials: true})
 		return
 	}
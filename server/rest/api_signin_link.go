@@ -0,0 +1,89 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/auth"
+	"boivie/ubergang/server/common"
+	"boivie/ubergang/server/mail"
+	"boivie/ubergang/server/models"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// How long sign-in links that are sent by e-mail are valid, which is the same
+// as for sign-in requests that are confirmed using a PIN.
+const signinLinkLifetime = 30 * time.Minute
+
+func (s *ApiModule) signinUrl(token string) string {
+	return fmt.Sprintf("https://%s/signin/%s", s.config.Get().AdminFqdn, token)
+}
+
+func (s *ApiModule) handleSigninLink(w http.ResponseWriter, r *http.Request) {
+	// Note: This endpoint should not be authenticated, as it's part of the
+	// sign-in flow.
+
+	var req api.ApiSendSigninLinkRequest
+	err := parseJsonRequest(w, r, &req)
+	if err != nil {
+		return
+	}
+
+	if !s.mailer.IsConfigured() {
+		jsonify(w, api.ApiSendSigninLinkResponse{
+			Error: &api.ApiSendSigninLinkError{NotConfigured: true}})
+		return
+	}
+
+	user, err := s.db.GetUserByEmail(req.Email)
+	if err == nil && user.IsDisabled {
+		err = errors.New("user is disabled")
+	}
+	if err == nil && user.TotpPolicy == models.TotpPolicy_TOTP_POLICY_REQUIRED {
+		// The link alone would bypass the one-time password.
+		err = errors.New("user requires a one-time password")
+	}
+	if err != nil {
+		// Don't reveal if the user exists.
+		s.log.Infof("Not sending sign-in link to %s: %v", req.Email, err)
+		jsonify(w, api.ApiSendSigninLinkResponse{})
+		return
+	}
+
+	now := time.Now()
+	token := common.MakeRandomSecret()
+	err = s.auth.AddSigninRequest(user.Id, &models.SigninRequest{
+		Id:        token,
+		ExpiresAt: timestamppb.New(now.Add(signinLinkLifetime)),
+		Confirmed: true,
+		OneTime:   true,
+		UserAgent: r.UserAgent(),
+		Ip:        s.events.ClientIP(r),
+	}, now)
+	if errors.Is(err, auth.ErrTooManySigninRequests) {
+		// Responded to as for unknown users, to not reveal that the user
+		// exists.
+		s.log.Infof("Not sending sign-in link to %s: %v", req.Email, err)
+		jsonify(w, api.ApiSendSigninLinkResponse{})
+		return
+	} else if err != nil {
+		s.log.Warnf("Failed to create sign-in link for user %s: %v", user.Id, err)
+		jsonify(w, api.ApiSendSigninLinkResponse{
+			Error: &api.ApiSendSigninLinkError{InternalError: true}})
+		return
+	}
+
+	err = s.mailer.Send(user.Email, mail.SigninLink(s.config.Get().AdminFqdn, s.signinUrl(token), signinLinkLifetime))
+	if err != nil {
+		s.log.Warnf("Failed to send sign-in link to user %s: %v", user.Id, err)
+		_ = s.auth.RemoveSigninRequest(user.Id, token)
+		jsonify(w, api.ApiSendSigninLinkResponse{
+			Error: &api.ApiSendSigninLinkError{InternalError: true}})
+		return
+	}
+
+	jsonify(w, api.ApiSendSigninLinkResponse{})
+}
@@ -0,0 +1,111 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/mail"
+	"boivie/ubergang/server/models"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startMailServer configures the fixture to send e-mails to a local SMTP
+// server.
+func (f *Fixture) startMailServer(t *testing.T) *mail.TestServer {
+	t.Helper()
+	server := mail.StartTestServer(t)
+	f.Config.Update(func(c *models.Configuration) {
+		c.Smtp = server.Settings()
+	})
+	return server
+}
+
+var signinUrlRegexp = regexp.MustCompile(`https://test\.example\.com/signin/(\S+)`)
+
+// signinTokenFromEmail returns the sign-in token of the link in an e-mail.
+func signinTokenFromEmail(t *testing.T, msg *mail.ReceivedMessage) string {
+	t.Helper()
+	m := signinUrlRegexp.FindStringSubmatch(msg.Body)
+	require.NotNil(t, m, "no sign-in link in e-mail: %s", msg.Body)
+	return m[1]
+}
+
+func (f *Fixture) sendSigninLink(email string) *api.ApiSendSigninLinkResponse {
+	resp := &api.ApiSendSigninLinkResponse{}
+	f.request("POST", "/api/signin/link", &api.ApiSendSigninLinkRequest{Email: email}, nil, resp)
+	return resp
+}
+
+func TestSigninLink(t *testing.T) {
+	t.Run("sends link that can be used once", func(t *testing.T) {
+		f := CreateFixture(t)
+		server := f.startMailServer(t)
+		f.CreateUser("user@example.com")
+
+		resp := f.sendSigninLink("user@example.com")
+		require.Nil(t, resp.Error)
+
+		messages := server.Messages()
+		require.Len(t, messages, 1)
+		assert.Equal(t, []string{"user@example.com"}, messages[0].To)
+		assert.Equal(t, "Sign in to test.example.com", messages[0].Subject)
+		token := signinTokenFromEmail(t, messages[0])
+
+		poll, rr := f.pollPin(t, token, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Nil(t, poll.Error)
+		require.NotNil(t, poll.Success)
+		assert.NotEmpty(t, poll.Success.Cookie)
+
+		poll, _ = f.pollPin(t, token, nil)
+		require.NotNil(t, poll.Error)
+		assert.True(t, poll.Error.InvalidToken)
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		f := CreateFixture(t)
+		f.CreateUser("user@example.com")
+
+		resp := f.sendSigninLink("user@example.com")
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.NotConfigured)
+	})
+
+	t.Run("doesn't reveal unknown users", func(t *testing.T) {
+		f := CreateFixture(t)
+		server := f.startMailServer(t)
+
+		resp := f.sendSigninLink("unknown@example.com")
+		assert.Nil(t, resp.Error)
+		assert.Empty(t, server.Messages())
+	})
+
+	t.Run("not sent if a one-time password is required", func(t *testing.T) {
+		f := CreateFixture(t)
+		server := f.startMailServer(t)
+		cookie, _ := f.CreateUser("user@example.com")
+		f.setTotpPolicy(t, cookie, models.TotpPolicy_TOTP_POLICY_REQUIRED)
+
+		resp := f.sendSigninLink("user@example.com")
+		assert.Nil(t, resp.Error)
+		assert.Empty(t, server.Messages())
+	})
+
+	t.Run("is rate limited", func(t *testing.T) {
+		f := CreateFixture(t)
+		server := f.startMailServer(t)
+		// Creating the user adds the first sign-in request.
+		f.CreateUser("user@example.com")
+
+		for i := 0; i < 9; i++ {
+			require.Nil(t, f.sendSigninLink("user@example.com").Error)
+		}
+		// Responds as for unknown users, to not reveal that the user exists.
+		resp := f.sendSigninLink("user@example.com")
+		assert.Nil(t, resp.Error)
+		assert.Len(t, server.Messages(), 9)
+	})
+}
@@ -3,13 +3,14 @@ package rest
 import (
 	"boivie/ubergang/server/api"
 	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/notify"
 	"boivie/ubergang/server/wa"
 	"errors"
 	"net/http"
 )
 
 func (s *ApiModule) handleSigninPinConfirm(w http.ResponseWriter, r *http.Request) {
-	user, session, err := s.session.GetAndValidate(w, r)
+	user, session, err := s.session.GetAndValidateSession(w, r)
 	if err != nil {
 		return
 	}
@@ -36,16 +37,18 @@ func (s *ApiModule) handleSigninPinConfirm(w http.ResponseWriter, r *http.Reques
 
 	_, err = s.webauthn.ValidateAssertion(&req.Credential, state, wa.NewUser(user, s.db.ListCredentials(user.Id)))
 	if err != nil {
-		s.log.Warnf("Failed to validate assertion: %v", err)
+		s.recordFailedAssertion(r, user, err)
 		jsonify(w, api.ApiConfirmSigninPinResponse{
 			Error: &api.ApiConfirmSigninPinError{InvalidEnrollment: true}})
 		return
 	}
 
+	var approved *models.SigninRequest
 	err = s.db.UpdateUser(user.Id, func(old *models.User) (*models.User, error) {
 		for idx := range old.SigninRequests {
 			if old.SigninRequests[idx].Id == state.GetConfirmSignin().SigninRequestId {
 				old.SigninRequests[idx].Confirmed = true
+				approved = old.SigninRequests[idx]
 				return old, nil
 			}
 		}
@@ -58,5 +61,8 @@ func (s *ApiModule) handleSigninPinConfirm(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// The notification is about the device that is signing in.
+	s.notifier.Notify(notify.NewEvent(notify.EventSigninApproved, user, approved.Ip, approved.UserAgent))
+
 	jsonify(w, api.ApiConfirmSigninPinResponse{})
 }
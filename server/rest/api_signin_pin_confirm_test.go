@@ -2,6 +2,7 @@ package rest
 
 import (
 	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/notify"
 	"encoding/base64"
 	"net/http"
 	"net/http/httptest"
@@ -158,4 +159,22 @@ func TestSigninPinConfirm(t *testing.T) {
 		// This should fail because the token's user ID won't match the session user ID
 		assert.Equal(t, http.StatusBadRequest, rr.Code)
 	})
+
+	t.Run("notifies about the device that signs in", func(t *testing.T) {
+		f, cookie, cred, enrollReq, queryResp := setupPinConfirm(t)
+		recorder := f.RecordNotifications()
+
+		assertionResponse := f.SignAssertionRequest(queryResp.AssertionRequest, enrollReq.Options.User.ID, &cred)
+		resp, _ := f.confirmPinSignin(t, cookie, &api.ApiConfirmSigninPinRequest{
+			Token:      queryResp.Token,
+			Credential: *assertionResponse,
+		})
+		require.Nil(t, resp.Error)
+
+		events := recorder.Events()
+		require.Len(t, events, 1)
+		assert.Equal(t, notify.EventSigninApproved, events[0].Type)
+		assert.Equal(t, "test@example.com", events[0].UserEmail)
+		assert.Equal(t, "test useragent", events[0].UserAgent)
+	})
 }
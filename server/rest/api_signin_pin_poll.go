@@ -2,6 +2,7 @@ package rest
 
 import (
 	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/security"
 	"encoding/base64"
 	"net/http"
 	"time"
@@ -25,7 +26,7 @@ func (s *ApiModule) handleSigninPinPoll(w http.ResponseWriter, r *http.Request)
 
 	user, err := s.db.GetUserBySigninRequest(req.Id)
 	if err != nil {
-		s.log.Warnf("User not found for %s", req.Id)
+		s.events.Record(security.SourceHttp, s.events.ClientIP(r), "", "invalid sign-in request")
 		respondErr(api.ApiPollSigninPinError{InvalidToken: true})
 		return
 	}
@@ -38,7 +39,7 @@ func (s *ApiModule) handleSigninPinPoll(w http.ResponseWriter, r *http.Request)
 			}
 
 			if !lreq.Confirmed {
-				png, err := qrcode.Encode("https://"+s.config.AdminFqdn+"/confirm/"+lreq.Pin, qrcode.Low, 256)
+				png, err := qrcode.Encode("https://"+s.config.Get().AdminFqdn+"/confirm/"+lreq.Pin, qrcode.Low, 256)
 				if err != nil {
 					respondErr(api.ApiPollSigninPinError{InternalError: true})
 					return
@@ -46,13 +47,21 @@ func (s *ApiModule) handleSigninPinPoll(w http.ResponseWriter, r *http.Request)
 				qrCodeUri := "data:image/png;base64," + base64.StdEncoding.EncodeToString([]byte(png))
 				jsonify(w, api.ApiPollSigninPinResponse{Pending: &api.ApiSignInPollPending{
 					Pin:        lreq.Pin,
-					ConfirmUrl: "https://" + s.config.AdminFqdn + "/confirm/",
+					ConfirmUrl: "https://" + s.config.Get().AdminFqdn + "/confirm/",
 					QrCodeUrl:  qrCodeUri,
 				}})
 				return
 			}
 
-			session, err := s.signin(r, user)
+			if lreq.OneTime {
+				if err := s.auth.ConsumeSigninRequest(user.Id, lreq.Id); err != nil {
+					s.log.Warnf("Failed to use sign-in request of user %s: %v", user.Id, err)
+					respondErr(api.ApiPollSigninPinError{InvalidToken: true})
+					return
+				}
+			}
+
+			session, err := s.signin(r, user, false)
 			if err != nil {
 				respondErr(api.ApiPollSigninPinError{InternalError: true})
 				return
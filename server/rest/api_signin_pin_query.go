@@ -8,7 +8,7 @@ import (
 )
 
 func (s *ApiModule) handleSigninPinQuery(w http.ResponseWriter, r *http.Request) {
-	user, session, err := s.session.GetAndValidate(w, r)
+	user, session, err := s.session.GetAndValidateSession(w, r)
 	if err != nil {
 		return
 	}
@@ -2,6 +2,7 @@ package rest
 
 import (
 	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/auth"
 	"boivie/ubergang/server/common"
 	"boivie/ubergang/server/models"
 	"errors"
@@ -23,6 +24,9 @@ func (s *ApiModule) handleSigninPinRequest(w http.ResponseWriter, r *http.Reques
 	}
 
 	user, err := s.db.GetUserByEmail(req.Email)
+	if err == nil && user.IsDisabled {
+		err = errors.New("user is disabled")
+	}
 	if err != nil {
 		s.log.Warnf("User not found for %s", req.Email)
 		jsonify(w, api.ApiRequestSigninPinResponse{
@@ -40,21 +44,20 @@ func (s *ApiModule) handleSigninPinRequest(w http.ResponseWriter, r *http.Reques
 
 	pollId := uuid.New().String()
 
-	err = s.db.UpdateUser(user.Id, func(old *models.User) (*models.User, error) {
-		if old == nil {
-			return nil, errors.New("user not found")
-		}
-		// TODO: Rate limit
-		old.SigninRequests = append(old.SigninRequests, &models.SigninRequest{
-			Id:        pollId,
-			Pin:       pin,
-			ExpiresAt: timestamppb.New(time.Now().Add(30 * time.Minute)),
-			UserAgent: req.UserAgent,
-			Ip:        common.ReadUserIP(r),
-		})
-		return old, nil
-	})
-	if err != nil {
+	now := time.Now()
+	err = s.auth.AddSigninRequest(user.Id, &models.SigninRequest{
+		Id:        pollId,
+		Pin:       pin,
+		ExpiresAt: timestamppb.New(now.Add(30 * time.Minute)),
+		UserAgent: req.UserAgent,
+		Ip:        s.events.ClientIP(r),
+	}, now)
+	if errors.Is(err, auth.ErrTooManySigninRequests) {
+		jsonify(w, api.ApiRequestSigninPinResponse{
+			Error: &api.ApiRequestSigninPinError{
+				TooManyRequests: true}})
+		return
+	} else if err != nil {
 		s.log.Warnf("Failed to update user: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
@@ -3,8 +3,10 @@ package rest
 import (
 	"boivie/ubergang/server/api"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSigninPinRequest(t *testing.T) {
@@ -41,4 +43,40 @@ func TestSigninPinRequest(t *testing.T) {
 		assert.Nil(t, resp.Error, "Did not expect an error")
 		assert.NotEmpty(t, resp.ID, "Expected a non-empty ID")
 	})
+
+	t.Run("is rate limited", func(t *testing.T) {
+		f := CreateFixture(t)
+		// Creating the user adds the first sign-in request.
+		f.CreateUser("test@example.com")
+		req := &api.ApiRequestSigninPinRequest{
+			Email: "test@example.com",
+		}
+		for i := 0; i < 9; i++ {
+			resp := &api.ApiRequestSigninPinResponse{}
+			f.request("POST", "/api/signin/pin/request", req, nil, resp)
+			assert.Nil(t, resp.Error, "Did not expect an error")
+		}
+		resp := &api.ApiRequestSigninPinResponse{}
+		f.request("POST", "/api/signin/pin/request", req, nil, resp)
+		assert.NotNil(t, resp.Error, "Expected an error")
+		assert.True(t, resp.Error.TooManyRequests, "Expected TooManyRequests error")
+	})
+
+	t.Run("expired requests are purged", func(t *testing.T) {
+		f := CreateFixture(t)
+		// The invitation is valid for a week.
+		_, invitation := f.CreateUser("test@example.com")
+		resp := &api.ApiRequestSigninPinResponse{}
+		f.request("POST", "/api/signin/pin/request", &api.ApiRequestSigninPinRequest{Email: "test@example.com"}, nil, resp)
+		require.Nil(t, resp.Error)
+
+		count, err := f.Db.PurgeSigninRequests(time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		_, err = f.Db.GetUserBySigninRequest(resp.ID)
+		assert.Error(t, err)
+		_, err = f.Db.GetUserBySigninRequest(invitation)
+		assert.NoError(t, err)
+	})
 }
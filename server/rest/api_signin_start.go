@@ -22,9 +22,16 @@ func (s *ApiModule) handleSigninStart(w http.ResponseWriter, r *http.Request) {
 	challengeStr := base64.RawURLEncoding.EncodeToString(challenge)
 
 	key := []byte("secret")
+	rpId, fallback := s.webauthn.AssertionRPIDs()
+	// The relying party IDs, in case they're changed before the user signs in.
+	audience := jwt.ClaimStrings{rpId}
+	if fallback != "" {
+		audience = append(audience, fallback)
+	}
 	claims := &jwt.RegisteredClaims{
 		ExpiresAt: jwt.NewNumericDate(time.Now().Add(300 * time.Second)),
 		Subject:   challengeStr,
+		Audience:  audience,
 	}
 	t := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	token, err := t.SignedString(key)
@@ -38,9 +45,10 @@ func (s *ApiModule) handleSigninStart(w http.ResponseWriter, r *http.Request) {
 		AssertionRequest: api.ApiAssertionRequest{
 			Challenge:        challengeStr,
 			Timeout:          300_000,
-			RPID:             s.webauthn.RPID(),
+			RPID:             rpId,
 			AllowCredentials: []api.ApiPublicKeyCredentialDescriptor{},
-			UserVerification: "required",
+			UserVerification: string(s.webauthn.UserVerification()),
+			FallbackRPID:     fallback,
 		},
 	})
 }
@@ -0,0 +1,138 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/auth"
+	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/security"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// How long the user has to enter a one-time password after signing in.
+const signinTotpLifetime = 5 * time.Minute
+
+// requireTotp records that `user` has signed in, but must also provide a
+// one-time password, and returns the token to provide it with.
+func (s *ApiModule) requireTotp(user *models.User, passkeyCredentialId, redirect string) (string, error) {
+	stateUuid, err := uuid.NewV7()
+	if err != nil {
+		return "", err
+	}
+	err = s.db.StoreAuthenticationState(&stateUuid, &models.AuthenticationState{
+		UserId:    user.Id,
+		ExpiresAt: timestamppb.New(time.Now().Add(signinTotpLifetime)),
+		Type: &models.AuthenticationState_SignInTotp{
+			SignInTotp: &models.AuthenticationStateSignInTotp{
+				PasskeyCredentialId: passkeyCredentialId,
+				Redirect:            redirect,
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	return stateUuid.String(), nil
+}
+
+// markCredentialUsed records that a credential was used to create `session`.
+func (s *ApiModule) markCredentialUsed(credentialId string, session *models.Session) error {
+	return s.db.UpdateCredential(credentialId, func(old *models.Credential) (*models.Credential, error) {
+		if old == nil {
+			return nil, errors.New("credential not found")
+		}
+		old.LastUsedAt = timestamppb.Now()
+		if !contains(old.UsedBySessionIds, session.Id) {
+			old.UsedBySessionIds = append(old.UsedBySessionIds, session.Id)
+		}
+		return old, nil
+	})
+}
+
+func (s *ApiModule) handleSigninTotp(w http.ResponseWriter, r *http.Request) {
+	// Note: This endpoint should not be authenticated, as it's part of the
+	// sign-in flow.
+
+	var req api.ApiSignInTotpRequest
+	err := parseJsonRequest(w, r, &req)
+	if err != nil {
+		return
+	}
+
+	var user *models.User
+	var signin *models.AuthenticationStateSignInTotp
+	now := time.Now()
+	if req.Token != "" {
+		// The user has already signed in, and this is the second factor.
+		state, err := s.db.ConsumeAuthenticationState(req.Token)
+		if err != nil || state.GetSignInTotp() == nil || state.ExpiresAt.AsTime().Before(now) {
+			jsonify(w, api.ApiSignInTotpResponse{
+				Error: &api.ApiSignInTotpError{InvalidToken: true}})
+			return
+		}
+		signin = state.GetSignInTotp()
+		user, err = s.db.GetUserById(state.UserId)
+		if err != nil {
+			jsonify(w, api.ApiSignInTotpResponse{
+				Error: &api.ApiSignInTotpError{InvalidToken: true}})
+			return
+		}
+	} else {
+		// Don't reveal if the user exists, or may use one-time passwords.
+		user, err = s.db.GetUserByEmail(req.Email)
+		if err != nil || user.TotpPolicy != models.TotpPolicy_TOTP_POLICY_FALLBACK {
+			s.log.Infof("User %s may not sign in using a one-time password", req.Email)
+			jsonify(w, api.ApiSignInTotpResponse{
+				Error: &api.ApiSignInTotpError{InvalidCode: true}})
+			return
+		}
+		signin = &models.AuthenticationStateSignInTotp{Redirect: req.Redirect}
+	}
+	if user.IsDisabled {
+		jsonify(w, api.ApiSignInTotpResponse{
+			Error: &api.ApiSignInTotpError{InvalidCode: true}})
+		return
+	}
+
+	credential, err := s.auth.VerifyTotp(user.Id, req.Code, now)
+	if err != nil {
+		s.events.Record(security.SourceHttp, s.events.ClientIP(r), user.Email, err.Error())
+		response := api.ApiSignInTotpResponse{
+			Error: &api.ApiSignInTotpError{
+				InvalidCode: !errors.Is(err, auth.ErrTotpBlocked),
+				Blocked:     errors.Is(err, auth.ErrTotpBlocked),
+			}}
+		if req.Token != "" {
+			// Let the user try again without having to sign in again.
+			response.Token, _ = s.requireTotp(user, signin.PasskeyCredentialId, signin.Redirect)
+		}
+		jsonify(w, response)
+		return
+	}
+
+	// Only passkeys verify the user.
+	session, err := s.signin(r, user, signin.PasskeyCredentialId != "")
+	if err != nil {
+		jsonify(w, api.ApiSignInTotpResponse{
+			Error: &api.ApiSignInTotpError{InternalError: true}})
+		return
+	}
+	for _, credentialId := range []string{credential.Id, signin.PasskeyCredentialId} {
+		if credentialId == "" {
+			continue
+		}
+		if err := s.markCredentialUsed(credentialId, session); err != nil {
+			s.log.Warnf("Failed to update credential %s: %v", credentialId, err)
+		}
+	}
+
+	jsonify(w, api.ApiSignInTotpResponse{
+		Success: &api.ApiSignInWebauthnSuccess{
+			Cookie:   s.session.CreateSessionCookie(session).String(),
+			Redirect: s.createRedirect(signin.Redirect, session),
+		}})
+}
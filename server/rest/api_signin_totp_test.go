@@ -0,0 +1,162 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/auth"
+	"boivie/ubergang/server/models"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (f *Fixture) signinTotp(req *api.ApiSignInTotpRequest) *api.ApiSignInTotpResponse {
+	resp := &api.ApiSignInTotpResponse{}
+	f.request("POST", "/api/signin/totp", req, nil, resp)
+	return resp
+}
+
+// nextTotpCode returns a code that hasn't been used yet, as the one for the
+// current time step was used when enrolling.
+func nextTotpCode(secret []byte) string {
+	return auth.TotpCode(secret, time.Now().Add(30*time.Second))
+}
+
+func TestSigninTotp(t *testing.T) {
+	t.Run("fallback with email", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("user@example.com")
+		f.setTotpPolicy(t, cookie, models.TotpPolicy_TOTP_POLICY_FALLBACK)
+		secret := f.enrollTotp(t, cookie)
+
+		resp := f.signinTotp(&api.ApiSignInTotpRequest{
+			Email: "user@example.com",
+			Code:  nextTotpCode(secret),
+		})
+		require.Nil(t, resp.Error)
+		require.NotNil(t, resp.Success)
+		assert.NotEmpty(t, resp.Success.Cookie)
+	})
+
+	t.Run("fallback not allowed", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("user@example.com")
+		f.setTotpPolicy(t, cookie, models.TotpPolicy_TOTP_POLICY_FALLBACK)
+		secret := f.enrollTotp(t, cookie)
+		f.setTotpPolicy(t, cookie, models.TotpPolicy_TOTP_POLICY_DISABLED)
+
+		resp := f.signinTotp(&api.ApiSignInTotpRequest{
+			Email: "user@example.com",
+			Code:  nextTotpCode(secret),
+		})
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.InvalidCode)
+		assert.Nil(t, resp.Success)
+	})
+
+	t.Run("code can't be reused", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("user@example.com")
+		f.setTotpPolicy(t, cookie, models.TotpPolicy_TOTP_POLICY_FALLBACK)
+		secret := f.enrollTotp(t, cookie)
+
+		req := &api.ApiSignInTotpRequest{
+			Email: "user@example.com",
+			Code:  nextTotpCode(secret),
+		}
+		require.Nil(t, f.signinTotp(req).Error)
+
+		resp := f.signinTotp(req)
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.InvalidCode)
+
+		events := f.Db.ListSecurityEvents(func(*models.SecurityEvent) bool { return true }, 10)
+		require.Len(t, events, 1)
+		assert.Equal(t, "user@example.com", events[0].Principal)
+		assert.Equal(t, auth.ErrInvalidTotpCode.Error(), events[0].Reason)
+	})
+
+	t.Run("blocked after failed attempts", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("user@example.com")
+		f.setTotpPolicy(t, cookie, models.TotpPolicy_TOTP_POLICY_FALLBACK)
+		secret := f.enrollTotp(t, cookie)
+
+		code := nextTotpCode(secret)
+		wrong := "000000"
+		if code == wrong {
+			wrong = "111111"
+		}
+		for i := 0; i < 5; i++ {
+			resp := f.signinTotp(&api.ApiSignInTotpRequest{Email: "user@example.com", Code: wrong})
+			require.NotNil(t, resp.Error)
+			assert.True(t, resp.Error.InvalidCode)
+		}
+
+		resp := f.signinTotp(&api.ApiSignInTotpRequest{Email: "user@example.com", Code: code})
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.Blocked)
+		assert.Nil(t, resp.Success)
+	})
+
+	t.Run("required after passkey", func(t *testing.T) {
+		f, cookie, enrollReq, cred := setupUserWithCredential(t)
+		f.setTotpPolicy(t, cookie, models.TotpPolicy_TOTP_POLICY_REQUIRED)
+		secret := f.enrollTotp(t, cookie)
+
+		signin := f.signinEmail(t, "test")
+		assertion := f.SignAssertionRequest(&signin.Success.AssertionRequest, enrollReq.Options.User.ID, &cred)
+		webauthnResp := &api.ApiSignInWebauthResponse{}
+		rr := f.request("POST", "/api/signin/webauthn", &api.ApiSignInWebauthnRequest{
+			Token:      signin.Success.Token,
+			Credential: *assertion,
+		}, nil, webauthnResp)
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Nil(t, webauthnResp.Error)
+		require.Nil(t, webauthnResp.Success)
+		require.NotNil(t, webauthnResp.TotpRequired)
+
+		// A wrong code gives a new token to try again with.
+		resp := f.signinTotp(&api.ApiSignInTotpRequest{
+			Token: webauthnResp.TotpRequired.Token,
+			Code:  "12345",
+		})
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.InvalidCode)
+		require.NotEmpty(t, resp.Token)
+
+		resp = f.signinTotp(&api.ApiSignInTotpRequest{
+			Token: resp.Token,
+			Code:  nextTotpCode(secret),
+		})
+		require.Nil(t, resp.Error)
+		require.NotNil(t, resp.Success)
+		assert.NotEmpty(t, resp.Success.Cookie)
+	})
+
+	t.Run("required but not enrolled", func(t *testing.T) {
+		f, cookie, enrollReq, cred := setupUserWithCredential(t)
+		f.setTotpPolicy(t, cookie, models.TotpPolicy_TOTP_POLICY_REQUIRED)
+
+		signin := f.signinEmail(t, "test")
+		assertion := f.SignAssertionRequest(&signin.Success.AssertionRequest, enrollReq.Options.User.ID, &cred)
+		resp := &api.ApiSignInWebauthResponse{}
+		f.request("POST", "/api/signin/webauthn", &api.ApiSignInWebauthnRequest{
+			Token:      signin.Success.Token,
+			Credential: *assertion,
+		}, nil, resp)
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.TotpNotEnrolled)
+		assert.Nil(t, resp.Success)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		f := CreateFixture(t)
+
+		resp := f.signinTotp(&api.ApiSignInTotpRequest{Token: "invalid", Code: "123456"})
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.InvalidToken)
+	})
+}
@@ -0,0 +1,146 @@
+package rest
+
+import (
+	"boivie/ubergang/server/auth"
+	"boivie/ubergang/server/federation"
+	"boivie/ubergang/server/models"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/mux"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// linkFederatedIdentity links `identity` at `providerId` to the user, or
+// records that it has been used if it's already linked. A user has at most one
+// linked identity per provider.
+func (s *ApiModule) linkFederatedIdentity(userId, providerId string, identity *federation.Identity, now time.Time) error {
+	return s.db.UpdateUser(userId, func(old *models.User) (*models.User, error) {
+		if old == nil {
+			return nil, errUserNotFound
+		}
+		var linked *models.FederatedIdentity
+		for _, existing := range old.FederatedIdentities {
+			if existing.ProviderId == providerId {
+				linked = existing
+			}
+		}
+		if linked == nil || linked.Subject != identity.Subject {
+			if linked != nil {
+				s.log.Infof("Replacing identity at %s linked to user %s", providerId, userId)
+			}
+			old.FederatedIdentities = removeFederatedIdentity(old.FederatedIdentities, providerId)
+			linked = &models.FederatedIdentity{
+				ProviderId: providerId,
+				Subject:    identity.Subject,
+				LinkedAt:   timestamppb.New(now),
+			}
+			old.FederatedIdentities = append(old.FederatedIdentities, linked)
+		}
+		linked.Email = identity.Email
+		linked.LastUsedAt = timestamppb.New(now)
+		return old, nil
+	})
+}
+
+// handleSigninUpstreamCallback is where the upstream provider sends the user
+// back to, after they have signed in there.
+func (s *ApiModule) handleSigninUpstreamCallback(w http.ResponseWriter, r *http.Request) {
+	fail := func(reason string) {
+		http.Redirect(w, r, "/signin?error="+reason, http.StatusFound)
+	}
+
+	now := time.Now()
+	q := r.URL.Query()
+	providerId := mux.Vars(r)["id"]
+	state, err := s.db.ConsumeAuthenticationState(q.Get("state"))
+	if err != nil || state.GetUpstreamOidc() == nil || state.GetUpstreamOidc().ProviderId != providerId ||
+		state.ExpiresAt.AsTime().Before(now) {
+		s.log.Warn("Authentication state not found or not intended for upstream sign-in")
+		fail("invalid_state")
+		return
+	}
+	upstream := state.GetUpstreamOidc()
+
+	if upstreamError := q.Get("error"); upstreamError != "" {
+		s.log.Infof("Upstream OIDC provider %s returned error: %s", providerId, upstreamError)
+		fail("upstream_denied")
+		return
+	}
+
+	provider, err := s.db.GetUpstreamOidcProvider(providerId)
+	if err != nil {
+		fail("upstream_unavailable")
+		return
+	}
+
+	identity, err := s.federation.Exchange(r.Context(), provider, s.upstreamRedirectUri(provider.Id),
+		q.Get("code"), upstream.CodeVerifier, upstream.Nonce)
+	if err != nil {
+		s.log.Warnf("Failed to sign in using upstream OIDC provider %s: %v", provider.Id, err)
+		fail("upstream_failed")
+		return
+	}
+
+	if upstream.LinkSessionId != "" {
+		user, _, err := s.db.GetSession(upstream.LinkSessionId)
+		if err != nil || user.Id != state.UserId {
+			fail("invalid_state")
+			return
+		}
+		if err := s.linkFederatedIdentity(user.Id, provider.Id, identity, now); err != nil {
+			s.log.Warnf("Failed to link identity at %s to user %s: %v", provider.Id, user.Id, err)
+			fail("already_linked")
+			return
+		}
+		s.log.Infof("Linked identity at %s to user %s", provider.Id, user.Id)
+		http.Redirect(w, r, upstream.Redirect, http.StatusFound)
+		return
+	}
+
+	user, err := s.db.GetUserByFederatedIdentity(provider.Id, identity.Subject)
+	if err != nil {
+		if !provider.LinkByEmail || !identity.EmailVerified || identity.Email == "" {
+			s.log.Infof("No user linked to identity at %s", provider.Id)
+			fail("not_linked")
+			return
+		}
+		user, err = s.db.GetUserByEmail(identity.Email)
+		if err != nil {
+			s.log.Infof("No user with the e-mail address verified by %s", provider.Id)
+			fail("not_linked")
+			return
+		}
+		s.log.Infof("Linking identity at %s to user %s by e-mail address", provider.Id, user.Id)
+	}
+	if err := s.linkFederatedIdentity(user.Id, provider.Id, identity, now); err != nil {
+		s.log.Warnf("Failed to update identity at %s for user %s: %v", provider.Id, user.Id, err)
+		fail("already_linked")
+		return
+	}
+
+	if user.TotpPolicy == models.TotpPolicy_TOTP_POLICY_REQUIRED {
+		if auth.FindTotpCredential(s.db.ListCredentials(user.Id)) == nil {
+			s.log.Warnf("User %s must use a one-time password, but has none", user.Id)
+			fail("totp_not_enrolled")
+			return
+		}
+		token, err := s.requireTotp(user, "", upstream.Redirect)
+		if err != nil {
+			fail("internal_error")
+			return
+		}
+		http.Redirect(w, r, "/signin?totp="+url.QueryEscape(token), http.StatusFound)
+		return
+	}
+
+	session, err := s.signin(r, user, false)
+	if err != nil {
+		fail("internal_error")
+		return
+	}
+
+	http.SetCookie(w, s.session.CreateSessionCookie(session))
+	http.Redirect(w, r, upstream.Redirect, http.StatusFound)
+}
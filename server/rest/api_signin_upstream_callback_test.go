@@ -0,0 +1,307 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAuthorization struct {
+	nonce         string
+	codeChallenge string
+	redirectUri   string
+}
+
+// fakeIssuer is a local stand-in for an upstream OIDC provider.
+type fakeIssuer struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	// The identity of the user signing in.
+	subject       string
+	email         string
+	emailVerified bool
+	// If set, the ID token contains this nonce instead of the requested one.
+	nonceOverride string
+
+	mu    sync.Mutex
+	codes map[string]fakeAuthorization
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	i := &fakeIssuer{
+		t:             t,
+		key:           key,
+		subject:       "upstream-subject",
+		email:         "user@example.com",
+		emailVerified: true,
+		codes:         make(map[string]fakeAuthorization),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		jsonify(w, map[string]string{
+			"issuer":                 i.server.URL,
+			"authorization_endpoint": i.server.URL + "/authorize",
+			"token_endpoint":         i.server.URL + "/token",
+			"jwks_uri":               i.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		jsonify(w, map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "test-key",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(i.key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(i.key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", i.handleToken)
+	i.server = httptest.NewServer(mux)
+	t.Cleanup(i.server.Close)
+	return i
+}
+
+func (i *fakeIssuer) handleToken(w http.ResponseWriter, r *http.Request) {
+	clientId, clientSecret, _ := r.BasicAuth()
+	if clientId != "ubergang" || clientSecret != "upstream-secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	_ = r.ParseForm()
+	i.mu.Lock()
+	authorization, found := i.codes[r.PostForm.Get("code")]
+	delete(i.codes, r.PostForm.Get("code"))
+	i.mu.Unlock()
+	hash := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !found || authorization.redirectUri != r.PostForm.Get("redirect_uri") ||
+		authorization.codeChallenge != base64.RawURLEncoding.EncodeToString(hash[:]) {
+		w.WriteHeader(http.StatusBadRequest)
+		jsonify(w, api.ApiOAuthErrorResponse{Error: "invalid_grant"})
+		return
+	}
+
+	nonce := authorization.nonce
+	if i.nonceOverride != "" {
+		nonce = i.nonceOverride
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            i.server.URL,
+		"sub":            i.subject,
+		"aud":            "ubergang",
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"iat":            time.Now().Unix(),
+		"nonce":          nonce,
+		"email":          i.email,
+		"email_verified": i.emailVerified,
+	})
+	token.Header["kid"] = "test-key"
+	idToken, err := token.SignedString(i.key)
+	require.NoError(i.t, err)
+	jsonify(w, map[string]interface{}{
+		"access_token": "upstream-access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+// authorize acts as the user signing in at the provider, and returns the
+// callback URL that the user is sent back to.
+func (i *fakeIssuer) authorize(authUrl string) string {
+	u, err := url.Parse(authUrl)
+	require.NoError(i.t, err)
+	require.Equal(i.t, i.server.URL+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+	q := u.Query()
+	require.Equal(i.t, "S256", q.Get("code_challenge_method"))
+
+	code := "code-" + q.Get("state")
+	i.mu.Lock()
+	i.codes[code] = fakeAuthorization{
+		nonce:         q.Get("nonce"),
+		codeChallenge: q.Get("code_challenge"),
+		redirectUri:   q.Get("redirect_uri"),
+	}
+	i.mu.Unlock()
+
+	callback, err := url.Parse(q.Get("redirect_uri"))
+	require.NoError(i.t, err)
+	callback.RawQuery = url.Values{"code": {code}, "state": {q.Get("state")}}.Encode()
+	return callback.String()
+}
+
+func setupUpstreamTest(t *testing.T, linkByEmail bool) (*Fixture, *fakeIssuer, *http.Cookie) {
+	t.Helper()
+	f := CreateFixture(t)
+	issuer := newFakeIssuer(t)
+	adminCookie, _ := f.CreateAdmin("admin@example.com")
+	name := "Upstream"
+	clientId := "ubergang"
+	clientSecret := "upstream-secret"
+	rr := f.request("POST", "/api/upstream-oidc/idp", &api.ApiUpdateUpstreamOidcProviderRequest{
+		Name:         &name,
+		Issuer:       &issuer.server.URL,
+		ClientId:     &clientId,
+		ClientSecret: &clientSecret,
+		LinkByEmail:  &linkByEmail,
+	}, adminCookie, nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	return f, issuer, adminCookie
+}
+
+// signinUpstream runs the sign-in flow, and returns the final response.
+func (f *Fixture) signinUpstream(t *testing.T, issuer *fakeIssuer, cookie *http.Cookie, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	rr := f.request("GET", "/api/signin/upstream/idp/start?"+query, nil, cookie, nil)
+	require.Equal(t, http.StatusFound, rr.Code, rr.Body.String())
+	callback, err := url.Parse(issuer.authorize(rr.Header().Get("Location")))
+	require.NoError(t, err)
+	assert.Equal(t, "https://test.example.com/api/signin/upstream/idp/callback", callback.Scheme+"://"+callback.Host+callback.Path)
+	return f.request("GET", callback.RequestURI(), nil, nil, nil)
+}
+
+func sessionCookie(rr *httptest.ResponseRecorder) *http.Cookie {
+	for _, cookie := range rr.Result().Cookies() {
+		if cookie.Name == "__ug_sess" {
+			return cookie
+		}
+	}
+	return nil
+}
+
+func TestSigninUpstreamCallback(t *testing.T) {
+	t.Run("links by verified e-mail address", func(t *testing.T) {
+		f, issuer, _ := setupUpstreamTest(t, true)
+		_, userId := f.CreateUserGetId("user@example.com")
+
+		rr := f.signinUpstream(t, issuer, nil, "rd=/oauth/authorize%3Fclient_id%3Dapp")
+		require.Equal(t, http.StatusFound, rr.Code)
+		assert.Equal(t, "/oauth/authorize?client_id=app", rr.Header().Get("Location"))
+		cookie := sessionCookie(rr)
+		require.NotNil(t, cookie)
+
+		user := f.getUser(cookie, "me")
+		assert.Equal(t, userId, user.ID)
+		require.Len(t, user.FederatedIdentities, 1)
+		assert.Equal(t, "idp", user.FederatedIdentities[0].ProviderId)
+		assert.Equal(t, "upstream-subject", user.FederatedIdentities[0].Subject)
+
+		// Signing in again uses the link, even if the e-mail address changes.
+		issuer.email = "other@example.com"
+		rr = f.signinUpstream(t, issuer, nil, "")
+		require.NotNil(t, sessionCookie(rr))
+		assert.Equal(t, "/", rr.Header().Get("Location"))
+	})
+
+	t.Run("does not link unverified e-mail address", func(t *testing.T) {
+		f, issuer, _ := setupUpstreamTest(t, true)
+		f.CreateUser("user@example.com")
+		issuer.emailVerified = false
+
+		rr := f.signinUpstream(t, issuer, nil, "")
+		assert.Equal(t, "/signin?error=not_linked", rr.Header().Get("Location"))
+		assert.Nil(t, sessionCookie(rr))
+	})
+
+	t.Run("requires explicit link", func(t *testing.T) {
+		f, issuer, _ := setupUpstreamTest(t, false)
+		userCookie, userId := f.CreateUserGetId("user@example.com")
+
+		rr := f.signinUpstream(t, issuer, nil, "")
+		assert.Equal(t, "/signin?error=not_linked", rr.Header().Get("Location"))
+
+		rr = f.signinUpstream(t, issuer, userCookie, "link=true&rd=/account")
+		require.Equal(t, http.StatusFound, rr.Code)
+		assert.Equal(t, "/account", rr.Header().Get("Location"))
+
+		rr = f.signinUpstream(t, issuer, nil, "")
+		cookie := sessionCookie(rr)
+		require.NotNil(t, cookie)
+		assert.Equal(t, userId, f.getUser(cookie, "me").ID)
+	})
+
+	t.Run("linking requires the session to remain valid", func(t *testing.T) {
+		f, issuer, _ := setupUpstreamTest(t, false)
+		userCookie, _ := f.CreateUserGetId("user@example.com")
+
+		rr := f.request("GET", "/api/signin/upstream/idp/start?link=true", nil, userCookie, nil)
+		require.Equal(t, http.StatusFound, rr.Code, rr.Body.String())
+		require.NoError(t, f.Db.DeleteSession(f.getUser(userCookie, "me").CurrentSession.ID))
+
+		callback, err := url.Parse(issuer.authorize(rr.Header().Get("Location")))
+		require.NoError(t, err)
+		rr = f.request("GET", callback.RequestURI(), nil, nil, nil)
+		assert.Equal(t, "/signin?error=invalid_state", rr.Header().Get("Location"))
+		assert.Nil(t, sessionCookie(rr))
+
+		rr = f.signinUpstream(t, issuer, nil, "")
+		assert.Equal(t, "/signin?error=not_linked", rr.Header().Get("Location"))
+	})
+
+	t.Run("identity can only be linked to one user", func(t *testing.T) {
+		f, issuer, _ := setupUpstreamTest(t, false)
+		firstCookie, _ := f.CreateUserGetId("first@example.com")
+		secondCookie, _ := f.CreateUserGetId("second@example.com")
+
+		rr := f.signinUpstream(t, issuer, firstCookie, "link=true")
+		assert.Equal(t, "/", rr.Header().Get("Location"))
+
+		rr = f.signinUpstream(t, issuer, secondCookie, "link=true")
+		assert.Equal(t, "/signin?error=already_linked", rr.Header().Get("Location"))
+	})
+
+	t.Run("ignores external redirect", func(t *testing.T) {
+		f, issuer, _ := setupUpstreamTest(t, true)
+		f.CreateUser("user@example.com")
+
+		rr := f.signinUpstream(t, issuer, nil, "rd=https://evil.example.com/")
+		assert.Equal(t, "/", rr.Header().Get("Location"))
+	})
+
+	t.Run("rejects wrong nonce", func(t *testing.T) {
+		f, issuer, _ := setupUpstreamTest(t, true)
+		f.CreateUser("user@example.com")
+		issuer.nonceOverride = "replayed"
+
+		rr := f.signinUpstream(t, issuer, nil, "")
+		assert.Equal(t, "/signin?error=upstream_failed", rr.Header().Get("Location"))
+		assert.Nil(t, sessionCookie(rr))
+	})
+
+	t.Run("rejects unknown state", func(t *testing.T) {
+		f, _, _ := setupUpstreamTest(t, true)
+
+		rr := f.request("GET", "/api/signin/upstream/idp/callback?code=x&state=0190a5a0-0000-7000-8000-000000000000", nil, nil, nil)
+		assert.Equal(t, "/signin?error=invalid_state", rr.Header().Get("Location"))
+	})
+
+	t.Run("upstream error", func(t *testing.T) {
+		f, _, _ := setupUpstreamTest(t, true)
+
+		rr := f.request("GET", "/api/signin/upstream/idp/start", nil, nil, nil)
+		u, err := url.Parse(rr.Header().Get("Location"))
+		require.NoError(t, err)
+		state := u.Query().Get("state")
+
+		rr = f.request("GET", "/api/signin/upstream/idp/callback?error=access_denied&state="+state, nil, nil, nil)
+		assert.Equal(t, "/signin?error=upstream_denied", rr.Header().Get("Location"))
+	})
+}
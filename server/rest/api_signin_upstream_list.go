@@ -0,0 +1,30 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"sort"
+)
+
+// handleSigninUpstreamList lists the providers that can be used to sign in.
+// It's used by the sign-in page, so no session is required.
+func (s *ApiModule) handleSigninUpstreamList(w http.ResponseWriter, r *http.Request) {
+	providers := make([]api.ApiSigninProvider, 0)
+	for _, provider := range s.db.ListUpstreamOidcProviders() {
+		if provider.Issuer == "" || provider.ClientId == "" {
+			continue
+		}
+		providers = append(providers, api.ApiSigninProvider{
+			ID:   provider.Id,
+			Name: provider.Name,
+		})
+	}
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].Name < providers[j].Name
+	})
+
+	jsonify(w, api.ApiListSigninProvidersResponse{
+		Providers:   providers,
+		SigninLinks: s.mailer.IsConfigured(),
+	})
+}
@@ -0,0 +1,47 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListSigninUpstream(t *testing.T) {
+	f := CreateFixture(t)
+	cookie, _ := f.CreateAdmin("admin@example.com")
+	name := "Zebra"
+	issuer := "https://idp.example.com"
+	clientId := "client"
+	require.Equal(t, http.StatusOK, f.updateUpstreamOidc(cookie, "z", &api.ApiUpdateUpstreamOidcProviderRequest{
+		Name: &name, Issuer: &issuer, ClientId: &clientId,
+	}))
+	name = "Antelope"
+	require.Equal(t, http.StatusOK, f.updateUpstreamOidc(cookie, "a", &api.ApiUpdateUpstreamOidcProviderRequest{
+		Name: &name, Issuer: &issuer, ClientId: &clientId,
+	}))
+	// Not fully configured, so not listed.
+	require.Equal(t, http.StatusOK, f.updateUpstreamOidc(cookie, "incomplete", &api.ApiUpdateUpstreamOidcProviderRequest{}))
+
+	resp := &api.ApiListSigninProvidersResponse{}
+	rr := f.request("GET", "/api/signin/upstream", nil, nil, resp)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, []api.ApiSigninProvider{
+		{ID: "a", Name: "Antelope"},
+		{ID: "z", Name: "Zebra"},
+	}, resp.Providers)
+}
+
+func TestListSigninUpstreamSigninLinks(t *testing.T) {
+	f := CreateFixture(t)
+
+	resp := &api.ApiListSigninProvidersResponse{}
+	f.request("GET", "/api/signin/upstream", nil, nil, resp)
+	assert.False(t, resp.SigninLinks)
+
+	f.startMailServer(t)
+	f.request("GET", "/api/signin/upstream", nil, nil, resp)
+	assert.True(t, resp.SigninLinks)
+}
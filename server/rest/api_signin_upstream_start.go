@@ -0,0 +1,84 @@
+package rest
+
+import (
+	"boivie/ubergang/server/common"
+	"boivie/ubergang/server/models"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"golang.org/x/oauth2"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// How long the user has to sign in at the upstream provider.
+const upstreamSigninLifetime = 10 * time.Minute
+
+// localRedirect returns `rd` if it's a path on the admin host, and "/"
+// otherwise.
+func localRedirect(rd string) string {
+	if strings.HasPrefix(rd, "/") && !strings.HasPrefix(rd, "//") && !strings.HasPrefix(rd, "/\\") {
+		return rd
+	}
+	return "/"
+}
+
+func (s *ApiModule) upstreamRedirectUri(providerId string) string {
+	return s.issuer() + "/api/signin/upstream/" + providerId + "/callback"
+}
+
+// handleSigninUpstreamStart sends the user to the upstream provider to sign
+// in, or to link their identity there to the signed in user if `link=true`.
+func (s *ApiModule) handleSigninUpstreamStart(w http.ResponseWriter, r *http.Request) {
+	provider, err := s.db.GetUpstreamOidcProvider(mux.Vars(r)["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	q := r.URL.Query()
+	upstream := &models.AuthenticationStateUpstreamOidc{
+		ProviderId:   provider.Id,
+		Nonce:        common.MakeRandomSecret(),
+		CodeVerifier: oauth2.GenerateVerifier(),
+		Redirect:     localRedirect(q.Get("rd")),
+	}
+	state := &models.AuthenticationState{
+		ExpiresAt: timestamppb.New(time.Now().Add(upstreamSigninLifetime)),
+		Type: &models.AuthenticationState_UpstreamOidc{
+			UpstreamOidc: upstream,
+		},
+	}
+	if q.Get("link") == "true" {
+		user, session, err := s.session.GetAndValidateSession(w, r)
+		if err != nil {
+			return
+		}
+		state.UserId = user.Id
+		upstream.LinkSessionId = session.Id
+	}
+
+	stateUuid, err := uuid.NewV7()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	err = s.db.StoreAuthenticationState(&stateUuid, state)
+	if err != nil {
+		s.log.Warn("Failed to store authentication state")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	authUrl, err := s.federation.AuthCodeURL(r.Context(), provider, s.upstreamRedirectUri(provider.Id),
+		stateUuid.String(), upstream.Nonce, upstream.CodeVerifier)
+	if err != nil {
+		s.log.Warnf("Failed to contact upstream OIDC provider %s: %v", provider.Id, err)
+		http.Redirect(w, r, "/signin?error=upstream_unavailable", http.StatusFound)
+		return
+	}
+
+	http.Redirect(w, r, authUrl, http.StatusFound)
+}
@@ -0,0 +1,52 @@
+package rest
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigninUpstreamStart(t *testing.T) {
+	t.Run("redirects to provider", func(t *testing.T) {
+		f, issuer, _ := setupUpstreamTest(t, true)
+
+		rr := f.request("GET", "/api/signin/upstream/idp/start", nil, nil, nil)
+		require.Equal(t, http.StatusFound, rr.Code)
+		u, err := url.Parse(rr.Header().Get("Location"))
+		require.NoError(t, err)
+		assert.Equal(t, issuer.server.URL+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+		q := u.Query()
+		assert.Equal(t, "code", q.Get("response_type"))
+		assert.Equal(t, "ubergang", q.Get("client_id"))
+		assert.Equal(t, "https://test.example.com/api/signin/upstream/idp/callback", q.Get("redirect_uri"))
+		assert.Contains(t, q.Get("scope"), "openid")
+		assert.NotEmpty(t, q.Get("state"))
+		assert.NotEmpty(t, q.Get("nonce"))
+		assert.NotEmpty(t, q.Get("code_challenge"))
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		f, _, _ := setupUpstreamTest(t, true)
+
+		rr := f.request("GET", "/api/signin/upstream/missing/start", nil, nil, nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("linking requires session", func(t *testing.T) {
+		f, _, _ := setupUpstreamTest(t, true)
+
+		rr := f.request("GET", "/api/signin/upstream/idp/start?link=true", nil, nil, nil)
+		assert.NotEqual(t, http.StatusFound, rr.Code)
+	})
+
+	t.Run("unreachable provider", func(t *testing.T) {
+		f, issuer, _ := setupUpstreamTest(t, true)
+		issuer.server.Close()
+
+		rr := f.request("GET", "/api/signin/upstream/idp/start", nil, nil, nil)
+		assert.Equal(t, "/signin?error=upstream_unavailable", rr.Header().Get("Location"))
+	})
+}
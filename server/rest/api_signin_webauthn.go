@@ -2,7 +2,10 @@ package rest
 
 import (
 	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/auth"
 	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/notify"
+	"boivie/ubergang/server/security"
 	"boivie/ubergang/server/wa"
 	"bytes"
 	"encoding/base64"
@@ -11,6 +14,7 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/golang-jwt/jwt/v5"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -24,6 +28,11 @@ func contains(haystack []string, needle string) bool {
 	return false
 }
 
+// recordFailedAssertion records that `user` failed to sign in using a passkey.
+func (s *ApiModule) recordFailedAssertion(r *http.Request, user *models.User, err error) {
+	s.events.Record(security.SourceHttp, s.events.ClientIP(r), user.Email, "invalid passkey assertion: "+err.Error())
+}
+
 func (s *ApiModule) handleSigninWebauthn(w http.ResponseWriter, r *http.Request) {
 	respondErr := func(err api.ApiSignInWebauthnError) {
 		jsonify(w, api.ApiSignInWebauthResponse{Error: &err})
@@ -57,13 +66,7 @@ func (s *ApiModule) handleSigninWebauthn(w http.ResponseWriter, r *http.Request)
 	waUser := wa.NewUser(user, credentials)
 	credential, err := s.webauthn.ValidateAssertion(&req.Credential, state, waUser)
 	if err != nil {
-		s.log.Warnf("WebAuthn credential doesn't validate: %v", err)
-		respondErr(api.ApiSignInWebauthnError{InvalidCredential: true})
-		return
-	}
-
-	session, err := s.signin(r, user)
-	if err != nil {
+		s.recordFailedAssertion(r, user, err)
 		respondErr(api.ApiSignInWebauthnError{InvalidCredential: true})
 		return
 	}
@@ -76,7 +79,30 @@ func (s *ApiModule) handleSigninWebauthn(w http.ResponseWriter, r *http.Request)
 	}
 
 	if matchingCredentialId == "" {
-		s.log.Warnf("WebAuthn credential doesn't match any credential: %v", err)
+		s.recordFailedAssertion(r, user, errors.New("credential doesn't match any credential"))
+		respondErr(api.ApiSignInWebauthnError{InvalidCredential: true})
+		return
+	}
+
+	if user.TotpPolicy == models.TotpPolicy_TOTP_POLICY_REQUIRED {
+		if auth.FindTotpCredential(credentials) == nil {
+			s.log.Warnf("User %s must use a one-time password, but has none", user.Id)
+			respondErr(api.ApiSignInWebauthnError{TotpNotEnrolled: true})
+			return
+		}
+		s.updateSignCount(matchingCredentialId, credential)
+		token, err := s.requireTotp(user, matchingCredentialId, req.Redirect)
+		if err != nil {
+			respondErr(api.ApiSignInWebauthnError{InternalError: true})
+			return
+		}
+		jsonify(w, api.ApiSignInWebauthResponse{
+			TotpRequired: &api.ApiSignInTotpRequired{Token: token}})
+		return
+	}
+
+	session, err := s.signin(r, user, true)
+	if err != nil {
 		respondErr(api.ApiSignInWebauthnError{InvalidCredential: true})
 		return
 	}
@@ -107,6 +133,22 @@ func (s *ApiModule) handleSigninWebauthn(w http.ResponseWriter, r *http.Request)
 		}})
 }
 
+// updateSignCount records the authenticator's signature counter, for when the
+// session is created later.
+func (s *ApiModule) updateSignCount(credentialId string, credential *webauthn.Credential) {
+	err := s.db.UpdateCredential(credentialId, func(old *models.Credential) (*models.Credential, error) {
+		if old == nil {
+			return nil, errors.New("credential not found")
+		}
+		old.GetWebauthnCredential().SignCount = credential.Authenticator.SignCount
+		old.GetWebauthnCredential().CloneWarning = credential.Authenticator.CloneWarning
+		return old, nil
+	})
+	if err != nil {
+		s.log.Warnf("Failed to update credential %s: %v", credentialId, err)
+	}
+}
+
 func (s *ApiModule) getPasswordlessState(w http.ResponseWriter, r *http.Request, req api.ApiSignInWebauthnRequest) (*models.AuthenticationState, error) {
 	token, err := jwt.ParseWithClaims(req.Token, &jwt.RegisteredClaims{}, func(token *jwt.Token) (interface{}, error) {
 		return []byte("secret"), nil
@@ -125,10 +167,21 @@ func (s *ApiModule) getPasswordlessState(w http.ResponseWriter, r *http.Request,
 		return nil, err
 	}
 
+	rpId, fallback := s.webauthn.AdminRPID(), ""
+	if len(claims.Audience) > 0 {
+		rpId = claims.Audience[0]
+	}
+	if len(claims.Audience) > 1 {
+		fallback = claims.Audience[1]
+	}
+
 	return &models.AuthenticationState{
-		UserId:    string(decoded),
-		Challenge: claims.Subject,
-		ExpiresAt: timestamppb.New(claims.ExpiresAt.Time),
+		RpId:             rpId,
+		FallbackRpId:     fallback,
+		UserVerification: string(s.webauthn.UserVerification()),
+		UserId:           string(decoded),
+		Challenge:        claims.Subject,
+		ExpiresAt:        timestamppb.New(claims.ExpiresAt.Time),
 		Type: &models.AuthenticationState_SignIn{
 			SignIn: &models.AthenticationStateSignIn{},
 		},
@@ -149,21 +202,37 @@ func (s *ApiModule) createRedirect(redirect string, session *models.Session) str
 	return u.String()
 }
 
-func (s *ApiModule) signin(r *http.Request, user *models.User) (session *models.Session, err error) {
+// signin creates a new session for `user`, or re-authenticates the current one.
+// `verified` is set when the user signed in using a passkey assertion.
+func (s *ApiModule) signin(r *http.Request, user *models.User, verified bool) (session *models.Session, err error) {
 	userAgent := r.Header.Get("user-agent")
+	created := false
 	_, session, err = s.session.ReuseSession(r)
-	if err != nil || session.UserId != user.Id {
+	if err != nil || session.UserId != user.Id || session.ImpersonatorId != "" {
 		session, err = s.auth.CreateSession(user.Id, userAgent, r.RemoteAddr)
+		created = true
 	}
 	if err != nil {
 		return
 	}
+	now := timestamppb.Now()
 	session.UserAgent = userAgent
 	session.RemoteAddr = r.RemoteAddr
+	session.AuthenticatedAt = now
+	session.AccessedAt = now
+	if verified {
+		session.VerifiedAt = now
+	}
 
 	// Update the session with new IP.
 	err = s.db.UpdateSession(session.Id, func(old *models.Session) (*models.Session, error) {
 		return session, nil
 	})
+	if err == nil {
+		s.session.Touch(session, r)
+		if created {
+			s.notifier.Notify(notify.NewEvent(notify.EventNewSession, user, s.events.ClientIP(r), userAgent))
+		}
+	}
 	return
 }
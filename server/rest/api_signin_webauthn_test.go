@@ -2,6 +2,8 @@ package rest
 
 import (
 	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/notify"
 	"net/http"
 	"testing"
 
@@ -45,6 +47,169 @@ func (f *Fixture) signinWebauthn(t *testing.T, cookie *http.Cookie, token string
 	return cookies[0]
 }
 
+// signinWebauthnWithCounter signs in using `cred`, as if its signature counter
+// was `counter`.
+func (f *Fixture) signinWebauthnWithCounter(t *testing.T, enrollReq *api.ApiEnrollRequest, cred virtualwebauthn.Credential, counter uint32) *api.ApiSignInWebauthResponse {
+	t.Helper()
+	cred.Counter = counter
+	signin := f.signinEmail(t, "test")
+	req := &api.ApiSignInWebauthnRequest{
+		Token:      signin.Success.Token,
+		Credential: *f.SignAssertionRequest(&signin.Success.AssertionRequest, enrollReq.Options.User.ID, &cred),
+	}
+	resp := &api.ApiSignInWebauthResponse{}
+	rr := f.request("POST", "/api/signin/webauthn", req, nil, resp)
+	require.Equal(t, http.StatusOK, rr.Code)
+	return resp
+}
+
+func TestSigninWebauthnPasskeyPolicy(t *testing.T) {
+	t.Run("flags cloned passkeys", func(t *testing.T) {
+		f, cookie, enrollReq, cred := setupUserWithCredential(t)
+
+		require.NotNil(t, f.signinWebauthnWithCounter(t, enrollReq, cred, 5).Success)
+		// The counter went backwards.
+		require.NotNil(t, f.signinWebauthnWithCounter(t, enrollReq, cred, 3).Success)
+
+		credential := f.getUser(cookie, "me").Credentials[0]
+		assert.True(t, credential.CloneWarning)
+		assert.Equal(t, []string{"cloned"}, credential.PolicyViolations)
+		assert.False(t, credential.Blocked)
+	})
+
+	t.Run("blocks cloned passkeys", func(t *testing.T) {
+		f, cookie, enrollReq, cred := setupUserWithCredential(t)
+		f.Config.Update(func(c *models.Configuration) {
+			c.PasskeyPolicy = &models.PasskeyPolicy{CloneAction: models.CloneAction_CLONE_ACTION_BLOCK}
+		})
+
+		require.NotNil(t, f.signinWebauthnWithCounter(t, enrollReq, cred, 5).Success)
+		resp := f.signinWebauthnWithCounter(t, enrollReq, cred, 3)
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.InvalidCredential)
+
+		// It stays blocked, even if the counter is fine again.
+		resp = f.signinWebauthnWithCounter(t, enrollReq, cred, 10)
+		require.NotNil(t, resp.Error)
+
+		credential := f.getUser(cookie, "me").Credentials[0]
+		assert.True(t, credential.CloneWarning)
+		assert.True(t, credential.Blocked)
+	})
+
+	t.Run("rejects denied authenticators", func(t *testing.T) {
+		f, cookie, enrollReq, cred := setupUserWithCredential(t)
+		aaguid := f.getUser(cookie, "me").Credentials[0].Aaguid
+		f.Config.Update(func(c *models.Configuration) {
+			c.PasskeyPolicy = &models.PasskeyPolicy{DeniedAaguids: []string{aaguid}}
+		})
+
+		resp := f.signinWebauthnWithCounter(t, enrollReq, cred, 0)
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.InvalidCredential)
+
+		credential := f.getUser(cookie, "me").Credentials[0]
+		assert.Equal(t, []string{"authenticator_not_allowed"}, credential.PolicyViolations)
+		assert.True(t, credential.Blocked)
+	})
+}
+
+func TestSigninWebauthnSiteRpId(t *testing.T) {
+	f, cookie, enrollReq, cred := setupUserWithCredential(t)
+	// The passkey was created before its relying party ID was recorded.
+	credentialId := f.getUser(cookie, "me").Credentials[0].ID
+	err := f.Db.UpdateCredential(credentialId, func(old *models.Credential) (*models.Credential, error) {
+		old.GetWebauthnCredential().RpId = ""
+		return old, nil
+	})
+	require.NoError(t, err)
+
+	f.Config.Update(func(c *models.Configuration) {
+		c.SiteFqdn = "example.com"
+	})
+	f.Config.Update(func(c *models.Configuration) {
+		c.Webauthn = &models.WebauthnSettings{UseSiteRpId: true}
+	})
+
+	// Only the old passkey can be used, until there is a new one.
+	signin := f.signinEmail(t, "test")
+	assert.Equal(t, "test.example.com", signin.Success.AssertionRequest.RPID)
+	assert.Empty(t, signin.Success.AssertionRequest.FallbackRPID)
+	require.NotNil(t, f.signinWebauthnWithCounter(t, enrollReq, cred, 1).Success)
+
+	resp, err := f.StartEnroll(cookie)
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", resp.EnrollRequest.Options.RP.ID)
+	newCred, attestation := f.GenerateCredential(resp.EnrollRequest)
+	finishResp, err := f.FinishEnroll(cookie, resp.EnrollRequest.Token, attestation)
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", finishResp.Credential.RpId)
+
+	// With passkeys for both, the new one is asked for first, but the old one
+	// still works, e.g. if the new one is lost.
+	signin = f.signinEmail(t, "test")
+	assert.Equal(t, "example.com", signin.Success.AssertionRequest.RPID)
+	assert.Equal(t, "test.example.com", signin.Success.AssertionRequest.FallbackRPID)
+	require.Len(t, signin.Success.AssertionRequest.AllowCredentials, 2)
+	require.NotNil(t, f.signinWebauthnWithCounter(t, resp.EnrollRequest, newCred, 1).Success)
+	signin = f.signinEmail(t, "test")
+	fallback := signin.Success.AssertionRequest
+	fallback.RPID = fallback.FallbackRPID
+	cred.Counter = 2
+	f.signinWebauthn(t, nil, signin.Success.Token, f.SignAssertionRequest(&fallback, enrollReq.Options.User.ID, &cred))
+
+	// Without an e-mail address, passkeys for either can be used.
+	userHandle := resp.EnrollRequest.Options.User.ID
+	startResp := &api.ApiStartSigninResponse{}
+	rr := f.request("GET", "/api/signin/start", nil, nil, startResp)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "example.com", startResp.AssertionRequest.RPID)
+	assert.Equal(t, "test.example.com", startResp.AssertionRequest.FallbackRPID)
+	newCred.Counter = 2
+	credential := f.SignAssertionRequest(&startResp.AssertionRequest, userHandle, &newCred)
+	f.signinWebauthn(t, nil, startResp.Token, credential)
+
+	rr = f.request("GET", "/api/signin/start", nil, nil, startResp)
+	require.Equal(t, http.StatusOK, rr.Code)
+	fallback = startResp.AssertionRequest
+	fallback.RPID = fallback.FallbackRPID
+	cred.Counter = 3
+	f.signinWebauthn(t, nil, startResp.Token, f.SignAssertionRequest(&fallback, userHandle, &cred))
+
+	// A passkey can't be used for another relying party ID than its own.
+	rr = f.request("GET", "/api/signin/start", nil, nil, startResp)
+	require.Equal(t, http.StatusOK, rr.Code)
+	mismatched := startResp.AssertionRequest
+	mismatched.RPID = mismatched.FallbackRPID
+	newCred.Counter = 3
+	signinResp := &api.ApiSignInWebauthResponse{}
+	rr = f.request("POST", "/api/signin/webauthn", &api.ApiSignInWebauthnRequest{
+		Token:      startResp.Token,
+		Credential: *f.SignAssertionRequest(&mismatched, userHandle, &newCred),
+	}, nil, signinResp)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Nil(t, signinResp.Success)
+
+	// Backends may use the site's passkeys, but never to sign in as the user.
+	adminCookie, _ := f.CreateAdmin("admin@example.com")
+	rr = f.CreateBackend(adminCookie, &api.ApiBackend{Fqdn: "grafana.example.com", UpstreamUrl: "http://localhost:3000"})
+	require.Equal(t, http.StatusOK, rr.Code)
+	f.Config.Update(func(c *models.Configuration) {
+		c.Webauthn.RelatedOrigins = true
+	})
+	signin = f.signinEmail(t, "test")
+	newCred.Counter = 4
+	req := &api.ApiSignInWebauthnRequest{
+		Token:      signin.Success.Token,
+		Credential: *f.SignAssertionRequestFrom("https://grafana.example.com", &signin.Success.AssertionRequest, resp.EnrollRequest.Options.User.ID, &newCred),
+	}
+	signinResp = &api.ApiSignInWebauthResponse{}
+	rr = f.request("POST", "/api/signin/webauthn", req, nil, signinResp)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Nil(t, signinResp.Success)
+	assert.NotNil(t, signinResp.Error)
+}
+
 func TestSigninWebauthn(t *testing.T) {
 	t.Run("with email creates new session", func(t *testing.T) {
 		f, cookie, enrollReq, cred := setupUserWithCredential(t)
@@ -91,4 +256,22 @@ func TestSigninWebauthn(t *testing.T) {
 
 		assert.NotEqual(t, oldSessionId, newSessionId, "Expected a new session to be created, but session was reused")
 	})
+
+	t.Run("notifies about new sessions only", func(t *testing.T) {
+		f, cookie, enrollReq, cred := setupUserWithCredential(t)
+		recorder := f.RecordNotifications()
+
+		signin := f.signinEmail(t, "test")
+		assertionResponse := f.SignAssertionRequest(&signin.Success.AssertionRequest, enrollReq.Options.User.ID, &cred)
+		f.signinWebauthn(t, cookie, signin.Success.Token, assertionResponse)
+		assert.Empty(t, recorder.Events())
+
+		signin = f.signinEmail(t, "test")
+		assertionResponse = f.SignAssertionRequest(&signin.Success.AssertionRequest, enrollReq.Options.User.ID, &cred)
+		f.signinWebauthn(t, nil, signin.Success.Token, assertionResponse)
+		events := recorder.Events()
+		require.Len(t, events, 1)
+		assert.Equal(t, notify.EventNewSession, events[0].Type)
+		assert.Equal(t, "test", events[0].UserEmail)
+	})
 }
@@ -2,6 +2,7 @@ package rest
 
 import (
 	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/notify"
 	"boivie/ubergang/server/wa"
 	"net/http"
 	"time"
@@ -30,7 +31,7 @@ func (s *ApiModule) handlePostSshKeyConfirm(w http.ResponseWriter, r *http.Reque
 
 	_, err = s.webauthn.ValidateAssertion(&req.Credential, state, wa.NewUser(user, s.db.ListCredentials(user.Id)))
 	if err != nil {
-		s.log.Warnf("Failed to validate assertion: %v", err)
+		s.recordFailedAssertion(r, user, err)
 		jsonify(w, api.ApiPostConfirmSshKeyResponse{
 			Error: &api.ApiPostConfirmSshKeyError{
 				FailedAuthentication: true}})
@@ -45,6 +46,10 @@ func (s *ApiModule) handlePostSshKeyConfirm(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	event := notify.NewEvent(notify.EventSshKeyConfirmed, user, s.events.ClientIP(r), r.UserAgent())
+	event.Name = key.Name
+	s.notifier.Notify(event)
+
 	jsonify(w, api.ApiPostConfirmSshKeyResponse{
 		Result: &api.ApiPostConfirmSshKeyResult{
 			ExpiresAt: key.ExpiresAt.AsTime().Format(time.RFC3339)}})
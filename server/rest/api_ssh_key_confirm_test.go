@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"boivie/ubergang/server/notify"
 	"bytes"
 	"crypto/ed25519"
 	"crypto/rand"
@@ -36,6 +37,7 @@ func TestConfirmSshKey(t *testing.T) {
 	b.WriteByte('\n')
 
 	_, _ = f.proposeSshKey(key.KeyID, "SECRET", b.String())
+	recorder := f.RecordNotifications()
 
 	confirmResp := f.requestConfirmSshKey(cookie, key.KeyID)
 	if confirmResp.Authenticate == nil {
@@ -51,4 +53,9 @@ func TestConfirmSshKey(t *testing.T) {
 	if finalConfirmResp.Result.ExpiresAt == "" {
 		t.Errorf("Expected expiresAt to be set")
 	}
+
+	events := recorder.Events()
+	require.Len(t, events, 1)
+	require.Equal(t, notify.EventSshKeyConfirmed, events[0].Type)
+	require.Equal(t, "SSH-key-1", events[0].Name)
 }
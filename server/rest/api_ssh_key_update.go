@@ -23,7 +23,7 @@ func (s *ApiModule) handleUpdateSshKey(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	url := fmt.Sprintf("https://%s/ssh/%s", s.config.AdminFqdn, key.Id)
+	url := fmt.Sprintf("https://%s/ssh/%s", s.config.Get().AdminFqdn, key.Id)
 	jsonify(w, api.ApiProposeSshKeyResponse{
 		ConfirmUrl: url,
 	})
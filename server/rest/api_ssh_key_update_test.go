@@ -8,6 +8,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -50,4 +51,15 @@ func TestProposeSshKey(t *testing.T) {
 	require.Len(t, user.SSHKeys, 1, "Expected SSH keys to be 1")
 	assert.Equal(t, key.KeyID, user.SSHKeys[0].ID)
 	assert.Equal(t, fingerprintBase64, user.SSHKeys[0].Sha256Fingerprint)
+
+	// Not confirmed in time.
+	count, err := f.Db.PurgeUnconfirmedSshKeys(time.Now().Add(24 * time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+	count, err = f.Db.PurgeUnconfirmedSshKeys(time.Now().Add(8 * 24 * time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Empty(t, f.getUser(cookie, "me").SSHKeys)
+	_, err = f.Db.GetSshKeyByFingerprint(fingerprint[:])
+	assert.Error(t, err)
 }
@@ -3,18 +3,23 @@ package rest
 import (
 	"boivie/ubergang/server/api"
 	"boivie/ubergang/server/auth"
+	"boivie/ubergang/server/config"
 	"boivie/ubergang/server/db"
 	"boivie/ubergang/server/log"
 	"boivie/ubergang/server/models"
 	"boivie/ubergang/server/mqtt"
+	"boivie/ubergang/server/notify"
+	"boivie/ubergang/server/security"
 	"boivie/ubergang/server/session"
 	"bytes"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"path"
+	"sync"
 	"testing"
 
 	"github.com/descope/virtualwebauthn"
@@ -23,10 +28,13 @@ import (
 )
 
 type Fixture struct {
-	Session *session.SessionStore
-	Auth    *auth.Auth
-	router  *mux.Router
-	Db      *db.DB
+	Config    *config.Store
+	Session   *session.SessionStore
+	Auth      *auth.Auth
+	router    *mux.Router
+	Db        *db.DB
+	Notifier  *notify.Notifier
+	Publisher *FakeMqttPublisher
 }
 
 type FakeMqttConnectionTracker struct{}
@@ -37,29 +45,95 @@ func (f *FakeMqttConnectionTracker) GetActiveConnections() map[string]*mqtt.Clie
 
 func (f *FakeMqttConnectionTracker) DisconnectClient(clientId string) {}
 
+type FakeMqttMessage struct {
+	Topic   string
+	Payload []byte
+}
+
+type FakeMqttPublisher struct {
+	mu       sync.Mutex
+	Messages []FakeMqttMessage
+}
+
+func (p *FakeMqttPublisher) Publish(topic string, payload []byte, qos byte, retain bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Messages = append(p.Messages, FakeMqttMessage{topic, payload})
+	return nil
+}
+
+func (p *FakeMqttPublisher) IsConnected() bool { return true }
+
+func (p *FakeMqttPublisher) Close() error { return nil }
+
+// NotificationRecorder is a notification channel that keeps all events.
+type NotificationRecorder struct {
+	notifier *notify.Notifier
+	mu       sync.Mutex
+	events   []*notify.Event
+}
+
+func (r *NotificationRecorder) Name() string { return "recorder" }
+
+func (r *NotificationRecorder) Send(event *notify.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	return nil
+}
+
+// Events waits for pending notifications, and returns all that have been
+// sent.
+func (r *NotificationRecorder) Events() []*notify.Event {
+	r.notifier.Wait()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*notify.Event{}, r.events...)
+}
+
+// Types returns the types of all events that have been sent.
+func (r *NotificationRecorder) Types() []notify.EventType {
+	var ret []notify.EventType
+	for _, event := range r.Events() {
+		ret = append(ret, event.Type)
+	}
+	return ret
+}
+
+func (f *Fixture) RecordNotifications() *NotificationRecorder {
+	recorder := &NotificationRecorder{notifier: f.Notifier}
+	f.Notifier.AddChannel(recorder)
+	return recorder
+}
+
 func CreateFixture(t *testing.T) *Fixture {
-	config := &models.Configuration{
+	config := config.New(&models.Configuration{
 		AdminFqdn: "test.example.com",
-	}
+	})
 
 	log := log.NewLogger(log.Fields{})
 	db, err := db.New(log, path.Join(t.TempDir(), "test.db"))
 	if err != nil {
 		panic(err)
 	}
-	session := session.NewSessionStore(log, db)
 	auth := auth.New(log, db)
+	events := security.New(log, config, db)
+	session := session.NewSessionStore(log, config, db, auth, events, nil)
 	if err != nil {
 		panic(err)
 	}
-	api := New(config, db, log, session, auth, &FakeMqttConnectionTracker{})
+	publisher := &FakeMqttPublisher{}
+	api := New(config, db, log, session, auth, events, &FakeMqttConnectionTracker{}, publisher)
 	router := mux.NewRouter()
 	api.RegisterEndpoints(router)
 	return &Fixture{
+		config,
 		session,
 		auth,
 		router,
-		db}
+		db,
+		api.notifier,
+		publisher}
 }
 
 func (f *Fixture) getUser(cookie *http.Cookie, id string) *api.ApiUser {
@@ -199,6 +273,12 @@ func (f *Fixture) SigninFromConfirmedPollId(pollId string) *http.Cookie {
 }
 
 func (f *Fixture) request(method, url string, req interface{}, cookie *http.Cookie, res interface{}) *httptest.ResponseRecorder {
+	return f.requestFrom("", method, url, req, cookie, res)
+}
+
+// requestFrom makes a request from the IP address `ip`, or from an unknown
+// address if it's empty.
+func (f *Fixture) requestFrom(ip, method, url string, req interface{}, cookie *http.Cookie, res interface{}) *httptest.ResponseRecorder {
 	buf := bytes.Buffer{}
 	if req != nil {
 		_ = json.NewEncoder(&buf).Encode(req)
@@ -208,6 +288,9 @@ func (f *Fixture) request(method, url string, req interface{}, cookie *http.Cook
 
 	rr := httptest.NewRecorder()
 	httpReq.Host = "test.example.com"
+	if ip != "" {
+		httpReq.RemoteAddr = net.JoinHostPort(ip, "1234")
+	}
 	if cookie != nil {
 		httpReq.AddCookie(cookie)
 	}
@@ -311,7 +394,7 @@ func (f *Fixture) GenerateCredential(request *api.ApiEnrollRequest) (virtualweba
 	rp := virtualwebauthn.RelyingParty{
 		ID:     request.Options.RP.ID,
 		Name:   request.Options.RP.Name,
-		Origin: "https://" + request.Options.RP.ID}
+		Origin: "https://" + f.Config.Get().AdminFqdn}
 
 	att := virtualwebauthn.CreateAttestationResponse(rp, authenticator, cred, options)
 
@@ -338,6 +421,11 @@ func (f *Fixture) GenerateCredential(request *api.ApiEnrollRequest) (virtualweba
 }
 
 func (f *Fixture) SignAssertionRequest(req *api.ApiAssertionRequest, userHandleB64 string, cred *virtualwebauthn.Credential) *api.ApiAssertionCredential {
+	return f.SignAssertionRequestFrom("https://"+f.Config.Get().AdminFqdn, req, userHandleB64, cred)
+}
+
+// SignAssertionRequestFrom signs `req` as if the browser was on `origin`.
+func (f *Fixture) SignAssertionRequestFrom(origin string, req *api.ApiAssertionRequest, userHandleB64 string, cred *virtualwebauthn.Credential) *api.ApiAssertionCredential {
 	authenticator := virtualwebauthn.NewAuthenticator()
 	decoded, _ := base64.RawURLEncoding.DecodeString(userHandleB64)
 	authenticator.Options.UserHandle = decoded
@@ -352,7 +440,7 @@ func (f *Fixture) SignAssertionRequest(req *api.ApiAssertionRequest, userHandleB
 		}),
 		RelyingPartyID: req.RPID,
 	}
-	rp := virtualwebauthn.RelyingParty{Name: req.RPID, ID: req.RPID, Origin: "https://" + req.RPID}
+	rp := virtualwebauthn.RelyingParty{Name: req.RPID, ID: req.RPID, Origin: origin}
 
 	ar := virtualwebauthn.CreateAssertionResponse(rp, authenticator, *cred, ao)
 
@@ -8,7 +8,7 @@ import (
 )
 
 func (s *ApiModule) handleTestingSetup(w http.ResponseWriter, r *http.Request) {
-	if !s.config.IsInTestMode {
+	if !s.config.Get().IsInTestMode {
 		s.log.Warn("Not in test mode")
 		http.Error(w, "Not in test mode", http.StatusInternalServerError)
 		return
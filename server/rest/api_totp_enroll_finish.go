@@ -0,0 +1,92 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/auth"
+	"boivie/ubergang/server/common"
+	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/notify"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *ApiModule) handleTotpEnrollFinish(w http.ResponseWriter, r *http.Request) {
+	user, session, err := s.session.GetAndValidateSession(w, r)
+	if err != nil {
+		return
+	}
+	var req api.ApiFinishTotpEnrollRequest
+	err = parseJsonRequest(w, r, &req)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	state, err := s.db.ConsumeAuthenticationState(req.Token)
+	if err != nil || state.GetEnrollTotp() == nil || state.UserId != user.Id ||
+		state.GetEnrollTotp().SessionId != session.Id || state.ExpiresAt.AsTime().Before(now) {
+		jsonify(w, api.ApiFinishTotpEnrollResponse{
+			Error: &api.ApiFinishTotpEnrollError{InvalidEnrollment: true}})
+		return
+	}
+
+	// The first code proves that the authenticator app has been set up
+	// correctly.
+	secret := state.GetEnrollTotp().Secret
+	step, ok := auth.ValidateTotpCode(secret, req.Code, 0, now)
+	if !ok {
+		jsonify(w, api.ApiFinishTotpEnrollResponse{
+			Error: &api.ApiFinishTotpEnrollError{InvalidCode: true}})
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = "Authenticator app"
+	}
+	cred := &models.Credential{
+		Id:                 common.MakeRandomID(),
+		UserId:             user.Id,
+		Name:               name,
+		CreatedAt:          timestamppb.New(now),
+		LastUsedAt:         timestamppb.New(now),
+		CreatedBySessionId: session.Id,
+		UsedBySessionIds:   []string{session.Id},
+		Type: &models.Credential_TotpCredential{
+			TotpCredential: &models.TotpCredential{
+				Secret:       secret,
+				LastUsedStep: step,
+			},
+		},
+	}
+
+	// Check again, in case another enrollment finished in the meantime.
+	if auth.FindTotpCredential(s.db.ListCredentials(user.Id)) != nil {
+		jsonify(w, api.ApiFinishTotpEnrollResponse{
+			Error: &api.ApiFinishTotpEnrollError{InvalidEnrollment: true}})
+		return
+	}
+	err = s.db.UpdateCredential(cred.Id, func(old *models.Credential) (*models.Credential, error) {
+		if old != nil {
+			return nil, errors.New("credential ID collision")
+		}
+		return cred, nil
+	})
+	if err != nil {
+		s.log.Warnf("Failed to store TOTP credential: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	s.log.Infof("User %s enrolled TOTP credential %s", user.Id, cred.Id)
+	event := notify.NewEvent(notify.EventCredentialEnrolled, user, s.events.ClientIP(r), r.UserAgent())
+	event.Name = cred.Name
+	s.notifier.Notify(event)
+
+	apiCredential := ToApiCredential(cred, s.config.Get().PasskeyPolicy)
+	jsonify(w, api.ApiFinishTotpEnrollResponse{Credential: &apiCredential})
+}
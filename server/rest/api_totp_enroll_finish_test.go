@@ -0,0 +1,87 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/auth"
+	"boivie/ubergang/server/models"
+	"encoding/base32"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeTotpSecret(t *testing.T, secret string) []byte {
+	t.Helper()
+	decoded, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	require.NoError(t, err)
+	return decoded
+}
+
+// enrollTotp enrolls a TOTP credential, and returns its secret. The code for
+// the current time step has been used.
+func (f *Fixture) enrollTotp(t *testing.T, cookie *http.Cookie) []byte {
+	t.Helper()
+	start := f.startTotpEnroll(cookie)
+	require.Nil(t, start.Error)
+	secret := decodeTotpSecret(t, start.Secret)
+
+	resp := &api.ApiFinishTotpEnrollResponse{}
+	f.request("POST", "/api/totp/enroll/finish", &api.ApiFinishTotpEnrollRequest{
+		Token: start.Token,
+		Code:  auth.TotpCode(secret, time.Now()),
+		Name:  "Phone",
+	}, cookie, resp)
+	require.Nil(t, resp.Error)
+	return secret
+}
+
+func TestTotpEnrollFinish(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("user@example.com")
+		f.setTotpPolicy(t, cookie, models.TotpPolicy_TOTP_POLICY_FALLBACK)
+		f.enrollTotp(t, cookie)
+
+		user := f.getUser(cookie, "me")
+		require.Len(t, user.Credentials, 1)
+		assert.Equal(t, "totp", user.Credentials[0].Type)
+		assert.Equal(t, "Phone", user.Credentials[0].Name)
+	})
+
+	t.Run("wrong code", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("user@example.com")
+		f.setTotpPolicy(t, cookie, models.TotpPolicy_TOTP_POLICY_FALLBACK)
+		start := f.startTotpEnroll(cookie)
+		secret := decodeTotpSecret(t, start.Secret)
+
+		resp := &api.ApiFinishTotpEnrollResponse{}
+		f.request("POST", "/api/totp/enroll/finish", &api.ApiFinishTotpEnrollRequest{
+			Token: start.Token,
+			Code:  auth.TotpCode(secret, time.Now().Add(-time.Hour)),
+		}, cookie, resp)
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.InvalidCode)
+		assert.Empty(t, f.getUser(cookie, "me").Credentials)
+	})
+
+	t.Run("other user's token", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("user@example.com")
+		otherCookie, _ := f.CreateUser("other@example.com")
+		f.setTotpPolicy(t, cookie, models.TotpPolicy_TOTP_POLICY_FALLBACK)
+		start := f.startTotpEnroll(cookie)
+		secret := decodeTotpSecret(t, start.Secret)
+
+		resp := &api.ApiFinishTotpEnrollResponse{}
+		f.request("POST", "/api/totp/enroll/finish", &api.ApiFinishTotpEnrollRequest{
+			Token: start.Token,
+			Code:  auth.TotpCode(secret, time.Now()),
+		}, otherCookie, resp)
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.InvalidEnrollment)
+	})
+}
@@ -0,0 +1,71 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/auth"
+	"boivie/ubergang/server/models"
+	"encoding/base64"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	qrcode "github.com/skip2/go-qrcode"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// How long the user has to scan the QR code and enter the first code.
+const totpEnrollLifetime = 10 * time.Minute
+
+func (s *ApiModule) handleTotpEnrollStart(w http.ResponseWriter, r *http.Request) {
+	user, session, err := s.session.GetAndValidateSession(w, r)
+	if err != nil {
+		return
+	}
+
+	if user.TotpPolicy == models.TotpPolicy_TOTP_POLICY_DISABLED {
+		jsonify(w, api.ApiStartTotpEnrollResponse{
+			Error: &api.ApiStartTotpEnrollError{NotAllowed: true}})
+		return
+	}
+	if auth.FindTotpCredential(s.db.ListCredentials(user.Id)) != nil {
+		jsonify(w, api.ApiStartTotpEnrollResponse{
+			Error: &api.ApiStartTotpEnrollError{AlreadyEnrolled: true}})
+		return
+	}
+
+	secret := auth.GenerateTotpSecret()
+	stateUuid, err := uuid.NewV7()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	err = s.db.StoreAuthenticationState(&stateUuid, &models.AuthenticationState{
+		UserId:    user.Id,
+		ExpiresAt: timestamppb.New(time.Now().Add(totpEnrollLifetime)),
+		Type: &models.AuthenticationState_EnrollTotp{
+			EnrollTotp: &models.AuthenticationStateEnrollTotp{
+				SessionId: session.Id,
+				Secret:    secret,
+			},
+		},
+	})
+	if err != nil {
+		s.log.Warn("Failed to store authentication state")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	uri := auth.TotpUri(s.config.Get().AdminFqdn, user.Email, secret)
+	png, err := qrcode.Encode(uri, qrcode.Low, 256)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	jsonify(w, api.ApiStartTotpEnrollResponse{
+		Token:     stateUuid.String(),
+		Secret:    auth.EncodeTotpSecret(secret),
+		Uri:       uri,
+		QrCodeUrl: "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+	})
+}
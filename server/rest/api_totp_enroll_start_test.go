@@ -0,0 +1,91 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/models"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (f *Fixture) setTotpPolicy(t *testing.T, cookie *http.Cookie, policy models.TotpPolicy) {
+	t.Helper()
+	user := f.getUser(cookie, "me")
+	require.NoError(t, f.Db.UpdateUser(user.ID, func(old *models.User) (*models.User, error) {
+		old.TotpPolicy = policy
+		return old, nil
+	}))
+}
+
+func (f *Fixture) startTotpEnroll(cookie *http.Cookie) *api.ApiStartTotpEnrollResponse {
+	resp := &api.ApiStartTotpEnrollResponse{}
+	f.request("POST", "/api/totp/enroll/start", nil, cookie, resp)
+	return resp
+}
+
+func TestTotpEnrollStart(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("user@example.com")
+		f.setTotpPolicy(t, cookie, models.TotpPolicy_TOTP_POLICY_FALLBACK)
+
+		resp := f.startTotpEnroll(cookie)
+		require.Nil(t, resp.Error)
+		assert.NotEmpty(t, resp.Token)
+		assert.Len(t, resp.Secret, 32)
+		assert.True(t, strings.HasPrefix(resp.Uri, "otpauth://totp/test.example.com:user@example.com?"))
+		assert.Contains(t, resp.Uri, "secret="+resp.Secret)
+		assert.True(t, strings.HasPrefix(resp.QrCodeUrl, "data:image/png;base64,"))
+	})
+
+	t.Run("not allowed by default", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("user@example.com")
+
+		resp := f.startTotpEnroll(cookie)
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.NotAllowed)
+	})
+
+	t.Run("already enrolled", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("user@example.com")
+		f.setTotpPolicy(t, cookie, models.TotpPolicy_TOTP_POLICY_FALLBACK)
+		f.enrollTotp(t, cookie)
+
+		resp := f.startTotpEnroll(cookie)
+		require.NotNil(t, resp.Error)
+		assert.True(t, resp.Error.AlreadyEnrolled)
+	})
+
+	t.Run("expired states are purged", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("user@example.com")
+		f.setTotpPolicy(t, cookie, models.TotpPolicy_TOTP_POLICY_FALLBACK)
+		require.Nil(t, f.startTotpEnroll(cookie).Error)
+
+		count, err := f.Db.PurgeAuthenticationStates(time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+
+		count, err = f.Db.PurgeAuthenticationStates(time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		// Long gone, so it's removed without being looked at.
+		require.Nil(t, f.startTotpEnroll(cookie).Error)
+		count, err = f.Db.PurgeAuthenticationStates(time.Now().Add(48 * time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("not signed in", func(t *testing.T) {
+		f := CreateFixture(t)
+		rr := f.request("POST", "/api/totp/enroll/start", nil, nil, nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+}
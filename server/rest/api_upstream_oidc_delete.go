@@ -0,0 +1,40 @@
+package rest
+
+import (
+	"boivie/ubergang/server/models"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func (s *ApiModule) handleUpstreamOidcDelete(w http.ResponseWriter, r *http.Request) {
+	user, session, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	if !user.IsAdmin {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+
+	// Linked identities are kept, so that they work again if the provider is
+	// re-created with the same ID.
+	var before *models.UpstreamOidcProvider
+	err = s.db.UpdateUpstreamOidcProvider(id, func(old *models.UpstreamOidcProvider) (*models.UpstreamOidcProvider, error) {
+		before = old
+		return nil, nil
+	})
+
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if before != nil {
+		s.audit(r, user, session, "upstream-oidc.delete", id, before, nil)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
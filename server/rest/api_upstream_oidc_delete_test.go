@@ -0,0 +1,21 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteUpstreamOidc(t *testing.T) {
+	f := CreateFixture(t)
+	cookie, _ := f.CreateAdmin("admin@example.com")
+	require.Equal(t, http.StatusOK, f.updateUpstreamOidc(cookie, "idp", &api.ApiUpdateUpstreamOidcProviderRequest{}))
+
+	rr := f.request("DELETE", "/api/upstream-oidc/idp", nil, cookie, nil)
+	require.Equal(t, http.StatusNoContent, rr.Code)
+
+	assert.Nil(t, f.getUpstreamOidc(cookie, "idp"))
+}
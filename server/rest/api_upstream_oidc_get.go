@@ -0,0 +1,43 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/models"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+func ToApiUpstreamOidcProvider(provider *models.UpstreamOidcProvider) api.ApiUpstreamOidcProvider {
+	return api.ApiUpstreamOidcProvider{
+		ID:              provider.Id,
+		Name:            provider.Name,
+		Issuer:          provider.Issuer,
+		ClientId:        provider.ClientId,
+		HasClientSecret: provider.ClientSecret != "",
+		LinkByEmail:     provider.LinkByEmail,
+		CreatedAt:       provider.CreatedAt.AsTime().Format(time.RFC3339),
+		UpdatedAt:       provider.UpdatedAt.AsTime().Format(time.RFC3339),
+	}
+}
+
+func (s *ApiModule) handleUpstreamOidcGet(w http.ResponseWriter, r *http.Request) {
+	user, _, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	if !user.IsAdmin {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	provider, err := s.db.GetUpstreamOidcProvider(mux.Vars(r)["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	jsonify(w, ToApiUpstreamOidcProvider(provider))
+}
@@ -0,0 +1,31 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetUpstreamOidc(t *testing.T) {
+	t.Run("does not return secret", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+		require.Equal(t, http.StatusOK, f.updateUpstreamOidc(cookie, "idp", &api.ApiUpdateUpstreamOidcProviderRequest{}))
+
+		rr := f.request("GET", "/api/upstream-oidc/idp", nil, cookie, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.NotContains(t, rr.Body.String(), `"clientSecret"`)
+		assert.Contains(t, rr.Body.String(), `"hasClientSecret":false`)
+	})
+
+	t.Run("returns not found", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+
+		rr := f.request("GET", "/api/upstream-oidc/missing", nil, cookie, nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
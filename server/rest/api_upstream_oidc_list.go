@@ -0,0 +1,31 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"sort"
+)
+
+func (s *ApiModule) handleUpstreamOidcList(w http.ResponseWriter, r *http.Request) {
+	user, _, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	if !user.IsAdmin {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	providers := make([]api.ApiUpstreamOidcProvider, 0)
+	for _, provider := range s.db.ListUpstreamOidcProviders() {
+		providers = append(providers, ToApiUpstreamOidcProvider(provider))
+	}
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].ID < providers[j].ID
+	})
+
+	jsonify(w, api.ApiListUpstreamOidcProvidersResponse{
+		Providers: providers,
+	})
+}
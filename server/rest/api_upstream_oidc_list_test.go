@@ -0,0 +1,24 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListUpstreamOidc(t *testing.T) {
+	f := CreateFixture(t)
+	cookie, _ := f.CreateAdmin("admin@example.com")
+	require.Equal(t, http.StatusOK, f.updateUpstreamOidc(cookie, "b", &api.ApiUpdateUpstreamOidcProviderRequest{}))
+	require.Equal(t, http.StatusOK, f.updateUpstreamOidc(cookie, "a", &api.ApiUpdateUpstreamOidcProviderRequest{}))
+
+	resp := &api.ApiListUpstreamOidcProvidersResponse{}
+	rr := f.request("GET", "/api/upstream-oidc", nil, cookie, resp)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Len(t, resp.Providers, 2)
+	assert.Equal(t, "a", resp.Providers[0].ID)
+	assert.Equal(t, "b", resp.Providers[1].ID)
+}
@@ -0,0 +1,90 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/models"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/mux"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func isValidIssuer(issuer string) bool {
+	u, err := url.Parse(issuer)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "https" || u.Scheme == "http") && u.Host != "" && u.RawQuery == "" && u.Fragment == ""
+}
+
+func (s *ApiModule) handleUpstreamOidcUpdate(w http.ResponseWriter, r *http.Request) {
+	user, session, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	if !user.IsAdmin {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	var req api.ApiUpdateUpstreamOidcProviderRequest
+	err = parseJsonRequest(w, r, &req)
+	if err != nil {
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		http.Error(w, "ID is required", http.StatusBadRequest)
+		return
+	}
+
+	if req.Issuer != nil && !isValidIssuer(*req.Issuer) {
+		http.Error(w, "Invalid issuer", http.StatusBadRequest)
+		return
+	}
+
+	var before, after *models.UpstreamOidcProvider
+	err = s.db.UpdateUpstreamOidcProvider(id, func(old *models.UpstreamOidcProvider) (*models.UpstreamOidcProvider, error) {
+		before = proto.Clone(old).(*models.UpstreamOidcProvider)
+		now := time.Now()
+		if old == nil {
+			old = &models.UpstreamOidcProvider{
+				Id:        id,
+				Name:      id,
+				CreatedAt: timestamppb.New(now),
+			}
+		}
+		if req.Name != nil {
+			old.Name = *req.Name
+		}
+		if req.Issuer != nil {
+			old.Issuer = *req.Issuer
+		}
+		if req.ClientId != nil {
+			old.ClientId = *req.ClientId
+		}
+		if req.ClientSecret != nil {
+			old.ClientSecret = *req.ClientSecret
+		}
+		if req.LinkByEmail != nil {
+			old.LinkByEmail = *req.LinkByEmail
+		}
+		old.UpdatedAt = timestamppb.New(now)
+		after = old
+		return old, nil
+	})
+
+	if err != nil {
+		s.log.Warnf("Failed to update upstream OIDC provider %s: %v", id, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.audit(r, user, session, "upstream-oidc.update", id, before, after)
+
+	jsonify(w, api.ApiUpdateUpstreamOidcProviderResponse{})
+}
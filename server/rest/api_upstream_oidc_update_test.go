@@ -0,0 +1,69 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (f *Fixture) updateUpstreamOidc(cookie *http.Cookie, id string, req *api.ApiUpdateUpstreamOidcProviderRequest) int {
+	rr := f.request("POST", "/api/upstream-oidc/"+id, req, cookie, &api.ApiUpdateUpstreamOidcProviderResponse{})
+	return rr.Code
+}
+
+func (f *Fixture) getUpstreamOidc(cookie *http.Cookie, id string) *api.ApiUpstreamOidcProvider {
+	resp := &api.ApiUpstreamOidcProvider{}
+	rr := f.request("GET", "/api/upstream-oidc/"+id, nil, cookie, resp)
+	if rr.Code != http.StatusOK {
+		return nil
+	}
+	return resp
+}
+
+func TestUpdateUpstreamOidc(t *testing.T) {
+	t.Run("creates provider", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+
+		name := "Google"
+		issuer := "https://accounts.google.com"
+		clientId := "client"
+		clientSecret := "secret"
+		code := f.updateUpstreamOidc(cookie, "google", &api.ApiUpdateUpstreamOidcProviderRequest{
+			Name:         &name,
+			Issuer:       &issuer,
+			ClientId:     &clientId,
+			ClientSecret: &clientSecret,
+		})
+		require.Equal(t, http.StatusOK, code)
+
+		provider := f.getUpstreamOidc(cookie, "google")
+		require.NotNil(t, provider)
+		assert.Equal(t, "Google", provider.Name)
+		assert.Equal(t, issuer, provider.Issuer)
+		assert.Equal(t, clientId, provider.ClientId)
+		assert.True(t, provider.HasClientSecret)
+		assert.False(t, provider.LinkByEmail)
+	})
+
+	t.Run("rejects invalid issuer", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateAdmin("admin@example.com")
+
+		for _, issuer := range []string{"accounts.google.com", "ftp://example.com", "https://example.com/?a=b"} {
+			code := f.updateUpstreamOidc(cookie, "idp", &api.ApiUpdateUpstreamOidcProviderRequest{Issuer: &issuer})
+			assert.Equal(t, http.StatusBadRequest, code, issuer)
+		}
+	})
+
+	t.Run("requires admin", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("user@example.com")
+
+		code := f.updateUpstreamOidc(cookie, "idp", &api.ApiUpdateUpstreamOidcProviderRequest{})
+		assert.Equal(t, http.StatusForbidden, code)
+	})
+}
@@ -3,12 +3,15 @@ package rest
 import (
 	"boivie/ubergang/server/api"
 	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/wa"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 )
 
 func (s *ApiModule) handleUserGet(w http.ResponseWriter, r *http.Request) {
+	config := s.config.Get()
 	sessionUser, session, err := s.session.GetAndValidate(w, r)
 	if err != nil {
 		return
@@ -30,25 +33,50 @@ func (s *ApiModule) handleUserGet(w http.ResponseWriter, r *http.Request) {
 	}
 
 	au := api.ApiUser{
-		ID:             user.Id,
-		Email:          user.Email,
-		DisplayName:    user.DisplayName,
-		AllowedHosts:   user.AllowedHosts,
-		IsAdmin:        user.IsAdmin,
-		Credentials:    make([]api.ApiCredential, 0),
-		Sessions:       make([]api.ApiSession, 0),
-		SSHKeys:        make([]api.ApiSSHKey, 0),
-		CurrentSession: currentSession,
+		ID:                  user.Id,
+		Email:               user.Email,
+		DisplayName:         user.DisplayName,
+		AllowedHosts:        user.AllowedHosts,
+		IsAdmin:             user.IsAdmin,
+		IsDisabled:          user.IsDisabled,
+		TotpPolicy:          totpPolicyNames[user.TotpPolicy],
+		Groups:              user.Groups,
+		Credentials:         make([]api.ApiCredential, 0),
+		Sessions:            make([]api.ApiSession, 0),
+		SSHKeys:             make([]api.ApiSSHKey, 0),
+		AccessTokens:        make([]api.ApiAccessToken, 0),
+		AppPasswords:        make([]api.ApiAppPassword, 0),
+		FederatedIdentities: make([]api.ApiFederatedIdentity, 0),
+		CurrentSession:      currentSession,
+	}
+	if userId == "me" && session.ImpersonatorId != "" {
+		if admin, err := s.session.Impersonator(session); err == nil {
+			au.Impersonation = &api.ApiImpersonation{
+				ImpersonatorEmail: admin.Email,
+				ExpiresAt:         session.ExpiresAt.AsTime().Format(time.RFC3339),
+			}
+		}
 	}
 
-	for _, c := range s.db.ListCredentials(user.Id) {
-		au.Credentials = append(au.Credentials, ToApiCredential(c))
+	credentials := s.db.ListCredentials(user.Id)
+	for _, c := range credentials {
+		au.Credentials = append(au.Credentials, ToApiCredential(c, config.PasskeyPolicy))
 	}
+	au.PasskeyPolicyViolations = wa.UserViolations(config.PasskeyPolicy, credentials)
 	for _, s := range s.db.ListSessions(user.Id) {
 		au.Sessions = append(au.Sessions, ToApiSession(s))
 	}
 	for _, key := range s.db.ListSshKeys(user.Id) {
 		au.SSHKeys = append(au.SSHKeys, ToApiSshKey(key))
 	}
+	for _, token := range s.db.ListAccessTokens(user.Id) {
+		au.AccessTokens = append(au.AccessTokens, ToApiAccessToken(token))
+	}
+	for _, identity := range user.FederatedIdentities {
+		au.FederatedIdentities = append(au.FederatedIdentities, ToApiFederatedIdentity(identity))
+	}
+	for _, appPassword := range s.db.ListAppPasswords(user.Id) {
+		au.AppPasswords = append(au.AppPasswords, ToApiAppPassword(appPassword))
+	}
 	jsonify(w, au)
 }
@@ -0,0 +1,95 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/auth"
+	"boivie/ubergang/server/models"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// explainAccess returns whether `user` may access `host` through the proxy,
+// why, and what else applies to such requests.
+func explainAccess(user *models.User, host string, backend *models.Backend) (allowed bool, reason string, notes []string) {
+	notes = make([]string, 0)
+	if backend == nil {
+		return false, fmt.Sprintf("There is no backend for %s", host), notes
+	}
+	if backend.AccessLevel == models.AccessLevel_PUBLIC {
+		allowed, reason = true, "The backend is public, so no sign-in is required"
+	} else if user.IsDisabled {
+		return false, "The user is disabled", notes
+	} else if !auth.IsAllowed(user, nil, host) {
+		return false, fmt.Sprintf("%s is not one of the user's allowed hosts", host), notes
+	} else if user.IsAdmin {
+		allowed, reason = true, "The user is an administrator, who may access all backends"
+	} else {
+		allowed, reason = true, fmt.Sprintf("%s is one of the user's allowed hosts", host)
+	}
+
+	if backend.AccessLevel != models.AccessLevel_PUBLIC {
+		if p := backend.SessionPolicy; p != nil {
+			if d := p.AbsoluteLifetime.AsDuration(); d > 0 {
+				notes = append(notes, fmt.Sprintf("The user must have signed in within %s", d))
+			}
+			if d := p.IdleTimeout.AsDuration(); d > 0 {
+				notes = append(notes, fmt.Sprintf("The session must have been used within %s", d))
+			}
+		}
+		if d := backend.MaxAuthAge.AsDuration(); d > 0 {
+			notes = append(notes, fmt.Sprintf("The session must have been verified with a passkey within %s, and access tokens and app passwords can't be used", d))
+		} else if backend.AllowBasicAuth {
+			notes = append(notes, "App passwords may be used with HTTP Basic authentication")
+		}
+	}
+	if backend.ScriptHandler.GetJsScript() != "" {
+		notes = append(notes, "The backend's script handler may respond to the request before it reaches the upstream")
+	}
+	return
+}
+
+func (s *ApiModule) handleUserAccess(w http.ResponseWriter, r *http.Request) {
+	sessionUser, _, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	if !sessionUser.IsAdmin {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	user, err := s.db.GetUserById(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	raw := r.URL.Query().Get("url")
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" {
+		http.Error(w, "Invalid URL", http.StatusBadRequest)
+		return
+	}
+	host := strings.ToLower(u.Hostname())
+
+	backend, err := s.db.GetBackend(host)
+	if err != nil {
+		backend = nil
+	}
+	allowed, reason, notes := explainAccess(user, host, backend)
+	jsonify(w, api.ApiUserAccessResponse{
+		Url:     u.String(),
+		Host:    host,
+		Allowed: allowed,
+		Reason:  reason,
+		Notes:   notes,
+	})
+}
@@ -0,0 +1,123 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/models"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func (f *Fixture) checkAccess(t *testing.T, cookie *http.Cookie, userId, u string) *api.ApiUserAccessResponse {
+	t.Helper()
+	resp := &api.ApiUserAccessResponse{}
+	rr := f.request("GET", "/api/user/"+userId+"/access?url="+url.QueryEscape(u), nil, cookie, resp)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	return resp
+}
+
+func TestUserAccess(t *testing.T) {
+	setup := func(t *testing.T) (*Fixture, *http.Cookie, string) {
+		f := CreateFixture(t)
+		adminCookie, _ := f.CreateAdmin("admin@example.com")
+		_, userId := f.CreateUserGetId("user@example.com")
+		rr := f.CreateBackend(adminCookie, &api.ApiBackend{Fqdn: "app.example.com", UpstreamUrl: "http://localhost:8080"})
+		require.Equal(t, http.StatusOK, rr.Code)
+		return f, adminCookie, userId
+	}
+	allowHost := func(t *testing.T, f *Fixture, userId, host string) {
+		err := f.Db.UpdateUser(userId, func(old *models.User) (*models.User, error) {
+			old.AllowedHosts = append(old.AllowedHosts, host)
+			return old, nil
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("denies hosts that aren't allowed", func(t *testing.T) {
+		f, adminCookie, userId := setup(t)
+
+		resp := f.checkAccess(t, adminCookie, userId, "https://app.example.com/admin")
+		assert.False(t, resp.Allowed)
+		assert.Equal(t, "app.example.com", resp.Host)
+		assert.Contains(t, resp.Reason, "not one of the user's allowed hosts")
+	})
+
+	t.Run("allows allowed hosts", func(t *testing.T) {
+		f, adminCookie, userId := setup(t)
+		allowHost(t, f, userId, "app.example.com")
+
+		resp := f.checkAccess(t, adminCookie, userId, "app.example.com/admin")
+		assert.True(t, resp.Allowed)
+		assert.Equal(t, "https://app.example.com/admin", resp.Url)
+		assert.Contains(t, resp.Reason, "is one of the user's allowed hosts")
+		assert.Empty(t, resp.Notes)
+	})
+
+	t.Run("explains backend requirements", func(t *testing.T) {
+		f, adminCookie, userId := setup(t)
+		allowHost(t, f, userId, "app.example.com")
+		backend, err := f.Db.GetBackend("app.example.com")
+		require.NoError(t, err)
+		backend.MaxAuthAge = durationpb.New(time.Hour)
+		backend.SessionPolicy = &models.SessionPolicy{IdleTimeout: durationpb.New(time.Hour)}
+		require.NoError(t, f.Db.UpdateBackend(backend.Fqdn, func(old *models.Backend) (*models.Backend, error) {
+			return backend, nil
+		}))
+
+		resp := f.checkAccess(t, adminCookie, userId, "https://app.example.com/")
+		assert.True(t, resp.Allowed)
+		assert.Len(t, resp.Notes, 2)
+	})
+
+	t.Run("denies disabled users", func(t *testing.T) {
+		f, adminCookie, userId := setup(t)
+		allowHost(t, f, userId, "app.example.com")
+		err := f.Db.UpdateUser(userId, func(old *models.User) (*models.User, error) {
+			old.IsDisabled = true
+			return old, nil
+		})
+		require.NoError(t, err)
+
+		resp := f.checkAccess(t, adminCookie, userId, "https://app.example.com/")
+		assert.False(t, resp.Allowed)
+		assert.Equal(t, "The user is disabled", resp.Reason)
+	})
+
+	t.Run("allows public backends", func(t *testing.T) {
+		f, adminCookie, userId := setup(t)
+		rr := f.CreateBackend(adminCookie, &api.ApiBackend{Fqdn: "public.example.com", UpstreamUrl: "http://localhost:8081", AccessLevel: "PUBLIC"})
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		resp := f.checkAccess(t, adminCookie, userId, "https://public.example.com/")
+		assert.True(t, resp.Allowed)
+		assert.Contains(t, resp.Reason, "public")
+	})
+
+	t.Run("explains unknown hosts", func(t *testing.T) {
+		f, adminCookie, userId := setup(t)
+
+		resp := f.checkAccess(t, adminCookie, userId, "https://unknown.example.com/")
+		assert.False(t, resp.Allowed)
+		assert.Contains(t, resp.Reason, "There is no backend")
+	})
+
+	t.Run("rejects invalid URLs", func(t *testing.T) {
+		f, adminCookie, userId := setup(t)
+
+		rr := f.request("GET", "/api/user/"+userId+"/access?url=", nil, adminCookie, nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("is forbidden for non-admin user", func(t *testing.T) {
+		f, _, userId := setup(t)
+		cookie, _ := f.CreateUser("other@example.com")
+
+		rr := f.request("GET", "/api/user/"+userId+"/access?url=app.example.com", nil, cookie, nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+}
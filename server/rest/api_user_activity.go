@@ -0,0 +1,49 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+func (s *ApiModule) handleUserActivity(w http.ResponseWriter, r *http.Request) {
+	sessionUser, _, err := s.session.GetAndValidate(w, r)
+	if err != nil {
+		return
+	}
+
+	userId := mux.Vars(r)["id"]
+	if userId == "me" {
+		userId = sessionUser.Id
+	}
+	if userId != sessionUser.Id && !sessionUser.IsAdmin {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	if _, err := s.db.GetUserById(userId); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	activity := make([]api.ApiSessionActivity, 0)
+	for _, session := range s.db.ListSessions(userId) {
+		for _, access := range session.AccessHistory {
+			activity = append(activity, api.ApiSessionActivity{
+				SessionID:       session.Id,
+				FirstAccessedAt: access.FirstAccessedAt.AsTime().Format(time.RFC3339),
+				LastAccessedAt:  access.LastAccessedAt.AsTime().Format(time.RFC3339),
+				RemoteAddr:      access.RemoteAddr,
+				UserAgent:       access.UserAgent,
+			})
+		}
+	}
+	sort.SliceStable(activity, func(i, j int) bool {
+		return activity[i].LastAccessedAt > activity[j].LastAccessedAt
+	})
+
+	jsonify(w, api.ApiUserActivityResponse{Activity: activity})
+}
@@ -0,0 +1,107 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/session"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (f *Fixture) recordAccesses(t *testing.T, userId string, accesses ...session.Access) {
+	t.Helper()
+	sessions := f.Db.ListSessions(userId)
+	require.Len(t, sessions, 1)
+	err := f.Db.UpdateSession(sessions[0].Id, func(old *models.Session) (*models.Session, error) {
+		for _, access := range accesses {
+			session.RecordAccess(old, access)
+		}
+		return old, nil
+	})
+	require.NoError(t, err)
+}
+
+func TestUserActivity(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("lists recent activity", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, userId := f.CreateUserGetId("test@example.com")
+
+		f.recordAccesses(t, userId,
+			session.Access{AccessedAt: start, RemoteAddr: "192.0.2.1", UserAgent: "Firefox"},
+			session.Access{AccessedAt: start.Add(time.Minute), RemoteAddr: "192.0.2.1", UserAgent: "Firefox"},
+			session.Access{AccessedAt: start.Add(time.Hour), RemoteAddr: "198.51.100.7", UserAgent: "curl"},
+		)
+
+		resp := &api.ApiUserActivityResponse{}
+		rr := f.request("GET", "/api/user/me/activity", nil, cookie, resp)
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Len(t, resp.Activity, 2)
+		assert.Equal(t, "198.51.100.7", resp.Activity[0].RemoteAddr)
+		assert.Equal(t, "curl", resp.Activity[0].UserAgent)
+		assert.Equal(t, "192.0.2.1", resp.Activity[1].RemoteAddr)
+		assert.Equal(t, start.Format(time.RFC3339), resp.Activity[1].FirstAccessedAt)
+		assert.Equal(t, start.Add(time.Minute).Format(time.RFC3339), resp.Activity[1].LastAccessedAt)
+
+		user := f.getUser(cookie, "me")
+		require.Len(t, user.Sessions, 1)
+		assert.Equal(t, "198.51.100.7", user.Sessions[0].LastRemoteAddr)
+		assert.Equal(t, "curl", user.Sessions[0].LastUserAgent)
+		assert.Equal(t, start.Add(time.Hour).Format(time.RFC3339), user.Sessions[0].AccessedAt)
+	})
+
+	t.Run("history is bounded", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, userId := f.CreateUserGetId("test@example.com")
+
+		var accesses []session.Access
+		for i := 0; i < 30; i++ {
+			accesses = append(accesses, session.Access{
+				AccessedAt: start.Add(time.Duration(i) * time.Minute),
+				RemoteAddr: "192.0.2.1",
+				UserAgent:  "Agent " + string(rune('A'+i)),
+			})
+		}
+		f.recordAccesses(t, userId, accesses...)
+
+		resp := &api.ApiUserActivityResponse{}
+		rr := f.request("GET", "/api/user/me/activity", nil, cookie, resp)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Len(t, resp.Activity, 20)
+		assert.Equal(t, accesses[29].UserAgent, resp.Activity[0].UserAgent)
+	})
+
+	t.Run("admin can view other users", func(t *testing.T) {
+		f := CreateFixture(t)
+		adminCookie, _ := f.CreateAdmin("admin@example.com")
+		_, userId := f.CreateUserGetId("user@example.com")
+		f.recordAccesses(t, userId, session.Access{AccessedAt: start, RemoteAddr: "192.0.2.1", UserAgent: "Firefox"})
+
+		resp := &api.ApiUserActivityResponse{}
+		rr := f.request("GET", "/api/user/"+userId+"/activity", nil, adminCookie, resp)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Len(t, resp.Activity, 1)
+	})
+
+	t.Run("users can't view other users", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("user.a@example.com")
+		_, otherId := f.CreateUserGetId("user.b@example.com")
+
+		rr := f.request("GET", "/api/user/"+otherId+"/activity", nil, cookie, nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("returns not found for non-existent user", func(t *testing.T) {
+		f := CreateFixture(t)
+		adminCookie, _ := f.CreateAdmin("admin@example.com")
+
+		rr := f.request("GET", "/api/user/non-existent/activity", nil, adminCookie, nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
@@ -2,11 +2,13 @@ package rest
 
 import (
 	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/auth"
+	"boivie/ubergang/server/mail"
 	"net/http"
 )
 
 func (s *ApiModule) handleUserCreate(w http.ResponseWriter, r *http.Request) {
-	user, _, err := s.session.GetAndValidate(w, r)
+	user, session, err := s.session.GetAndValidate(w, r)
 	if err != nil {
 		return
 	}
@@ -27,14 +29,26 @@ func (s *ApiModule) handleUserCreate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	newUser, _, err := s.auth.CreateUser(req.Email, req.Email, false, []string{})
+	newUser, pollId, err := s.auth.CreateUser(req.Email, req.Email, false, []string{})
 	if err != nil {
 		s.log.Warnf("Failed to create user: %v", err)
 		http.Error(w, "Failed to create user", http.StatusBadRequest)
 		return
 	}
+	s.audit(r, user, session, "user.create", newUser.Id, nil, newUser)
+
+	invitationSent := false
+	if req.SendInvitation {
+		err = s.mailer.Send(newUser.Email, mail.Invitation(s.config.Get().AdminFqdn, s.signinUrl(pollId), auth.InvitationLifetime))
+		if err != nil {
+			s.log.Warnf("Failed to send invitation to user %s: %v", newUser.Id, err)
+		} else {
+			invitationSent = true
+		}
+	}
 
 	jsonify(w, api.ApiCreateUserResponse{
-		ID: newUser.Id,
+		ID:             newUser.Id,
+		InvitationSent: invitationSent,
 	})
 }
@@ -75,4 +75,43 @@ func TestCreateUser(t *testing.T) {
 		rr := f.request("POST", "/api/user", req, nil, nil)
 		assert.Equal(t, http.StatusForbidden, rr.Code)
 	})
+
+	t.Run("sends invitation", func(t *testing.T) {
+		f := CreateFixture(t)
+		server := f.startMailServer(t)
+		adminCookie, _ := f.CreateAdmin("admin@example.com")
+
+		req := api.ApiCreateUserRequest{
+			Email:          "newuser@example.com",
+			SendInvitation: true,
+		}
+
+		var resp api.ApiCreateUserResponse
+		rr := f.request("POST", "/api/user", req, adminCookie, &resp)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.True(t, resp.InvitationSent)
+
+		messages := server.Messages()
+		require.Len(t, messages, 1)
+		assert.Equal(t, []string{"newuser@example.com"}, messages[0].To)
+		token := signinTokenFromEmail(t, messages[0])
+		cookie := f.SigninFromConfirmedPollId(token)
+		assert.Equal(t, resp.ID, f.getUser(cookie, "me").ID)
+	})
+
+	t.Run("creates user if invitation can't be sent", func(t *testing.T) {
+		f := CreateFixture(t)
+		adminCookie, _ := f.CreateAdmin("admin@example.com")
+
+		req := api.ApiCreateUserRequest{
+			Email:          "newuser@example.com",
+			SendInvitation: true,
+		}
+
+		var resp api.ApiCreateUserResponse
+		rr := f.request("POST", "/api/user", req, adminCookie, &resp)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.NotEmpty(t, resp.ID)
+		assert.False(t, resp.InvitationSent)
+	})
 }
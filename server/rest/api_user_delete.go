@@ -7,7 +7,7 @@ import (
 )
 
 func (s *ApiModule) handleUserDelete(w http.ResponseWriter, r *http.Request) {
-	user, _, err := s.session.GetAndValidate(w, r)
+	user, session, err := s.session.GetAndValidate(w, r)
 	if err != nil {
 		return
 	}
@@ -24,12 +24,19 @@ func (s *ApiModule) handleUserDelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	before, err := s.db.GetUserById(userId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
 	err = s.db.DeleteUser(userId)
 
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusNotFound)
 		return
 	}
+	s.audit(r, user, session, "user.delete", userId, before, nil)
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -0,0 +1,61 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/notify"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// How long an administrator may view the site as another user before having
+// to start over.
+const ImpersonationLifetime = 15 * time.Minute
+
+func (s *ApiModule) handleUserImpersonate(w http.ResponseWriter, r *http.Request) {
+	sessionUser, session, err := s.session.GetAndValidateSession(w, r)
+	if err != nil {
+		return
+	}
+
+	if !sessionUser.IsAdmin {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	userId := mux.Vars(r)["id"]
+	if userId == sessionUser.Id {
+		http.Error(w, "Can't view the site as yourself", http.StatusBadRequest)
+		return
+	}
+
+	user, err := s.db.GetUserById(userId)
+	if err != nil {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+	if user.IsDisabled {
+		http.Error(w, "User is disabled", http.StatusBadRequest)
+		return
+	}
+
+	impersonation, err := s.auth.CreateImpersonationSession(user.Id, session, r.UserAgent(), r.RemoteAddr, ImpersonationLifetime)
+	if err != nil {
+		s.log.Warnf("Failed to create impersonation session for user %s: %v", userId, err)
+		http.Error(w, "Failed to create session", http.StatusInternalServerError)
+		return
+	}
+
+	s.log.Infof("User %s started viewing the site as user %s", sessionUser.Id, userId)
+	s.audit(r, sessionUser, session, "user.impersonate", userId, nil, impersonation)
+
+	event := notify.NewEvent(notify.EventImpersonated, user, s.events.ClientIP(r), r.UserAgent())
+	event.Name = sessionUser.Email
+	s.notifier.Notify(event)
+
+	jsonify(w, api.ApiUserImpersonateResponse{
+		Cookie:    s.session.CreateSessionCookie(impersonation).String(),
+		ExpiresAt: impersonation.ExpiresAt.AsTime().Format(time.RFC3339),
+	})
+}
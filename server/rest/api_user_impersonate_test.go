@@ -0,0 +1,155 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/notify"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// impersonate makes the administrator using `cookie` view the site as
+// `userId`, and returns the cookie to do so.
+func (f *Fixture) impersonate(t *testing.T, cookie *http.Cookie, userId string) *http.Cookie {
+	t.Helper()
+	resp := &api.ApiUserImpersonateResponse{}
+	rr := f.request("POST", "/api/user/"+userId+"/impersonate", nil, cookie, resp)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	cookies := (&http.Response{Header: http.Header{"Set-Cookie": {resp.Cookie}}}).Cookies()
+	require.NotEmpty(t, cookies)
+	return cookies[0]
+}
+
+func TestUserImpersonate(t *testing.T) {
+	t.Run("views the site as the user", func(t *testing.T) {
+		f := CreateFixture(t)
+		adminCookie, _ := f.CreateAdmin("admin@example.com")
+		_, userId := f.CreateUserGetId("user@example.com")
+
+		cookie := f.impersonate(t, adminCookie, userId)
+
+		me := f.getUser(cookie, "me")
+		assert.Equal(t, userId, me.ID)
+		require.NotNil(t, me.Impersonation)
+		assert.Equal(t, "admin@example.com", me.Impersonation.ImpersonatorEmail)
+		expiresAt, err := time.Parse(time.RFC3339, me.Impersonation.ExpiresAt)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(ImpersonationLifetime), expiresAt, time.Minute)
+
+		// The administrator's own session is still there.
+		assert.Nil(t, f.getUser(adminCookie, "me").Impersonation)
+	})
+
+	t.Run("can't make changes", func(t *testing.T) {
+		f := CreateFixture(t)
+		adminCookie, _ := f.CreateAdmin("admin@example.com")
+		_, userId := f.CreateUserGetId("user@example.com")
+		cookie := f.impersonate(t, adminCookie, userId)
+
+		rr := f.request("POST", "/api/ssh-key", &api.ApiCreateSshKeyRequest{Name: "key"}, cookie, nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		assert.Empty(t, f.Db.ListSshKeys(userId))
+	})
+
+	t.Run("expires", func(t *testing.T) {
+		f := CreateFixture(t)
+		adminCookie, _ := f.CreateAdmin("admin@example.com")
+		_, userId := f.CreateUserGetId("user@example.com")
+		cookie := f.impersonate(t, adminCookie, userId)
+
+		for _, session := range f.Db.ListSessions(userId) {
+			if session.ImpersonatorId == "" {
+				continue
+			}
+			err := f.Db.UpdateSession(session.Id, func(old *models.Session) (*models.Session, error) {
+				old.ExpiresAt = timestamppb.New(time.Now().Add(-time.Second))
+				return old, nil
+			})
+			require.NoError(t, err)
+		}
+
+		rr := f.request("GET", "/api/user/me", nil, cookie, nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("ends when the administrator is no longer one", func(t *testing.T) {
+		f := CreateFixture(t)
+		adminCookie, adminId := f.CreateAdminGetId("admin@example.com")
+		_, userId := f.CreateUserGetId("user@example.com")
+		cookie := f.impersonate(t, adminCookie, userId)
+
+		err := f.Db.UpdateUser(adminId, func(old *models.User) (*models.User, error) {
+			old.IsAdmin = false
+			return old, nil
+		})
+		require.NoError(t, err)
+
+		rr := f.request("GET", "/api/user/me", nil, cookie, nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("is audited and notifies the user", func(t *testing.T) {
+		f := CreateFixture(t)
+		adminCookie, _ := f.CreateAdmin("admin@example.com")
+		_, userId := f.CreateUserGetId("user@example.com")
+		recorder := f.RecordNotifications()
+
+		f.impersonate(t, adminCookie, userId)
+
+		records := f.Db.ListAuditRecords(func(record *models.AuditRecord) bool {
+			return record.Action == "user.impersonate"
+		}, 10)
+		require.Len(t, records, 1)
+		assert.Equal(t, userId, records[0].TargetId)
+
+		events := recorder.Events()
+		require.Len(t, events, 1)
+		assert.Equal(t, notify.EventImpersonated, events[0].Type)
+		assert.Equal(t, "user@example.com", events[0].UserEmail)
+		assert.Equal(t, "admin@example.com", events[0].Name)
+	})
+
+	t.Run("is forbidden for non-admin user", func(t *testing.T) {
+		f := CreateFixture(t)
+		cookie, _ := f.CreateUser("user@example.com")
+		_, otherId := f.CreateUserGetId("other@example.com")
+
+		rr := f.request("POST", "/api/user/"+otherId+"/impersonate", nil, cookie, nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("can't view the site as yourself", func(t *testing.T) {
+		f := CreateFixture(t)
+		adminCookie, adminId := f.CreateAdminGetId("admin@example.com")
+
+		rr := f.request("POST", "/api/user/"+adminId+"/impersonate", nil, adminCookie, nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("can't view the site as a disabled user", func(t *testing.T) {
+		f := CreateFixture(t)
+		adminCookie, _ := f.CreateAdmin("admin@example.com")
+		_, userId := f.CreateUserGetId("user@example.com")
+		err := f.Db.UpdateUser(userId, func(old *models.User) (*models.User, error) {
+			old.IsDisabled = true
+			return old, nil
+		})
+		require.NoError(t, err)
+
+		rr := f.request("POST", "/api/user/"+userId+"/impersonate", nil, adminCookie, nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("returns not found for non-existent user", func(t *testing.T) {
+		f := CreateFixture(t)
+		adminCookie, _ := f.CreateAdmin("admin@example.com")
+
+		rr := f.request("POST", "/api/user/non-existent-id/impersonate", nil, adminCookie, nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
@@ -10,28 +10,64 @@ import (
 	"time"
 )
 
-func ToApiCredential(c *models.Credential) api.ApiCredential {
+// The names of the TOTP policies in the API.
+var totpPolicyNames = map[models.TotpPolicy]string{
+	models.TotpPolicy_TOTP_POLICY_DISABLED: "disabled",
+	models.TotpPolicy_TOTP_POLICY_FALLBACK: "fallback",
+	models.TotpPolicy_TOTP_POLICY_REQUIRED: "required",
+}
+
+func ToApiCredential(c *models.Credential, policy *models.PasskeyPolicy) api.ApiCredential {
+	credentialType := "webauthn"
+	if c.GetTotpCredential() != nil {
+		credentialType = "totp"
+	}
 	return api.ApiCredential{
 		ID:         c.Id,
 		Name:       c.Name,
-		Type:       "webauthn",
+		Type:       credentialType,
 		CreatedAt:  c.CreatedAt.AsTime().Format(time.RFC3339),
 		CreatedBy:  c.CreatedBySessionId,
 		LastUsedAt: c.LastUsedAt.AsTime().Format(time.RFC3339),
 		UsedBy:     c.UsedBySessionIds,
-		Transports: c.GetWebauthnCredential().Transports,
-		Aaguid:     wa.FormatAaguidBytesToString(c.GetWebauthnCredential().Aaguid),
+		Transports: c.GetWebauthnCredential().GetTransports(),
+		Aaguid:     wa.FormatAaguidBytesToString(c.GetWebauthnCredential().GetAaguid()),
+
+		CloneWarning:     c.GetWebauthnCredential().GetCloneWarning(),
+		PolicyViolations: wa.CredentialViolations(policy, c),
+		Blocked:          wa.IsBlocked(policy, c),
+		Attachment:       c.GetWebauthnCredential().GetAttachment(),
+		RpId:             c.GetWebauthnCredential().GetRpId(),
 	}
 }
 
 func ToApiSession(s *models.Session) api.ApiSession {
+	accessedAt := ""
+	if s.AccessedAt != nil {
+		accessedAt = s.AccessedAt.AsTime().Format(time.RFC3339)
+	}
 	return api.ApiSession{
-		ID:         s.Id,
-		UserAgent:  s.UserAgent,
-		RemoteAddr: s.RemoteAddr,
-		CreatedAt:  s.CreatedAt.AsTime().Format(time.RFC3339),
-		AccessedAt: "",
+		ID:             s.Id,
+		UserAgent:      s.UserAgent,
+		RemoteAddr:     s.RemoteAddr,
+		CreatedAt:      s.CreatedAt.AsTime().Format(time.RFC3339),
+		AccessedAt:     accessedAt,
+		LastUserAgent:  s.LastUserAgent,
+		LastRemoteAddr: s.LastRemoteAddr,
+	}
+}
+
+func ToApiFederatedIdentity(identity *models.FederatedIdentity) api.ApiFederatedIdentity {
+	ret := api.ApiFederatedIdentity{
+		ProviderId: identity.ProviderId,
+		Subject:    identity.Subject,
+		Email:      identity.Email,
+		LinkedAt:   identity.LinkedAt.AsTime().Format(time.RFC3339),
+	}
+	if identity.LastUsedAt != nil {
+		ret.LastUsedAt = identity.LastUsedAt.AsTime().Format(time.RFC3339)
 	}
+	return ret
 }
 
 func ToApiSshKey(key *models.SshKey) api.ApiSSHKey {
@@ -49,6 +85,7 @@ func ToApiSshKey(key *models.SshKey) api.ApiSSHKey {
 }
 
 func (s *ApiModule) handleUserList(w http.ResponseWriter, r *http.Request) {
+	config := s.config.Get()
 	user, _, err := s.session.GetAndValidate(w, r)
 	if err != nil {
 		return
@@ -62,25 +99,42 @@ func (s *ApiModule) handleUserList(w http.ResponseWriter, r *http.Request) {
 	users := make([]api.ApiUser, 0)
 	for _, u := range s.db.ListUsers() {
 		au := api.ApiUser{
-			ID:             u.Id,
-			Email:          u.Email,
-			DisplayName:    u.DisplayName,
-			AllowedHosts:   u.AllowedHosts,
-			IsAdmin:        u.IsAdmin,
-			Credentials:    make([]api.ApiCredential, 0),
-			Sessions:       make([]api.ApiSession, 0),
-			SSHKeys:        make([]api.ApiSSHKey, 0),
-			CurrentSession: nil,
+			ID:                  u.Id,
+			Email:               u.Email,
+			DisplayName:         u.DisplayName,
+			AllowedHosts:        u.AllowedHosts,
+			IsAdmin:             u.IsAdmin,
+			IsDisabled:          u.IsDisabled,
+			TotpPolicy:          totpPolicyNames[u.TotpPolicy],
+			Groups:              u.Groups,
+			Credentials:         make([]api.ApiCredential, 0),
+			Sessions:            make([]api.ApiSession, 0),
+			SSHKeys:             make([]api.ApiSSHKey, 0),
+			AccessTokens:        make([]api.ApiAccessToken, 0),
+			AppPasswords:        make([]api.ApiAppPassword, 0),
+			FederatedIdentities: make([]api.ApiFederatedIdentity, 0),
+			CurrentSession:      nil,
 		}
-		for _, c := range s.db.ListCredentials(u.Id) {
-			au.Credentials = append(au.Credentials, ToApiCredential(c))
+		credentials := s.db.ListCredentials(u.Id)
+		for _, c := range credentials {
+			au.Credentials = append(au.Credentials, ToApiCredential(c, config.PasskeyPolicy))
 		}
+		au.PasskeyPolicyViolations = wa.UserViolations(config.PasskeyPolicy, credentials)
 		for _, s := range s.db.ListSessions(u.Id) {
 			au.Sessions = append(au.Sessions, ToApiSession(s))
 		}
 		for _, key := range s.db.ListSshKeys(u.Id) {
 			au.SSHKeys = append(au.SSHKeys, ToApiSshKey(key))
 		}
+		for _, token := range s.db.ListAccessTokens(u.Id) {
+			au.AccessTokens = append(au.AccessTokens, ToApiAccessToken(token))
+		}
+		for _, identity := range u.FederatedIdentities {
+			au.FederatedIdentities = append(au.FederatedIdentities, ToApiFederatedIdentity(identity))
+		}
+		for _, appPassword := range s.db.ListAppPasswords(u.Id) {
+			au.AppPasswords = append(au.AppPasswords, ToApiAppPassword(appPassword))
+		}
 		users = append(users, au)
 	}
 
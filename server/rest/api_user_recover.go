@@ -2,9 +2,13 @@ package rest
 
 import (
 	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/auth"
 	"boivie/ubergang/server/common"
+	"boivie/ubergang/server/mail"
 	"boivie/ubergang/server/models"
-	"fmt"
+	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"time"
 
@@ -12,8 +16,11 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// How long recovery links are valid.
+const recoveryLifetime = 7 * 24 * time.Hour
+
 func (s *ApiModule) handleUserRecover(w http.ResponseWriter, r *http.Request) {
-	sessionUser, _, err := s.session.GetAndValidate(w, r)
+	sessionUser, session, err := s.session.GetAndValidate(w, r)
 	if err != nil {
 		return
 	}
@@ -29,38 +36,51 @@ func (s *ApiModule) handleUserRecover(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pollId := common.MakeSigninRequestToken()
-
-	err = s.db.UpdateUser(userId, func(user *models.User) (*models.User, error) {
-		if user == nil {
-			return nil, fmt.Errorf("user not found")
-		}
-
-		signinRequest := &models.SigninRequest{
-			Id:        pollId,
-			ExpiresAt: timestamppb.New(time.Now().Add(7 * 24 * time.Hour)),
-			Confirmed: true,
-		}
-
-		user.SigninRequests = append(user.SigninRequests, signinRequest)
-
-		return user, nil
-	})
+	var req api.ApiUserRecoverRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
 
+	user, err := s.db.GetUserById(userId)
 	if err != nil {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	pollId := common.MakeSigninRequestToken()
+	now := time.Now()
+	err = s.auth.AddSigninRequest(userId, &models.SigninRequest{
+		Id:        pollId,
+		ExpiresAt: timestamppb.New(now.Add(recoveryLifetime)),
+		Confirmed: true,
+	}, now)
+	if errors.Is(err, auth.ErrTooManySigninRequests) {
+		http.Error(w, "Too many recovery tokens", http.StatusTooManyRequests)
+		return
+	} else if err != nil {
 		s.log.Warnf("Failed to create recovery token for user %s: %v", userId, err)
-		if err.Error() == "user not found" {
-			http.Error(w, "User not found", http.StatusNotFound)
-		} else {
-			http.Error(w, "Failed to create recovery token", http.StatusInternalServerError)
-		}
+		http.Error(w, "Failed to create recovery token", http.StatusInternalServerError)
 		return
 	}
 
-	recoveryUrl := fmt.Sprintf("https://%s/signin/%s", s.config.AdminFqdn, pollId)
+	recoveryUrl := s.signinUrl(pollId)
 	s.log.Infof("Created recovery token for user %s", userId)
+	after, _ := s.db.GetUserById(userId)
+	s.audit(r, sessionUser, session, "user.recover", userId, user, after)
+
+	emailSent := false
+	if req.SendEmail {
+		err = s.mailer.Send(user.Email, mail.Recovery(s.config.Get().AdminFqdn, recoveryUrl, recoveryLifetime))
+		if err != nil {
+			s.log.Warnf("Failed to send recovery e-mail to user %s: %v", userId, err)
+		} else {
+			emailSent = true
+		}
+	}
 
 	jsonify(w, api.ApiUserRecoverResponse{
 		RecoveryUrl: recoveryUrl,
+		EmailSent:   emailSent,
 	})
 }
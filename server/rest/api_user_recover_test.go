@@ -149,4 +149,35 @@ func TestUserRecover(t *testing.T) {
 		assert.Equal(t, targetUserId, user.ID)
 		assert.Equal(t, "user@example.com", user.Email)
 	})
+
+	t.Run("sends recovery URL by e-mail", func(t *testing.T) {
+		f := CreateFixture(t)
+		server := f.startMailServer(t)
+		adminCookie, _ := f.CreateAdmin("admin@example.com")
+		_, targetUserId := f.CreateUserGetId("user@example.com")
+
+		var resp api.ApiUserRecoverResponse
+		req := api.ApiUserRecoverRequest{SendEmail: true}
+		rr := f.request("POST", "/api/user/"+targetUserId+"/recover", req, adminCookie, &resp)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.True(t, resp.EmailSent)
+
+		messages := server.Messages()
+		require.Len(t, messages, 1)
+		assert.Equal(t, []string{"user@example.com"}, messages[0].To)
+		assert.Contains(t, messages[0].Body, resp.RecoveryUrl)
+	})
+
+	t.Run("doesn't send e-mail unless asked to", func(t *testing.T) {
+		f := CreateFixture(t)
+		server := f.startMailServer(t)
+		adminCookie, _ := f.CreateAdmin("admin@example.com")
+		_, targetUserId := f.CreateUserGetId("user@example.com")
+
+		var resp api.ApiUserRecoverResponse
+		rr := f.request("POST", "/api/user/"+targetUserId+"/recover", nil, adminCookie, &resp)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.False(t, resp.EmailSent)
+		assert.Empty(t, server.Messages())
+	})
 }
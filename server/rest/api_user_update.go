@@ -7,10 +7,20 @@ import (
 	"net/http"
 
 	"github.com/gorilla/mux"
+	"google.golang.org/protobuf/proto"
 )
 
+func parseTotpPolicy(name string) (models.TotpPolicy, bool) {
+	for policy, n := range totpPolicyNames {
+		if n == name {
+			return policy, true
+		}
+	}
+	return models.TotpPolicy_TOTP_POLICY_DISABLED, false
+}
+
 func (s *ApiModule) handleUserUpdate(w http.ResponseWriter, r *http.Request) {
-	sessionUser, _, err := s.session.GetAndValidate(w, r)
+	sessionUser, session, err := s.session.GetAndValidate(w, r)
 	if err != nil {
 		return
 	}
@@ -42,10 +52,39 @@ func (s *ApiModule) handleUserUpdate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Non-admins cannot change groups
+	if !sessionUser.IsAdmin && req.Groups != nil {
+		http.Error(w, "Not authorized to change groups", http.StatusForbidden)
+		return
+	}
+
+	// Non-admins cannot disable users, and admins cannot disable themselves
+	if req.Disabled != nil && (!sessionUser.IsAdmin || isUpdatingSelf) {
+		http.Error(w, "Not authorized to disable user", http.StatusForbidden)
+		return
+	}
+
+	// Only admins can decide if users may use one-time passwords
+	var totpPolicy models.TotpPolicy
+	if req.TotpPolicy != nil {
+		if !sessionUser.IsAdmin {
+			http.Error(w, "Not authorized to change TOTP policy", http.StatusForbidden)
+			return
+		}
+		var found bool
+		totpPolicy, found = parseTotpPolicy(*req.TotpPolicy)
+		if !found {
+			http.Error(w, "Invalid TOTP policy", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var before, after *models.User
 	err = s.db.UpdateUser(userId, func(old *models.User) (*models.User, error) {
 		if old == nil {
 			return nil, fmt.Errorf("user not found")
 		}
+		before = proto.Clone(old).(*models.User)
 
 		if req.Email != nil {
 			old.Email = *req.Email
@@ -59,6 +98,16 @@ func (s *ApiModule) handleUserUpdate(w http.ResponseWriter, r *http.Request) {
 		if req.AllowedHosts != nil {
 			old.AllowedHosts = *req.AllowedHosts
 		}
+		if req.Groups != nil {
+			old.Groups = *req.Groups
+		}
+		if req.Disabled != nil {
+			old.IsDisabled = *req.Disabled
+		}
+		if req.TotpPolicy != nil {
+			old.TotpPolicy = totpPolicy
+		}
+		after = old
 		return old, nil
 	})
 
@@ -71,6 +120,7 @@ func (s *ApiModule) handleUserUpdate(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
+	s.audit(r, sessionUser, session, "user.update", userId, before, after)
 
 	jsonify(w, api.ApiUpdateUserResponse{})
 }
@@ -93,6 +93,125 @@ func TestUpdateUser(t *testing.T) {
 		assert.True(t, updatedUser.IsAdmin)
 	})
 
+	t.Run("updates user groups as admin", func(t *testing.T) {
+		f := CreateFixture(t)
+		adminCookie, _ := f.CreateAdmin("admin@example.com")
+		userCookie, userId := f.CreateUserGetId("user@example.com")
+
+		groups := []string{"family", "media"}
+		req := api.ApiUpdateUserRequest{
+			Groups: &groups,
+		}
+
+		rr := f.request("POST", "/api/user/"+userId, req, adminCookie, nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		user := f.getUser(userCookie, userId)
+		assert.Equal(t, groups, user.Groups)
+	})
+
+	t.Run("prevents regular user from changing their groups", func(t *testing.T) {
+		f := CreateFixture(t)
+		_, _ = f.CreateAdmin("admin@example.com")
+		userCookie, userId := f.CreateUserGetId("user@example.com")
+
+		groups := []string{"admins"}
+		req := api.ApiUpdateUserRequest{
+			Groups: &groups,
+		}
+
+		rr := f.request("POST", "/api/user/"+userId, req, userCookie, nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+
+		user := f.getUser(userCookie, userId)
+		assert.Empty(t, user.Groups)
+	})
+
+	t.Run("updates TOTP policy as admin", func(t *testing.T) {
+		f := CreateFixture(t)
+		adminCookie, _ := f.CreateAdmin("admin@example.com")
+		userCookie, userId := f.CreateUserGetId("user@example.com")
+
+		policy := "required"
+		req := api.ApiUpdateUserRequest{
+			TotpPolicy: &policy,
+		}
+
+		rr := f.request("POST", "/api/user/"+userId, req, adminCookie, nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		user := f.getUser(userCookie, userId)
+		assert.Equal(t, "required", user.TotpPolicy)
+	})
+
+	t.Run("rejects invalid TOTP policy", func(t *testing.T) {
+		f := CreateFixture(t)
+		adminCookie, _ := f.CreateAdmin("admin@example.com")
+		userCookie, userId := f.CreateUserGetId("user@example.com")
+
+		policy := "sometimes"
+		req := api.ApiUpdateUserRequest{
+			TotpPolicy: &policy,
+		}
+
+		rr := f.request("POST", "/api/user/"+userId, req, adminCookie, nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+		user := f.getUser(userCookie, userId)
+		assert.Equal(t, "disabled", user.TotpPolicy)
+	})
+
+	t.Run("prevents regular user from changing their TOTP policy", func(t *testing.T) {
+		f := CreateFixture(t)
+		_, _ = f.CreateAdmin("admin@example.com")
+		userCookie, userId := f.CreateUserGetId("user@example.com")
+
+		policy := "fallback"
+		req := api.ApiUpdateUserRequest{
+			TotpPolicy: &policy,
+		}
+
+		rr := f.request("POST", "/api/user/"+userId, req, userCookie, nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+
+		user := f.getUser(userCookie, userId)
+		assert.Equal(t, "disabled", user.TotpPolicy)
+	})
+
+	t.Run("disables user as admin", func(t *testing.T) {
+		f := CreateFixture(t)
+		adminCookie, _ := f.CreateAdmin("admin@example.com")
+		userCookie, userId := f.CreateUserGetId("user@example.com")
+
+		disabled := true
+		req := api.ApiUpdateUserRequest{
+			Disabled: &disabled,
+		}
+
+		rr := f.request("POST", "/api/user/"+userId, req, adminCookie, nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		user := f.getUser(adminCookie, userId)
+		assert.True(t, user.IsDisabled)
+
+		// The user's session can no longer be used.
+		rr = f.request("GET", "/api/user/me", nil, userCookie, nil)
+		assert.NotEqual(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("prevents admin from disabling themselves", func(t *testing.T) {
+		f := CreateFixture(t)
+		adminCookie, adminId := f.CreateAdminGetId("admin@example.com")
+
+		disabled := true
+		req := api.ApiUpdateUserRequest{
+			Disabled: &disabled,
+		}
+
+		rr := f.request("POST", "/api/user/"+adminId, req, adminCookie, nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
 	t.Run("demotes admin to regular user", func(t *testing.T) {
 		f := CreateFixture(t)
 		adminCookie, _ := f.CreateAdmin("admin@example.com")
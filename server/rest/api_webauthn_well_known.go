@@ -0,0 +1,15 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"net/http"
+)
+
+// handleWebauthnWellKnown lists the origins that may use passkeys created for
+// this relying party ID. Backends are only listed if related origins have been
+// enabled.
+func (s *ApiModule) handleWebauthnWellKnown(w http.ResponseWriter, r *http.Request) {
+	jsonify(w, api.ApiWebauthnWellKnownResponse{
+		Origins: s.webauthn.WellKnownOrigins(),
+	})
+}
@@ -0,0 +1,46 @@
+package rest
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/models"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebauthnWellKnown(t *testing.T) {
+	f := CreateFixture(t)
+	cookie, _ := f.CreateAdmin("admin@example.com")
+	rr := f.CreateBackend(cookie, &api.ApiBackend{Fqdn: "grafana.example.com", UpstreamUrl: "http://localhost:3000"})
+	require.Equal(t, http.StatusOK, rr.Code)
+	rr = f.CreateBackend(cookie, &api.ApiBackend{Fqdn: "public.example.com", UpstreamUrl: "http://localhost:3001", AccessLevel: "PUBLIC"})
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	wellKnown := func() []string {
+		resp := &api.ApiWebauthnWellKnownResponse{}
+		rr := f.request("GET", "/.well-known/webauthn", nil, nil, resp)
+		require.Equal(t, http.StatusOK, rr.Code)
+		return resp.Origins
+	}
+
+	t.Run("lists only the admin origin by default", func(t *testing.T) {
+		assert.Equal(t, []string{"https://test.example.com"}, wellKnown())
+	})
+
+	t.Run("requires the site's relying party ID for related origins", func(t *testing.T) {
+		f.Config.Update(func(c *models.Configuration) {
+			c.Webauthn = &models.WebauthnSettings{RelatedOrigins: true}
+		})
+		assert.Equal(t, []string{"https://test.example.com"}, wellKnown())
+	})
+
+	t.Run("lists backends that aren't public", func(t *testing.T) {
+		f.Config.Update(func(c *models.Configuration) {
+			c.SiteFqdn = "example.com"
+			c.Webauthn = &models.WebauthnSettings{UseSiteRpId: true, RelatedOrigins: true}
+		})
+		assert.Equal(t, []string{"https://test.example.com", "https://grafana.example.com"}, wellKnown())
+	})
+}
@@ -0,0 +1,363 @@
+package scim
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Filter is a parsed filter expression, as described in RFC 7644, section
+// 3.4.2.2. It's evaluated against a resource in its generic form, see ToMap.
+type Filter interface {
+	Matches(resource map[string]interface{}) bool
+}
+
+type andFilter struct{ left, right Filter }
+
+func (f *andFilter) Matches(resource map[string]interface{}) bool {
+	return f.left.Matches(resource) && f.right.Matches(resource)
+}
+
+type orFilter struct{ left, right Filter }
+
+func (f *orFilter) Matches(resource map[string]interface{}) bool {
+	return f.left.Matches(resource) || f.right.Matches(resource)
+}
+
+type notFilter struct{ inner Filter }
+
+func (f *notFilter) Matches(resource map[string]interface{}) bool {
+	return !f.inner.Matches(resource)
+}
+
+type presentFilter struct{ path string }
+
+func (f *presentFilter) Matches(resource map[string]interface{}) bool {
+	for _, v := range resolve(resource, f.path) {
+		if s, ok := v.(string); !ok || s != "" {
+			return true
+		}
+	}
+	return false
+}
+
+type compareFilter struct {
+	path  string
+	op    string
+	value interface{}
+}
+
+func (f *compareFilter) Matches(resource map[string]interface{}) bool {
+	for _, v := range resolve(resource, f.path) {
+		if compare(v, f.op, f.value) {
+			return true
+		}
+	}
+	return false
+}
+
+func compare(actual interface{}, op string, expected interface{}) bool {
+	switch a := actual.(type) {
+	case string:
+		e, ok := expected.(string)
+		if !ok {
+			return false
+		}
+		// All supported attributes are case insensitive.
+		a, e = strings.ToLower(a), strings.ToLower(e)
+		switch op {
+		case "eq":
+			return a == e
+		case "ne":
+			return a != e
+		case "co":
+			return strings.Contains(a, e)
+		case "sw":
+			return strings.HasPrefix(a, e)
+		case "ew":
+			return strings.HasSuffix(a, e)
+		case "gt":
+			return a > e
+		case "ge":
+			return a >= e
+		case "lt":
+			return a < e
+		case "le":
+			return a <= e
+		}
+	case bool:
+		e, ok := expected.(bool)
+		if !ok {
+			return false
+		}
+		switch op {
+		case "eq":
+			return a == e
+		case "ne":
+			return a != e
+		}
+	case float64:
+		e, ok := expected.(float64)
+		if !ok {
+			return false
+		}
+		switch op {
+		case "eq":
+			return a == e
+		case "ne":
+			return a != e
+		case "gt":
+			return a > e
+		case "ge":
+			return a >= e
+		case "lt":
+			return a < e
+		case "le":
+			return a <= e
+		}
+	}
+	return false
+}
+
+// splitSchema splits an attribute path that is prefixed with a schema URN,
+// such as "urn:ietf:params:scim:schemas:core:2.0:User:userName", into the
+// schema and the attribute path.
+func splitSchema(path string) (string, string) {
+	if !strings.HasPrefix(strings.ToLower(path), "urn:") {
+		return "", path
+	}
+	i := strings.LastIndex(path, ":")
+	schema := path[:i]
+	if strings.EqualFold(schema, SchemaUser) || strings.EqualFold(schema, SchemaGroup) {
+		// Attributes of the core schemas are found at the top level.
+		schema = ""
+	}
+	return schema, path[i+1:]
+}
+
+// resolve returns all values that an attribute path, such as "emails.value",
+// refers to. Multi-valued attributes are flattened.
+func resolve(resource map[string]interface{}, path string) []interface{} {
+	schema, attrPath := splitSchema(path)
+	current := []interface{}{resource}
+	if schema != "" {
+		_, container, _ := lookup(resource, schema)
+		current = []interface{}{container}
+	}
+	for _, part := range strings.Split(attrPath, ".") {
+		var next []interface{}
+		for _, v := range current {
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			_, value, found := lookup(m, part)
+			if !found || value == nil {
+				continue
+			}
+			if values, ok := value.([]interface{}); ok {
+				next = append(next, values...)
+			} else {
+				next = append(next, value)
+			}
+		}
+		current = next
+	}
+	return current
+}
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenString
+	tokenOpen
+	tokenClose
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenOpen})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenClose})
+			i++
+		case r == '"':
+			j := i + 1
+			for ; j < len(runes) && runes[j] != '"'; j++ {
+				if runes[j] == '\\' {
+					j++
+				}
+			}
+			if j >= len(runes) {
+				return nil, errorf(ErrInvalidFilter, "unterminated string")
+			}
+			var value string
+			if err := json.Unmarshal([]byte(string(runes[i:j+1])), &value); err != nil {
+				return nil, errorf(ErrInvalidFilter, "invalid string: %v", err)
+			}
+			tokens = append(tokens, token{kind: tokenString, value: value})
+			i = j + 1
+		default:
+			j := i
+			for j < len(runes) && !unicode.IsSpace(runes[j]) && runes[j] != '(' && runes[j] != ')' {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenWord, value: string(runes[i:j])})
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+type filterParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *filterParser) peekWord(word string) bool {
+	return p.pos < len(p.tokens) && p.tokens[p.pos].kind == tokenWord && strings.EqualFold(p.tokens[p.pos].value, word)
+}
+
+func (p *filterParser) next() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	p.pos++
+	return p.tokens[p.pos-1], true
+}
+
+func (p *filterParser) parseOr() (Filter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekWord("or") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orFilter{left, right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (Filter, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekWord("and") {
+		p.pos++
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = &andFilter{left, right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseTerm() (Filter, error) {
+	if p.peekWord("not") {
+		p.pos++
+		inner, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		return &notFilter{inner}, nil
+	}
+	t, ok := p.next()
+	if !ok {
+		return nil, errorf(ErrInvalidFilter, "unexpected end of filter")
+	}
+	if t.kind == tokenOpen {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t, ok := p.next(); !ok || t.kind != tokenClose {
+			return nil, errorf(ErrInvalidFilter, "missing ')'")
+		}
+		return inner, nil
+	}
+	if t.kind != tokenWord {
+		return nil, errorf(ErrInvalidFilter, "expected attribute")
+	}
+	path := t.value
+
+	t, ok = p.next()
+	if !ok || t.kind != tokenWord {
+		return nil, errorf(ErrInvalidFilter, "expected operator after %s", path)
+	}
+	op := strings.ToLower(t.value)
+	switch op {
+	case "pr":
+		return &presentFilter{path}, nil
+	case "eq", "ne", "co", "sw", "ew", "gt", "ge", "lt", "le":
+	default:
+		return nil, errorf(ErrInvalidFilter, "unsupported operator %s", t.value)
+	}
+
+	t, ok = p.next()
+	if !ok {
+		return nil, errorf(ErrInvalidFilter, "expected value after %s %s", path, op)
+	}
+	var value interface{}
+	switch {
+	case t.kind == tokenString:
+		value = t.value
+	case t.kind == tokenWord && t.value == "true":
+		value = true
+	case t.kind == tokenWord && t.value == "false":
+		value = false
+	case t.kind == tokenWord && t.value == "null":
+		// "eq null" is the same as not present.
+		if op == "eq" {
+			return &notFilter{&presentFilter{path}}, nil
+		} else if op == "ne" {
+			return &presentFilter{path}, nil
+		}
+		return nil, errorf(ErrInvalidFilter, "null can only be compared for equality")
+	case t.kind == tokenWord:
+		number, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return nil, errorf(ErrInvalidFilter, "invalid value %s", t.value)
+		}
+		value = number
+	default:
+		return nil, errorf(ErrInvalidFilter, "expected value after %s %s", path, op)
+	}
+	return &compareFilter{path: path, op: op, value: value}, nil
+}
+
+// ParseFilter parses a filter expression, such as `userName eq "a@b.com"`.
+func ParseFilter(s string) (Filter, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{tokens: tokens}
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.tokens) {
+		return nil, errorf(ErrInvalidFilter, "unexpected trailing input")
+	}
+	return f, nil
+}
@@ -0,0 +1,71 @@
+package scim
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilter(t *testing.T) {
+	user, err := ToMap(&User{
+		Schemas:     []string{SchemaUser, SchemaUserExtension},
+		ID:          "abc",
+		UserName:    "Alice@Example.com",
+		DisplayName: "Alice",
+		Emails:      []Email{{Value: "alice@example.com", Type: "work", Primary: true}},
+		Extension:   &UserExtension{IsAdmin: true},
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		filter  string
+		matches bool
+	}{
+		{`userName eq "alice@example.com"`, true},
+		{`UserName Eq "ALICE@EXAMPLE.COM"`, true},
+		{`userName eq "bob@example.com"`, false},
+		{`userName ne "bob@example.com"`, true},
+		{`displayName sw "Al"`, true},
+		{`displayName ew "ce"`, true},
+		{`displayName co "lic"`, true},
+		{`emails.value eq "alice@example.com"`, true},
+		{`emails.type eq "home"`, false},
+		{`externalId pr`, false},
+		{`externalId eq null`, true},
+		{`urn:ietf:params:scim:schemas:core:2.0:User:userName eq "alice@example.com"`, true},
+		{`urn:ietf:params:scim:schemas:extension:ubergang:2.0:User:isAdmin eq true`, true},
+		{`userName eq "bob@example.com" or displayName eq "Alice"`, true},
+		{`userName eq "bob@example.com" or displayName eq "Alice" and id eq "x"`, false},
+		{`(userName eq "bob@example.com" or displayName eq "Alice") and id eq "abc"`, true},
+		{`not (id eq "abc")`, false},
+		{`displayName eq "with \"quotes\""`, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.filter, func(t *testing.T) {
+			f, err := ParseFilter(tc.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tc.matches, f.Matches(user))
+		})
+	}
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	for _, filter := range []string{
+		``,
+		`userName`,
+		`userName eq`,
+		`userName xx "a"`,
+		`userName eq "a`,
+		`(userName eq "a"`,
+		`userName eq "a" extra`,
+		`userName gt null`,
+	} {
+		t.Run(filter, func(t *testing.T) {
+			_, err := ParseFilter(filter)
+			var scimErr *Error
+			require.ErrorAs(t, err, &scimErr)
+			assert.Equal(t, ErrInvalidFilter, scimErr.Type)
+		})
+	}
+}
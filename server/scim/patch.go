@@ -0,0 +1,248 @@
+package scim
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// ApplyPatch applies PATCH operations, as described in RFC 7644, section
+// 3.5.2, to a resource in its generic form, see ToMap.
+func ApplyPatch(resource map[string]interface{}, ops []PatchOperation) error {
+	for _, op := range ops {
+		var value interface{}
+		if len(op.Value) > 0 {
+			if err := json.Unmarshal(op.Value, &value); err != nil {
+				return errorf(ErrInvalidSyntax, "invalid value: %v", err)
+			}
+		}
+		name := strings.ToLower(op.Op)
+		switch name {
+		case "add", "replace", "remove":
+		default:
+			return errorf(ErrInvalidSyntax, "unsupported operation %q", op.Op)
+		}
+
+		if op.Path == "" {
+			if name == "remove" {
+				return errorf(ErrNoTarget, "remove requires a path")
+			}
+			values, ok := value.(map[string]interface{})
+			if !ok {
+				return errorf(ErrInvalidValue, "value must be an object when there is no path")
+			}
+			for k, v := range values {
+				if err := applyWithoutPath(resource, name, k, v); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err := applyPath(resource, name, op.Path, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyWithoutPath(resource map[string]interface{}, op, key string, value interface{}) error {
+	if values, ok := value.(map[string]interface{}); ok && isSchema(resource, key) {
+		for k, v := range values {
+			if err := applyPath(resource, op, key+":"+k, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return applyPath(resource, op, key, value)
+}
+
+// isSchema returns true if `key` is one of the resource's schemas, such as an
+// extension.
+func isSchema(resource map[string]interface{}, key string) bool {
+	schemas, _ := resource["schemas"].([]interface{})
+	for _, schema := range schemas {
+		if s, ok := schema.(string); ok && strings.EqualFold(s, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// container returns the object that holds attributes of `schema`, creating
+// it if needed.
+func container(resource map[string]interface{}, schema string) map[string]interface{} {
+	if schema == "" {
+		return resource
+	}
+	key, value, _ := lookup(resource, schema)
+	if m, ok := value.(map[string]interface{}); ok {
+		return m
+	}
+	m := make(map[string]interface{})
+	resource[key] = m
+	return m
+}
+
+func applyPath(resource map[string]interface{}, op, path string, value interface{}) error {
+	schema, attrPath := splitSchema(path)
+	target := container(resource, schema)
+
+	var name, filter, sub string
+	if i := strings.Index(attrPath, "["); i >= 0 {
+		j := strings.LastIndex(attrPath, "]")
+		if j < i {
+			return errorf(ErrInvalidPath, "invalid path %q", path)
+		}
+		name, filter, sub = attrPath[:i], attrPath[i+1:j], attrPath[j+1:]
+		if sub != "" {
+			if !strings.HasPrefix(sub, ".") {
+				return errorf(ErrInvalidPath, "invalid path %q", path)
+			}
+			sub = sub[1:]
+		}
+	} else {
+		name, sub, _ = strings.Cut(attrPath, ".")
+	}
+	if name == "" {
+		return errorf(ErrInvalidPath, "invalid path %q", path)
+	}
+
+	if filter != "" {
+		return applyFiltered(target, op, name, filter, sub, value)
+	}
+	if sub != "" {
+		key, existing, found := lookup(target, name)
+		m, ok := existing.(map[string]interface{})
+		if !ok {
+			if found && existing != nil {
+				return errorf(ErrInvalidPath, "%s is not a complex attribute", name)
+			}
+			if op == "remove" {
+				return nil
+			}
+			m = make(map[string]interface{})
+			target[key] = m
+		}
+		return applyAttribute(m, op, sub, value)
+	}
+	return applyAttribute(target, op, name, value)
+}
+
+func applyAttribute(target map[string]interface{}, op, name string, value interface{}) error {
+	key, existing, _ := lookup(target, name)
+	existingValues, isMultiValued := existing.([]interface{})
+	switch op {
+	case "add":
+		if isMultiValued {
+			values, ok := value.([]interface{})
+			if !ok {
+				values = []interface{}{value}
+			}
+			for _, v := range values {
+				if indexOf(existingValues, v) < 0 {
+					existingValues = append(existingValues, v)
+				}
+			}
+			target[key] = existingValues
+			return nil
+		}
+		target[key] = value
+	case "replace":
+		target[key] = value
+	case "remove":
+		values, ok := value.([]interface{})
+		if isMultiValued && ok {
+			// Not in the RFC, but some clients remove members this way.
+			for _, v := range values {
+				if i := indexOf(existingValues, v); i >= 0 {
+					existingValues = append(existingValues[:i], existingValues[i+1:]...)
+				}
+			}
+			target[key] = existingValues
+			return nil
+		}
+		delete(target, key)
+	}
+	return nil
+}
+
+// indexOf returns the index of `value` in `values`. Complex values are
+// considered equal if they have the same "value" sub-attribute.
+func indexOf(values []interface{}, value interface{}) int {
+	for i, v := range values {
+		a, aOk := v.(map[string]interface{})
+		b, bOk := value.(map[string]interface{})
+		if aOk && bOk {
+			_, av, aFound := lookup(a, "value")
+			_, bv, bFound := lookup(b, "value")
+			if aFound && bFound && av == bv {
+				return i
+			}
+		}
+		if reflect.DeepEqual(v, value) {
+			return i
+		}
+	}
+	return -1
+}
+
+func applyFiltered(target map[string]interface{}, op, name, filterExpr, sub string, value interface{}) error {
+	filter, err := ParseFilter(filterExpr)
+	if err != nil {
+		return err
+	}
+	key, existing, _ := lookup(target, name)
+	values, _ := existing.([]interface{})
+
+	var matched []map[string]interface{}
+	remaining := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		if m, ok := v.(map[string]interface{}); ok && filter.Matches(m) {
+			matched = append(matched, m)
+			if op == "remove" && sub == "" {
+				continue
+			}
+		}
+		remaining = append(remaining, v)
+	}
+
+	switch op {
+	case "remove":
+		for _, m := range matched {
+			if sub != "" {
+				k, _, _ := lookup(m, sub)
+				delete(m, k)
+			}
+		}
+		target[key] = remaining
+		return nil
+	case "add", "replace":
+		if len(matched) == 0 {
+			// Create the value that the filter refers to, e.g. for
+			// `emails[type eq "work"].value`.
+			eq, ok := filter.(*compareFilter)
+			if !ok || eq.op != "eq" || strings.Contains(eq.path, ".") {
+				return errorf(ErrNoTarget, "no value matches %q", filterExpr)
+			}
+			m := map[string]interface{}{eq.path: eq.value}
+			values = append(values, m)
+			target[key] = values
+			matched = append(matched, m)
+		}
+		for _, m := range matched {
+			if sub != "" {
+				k, _, _ := lookup(m, sub)
+				m[k] = value
+			} else if fields, ok := value.(map[string]interface{}); ok {
+				for k, v := range fields {
+					existingKey, _, _ := lookup(m, k)
+					m[existingKey] = v
+				}
+			} else {
+				return errorf(ErrInvalidValue, "value must be an object")
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package scim
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func patchUser(t *testing.T, user *User, ops string) (*User, error) {
+	t.Helper()
+	var req PatchRequest
+	require.NoError(t, json.Unmarshal([]byte(ops), &req))
+	m, err := ToMap(user)
+	require.NoError(t, err)
+	if err := ApplyPatch(m, req.Operations); err != nil {
+		return nil, err
+	}
+	ret := &User{}
+	return ret, FromMap(m, ret)
+}
+
+func testUser() *User {
+	active := Bool(true)
+	return &User{
+		Schemas:     []string{SchemaUser, SchemaUserExtension},
+		ID:          "abc",
+		UserName:    "alice@example.com",
+		DisplayName: "Alice",
+		Emails:      []Email{{Value: "alice@example.com", Type: "work", Primary: true}},
+		Active:      &active,
+		Extension:   &UserExtension{AllowedHosts: []string{"a.example.com"}},
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	t.Run("replace without path", func(t *testing.T) {
+		user, err := patchUser(t, testUser(), `{"Operations": [
+			{"op": "replace", "value": {"active": false, "displayName": "Alice Smith"}}
+		]}`)
+		require.NoError(t, err)
+		assert.False(t, bool(*user.Active))
+		assert.Equal(t, "Alice Smith", user.DisplayName)
+	})
+
+	t.Run("string booleans and capitalized operations", func(t *testing.T) {
+		user, err := patchUser(t, testUser(), `{"Operations": [
+			{"op": "Replace", "path": "active", "value": "False"}
+		]}`)
+		require.NoError(t, err)
+		assert.False(t, bool(*user.Active))
+	})
+
+	t.Run("sub-attribute", func(t *testing.T) {
+		user, err := patchUser(t, testUser(), `{"Operations": [
+			{"op": "add", "path": "name.formatted", "value": "Alice Smith"}
+		]}`)
+		require.NoError(t, err)
+		assert.Equal(t, "Alice Smith", user.Name.Formatted)
+	})
+
+	t.Run("value filter", func(t *testing.T) {
+		user, err := patchUser(t, testUser(), `{"Operations": [
+			{"op": "replace", "path": "emails[type eq \"work\"].value", "value": "alice@corp.example.com"}
+		]}`)
+		require.NoError(t, err)
+		assert.Equal(t, "alice@corp.example.com", user.PrimaryEmail())
+	})
+
+	t.Run("value filter without match creates value", func(t *testing.T) {
+		user := testUser()
+		user.Emails = nil
+		user, err := patchUser(t, user, `{"Operations": [
+			{"op": "add", "path": "emails[type eq \"work\"].value", "value": "alice@corp.example.com"}
+		]}`)
+		require.NoError(t, err)
+		assert.Equal(t, []Email{{Value: "alice@corp.example.com", Type: "work"}}, user.Emails)
+	})
+
+	t.Run("extension attributes", func(t *testing.T) {
+		user, err := patchUser(t, testUser(), `{"Operations": [
+			{"op": "replace", "path": "urn:ietf:params:scim:schemas:extension:ubergang:2.0:User:isAdmin", "value": true},
+			{"op": "add", "value": {"urn:ietf:params:scim:schemas:extension:ubergang:2.0:User": {"allowedHosts": ["b.example.com"]}}}
+		]}`)
+		require.NoError(t, err)
+		assert.True(t, bool(user.Extension.IsAdmin))
+		assert.Equal(t, []string{"a.example.com", "b.example.com"}, user.Extension.AllowedHosts)
+	})
+
+	t.Run("members", func(t *testing.T) {
+		group, err := ToMap(&Group{
+			Schemas:     []string{SchemaGroup},
+			DisplayName: "admins",
+			Members:     []Reference{{Value: "a"}, {Value: "b"}},
+		})
+		require.NoError(t, err)
+		var req PatchRequest
+		require.NoError(t, json.Unmarshal([]byte(`{"Operations": [
+			{"op": "add", "path": "members", "value": [{"value": "b"}, {"value": "c"}]},
+			{"op": "remove", "path": "members[value eq \"a\"]"},
+			{"op": "remove", "path": "members", "value": [{"value": "b"}]}
+		]}`), &req))
+		require.NoError(t, ApplyPatch(group, req.Operations))
+
+		ret := &Group{}
+		require.NoError(t, FromMap(group, ret))
+		assert.Equal(t, []Reference{{Value: "c"}}, ret.Members)
+	})
+
+	t.Run("remove requires path", func(t *testing.T) {
+		_, err := patchUser(t, testUser(), `{"Operations": [{"op": "remove"}]}`)
+		var scimErr *Error
+		require.ErrorAs(t, err, &scimErr)
+		assert.Equal(t, ErrNoTarget, scimErr.Type)
+	})
+
+	t.Run("unsupported operation", func(t *testing.T) {
+		_, err := patchUser(t, testUser(), `{"Operations": [{"op": "move", "path": "active"}]}`)
+		var scimErr *Error
+		require.ErrorAs(t, err, &scimErr)
+		assert.Equal(t, ErrInvalidSyntax, scimErr.Type)
+	})
+}
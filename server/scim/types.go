@@ -0,0 +1,208 @@
+// Package scim implements the parts of SCIM 2.0 (RFC 7643 and RFC 7644) that
+// are needed to let an external directory provision users and groups.
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	ContentType = "application/scim+json"
+
+	SchemaUser          = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup         = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaUserExtension = "urn:ietf:params:scim:schemas:extension:ubergang:2.0:User"
+	SchemaListResponse  = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp       = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError         = "urn:ietf:params:scim:api:messages:2.0:Error"
+	SchemaConfig        = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+)
+
+// Values of `scimType` in error responses.
+const (
+	ErrInvalidFilter = "invalidFilter"
+	ErrInvalidPath   = "invalidPath"
+	ErrInvalidValue  = "invalidValue"
+	ErrInvalidSyntax = "invalidSyntax"
+	ErrNoTarget      = "noTarget"
+	ErrUniqueness    = "uniqueness"
+	ErrMutability    = "mutability"
+)
+
+// Bool is a boolean that also accepts "true" and "false" as strings, which
+// some clients send.
+type Bool bool
+
+func (b *Bool) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case bool:
+		*b = Bool(v)
+	case string:
+		switch strings.ToLower(v) {
+		case "true":
+			*b = true
+		case "false":
+			*b = false
+		default:
+			return fmt.Errorf("invalid boolean: %q", v)
+		}
+	default:
+		return fmt.Errorf("invalid boolean: %s", string(data))
+	}
+	return nil
+}
+
+type Meta struct {
+	ResourceType string `json:"resourceType"`
+	Created      string `json:"created,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+	Location     string `json:"location"`
+}
+
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type Email struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary Bool   `json:"primary,omitempty"`
+}
+
+// UserExtension holds the attributes that don't have a counterpart in the core
+// user schema.
+type UserExtension struct {
+	IsAdmin      Bool     `json:"isAdmin"`
+	AllowedHosts []string `json:"allowedHosts"`
+}
+
+// Reference is used for a user's groups and for a group's members.
+type Reference struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+type User struct {
+	Schemas     []string       `json:"schemas"`
+	ID          string         `json:"id,omitempty"`
+	ExternalID  string         `json:"externalId,omitempty"`
+	UserName    string         `json:"userName"`
+	DisplayName string         `json:"displayName,omitempty"`
+	Name        *Name          `json:"name,omitempty"`
+	Emails      []Email        `json:"emails,omitempty"`
+	Active      *Bool          `json:"active,omitempty"`
+	Groups      []Reference    `json:"groups,omitempty"`
+	Extension   *UserExtension `json:"urn:ietf:params:scim:schemas:extension:ubergang:2.0:User,omitempty"`
+	Meta        *Meta          `json:"meta,omitempty"`
+}
+
+// PrimaryEmail returns the primary e-mail address, or the first one if none
+// is marked as primary.
+func (u *User) PrimaryEmail() string {
+	for _, email := range u.Emails {
+		if email.Primary {
+			return email.Value
+		}
+	}
+	if len(u.Emails) > 0 {
+		return u.Emails[0].Value
+	}
+	return ""
+}
+
+type Group struct {
+	Schemas     []string    `json:"schemas"`
+	ID          string      `json:"id,omitempty"`
+	ExternalID  string      `json:"externalId,omitempty"`
+	DisplayName string      `json:"displayName"`
+	Members     []Reference `json:"members"`
+	Meta        *Meta       `json:"meta,omitempty"`
+}
+
+type ListResponse struct {
+	Schemas      []string      `json:"schemas"`
+	TotalResults int           `json:"totalResults"`
+	StartIndex   int           `json:"startIndex"`
+	ItemsPerPage int           `json:"itemsPerPage"`
+	Resources    []interface{} `json:"Resources"`
+}
+
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+type ErrorResponse struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+// Error is returned when a request is invalid, and is reported to the client
+// as a 400 Bad Request with `Type` as `scimType`.
+type Error struct {
+	Type   string
+	Detail string
+}
+
+func (e *Error) Error() string {
+	return e.Type + ": " + e.Detail
+}
+
+func errorf(scimType string, format string, args ...interface{}) error {
+	return &Error{Type: scimType, Detail: fmt.Sprintf(format, args...)}
+}
+
+// ToMap converts a resource to its generic JSON representation, which is
+// what filters and patches operate on.
+func ToMap(resource interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string]interface{})
+	err = json.Unmarshal(data, &ret)
+	return ret, err
+}
+
+// FromMap is the inverse of ToMap.
+func FromMap(m map[string]interface{}, resource interface{}) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, resource); err != nil {
+		return errorf(ErrInvalidValue, "%v", err)
+	}
+	return nil
+}
+
+// lookup returns the value of `attr` in `m`. Attribute names are case
+// insensitive.
+func lookup(m map[string]interface{}, attr string) (string, interface{}, bool) {
+	if v, found := m[attr]; found {
+		return attr, v, true
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, attr) {
+			return k, v, true
+		}
+	}
+	return attr, nil, false
+}
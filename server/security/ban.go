@@ -0,0 +1,222 @@
+package security
+
+import (
+	"boivie/ubergang/server/models"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Used for the values that aren't set in the ban policy.
+const (
+	DefaultMaxFailures             = 20
+	DefaultFailureWindow           = 10 * time.Minute
+	DefaultBanDuration             = 5 * time.Minute
+	DefaultMaxBanDuration          = 24 * time.Hour
+	DefaultSigninRequestsPerMinute = 30
+	// Enough for a sign-in page that polls every second.
+	DefaultTokenRequestsPerMinute = 120
+)
+
+var (
+	failuresMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ubergang_auth_failures_total",
+		Help: "The total number of failed attempts to authenticate",
+	}, []string{"source"})
+	bansMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ubergang_bans_total",
+		Help: "The total number of IP addresses that have been banned",
+	}, []string{"source"})
+	rejectedMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ubergang_rejected_requests_total",
+		Help: "The total number of requests rejected as the client was banned or rate limited",
+	}, []string{"source", "reason"})
+)
+
+type banPolicy struct {
+	maxFailures             uint32
+	failureWindow           time.Duration
+	banDuration             time.Duration
+	maxBanDuration          time.Duration
+	signinRequestsPerMinute uint32
+	tokenRequestsPerMinute  uint32
+}
+
+func orDefault[T comparable](value, def T) T {
+	var zero T
+	if value == zero {
+		return def
+	}
+	return value
+}
+
+// policy returns the ban policy, or false if banning is disabled.
+func (e *Events) policy() (banPolicy, bool) {
+	p := e.config.Get().BanPolicy
+	if p.GetDisabled() {
+		return banPolicy{}, false
+	}
+	return banPolicy{
+		maxFailures:             orDefault(p.GetMaxFailures(), DefaultMaxFailures),
+		failureWindow:           orDefault(p.GetFailureWindow().AsDuration(), DefaultFailureWindow),
+		banDuration:             orDefault(p.GetBanDuration().AsDuration(), DefaultBanDuration),
+		maxBanDuration:          orDefault(p.GetMaxBanDuration().AsDuration(), DefaultMaxBanDuration),
+		signinRequestsPerMinute: orDefault(p.GetSigninRequestsPerMinute(), DefaultSigninRequestsPerMinute),
+		tokenRequestsPerMinute:  orDefault(p.GetTokenRequestsPerMinute(), DefaultTokenRequestsPerMinute),
+	}, true
+}
+
+// IsBanned returns true if `ban` is in effect at `now`.
+func IsBanned(ban *models.Ban, now time.Time) bool {
+	return ban.GetBannedUntil() != nil && now.Before(ban.BannedUntil.AsTime())
+}
+
+// duration returns how long the ban after `banCount` earlier ones is.
+func (p banPolicy) duration(banCount uint32) time.Duration {
+	d := p.banDuration
+	for i := uint32(0); i < banCount && d < p.maxBanDuration; i++ {
+		d *= 2
+	}
+	return min(d, p.maxBanDuration)
+}
+
+// fail counts a failure from `ip`, and bans it if it has failed too often.
+func (e *Events) fail(source Source, ip string, now time.Time) {
+	p, enabled := e.policy()
+	if !enabled || ip == "" {
+		return
+	}
+	var banned *models.Ban
+	err := e.db.UpdateBan(ip, func(old *models.Ban) (*models.Ban, error) {
+		if old == nil {
+			old = &models.Ban{Ip: ip}
+		}
+		if IsBanned(old, now) {
+			// Already dealt with.
+			return old, nil
+		}
+		if old.BannedUntil != nil && now.Sub(old.BannedUntil.AsTime()) > p.maxBanDuration {
+			// It's been behaving for a while, so start over.
+			old.BanCount = 0
+		}
+		if old.WindowStart == nil || now.Sub(old.WindowStart.AsTime()) > p.failureWindow {
+			old.Failures = 0
+			old.WindowStart = timestamppb.New(now)
+		}
+		old.Failures++
+		if old.Failures >= p.maxFailures {
+			old.BannedUntil = timestamppb.New(now.Add(p.duration(old.BanCount)))
+			old.BanCount++
+			old.Source = string(source)
+			old.Failures = 0
+			old.WindowStart = nil
+			banned = old
+		}
+		return old, nil
+	})
+	if err != nil {
+		e.log.Warnf("Failed to count failure from %s: %v", ip, err)
+		return
+	}
+	if banned != nil {
+		bansMetric.WithLabelValues(string(source)).Inc()
+		e.log.Warnf("Banned %s until %s after repeated %s failures", ip,
+			banned.BannedUntil.AsTime().Format(time.RFC3339), source)
+	}
+}
+
+// Banned returns true if connections from `ip` are to be rejected.
+func (e *Events) Banned(source Source, ip string) bool {
+	if _, enabled := e.policy(); !enabled || ip == "" {
+		return false
+	}
+	ban, err := e.db.GetBan(ip)
+	if err != nil || !IsBanned(ban, time.Now()) {
+		return false
+	}
+	rejectedMetric.WithLabelValues(string(source), "banned").Inc()
+	return true
+}
+
+// Unban lifts the ban of `ip`, and forgets its earlier failures. It returns
+// what was forgotten, or nil if there was nothing.
+func (e *Events) Unban(ip string) (ret *models.Ban, err error) {
+	err = e.db.UpdateBan(ip, func(old *models.Ban) (*models.Ban, error) {
+		ret = old
+		return nil, nil
+	})
+	return
+}
+
+// rateLimiter counts requests per IP address in fixed one minute windows.
+type rateLimiter struct {
+	mu      sync.Mutex
+	windows map[string]*rateWindow
+}
+
+type rateWindow struct {
+	start time.Time
+	count uint32
+}
+
+// allow returns true if `ip` has made at most `limit` requests, including
+// this one, in the current window.
+func (l *rateLimiter) allow(ip string, limit uint32, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.windows == nil {
+		l.windows = map[string]*rateWindow{}
+	}
+	w := l.windows[ip]
+	if w == nil || now.Sub(w.start) >= time.Minute {
+		if len(l.windows) > 10000 {
+			for k, v := range l.windows {
+				if now.Sub(v.start) >= time.Minute {
+					delete(l.windows, k)
+				}
+			}
+		}
+		w = &rateWindow{start: now}
+		l.windows[ip] = w
+	}
+	w.count++
+	return w.count <= limit
+}
+
+// AllowSignin returns true if `ip` may make a request to a sign-in endpoint,
+// i.e. it's not banned and hasn't made too many requests recently.
+func (e *Events) AllowSignin(ip string) bool {
+	p, enabled := e.policy()
+	if !enabled || ip == "" {
+		return true
+	}
+	if e.Banned(SourceHttp, ip) {
+		return false
+	}
+	if !e.limiter.allow(ip, p.signinRequestsPerMinute, time.Now()) {
+		rejectedMetric.WithLabelValues(string(SourceHttp), "rate_limited").Inc()
+		return false
+	}
+	return true
+}
+
+// AllowTokenRequest returns true if `ip` may make a request to an endpoint
+// that is polled or used by machines, such as the token endpoint. These have
+// a separate and higher limit than the sign-in endpoints.
+func (e *Events) AllowTokenRequest(ip string) bool {
+	p, enabled := e.policy()
+	if !enabled || ip == "" {
+		return true
+	}
+	if e.Banned(SourceHttp, ip) {
+		return false
+	}
+	if !e.tokenLimiter.allow(ip, p.tokenRequestsPerMinute, time.Now()) {
+		rejectedMetric.WithLabelValues(string(SourceHttp), "rate_limited").Inc()
+		return false
+	}
+	return true
+}
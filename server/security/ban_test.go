@@ -0,0 +1,145 @@
+package security
+
+import (
+	"boivie/ubergang/server/config"
+	"boivie/ubergang/server/db"
+	"boivie/ubergang/server/log"
+	"boivie/ubergang/server/models"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func createEvents(t *testing.T, policy *models.BanPolicy) *Events {
+	log := log.NewLogger(log.Fields{})
+	db, err := db.New(log, path.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	return New(log, config.New(&models.Configuration{BanPolicy: policy}), db)
+}
+
+func TestBanDuration(t *testing.T) {
+	p := banPolicy{banDuration: time.Minute, maxBanDuration: 10 * time.Minute}
+	assert.Equal(t, time.Minute, p.duration(0))
+	assert.Equal(t, 2*time.Minute, p.duration(1))
+	assert.Equal(t, 8*time.Minute, p.duration(3))
+	assert.Equal(t, 10*time.Minute, p.duration(4))
+	assert.Equal(t, 10*time.Minute, p.duration(1000))
+}
+
+func TestBan(t *testing.T) {
+	policy := &models.BanPolicy{
+		MaxFailures:    3,
+		FailureWindow:  durationpb.New(time.Minute),
+		BanDuration:    durationpb.New(time.Minute),
+		MaxBanDuration: durationpb.New(time.Hour),
+	}
+
+	t.Run("bans after too many failures", func(t *testing.T) {
+		e := createEvents(t, policy)
+		now := time.Now()
+		e.fail(SourceMqtt, "10.0.0.1", now)
+		e.fail(SourceMqtt, "10.0.0.1", now)
+		assert.False(t, e.Banned(SourceMqtt, "10.0.0.1"))
+		e.fail(SourceMqtt, "10.0.0.1", now)
+		assert.True(t, e.Banned(SourceMqtt, "10.0.0.1"))
+		assert.True(t, e.Banned(SourceSsh, "10.0.0.1"))
+		assert.False(t, e.Banned(SourceMqtt, "10.0.0.2"))
+
+		ban, err := e.db.GetBan("10.0.0.1")
+		require.NoError(t, err)
+		assert.Equal(t, "mqtt", ban.Source)
+		assert.Equal(t, uint32(1), ban.BanCount)
+		assert.WithinDuration(t, now.Add(time.Minute), ban.BannedUntil.AsTime(), time.Second)
+	})
+
+	t.Run("failures outside the window are forgotten", func(t *testing.T) {
+		e := createEvents(t, policy)
+		now := time.Now()
+		e.fail(SourceSsh, "10.0.0.1", now.Add(-2*time.Minute))
+		e.fail(SourceSsh, "10.0.0.1", now.Add(-2*time.Minute))
+		e.fail(SourceSsh, "10.0.0.1", now)
+		assert.False(t, e.Banned(SourceSsh, "10.0.0.1"))
+	})
+
+	t.Run("bans get longer", func(t *testing.T) {
+		e := createEvents(t, policy)
+		now := time.Now().Add(-2 * time.Minute)
+		for i := 0; i < 3; i++ {
+			e.fail(SourceSsh, "10.0.0.1", now)
+		}
+		// The first ban has expired.
+		assert.False(t, e.Banned(SourceSsh, "10.0.0.1"))
+		now = time.Now()
+		for i := 0; i < 3; i++ {
+			e.fail(SourceSsh, "10.0.0.1", now)
+		}
+		ban, err := e.db.GetBan("10.0.0.1")
+		require.NoError(t, err)
+		assert.Equal(t, uint32(2), ban.BanCount)
+		assert.WithinDuration(t, now.Add(2*time.Minute), ban.BannedUntil.AsTime(), time.Second)
+	})
+
+	t.Run("failures while banned don't extend the ban", func(t *testing.T) {
+		e := createEvents(t, policy)
+		now := time.Now()
+		for i := 0; i < 10; i++ {
+			e.fail(SourceHttp, "10.0.0.1", now)
+		}
+		ban, err := e.db.GetBan("10.0.0.1")
+		require.NoError(t, err)
+		assert.Equal(t, uint32(1), ban.BanCount)
+	})
+
+	t.Run("unban", func(t *testing.T) {
+		e := createEvents(t, policy)
+		for i := 0; i < 3; i++ {
+			e.fail(SourceSsh, "10.0.0.1", time.Now())
+		}
+		ban, err := e.Unban("10.0.0.1")
+		require.NoError(t, err)
+		assert.NotNil(t, ban)
+		assert.False(t, e.Banned(SourceSsh, "10.0.0.1"))
+
+		ban, err = e.Unban("10.0.0.1")
+		require.NoError(t, err)
+		assert.Nil(t, ban)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		e := createEvents(t, &models.BanPolicy{Disabled: true, MaxFailures: 1})
+		e.fail(SourceSsh, "10.0.0.1", time.Now())
+		assert.False(t, e.Banned(SourceSsh, "10.0.0.1"))
+		assert.Empty(t, e.db.ListBans())
+	})
+
+	t.Run("unknown addresses aren't banned", func(t *testing.T) {
+		e := createEvents(t, &models.BanPolicy{MaxFailures: 1})
+		e.fail(SourceSsh, "", time.Now())
+		assert.False(t, e.Banned(SourceSsh, ""))
+	})
+}
+
+func TestAllowSignin(t *testing.T) {
+	e := createEvents(t, &models.BanPolicy{SigninRequestsPerMinute: 2})
+	assert.True(t, e.AllowSignin("10.0.0.1"))
+	assert.True(t, e.AllowSignin("10.0.0.1"))
+	assert.False(t, e.AllowSignin("10.0.0.1"))
+	assert.True(t, e.AllowSignin("10.0.0.2"))
+
+	// A new window.
+	assert.True(t, e.limiter.allow("10.0.0.1", 2, time.Now().Add(time.Minute)))
+}
+
+func TestAllowTokenRequest(t *testing.T) {
+	e := createEvents(t, &models.BanPolicy{SigninRequestsPerMinute: 1, TokenRequestsPerMinute: 2})
+	assert.True(t, e.AllowSignin("10.0.0.1"))
+	// Limited separately from the sign-in endpoints.
+	assert.True(t, e.AllowTokenRequest("10.0.0.1"))
+	assert.True(t, e.AllowTokenRequest("10.0.0.1"))
+	assert.False(t, e.AllowTokenRequest("10.0.0.1"))
+	assert.True(t, e.AllowTokenRequest("10.0.0.2"))
+}
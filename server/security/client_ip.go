@@ -0,0 +1,83 @@
+package security
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ParseProxy parses a trusted proxy, which is an address or a CIDR range.
+func ParseProxy(proxy string) (netip.Prefix, error) {
+	if strings.Contains(proxy, "/") {
+		prefix, err := netip.ParsePrefix(proxy)
+		return prefix.Masked(), err
+	}
+	addr, err := netip.ParseAddr(proxy)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+func parseAddr(value string) (netip.Addr, bool) {
+	value = strings.TrimSpace(value)
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		value = host
+	}
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap().WithZone(""), true
+}
+
+// ClientIP returns the address of the client that made `r`. The headers that
+// tell the client's address are easily forged, so they are only used if `r`
+// came from one of the trusted proxies in the ban policy.
+func (e *Events) ClientIP(r *http.Request) string {
+	peer, ok := parseAddr(r.RemoteAddr)
+	if !ok {
+		return ""
+	}
+
+	var proxies []netip.Prefix
+	for _, proxy := range e.config.Get().BanPolicy.GetTrustedProxies() {
+		if prefix, err := ParseProxy(proxy); err == nil {
+			proxies = append(proxies, prefix)
+		}
+	}
+	isTrusted := func(addr netip.Addr) bool {
+		for _, prefix := range proxies {
+			if prefix.Contains(addr) {
+				return true
+			}
+		}
+		return false
+	}
+	if !isTrusted(peer) {
+		return peer.String()
+	}
+
+	// Each proxy appends the address it got the request from, so the client is
+	// the last one that isn't a trusted proxy. Anything before that may have
+	// been sent by the client.
+	var forwarded []string
+	for _, value := range r.Header.Values("X-Forwarded-For") {
+		forwarded = append(forwarded, strings.Split(value, ",")...)
+	}
+	if len(forwarded) == 0 {
+		if addr, ok := parseAddr(r.Header.Get("X-Real-IP")); ok {
+			return addr.String()
+		}
+	}
+	client := peer
+	for i := len(forwarded) - 1; i >= 0 && isTrusted(client); i-- {
+		addr, ok := parseAddr(forwarded[i])
+		if !ok {
+			break
+		}
+		client = addr
+	}
+	return client.String()
+}
@@ -0,0 +1,64 @@
+package security
+
+import (
+	"boivie/ubergang/server/models"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseProxy(t *testing.T) {
+	prefix, err := ParseProxy("10.0.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.1/32", prefix.String())
+
+	prefix, err = ParseProxy("10.1.2.3/8")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.0/8", prefix.String())
+
+	_, err = ParseProxy("proxy.example.com")
+	assert.Error(t, err)
+}
+
+func TestClientIP(t *testing.T) {
+	e := createEvents(t, &models.BanPolicy{TrustedProxies: []string{"10.0.0.0/8", "2001:db8::1"}})
+	clientIP := func(remoteAddr string, headers ...string) string {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = remoteAddr
+		for i := 0; i+1 < len(headers); i += 2 {
+			r.Header.Add(headers[i], headers[i+1])
+		}
+		return e.ClientIP(r)
+	}
+
+	t.Run("ignores headers from untrusted peers", func(t *testing.T) {
+		assert.Equal(t, "192.0.2.1", clientIP("192.0.2.1:1234", "X-Forwarded-For", "198.51.100.1"))
+		assert.Equal(t, "192.0.2.1", clientIP("192.0.2.1:1234", "X-Real-IP", "198.51.100.1"))
+	})
+
+	t.Run("uses headers from trusted proxies", func(t *testing.T) {
+		assert.Equal(t, "198.51.100.1", clientIP("10.0.0.1:1234", "X-Forwarded-For", "198.51.100.1"))
+		assert.Equal(t, "198.51.100.1", clientIP("[2001:db8::1]:1234", "X-Forwarded-For", "198.51.100.1"))
+		assert.Equal(t, "198.51.100.1", clientIP("10.0.0.1:1234", "X-Real-IP", "198.51.100.1"))
+	})
+
+	t.Run("skips trusted proxies", func(t *testing.T) {
+		assert.Equal(t, "198.51.100.1", clientIP("10.0.0.1:1234", "X-Forwarded-For", "198.51.100.1, 10.0.0.2"))
+		assert.Equal(t, "198.51.100.1", clientIP("10.0.0.1:1234",
+			"X-Forwarded-For", "198.51.100.1", "X-Forwarded-For", "10.0.0.2"))
+	})
+
+	t.Run("ignores addresses added by the client", func(t *testing.T) {
+		assert.Equal(t, "198.51.100.1", clientIP("10.0.0.1:1234", "X-Forwarded-For", "203.0.113.1, 198.51.100.1"))
+		assert.Equal(t, "10.0.0.1", clientIP("10.0.0.1:1234", "X-Forwarded-For", "invalid"))
+	})
+
+	t.Run("without proxies", func(t *testing.T) {
+		e := createEvents(t, nil)
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = "[::ffff:192.0.2.1]:1234"
+		r.Header.Set("X-Forwarded-For", "198.51.100.1")
+		assert.Equal(t, "192.0.2.1", e.ClientIP(r))
+	})
+}
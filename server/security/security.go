@@ -0,0 +1,88 @@
+// Package security records failed attempts to authenticate, from all the ways
+// that clients can connect, so that they can be reviewed and acted upon.
+//
+// Events are logged as they happen and a bounded history is stored. Clients
+// that fail too often are banned for a while. Events can also be exported in
+// a format that fail2ban can parse, using a filter such as:
+//
+//	[Definition]
+//	failregex = ^\S+ ubergang: authentication failure; source=\S+ ip=<HOST>
+package security
+
+import (
+	"boivie/ubergang/server/common"
+	"boivie/ubergang/server/config"
+	"boivie/ubergang/server/db"
+	"boivie/ubergang/server/log"
+	"boivie/ubergang/server/models"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// MaxEvents is the number of events that are kept.
+const MaxEvents = 10000
+
+type Source string
+
+const (
+	SourceHttp Source = "http"
+	SourceSsh  Source = "ssh"
+	SourceMqtt Source = "mqtt"
+)
+
+type Events struct {
+	log          *log.Log
+	config       *config.Store
+	db           *db.DB
+	max          int
+	limiter      rateLimiter
+	tokenLimiter rateLimiter
+}
+
+func New(log *log.Log, config *config.Store, db *db.DB) *Events {
+	return &Events{log: log, config: config, db: db, max: MaxEvents}
+}
+
+// Record records that a client at `ip` failed to authenticate as
+// `principal`, which may be empty if unknown. Clients that fail too often are
+// banned.
+func (e *Events) Record(source Source, ip, principal, reason string) {
+	failuresMetric.WithLabelValues(string(source)).Inc()
+	event := &models.SecurityEvent{
+		Id:        common.MakeRandomID(),
+		Timestamp: timestamppb.New(time.Now()),
+		Source:    string(source),
+		Ip:        ip,
+		Principal: principal,
+		Reason:    reason,
+	}
+	e.log.Warnf("%s: authentication failure from %s (%q): %s", source, ip, principal, reason)
+	if err := e.db.AppendSecurityEvent(event, e.max); err != nil {
+		e.log.Warnf("Failed to store security event: %v", err)
+	}
+	e.fail(source, ip, event.Timestamp.AsTime())
+}
+
+// Host returns the host part of a network address such as "10.0.0.1:1234".
+func Host(addr net.Addr) string {
+	if addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
+
+// Fail2ban formats an event as a single line, where the values that clients
+// control are quoted.
+func Fail2ban(event *models.SecurityEvent) string {
+	return fmt.Sprintf("%s ubergang: authentication failure; source=%s ip=%s principal=%s reason=%s",
+		event.Timestamp.AsTime().UTC().Format(time.RFC3339),
+		event.Source, event.Ip, strconv.Quote(event.Principal), strconv.Quote(event.Reason))
+}
@@ -0,0 +1,58 @@
+package security
+
+import (
+	"boivie/ubergang/server/config"
+	"boivie/ubergang/server/db"
+	"boivie/ubergang/server/log"
+	"boivie/ubergang/server/models"
+	"net"
+	"path"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestHost(t *testing.T) {
+	assert.Equal(t, "10.0.0.1", Host(&net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 1234}))
+	assert.Equal(t, "2001:db8::1", Host(&net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 22}))
+	assert.Equal(t, "", Host(nil))
+}
+
+func TestFail2ban(t *testing.T) {
+	event := &models.SecurityEvent{
+		Timestamp: timestamppb.New(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+		Source:    string(SourceMqtt),
+		Ip:        "10.0.0.1",
+		Principal: "evil\nip=10.0.0.2",
+		Reason:    "invalid password",
+	}
+	line := Fail2ban(event)
+	assert.Equal(t, `2024-01-02T03:04:05Z ubergang: authentication failure; source=mqtt ip=10.0.0.1 principal="evil\nip=10.0.0.2" reason="invalid password"`, line)
+
+	// The filter in the package documentation, with fail2ban's <HOST> expanded.
+	filter := regexp.MustCompile(`^\S+ ubergang: authentication failure; source=\S+ ip=(\S+)`)
+	match := filter.FindStringSubmatch(line)
+	require.NotNil(t, match)
+	assert.Equal(t, "10.0.0.1", match[1])
+}
+
+func TestRecord(t *testing.T) {
+	log := log.NewLogger(log.Fields{})
+	db, err := db.New(log, path.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	events := &Events{log: log, config: config.New(&models.Configuration{}), db: db, max: 3}
+
+	for _, principal := range []string{"a", "b", "c", "d", "e"} {
+		events.Record(SourceSsh, "10.0.0.1", principal, "unknown key")
+	}
+	all := db.ListSecurityEvents(func(*models.SecurityEvent) bool { return true }, 10)
+	require.Len(t, all, 3)
+	// Newest first, and the oldest were deleted.
+	assert.Equal(t, "e", all[0].Principal)
+	assert.Equal(t, "c", all[2].Principal)
+	assert.Equal(t, "ssh", all[0].Source)
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"boivie/ubergang/server/config"
 	"context"
 	"embed"
 	"fmt"
@@ -30,6 +31,7 @@ import (
 	"boivie/ubergang/server/mqtt"
 	"boivie/ubergang/server/proxy"
 	"boivie/ubergang/server/rest"
+	"boivie/ubergang/server/security"
 	"boivie/ubergang/server/session"
 	"boivie/ubergang/server/ssh_server"
 	"boivie/ubergang/server/tls"
@@ -52,6 +54,7 @@ var flgMqttTlsPort = flag.Int("mqtt-tls", 8883, "Port for MQTT TLS proxy server"
 var flgMqttServer = flag.String("mqtt-server", "", "MQTT server")
 var flgLocalDev = flag.Bool("local-dev", false, "Local development")
 var flgVerbose = flag.Bool("verbose", false, "Verbose logs")
+var flgMigrateDryRun = flag.Bool("migrate-dry-run", false, "Show the pending database migrations, without applying them, and exit")
 
 var (
 	httpRequestsTotalMetric = promauto.NewCounterVec(prometheus.CounterOpts{
@@ -71,11 +74,11 @@ var (
 )
 
 type Server struct {
-	config         *models.Configuration
+	config         *config.Store
 	db             *db.DB
 	tlsManager     tls.TlsManager
 	assets         *embed.FS
-	updateAccessed chan *models.Session
+	updateAccessed chan session.Access
 	backendManager *backends.BackendManager
 	log            *uglog.Log
 	session        *session.SessionStore
@@ -97,9 +100,23 @@ func NewServer(dbFile string, assets *embed.FS) *Server {
 		os.Exit(1)
 	}
 
-	config, err := db.GetConfiguration()
+	migrations, err := db.Migrate(*flgMigrateDryRun)
 	if err != nil {
-		config = &models.Configuration{}
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+	if *flgMigrateDryRun {
+		if len(migrations) == 0 {
+			fmt.Println("The database is up to date. Nothing to migrate.")
+		}
+		for _, m := range migrations {
+			fmt.Printf("Would migrate to schema version %d: %s\n", m.Version, m.Description)
+		}
+		os.Exit(0)
+	}
+
+	configuration, err := db.GetConfiguration()
+	if err != nil {
+		configuration = &models.Configuration{}
 	}
 	if *flgVerbose {
 		uglog.SetLogLevel(logrus.DebugLevel)
@@ -109,7 +126,7 @@ func NewServer(dbFile string, assets *embed.FS) *Server {
 	backends := backends.New(db, log)
 
 	// Check if server is configured
-	isConfigured := config.Email != "" && config.SiteFqdn != "" && config.AdminFqdn != ""
+	isConfigured := configuration.Email != "" && configuration.SiteFqdn != "" && configuration.AdminFqdn != ""
 
 	if !isConfigured {
 		// BOOTSTRAP MODE: Use self-signed certificate
@@ -138,7 +155,7 @@ func NewServer(dbFile string, assets *embed.FS) *Server {
 				log.Fatalf("Failed to load bootstrap certificate: %v", err)
 			}
 		}
-	} else if config.IsInTestMode {
+	} else if configuration.IsInTestMode {
 		// TEST MODE: Use self-signed certs
 		log.Infof("Using self-signed certs (test mode)")
 		tlsManager = tls.NewLocalCertTlsManager()
@@ -146,14 +163,14 @@ func NewServer(dbFile string, assets *embed.FS) *Server {
 		// PRODUCTION MODE: Use Let's Encrypt
 		// Check if Google Cloud DNS credentials are configured via environment variable
 		if os.Getenv("GOOGLE_APPLICATION_CREDENTIALS") != "" {
-			log.Infof("Using LetsEncrypt certificates via certmagic with DNS-01 (wildcard for *.%s)", config.SiteFqdn)
+			log.Infof("Using LetsEncrypt certificates via certmagic with DNS-01 (wildcard for *.%s)", configuration.SiteFqdn)
 		} else {
 			log.Infof("Using LetsEncrypt certificates via certmagic with HTTP-01")
 		}
 
-		tlsManager = tls.NewCertMagicTlsManager(db, config.Email, config.SiteFqdn, func(ctx context.Context, host string) error {
+		tlsManager = tls.NewCertMagicTlsManager(db, configuration.Email, configuration.SiteFqdn, func(ctx context.Context, host string) error {
 			// Allow admin FQDN
-			if host == config.AdminFqdn {
+			if host == configuration.AdminFqdn {
 				return nil
 			}
 
@@ -163,9 +180,9 @@ func NewServer(dbFile string, assets *embed.FS) *Server {
 			}
 
 			// When using wildcard certificates, allow any subdomain of SiteFqdn
-			if os.Getenv("GOOGLE_APPLICATION_CREDENTIALS") != "" && config.SiteFqdn != "" {
+			if os.Getenv("GOOGLE_APPLICATION_CREDENTIALS") != "" && configuration.SiteFqdn != "" {
 				// Check if host is a subdomain of SiteFqdn or matches exactly
-				if host == config.SiteFqdn || strings.HasSuffix(host, "."+config.SiteFqdn) {
+				if host == configuration.SiteFqdn || strings.HasSuffix(host, "."+configuration.SiteFqdn) {
 					return nil
 				}
 			}
@@ -174,10 +191,12 @@ func NewServer(dbFile string, assets *embed.FS) *Server {
 		})
 	}
 
-	session := session.NewSessionStore(log, db)
+	config := config.New(configuration)
+	updateAccessed := make(chan session.Access, 100)
 	auth := auth.New(log, db)
-	updateAccessed := make(chan *models.Session)
-	mqttProxy := mqtt.New(log, config, db, tlsManager, *flgMqttServer)
+	events := security.New(log, config, db)
+	session := session.NewSessionStore(log, config, db, auth, events, updateAccessed)
+	mqttProxy := mqtt.New(log, config, db, events, tlsManager, *flgMqttServer)
 
 	// Create MQTT publisher if broker is configured
 	var mqttPublisher mqtt.MQTTPublisher = nil
@@ -196,14 +215,15 @@ func NewServer(dbFile string, assets *embed.FS) *Server {
 		backendManager: backends,
 		session:        session,
 		auth:           auth,
-		api:            rest.New(config, db, log, session, auth, mqttProxy),
-		proxy:          proxy.New(config, log, session, updateAccessed, backends, mqttPublisher),
-		sshServer:      ssh_server.New(log, config, db, backends),
+		api:            rest.New(config, db, log, session, auth, events, mqttProxy, mqttPublisher),
+		proxy:          proxy.New(config, log, session, auth, events, backends, mqttPublisher),
+		sshServer:      ssh_server.New(log, config, db, events, backends),
 		mqttProxy:      mqttProxy,
 		mqttPublisher:  mqttPublisher,
 	}
 
 	go s.sessionAccessUpdater()
+	go db.PerformPeriodicPurging(1*time.Hour, session.IsStale)
 	//go db.PerformPeriodicBackups()
 	return s
 }
@@ -374,6 +394,9 @@ func (b *localFrontend) URL() *url.URL {
 func (b *localFrontend) JsScript() *goja.Program {
 	return nil
 }
+func (b *localFrontend) SessionPolicy() *models.SessionPolicy { return nil }
+func (b *localFrontend) MaxAuthAge() time.Duration            { return 0 }
+func (b *localFrontend) AllowsBasicAuth() bool                { return false }
 func (b *localFrontend) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
 	return (&net.Dialer{
 		Timeout:   2 * time.Second,
@@ -383,14 +406,15 @@ func (b *localFrontend) DialContext(ctx context.Context, network, address string
 }
 
 func (s *Server) httpsServer() {
+	config := s.config.Get()
 	logger := log.New(os.Stdout, "", log.LstdFlags)
 
 	r := mux.NewRouter()
 
 	// Check if server is configured
-	isConfigured := s.config.Email != "" && s.config.SiteFqdn != "" && s.config.AdminFqdn != ""
+	isConfigured := config.Email != "" && config.SiteFqdn != "" && config.AdminFqdn != ""
 
-	if !s.config.IsInTestMode && !isConfigured {
+	if !config.IsInTestMode && !isConfigured {
 		// BOOTSTRAP MODE: Ignore Host header, serve bootstrap UI on all hosts
 		logger.Printf("Bootstrap mode: serving setup UI on all hosts")
 
@@ -400,23 +424,23 @@ func (s *Server) httpsServer() {
 		// Serve bootstrap frontend
 		if *flgLocalDev {
 			r.PathPrefix("/").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				s.proxy.ProxyRequest(w, r, &localFrontend{}, nil, nil)
+				s.proxy.ProxyRequest(w, r, &localFrontend{}, nil)
 			})
 		} else {
 			r.PathPrefix("/").Handler(AssetHandler(s.assets, "web/dist"))
 		}
 	} else {
 		// NORMAL MODE: Use host-based routing
-		logger.Printf("Registering API endpoint at %s", s.config.AdminFqdn)
+		logger.Printf("Registering API endpoint at %s", config.AdminFqdn)
 		s.api.RegisterEndpoints(r)
-		r.Host(s.config.AdminFqdn).Path("/authorize").HandlerFunc(s.proxy.HandleAuthorize)
+		r.Host(config.AdminFqdn).Path("/authorize").HandlerFunc(s.proxy.HandleAuthorize)
 
 		if *flgLocalDev {
-			r.Host(s.config.AdminFqdn).HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				s.proxy.ProxyRequest(w, r, &localFrontend{}, nil, nil)
+			r.Host(config.AdminFqdn).HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				s.proxy.ProxyRequest(w, r, &localFrontend{}, nil)
 			})
 		} else {
-			r.Host(s.config.AdminFqdn).Handler(AssetHandler(s.assets, "web/dist"))
+			r.Host(config.AdminFqdn).Handler(AssetHandler(s.assets, "web/dist"))
 		}
 		r.PathPrefix("/").HandlerFunc(s.proxy.ProxyHandler)
 	}
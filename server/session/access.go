@@ -0,0 +1,59 @@
+package session
+
+import (
+	"boivie/ubergang/server/models"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// The number of distinct IP address and user agent combinations to remember
+// for each session.
+const maxAccessHistory = 20
+
+// Access is a single use of a session.
+type Access struct {
+	SessionId  string
+	AccessedAt time.Time
+	RemoteAddr string
+	UserAgent  string
+	// The FQDN of the backend that the session was used for, if any.
+	Backend string
+}
+
+// RecordAccess updates `session` with `access`.
+func RecordAccess(session *models.Session, access Access) {
+	at := timestamppb.New(access.AccessedAt)
+	if session.AccessedAt == nil || session.AccessedAt.AsTime().Before(access.AccessedAt) {
+		session.AccessedAt = at
+		session.LastRemoteAddr = access.RemoteAddr
+		session.LastUserAgent = access.UserAgent
+	}
+	if access.Backend != "" {
+		if old, ok := session.BackendAccessedAt[access.Backend]; !ok || old.AsTime().Before(access.AccessedAt) {
+			if session.BackendAccessedAt == nil {
+				session.BackendAccessedAt = make(map[string]*timestamppb.Timestamp)
+			}
+			session.BackendAccessedAt[access.Backend] = at
+		}
+	}
+
+	if len(session.AccessHistory) > 0 {
+		latest := session.AccessHistory[0]
+		if latest.RemoteAddr == access.RemoteAddr && latest.UserAgent == access.UserAgent {
+			if latest.LastAccessedAt.AsTime().Before(access.AccessedAt) {
+				latest.LastAccessedAt = at
+			}
+			return
+		}
+	}
+	session.AccessHistory = append([]*models.SessionAccess{{
+		FirstAccessedAt: at,
+		LastAccessedAt:  at,
+		RemoteAddr:      access.RemoteAddr,
+		UserAgent:       access.UserAgent,
+	}}, session.AccessHistory...)
+	if len(session.AccessHistory) > maxAccessHistory {
+		session.AccessHistory = session.AccessHistory[:maxAccessHistory]
+	}
+}
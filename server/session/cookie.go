@@ -1,9 +1,12 @@
 package session
 
 import (
+	"boivie/ubergang/server/auth"
+	"boivie/ubergang/server/config"
 	"boivie/ubergang/server/db"
 	"boivie/ubergang/server/log"
 	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/security"
 	"errors"
 	"fmt"
 	"net/http"
@@ -11,19 +14,31 @@ import (
 	"time"
 )
 
+var ErrInvalidSessionSecret = errors.New("invalid session secret")
+
 type SessionStore struct {
-	log           *log.Log
-	db            *db.DB
-	sessionCookie string
-	sessionExpiry time.Duration
+	log            *log.Log
+	config         *config.Store
+	db             *db.DB
+	auth           *auth.Auth
+	updateAccessed chan<- Access
+	events         *security.Events
+	sessionCookie  string
+	sessionExpiry  time.Duration
 }
 
-func NewSessionStore(log *log.Log, db *db.DB) *SessionStore {
+// NewSessionStore creates a session store. Uses of sessions will be sent on
+// `updateAccessed`, unless it's nil.
+func NewSessionStore(log *log.Log, config *config.Store, db *db.DB, auth *auth.Auth, events *security.Events, updateAccessed chan<- Access) *SessionStore {
 	ss := &SessionStore{
-		log:           log,
-		db:            db,
-		sessionCookie: "__ug_sess",
-		sessionExpiry: 10 * 365 * 24 * time.Hour,
+		log:            log,
+		config:         config,
+		db:             db,
+		auth:           auth,
+		updateAccessed: updateAccessed,
+		events:         events,
+		sessionCookie:  "__ug_sess",
+		sessionExpiry:  10 * 365 * 24 * time.Hour,
 	}
 	return ss
 }
@@ -33,7 +48,21 @@ func (s *SessionStore) Get(r *http.Request) (*models.User, *models.Session, erro
 	if err != nil {
 		return nil, nil, err
 	}
-	return s.DecodeSessionCookie(c.Value, true /*validateSecret*/)
+	user, session, err := s.DecodeSessionCookie(c.Value, true /*validateSecret*/)
+	if err != nil {
+		s.RecordInvalid(r, err)
+	}
+	return user, session, err
+}
+
+// RecordInvalid records a security event if `err` shows that `r` used an
+// existing session with the wrong secret. Sessions that have expired or been
+// deleted, e.g. when signing out, are expected, and aren't recorded.
+func (s *SessionStore) RecordInvalid(r *http.Request, err error) {
+	if !errors.Is(err, ErrInvalidSessionSecret) {
+		return
+	}
+	s.events.Record(security.SourceHttp, s.events.ClientIP(r), "", "invalid session cookie: "+err.Error())
 }
 
 func (s *SessionStore) ReuseSession(r *http.Request) (*models.User, *models.Session, error) {
@@ -45,29 +74,100 @@ func (s *SessionStore) ReuseSession(r *http.Request) (*models.User, *models.Sess
 }
 
 func (s *SessionStore) GetWithUser(r *http.Request) (*models.User, *models.Session, error) {
-	c, err := r.Cookie(s.sessionCookie)
+	return s.Get(r)
+}
+
+// getByAccessToken authenticates a request that has an access token with the
+// admin scope, instead of a session cookie. Such requests have no session, so
+// a session that isn't stored, and has no ID, is returned in its place.
+func (s *SessionStore) getByAccessToken(r *http.Request, bearer string) (*models.User, *models.Session, error) {
+	user, token, err := s.auth.ValidateAccessToken(bearer, time.Now())
 	if err != nil {
+		s.events.Record(security.SourceHttp, s.events.ClientIP(r), "", "invalid access token: "+err.Error())
 		return nil, nil, err
 	}
-	return s.DecodeSessionCookie(c.Value, true /*validateSecret*/)
+	if !auth.HasScope(token, auth.ScopeAdmin) || !user.IsAdmin {
+		return nil, nil, fmt.Errorf("access token %s can't be used for the admin API", token.Id)
+	}
+	return user, &models.Session{UserId: user.Id}, nil
 }
 
 func (s *SessionStore) GetAndValidate(w http.ResponseWriter, r *http.Request) (*models.User, *models.Session, error) {
+	if bearer, found := auth.BearerToken(r); found && auth.IsAccessToken(bearer) {
+		user, session, err := s.getByAccessToken(r, bearer)
+		if err != nil {
+			s.log.Warnf("Failed to authenticate: %v", err)
+			http.Error(w, "Not authorized", http.StatusForbidden)
+		}
+		return user, session, err
+	}
+
 	user, session, err := s.Get(r)
 	if err != nil {
 		s.log.Warnf("Failed to authenticate: %v", err)
 		http.Error(w, "Not authorized", http.StatusForbidden)
 		return nil, nil, err
 	}
+	if session.ImpersonatorId != "" && r.Method != http.MethodGet && r.Method != http.MethodHead {
+		// Administrators may look, but not touch.
+		s.log.Warnf("Rejected %s %s from impersonation session %s", r.Method, r.URL.Path, session.Id)
+		http.Error(w, "Not allowed while viewing as another user", http.StatusForbidden)
+		return nil, nil, ErrImpersonating
+	}
+	s.Touch(session, r)
 	return user, session, err
 }
 
+// GetAndValidateSession is like GetAndValidate, for requests that are bound to
+// the caller's session, such as enrolling a passkey or confirming a sign-in on
+// another device. Access tokens have no session, so they are rejected.
+func (s *SessionStore) GetAndValidateSession(w http.ResponseWriter, r *http.Request) (*models.User, *models.Session, error) {
+	if bearer, found := auth.BearerToken(r); found && auth.IsAccessToken(bearer) {
+		s.log.Warnf("Rejected %s %s using an access token", r.Method, r.URL.Path)
+		http.Error(w, "Not allowed using an access token", http.StatusForbidden)
+		return nil, nil, ErrNoSession
+	}
+	return s.GetAndValidate(w, r)
+}
+
+// Touch records that the session has been used by `r`.
+func (s *SessionStore) Touch(session *models.Session, r *http.Request) {
+	s.TouchBackend(session, r, "")
+}
+
+// TouchBackend records that the session has been used by `r` to access
+// `backend`.
+func (s *SessionStore) TouchBackend(session *models.Session, r *http.Request, backend string) {
+	if s.updateAccessed == nil {
+		return
+	}
+	access := Access{
+		SessionId:  session.Id,
+		AccessedAt: time.Now(),
+		RemoteAddr: s.events.ClientIP(r),
+		UserAgent:  r.UserAgent(),
+		Backend:    backend,
+	}
+	select {
+	case s.updateAccessed <- access:
+	default:
+		s.log.Debugf("Dropped access update for session %s", session.Id)
+	}
+}
+
 func (s *SessionStore) CreateSessionCookie(session *models.Session) *http.Cookie {
+	expiry := s.sessionExpiry
+	if policy := s.config.Get().SessionPolicy; policy != nil && policy.AbsoluteLifetime.AsDuration() > 0 {
+		expiry = time.Until(AuthenticatedAt(session).Add(policy.AbsoluteLifetime.AsDuration()))
+	}
+	if session.ExpiresAt != nil {
+		expiry = min(expiry, time.Until(session.ExpiresAt.AsTime()))
+	}
 	return &http.Cookie{
 		Name:    s.sessionCookie,
 		Path:    "/",
 		Value:   s.EncodeSessionCookie(session),
-		Expires: time.Now().Add(s.sessionExpiry),
+		Expires: time.Now().Add(expiry),
 		Secure:  true,
 	}
 }
@@ -85,10 +185,51 @@ func (s *SessionStore) DecodeSessionCookie(value string, validateSecret bool) (*
 	if err != nil {
 		return nil, nil, err
 	}
+	if user.IsDisabled {
+		return nil, nil, errors.New("user is disabled")
+	}
 
-	if validateSecret && session.Secret != parts[1] {
-		return nil, nil, errors.New("invalid session secret")
+	if validateSecret {
+		if session.Secret != parts[1] {
+			return nil, nil, ErrInvalidSessionSecret
+		}
+		if err := CheckPolicy(s.config.Get().SessionPolicy, session, time.Now()); err != nil {
+			return nil, nil, err
+		}
+		if session.ImpersonatorId != "" {
+			if _, err := s.Impersonator(session); err != nil {
+				return nil, nil, err
+			}
+		}
 	}
 
 	return user, session, nil
 }
+
+// Impersonator returns the administrator that uses `session` to view the site
+// as another user, if they still are an administrator.
+func (s *SessionStore) Impersonator(session *models.Session) (*models.User, error) {
+	admin, err := s.db.GetUserById(session.ImpersonatorId)
+	if err != nil {
+		return nil, err
+	}
+	if !admin.IsAdmin || admin.IsDisabled {
+		return nil, errors.New("impersonator is no longer an administrator")
+	}
+	return admin, nil
+}
+
+// IsStale returns true if `session` is no longer valid at `now` according to
+// the global session policy.
+func (s *SessionStore) IsStale(session *models.Session, now time.Time) bool {
+	return CheckPolicy(s.config.Get().SessionPolicy, session, now) != nil
+}
+
+// ReapExpired deletes all sessions that are no longer valid according to the
+// global session policy, and returns the number of deleted sessions.
+func (s *SessionStore) ReapExpired() (int, error) {
+	now := time.Now()
+	return s.db.DeleteSessionsIf(func(session *models.Session) bool {
+		return s.IsStale(session, now)
+	})
+}
@@ -0,0 +1,90 @@
+package session
+
+import (
+	"boivie/ubergang/server/models"
+	"errors"
+	"time"
+)
+
+var (
+	ErrSessionExpired     = errors.New("session has expired")
+	ErrSessionIdle        = errors.New("session has been idle for too long")
+	ErrAuthenticationAged = errors.New("session has not been verified recently enough")
+	ErrImpersonating      = errors.New("session is used to view the site as another user")
+	ErrNoSession          = errors.New("access tokens have no session")
+)
+
+// AuthenticatedAt returns when the user last signed in using the session.
+// Sessions created before this was tracked fall back to their creation time.
+func AuthenticatedAt(session *models.Session) time.Time {
+	if session.AuthenticatedAt != nil {
+		return session.AuthenticatedAt.AsTime()
+	}
+	return session.CreatedAt.AsTime()
+}
+
+// LastActiveAt returns when the session was last used, or when the user last
+// signed in if it hasn't been used since.
+func LastActiveAt(session *models.Session) time.Time {
+	authenticatedAt := AuthenticatedAt(session)
+	if session.AccessedAt != nil && session.AccessedAt.AsTime().After(authenticatedAt) {
+		return session.AccessedAt.AsTime()
+	}
+	return authenticatedAt
+}
+
+// LastBackendActiveAt returns when the session was last used for `backend`, or
+// when the user last signed in if it hasn't been used for it since.
+func LastBackendActiveAt(session *models.Session, backend string) time.Time {
+	authenticatedAt := AuthenticatedAt(session)
+	if at, ok := session.BackendAccessedAt[backend]; ok && at.AsTime().After(authenticatedAt) {
+		return at.AsTime()
+	}
+	return authenticatedAt
+}
+
+// CheckPolicy returns an error if `session` is no longer valid according to
+// `policy`. A nil policy never expires any session, except those that have an
+// expiry time of their own.
+func CheckPolicy(policy *models.SessionPolicy, session *models.Session, now time.Time) error {
+	return checkPolicy(policy, session, LastActiveAt(session), now)
+}
+
+// CheckBackendPolicy is like CheckPolicy, but for the policy of `backend`. The
+// idle timeout only considers when the session was last used for that
+// backend, so that using other backends doesn't keep it alive.
+func CheckBackendPolicy(policy *models.SessionPolicy, backend string, session *models.Session, now time.Time) error {
+	return checkPolicy(policy, session, LastBackendActiveAt(session, backend), now)
+}
+
+func checkPolicy(policy *models.SessionPolicy, session *models.Session, lastActiveAt time.Time, now time.Time) error {
+	if session.ExpiresAt != nil && now.After(session.ExpiresAt.AsTime()) {
+		return ErrSessionExpired
+	}
+	if policy == nil {
+		return nil
+	}
+	if policy.AbsoluteLifetime != nil && policy.AbsoluteLifetime.AsDuration() > 0 {
+		if now.Sub(AuthenticatedAt(session)) > policy.AbsoluteLifetime.AsDuration() {
+			return ErrSessionExpired
+		}
+	}
+	if policy.IdleTimeout != nil && policy.IdleTimeout.AsDuration() > 0 {
+		if now.Sub(lastActiveAt) > policy.IdleTimeout.AsDuration() {
+			return ErrSessionIdle
+		}
+	}
+	return nil
+}
+
+// CheckAuthAge returns an error if the session hasn't been verified with a
+// passkey assertion within `maxAge`. A zero `maxAge` disables the check.
+func CheckAuthAge(maxAge time.Duration, session *models.Session, now time.Time) error {
+	if maxAge <= 0 {
+		return nil
+	}
+	if session.VerifiedAt == nil || now.Sub(session.VerifiedAt.AsTime()) > maxAge {
+		return ErrAuthenticationAged
+	}
+	return nil
+}
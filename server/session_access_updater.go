@@ -2,32 +2,47 @@ package server
 
 import (
 	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/session"
 	"time"
 )
 
+// sessionAccessUpdater collects session uses and periodically writes them to
+// the database in a single transaction.
 func (s *Server) sessionAccessUpdater() {
-	toUpdate := make(map[string]time.Time)
-	flush := make(chan *models.Session, 1)
+	pending := make(map[string][]session.Access)
+	ticker := time.NewTicker(1 * time.Minute)
+	defer ticker.Stop()
 	for {
 		select {
-		case c := <-s.updateAccessed:
-			if _, found := toUpdate[c.Id]; !found {
-				go func() {
-					time.Sleep(1 * time.Minute)
-					flush <- c
-				}()
+		case access := <-s.updateAccessed:
+			accesses := pending[access.SessionId]
+			if n := len(accesses); n > 0 &&
+				accesses[n-1].RemoteAddr == access.RemoteAddr &&
+				accesses[n-1].UserAgent == access.UserAgent &&
+				accesses[n-1].Backend == access.Backend {
+				accesses[n-1] = access
+			} else {
+				accesses = append(accesses, access)
 			}
-			toUpdate[c.Id] = time.Now()
-		case session := <-flush:
-			// for idx := range u.Sessions {
-			// 	if u.Sessions[idx].ID == session.ID {
-			// 		u.Sessions[idx].AccessedAt = toUpdate[session.ID].Format(time.RFC3339)
-			// 	}
-			// }
-			// tx := s.storage.StartTransaction("Update access time")
-			// tx.UpdateUser(u)
-			// tx.Commit()
-			delete(toUpdate, session.Id)
+			pending[access.SessionId] = accesses
+		case <-ticker.C:
+			if len(pending) == 0 {
+				continue
+			}
+			ids := make([]string, 0, len(pending))
+			for id := range pending {
+				ids = append(ids, id)
+			}
+			err := s.db.UpdateSessions(ids, func(old *models.Session) (*models.Session, error) {
+				for _, access := range pending[old.Id] {
+					session.RecordAccess(old, access)
+				}
+				return old, nil
+			})
+			if err != nil {
+				s.log.Warnf("Failed to update session access times: %v", err)
+			}
+			pending = make(map[string][]session.Access)
 		}
 	}
 }
@@ -2,9 +2,11 @@ package ssh_server
 
 import (
 	"boivie/ubergang/server/backends"
+	"boivie/ubergang/server/config"
 	"boivie/ubergang/server/db"
 	"boivie/ubergang/server/log"
 	"boivie/ubergang/server/models"
+	"boivie/ubergang/server/security"
 	"context"
 	"crypto/sha256"
 	"encoding/json"
@@ -12,6 +14,7 @@ import (
 	"io"
 	"net"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/dop251/goja"
@@ -52,9 +55,13 @@ type contextKey struct {
 var ContextKey = &contextKey{"ug_ctx"}
 
 type ugCtx struct {
-	SshKeyID      string
-	SshKeyValid   bool
-	addedBackends []*roamingBackend
+	SshKeyID         string
+	ServiceAccountID string
+	SshKeyValid      bool
+	addedBackends    []*roamingBackend
+	// Why the last offered key was rejected, and the user it claimed to be.
+	authFailure     string
+	authFailureUser string
 }
 
 func getCtx(c ssh.Context) *ugCtx {
@@ -64,11 +71,35 @@ func getCtx(c ssh.Context) *ugCtx {
 	return c.Value(ContextKey).(*ugCtx)
 }
 
+// authFailureConn records a security event when a connection is closed without
+// having authenticated. Clients commonly offer several keys, so a rejected key
+// is only a failure if no other key is accepted.
+type authFailureConn struct {
+	net.Conn
+	ctx    ssh.Context
+	events *security.Events
+	once   sync.Once
+}
+
+func (c *authFailureConn) Close() error {
+	c.once.Do(func() {
+		if c.ctx.Value(ssh.ContextKeyConn) != nil {
+			// Authenticated.
+			return
+		}
+		if uc := getCtx(c.ctx); uc.authFailure != "" {
+			c.events.Record(security.SourceSsh, security.Host(c.RemoteAddr()), uc.authFailureUser, uc.authFailure)
+		}
+	})
+	return c.Conn.Close()
+}
+
 type SSHServer struct {
 	log      *log.Log
-	config   *models.Configuration
+	config   *config.Store
 	db       *db.DB
 	backends *backends.BackendManager
+	events   *security.Events
 }
 
 type roamingConn struct {
@@ -102,6 +133,9 @@ func (b *roamingBackend) URL() *url.URL {
 func (b *roamingBackend) JsScript() *goja.Program {
 	return nil
 }
+func (b *roamingBackend) SessionPolicy() *models.SessionPolicy { return nil }
+func (b *roamingBackend) MaxAuthAge() time.Duration            { return 0 }
+func (b *roamingBackend) AllowsBasicAuth() bool                { return false }
 func (b *roamingBackend) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
 	payload := gossh.Marshal(&remoteForwardChannelData{
 		DestAddr:   b.bindAddr,
@@ -118,8 +152,8 @@ func (b *roamingBackend) DialContext(ctx context.Context, network, address strin
 	return &roamingConn{ch}, nil
 }
 
-func New(log *log.Log, config *models.Configuration, db *db.DB, backends *backends.BackendManager) *SSHServer {
-	return &SSHServer{log, config, db, backends}
+func New(log *log.Log, config *config.Store, db *db.DB, events *security.Events, backends *backends.BackendManager) *SSHServer {
+	return &SSHServer{log, config, db, backends, events}
 }
 
 func (s *SSHServer) DirectTCPIPHandler(srv *ssh.Server, conn *gossh.ServerConn, newChan gossh.NewChannel, ctx ssh.Context) {
@@ -196,7 +230,7 @@ func (s *SSHServer) RemoteForwardHandler(ctx ssh.Context, srv *ssh.Server, req *
 		return false, []byte{}
 	}
 
-	host := reqPayload.BindAddr + "-roam." + s.config.SiteFqdn
+	host := reqPayload.BindAddr + "-roam." + s.config.Get().SiteFqdn
 	backend := &roamingBackend{
 		conn:     ctx.Value(ssh.ContextKeyConn).(*gossh.ServerConn),
 		log:      s.log,
@@ -274,18 +308,42 @@ func (s *SSHServer) ServeSSH(keyPem []byte, port int) {
 		RequestHandlers: map[string]ssh.RequestHandler{
 			"tcpip-forward": s.RemoteForwardHandler,
 		},
+		ConnCallback: func(ctx ssh.Context, conn net.Conn) net.Conn {
+			if s.events.Banned(security.SourceSsh, security.Host(conn.RemoteAddr())) {
+				return nil
+			}
+			return &authFailureConn{Conn: conn, ctx: ctx, events: s.events}
+		},
 		PublicKeyHandler: func(ctx ssh.Context, pubKey ssh.PublicKey) bool {
 			c := getCtx(ctx)
+			reject := func(user, reason string) bool {
+				c.authFailureUser = user
+				c.authFailure = reason
+				return false
+			}
 			sha256Fingerprint := sha256.Sum256(pubKey.Marshal())
 			key, err := s.db.GetSshKeyByFingerprint(sha256Fingerprint[:])
 			if err != nil {
-				s.log.Warnf("Failed to find ssh key")
-				return false
+				account, err := s.db.GetServiceAccountBySshFingerprint(sha256Fingerprint[:])
+				if err != nil {
+					return reject(ctx.User(), "unknown key "+gossh.FingerprintSHA256(pubKey))
+				}
+				if account.Disabled {
+					s.log.Infof("Rejecting key for disabled service account %s", account.Name)
+					return reject(account.Name, "service account is disabled")
+				}
+				// Service accounts don't need to periodically confirm their keys.
+				c.ServiceAccountID = account.Id
+				c.SshKeyValid = true
+				s.log.Infof("Accepting key for service account %s", account.Name)
+				return true
 			}
 			user, err := s.db.GetUserById(key.UserId)
 			if err != nil {
-				s.log.Warnf("Failed to find user: %s", key.UserId)
-				return false
+				return reject(ctx.User(), "key "+key.Name+" belongs to unknown user "+key.UserId)
+			}
+			if user.IsDisabled {
+				return reject(user.Email, "user is disabled")
 			}
 			c.SshKeyID = key.Id
 			if key.ExpiresAt == nil {
@@ -15,14 +15,15 @@ func (s *Server) StartTestMode() {
 			os.Exit(1)
 		}
 
-		s.config = &models.Configuration{
+		config := &models.Configuration{
 			Email:        "hello@example.com",
 			SiteFqdn:     "example.com",
 			AdminFqdn:    ADMIN_HOST,
 			IsInTestMode: true,
 		}
+		s.config.Set(config)
 
-		return s.config, nil
+		return config, nil
 	}); err != nil {
 		fmt.Printf("Failed to update configuration: %v\n", err)
 		os.Exit(1)
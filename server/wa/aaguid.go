@@ -2,8 +2,15 @@ package wa
 
 import (
 	"encoding/hex"
+	"sort"
 )
 
+// Authenticator is a kind of authenticator that passkeys can be stored in.
+type Authenticator struct {
+	Aaguid string
+	Name   string
+}
+
 func FormatAaguidBytesToString(b []byte) string {
 	if len(b) != 16 {
 		b = make([]byte, 16)
@@ -18,3 +25,25 @@ func (wa *WA) ResolveNameFromAaGuid(aaguid []byte) string {
 	}
 	return "Unnamed passkey"
 }
+
+// AuthenticatorName returns the name of the authenticator with `aaguid`, or an
+// empty string if it's not known.
+func (wa *WA) AuthenticatorName(aaguid string) string {
+	return wa.aaguidMap[aaguid].Name
+}
+
+// KnownAuthenticators returns all authenticators with known names, sorted by
+// name.
+func (wa *WA) KnownAuthenticators() []Authenticator {
+	ret := make([]Authenticator, 0, len(wa.aaguidMap))
+	for aaguid, entry := range wa.aaguidMap {
+		ret = append(ret, Authenticator{Aaguid: aaguid, Name: entry.Name})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Name != ret[j].Name {
+			return ret[i].Name < ret[j].Name
+		}
+		return ret[i].Aaguid < ret[j].Aaguid
+	})
+	return ret
+}
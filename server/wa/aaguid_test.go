@@ -1,6 +1,7 @@
 package wa
 
 import (
+	"boivie/ubergang/server/config"
 	"boivie/ubergang/server/db"
 	"boivie/ubergang/server/log"
 	"boivie/ubergang/server/models"
@@ -14,9 +15,9 @@ import (
 )
 
 func TestAaGuid(t *testing.T) {
-	config := &models.Configuration{
+	config := config.New(&models.Configuration{
 		AdminFqdn: "test.example.com",
-	}
+	})
 
 	log := log.NewLogger(log.Fields{})
 	db, err := db.New(log, path.Join(t.TempDir(), "test.db"))
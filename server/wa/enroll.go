@@ -19,8 +19,20 @@ func getCredentialSid(credentialID []byte) string {
 	return base64.RawURLEncoding.EncodeToString(hash[0:18])
 }
 
-func (s *WA) CreateEnrollRequest(user *models.User, sessionId string, credentials []*models.Credential) (state *models.AuthenticationState, ret *api.ApiPublicKeyCredentialCreationOptions, err error) {
-	creation, session, err := s.webAuthn.BeginRegistration(NewUser(user, credentials))
+// CreateEnrollRequest starts enrolling a passkey, stored at `attachment` if
+// set. Otherwise, it's stored where the WebAuthn settings say.
+func (s *WA) CreateEnrollRequest(user *models.User, sessionId string, credentials []*models.Credential, attachment string) (state *models.AuthenticationState, ret *api.ApiPublicKeyCredentialCreationOptions, err error) {
+	attachment, err = s.chooseAttachment(attachment)
+	if err != nil {
+		return
+	}
+	rp, err := s.relyingParty(s.RPID())
+	if err != nil {
+		return
+	}
+	creation, session, err := rp.BeginRegistration(NewUser(user, credentials),
+		webauthn.WithAuthenticatorSelection(s.authenticatorSelection(attachment)),
+		webauthn.WithPublicKeyCredentialHints(hints(attachment)))
 	if err != nil {
 		return
 	}
@@ -31,9 +43,11 @@ func (s *WA) CreateEnrollRequest(user *models.User, sessionId string, credential
 		Challenge:          session.Challenge,
 		AllowedCredentials: session.AllowedCredentialIDs,
 		ExpiresAt:          timestamppb.New(session.Expires),
+		RpId:               rp.Config.RPID,
 		Type: &models.AuthenticationState_Enroll{
 			Enroll: &models.AuthenticationStateEnroll{
-				SessionId: sessionId,
+				SessionId:  sessionId,
+				Attachment: attachment,
 			},
 		},
 	}
@@ -66,6 +80,7 @@ func (s *WA) CreateEnrollRequest(user *models.User, sessionId string, credential
 			ResidentKey:             string(creation.Response.AuthenticatorSelection.ResidentKey),
 			UserVerification:        string(creation.Response.AuthenticatorSelection.UserVerification),
 		},
+		Hints: hintStrings(creation.Response.Hints),
 	}
 
 	return
@@ -90,15 +105,30 @@ func (w *WA) CreateCredential(user *models.User, sess *models.Session, state *mo
 	decoded, _ := base64.RawURLEncoding.DecodeString(attestationResponse.AttestationObject)
 	response.AttestationResponse.AttestationObject = protocol.URLEncodedBase64(decoded)
 	response.AttestationResponse.Transports = attestationResponse.Transports
+	response.AuthenticatorAttachment = attestationResponse.AuthenticatorAttachment
 
 	pcc, err := response.Parse()
 	if err != nil {
 		return
 	}
-	cred, err := w.webAuthn.CreateCredential(NewUser(user, []*models.Credential{}), session, pcc)
+	rp, err := w.relyingParty(state.RpId)
 	if err != nil {
 		return
 	}
+	cred, err := rp.CreateCredential(NewUser(user, []*models.Credential{}), session, pcc)
+	if err != nil {
+		return
+	}
+	if !AllowsAuthenticator(w.config.Get().PasskeyPolicy, cred.Authenticator.AAGUID) {
+		err = ErrAuthenticatorNotAllowed
+		return
+	}
+
+	// Not all browsers report where the passkey was stored.
+	attachment := string(cred.Authenticator.Attachment)
+	if attachment == "" {
+		attachment = state.GetEnroll().GetAttachment()
+	}
 
 	now := time.Now()
 	credential = &models.Credential{
@@ -119,11 +149,12 @@ func (w *WA) CreateCredential(user *models.User, sess *models.Session, state *mo
 				Aaguid:             cred.Authenticator.AAGUID,
 				SignCount:          cred.Authenticator.SignCount,
 				CloneWarning:       cred.Authenticator.CloneWarning,
-				Attachment:         string(cred.Authenticator.Attachment),
+				Attachment:         attachment,
 				FlagUserPresent:    cred.Flags.UserPresent,
 				FlagUserVerified:   cred.Flags.UserVerified,
 				FlagBackupEligible: cred.Flags.BackupEligible,
 				FlagBackupState:    cred.Flags.BackupState,
+				RpId:               rp.Config.RPID,
 			},
 		},
 	}
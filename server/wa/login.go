@@ -4,7 +4,9 @@ import (
 	"boivie/ubergang/server/api"
 	"boivie/ubergang/server/models"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/go-webauthn/webauthn/protocol"
 	"github.com/go-webauthn/webauthn/webauthn"
@@ -23,8 +25,46 @@ func toPublicKeyCredentialDescriptor(cred protocol.CredentialDescriptor) api.Api
 	}
 }
 
+// AssertionRPIDs returns the relying party ID to sign in with, and the one to
+// fall back to if the user has no passkey for it. When the site's relying
+// party ID is used, the passkeys that were created for the admin FQDN before
+// keep working.
+func (s *WA) AssertionRPIDs() (rpId, fallback string) {
+	rpId = s.RPID()
+	if admin := s.AdminRPID(); admin != rpId {
+		fallback = admin
+	}
+	return
+}
+
+// assertionRPIDs is like AssertionRPIDs, for a user with `credentials`. As a
+// browser can only look for passkeys of one relying party ID at a time, the
+// one that the user has no passkeys for isn't asked for.
+func (s *WA) assertionRPIDs(credentials []*models.Credential) (rpId, fallback string) {
+	rpId, fallback = s.AssertionRPIDs()
+	hasPasskey := func(rpId string) bool {
+		return slices.ContainsFunc(credentials, func(c *models.Credential) bool {
+			return c.GetWebauthnCredential() != nil && s.CredentialRPID(c) == rpId
+		})
+	}
+	if fallback == "" || !hasPasskey(fallback) {
+		return rpId, ""
+	}
+	if !hasPasskey(rpId) {
+		return fallback, ""
+	}
+	return rpId, fallback
+}
+
 func (s *WA) CreateAssertion(user *models.User, credentials []*models.Credential, fixupState func(state *models.AuthenticationState)) (token string, credentialAssertion *api.ApiAssertionRequest, err error) {
-	options, st, err := s.webAuthn.BeginLogin(NewUser(user, credentials))
+	rpId, fallback := s.assertionRPIDs(credentials)
+	rp, err := s.relyingParty(rpId)
+	if err != nil {
+		return
+	}
+	options, st, err := rp.BeginLogin(NewUser(user, credentials),
+		webauthn.WithUserVerification(s.UserVerification()),
+		webauthn.WithAssertionPublicKeyCredentialHints(assertionHints(credentials)))
 	if err != nil {
 		return
 	}
@@ -45,7 +85,9 @@ func (s *WA) CreateAssertion(user *models.User, credentials []*models.Credential
 		UserId:             user.Id,
 		Challenge:          st.Challenge,
 		AllowedCredentials: st.AllowedCredentialIDs,
-		ExpiresAt:          timestamppb.New(st.Expires)}
+		ExpiresAt:          timestamppb.New(st.Expires),
+		RpId:               rp.Config.RPID,
+		FallbackRpId:       fallback}
 	fixupState(state)
 	err = s.db.StoreAuthenticationState(&stateUuid, state)
 	if err != nil {
@@ -60,10 +102,24 @@ func (s *WA) CreateAssertion(user *models.User, credentials []*models.Credential
 			return toPublicKeyCredentialDescriptor(cred)
 		}),
 		UserVerification: string(options.Response.UserVerification),
+		FallbackRPID:     fallback,
+		Hints:            hintStrings(options.Response.Hints),
 	}
 	return
 }
 
+// validationRPID returns the relying party ID that the passkey with ID
+// `credentialId` signs `state` for: the fallback if it was created for it.
+func (s *WA) validationRPID(state *models.AuthenticationState, credentialId []byte) string {
+	if state.FallbackRpId != "" {
+		stored, err := s.db.GetCredential(getCredentialSid(credentialId))
+		if err == nil && s.CredentialRPID(stored) == state.FallbackRpId {
+			return state.FallbackRpId
+		}
+	}
+	return state.RpId
+}
+
 func (s *WA) ValidateAssertion(cred *api.ApiAssertionCredential, state *models.AuthenticationState, user webauthn.User) (*webauthn.Credential, error) {
 	st := webauthn.SessionData{
 		UserVerification:     protocol.UserVerificationRequirement(state.UserVerification),
@@ -93,5 +149,38 @@ func (s *WA) ValidateAssertion(cred *api.ApiAssertionCredential, state *models.A
 		return nil, err
 	}
 
-	return s.webAuthn.ValidateLogin(user, st, par)
+	rp, err := s.relyingParty(s.validationRPID(state, par.RawID))
+	if err != nil {
+		return nil, err
+	}
+	credential, err := rp.ValidateLogin(user, st, par)
+	if err != nil {
+		return nil, err
+	}
+	policy := s.config.Get().PasskeyPolicy
+	if !AllowsAuthenticator(policy, credential.Authenticator.AAGUID) {
+		return nil, ErrAuthenticatorNotAllowed
+	}
+	if credential.Authenticator.CloneWarning {
+		// Don't let a possibly cloned passkey in without remembering it.
+		if err := s.flagCloned(credential); err != nil {
+			return nil, fmt.Errorf("failed to flag cloned passkey: %w", err)
+		}
+		if policy.GetCloneAction() == models.CloneAction_CLONE_ACTION_BLOCK {
+			return nil, ErrCloned
+		}
+	}
+	return credential, nil
+}
+
+// flagCloned records that `credential` may have been cloned, as its signature
+// counter has gone backwards. The flag is never cleared.
+func (s *WA) flagCloned(credential *webauthn.Credential) error {
+	return s.db.UpdateCredential(getCredentialSid(credential.ID), func(old *models.Credential) (*models.Credential, error) {
+		if old == nil || old.GetWebauthnCredential() == nil {
+			return nil, errors.New("credential not found")
+		}
+		old.GetWebauthnCredential().CloneWarning = true
+		return old, nil
+	})
 }
@@ -1,14 +1,10 @@
 package wa
 
 import (
+	"boivie/ubergang/server/config"
 	"boivie/ubergang/server/db"
-	"boivie/ubergang/server/models"
 	"embed"
 	"encoding/json"
-	"strings"
-
-	"github.com/go-webauthn/webauthn/protocol"
-	"github.com/go-webauthn/webauthn/webauthn"
 )
 
 type knownAaGuid struct {
@@ -18,7 +14,7 @@ type knownAaGuid struct {
 }
 
 type WA struct {
-	webAuthn  *webauthn.WebAuthn
+	config    *config.Store
 	db        *db.DB
 	aaguidMap map[string]knownAaGuid
 }
@@ -28,10 +24,7 @@ var (
 	data embed.FS
 )
 
-func New(config *models.Configuration, db *db.DB) *WA {
-	// Keep the domain, strip the port from `config.AdminFqdn`
-	rpId := strings.Split(config.AdminFqdn, ":")[0]
-
+func New(config *config.Store, db *db.DB) *WA {
 	var aaguidMap map[string]knownAaGuid
 	file, err := data.Open("webauthn-data/aaguid.json")
 	if err != nil {
@@ -44,30 +37,9 @@ func New(config *models.Configuration, db *db.DB) *WA {
 		panic(err)
 	}
 
-	wconfig := &webauthn.Config{
-		RPID:                  rpId,
-		RPDisplayName:         "ubergang",
-		RPOrigins:             []string{"https://" + config.AdminFqdn},
-		AttestationPreference: "none",
-		AuthenticatorSelection: protocol.AuthenticatorSelection{
-			AuthenticatorAttachment: "platform",
-			RequireResidentKey:      &[]bool{true}[0],
-			ResidentKey:             "required",
-			UserVerification:        "required",
-		},
-	}
-
-	webAuthn, err := webauthn.New(wconfig)
-	if err != nil {
-		panic(err)
-	}
 	return &WA{
-		webAuthn:  webAuthn,
+		config:    config,
 		db:        db,
 		aaguidMap: aaguidMap,
 	}
 }
-
-func (w *WA) RPID() string {
-	return w.webAuthn.Config.RPID
-}
@@ -0,0 +1,77 @@
+package wa
+
+import (
+	"boivie/ubergang/server/models"
+	"errors"
+	"slices"
+)
+
+// Why a passkey, or a user's set of passkeys, doesn't comply with the passkey
+// policy.
+const (
+	ViolationAuthenticatorNotAllowed = "authenticator_not_allowed"
+	ViolationCloned                  = "cloned"
+	ViolationTooFewPasskeys          = "too_few_passkeys"
+)
+
+var (
+	ErrAuthenticatorNotAllowed = errors.New("authenticator not allowed by the passkey policy")
+	ErrCloned                  = errors.New("passkey may have been cloned")
+)
+
+// AllowsAuthenticator returns true if passkeys from the authenticator with
+// `aaguid` may be enrolled and used.
+func AllowsAuthenticator(policy *models.PasskeyPolicy, aaguid []byte) bool {
+	id := FormatAaguidBytesToString(aaguid)
+	if slices.Contains(policy.GetDeniedAaguids(), id) {
+		return false
+	}
+	return len(policy.GetAllowedAaguids()) == 0 || slices.Contains(policy.GetAllowedAaguids(), id)
+}
+
+// CredentialViolations returns how `credential` violates the policy.
+func CredentialViolations(policy *models.PasskeyPolicy, credential *models.Credential) []string {
+	waCred := credential.GetWebauthnCredential()
+	if waCred == nil {
+		return nil
+	}
+	var ret []string
+	if !AllowsAuthenticator(policy, waCred.Aaguid) {
+		ret = append(ret, ViolationAuthenticatorNotAllowed)
+	}
+	if waCred.CloneWarning {
+		ret = append(ret, ViolationCloned)
+	}
+	return ret
+}
+
+// IsBlocked returns true if `credential` may not be used.
+func IsBlocked(policy *models.PasskeyPolicy, credential *models.Credential) bool {
+	waCred := credential.GetWebauthnCredential()
+	if waCred == nil {
+		return false
+	}
+	return !AllowsAuthenticator(policy, waCred.Aaguid) ||
+		(waCred.CloneWarning && policy.GetCloneAction() == models.CloneAction_CLONE_ACTION_BLOCK)
+}
+
+// CountUsablePasskeys returns the number of passkeys in `credentials` that
+// aren't blocked.
+func CountUsablePasskeys(policy *models.PasskeyPolicy, credentials []*models.Credential) int {
+	count := 0
+	for _, c := range credentials {
+		if c.GetWebauthnCredential() != nil && !IsBlocked(policy, c) {
+			count++
+		}
+	}
+	return count
+}
+
+// UserViolations returns how the passkeys of a user violate the policy, as a
+// whole.
+func UserViolations(policy *models.PasskeyPolicy, credentials []*models.Credential) []string {
+	if CountUsablePasskeys(policy, credentials) < int(policy.GetMinPasskeys()) {
+		return []string{ViolationTooFewPasskeys}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package wa
+
+import (
+	"boivie/ubergang/server/models"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func passkey(aaguid string, cloned bool) *models.Credential {
+	b, _ := hex.DecodeString(aaguid)
+	return &models.Credential{
+		Type: &models.Credential_WebauthnCredential{
+			WebauthnCredential: &models.WebAuthnCredential{Aaguid: b, CloneWarning: cloned},
+		},
+	}
+}
+
+func TestPasskeyPolicy(t *testing.T) {
+	chrome := "adce000235bcc60a648b0b25f1f05503"
+	other := "11111111111111111111111111111111"
+
+	t.Run("allows everything by default", func(t *testing.T) {
+		assert.True(t, AllowsAuthenticator(nil, passkey(chrome, false).GetWebauthnCredential().Aaguid))
+		assert.Empty(t, CredentialViolations(nil, passkey(chrome, false)))
+		assert.False(t, IsBlocked(nil, passkey(chrome, true)))
+		assert.Empty(t, UserViolations(nil, nil))
+	})
+
+	t.Run("allowed and denied authenticators", func(t *testing.T) {
+		allowed := &models.PasskeyPolicy{AllowedAaguids: []string{"adce0002-35bc-c60a-648b-0b25f1f05503"}}
+		assert.False(t, IsBlocked(allowed, passkey(chrome, false)))
+		assert.True(t, IsBlocked(allowed, passkey(other, false)))
+		assert.Equal(t, []string{ViolationAuthenticatorNotAllowed}, CredentialViolations(allowed, passkey(other, false)))
+
+		denied := &models.PasskeyPolicy{DeniedAaguids: []string{"adce0002-35bc-c60a-648b-0b25f1f05503"}}
+		assert.True(t, IsBlocked(denied, passkey(chrome, false)))
+		assert.False(t, IsBlocked(denied, passkey(other, false)))
+	})
+
+	t.Run("cloned passkeys", func(t *testing.T) {
+		assert.Equal(t, []string{ViolationCloned}, CredentialViolations(nil, passkey(chrome, true)))
+		block := &models.PasskeyPolicy{CloneAction: models.CloneAction_CLONE_ACTION_BLOCK}
+		assert.True(t, IsBlocked(block, passkey(chrome, true)))
+		assert.False(t, IsBlocked(block, passkey(chrome, false)))
+	})
+
+	t.Run("minimum number of passkeys", func(t *testing.T) {
+		policy := &models.PasskeyPolicy{MinPasskeys: 2, CloneAction: models.CloneAction_CLONE_ACTION_BLOCK}
+		totp := &models.Credential{Type: &models.Credential_TotpCredential{TotpCredential: &models.TotpCredential{}}}
+		assert.Equal(t, []string{ViolationTooFewPasskeys},
+			UserViolations(policy, []*models.Credential{passkey(chrome, false), totp}))
+		// Blocked passkeys don't count.
+		assert.Equal(t, []string{ViolationTooFewPasskeys},
+			UserViolations(policy, []*models.Credential{passkey(chrome, false), passkey(chrome, true)}))
+		assert.Empty(t, UserViolations(policy, []*models.Credential{passkey(chrome, false), passkey(other, false)}))
+	})
+}
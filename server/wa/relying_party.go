@@ -0,0 +1,86 @@
+package wa
+
+import (
+	"boivie/ubergang/server/models"
+	"slices"
+	"strings"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+// hostname returns `fqdn` without the port.
+func hostname(fqdn string) string {
+	return strings.Split(fqdn, ":")[0]
+}
+
+// AdminRPID returns the relying party ID of the admin FQDN, which passkeys
+// have been created for unless the site's is used.
+func (w *WA) AdminRPID() string {
+	return hostname(w.config.Get().AdminFqdn)
+}
+
+// RPID returns the relying party ID that new passkeys are created for.
+func (w *WA) RPID() string {
+	config := w.config.Get()
+	if config.Webauthn.GetUseSiteRpId() && config.SiteFqdn != "" {
+		return hostname(config.SiteFqdn)
+	}
+	return w.AdminRPID()
+}
+
+// CredentialRPID returns the relying party ID that `credential` was created
+// for.
+func (w *WA) CredentialRPID(credential *models.Credential) string {
+	if rpId := credential.GetWebauthnCredential().GetRpId(); rpId != "" {
+		return rpId
+	}
+	return w.AdminRPID()
+}
+
+// AdminOrigin returns the only origin that passkeys are accepted from when
+// signing in or enrolling through the admin FQDN.
+func (w *WA) AdminOrigin() string {
+	return "https://" + w.config.Get().AdminFqdn
+}
+
+// RelatedOrigins returns the origins of the backends that may use the site's
+// passkeys for step-up authentication within `/_ubergang/`. There are none
+// unless they have been enabled together with the site's relying party ID.
+// Public backends are never included, as anyone can put content on them.
+func (w *WA) RelatedOrigins() []string {
+	config := w.config.Get()
+	if !config.Webauthn.GetUseSiteRpId() || !config.Webauthn.GetRelatedOrigins() || config.SiteFqdn == "" {
+		return nil
+	}
+	ret := []string{}
+	for _, backend := range w.db.ListBackends() {
+		if backend.AccessLevel == models.AccessLevel_PUBLIC {
+			continue
+		}
+		origin := "https://" + backend.Fqdn
+		if origin != w.AdminOrigin() && !slices.Contains(ret, origin) {
+			ret = append(ret, origin)
+		}
+	}
+	return ret
+}
+
+// WellKnownOrigins returns the origins to list at /.well-known/webauthn, which
+// lets browsers use passkeys from origins outside of their relying party ID.
+func (w *WA) WellKnownOrigins() []string {
+	return append([]string{w.AdminOrigin()}, w.RelatedOrigins()...)
+}
+
+// relyingParty returns the relying party for `rpId`. It only accepts the admin
+// origin, as all ceremonies that it's used for are on the admin FQDN.
+func (w *WA) relyingParty(rpId string) (*webauthn.WebAuthn, error) {
+	if rpId == "" {
+		rpId = w.AdminRPID()
+	}
+	return webauthn.New(&webauthn.Config{
+		RPID:                  rpId,
+		RPDisplayName:         "ubergang",
+		RPOrigins:             []string{w.AdminOrigin()},
+		AttestationPreference: "none",
+	})
+}
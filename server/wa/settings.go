@@ -0,0 +1,112 @@
+package wa
+
+import (
+	"boivie/ubergang/server/models"
+	"errors"
+
+	"github.com/go-webauthn/webauthn/protocol"
+)
+
+// Where passkeys are stored.
+const (
+	AttachmentPlatform      = string(protocol.Platform)
+	AttachmentCrossPlatform = string(protocol.CrossPlatform)
+	// Lets the user choose.
+	AttachmentAny = "any"
+)
+
+var ErrAttachmentNotAllowed = errors.New("attachment not allowed by the WebAuthn settings")
+
+func orDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
+
+// Attachment returns where passkeys may be stored.
+func (w *WA) Attachment() string {
+	return orDefault(w.config.Get().Webauthn.GetAttachment(), AttachmentPlatform)
+}
+
+// UserVerification returns whether the user must be verified, e.g. using a
+// PIN or biometrics, when using a passkey.
+func (w *WA) UserVerification() protocol.UserVerificationRequirement {
+	return protocol.UserVerificationRequirement(
+		orDefault(w.config.Get().Webauthn.GetUserVerification(), string(protocol.VerificationRequired)))
+}
+
+func (w *WA) residentKey() protocol.ResidentKeyRequirement {
+	return protocol.ResidentKeyRequirement(
+		orDefault(w.config.Get().Webauthn.GetResidentKey(), string(protocol.ResidentKeyRequirementRequired)))
+}
+
+// authenticatorSelection returns the criteria for creating a passkey stored
+// at `attachment`, or anywhere if it's empty.
+func (w *WA) authenticatorSelection(attachment string) protocol.AuthenticatorSelection {
+	residentKey := w.residentKey()
+	requireResidentKey := protocol.ResidentKeyNotRequired()
+	if residentKey == protocol.ResidentKeyRequirementRequired {
+		requireResidentKey = protocol.ResidentKeyRequired()
+	}
+	return protocol.AuthenticatorSelection{
+		AuthenticatorAttachment: protocol.AuthenticatorAttachment(attachment),
+		RequireResidentKey:      requireResidentKey,
+		ResidentKey:             residentKey,
+		UserVerification:        w.UserVerification(),
+	}
+}
+
+// chooseAttachment returns where a passkey is to be stored, given what the
+// user asked for, if anything.
+func (w *WA) chooseAttachment(requested string) (string, error) {
+	allowed := w.Attachment()
+	switch {
+	case requested == "" && allowed == AttachmentAny:
+		return "", nil
+	case requested == "":
+		return allowed, nil
+	case requested != AttachmentPlatform && requested != AttachmentCrossPlatform:
+		return "", ErrAttachmentNotAllowed
+	case allowed != AttachmentAny && requested != allowed:
+		return "", ErrAttachmentNotAllowed
+	}
+	return requested, nil
+}
+
+// hints tells the browser what kind of authenticator to suggest for
+// `attachment`.
+func hints(attachment string) []protocol.PublicKeyCredentialHints {
+	switch attachment {
+	case AttachmentPlatform:
+		return []protocol.PublicKeyCredentialHints{protocol.PublicKeyCredentialHintClientDevice}
+	case AttachmentCrossPlatform:
+		return []protocol.PublicKeyCredentialHints{protocol.PublicKeyCredentialHintSecurityKey}
+	}
+	return nil
+}
+
+// assertionHints returns the hints for signing in using one of
+// `credentials`, if they are all stored in the same kind of authenticator.
+func assertionHints(credentials []*models.Credential) []protocol.PublicKeyCredentialHints {
+	attachment := ""
+	for _, c := range credentials {
+		waCred := c.GetWebauthnCredential()
+		if waCred == nil {
+			continue
+		}
+		if waCred.Attachment == "" || (attachment != "" && waCred.Attachment != attachment) {
+			return nil
+		}
+		attachment = waCred.Attachment
+	}
+	return hints(attachment)
+}
+
+func hintStrings(hints []protocol.PublicKeyCredentialHints) []string {
+	ret := make([]string, 0, len(hints))
+	for _, h := range hints {
+		ret = append(ret, string(h))
+	}
+	return ret
+}
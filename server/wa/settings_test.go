@@ -0,0 +1,48 @@
+package wa
+
+import (
+	"boivie/ubergang/server/config"
+	"boivie/ubergang/server/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func storedAt(attachment string) *models.Credential {
+	return &models.Credential{
+		Type: &models.Credential_WebauthnCredential{
+			WebauthnCredential: &models.WebAuthnCredential{Attachment: attachment},
+		},
+	}
+}
+
+func TestChooseAttachment(t *testing.T) {
+	w := &WA{config: config.New(&models.Configuration{})}
+	attachment, err := w.chooseAttachment("")
+	assert.NoError(t, err)
+	assert.Equal(t, AttachmentPlatform, attachment)
+	_, err = w.chooseAttachment(AttachmentCrossPlatform)
+	assert.ErrorIs(t, err, ErrAttachmentNotAllowed)
+
+	w.config.Update(func(c *models.Configuration) {
+		c.Webauthn = &models.WebauthnSettings{Attachment: AttachmentAny}
+	})
+	attachment, err = w.chooseAttachment("")
+	assert.NoError(t, err)
+	assert.Empty(t, attachment)
+	attachment, err = w.chooseAttachment(AttachmentCrossPlatform)
+	assert.NoError(t, err)
+	assert.Equal(t, AttachmentCrossPlatform, attachment)
+	_, err = w.chooseAttachment(AttachmentAny)
+	assert.ErrorIs(t, err, ErrAttachmentNotAllowed)
+}
+
+func TestAssertionHints(t *testing.T) {
+	assert.Empty(t, assertionHints(nil))
+	assert.Equal(t, []string{"client-device"},
+		hintStrings(assertionHints([]*models.Credential{storedAt("platform"), storedAt("platform")})))
+	assert.Equal(t, []string{"security-key"},
+		hintStrings(assertionHints([]*models.Credential{storedAt("cross-platform")})))
+	assert.Empty(t, assertionHints([]*models.Credential{storedAt("platform"), storedAt("cross-platform")}))
+	assert.Empty(t, assertionHints([]*models.Credential{storedAt("platform"), storedAt("")}))
+}
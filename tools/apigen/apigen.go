@@ -0,0 +1,41 @@
+// apigen generates the OpenAPI document and the Go client of the JSON API.
+//
+// Usage: apigen API_TYPES_GO OPENAPI_JSON CLIENT_GO
+package main
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/api/openapi"
+	"log"
+	"os"
+)
+
+func main() {
+	if len(os.Args) != 4 {
+		log.Fatal("usage: apigen API_TYPES_GO OPENAPI_JSON CLIENT_GO")
+	}
+
+	docs, err := openapi.ParseDocs(os.Args[1])
+	if err != nil {
+		log.Fatal(err)
+	}
+	doc, err := openapi.Generate(api.Endpoints, docs)
+	if err != nil {
+		log.Fatal(err)
+	}
+	spec, err := doc.Marshal()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(os.Args[2], spec, 0644); err != nil {
+		log.Fatal(err)
+	}
+
+	client, err := openapi.GenerateClient(api.Endpoints)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(os.Args[3], client, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
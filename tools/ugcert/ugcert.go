@@ -2,6 +2,7 @@ package main
 
 import (
 	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/api/client"
 	"bytes"
 	"crypto/ed25519"
 	"crypto/rand"
@@ -15,7 +16,6 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/carlmjohnson/requests"
 	"github.com/pkg/browser"
 	"github.com/urfave/cli/v2"
 	"golang.org/x/crypto/ssh"
@@ -185,17 +185,11 @@ func updateKey(cCtx *cli.Context) error {
 	_ = os.WriteFile(fname, privateKey, 0600)
 	_ = os.WriteFile(fname+".pub", authorizedKey, 0644)
 
-	var res api.ApiProposeSshKeyResponse
 	req := api.ApiProposeSshKeyRequest{
 		KeySecret: server.KeySecret,
 		PublicKey: string(authorizedKey),
 	}
-	err = requests.
-		URL("/api/authorized_key/" + server.KeyID).
-		Host(server.Host).
-		BodyJSON(&req).
-		ToJSON(&res).
-		Fetch(cCtx.Context)
+	res, err := client.New("https://"+server.Host, "").ProposeSshKey(cCtx.Context, server.KeyID, &req)
 	if err != nil {
 		return err
 	}
@@ -209,12 +203,7 @@ func addKey(cCtx *cli.Context) error {
 	u, _ := url.Parse(cCtx.Args().First())
 	keyID := strings.TrimPrefix(u.Path, "/")
 
-	var key api.ApiSSHKey
-	err := requests.
-		URL(fmt.Sprintf("/api/authorized_key/%s", keyID)).
-		Host(u.Hostname()).
-		ToJSON(&key).
-		Fetch(cCtx.Context)
+	key, err := client.New("https://"+u.Hostname(), "").GetSshKey(cCtx.Context, keyID)
 	if err != nil {
 		return err
 	}
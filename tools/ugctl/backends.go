@@ -0,0 +1,179 @@
+package main
+
+import (
+	"boivie/ubergang/server/api"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+func backendRows(backends ...api.ApiBackend) [][]string {
+	rows := make([][]string, 0, len(backends))
+	for _, b := range backends {
+		rows = append(rows, []string{b.Fqdn, b.UpstreamUrl, b.AccessLevel, yesNo(b.JsScript != "")})
+	}
+	return rows
+}
+
+var backendHeader = []string{"FQDN", "UPSTREAM", "ACCESS", "SCRIPT"}
+
+func listBackends(cCtx *cli.Context) error {
+	c, err := newClient(cCtx)
+	if err != nil {
+		return err
+	}
+	res, err := c.ListBackends(cCtx.Context)
+	if err != nil {
+		return err
+	}
+	return printResult(cCtx, res.Backends, backendHeader, backendRows(res.Backends...))
+}
+
+func showBackend(cCtx *cli.Context) error {
+	fqdn, err := requireArg(cCtx, "FQDN")
+	if err != nil {
+		return err
+	}
+	c, err := newClient(cCtx)
+	if err != nil {
+		return err
+	}
+	backend, err := c.GetBackend(cCtx.Context, fqdn)
+	if err != nil {
+		return err
+	}
+	return printResult(cCtx, backend, backendHeader, backendRows(*backend))
+}
+
+// updateBackendRequest returns a request that changes what's given by flags,
+// and keeps the rest of `backend`.
+func updateBackendRequest(cCtx *cli.Context, backend *api.ApiBackend) (*api.ApiUpdateBackendRequest, error) {
+	req := &api.ApiUpdateBackendRequest{JsScript: backend.JsScript}
+	if cCtx.IsSet("upstream") {
+		upstream := cCtx.String("upstream")
+		req.UpstreamUrl = &upstream
+	}
+	if cCtx.IsSet("access-level") {
+		level := strings.ToUpper(cCtx.String("access-level"))
+		if level != "NORMAL" && level != "PUBLIC" {
+			return nil, fmt.Errorf("invalid access level '%s'", level)
+		}
+		req.AccessLevel = &level
+	}
+	if cCtx.IsSet("header") {
+		headers := make([]api.ApiBackendHeader, 0)
+		for _, h := range cCtx.StringSlice("header") {
+			name, value, found := strings.Cut(h, "=")
+			if !found || name == "" {
+				return nil, fmt.Errorf("expected Name=Value, got '%s'", h)
+			}
+			headers = append(headers, api.ApiBackendHeader{Name: name, Value: value})
+		}
+		req.Headers = &headers
+	}
+	if cCtx.IsSet("js-script") {
+		req.JsScript = ""
+		if filename := cCtx.String("js-script"); filename != "" {
+			script, err := os.ReadFile(filename)
+			if err != nil {
+				return nil, err
+			}
+			req.JsScript = string(script)
+		}
+	}
+	if cCtx.IsSet("max-auth-age") {
+		seconds := int64(cCtx.Duration("max-auth-age").Seconds())
+		req.MaxAuthAgeSeconds = &seconds
+	}
+	if cCtx.IsSet("allow-basic-auth") {
+		allow := cCtx.Bool("allow-basic-auth")
+		req.AllowBasicAuth = &allow
+	}
+	return req, nil
+}
+
+func createBackend(cCtx *cli.Context) error {
+	fqdn, err := requireArg(cCtx, "FQDN")
+	if err != nil {
+		return err
+	}
+	c, err := newClient(cCtx)
+	if err != nil {
+		return err
+	}
+	if _, err := c.GetBackend(cCtx.Context, fqdn); err == nil {
+		return fmt.Errorf("backend %s already exists", fqdn)
+	}
+	req, err := updateBackendRequest(cCtx, &api.ApiBackend{})
+	if err != nil {
+		return err
+	}
+	if _, err := c.UpdateBackend(cCtx.Context, fqdn, req); err != nil {
+		return err
+	}
+	fmt.Printf("Created backend %s.\n", fqdn)
+	return nil
+}
+
+func updateBackend(cCtx *cli.Context) error {
+	fqdn, err := requireArg(cCtx, "FQDN")
+	if err != nil {
+		return err
+	}
+	c, err := newClient(cCtx)
+	if err != nil {
+		return err
+	}
+	backend, err := c.GetBackend(cCtx.Context, fqdn)
+	if err != nil {
+		return err
+	}
+	req, err := updateBackendRequest(cCtx, backend)
+	if err != nil {
+		return err
+	}
+	if _, err := c.UpdateBackend(cCtx.Context, fqdn, req); err != nil {
+		return err
+	}
+	fmt.Printf("Updated backend %s.\n", fqdn)
+	return nil
+}
+
+func deleteBackend(cCtx *cli.Context) error {
+	fqdn, err := requireArg(cCtx, "FQDN")
+	if err != nil {
+		return err
+	}
+	c, err := newClient(cCtx)
+	if err != nil {
+		return err
+	}
+	if err := c.DeleteBackend(cCtx.Context, fqdn); err != nil {
+		return err
+	}
+	fmt.Printf("Deleted backend %s.\n", fqdn)
+	return nil
+}
+
+var backendFlags = []cli.Flag{
+	&cli.StringFlag{Name: "upstream", Usage: "The `URL` that requests are forwarded to"},
+	&cli.StringFlag{Name: "access-level", Usage: "NORMAL, or PUBLIC to not require signing in"},
+	&cli.StringSliceFlag{Name: "header", Usage: "A `Name=Value` header to add to requests. Repeat for more"},
+	&cli.StringFlag{Name: "js-script", Usage: "Handle requests with the script in `FILE`. Empty to remove"},
+	&cli.DurationFlag{Name: "max-auth-age", Usage: "Require a passkey sign-in within `DURATION`. 0 to remove"},
+	&cli.BoolFlag{Name: "allow-basic-auth", Usage: "Accept app passwords using HTTP Basic authentication"},
+}
+
+var backendCommand = &cli.Command{
+	Name:  "backend",
+	Usage: "manage backends",
+	Subcommands: []*cli.Command{
+		{Name: "list", Usage: "list backends", Action: listBackends},
+		{Name: "get", Usage: "show a backend", ArgsUsage: "FQDN", Action: showBackend},
+		{Name: "create", Usage: "create a backend", ArgsUsage: "FQDN", Flags: backendFlags, Action: createBackend},
+		{Name: "update", Usage: "change a backend", ArgsUsage: "FQDN", Flags: backendFlags, Action: updateBackend},
+		{Name: "delete", Usage: "delete a backend", ArgsUsage: "FQDN", Action: deleteBackend},
+	},
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/api/client"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/carlmjohnson/requests"
+	"github.com/pkg/browser"
+	"github.com/urfave/cli/v2"
+)
+
+// deviceLogin signs in using the OAuth device flow, with the public OIDC
+// client `clientId`, and returns an access token with the admin scope.
+func deviceLogin(cCtx *cli.Context, baseUrl, clientId string) (string, error) {
+	var authorization api.ApiDeviceAuthorizationResponse
+	err := requests.
+		URL(baseUrl + "/oauth/device_authorization").
+		BodyForm(url.Values{"client_id": {clientId}, "scope": {"admin"}}).
+		ToJSON(&authorization).
+		Fetch(cCtx.Context)
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Printf("To sign in, visit %s and enter the code %s\n", authorization.VerificationUri, authorization.UserCode)
+	_ = browser.OpenURL(authorization.VerificationUriComplete)
+
+	interval := time.Duration(authorization.Interval) * time.Second
+	deadline := time.Now().Add(time.Duration(authorization.ExpiresIn) * time.Second)
+	for time.Now().Before(deadline) {
+		time.Sleep(interval)
+
+		var token api.ApiOAuthTokenResponse
+		var oauthErr api.ApiOAuthErrorResponse
+		err := requests.
+			URL(baseUrl + "/oauth/token").
+			BodyForm(url.Values{
+				"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+				"device_code": {authorization.DeviceCode},
+				"client_id":   {clientId},
+			}).
+			AddValidator(requests.ErrorJSON(&oauthErr)).
+			ToJSON(&token).
+			Fetch(cCtx.Context)
+		switch {
+		case err == nil:
+			if token.AccessToken == "" {
+				return "", errors.New("the server didn't grant an access token")
+			}
+			return token.AccessToken, nil
+		case oauthErr.Error == "authorization_pending":
+		case oauthErr.Error == "slow_down":
+			interval += 5 * time.Second
+		case oauthErr.Error != "":
+			return "", fmt.Errorf("failed to sign in: %s", oauthErr.Error)
+		default:
+			return "", err
+		}
+	}
+	return "", errors.New("the code expired before it was used")
+}
+
+func login(cCtx *cli.Context) error {
+	config, err := readConfig(cCtx.String("config"))
+	if err != nil {
+		return err
+	}
+	server := cCtx.String("server")
+	if server == "" {
+		server = config.Server
+	}
+	if server == "" {
+		return errors.New("the server must be given with --server")
+	}
+
+	token := cCtx.String("token")
+	if token == "" {
+		clientId := cCtx.String("client-id")
+		if clientId == "" {
+			return errors.New("either --token or --client-id must be given")
+		}
+		token, err = deviceLogin(cCtx, serverUrl(server), clientId)
+		if err != nil {
+			return err
+		}
+	}
+
+	me, err := client.New(serverUrl(server), token).GetUser(cCtx.Context, "me")
+	if err != nil {
+		return err
+	}
+
+	config.Server = server
+	config.Token = token
+	if err := writeConfig(cCtx.String("config"), config); err != nil {
+		return err
+	}
+	fmt.Printf("Signed in to %s as %s.\n", server, me.Email)
+	return nil
+}
+
+func logout(cCtx *cli.Context) error {
+	config, err := readConfig(cCtx.String("config"))
+	if err != nil {
+		return err
+	}
+	config.Token = ""
+	if err := writeConfig(cCtx.String("config"), config); err != nil {
+		return err
+	}
+	fmt.Println("Signed out. Revoke the access token in the web UI if it's no longer needed.")
+	return nil
+}
+
+var loginCommand = &cli.Command{
+	Name:  "login",
+	Usage: "sign in, using an access token or the device flow",
+	Description: "Either give an access token with the admin scope using --token, or the id\n" +
+		"of a public OIDC client with --client-id to sign in using a browser.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "client-id",
+			Usage: "Sign in using the device flow of the public OIDC client `ID`",
+		},
+	},
+	Action: login,
+}
+
+var logoutCommand = &cli.Command{
+	Name:   "logout",
+	Usage:  "forget the stored access token",
+	Action: logout,
+}
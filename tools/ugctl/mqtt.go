@@ -0,0 +1,273 @@
+package main
+
+import (
+	"boivie/ubergang/server/api"
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+var mqttProfileHeader = []string{"ID", "PUBLISH", "SUBSCRIBE"}
+
+func mqttProfileRows(profiles ...api.ApiMqttProfile) [][]string {
+	rows := make([][]string, 0, len(profiles))
+	for _, p := range profiles {
+		rows = append(rows, []string{p.Id, strings.Join(p.AllowPublish, ","), strings.Join(p.AllowSubscribe, ",")})
+	}
+	return rows
+}
+
+func listMqttProfiles(cCtx *cli.Context) error {
+	c, err := newClient(cCtx)
+	if err != nil {
+		return err
+	}
+	res, err := c.ListMqttProfiles(cCtx.Context)
+	if err != nil {
+		return err
+	}
+	return printResult(cCtx, res.MqttProfiles, mqttProfileHeader, mqttProfileRows(res.MqttProfiles...))
+}
+
+func showMqttProfile(cCtx *cli.Context) error {
+	id, err := requireArg(cCtx, "ID")
+	if err != nil {
+		return err
+	}
+	c, err := newClient(cCtx)
+	if err != nil {
+		return err
+	}
+	profile, err := c.GetMqttProfile(cCtx.Context, id)
+	if err != nil {
+		return err
+	}
+	return printResult(cCtx, profile, mqttProfileHeader, mqttProfileRows(*profile))
+}
+
+func updateMqttProfileRequest(cCtx *cli.Context) *api.ApiUpdateMqttProfileRequest {
+	req := &api.ApiUpdateMqttProfileRequest{}
+	if cCtx.IsSet("allow-publish") {
+		topics := cCtx.StringSlice("allow-publish")
+		req.AllowPublish = &topics
+	}
+	if cCtx.IsSet("allow-subscribe") {
+		topics := cCtx.StringSlice("allow-subscribe")
+		req.AllowSubscribe = &topics
+	}
+	return req
+}
+
+func createMqttProfile(cCtx *cli.Context) error {
+	id, err := requireArg(cCtx, "ID")
+	if err != nil {
+		return err
+	}
+	c, err := newClient(cCtx)
+	if err != nil {
+		return err
+	}
+	if _, err := c.GetMqttProfile(cCtx.Context, id); err == nil {
+		return fmt.Errorf("MQTT profile %s already exists", id)
+	}
+	if _, err := c.UpdateMqttProfile(cCtx.Context, id, updateMqttProfileRequest(cCtx)); err != nil {
+		return err
+	}
+	fmt.Printf("Created MQTT profile %s.\n", id)
+	return nil
+}
+
+func updateMqttProfile(cCtx *cli.Context) error {
+	id, err := requireArg(cCtx, "ID")
+	if err != nil {
+		return err
+	}
+	c, err := newClient(cCtx)
+	if err != nil {
+		return err
+	}
+	if _, err := c.GetMqttProfile(cCtx.Context, id); err != nil {
+		return err
+	}
+	if _, err := c.UpdateMqttProfile(cCtx.Context, id, updateMqttProfileRequest(cCtx)); err != nil {
+		return err
+	}
+	fmt.Printf("Updated MQTT profile %s.\n", id)
+	return nil
+}
+
+func deleteMqttProfile(cCtx *cli.Context) error {
+	id, err := requireArg(cCtx, "ID")
+	if err != nil {
+		return err
+	}
+	c, err := newClient(cCtx)
+	if err != nil {
+		return err
+	}
+	if err := c.DeleteMqttProfile(cCtx.Context, id); err != nil {
+		return err
+	}
+	fmt.Printf("Deleted MQTT profile %s.\n", id)
+	return nil
+}
+
+var mqttProfileFlags = []cli.Flag{
+	&cli.StringSliceFlag{Name: "allow-publish", Usage: "Allow publishing to `TOPIC`. Repeat for more"},
+	&cli.StringSliceFlag{Name: "allow-subscribe", Usage: "Allow subscribing to `TOPIC`. Repeat for more"},
+}
+
+var mqttProfileCommand = &cli.Command{
+	Name:  "mqtt-profile",
+	Usage: "manage MQTT profiles",
+	Subcommands: []*cli.Command{
+		{Name: "list", Usage: "list MQTT profiles", Action: listMqttProfiles},
+		{Name: "get", Usage: "show an MQTT profile", ArgsUsage: "ID", Action: showMqttProfile},
+		{Name: "create", Usage: "create an MQTT profile", ArgsUsage: "ID", Flags: mqttProfileFlags, Action: createMqttProfile},
+		{Name: "update", Usage: "change an MQTT profile", ArgsUsage: "ID", Flags: mqttProfileFlags, Action: updateMqttProfile},
+		{Name: "delete", Usage: "delete an MQTT profile", ArgsUsage: "ID", Action: deleteMqttProfile},
+	},
+}
+
+var mqttClientHeader = []string{"ID", "PROFILE", "CONNECTED"}
+
+func mqttClientRows(clients ...api.ApiMqttClient) [][]string {
+	rows := make([][]string, 0, len(clients))
+	for _, c := range clients {
+		connected := "-"
+		if c.Connected != nil {
+			connected = c.Connected.RemoteAddr
+		}
+		rows = append(rows, []string{c.Id, c.ProfileId, connected})
+	}
+	return rows
+}
+
+func listMqttClients(cCtx *cli.Context) error {
+	c, err := newClient(cCtx)
+	if err != nil {
+		return err
+	}
+	res, err := c.ListMqttClients(cCtx.Context)
+	if err != nil {
+		return err
+	}
+	return printResult(cCtx, res.MqttClients, mqttClientHeader, mqttClientRows(res.MqttClients...))
+}
+
+func showMqttClient(cCtx *cli.Context) error {
+	id, err := requireArg(cCtx, "ID")
+	if err != nil {
+		return err
+	}
+	c, err := newClient(cCtx)
+	if err != nil {
+		return err
+	}
+	mqttClient, err := c.GetMqttClient(cCtx.Context, id)
+	if err != nil {
+		return err
+	}
+	return printResult(cCtx, mqttClient, mqttClientHeader, mqttClientRows(*mqttClient))
+}
+
+func updateMqttClientRequest(cCtx *cli.Context) (*api.ApiUpdateMqttClientRequest, error) {
+	req := &api.ApiUpdateMqttClientRequest{}
+	if cCtx.IsSet("profile") {
+		profile := cCtx.String("profile")
+		req.ProfileId = &profile
+	}
+	if cCtx.IsSet("password") {
+		password := cCtx.String("password")
+		req.Password = &password
+	}
+	if cCtx.IsSet("value") {
+		values, err := parseKeyValues(cCtx.StringSlice("value"))
+		if err != nil {
+			return nil, err
+		}
+		req.Values = &values
+	}
+	return req, nil
+}
+
+func createMqttClient(cCtx *cli.Context) error {
+	id, err := requireArg(cCtx, "ID")
+	if err != nil {
+		return err
+	}
+	c, err := newClient(cCtx)
+	if err != nil {
+		return err
+	}
+	if _, err := c.GetMqttClient(cCtx.Context, id); err == nil {
+		return fmt.Errorf("MQTT client %s already exists", id)
+	}
+	req, err := updateMqttClientRequest(cCtx)
+	if err != nil {
+		return err
+	}
+	if _, err := c.UpdateMqttClient(cCtx.Context, id, req); err != nil {
+		return err
+	}
+	fmt.Printf("Created MQTT client %s.\n", id)
+	return nil
+}
+
+func updateMqttClient(cCtx *cli.Context) error {
+	id, err := requireArg(cCtx, "ID")
+	if err != nil {
+		return err
+	}
+	c, err := newClient(cCtx)
+	if err != nil {
+		return err
+	}
+	if _, err := c.GetMqttClient(cCtx.Context, id); err != nil {
+		return err
+	}
+	req, err := updateMqttClientRequest(cCtx)
+	if err != nil {
+		return err
+	}
+	if _, err := c.UpdateMqttClient(cCtx.Context, id, req); err != nil {
+		return err
+	}
+	fmt.Printf("Updated MQTT client %s.\n", id)
+	return nil
+}
+
+func deleteMqttClient(cCtx *cli.Context) error {
+	id, err := requireArg(cCtx, "ID")
+	if err != nil {
+		return err
+	}
+	c, err := newClient(cCtx)
+	if err != nil {
+		return err
+	}
+	if err := c.DeleteMqttClient(cCtx.Context, id); err != nil {
+		return err
+	}
+	fmt.Printf("Deleted MQTT client %s.\n", id)
+	return nil
+}
+
+var mqttClientFlags = []cli.Flag{
+	&cli.StringFlag{Name: "profile", Usage: "The MQTT profile `ID` of the client"},
+	&cli.StringFlag{Name: "password", Usage: "The `PASSWORD` the client connects with"},
+	&cli.StringSliceFlag{Name: "value", Usage: "A `key=value` to expand in topics. Repeat for more"},
+}
+
+var mqttClientCommand = &cli.Command{
+	Name:  "mqtt-client",
+	Usage: "manage MQTT clients",
+	Subcommands: []*cli.Command{
+		{Name: "list", Usage: "list MQTT clients", Action: listMqttClients},
+		{Name: "get", Usage: "show an MQTT client", ArgsUsage: "ID", Action: showMqttClient},
+		{Name: "create", Usage: "create an MQTT client", ArgsUsage: "ID", Flags: mqttClientFlags, Action: createMqttClient},
+		{Name: "update", Usage: "change an MQTT client", ArgsUsage: "ID", Flags: mqttClientFlags, Action: updateMqttClient},
+		{Name: "delete", Usage: "delete an MQTT client", ArgsUsage: "ID", Action: deleteMqttClient},
+	},
+}
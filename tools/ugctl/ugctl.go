@@ -0,0 +1,213 @@
+package main
+
+import (
+	"boivie/ubergang/server/api/client"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFile is where `ugctl login` stores the server and the access token.
+type ConfigFile struct {
+	Server string `yaml:"server"`
+	Token  string `yaml:"token"`
+}
+
+func getConfigFilename(filename string) (string, error) {
+	if filename != "" {
+		return filename, nil
+	}
+	dirname, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dirname, "ubergang", "ugctl.yaml"), nil
+}
+
+func readConfig(filename string) (*ConfigFile, error) {
+	filename, err := getConfigFilename(filename)
+	if err != nil {
+		return nil, err
+	}
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			contents = []byte{}
+		} else {
+			return nil, err
+		}
+	}
+
+	var config ConfigFile
+	err = yaml.Unmarshal(contents, &config)
+	if err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func writeConfig(filename string, cfg *ConfigFile) error {
+	filename, err := getConfigFilename(filename)
+	if err != nil {
+		return err
+	}
+	contents, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, contents, 0600)
+}
+
+// serverUrl returns the base URL of the admin API on `server`, which is either
+// a host name or a URL.
+func serverUrl(server string) string {
+	if !strings.Contains(server, "://") {
+		server = "https://" + server
+	}
+	return strings.TrimSuffix(server, "/")
+}
+
+// newClient returns a client for the server and token given by the flags or,
+// if not set, the configuration file.
+func newClient(cCtx *cli.Context) (*client.Client, error) {
+	config, err := readConfig(cCtx.String("config"))
+	if err != nil {
+		return nil, err
+	}
+	server := cCtx.String("server")
+	if server == "" {
+		server = config.Server
+	}
+	token := cCtx.String("token")
+	if token == "" {
+		token = config.Token
+	}
+	if server == "" || token == "" {
+		return nil, errors.New("not signed in. Run `ugctl --server HOST login` first")
+	}
+	return client.New(serverUrl(server), token), nil
+}
+
+// printResult prints `v` in the format given by the --output flag. Tables
+// have the columns in `header`, and a row per entry in `rows`.
+func printResult(cCtx *cli.Context, v any, header []string, rows [][]string) error {
+	switch cCtx.String("output") {
+	case "json":
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	case "yaml":
+		// Round-trip through JSON, so that the keys are the same as in the
+		// API.
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		var generic any
+		if err := json.Unmarshal(data, &generic); err != nil {
+			return err
+		}
+		data, err = yaml.Marshal(generic)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(data))
+	case "table", "":
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, strings.Join(header, "\t"))
+		for _, row := range rows {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("unknown output format '%s'", cCtx.String("output"))
+	}
+	return nil
+}
+
+// requireArg returns the only positional argument, which is named `name`.
+func requireArg(cCtx *cli.Context, name string) (string, error) {
+	if cCtx.NArg() != 1 {
+		return "", fmt.Errorf("expected %s", name)
+	}
+	return cCtx.Args().First(), nil
+}
+
+// parseKeyValues parses "key=value" flags.
+func parseKeyValues(values []string) (map[string]string, error) {
+	ret := make(map[string]string)
+	for _, v := range values {
+		key, value, found := strings.Cut(v, "=")
+		if !found || key == "" {
+			return nil, fmt.Errorf("expected key=value, got '%s'", v)
+		}
+		ret[key] = value
+	}
+	return ret, nil
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
+
+func main() {
+	app := &cli.App{
+		Name:  "ugctl",
+		Usage: "manage an ubergang server using its admin API",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Load configuration from `FILE`",
+				EnvVars: []string{"UGCTL_CONFIG"},
+			},
+			&cli.StringFlag{
+				Name:    "server",
+				Aliases: []string{"s"},
+				Usage:   "The admin `HOST` or URL of the server",
+				EnvVars: []string{"UGCTL_SERVER"},
+			},
+			&cli.StringFlag{
+				Name:    "token",
+				Usage:   "An access `TOKEN` with the admin scope",
+				EnvVars: []string{"UGCTL_TOKEN"},
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Output `FORMAT`: table, json or yaml",
+				Value:   "table",
+			},
+		},
+		Commands: []*cli.Command{
+			loginCommand,
+			logoutCommand,
+			backendCommand,
+			userCommand,
+			sessionCommand,
+			credentialCommand,
+			mqttProfileCommand,
+			mqttClientCommand,
+		},
+	}
+
+	if err := app.Run(os.Args); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,383 @@
+package main
+
+import (
+	"boivie/ubergang/server/api"
+	"boivie/ubergang/server/api/client"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+var userHeader = []string{"ID", "EMAIL", "NAME", "ADMIN", "DISABLED", "GROUPS"}
+
+func userRows(users ...api.ApiUser) [][]string {
+	rows := make([][]string, 0, len(users))
+	for _, u := range users {
+		rows = append(rows, []string{u.ID, u.Email, u.DisplayName, yesNo(u.IsAdmin), yesNo(u.IsDisabled), strings.Join(u.Groups, ",")})
+	}
+	return rows
+}
+
+// getUser returns the user identified by either its id or its e-mail address.
+func getUser(cCtx *cli.Context, c *client.Client, idOrEmail string) (*api.ApiUser, error) {
+	if strings.Contains(idOrEmail, "@") {
+		res, err := c.ListUsers(cCtx.Context)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range res.Users {
+			if strings.EqualFold(u.Email, idOrEmail) {
+				idOrEmail = u.ID
+				break
+			}
+		}
+		if strings.Contains(idOrEmail, "@") {
+			return nil, fmt.Errorf("user %s not found", idOrEmail)
+		}
+	}
+
+	return c.GetUser(cCtx.Context, idOrEmail)
+}
+
+func listUsers(cCtx *cli.Context) error {
+	c, err := newClient(cCtx)
+	if err != nil {
+		return err
+	}
+	res, err := c.ListUsers(cCtx.Context)
+	if err != nil {
+		return err
+	}
+	return printResult(cCtx, res.Users, userHeader, userRows(res.Users...))
+}
+
+func showUser(cCtx *cli.Context) error {
+	idOrEmail, err := requireArg(cCtx, "USER")
+	if err != nil {
+		return err
+	}
+	c, err := newClient(cCtx)
+	if err != nil {
+		return err
+	}
+	user, err := getUser(cCtx, c, idOrEmail)
+	if err != nil {
+		return err
+	}
+	return printResult(cCtx, user, userHeader, userRows(*user))
+}
+
+func updateUserRequest(cCtx *cli.Context) *api.ApiUpdateUserRequest {
+	req := &api.ApiUpdateUserRequest{}
+	if cCtx.IsSet("email") {
+		email := cCtx.String("email")
+		req.Email = &email
+	}
+	if cCtx.IsSet("name") {
+		name := cCtx.String("name")
+		req.DisplayName = &name
+	}
+	if cCtx.IsSet("admin") {
+		admin := cCtx.Bool("admin")
+		req.Admin = &admin
+	}
+	if cCtx.IsSet("disabled") {
+		disabled := cCtx.Bool("disabled")
+		req.Disabled = &disabled
+	}
+	if cCtx.IsSet("allowed-host") {
+		hosts := cCtx.StringSlice("allowed-host")
+		req.AllowedHosts = &hosts
+	}
+	if cCtx.IsSet("group") {
+		groups := cCtx.StringSlice("group")
+		req.Groups = &groups
+	}
+	if cCtx.IsSet("totp-policy") {
+		policy := cCtx.String("totp-policy")
+		req.TotpPolicy = &policy
+	}
+	return req
+}
+
+func createUser(cCtx *cli.Context) error {
+	email, err := requireArg(cCtx, "EMAIL")
+	if err != nil {
+		return err
+	}
+	c, err := newClient(cCtx)
+	if err != nil {
+		return err
+	}
+	res, err := c.CreateUser(cCtx.Context, &api.ApiCreateUserRequest{
+		Email:          email,
+		SendInvitation: cCtx.Bool("send-invitation"),
+	})
+	if err != nil {
+		return err
+	}
+
+	if req := updateUserRequest(cCtx); *req != (api.ApiUpdateUserRequest{}) {
+		if _, err := c.UpdateUser(cCtx.Context, res.ID, req); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("Created user %s with id %s.\n", email, res.ID)
+	if res.InvitationSent {
+		fmt.Println("An invitation was sent by e-mail.")
+	}
+	return nil
+}
+
+func updateUser(cCtx *cli.Context) error {
+	idOrEmail, err := requireArg(cCtx, "USER")
+	if err != nil {
+		return err
+	}
+	c, err := newClient(cCtx)
+	if err != nil {
+		return err
+	}
+	user, err := getUser(cCtx, c, idOrEmail)
+	if err != nil {
+		return err
+	}
+	if _, err := c.UpdateUser(cCtx.Context, user.ID, updateUserRequest(cCtx)); err != nil {
+		return err
+	}
+	fmt.Printf("Updated user %s.\n", user.Email)
+	return nil
+}
+
+func deleteUser(cCtx *cli.Context) error {
+	idOrEmail, err := requireArg(cCtx, "USER")
+	if err != nil {
+		return err
+	}
+	c, err := newClient(cCtx)
+	if err != nil {
+		return err
+	}
+	user, err := getUser(cCtx, c, idOrEmail)
+	if err != nil {
+		return err
+	}
+	if err := c.DeleteUser(cCtx.Context, user.ID); err != nil {
+		return err
+	}
+	fmt.Printf("Deleted user %s.\n", user.Email)
+	return nil
+}
+
+var userUpdateFlags = []cli.Flag{
+	&cli.StringFlag{Name: "email", Usage: "Change the e-mail `ADDRESS`"},
+	&cli.StringFlag{Name: "name", Usage: "The display `NAME`"},
+	&cli.BoolFlag{Name: "admin", Usage: "Make the user an administrator, or not with --admin=false"},
+	&cli.BoolFlag{Name: "disabled", Usage: "Disable the user, or enable with --disabled=false"},
+	&cli.StringSliceFlag{Name: "allowed-host", Usage: "Only allow access to `HOST`. Repeat for more"},
+	&cli.StringSliceFlag{Name: "group", Usage: "Add the user to `GROUP`. Repeat for more"},
+	&cli.StringFlag{Name: "totp-policy", Usage: "One of disabled, fallback or required"},
+}
+
+var userCommand = &cli.Command{
+	Name:  "user",
+	Usage: "manage users",
+	Description: "Users are identified by either their id or their e-mail address. Lists given\n" +
+		"by repeated flags replace the existing ones.",
+	Subcommands: []*cli.Command{
+		{Name: "list", Usage: "list users", Action: listUsers},
+		{Name: "get", Usage: "show a user", ArgsUsage: "USER", Action: showUser},
+		{
+			Name:      "create",
+			Usage:     "create a user",
+			ArgsUsage: "EMAIL",
+			Flags: append([]cli.Flag{
+				&cli.BoolFlag{Name: "send-invitation", Usage: "E-mail a sign-in link to the user"},
+			}, userUpdateFlags[1:]...),
+			Action: createUser,
+		},
+		{Name: "update", Usage: "change a user", ArgsUsage: "USER", Flags: userUpdateFlags, Action: updateUser},
+		{Name: "delete", Usage: "delete a user", ArgsUsage: "USER", Action: deleteUser},
+	},
+}
+
+// userFromFlag returns the user given by --user, or the signed in user.
+func userFromFlag(cCtx *cli.Context, c *client.Client) (*api.ApiUser, error) {
+	idOrEmail := cCtx.String("user")
+	if idOrEmail == "" {
+		idOrEmail = "me"
+	}
+	return getUser(cCtx, c, idOrEmail)
+}
+
+var userFlag = &cli.StringFlag{
+	Name:  "user",
+	Usage: "The id or e-mail address of the `USER`. Defaults to yourself",
+}
+
+var sessionHeader = []string{"ID", "CREATED", "LAST ACCESSED", "REMOTE ADDRESS", "USER AGENT"}
+
+func sessionRows(sessions ...api.ApiSession) [][]string {
+	rows := make([][]string, 0, len(sessions))
+	for _, s := range sessions {
+		rows = append(rows, []string{s.ID, s.CreatedAt, s.AccessedAt, s.LastRemoteAddr, s.LastUserAgent})
+	}
+	return rows
+}
+
+func listSessions(cCtx *cli.Context) error {
+	c, err := newClient(cCtx)
+	if err != nil {
+		return err
+	}
+	user, err := userFromFlag(cCtx, c)
+	if err != nil {
+		return err
+	}
+	return printResult(cCtx, user.Sessions, sessionHeader, sessionRows(user.Sessions...))
+}
+
+func showSession(cCtx *cli.Context) error {
+	id, err := requireArg(cCtx, "ID")
+	if err != nil {
+		return err
+	}
+	c, err := newClient(cCtx)
+	if err != nil {
+		return err
+	}
+	user, err := userFromFlag(cCtx, c)
+	if err != nil {
+		return err
+	}
+	for _, s := range user.Sessions {
+		if s.ID == id {
+			return printResult(cCtx, s, sessionHeader, sessionRows(s))
+		}
+	}
+	return fmt.Errorf("session %s not found for %s", id, user.Email)
+}
+
+func deleteSession(cCtx *cli.Context) error {
+	id, err := requireArg(cCtx, "ID")
+	if err != nil {
+		return err
+	}
+	c, err := newClient(cCtx)
+	if err != nil {
+		return err
+	}
+	if err := c.DeleteSession(cCtx.Context, id); err != nil {
+		return err
+	}
+	fmt.Printf("Signed out session %s.\n", id)
+	return nil
+}
+
+var sessionCommand = &cli.Command{
+	Name:  "session",
+	Usage: "manage sessions",
+	Subcommands: []*cli.Command{
+		{Name: "list", Usage: "list the sessions of a user", Flags: []cli.Flag{userFlag}, Action: listSessions},
+		{Name: "get", Usage: "show a session", ArgsUsage: "ID", Flags: []cli.Flag{userFlag}, Action: showSession},
+		{Name: "delete", Usage: "sign out a session", ArgsUsage: "ID", Action: deleteSession},
+	},
+}
+
+var credentialHeader = []string{"ID", "NAME", "TYPE", "CREATED", "LAST USED"}
+
+func credentialRows(credentials ...api.ApiCredential) [][]string {
+	rows := make([][]string, 0, len(credentials))
+	for _, c := range credentials {
+		rows = append(rows, []string{c.ID, c.Name, c.Type, c.CreatedAt, c.LastUsedAt})
+	}
+	return rows
+}
+
+func listCredentials(cCtx *cli.Context) error {
+	c, err := newClient(cCtx)
+	if err != nil {
+		return err
+	}
+	user, err := userFromFlag(cCtx, c)
+	if err != nil {
+		return err
+	}
+	return printResult(cCtx, user.Credentials, credentialHeader, credentialRows(user.Credentials...))
+}
+
+func showCredential(cCtx *cli.Context) error {
+	id, err := requireArg(cCtx, "ID")
+	if err != nil {
+		return err
+	}
+	c, err := newClient(cCtx)
+	if err != nil {
+		return err
+	}
+	user, err := userFromFlag(cCtx, c)
+	if err != nil {
+		return err
+	}
+	for _, c := range user.Credentials {
+		if c.ID == id {
+			return printResult(cCtx, c, credentialHeader, credentialRows(c))
+		}
+	}
+	return fmt.Errorf("credential %s not found for %s", id, user.Email)
+}
+
+func updateCredential(cCtx *cli.Context) error {
+	id, err := requireArg(cCtx, "ID")
+	if err != nil {
+		return err
+	}
+	if !cCtx.IsSet("name") {
+		return errors.New("nothing to update")
+	}
+	c, err := newClient(cCtx)
+	if err != nil {
+		return err
+	}
+	name := cCtx.String("name")
+	if _, err := c.UpdateCredential(cCtx.Context, id, &api.ApiUpdateCredentialRequest{Name: &name}); err != nil {
+		return err
+	}
+	fmt.Printf("Updated credential %s.\n", id)
+	return nil
+}
+
+func deleteCredential(cCtx *cli.Context) error {
+	id, err := requireArg(cCtx, "ID")
+	if err != nil {
+		return err
+	}
+	c, err := newClient(cCtx)
+	if err != nil {
+		return err
+	}
+	if err := c.DeleteCredential(cCtx.Context, id); err != nil {
+		return err
+	}
+	fmt.Printf("Deleted credential %s.\n", id)
+	return nil
+}
+
+var credentialCommand = &cli.Command{
+	Name:  "credential",
+	Usage: "manage passkeys",
+	Subcommands: []*cli.Command{
+		{Name: "list", Usage: "list the passkeys of a user", Flags: []cli.Flag{userFlag}, Action: listCredentials},
+		{Name: "get", Usage: "show a passkey", ArgsUsage: "ID", Flags: []cli.Flag{userFlag}, Action: showCredential},
+		{
+			Name:      "update",
+			Usage:     "rename one of your own passkeys",
+			ArgsUsage: "ID",
+			Flags:     []cli.Flag{&cli.StringFlag{Name: "name", Usage: "The new `NAME`"}},
+			Action:    updateCredential,
+		},
+		{Name: "delete", Usage: "delete a passkey", ArgsUsage: "ID", Action: deleteCredential},
+	},
+}
@@ -1,14 +1,21 @@
 import { createContext, useContext } from "react";
 import {
   ApiBackend,
+  ApiConfirmDeviceRequest,
+  ApiConfirmDeviceResponse,
   ApiConfirmSigninPinRequest,
   ApiConfirmSigninPinResponse,
   ApiCreateUserRequest,
   ApiCreateUserResponse,
+  ApiDenyDeviceRequest,
+  ApiDenyDeviceResponse,
   ApiFinishEnrollRequest,
   ApiFinishEnrollResponse,
+  ApiFinishTotpEnrollRequest,
+  ApiFinishTotpEnrollResponse,
   ApiGetConfirmSshKeyResponse,
   ApiListBackendsResponse,
+  ApiListSigninProvidersResponse,
   ApiListMqttClientsResponse,
   ApiListMqttProfilesResponse,
   ApiListUsersResponse,
@@ -18,15 +25,23 @@ import {
   ApiPollSigninPinResponse,
   ApiPostConfirmSshKeyRequest,
   ApiPostConfirmSshKeyResponse,
+  ApiQueryDeviceRequest,
+  ApiQueryDeviceResponse,
   ApiQuerySigninPinRequest,
   ApiQuerySigninPinResponse,
   ApiRequestSigninPinRequest,
   ApiRequestSigninPinResponse,
+  ApiSendSigninLinkRequest,
+  ApiSendSigninLinkResponse,
   ApiSigninEmailResponse,
+  ApiSignInTotpRequest,
+  ApiSignInTotpResponse,
   ApiSignInWebauthnRequest,
   ApiSignInWebauthResponse,
+  ApiStartEnrollRequest,
   ApiStartEnrollResponse,
   ApiStartSigninResponse,
+  ApiStartTotpEnrollResponse,
   ApiUpdateBackendRequest,
   ApiUpdateBackendResponse,
   ApiUpdateCredentialRequest,
@@ -38,7 +53,17 @@ import {
   ApiUpdateUserRequest,
   ApiUpdateUserResponse,
   ApiUser,
+  ApiUserRecoverRequest,
   ApiUserRecoverResponse,
+  ApiUserImpersonateResponse,
+  ApiListInvitationsResponse,
+  ApiCreateInvitationRequest,
+  ApiCreateInvitationResponse,
+  ApiImportInvitationsResponse,
+  ApiRedeemInvitationRequest,
+  ApiRedeemInvitationResponse,
+  ApiImpersonationStopResponse,
+  ApiUserAccessResponse,
   ApiBootstrapConfigureRequest,
   ApiBootstrapConfigureResponse,
   ApiBootstrapStatusResponse,
@@ -53,12 +78,26 @@ export interface ApiService {
     req: ApiSignInWebauthnRequest,
   ): Promise<ApiSignInWebauthResponse>;
 
+  SignInTotp(req: ApiSignInTotpRequest): Promise<ApiSignInTotpResponse>;
+
+  SendSigninLink(
+    req: ApiSendSigninLinkRequest,
+  ): Promise<ApiSendSigninLinkResponse>;
+
+  ListSigninProviders(): Promise<ApiListSigninProvidersResponse>;
+
   GetUser(userId: string): Promise<ApiUser>;
 
-  StartEnroll(): Promise<ApiStartEnrollResponse>;
+  StartEnroll(req: ApiStartEnrollRequest): Promise<ApiStartEnrollResponse>;
 
   FinishEnroll(req: ApiFinishEnrollRequest): Promise<ApiFinishEnrollResponse>;
 
+  StartTotpEnroll(): Promise<ApiStartTotpEnrollResponse>;
+
+  FinishTotpEnroll(
+    req: ApiFinishTotpEnrollRequest,
+  ): Promise<ApiFinishTotpEnrollResponse>;
+
   RequestSigninPin(
     req: ApiRequestSigninPinRequest,
   ): Promise<ApiRequestSigninPinResponse>;
@@ -75,6 +114,14 @@ export interface ApiService {
     req: ApiConfirmSigninPinRequest,
   ): Promise<ApiConfirmSigninPinResponse>;
 
+  QueryDevice(req: ApiQueryDeviceRequest): Promise<ApiQueryDeviceResponse>;
+
+  ConfirmDevice(
+    req: ApiConfirmDeviceRequest,
+  ): Promise<ApiConfirmDeviceResponse>;
+
+  DenyDevice(req: ApiDenyDeviceRequest): Promise<ApiDenyDeviceResponse>;
+
   GetConfirmSshKey(shortKeyId: string): Promise<ApiGetConfirmSshKeyResponse>;
 
   PostConfirmSshKey(
@@ -113,7 +160,34 @@ export interface ApiService {
 
   DeleteUser(userId: string): Promise<void>;
 
-  RecoverUser(userId: string): Promise<ApiUserRecoverResponse>;
+  RecoverUser(
+    userId: string,
+    req?: ApiUserRecoverRequest,
+  ): Promise<ApiUserRecoverResponse>;
+
+  ListInvitations(): Promise<ApiListInvitationsResponse>;
+
+  CreateInvitation(
+    req: ApiCreateInvitationRequest,
+  ): Promise<ApiCreateInvitationResponse>;
+
+  ImportInvitations(
+    data: string,
+    format: "csv" | "yaml",
+    sendEmail: boolean,
+  ): Promise<ApiImportInvitationsResponse>;
+
+  RevokeInvitation(id: string): Promise<void>;
+
+  RedeemInvitation(
+    req: ApiRedeemInvitationRequest,
+  ): Promise<ApiRedeemInvitationResponse>;
+
+  ImpersonateUser(userId: string): Promise<ApiUserImpersonateResponse>;
+
+  StopImpersonation(): Promise<ApiImpersonationStopResponse>;
+
+  CheckUserAccess(userId: string, url: string): Promise<ApiUserAccessResponse>;
 
   ListMqttProfiles(): Promise<ApiListMqttProfilesResponse>;
 
@@ -191,6 +265,40 @@ export const realApiService: ApiService = {
     return res.json();
   },
 
+  async SignInTotp(req: ApiSignInTotpRequest): Promise<ApiSignInTotpResponse> {
+    const res = await fetch("/api/signin/totp", {
+      method: "post",
+      headers: {
+        Accept: "application/json",
+        "Content-Type": "application/json",
+      },
+      body: JSON.stringify(req),
+    });
+    return res.json();
+  },
+
+  async SendSigninLink(
+    req: ApiSendSigninLinkRequest,
+  ): Promise<ApiSendSigninLinkResponse> {
+    const res = await fetch("/api/signin/link", {
+      method: "post",
+      headers: {
+        Accept: "application/json",
+        "Content-Type": "application/json",
+      },
+      body: JSON.stringify(req),
+    });
+    return res.json();
+  },
+
+  async ListSigninProviders(): Promise<ApiListSigninProvidersResponse> {
+    const res = await fetch("/api/signin/upstream", {
+      method: "get",
+      headers: { Accept: "application/json" },
+    });
+    return res.json();
+  },
+
   async GetUser(userId: string): Promise<ApiUser> {
     const res = await fetch(`/api/user/${userId}`, {
       method: "get",
@@ -199,14 +307,16 @@ export const realApiService: ApiService = {
     return res.json();
   },
 
-  async StartEnroll(): Promise<ApiStartEnrollResponse> {
+  async StartEnroll(
+    req: ApiStartEnrollRequest,
+  ): Promise<ApiStartEnrollResponse> {
     const res = await fetch("/api/enroll/start", {
       method: "post",
       headers: {
         Accept: "application/json",
         "Content-Type": "application/json",
       },
-      body: JSON.stringify({}),
+      body: JSON.stringify(req),
     });
     return res.json();
   },
@@ -225,6 +335,32 @@ export const realApiService: ApiService = {
     return res.json();
   },
 
+  async StartTotpEnroll(): Promise<ApiStartTotpEnrollResponse> {
+    const res = await fetch("/api/totp/enroll/start", {
+      method: "post",
+      headers: {
+        Accept: "application/json",
+        "Content-Type": "application/json",
+      },
+      body: JSON.stringify({}),
+    });
+    return res.json();
+  },
+
+  async FinishTotpEnroll(
+    req: ApiFinishTotpEnrollRequest,
+  ): Promise<ApiFinishTotpEnrollResponse> {
+    const res = await fetch("/api/totp/enroll/finish", {
+      method: "post",
+      headers: {
+        Accept: "application/json",
+        "Content-Type": "application/json",
+      },
+      body: JSON.stringify(req),
+    });
+    return res.json();
+  },
+
   async RequestSigninPin(
     req: ApiRequestSigninPinRequest,
   ): Promise<ApiRequestSigninPinResponse> {
@@ -281,6 +417,46 @@ export const realApiService: ApiService = {
     return res.json();
   },
 
+  async QueryDevice(
+    req: ApiQueryDeviceRequest,
+  ): Promise<ApiQueryDeviceResponse> {
+    const res = await fetch("/api/device/query", {
+      method: "post",
+      headers: {
+        Accept: "application/json",
+        "Content-Type": "application/json",
+      },
+      body: JSON.stringify(req),
+    });
+    return res.json();
+  },
+
+  async ConfirmDevice(
+    req: ApiConfirmDeviceRequest,
+  ): Promise<ApiConfirmDeviceResponse> {
+    const res = await fetch("/api/device/confirm", {
+      method: "post",
+      headers: {
+        Accept: "application/json",
+        "Content-Type": "application/json",
+      },
+      body: JSON.stringify(req),
+    });
+    return res.json();
+  },
+
+  async DenyDevice(req: ApiDenyDeviceRequest): Promise<ApiDenyDeviceResponse> {
+    const res = await fetch("/api/device/deny", {
+      method: "post",
+      headers: {
+        Accept: "application/json",
+        "Content-Type": "application/json",
+      },
+      body: JSON.stringify(req),
+    });
+    return res.json();
+  },
+
   async GetConfirmSshKey(
     shortKeyId: string,
   ): Promise<ApiGetConfirmSshKeyResponse> {
@@ -433,10 +609,17 @@ export const realApiService: ApiService = {
     }
   },
 
-  async RecoverUser(userId: string): Promise<ApiUserRecoverResponse> {
+  async RecoverUser(
+    userId: string,
+    req: ApiUserRecoverRequest = {},
+  ): Promise<ApiUserRecoverResponse> {
     const res = await fetch(`/api/user/${userId}/recover`, {
       method: "post",
-      headers: { Accept: "application/json" },
+      headers: {
+        Accept: "application/json",
+        "Content-Type": "application/json",
+      },
+      body: JSON.stringify(req),
     });
     if (!res.ok) {
       throw new Error(`Failed to generate recovery URL: ${res.statusText}`);
@@ -444,6 +627,119 @@ export const realApiService: ApiService = {
     return res.json();
   },
 
+  async ListInvitations(): Promise<ApiListInvitationsResponse> {
+    const res = await fetch("/api/invitation", {
+      method: "get",
+      headers: { Accept: "application/json" },
+    });
+    if (!res.ok) {
+      throw new Error(`Failed to list invitations: ${res.statusText}`);
+    }
+    return res.json();
+  },
+
+  async CreateInvitation(
+    req: ApiCreateInvitationRequest,
+  ): Promise<ApiCreateInvitationResponse> {
+    const res = await fetch("/api/invitation", {
+      method: "post",
+      headers: {
+        Accept: "application/json",
+        "Content-Type": "application/json",
+      },
+      body: JSON.stringify(req),
+    });
+    if (!res.ok) {
+      throw new Error(`Failed to create invitation: ${res.statusText}`);
+    }
+    return res.json();
+  },
+
+  async ImportInvitations(
+    data: string,
+    format: "csv" | "yaml",
+    sendEmail: boolean,
+  ): Promise<ApiImportInvitationsResponse> {
+    const res = await fetch(`/api/invitation/import?sendEmail=${sendEmail}`, {
+      method: "post",
+      headers: {
+        Accept: "application/json",
+        "Content-Type": format === "csv" ? "text/csv" : "application/x-yaml",
+      },
+      body: data,
+    });
+    if (!res.ok) {
+      throw new Error(`Failed to import invitations: ${await res.text()}`);
+    }
+    return res.json();
+  },
+
+  async RevokeInvitation(id: string): Promise<void> {
+    const res = await fetch(`/api/invitation/${id}`, {
+      method: "delete",
+      headers: { Accept: "application/json" },
+    });
+    if (!res.ok) {
+      throw new Error(`Failed to revoke invitation: ${res.statusText}`);
+    }
+  },
+
+  async RedeemInvitation(
+    req: ApiRedeemInvitationRequest,
+  ): Promise<ApiRedeemInvitationResponse> {
+    const res = await fetch("/api/invitation/redeem", {
+      method: "post",
+      headers: {
+        Accept: "application/json",
+        "Content-Type": "application/json",
+      },
+      body: JSON.stringify(req),
+    });
+    if (!res.ok) {
+      throw new Error(`Failed to redeem invitation: ${res.statusText}`);
+    }
+    return res.json();
+  },
+
+  async ImpersonateUser(userId: string): Promise<ApiUserImpersonateResponse> {
+    const res = await fetch(`/api/user/${userId}/impersonate`, {
+      method: "post",
+      headers: { Accept: "application/json" },
+    });
+    if (!res.ok) {
+      throw new Error(`Failed to view as user: ${res.statusText}`);
+    }
+    return res.json();
+  },
+
+  async StopImpersonation(): Promise<ApiImpersonationStopResponse> {
+    const res = await fetch("/api/impersonation", {
+      method: "delete",
+      headers: { Accept: "application/json" },
+    });
+    if (!res.ok) {
+      throw new Error(`Failed to stop viewing as user: ${res.statusText}`);
+    }
+    return res.json();
+  },
+
+  async CheckUserAccess(
+    userId: string,
+    url: string,
+  ): Promise<ApiUserAccessResponse> {
+    const res = await fetch(
+      `/api/user/${userId}/access?url=${encodeURIComponent(url)}`,
+      {
+        method: "get",
+        headers: { Accept: "application/json" },
+      },
+    );
+    if (!res.ok) {
+      throw new Error(`Failed to check access: ${res.statusText}`);
+    }
+    return res.json();
+  },
+
   async ListMqttProfiles(): Promise<ApiListMqttProfilesResponse> {
     const res = await fetch("/api/mqtt-profile", {
       method: "get",
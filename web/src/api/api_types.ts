@@ -4,6 +4,8 @@ export interface ApiAssertionRequest {
   rpId: string;
   allowCredentials: ApiPublicKeyCredentialDescriptor[];
   userVerification: string;
+  fallbackRpId?: string;
+  hints?: string[];
 }
 
 export interface ApiAuthenticatorAttestationResponse {
@@ -11,6 +13,7 @@ export interface ApiAuthenticatorAttestationResponse {
   attestationObject: string;
   clientDataJson: string;
   transports: string[];
+  authenticatorAttachment?: string;
 }
 
 export interface ApiAuthenticatorAssertionResponse {
@@ -65,6 +68,7 @@ export interface ApiPublicKeyCredentialCreationOptions {
   attestation: string;
   excludeCredentials: ApiPublicKeyCredentialDescriptor[];
   authenticatorSelection?: ApiAuthenticatorSelection;
+  hints?: string[];
 }
 
 export interface ApiEnrollRequest {
@@ -79,6 +83,7 @@ export interface ApiFinishEnrollRequest {
 
 export interface ApiFinishEnrollError {
   invalidEnrollment: boolean;
+  authenticatorNotAllowed?: boolean;
 }
 
 export interface ApiFinishEnrollResponse {
@@ -86,15 +91,48 @@ export interface ApiFinishEnrollResponse {
   error?: ApiFinishEnrollError;
 }
 
-export type ApiStartEnrollRequest = Record<string, never>;
+export interface ApiStartEnrollRequest {
+  attachment?: string;
+}
 
-export type ApiEnrollStartError = Record<string, never>;
+export interface ApiEnrollStartError {
+  attachmentNotAllowed?: boolean;
+}
 
 export interface ApiStartEnrollResponse {
   error?: ApiEnrollStartError;
   enrollRequest?: ApiEnrollRequest;
 }
 
+export interface ApiStartTotpEnrollError {
+  notAllowed?: boolean;
+  alreadyEnrolled?: boolean;
+}
+
+export interface ApiStartTotpEnrollResponse {
+  error?: ApiStartTotpEnrollError;
+  token?: string;
+  secret?: string;
+  uri?: string;
+  qrCodeUrl?: string;
+}
+
+export interface ApiFinishTotpEnrollRequest {
+  token: string;
+  code: string;
+  name: string;
+}
+
+export interface ApiFinishTotpEnrollError {
+  invalidEnrollment?: boolean;
+  invalidCode?: boolean;
+}
+
+export interface ApiFinishTotpEnrollResponse {
+  error?: ApiFinishTotpEnrollError;
+  credential?: ApiCredential;
+}
+
 export interface ApiUpdateCredentialRequest {
   name?: string;
 }
@@ -114,6 +152,9 @@ export interface ApiBackend {
   updatedAt: string;
   accessLevel: string;
   jsScript: string;
+  sessionPolicy: ApiSessionPolicy;
+  maxAuthAgeSeconds: number;
+  allowBasicAuth: boolean;
 }
 
 export interface ApiUpdateBackendRequest {
@@ -121,6 +162,9 @@ export interface ApiUpdateBackendRequest {
   headers?: ApiBackendHeader[];
   accessLevel?: string;
   jsScript?: string;
+  sessionPolicy?: ApiSessionPolicy;
+  maxAuthAgeSeconds?: number;
+  allowBasicAuth?: boolean;
 }
 
 export type ApiUpdateBackendResponse = Record<string, never>;
@@ -179,6 +223,120 @@ export interface ApiListMqttClientsResponse {
   mqtt_clients: ApiMqttClient[];
 }
 
+export interface ApiServiceAccount {
+  id: string;
+  name: string;
+  description: string;
+  allowedHosts: string[];
+  hasClientSecret: boolean;
+  publicKey: string;
+  mqttProfileId: string;
+  mqttValues: Record<string, string>;
+  sshAuthorizedKeys: string[];
+  createdAt: string;
+  updatedAt: string;
+  lastUsedAt?: string;
+  disabled: boolean;
+}
+
+export interface ApiUpdateServiceAccountRequest {
+  name?: string;
+  description?: string;
+  allowedHosts?: string[];
+  publicKey?: string;
+  mqttProfileId?: string;
+  mqttValues?: Record<string, string>;
+  sshAuthorizedKeys?: string[];
+}
+
+export type ApiUpdateServiceAccountResponse = Record<string, never>;
+
+export interface ApiListServiceAccountsResponse {
+  serviceAccounts: ApiServiceAccount[];
+}
+
+export interface ApiCreateServiceAccountSecretResponse {
+  clientId: string;
+  clientSecret: string;
+}
+
+export interface ApiOidcClient {
+  id: string;
+  name: string;
+  redirectUris: string[];
+  isPublic: boolean;
+  hasClientSecret: boolean;
+  allowedGroups: string[];
+  createdAt: string;
+  updatedAt: string;
+}
+
+export interface ApiUpdateOidcClientRequest {
+  name?: string;
+  redirectUris?: string[];
+  isPublic?: boolean;
+  allowedGroups?: string[];
+}
+
+export type ApiUpdateOidcClientResponse = Record<string, never>;
+
+export interface ApiListOidcClientsResponse {
+  oidcClients: ApiOidcClient[];
+}
+
+export interface ApiCreateOidcClientSecretResponse {
+  clientId: string;
+  clientSecret: string;
+}
+
+export interface ApiUpstreamOidcProvider {
+  id: string;
+  name: string;
+  issuer: string;
+  clientId: string;
+  hasClientSecret: boolean;
+  linkByEmail: boolean;
+  createdAt: string;
+  updatedAt: string;
+}
+
+export interface ApiUpdateUpstreamOidcProviderRequest {
+  name?: string;
+  issuer?: string;
+  clientId?: string;
+  clientSecret?: string;
+  linkByEmail?: boolean;
+}
+
+export type ApiUpdateUpstreamOidcProviderResponse = Record<string, never>;
+
+export interface ApiListUpstreamOidcProvidersResponse {
+  providers: ApiUpstreamOidcProvider[];
+}
+
+export interface ApiSigninProvider {
+  id: string;
+  name: string;
+}
+
+export interface ApiListSigninProvidersResponse {
+  providers: ApiSigninProvider[];
+  signinLinks: boolean;
+}
+
+export interface ApiSendSigninLinkRequest {
+  email: string;
+}
+
+export interface ApiSendSigninLinkError {
+  internalError?: boolean;
+  notConfigured?: boolean;
+}
+
+export interface ApiSendSigninLinkResponse {
+  error?: ApiSendSigninLinkError;
+}
+
 export interface ApiListUsersResponse {
   users: ApiUser[];
 }
@@ -216,6 +374,47 @@ export interface ApiQuerySigninPinResponse {
   assertionRequest?: ApiAssertionRequest;
 }
 
+export interface ApiQueryDeviceRequest {
+  userCode: string;
+}
+
+export interface ApiQueryDeviceError {
+  invalidCode?: boolean;
+  notAllowed?: boolean;
+  invalidCredentials?: boolean;
+}
+
+export interface ApiQueryDeviceResponse {
+  error?: ApiQueryDeviceError;
+  userCode?: string;
+  clientName?: string;
+  scopes?: string[];
+  requestorUserAgent?: string;
+  requestorIp?: string;
+  token?: string;
+  assertionRequest?: ApiAssertionRequest;
+}
+
+export interface ApiConfirmDeviceRequest {
+  token: string;
+  credential: ApiAssertionCredential;
+}
+
+export interface ApiConfirmDeviceError {
+  invalidToken?: boolean;
+  invalidCredential?: boolean;
+}
+
+export interface ApiConfirmDeviceResponse {
+  error?: ApiConfirmDeviceError;
+}
+
+export interface ApiDenyDeviceRequest {
+  userCode: string;
+}
+
+export type ApiDenyDeviceResponse = Record<string, never>;
+
 export interface ApiSignInEmailRequest {
   email: string;
   redirect: string;
@@ -272,6 +471,7 @@ export interface ApiRequestSigninPinRequest {
 
 export interface ApiRequestSigninPinError {
   invalidEmail: boolean;
+  tooManyRequests: boolean;
 }
 
 export interface ApiRequestSigninPinResponse {
@@ -293,6 +493,7 @@ export interface ApiSignInWebauthnRequest {
 export interface ApiSignInWebauthnError {
   internalError?: boolean;
   invalidCredential?: boolean;
+  totpNotEnrolled?: boolean;
 }
 
 export interface ApiSignInWebauthnSuccess {
@@ -300,9 +501,34 @@ export interface ApiSignInWebauthnSuccess {
   redirect: string;
 }
 
+export interface ApiSignInTotpRequired {
+  token: string;
+}
+
 export interface ApiSignInWebauthResponse {
   error?: ApiSignInWebauthnError;
   success?: ApiSignInWebauthnSuccess;
+  totpRequired?: ApiSignInTotpRequired;
+}
+
+export interface ApiSignInTotpRequest {
+  token?: string;
+  email?: string;
+  code: string;
+  redirect: string;
+}
+
+export interface ApiSignInTotpError {
+  internalError?: boolean;
+  invalidToken?: boolean;
+  invalidCode?: boolean;
+  blocked?: boolean;
+}
+
+export interface ApiSignInTotpResponse {
+  error?: ApiSignInTotpError;
+  token?: string;
+  success?: ApiSignInWebauthnSuccess;
 }
 
 export interface ApiPostConfirmSshKeyRequest {
@@ -369,6 +595,8 @@ export interface ApiSession {
   remoteAddr: string;
   createdAt: string;
   accessedAt: string;
+  lastUserAgent: string;
+  lastRemoteAddr: string;
 }
 
 export interface ApiCredential {
@@ -381,6 +609,11 @@ export interface ApiCredential {
   lastUsedAt: string;
   used_by_session_ids: string[];
   aaguid: string;
+  cloneWarning: boolean;
+  policyViolations?: ("authenticator_not_allowed" | "cloned")[];
+  blocked?: boolean;
+  attachment: "platform" | "cross-platform" | "";
+  rpId: string;
 }
 
 export interface ApiUser {
@@ -389,18 +622,136 @@ export interface ApiUser {
   displayName: string;
   allowedHosts: string[];
   isAdmin: boolean;
+  isDisabled: boolean;
+  groups: string[];
+  totpPolicy: string;
   credentials: ApiCredential[];
   sessions: ApiSession[];
   currentSession?: ApiSession;
   sshKeys: ApiSSHKey[];
+  accessTokens: ApiAccessToken[];
+  appPasswords: ApiAppPassword[];
+  federatedIdentities: ApiFederatedIdentity[];
+  passkeyPolicyViolations?: "too_few_passkeys"[];
+  impersonation?: ApiImpersonation;
+}
+
+export interface ApiImpersonation {
+  impersonatorEmail: string;
+  expiresAt: string;
+}
+
+export interface ApiFederatedIdentity {
+  providerId: string;
+  subject: string;
+  email: string;
+  linkedAt: string;
+  lastUsedAt?: string;
+}
+
+export interface ApiAccessToken {
+  id: string;
+  name: string;
+  scopes: string[];
+  allowedHosts: string[];
+  createdAt: string;
+  expiresAt: string;
+  lastUsedAt?: string;
+}
+
+export interface ApiStartCreateAccessTokenRequest {
+  name: string;
+  scopes: string[];
+  allowedHosts: string[];
+  expiresInDays?: number;
+}
+
+export interface ApiStartCreateAccessTokenError {
+  invalidName?: boolean;
+  invalidScope?: boolean;
+  invalidHost?: boolean;
+  invalidExpiry?: boolean;
+}
+
+export interface ApiStartCreateAccessTokenAuthenticate {
+  token: string;
+  assertionRequest: ApiAssertionRequest;
+}
+
+export interface ApiStartCreateAccessTokenResponse {
+  error?: ApiStartCreateAccessTokenError;
+  authenticate?: ApiStartCreateAccessTokenAuthenticate;
+}
+
+export interface ApiFinishCreateAccessTokenRequest {
+  token: string;
+  credential: ApiAssertionCredential;
+}
+
+export interface ApiFinishCreateAccessTokenError {
+  failedAuthentication?: boolean;
+}
+
+export interface ApiFinishCreateAccessTokenResult {
+  accessToken: ApiAccessToken;
+  secret: string;
+}
+
+export interface ApiFinishCreateAccessTokenResponse {
+  error?: ApiFinishCreateAccessTokenError;
+  result?: ApiFinishCreateAccessTokenResult;
+}
+
+export interface ApiAppPassword {
+  id: string;
+  name: string;
+  backendFqdn: string;
+  createdAt: string;
+  lastUsedAt?: string;
+  lastRemoteAddr?: string;
+}
+
+export interface ApiCreateAppPasswordRequest {
+  name: string;
+  backendFqdn: string;
+}
+
+export interface ApiCreateAppPasswordError {
+  invalidName?: boolean;
+  invalidBackend?: boolean;
+}
+
+export interface ApiCreateAppPasswordResult {
+  appPassword: ApiAppPassword;
+  username: string;
+  password: string;
+}
+
+export interface ApiCreateAppPasswordResponse {
+  error?: ApiCreateAppPasswordError;
+  result?: ApiCreateAppPasswordResult;
+}
+
+export interface ApiSessionActivity {
+  sessionId: string;
+  firstAccessedAt: string;
+  lastAccessedAt: string;
+  remoteAddr: string;
+  userAgent: string;
+}
+
+export interface ApiUserActivityResponse {
+  activity: ApiSessionActivity[];
 }
 
 export interface ApiCreateUserRequest {
   email: string;
+  sendInvitation?: boolean;
 }
 
 export interface ApiCreateUserResponse {
   id: string;
+  invitationSent: boolean;
 }
 
 export interface ApiUpdateUserRequest {
@@ -408,12 +759,230 @@ export interface ApiUpdateUserRequest {
   displayName?: string;
   admin?: boolean;
   allowedHosts?: string[];
+  groups?: string[];
+  disabled?: boolean;
+  totpPolicy?: string;
 }
 
 export type ApiUpdateUserResponse = Record<string, never>;
 
+export interface ApiUserRecoverRequest {
+  sendEmail?: boolean;
+}
+
 export interface ApiUserRecoverResponse {
   recoveryUrl: string;
+  emailSent: boolean;
+}
+
+export interface ApiInvitation {
+  id: string;
+  email: string;
+  displayName: string;
+  isAdmin: boolean;
+  allowedHosts: string[];
+  groups: string[];
+  createdAt: string;
+  expiresAt: string;
+  createdBy: string;
+  state: "pending" | "expired" | "revoked" | "redeemed";
+  userId?: string;
+}
+
+export interface ApiListInvitationsResponse {
+  invitations: ApiInvitation[];
+}
+
+export interface ApiCreateInvitationRequest {
+  email: string;
+  displayName?: string;
+  admin?: boolean;
+  allowedHosts?: string[];
+  groups?: string[];
+  lifetimeSeconds?: number;
+  sendEmail?: boolean;
+}
+
+export interface ApiCreateInvitationResponse {
+  invitation: ApiInvitation;
+  url: string;
+  emailSent: boolean;
+}
+
+export interface ApiImportedInvitation {
+  email: string;
+  url?: string;
+  error?: string;
+  emailSent: boolean;
+}
+
+export interface ApiImportInvitationsResponse {
+  invitations: ApiImportedInvitation[];
+}
+
+export interface ApiRedeemInvitationRequest {
+  token: string;
+}
+
+export interface ApiRedeemInvitationError {
+  invalidToken?: boolean;
+  expired?: boolean;
+  revoked?: boolean;
+  redeemed?: boolean;
+  userExists?: boolean;
+}
+
+export interface ApiRedeemInvitationSuccess {
+  cookie: string;
+  redirect: string;
+}
+
+export interface ApiRedeemInvitationResponse {
+  error?: ApiRedeemInvitationError;
+  success?: ApiRedeemInvitationSuccess;
+}
+
+export interface ApiUserImpersonateResponse {
+  cookie: string;
+  expiresAt: string;
+}
+
+export interface ApiImpersonationStopResponse {
+  cookie: string;
+}
+
+export interface ApiUserAccessResponse {
+  url: string;
+  host: string;
+  allowed: boolean;
+  reason: string;
+  notes: string[];
+}
+
+export interface ApiSessionPolicy {
+  absoluteLifetimeSeconds: number;
+  idleTimeoutSeconds: number;
+}
+
+export interface ApiSmtpSettings {
+  host: string;
+  port: number;
+  username: string;
+  password?: string;
+  passwordSet: boolean;
+  from: string;
+  implicitTls: boolean;
+}
+
+export interface ApiNotificationSettings {
+  emailUser: boolean;
+  emailAdmins: boolean;
+  webhookUrl: string;
+  webhookSecret?: string;
+  webhookSecretSet: boolean;
+  mqttTopic: string;
+}
+
+export interface ApiBanPolicy {
+  disabled: boolean;
+  maxFailures: number;
+  failureWindowSeconds: number;
+  banDurationSeconds: number;
+  maxBanDurationSeconds: number;
+  signinRequestsPerMinute: number;
+  tokenRequestsPerMinute: number;
+  trustedProxies: string[];
+}
+
+export interface ApiAuthenticator {
+  aaguid: string;
+  name?: string;
+}
+
+export interface ApiWebauthnSettings {
+  attachment: "" | "platform" | "cross-platform" | "any";
+  userVerification: "" | "required" | "preferred" | "discouraged";
+  residentKey: "" | "required" | "preferred" | "discouraged";
+  useSiteRpId: boolean;
+  relatedOrigins: boolean;
+}
+
+export interface ApiPasskeyPolicy {
+  allowedAuthenticators: ApiAuthenticator[];
+  deniedAuthenticators: ApiAuthenticator[];
+  cloneAction: "flag" | "block";
+  minPasskeys: number;
+}
+
+export interface ApiSettings {
+  sessionPolicy: ApiSessionPolicy;
+  smtp: ApiSmtpSettings;
+  notifications: ApiNotificationSettings;
+  banPolicy: ApiBanPolicy;
+  passkeyPolicy: ApiPasskeyPolicy;
+  webauthn: ApiWebauthnSettings;
+}
+
+export interface ApiUpdateSettingsRequest {
+  sessionPolicy?: ApiSessionPolicy;
+  smtp?: ApiSmtpSettings;
+  notifications?: ApiNotificationSettings;
+  banPolicy?: ApiBanPolicy;
+  passkeyPolicy?: ApiPasskeyPolicy;
+  webauthn?: ApiWebauthnSettings;
+}
+
+export type ApiUpdateSettingsResponse = Record<string, never>;
+
+export interface ApiAuditChange {
+  field: string;
+  before?: string;
+  after?: string;
+}
+
+export interface ApiAuditRecord {
+  id: string;
+  timestamp: string;
+  actorId: string;
+  actorName: string;
+  sessionId?: string;
+  ip: string;
+  action: string;
+  targetType: string;
+  targetId: string;
+  changes: ApiAuditChange[];
+}
+
+export interface ApiListAuditRecordsResponse {
+  records: ApiAuditRecord[];
+}
+
+export interface ApiSecurityEvent {
+  id: string;
+  timestamp: string;
+  source: "http" | "ssh" | "mqtt";
+  ip: string;
+  principal?: string;
+  reason: string;
+}
+
+export interface ApiListSecurityEventsResponse {
+  events: ApiSecurityEvent[];
+}
+
+export interface ApiBan {
+  ip: string;
+  bannedUntil: string;
+  banCount: number;
+  source: "http" | "ssh" | "mqtt";
+}
+
+export interface ApiListBansResponse {
+  bans: ApiBan[];
+}
+
+export interface ApiListAuthenticatorsResponse {
+  authenticators: ApiAuthenticator[];
 }
 
 export interface ApiTestingSetupResponse {
@@ -0,0 +1,46 @@
+import { useApiService } from "../api/api_client";
+import { ApiImpersonation } from "../api/api_types";
+
+export interface ImpersonationBannerProps {
+  email: string;
+  impersonation: ApiImpersonation;
+}
+
+export const ImpersonationBanner = ({
+  email,
+  impersonation,
+}: ImpersonationBannerProps) => {
+  const api = useApiService();
+
+  const stop = async () => {
+    try {
+      const res = await api.StopImpersonation();
+      if (res.cookie) {
+        document.cookie = res.cookie;
+        window.location.href = "/users";
+        return;
+      }
+    } catch (error) {
+      console.error("Failed to stop viewing as user:", error);
+    }
+    window.location.href = "/signin";
+  };
+
+  return (
+    <div className="flex items-center justify-between gap-4 mb-4 px-4 py-3 rounded-md bg-amber-100 text-amber-900">
+      <p className="text-sm">
+        {impersonation.impersonatorEmail} is viewing the site as{" "}
+        <strong>{email}</strong> until{" "}
+        {new Date(impersonation.expiresAt).toLocaleTimeString()}. Changes can't
+        be made.
+      </p>
+      <button
+        type="button"
+        onClick={stop}
+        className="inline-flex items-center justify-center h-8 px-4 text-sm font-medium rounded-full bg-amber-600 text-white hover:bg-amber-700"
+      >
+        Stop
+      </button>
+    </div>
+  );
+};
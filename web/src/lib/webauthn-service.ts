@@ -28,6 +28,9 @@ export interface WebauthnService {
   startAttestation(request: WebauthnAttestationRequest): void;
 }
 
+// The relying party ID of the passkey that was last used in this browser.
+const lastRpIdKey = "ug-last-passkey-rp-id";
+
 interface WebauthnServiceWithController extends WebauthnService {
   controller?: AbortController;
 }
@@ -37,9 +40,15 @@ export const realWebauthnService: WebauthnServiceWithController = {
 
   startConditionalAssertion(request: WebauthnAssertionRequest) {
     const req = request.request;
+    // Browsers only suggest the passkeys of one relying party ID, so use the
+    // fallback if that's what the user signed in with last time.
+    const rpId =
+      req.fallbackRpId && localStorage.getItem(lastRpIdKey) === req.fallbackRpId
+        ? req.fallbackRpId
+        : req.rpId;
     const opts: PublicKeyCredentialRequestOptions = {
       challenge: base64Decode(req.challenge),
-      rpId: req.rpId,
+      rpId,
       timeout: req.timeout,
       userVerification: req.userVerification as UserVerificationRequirement,
       allowCredentials: req.allowCredentials.map((c) => ({
@@ -79,6 +88,7 @@ export const realWebauthnService: WebauthnServiceWithController = {
             type: "public-key",
           },
         };
+        localStorage.setItem(lastRpIdKey, opts.rpId!);
         request.onCredential(assertionCredential);
       })
       .catch((error: Error) => {
@@ -90,7 +100,7 @@ export const realWebauthnService: WebauthnServiceWithController = {
       this.controller.abort();
     }
     const req = request.request;
-    const opts: PublicKeyCredentialRequestOptions = {
+    const opts: PublicKeyCredentialRequestOptions & { hints?: string[] } = {
       challenge: base64Decode(req.challenge),
       rpId: req.rpId,
       timeout: req.timeout,
@@ -100,6 +110,7 @@ export const realWebauthnService: WebauthnServiceWithController = {
         id: base64Decode(c.id),
         transports: c.transports as AuthenticatorTransport[],
       })),
+      hints: req.hints,
     };
     const navigatorObj = window.navigator;
     if (!navigatorObj || !navigatorObj.credentials) {
@@ -127,9 +138,22 @@ export const realWebauthnService: WebauthnServiceWithController = {
             type: "public-key",
           },
         };
+        localStorage.setItem(lastRpIdKey, opts.rpId!);
         request.onCredential(assertionCredential);
       })
       .catch((error: Error) => {
+        if (error.name === "NotAllowedError" && req.fallbackRpId) {
+          // The user may only have passkeys for the other relying party ID.
+          this.startAssertion({
+            ...request,
+            request: {
+              ...req,
+              rpId: req.fallbackRpId,
+              fallbackRpId: undefined,
+            },
+          });
+          return;
+        }
         request.onAssertionError(error);
       });
   },
@@ -137,7 +161,7 @@ export const realWebauthnService: WebauthnServiceWithController = {
   startAttestation(request: WebauthnAttestationRequest): void {
     const options = request.enroll.options;
     const user = options.user;
-    const opts: PublicKeyCredentialCreationOptions = {
+    const opts: PublicKeyCredentialCreationOptions & { hints?: string[] } = {
       rp: options.rp,
       user: Object.assign({}, user, { id: base64Decode(user.id) }),
       challenge: base64Decode(options.challenge),
@@ -164,6 +188,7 @@ export const realWebauthnService: WebauthnServiceWithController = {
               .userVerification as UserVerificationRequirement,
           }
         : undefined,
+      hints: options.hints,
     };
     const navigatorObj = window.navigator;
     if (!navigatorObj || !navigatorObj.credentials) {
@@ -185,6 +210,7 @@ export const realWebauthnService: WebauthnServiceWithController = {
           ),
           clientDataJson: base64Encode(attestation.response.clientDataJSON),
           transports: attestation.response.getTransports(),
+          authenticatorAttachment: attestation.authenticatorAttachment || "",
         };
         request.onCredential(attestationResponse);
       })
@@ -43,7 +43,9 @@ import ConfirmComponent from "./routes/confirm";
 import ConfirmPinComponent, {
   ConfirmPinLoader,
 } from "./routes/confirm-pin.tsx";
+import DeviceComponent from "./routes/device.tsx";
 import EnrollComponent from "./routes/enroll";
+import TotpEnrollComponent from "./routes/totp-enroll";
 import IndexComponent, { IndexAction, IndexLoader } from "./routes/index";
 import SigninComponent from "./routes/signin";
 import SigninTokenComponent, { SigninTokenLoader } from "./routes/signin-token";
@@ -64,6 +66,11 @@ import SessionEditComponent, {
   SessionEditLoader,
 } from "./routes/sessions-edit.tsx";
 import SetupComponent from "./routes/setup.tsx";
+import InvitationsComponent, {
+  InvitationsAction,
+  InvitationsLoader,
+} from "./routes/invitations.tsx";
+import InviteComponent, { InviteLoader } from "./routes/invite.tsx";
 
 const api = realApiService;
 const router = createBrowserRouter([
@@ -165,6 +172,13 @@ const router = createBrowserRouter([
         element: <NewUserComponent />,
         handle: { tabid: "users" },
       },
+      {
+        path: "/users/invitations",
+        loader: () => InvitationsLoader(api),
+        action: (args) => InvitationsAction(api, args),
+        element: <InvitationsComponent />,
+        handle: { tabid: "users" },
+      },
       {
         path: "/users/edit/:id",
         element: <EditUserComponent />,
@@ -176,6 +190,10 @@ const router = createBrowserRouter([
         path: "/enroll/",
         element: <EnrollComponent />,
       },
+      {
+        path: "/totp/enroll",
+        element: <TotpEnrollComponent />,
+      },
     ],
   },
   {
@@ -192,6 +210,11 @@ const router = createBrowserRouter([
     loader: ({ params }) => SigninTokenLoader(api, params.token!),
     element: <SigninTokenComponent />,
   },
+  {
+    path: "/invite/:token",
+    loader: ({ params }) => InviteLoader(api, params.token!),
+    element: <InviteComponent />,
+  },
   {
     path: "/confirm/",
     element: <ConfirmComponent />,
@@ -201,6 +224,10 @@ const router = createBrowserRouter([
     loader: ({ params }) => ConfirmPinLoader(api, params.pin!),
     element: <ConfirmPinComponent />,
   },
+  {
+    path: "/device",
+    element: <DeviceComponent />,
+  },
 ]);
 
 ReactDOM.createRoot(document.getElementById("root")!).render(
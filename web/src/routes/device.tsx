@@ -0,0 +1,370 @@
+import {
+  IconCheck,
+  IconDeviceDesktop,
+  IconDeviceMobile,
+  IconDeviceTv,
+  IconExclamationCircle,
+  IconGlobe,
+  IconKey,
+  IconLoader2,
+  IconX,
+} from "@tabler/icons-react";
+import { useEffect, useState } from "react";
+import { useSearchParams } from "react-router";
+import { useApiService } from "../api/api_client";
+import {
+  ApiAssertionCredential,
+  ApiQueryDeviceResponse,
+} from "../api/api_types";
+import { useWebauthnService } from "../lib/webauthn-hook";
+
+type State =
+  | { type: "input" }
+  | { type: "loading" }
+  | { type: "confirm"; req: ApiQueryDeviceResponse }
+  | { type: "authenticating" }
+  | { type: "approved" }
+  | { type: "denied" }
+  | { type: "error"; message: string; signin?: boolean };
+
+export default function Device() {
+  const api = useApiService();
+  const webauthn = useWebauthnService();
+  const [searchParams] = useSearchParams();
+  const [userCode, setUserCode] = useState(
+    searchParams.get("user_code") ?? "",
+  );
+  const [state, setState] = useState<State>({ type: "input" });
+
+  const query = async (code: string) => {
+    setState({ type: "loading" });
+    try {
+      const res = await api.QueryDevice({ userCode: code });
+      if (res.error?.invalidCode) {
+        setState({
+          type: "error",
+          message: "The code is invalid or has expired. Please try again.",
+        });
+      } else if (res.error?.notAllowed) {
+        setState({
+          type: "error",
+          message: "You are not allowed to authorize this device.",
+        });
+      } else if (res.error) {
+        setState({
+          type: "error",
+          message: "Unable to verify the code. Please try again.",
+        });
+      } else {
+        setState({ type: "confirm", req: res });
+      }
+    } catch (_error) {
+      // The request fails if the user isn't signed in.
+      setState({
+        type: "error",
+        message: "Unable to verify the code. Please sign in and try again.",
+        signin: true,
+      });
+    }
+  };
+
+  useEffect(() => {
+    const code = searchParams.get("user_code");
+    if (code) {
+      query(code);
+    }
+    // eslint-disable-next-line react-hooks/exhaustive-deps
+  }, []);
+
+  const approve = (req: ApiQueryDeviceResponse) => {
+    setState({ type: "authenticating" });
+
+    webauthn.startAssertion({
+      request: req.assertionRequest!,
+      onCredential: async function (
+        credential: ApiAssertionCredential,
+      ): Promise<void> {
+        try {
+          const response = await api.ConfirmDevice({
+            token: req.token!,
+            credential: credential,
+          });
+          if (!response.error) {
+            setState({ type: "approved" });
+          } else {
+            setState({
+              type: "error",
+              message: "Authentication failed. Please try again.",
+            });
+          }
+        } catch (_error) {
+          setState({
+            type: "error",
+            message: "Failed to authorize the device. Please try again.",
+          });
+        }
+      },
+      onAssertionError: function (error: Error): void {
+        console.error("WebAuthn assertion error:", error);
+        setState({
+          type: "error",
+          message: "Authentication was cancelled or failed. Please try again.",
+        });
+      },
+      onNotSupported: function (): void {
+        setState({
+          type: "error",
+          message: "WebAuthn is not supported on this device or browser.",
+        });
+      },
+    });
+  };
+
+  const deny = async (req: ApiQueryDeviceResponse) => {
+    try {
+      await api.DenyDevice({ userCode: req.userCode! });
+      setState({ type: "denied" });
+    } catch (_error) {
+      setState({
+        type: "error",
+        message: "Failed to deny the request. Please try again.",
+      });
+    }
+  };
+
+  const renderContent = () => {
+    switch (state.type) {
+      case "input":
+        return (
+          <form
+            className="space-y-6"
+            onSubmit={(e) => {
+              e.preventDefault();
+              query(userCode);
+            }}
+          >
+            <div className="text-center">
+              <IconDeviceTv
+                className="mx-auto text-emerald-500 mb-4"
+                size={48}
+              />
+              <p className="text-slate-600">
+                Enter the code that is shown on your device.
+              </p>
+            </div>
+            <input
+              type="text"
+              value={userCode}
+              onChange={(e) => setUserCode(e.target.value.toUpperCase())}
+              placeholder="XXXX-XXXX"
+              autoFocus
+              autoComplete="off"
+              className="block w-full px-3 py-3 text-center text-2xl tracking-widest font-mono uppercase border border-slate-300 rounded-md shadow-xs focus:outline-hidden focus:ring-emerald-500 focus:border-emerald-500"
+            />
+            <button
+              type="submit"
+              disabled={userCode.trim() === ""}
+              className="w-full px-4 py-3 text-sm font-medium text-white border border-transparent rounded-md shadow-xs bg-emerald-600 hover:bg-emerald-700 disabled:opacity-50 focus:outline-hidden focus:ring-2 focus:ring-offset-2 focus:ring-emerald-500"
+            >
+              Continue
+            </button>
+          </form>
+        );
+
+      case "loading":
+        return (
+          <div className="flex flex-col items-center justify-center space-y-4 py-8">
+            <IconLoader2 className="animate-spin text-emerald-500" size={48} />
+            <p className="text-lg text-slate-700">Verifying code...</p>
+          </div>
+        );
+
+      case "confirm":
+        return (
+          <div className="space-y-6">
+            <div className="text-center">
+              <p className="text-slate-600">
+                <strong>{state.req.clientName}</strong> is requesting access
+                to your account.
+              </p>
+            </div>
+
+            <div className="bg-slate-50 rounded-lg p-4 space-y-3">
+              <h3 className="text-sm font-medium text-slate-800 mb-3">
+                Request Details
+              </h3>
+
+              <div className="flex items-start space-x-3">
+                <IconKey className="text-slate-500 mt-0.5 shrink-0" size={16} />
+                <div>
+                  <dt className="text-xs font-medium text-slate-600 uppercase tracking-wide">
+                    Code
+                  </dt>
+                  <dd className="text-sm text-slate-800 font-mono">
+                    {state.req.userCode}
+                  </dd>
+                </div>
+              </div>
+
+              <div className="flex items-start space-x-3">
+                <IconCheck
+                  className="text-slate-500 mt-0.5 shrink-0"
+                  size={16}
+                />
+                <div>
+                  <dt className="text-xs font-medium text-slate-600 uppercase tracking-wide">
+                    Scopes
+                  </dt>
+                  <dd className="text-sm text-slate-800 font-mono">
+                    {state.req.scopes?.join(" ")}
+                  </dd>
+                </div>
+              </div>
+
+              <div className="flex items-start space-x-3">
+                <IconGlobe
+                  className="text-slate-500 mt-0.5 shrink-0"
+                  size={16}
+                />
+                <div>
+                  <dt className="text-xs font-medium text-slate-600 uppercase tracking-wide">
+                    IP Address
+                  </dt>
+                  <dd className="text-sm text-slate-800 font-mono">
+                    {state.req.requestorIp}
+                  </dd>
+                </div>
+              </div>
+
+              <div className="flex items-start space-x-3">
+                <IconDeviceMobile
+                  className="text-slate-500 mt-0.5 shrink-0"
+                  size={16}
+                />
+                <div>
+                  <dt className="text-xs font-medium text-slate-600 uppercase tracking-wide">
+                    Device
+                  </dt>
+                  <dd className="text-sm text-slate-800 break-all">
+                    {state.req.requestorUserAgent}
+                  </dd>
+                </div>
+              </div>
+            </div>
+
+            <div className="bg-amber-50 border border-amber-200 rounded-lg p-4">
+              <p className="text-sm text-amber-800">
+                <strong>Security Check:</strong> Only approve this request if
+                the code matches the one shown on your device, and you started
+                the request yourself.
+              </p>
+            </div>
+
+            <div className="flex space-x-3">
+              <button
+                type="button"
+                onClick={() => deny(state.req)}
+                className="flex-1 px-4 py-3 text-sm font-medium text-slate-700 bg-white border border-slate-300 rounded-md shadow-xs hover:bg-slate-50 focus:outline-hidden focus:ring-2 focus:ring-offset-2 focus:ring-emerald-500"
+              >
+                <div className="flex items-center justify-center space-x-2">
+                  <IconX size={18} />
+                  <span>Deny</span>
+                </div>
+              </button>
+              <button
+                type="button"
+                onClick={() => approve(state.req)}
+                className="flex-1 px-4 py-3 text-sm font-medium text-white border border-transparent rounded-md shadow-xs bg-emerald-600 hover:bg-emerald-700 focus:outline-hidden focus:ring-2 focus:ring-offset-2 focus:ring-emerald-500"
+              >
+                <div className="flex items-center justify-center space-x-2">
+                  <IconDeviceDesktop size={18} />
+                  <span>Approve with Passkey</span>
+                </div>
+              </button>
+            </div>
+          </div>
+        );
+
+      case "authenticating":
+        return (
+          <div className="flex flex-col items-center justify-center space-y-4 py-8">
+            <IconLoader2 className="animate-spin text-emerald-500" size={48} />
+            <p className="text-lg text-slate-700">Authenticating...</p>
+            <p className="text-sm text-slate-500 text-center">
+              Please use your passkey, biometric sensor, or security key to
+              confirm.
+            </p>
+          </div>
+        );
+
+      case "approved":
+        return (
+          <div className="flex flex-col items-center justify-center space-y-4 py-8">
+            <IconCheck className="text-emerald-500" size={48} />
+            <p className="text-lg text-emerald-700 font-medium">
+              Device authorized!
+            </p>
+            <p className="text-sm text-slate-600">
+              Please continue on your device.
+            </p>
+          </div>
+        );
+
+      case "denied":
+        return (
+          <div className="flex flex-col items-center justify-center space-y-4 py-8">
+            <IconX className="text-slate-500" size={48} />
+            <p className="text-lg text-slate-700 font-medium">
+              Request denied
+            </p>
+            <p className="text-sm text-slate-600">
+              The device has not been given access to your account.
+            </p>
+          </div>
+        );
+
+      case "error":
+        return (
+          <div className="space-y-6">
+            <div className="flex flex-col items-center space-y-4 py-4">
+              <IconExclamationCircle className="text-red-500" size={48} />
+              <p className="text-lg text-red-700 text-center">
+                {state.message}
+              </p>
+            </div>
+            {state.signin ? (
+              <a
+                href={`/signin?rd=${encodeURIComponent("/device?user_code=" + encodeURIComponent(userCode))}`}
+                className="block w-full px-4 py-2 text-center text-sm font-medium text-white border border-transparent rounded-md shadow-xs bg-emerald-600 hover:bg-emerald-700 focus:outline-hidden focus:ring-2 focus:ring-offset-2 focus:ring-emerald-500"
+              >
+                Sign In
+              </a>
+            ) : (
+              <button
+                type="button"
+                onClick={() => setState({ type: "input" })}
+                className="w-full px-4 py-2 text-sm font-medium text-white border border-transparent rounded-md shadow-xs bg-red-600 hover:bg-red-700 focus:outline-hidden focus:ring-2 focus:ring-offset-2 focus:ring-red-500"
+              >
+                Try Again
+              </button>
+            )}
+          </div>
+        );
+    }
+  };
+
+  return (
+    <section className="bg-gray-50 min-h-screen flex items-center justify-center py-12 px-4 sm:px-6 lg:px-8">
+      <div className="w-full max-w-md bg-white rounded-lg shadow-lg md:mt-0 xl:p-0">
+        <div className="p-6 space-y-6 sm:p-8">
+          <div className="text-center">
+            <h1 className="text-2xl font-bold leading-tight tracking-tight text-slate-800 md:text-3xl">
+              Authorize Device
+            </h1>
+          </div>
+          {renderContent()}
+        </div>
+      </div>
+    </section>
+  );
+}
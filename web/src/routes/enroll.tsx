@@ -11,6 +11,7 @@ import {
   IconExclamationCircle,
   IconLoader2,
   IconDeviceDesktop,
+  IconKey,
 } from "@tabler/icons-react";
 import { Link } from "react-router";
 
@@ -58,10 +59,12 @@ export default function Enroll() {
   const [state, setState] = useState<State>({ state: "start" });
   const [name, setName] = useState<string>("Unnamed passkey");
 
-  const startEnrollment = () => {
+  // `attachment` is where to store the passkey; "platform" for this device or
+  // "cross-platform" for a security key. If not set, the server decides.
+  const startEnrollment = (attachment?: string) => {
     setState({ state: "loading" });
     api
-      .StartEnroll()
+      .StartEnroll({ attachment })
       .then((res) => {
         if (res.error) {
           setState({ state: "error", startError: res.error });
@@ -132,11 +135,34 @@ export default function Enroll() {
             </p>
             <button
               type="button"
-              onClick={startEnrollment}
+              onClick={() => startEnrollment()}
               className="flex justify-center w-full px-4 py-2 text-sm font-medium text-white border border-transparent rounded-md shadow-xs bg-emerald-600 hover:bg-emerald-700 focus:outline-hidden focus:ring-2 focus:ring-offset-2 focus:ring-emerald-500"
             >
               Start Enrollment
             </button>
+            <div className="space-y-2">
+              <p className="text-sm text-slate-500">
+                Or choose where to store your passkey:
+              </p>
+              <div className="flex space-x-4">
+                <button
+                  type="button"
+                  onClick={() => startEnrollment("platform")}
+                  className="flex flex-1 items-center justify-center gap-2 px-4 py-2 text-sm font-medium text-slate-700 bg-white border border-gray-300 rounded-md shadow-xs hover:bg-gray-50 focus:outline-hidden focus:ring-2 focus:ring-offset-2 focus:ring-emerald-500"
+                >
+                  <IconDeviceDesktop size={18} />
+                  This device
+                </button>
+                <button
+                  type="button"
+                  onClick={() => startEnrollment("cross-platform")}
+                  className="flex flex-1 items-center justify-center gap-2 px-4 py-2 text-sm font-medium text-slate-700 bg-white border border-gray-300 rounded-md shadow-xs hover:bg-gray-50 focus:outline-hidden focus:ring-2 focus:ring-offset-2 focus:ring-emerald-500"
+                >
+                  <IconKey size={18} />
+                  Security key
+                </button>
+              </div>
+            </div>
           </div>
         );
       case "loading":
@@ -162,7 +188,9 @@ export default function Enroll() {
           <div className="flex flex-col items-center justify-center space-y-4 py-8 text-center">
             <IconExclamationCircle className="text-red-500" size={48} />
             <p className="text-lg text-red-700">
-              An error occurred during enrollment. Please try again.
+              {state.startError?.attachmentNotAllowed
+                ? "Passkeys can't be stored there. Please choose another option."
+                : "An error occurred during enrollment. Please try again."}
             </p>
             <button
               type="button"
@@ -26,6 +26,7 @@ import { ApiService } from "../api/api_client";
 import { relative_date } from "../lib/date_utils";
 import { UAParser } from "ua-parser-js";
 import { ApiUser } from "../api/api_types";
+import { ImpersonationBanner } from "../components/impersonation_banner";
 
 export async function IndexLoader(api: ApiService) {
   return await api.GetUser("me");
@@ -59,8 +60,16 @@ function getDeviceIcon(userAgent: string) {
 export default function Index() {
   const user = useLoaderData() as ApiUser;
   const now = new Date();
+  const passkeys = user.credentials.filter((e) => e.type !== "totp");
+  const totp = user.credentials.find((e) => e.type === "totp");
   return (
     <>
+      {user.impersonation && (
+        <ImpersonationBanner
+          email={user.email}
+          impersonation={user.impersonation}
+        />
+      )}
       <h1 className="text-2xl mb-3">Hello, {user.displayName}</h1>
       <h2 className="text-xl mb-2">Passkeys</h2>
       <div>
@@ -76,7 +85,7 @@ export default function Index() {
           </Link>
 
           <ul className="divide-y divide-slate-100 max-w-xl">
-            {user.credentials.map((e) => (
+            {passkeys.map((e) => (
               <li key={e.id} className="flex items-center gap-4 px-4 py-3">
                 <div className="self-start">
                   <a
@@ -166,6 +175,53 @@ export default function Index() {
           </ul>
         </>
       </div>
+      {(totp || user.totpPolicy !== "disabled") && (
+        <>
+          <h2 className="text-xl mt-4 mb-2">Authenticator App</h2>
+          {totp ? (
+            <ul className="divide-y divide-slate-100 max-w-xl">
+              <li className="flex items-center gap-4 px-4 py-3">
+                <div className="self-start">
+                  <IconDeviceMobile />
+                </div>
+                <div className="flex min-h-8 flex-1 flex-col items-start justify-center gap-0 overflow-hidden">
+                  <h4 className="w-full truncate text-base text-slate-700">
+                    {totp.name}
+                  </h4>
+                  <p className="w-full truncate text-sm text-slate-500">
+                    Added {relative_date(new Date(totp.createdAt), now)}, last
+                    used {relative_date(new Date(totp.lastUsedAt), now)}
+                  </p>
+                </div>
+                <div>
+                  <Form method="post">
+                    <input type="hidden" name="id" value={totp.id} />
+                    <Button
+                      type="submit"
+                      className="inline-flex h-10 items-center justify-center gap-2 justify-self-center whitespace-nowrap rounded-full px-5 text-sm font-medium tracking-wide text-slate-500 transition duration-300 hover:bg-red-50 hover:text-red-600 focus:bg-red-100 focus:text-red-700 focus-visible:outline-hidden"
+                    >
+                      <span className="relative only:-mx-5">
+                        <span className="sr-only">Remove authenticator app</span>
+                        <IconX />
+                      </span>
+                    </Button>
+                  </Form>
+                </div>
+              </li>
+            </ul>
+          ) : (
+            <Link
+              to="/totp/enroll"
+              className="inline-flex items-center justify-center h-10 gap-2 px-5 text-sm font-medium tracking-wide transition duration-300 border rounded-full focus-visible:outline-hidden whitespace-nowrap border-emerald-500 text-emerald-500 hover:border-emerald-600 hover:text-emerald-600 focus:border-emerald-700 focus:text-emerald-700"
+            >
+              <span className="order-2">Set up an authenticator app</span>
+              <span className="relative only:-mx-4">
+                <IconPlus size={24} />
+              </span>
+            </Link>
+          )}
+        </>
+      )}
       <h2 className="text-xl mt-4 mb-2">Sessions</h2>
       <div>
         <ul className="divide-y divide-slate-100 max-w-xl">
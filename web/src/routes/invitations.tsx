@@ -0,0 +1,254 @@
+import { IconAlertCircle, IconX } from "@tabler/icons-react";
+import {
+  ActionFunctionArgs,
+  Form,
+  useActionData,
+  useLoaderData,
+} from "react-router";
+import { ApiService } from "../api/api_client";
+import {
+  ApiImportedInvitation,
+  ApiListInvitationsResponse,
+} from "../api/api_types";
+
+type InvitationsActionData = {
+  error?: string;
+  invitations?: ApiImportedInvitation[];
+};
+
+function splitList(value: FormDataEntryValue | null): string[] {
+  return ((value as string) ?? "")
+    .split(/[\s,;]+/)
+    .map((s) => s.trim())
+    .filter((s) => s !== "");
+}
+
+export async function InvitationsLoader(api: ApiService) {
+  return await api.ListInvitations();
+}
+
+export async function InvitationsAction(
+  api: ApiService,
+  { request }: ActionFunctionArgs,
+): Promise<InvitationsActionData | null> {
+  const formData = await request.formData();
+  const intent = formData.get("intent") as string;
+  const sendEmail = formData.get("sendEmail") === "on";
+  try {
+    if (intent === "revoke") {
+      await api.RevokeInvitation(formData.get("id") as string);
+      return null;
+    } else if (intent === "import") {
+      const format = formData.get("format") === "csv" ? "csv" : "yaml";
+      const res = await api.ImportInvitations(
+        formData.get("data") as string,
+        format,
+        sendEmail,
+      );
+      return { invitations: res.invitations };
+    }
+    const res = await api.CreateInvitation({
+      email: formData.get("email") as string,
+      admin: formData.get("admin") === "on",
+      allowedHosts: splitList(formData.get("allowedHosts")),
+      groups: splitList(formData.get("groups")),
+      sendEmail,
+    });
+    return {
+      invitations: [
+        {
+          email: res.invitation.email,
+          url: res.url,
+          emailSent: res.emailSent,
+        },
+      ],
+    };
+  } catch (error) {
+    return {
+      error: error instanceof Error ? error.message : "Request failed",
+    };
+  }
+}
+
+const inputClassName =
+  "block w-full px-3 py-2 placeholder-gray-400 border border-gray-300 rounded-md shadow-xs appearance-none focus:outline-hidden focus:ring-emerald-500 focus:border-emerald-500 sm:text-sm";
+
+const buttonClassName =
+  "flex justify-center w-full px-4 py-2 text-sm font-medium text-white border border-transparent rounded-md shadow-xs bg-emerald-600 hover:bg-emerald-700 focus:outline-hidden focus:ring-2 focus:ring-offset-2 focus:ring-emerald-500";
+
+const stateClassNames: Record<string, string> = {
+  pending: "bg-emerald-100 text-emerald-800",
+  expired: "bg-slate-100 text-slate-600",
+  revoked: "bg-red-100 text-red-800",
+  redeemed: "bg-sky-100 text-sky-800",
+};
+
+export default function Invitations() {
+  const loader = useLoaderData() as ApiListInvitationsResponse;
+  const actionData = useActionData() as InvitationsActionData | undefined;
+
+  return (
+    <div className="max-w-2xl mx-auto">
+      <h1 className="text-2xl font-bold text-slate-800">Invitations</h1>
+      <p className="mt-2 text-slate-600">
+        Invited users get an account when they open their link. Links can be
+        used once and expire after a week.
+      </p>
+
+      {actionData?.error && (
+        <div className="mt-4 p-4 bg-red-50 border border-red-200 rounded-md flex items-start gap-3">
+          <IconAlertCircle className="text-red-600 shrink-0" size={20} />
+          <p className="text-sm text-red-700">{actionData.error}</p>
+        </div>
+      )}
+
+      {actionData?.invitations && (
+        <ul className="mt-4 divide-y divide-slate-100 rounded-md border border-slate-200">
+          {actionData.invitations.map((inv, i) => (
+            <li key={i} className="px-4 py-3 text-sm">
+              <p className="font-medium text-slate-700">{inv.email}</p>
+              {inv.error ? (
+                <p className="text-red-700">{inv.error}</p>
+              ) : (
+                <>
+                  <p className="font-mono break-all text-slate-500">
+                    {inv.url}
+                  </p>
+                  {inv.emailSent && (
+                    <p className="text-slate-500">Sent by e-mail</p>
+                  )}
+                </>
+              )}
+            </li>
+          ))}
+        </ul>
+      )}
+
+      <h2 className="mt-8 text-lg font-semibold text-slate-800">
+        Pending and past invitations
+      </h2>
+      <ul className="mt-2 divide-y divide-slate-100">
+        {loader.invitations.map((inv) => (
+          <li key={inv.id} className="flex items-center gap-4 py-3">
+            <div className="flex flex-1 flex-col overflow-hidden">
+              <div className="flex items-center gap-2">
+                <p className="truncate text-base text-slate-700">{inv.email}</p>
+                <span
+                  className={`inline-flex items-center rounded-full px-2 py-1 text-xs font-medium ${stateClassNames[inv.state]}`}
+                >
+                  {inv.state}
+                </span>
+                {inv.isAdmin && (
+                  <span className="inline-flex items-center rounded-full bg-amber-100 px-2 py-1 text-xs font-medium text-amber-800">
+                    admin
+                  </span>
+                )}
+              </div>
+              <p className="truncate text-sm text-slate-500">
+                Expires {new Date(inv.expiresAt).toLocaleString()}
+              </p>
+            </div>
+            {inv.state === "pending" && (
+              <Form method="post">
+                <input type="hidden" name="intent" value="revoke" />
+                <input type="hidden" name="id" value={inv.id} />
+                <button
+                  type="submit"
+                  aria-label={`Revoke invitation for ${inv.email}`}
+                  className="inline-flex h-10 items-center justify-center rounded-full px-5 text-slate-500 transition duration-300 hover:bg-red-50 hover:text-red-600"
+                >
+                  <IconX />
+                </button>
+              </Form>
+            )}
+          </li>
+        ))}
+      </ul>
+
+      <h2 className="mt-8 text-lg font-semibold text-slate-800">
+        Invite a user
+      </h2>
+      <Form className="mt-4 space-y-4" method="post">
+        <input type="hidden" name="intent" value="create" />
+        <input
+          name="email"
+          type="email"
+          required
+          placeholder="user@example.com"
+          className={inputClassName}
+        />
+        <input
+          name="allowedHosts"
+          placeholder="Allowed hosts, separated by spaces"
+          className={inputClassName}
+        />
+        <input
+          name="groups"
+          placeholder="Groups, separated by spaces"
+          className={inputClassName}
+        />
+        <label className="flex items-center">
+          <input
+            type="checkbox"
+            name="admin"
+            className="h-4 w-4 text-emerald-600 focus:ring-emerald-500 border-gray-300 rounded-sm"
+          />
+          <span className="ml-2 text-sm font-medium text-slate-700">
+            Administrator
+          </span>
+        </label>
+        <label className="flex items-center">
+          <input
+            type="checkbox"
+            name="sendEmail"
+            className="h-4 w-4 text-emerald-600 focus:ring-emerald-500 border-gray-300 rounded-sm"
+          />
+          <span className="ml-2 text-sm font-medium text-slate-700">
+            Send the invitation by e-mail
+          </span>
+        </label>
+        <button type="submit" className={buttonClassName}>
+          Create Invitation
+        </button>
+      </Form>
+
+      <h2 className="mt-8 text-lg font-semibold text-slate-800">
+        Import users
+      </h2>
+      <p className="mt-2 text-sm text-slate-600">
+        Paste a CSV file with a header row, or a YAML list. The columns and
+        keys are <code>email</code>, <code>display_name</code>,{" "}
+        <code>admin</code>, <code>allowed_hosts</code> and <code>groups</code>.
+      </p>
+      <Form className="mt-4 space-y-4" method="post">
+        <input type="hidden" name="intent" value="import" />
+        <textarea
+          name="data"
+          required
+          rows={8}
+          placeholder={
+            "email,admin,allowed_hosts\nuser@example.com,false,app.example.com"
+          }
+          className={`${inputClassName} font-mono`}
+        />
+        <select name="format" className={inputClassName} defaultValue="csv">
+          <option value="csv">CSV</option>
+          <option value="yaml">YAML</option>
+        </select>
+        <label className="flex items-center">
+          <input
+            type="checkbox"
+            name="sendEmail"
+            className="h-4 w-4 text-emerald-600 focus:ring-emerald-500 border-gray-300 rounded-sm"
+          />
+          <span className="ml-2 text-sm font-medium text-slate-700">
+            Send the invitations by e-mail
+          </span>
+        </label>
+        <button type="submit" className={buttonClassName}>
+          Import
+        </button>
+      </Form>
+    </div>
+  );
+}
@@ -0,0 +1,58 @@
+import { IconExclamationCircle } from "@tabler/icons-react";
+import { redirect, useLoaderData } from "react-router";
+import { ApiService } from "../api/api_client";
+import { ApiRedeemInvitationResponse } from "../api/api_types";
+
+export async function InviteLoader(api: ApiService, token: string) {
+  const res = await api.RedeemInvitation({ token });
+  if (res.success) {
+    document.cookie = res.success.cookie;
+    return redirect(res.success.redirect);
+  }
+  return res;
+}
+
+export default function Invite() {
+  const res = useLoaderData() as ApiRedeemInvitationResponse;
+
+  let errorTitle = "Invalid invitation";
+  let errorMessage = "The invitation link is invalid.";
+  if (res.error?.expired) {
+    errorTitle = "Invitation expired";
+    errorMessage =
+      "This invitation is no longer valid. Please ask for a new one.";
+  } else if (res.error?.revoked) {
+    errorTitle = "Invitation revoked";
+    errorMessage = "This invitation has been revoked.";
+  } else if (res.error?.redeemed) {
+    errorTitle = "Invitation already used";
+    errorMessage =
+      "This invitation has already been used. Please sign in instead.";
+  } else if (res.error?.userExists) {
+    errorTitle = "Account already exists";
+    errorMessage =
+      "There is already an account with this e-mail address. Please sign in instead.";
+  }
+
+  return (
+    <section className="bg-gray-50 min-h-screen flex items-center justify-center py-12 px-4 sm:px-6 lg:px-8">
+      <div className="w-full max-w-xl bg-white rounded-lg shadow-lg md:mt-0 xl:p-0">
+        <div className="p-6 space-y-6 sm:p-8">
+          <div className="flex flex-col items-center space-y-4 py-4">
+            <IconExclamationCircle className="text-red-500" size={48} />
+            <div className="text-center">
+              <p className="text-lg text-red-700 font-medium">{errorTitle}</p>
+              <p className="text-sm text-slate-500 mt-2">{errorMessage}</p>
+            </div>
+          </div>
+          <a
+            href="/signin"
+            className="block w-full px-4 py-2 text-sm font-medium text-white text-center border border-transparent rounded-md shadow-xs bg-emerald-600 hover:bg-emerald-700 focus:outline-hidden focus:ring-2 focus:ring-offset-2 focus:ring-emerald-500"
+          >
+            Sign in
+          </a>
+        </div>
+      </div>
+    </section>
+  );
+}
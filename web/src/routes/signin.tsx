@@ -1,4 +1,4 @@
-import { useState } from "react";
+import { useEffect, useState } from "react";
 import { useApiService } from "../api/api_client";
 import {
   ApiAssertionCredential,
@@ -6,13 +6,18 @@ import {
   ApiSignInEmailSuccess,
   ApiSignInWebauthResponse,
   ApiSigninEmailResponse,
+  ApiSigninProvider,
+  ApiSignInTotpResponse,
 } from "../api/api_types";
 import { EmailForm } from "../components/email_form";
+import OTPInput from "../components/otp_input";
 import { useWebauthnService } from "../lib/webauthn-hook";
 import {
   IconLoader2,
   IconExclamationCircle,
   IconDeviceDesktop,
+  IconDeviceMobile,
+  IconMail,
 } from "@tabler/icons-react";
 
 type SignInEmail = {
@@ -32,10 +37,26 @@ type RequestAssertionState = {
 type SigninEmailError = {
   state: "email-error";
   error: ApiSignInEmailError;
+  email: string;
 };
 
 type RequestSigninError = {
   state: "request-signin-error";
+  message?: string;
+};
+
+// A one-time password from an authenticator app is needed, either after
+// signing in (with `token`), or instead of a passkey (with `email`).
+type SignInTotp = {
+  state: "totp";
+  token?: string;
+  email?: string;
+  error?: string;
+};
+
+type SigninLinkSent = {
+  state: "link-sent";
+  email: string;
 };
 
 type State =
@@ -43,9 +64,43 @@ type State =
   | SignInLoading
   | RequestAssertionState
   | SigninEmailError
-  | RequestSigninError;
+  | RequestSigninError
+  | SignInTotp
+  | SigninLinkSent;
+
+// Where to go after signing in. Only paths on this host are accepted, e.g. to
+// return to the OIDC authorization endpoint.
+function signinRedirect(): string {
+  const rd = new URLSearchParams(window.location.search).get("rd");
+  if (rd && rd.startsWith("/") && !rd.startsWith("//") && !rd.startsWith("/\\")) {
+    return rd;
+  }
+  return "/";
+}
 
-function handleSignedIn(res: ApiSignInWebauthResponse) {
+// Errors that the upstream sign-in callback redirects back with.
+function upstreamErrorMessage(error: string | null): string | undefined {
+  switch (error) {
+    case null:
+      return undefined;
+    case "not_linked":
+      return "That account isn't linked to any user. Sign in with your passkey and link it from your account page first.";
+    case "already_linked":
+      return "That account is already linked to another user.";
+    case "upstream_denied":
+      return "Sign-in was cancelled.";
+    case "upstream_unavailable":
+      return "The identity provider can't be reached right now. Please try again later.";
+    case "totp_not_enrolled":
+      return "Your account requires an authenticator app, but none has been set up. Please contact an administrator.";
+    default:
+      return "Signing in with the identity provider failed. Please try again.";
+  }
+}
+
+function handleSignedIn(
+  res: ApiSignInWebauthResponse | ApiSignInTotpResponse,
+) {
   if (!res.success) {
     alert("Failed to log in");
     return;
@@ -68,7 +123,28 @@ export default function SignIn() {
   const api = useApiService();
   const webauthn = useWebauthnService();
 
-  const [state, setState] = useState<State>({ state: "email" });
+  const [state, setState] = useState<State>(() => {
+    // The upstream sign-in callback redirects here if a one-time password is
+    // also required.
+    const token = new URLSearchParams(window.location.search).get("totp");
+    return token ? { state: "totp", token } : { state: "email" };
+  });
+  const [code, setCode] = useState("");
+  const [providers, setProviders] = useState<ApiSigninProvider[]>([]);
+  const [signinLinks, setSigninLinks] = useState(false);
+  const upstreamError = upstreamErrorMessage(
+    new URLSearchParams(window.location.search).get("error"),
+  );
+
+  useEffect(() => {
+    api
+      .ListSigninProviders()
+      .then((res) => {
+        setProviders(res.providers);
+        setSigninLinks(res.signinLinks);
+      })
+      .catch((e) => console.log("Failed to list sign-in providers", e));
+  }, [api]);
 
   const startSigninPinFlow = (email: string) => {
     api
@@ -77,7 +153,13 @@ export default function SignIn() {
         userAgent: window.navigator.userAgent,
       })
       .then((res) => {
-        if (res.error) {
+        if (res.error?.tooManyRequests) {
+          setState({
+            state: "request-signin-error",
+            message:
+              "Too many sign-in requests. Please wait a few minutes and try again.",
+          });
+        } else if (res.error) {
           console.log("Failed to request signin pin", res.error);
           setState({ state: "request-signin-error" });
         } else {
@@ -86,6 +168,26 @@ export default function SignIn() {
       });
   };
 
+  const sendSigninLink = (email: string) => {
+    setState({ state: "loading" });
+    api
+      .SendSigninLink({ email })
+      .then((res) => {
+        if (res.error) {
+          setState({
+            state: "request-signin-error",
+            message: "The sign-in link couldn't be sent. Please try again.",
+          });
+        } else {
+          setState({ state: "link-sent", email });
+        }
+      })
+      .catch((e) => {
+        console.log("Failed to send sign-in link", e);
+        setState({ state: "request-signin-error" });
+      });
+  };
+
   const handleConditionalWebauthn = async () => {
     if (
       !PublicKeyCredential.isConditionalMediationAvailable ||
@@ -123,7 +225,7 @@ export default function SignIn() {
             onNotSupported: () => startSigninPinFlow(email),
           });
         } else if (res.error) {
-          setState({ state: "email-error", error: res.error });
+          setState({ state: "email-error", error: res.error, email });
         }
       })
       .catch((e) => {
@@ -139,9 +241,53 @@ export default function SignIn() {
     const signinResult = await api.SignInWebauthn({
       token,
       credential,
-      redirect: "/",
+      redirect: signinRedirect(),
     });
-    handleSignedIn(signinResult);
+    if (signinResult.totpRequired) {
+      setCode("");
+      setState({ state: "totp", token: signinResult.totpRequired.token });
+    } else if (signinResult.error?.totpNotEnrolled) {
+      setState({
+        state: "request-signin-error",
+        message:
+          "Your account requires an authenticator app, but none has been set up. Please contact an administrator.",
+      });
+    } else {
+      handleSignedIn(signinResult);
+    }
+  };
+
+  const handleSubmitTotp = async (totp: SignInTotp) => {
+    setState({ state: "loading" });
+    try {
+      const res = await api.SignInTotp({
+        token: totp.token,
+        email: totp.email,
+        code,
+        redirect: signinRedirect(),
+      });
+      setCode("");
+      if (res.success) {
+        handleSignedIn(res);
+      } else if (res.error?.invalidToken) {
+        setState({
+          state: "request-signin-error",
+          message: "The sign-in has expired. Please sign in again.",
+        });
+      } else {
+        setState({
+          ...totp,
+          // The token can only be used once, so use the new one.
+          token: totp.token ? res.token : undefined,
+          error: res.error?.blocked
+            ? "Too many failed attempts. Please wait a few minutes and try again."
+            : "The code is invalid. Please try again.",
+        });
+      }
+    } catch (e) {
+      console.log("Failed to sign in", e);
+      setState({ state: "request-signin-error" });
+    }
   };
 
   const renderErrorMessage = (error?: ApiSignInEmailError) => {
@@ -166,10 +312,24 @@ export default function SignIn() {
               Enter your email address to sign in with your passkey or receive a
               secure sign-in link.
             </p>
+            {upstreamError && (
+              <p className="text-sm text-red-700 text-center">
+                {upstreamError}
+              </p>
+            )}
             <EmailForm
               onSubmit={handleSubmitEmail}
               onConditionalWebauthn={handleConditionalWebauthn}
             />
+            {providers.map((provider) => (
+              <a
+                key={provider.id}
+                href={`/api/signin/upstream/${encodeURIComponent(provider.id)}/start?rd=${encodeURIComponent(signinRedirect())}`}
+                className="block w-full px-4 py-2 text-sm font-medium text-center text-slate-700 bg-white border border-slate-300 rounded-md hover:bg-slate-50 focus:outline-hidden focus:ring-2 focus:ring-offset-2 focus:ring-emerald-500"
+              >
+                Sign in with {provider.name}
+              </a>
+            ))}
           </div>
         );
       case "loading":
@@ -190,13 +350,30 @@ export default function SignIn() {
               Touch your security key or use your device's biometric sensor.
             </p>
 
-            <div className="pt-4 w-full max-w-xs">
+            <div className="pt-4 w-full max-w-xs space-y-3">
               <button
                 onClick={() => startSigninPinFlow(state.email)}
                 className="w-full px-4 py-2 text-sm font-medium text-emerald-700 bg-emerald-100 border border-transparent rounded-md hover:bg-emerald-200 focus:outline-hidden focus:ring-2 focus:ring-offset-2 focus:ring-emerald-500"
               >
                 Sign in with another device
               </button>
+              <button
+                onClick={() => {
+                  setCode("");
+                  setState({ state: "totp", email: state.email });
+                }}
+                className="w-full px-4 py-2 text-sm font-medium text-slate-700 bg-white border border-slate-300 rounded-md hover:bg-slate-50 focus:outline-hidden focus:ring-2 focus:ring-offset-2 focus:ring-emerald-500"
+              >
+                Use authenticator app
+              </button>
+              {signinLinks && (
+                <button
+                  onClick={() => sendSigninLink(state.email)}
+                  className="w-full px-4 py-2 text-sm font-medium text-slate-700 bg-white border border-slate-300 rounded-md hover:bg-slate-50 focus:outline-hidden focus:ring-2 focus:ring-offset-2 focus:ring-emerald-500"
+                >
+                  Email me a sign-in link
+                </button>
+              )}
             </div>
           </div>
         );
@@ -209,6 +386,27 @@ export default function SignIn() {
                 {renderErrorMessage(state.error)}
               </p>
             </div>
+            {state.error.no_credentials && (
+              <button
+                type="button"
+                onClick={() => {
+                  setCode("");
+                  setState({ state: "totp", email: state.email });
+                }}
+                className="w-full px-4 py-2 text-sm font-medium text-emerald-700 bg-emerald-100 border border-transparent rounded-md hover:bg-emerald-200 focus:outline-hidden focus:ring-2 focus:ring-offset-2 focus:ring-emerald-500"
+              >
+                Use authenticator app
+              </button>
+            )}
+            {state.error.no_credentials && signinLinks && (
+              <button
+                type="button"
+                onClick={() => sendSigninLink(state.email)}
+                className="w-full px-4 py-2 text-sm font-medium text-slate-700 bg-white border border-slate-300 rounded-md hover:bg-slate-50 focus:outline-hidden focus:ring-2 focus:ring-offset-2 focus:ring-emerald-500"
+              >
+                Email me a sign-in link
+              </button>
+            )}
             <button
               type="button"
               onClick={() => setState({ state: "email" })}
@@ -224,8 +422,8 @@ export default function SignIn() {
             <div className="flex flex-col items-center space-y-4 py-4">
               <IconExclamationCircle className="text-red-500" size={48} />
               <p className="text-lg text-red-700 text-center">
-                Unable to process your sign-in request. Please check your
-                connection and try again.
+                {state.message ??
+                  "Unable to process your sign-in request. Please check your connection and try again."}
               </p>
             </div>
             <button
@@ -237,6 +435,55 @@ export default function SignIn() {
             </button>
           </div>
         );
+      case "link-sent":
+        return (
+          <div className="flex flex-col items-center justify-center space-y-4 py-8 text-center">
+            <IconMail className="text-emerald-500" size={64} />
+            <p className="text-lg text-slate-700 font-medium">
+              Check your email
+            </p>
+            <p className="text-sm text-slate-500 max-w-xs">
+              If {state.email} has an account, a sign-in link has been sent to
+              it. The link can be used once.
+            </p>
+          </div>
+        );
+      case "totp":
+        return (
+          <form
+            className="space-y-6"
+            onSubmit={(e) => {
+              e.preventDefault();
+              handleSubmitTotp(state);
+            }}
+          >
+            <div className="text-center">
+              <IconDeviceMobile
+                className="mx-auto text-emerald-500 mb-4"
+                size={48}
+              />
+              <p className="text-slate-600">
+                Enter the 6-digit code from your authenticator app.
+              </p>
+            </div>
+            {state.error && (
+              <p className="text-sm text-red-700 text-center">{state.error}</p>
+            )}
+            <OTPInput
+              numInputs={6}
+              onChange={(otp) => setCode(otp)}
+              value={code}
+              shouldAutoFocus
+            />
+            <button
+              type="submit"
+              disabled={code.length !== 6 || (!state.token && !state.email)}
+              className="w-full px-4 py-2 text-sm font-medium text-white border border-transparent rounded-md shadow-xs bg-emerald-600 hover:bg-emerald-700 focus:outline-hidden focus:ring-2 focus:ring-offset-2 focus:ring-emerald-500 disabled:opacity-50 disabled:cursor-not-allowed"
+            >
+              Sign In
+            </button>
+          </form>
+        );
     }
     return assertUnreachable(state);
   })();
@@ -0,0 +1,232 @@
+import { useState } from "react";
+import { useApiService } from "../api/api_client";
+import { ApiStartTotpEnrollResponse } from "../api/api_types";
+import OTPInput from "../components/otp_input";
+import {
+  IconCheck,
+  IconExclamationCircle,
+  IconLoader2,
+} from "@tabler/icons-react";
+import { Link } from "react-router";
+
+type StartState = {
+  state: "start";
+};
+type EnrollLoading = {
+  state: "loading";
+};
+type EnrollScan = {
+  state: "scan";
+  enroll: ApiStartTotpEnrollResponse;
+  error?: string;
+};
+type EnrollError = {
+  state: "error";
+  message: string;
+};
+type EnrollDone = {
+  state: "done";
+};
+
+type State = StartState | EnrollLoading | EnrollScan | EnrollError | EnrollDone;
+
+function assertUnreachable(value: never): never {
+  throw new Error(`Didn't expect to get here: ${value}`);
+}
+
+export default function TotpEnroll() {
+  const api = useApiService();
+  const [state, setState] = useState<State>({ state: "start" });
+  const [name, setName] = useState("Authenticator app");
+  const [code, setCode] = useState("");
+
+  const startEnrollment = () => {
+    setState({ state: "loading" });
+    api
+      .StartTotpEnroll()
+      .then((res) => {
+        if (res.error?.notAllowed) {
+          setState({
+            state: "error",
+            message: "Your account isn't allowed to use an authenticator app.",
+          });
+        } else if (res.error?.alreadyEnrolled) {
+          setState({
+            state: "error",
+            message:
+              "An authenticator app has already been set up. Remove it first to set up a new one.",
+          });
+        } else if (res.error) {
+          setState({
+            state: "error",
+            message: "An error occurred during enrollment. Please try again.",
+          });
+        } else {
+          setCode("");
+          setState({ state: "scan", enroll: res });
+        }
+      })
+      .catch(() => {
+        setState({
+          state: "error",
+          message: "An error occurred during enrollment. Please try again.",
+        });
+      });
+  };
+
+  const finishEnrollment = (enroll: ApiStartTotpEnrollResponse) => {
+    api
+      .FinishTotpEnroll({ token: enroll.token!, code, name })
+      .then((res) => {
+        if (res.credential) {
+          setState({ state: "done" });
+        } else if (res.error?.invalidCode) {
+          setCode("");
+          setState({
+            state: "scan",
+            enroll,
+            error: "The code is invalid. Please try again.",
+          });
+        } else {
+          setState({
+            state: "error",
+            message: "The enrollment has expired. Please try again.",
+          });
+        }
+      })
+      .catch(() => {
+        setState({
+          state: "error",
+          message: "An error occurred during enrollment. Please try again.",
+        });
+      });
+  };
+
+  const renderContent = (() => {
+    switch (state.state) {
+      case "start":
+        return (
+          <div className="space-y-6">
+            <p className="text-slate-600">
+              Set up an authenticator app, which gives you one-time codes to
+              sign in with.
+            </p>
+            <button
+              type="button"
+              onClick={startEnrollment}
+              className="flex justify-center w-full px-4 py-2 text-sm font-medium text-white border border-transparent rounded-md shadow-xs bg-emerald-600 hover:bg-emerald-700 focus:outline-hidden focus:ring-2 focus:ring-offset-2 focus:ring-emerald-500"
+            >
+              Start Enrollment
+            </button>
+          </div>
+        );
+      case "loading":
+        return (
+          <div className="flex flex-col items-center justify-center space-y-4 py-8">
+            <IconLoader2 className="animate-spin text-emerald-500" size={48} />
+            <p className="text-lg text-slate-700">Loading...</p>
+          </div>
+        );
+      case "scan":
+        return (
+          <form
+            className="space-y-6"
+            onSubmit={(e) => {
+              e.preventDefault();
+              finishEnrollment(state.enroll);
+            }}
+          >
+            <p className="text-slate-600">
+              Scan the QR code with your authenticator app, or enter the key
+              manually. Then enter the code that the app shows.
+            </p>
+            <img
+              src={state.enroll.qrCodeUrl}
+              alt="QR code"
+              className="mx-auto"
+              width={200}
+              height={200}
+            />
+            <p className="text-center text-sm font-mono text-slate-700 break-all">
+              {state.enroll.secret}
+            </p>
+            <div>
+              <label
+                htmlFor="totp-name"
+                className="block text-sm font-medium text-slate-700"
+              >
+                Name
+              </label>
+              <div className="mt-1">
+                <input
+                  id="totp-name"
+                  type="text"
+                  className="block w-full px-3 py-2 placeholder-gray-400 border border-gray-300 rounded-md shadow-xs appearance-none focus:outline-hidden focus:ring-emerald-500 focus:border-emerald-500 sm:text-sm"
+                  value={name}
+                  onChange={(e) => setName(e.target.value)}
+                />
+              </div>
+            </div>
+            {state.error && (
+              <p className="text-sm text-red-700 text-center">{state.error}</p>
+            )}
+            <OTPInput
+              numInputs={6}
+              onChange={(otp) => setCode(otp)}
+              value={code}
+            />
+            <button
+              type="submit"
+              disabled={code.length !== 6}
+              className="flex justify-center w-full px-4 py-2 text-sm font-medium text-white border border-transparent rounded-md shadow-xs bg-emerald-600 hover:bg-emerald-700 focus:outline-hidden focus:ring-2 focus:ring-offset-2 focus:ring-emerald-500 disabled:opacity-50 disabled:cursor-not-allowed"
+            >
+              Finish
+            </button>
+          </form>
+        );
+      case "error":
+        return (
+          <div className="flex flex-col items-center justify-center space-y-4 py-8 text-center">
+            <IconExclamationCircle className="text-red-500" size={48} />
+            <p className="text-lg text-red-700">{state.message}</p>
+            <button
+              type="button"
+              onClick={() => setState({ state: "start" })}
+              className="px-4 py-2 text-sm font-medium text-white border border-transparent rounded-md shadow-xs bg-red-600 hover:bg-red-700 focus:outline-hidden focus:ring-2 focus:ring-offset-2 focus:ring-red-500"
+            >
+              Try Again
+            </button>
+          </div>
+        );
+      case "done":
+        return (
+          <div className="flex flex-col items-center justify-center space-y-4 py-8 text-center">
+            <IconCheck className="text-emerald-500" size={48} />
+            <p className="text-lg text-slate-700">
+              Authenticator app set up successfully!
+            </p>
+            <Link
+              to="/"
+              className="px-4 py-2 text-sm font-medium text-white border border-transparent rounded-md shadow-xs bg-emerald-600 hover:bg-emerald-700 focus:outline-hidden focus:ring-2 focus:ring-offset-2 focus:ring-emerald-500"
+            >
+              Go to Dashboard
+            </Link>
+          </div>
+        );
+    }
+    return assertUnreachable(state);
+  })();
+
+  return (
+    <section className="bg-gray-50 min-h-screen flex items-center justify-center py-12 px-4 sm:px-6 lg:px-8">
+      <div className="w-full max-w-md bg-white rounded-lg shadow-lg md:mt-0 xl:p-0">
+        <div className="p-6 space-y-6 sm:p-8">
+          <h1 className="text-2xl font-bold leading-tight tracking-tight text-slate-800 md:text-3xl text-center">
+            Set Up Authenticator App
+          </h1>
+          {renderContent}
+        </div>
+      </div>
+    </section>
+  );
+}
@@ -25,7 +25,11 @@ import {
 } from "react-router";
 import { UAParser } from "ua-parser-js";
 import { ApiService, useApiService } from "../api/api_client";
-import { ApiUser, ApiBackend } from "../api/api_types";
+import {
+  ApiUser,
+  ApiBackend,
+  ApiUserAccessResponse,
+} from "../api/api_types";
 import { StyledComboBox, StyledItem } from "../components/StyledComboBox";
 import { relative_date } from "../lib/date_utils";
 
@@ -75,7 +79,8 @@ export async function EditUserAction(
     .filter((h) => h.trim())
     .map((h) => h.trim())
     .sort();
-  const req = { email, displayName, admin, allowedHosts };
+  const totpPolicy = payload.totpPolicy as string;
+  const req = { email, displayName, admin, allowedHosts, totpPolicy };
   await api.UpdateUser(userId, req);
   return redirect("/users/");
 }
@@ -90,13 +95,17 @@ export default function UsersEdit() {
   const [email, setEmail] = useState(user.email);
   const [displayName, setDisplayName] = useState(user.displayName);
   const [admin, setAdmin] = useState(user.isAdmin);
+  const [totpPolicy, setTotpPolicy] = useState(user.totpPolicy || "disabled");
   const [allowedHosts, setAllowedHosts] = useState<string[]>(
     [...(user.allowedHosts || [])].sort(),
   );
   const [allowedHostsStr, setAllowedHostsStr] = useState("");
   const [recoveryUrl, setRecoveryUrl] = useState("");
+  const [recoveryEmailSent, setRecoveryEmailSent] = useState(false);
   const [copySuccess, setCopySuccess] = useState(false);
   const [isGenerating, setIsGenerating] = useState(false);
+  const [accessUrl, setAccessUrl] = useState("");
+  const [access, setAccess] = useState<ApiUserAccessResponse | null>(null);
 
   useEffect(() => {
     setAllowedHostsStr(allowedHosts.join("\n"));
@@ -114,13 +123,14 @@ export default function UsersEdit() {
     setAllowedHosts(allowedHosts.filter((_, i) => i !== index));
   };
 
-  const generateRecoveryUrl = async () => {
+  const generateRecoveryUrl = async (sendEmail: boolean) => {
     if (!id) return;
 
     setIsGenerating(true);
     try {
-      const response = await api.RecoverUser(id);
+      const response = await api.RecoverUser(id, { sendEmail });
       setRecoveryUrl(response.recoveryUrl);
+      setRecoveryEmailSent(response.emailSent);
     } catch (error) {
       console.error("Failed to generate recovery URL:", error);
       alert("Failed to generate recovery URL. Please try again.");
@@ -129,6 +139,30 @@ export default function UsersEdit() {
     }
   };
 
+  const viewAsUser = async () => {
+    if (!id) return;
+
+    try {
+      const response = await api.ImpersonateUser(id);
+      document.cookie = response.cookie;
+      window.location.href = "/";
+    } catch (error) {
+      console.error("Failed to view as user:", error);
+      alert("Failed to view the site as the user. Please try again.");
+    }
+  };
+
+  const checkAccess = async () => {
+    if (!id || !accessUrl) return;
+
+    try {
+      setAccess(await api.CheckUserAccess(id, accessUrl));
+    } catch (error) {
+      console.error("Failed to check access:", error);
+      alert("Failed to check access. Please check the URL.");
+    }
+  };
+
   const copyToClipboard = async () => {
     try {
       await navigator.clipboard.writeText(recoveryUrl);
@@ -215,6 +249,30 @@ export default function UsersEdit() {
           </label>
         </div>
 
+        <div>
+          <label
+            htmlFor="totpPolicy"
+            className="block text-sm font-medium text-slate-700"
+          >
+            Authenticator App
+          </label>
+          <div className="mt-1">
+            <select
+              id="totpPolicy"
+              name="totpPolicy"
+              value={totpPolicy}
+              onChange={(e) => setTotpPolicy(e.target.value)}
+              className="block w-full px-3 py-2 border border-gray-300 rounded-md shadow-xs focus:outline-hidden focus:ring-emerald-500 focus:border-emerald-500 sm:text-sm"
+            >
+              <option value="disabled">Not allowed</option>
+              <option value="fallback">Allowed instead of a passkey</option>
+              <option value="required">
+                Required in addition to a passkey
+              </option>
+            </select>
+          </div>
+        </div>
+
         <div>
           <label className="block text-sm font-medium text-slate-700">
             Allowed Hosts
@@ -280,16 +338,31 @@ export default function UsersEdit() {
           </p>
 
           {!recoveryUrl ? (
-            <button
-              type="button"
-              onClick={generateRecoveryUrl}
-              disabled={isGenerating}
-              className="inline-flex items-center justify-center h-10 gap-2 px-5 text-sm font-medium tracking-wide transition duration-300 border rounded-full focus-visible:outline-hidden whitespace-nowrap border-emerald-500 text-emerald-500 hover:border-emerald-600 hover:text-emerald-600 focus:border-emerald-700 focus:text-emerald-700 disabled:cursor-not-allowed disabled:border-emerald-300 disabled:text-emerald-300 disabled:shadow-none"
-            >
-              {isGenerating ? "Generating..." : "Generate Recovery URL"}
-            </button>
+            <div className="flex gap-2">
+              <button
+                type="button"
+                onClick={() => generateRecoveryUrl(false)}
+                disabled={isGenerating}
+                className="inline-flex items-center justify-center h-10 gap-2 px-5 text-sm font-medium tracking-wide transition duration-300 border rounded-full focus-visible:outline-hidden whitespace-nowrap border-emerald-500 text-emerald-500 hover:border-emerald-600 hover:text-emerald-600 focus:border-emerald-700 focus:text-emerald-700 disabled:cursor-not-allowed disabled:border-emerald-300 disabled:text-emerald-300 disabled:shadow-none"
+              >
+                {isGenerating ? "Generating..." : "Generate Recovery URL"}
+              </button>
+              <button
+                type="button"
+                onClick={() => generateRecoveryUrl(true)}
+                disabled={isGenerating}
+                className="inline-flex items-center justify-center h-10 gap-2 px-5 text-sm font-medium tracking-wide transition duration-300 border rounded-full focus-visible:outline-hidden whitespace-nowrap border-emerald-500 text-emerald-500 hover:border-emerald-600 hover:text-emerald-600 focus:border-emerald-700 focus:text-emerald-700 disabled:cursor-not-allowed disabled:border-emerald-300 disabled:text-emerald-300 disabled:shadow-none"
+              >
+                Send by E-mail
+              </button>
+            </div>
           ) : (
             <div className="space-y-3">
+              {recoveryEmailSent && (
+                <p className="text-sm text-emerald-700">
+                  The recovery link has been e-mailed to the user.
+                </p>
+              )}
               <div className="flex items-center gap-2">
                 <input
                   type="text"
@@ -322,6 +395,57 @@ export default function UsersEdit() {
         </div>
       </div>
 
+      <div className="mt-8">
+        <h2 className="text-xl font-bold text-slate-800 mb-4">
+          Access Debugging
+        </h2>
+        <div className="bg-slate-50 p-4 rounded-md space-y-4">
+          <p className="text-sm text-slate-600">
+            View the site as the user for 15 minutes, without being able to
+            make changes, or check if the user may access a URL.
+          </p>
+          <button
+            type="button"
+            onClick={viewAsUser}
+            disabled={user.isDisabled}
+            className="inline-flex items-center justify-center h-10 gap-2 px-5 text-sm font-medium tracking-wide transition duration-300 border rounded-full focus-visible:outline-hidden whitespace-nowrap border-emerald-500 text-emerald-500 hover:border-emerald-600 hover:text-emerald-600 focus:border-emerald-700 focus:text-emerald-700 disabled:cursor-not-allowed disabled:border-emerald-300 disabled:text-emerald-300 disabled:shadow-none"
+          >
+            View as User
+          </button>
+          <div className="flex items-center gap-2">
+            <input
+              type="text"
+              value={accessUrl}
+              onChange={(e) => setAccessUrl(e.target.value)}
+              placeholder="https://app.example.com/path"
+              className="block w-full px-3 py-2 text-sm bg-white border border-gray-300 rounded-md shadow-xs focus:outline-hidden focus:ring-emerald-500 focus:border-emerald-500"
+            />
+            <button
+              type="button"
+              onClick={checkAccess}
+              disabled={!accessUrl}
+              className="inline-flex items-center justify-center h-10 gap-2 px-3 text-sm font-medium tracking-wide transition duration-300 border rounded-md focus-visible:outline-hidden whitespace-nowrap border-emerald-500 text-emerald-500 hover:border-emerald-600 hover:text-emerald-600 focus:border-emerald-700 focus:text-emerald-700 disabled:cursor-not-allowed disabled:border-emerald-300 disabled:text-emerald-300"
+            >
+              Check Access
+            </button>
+          </div>
+          {access && (
+            <div className="text-sm">
+              <p
+                className={access.allowed ? "text-emerald-700" : "text-red-700"}
+              >
+                {access.allowed ? "Allowed" : "Denied"}: {access.reason}.
+              </p>
+              <ul className="list-disc pl-5 text-slate-600">
+                {access.notes.map((note) => (
+                  <li key={note}>{note}.</li>
+                ))}
+              </ul>
+            </div>
+          )}
+        </div>
+      </div>
+
       <div className="mt-8">
         <h2 className="text-xl font-bold text-slate-800 mb-4">Passkeys</h2>
         {user.credentials && user.credentials.length > 0 ? (
@@ -8,8 +8,9 @@ export async function NewUserAction(
   const formData = await request.formData();
   const payload = Object.fromEntries(formData.entries());
   const email = payload.email! as string;
+  const sendInvitation = payload.sendInvitation === "on";
 
-  const response = await api.CreateUser({ email });
+  const response = await api.CreateUser({ email, sendInvitation });
   return redirect("/users/edit/" + response.id);
 }
 
@@ -39,6 +40,19 @@ export default function UsersNew() {
           </div>
         </div>
 
+        <div>
+          <label className="flex items-center">
+            <input
+              type="checkbox"
+              name="sendInvitation"
+              className="h-4 w-4 text-emerald-600 focus:ring-emerald-500 border-gray-300 rounded-sm"
+            />
+            <span className="ml-2 text-sm font-medium text-slate-700">
+              Send an invitation by e-mail
+            </span>
+          </label>
+        </div>
+
         <div>
           <button
             type="submit"
@@ -1,4 +1,5 @@
 import {
+  IconMail,
   IconPencil,
   IconPlus,
   IconUser,
@@ -55,6 +56,15 @@ export default function Users() {
             <IconPlus size={24} />
           </span>
         </Link>
+        <Link
+          to="/users/invitations"
+          className="ml-2 inline-flex items-center justify-center h-10 gap-2 px-5 text-sm font-medium tracking-wide transition duration-300 border rounded-full focus-visible:outline-hidden whitespace-nowrap border-emerald-500 text-emerald-500 hover:border-emerald-600 hover:text-emerald-600 focus:border-emerald-700 focus:text-emerald-700"
+        >
+          <span className="order-2">Invitations</span>
+          <span className="relative only:-mx-4">
+            <IconMail size={24} />
+          </span>
+        </Link>
 
         <ul className="divide-y divide-slate-100 max-w-xl mt-4">
           {users.map((u) => {
@@ -88,6 +98,11 @@ export default function Users() {
                         admin
                       </span>
                     )}
+                    {u.isDisabled && (
+                      <span className="inline-flex items-center rounded-full bg-slate-100 px-2 py-1 text-xs font-medium text-slate-600">
+                        disabled
+                      </span>
+                    )}
                   </div>
                   <p className="w-full truncate text-sm text-slate-500">
                     {u.email}